@@ -5,15 +5,23 @@ import (
 )
 
 var (
-	// Configs contains all files that placed under the configs directory
 	//go:embed configs
-	Configs embed.FS
+	configs embed.FS
 
-	// Scripts contains all files that placed under the scripts directory
 	//go:embed scripts
-	Scripts embed.FS
+	scripts embed.FS
 
-	// Tuned contains all files that placed under the tuned directory
 	//go:embed tuned
-	Tuned embed.FS
+	tuned embed.FS
+)
+
+var (
+	// Configs contains all files that placed under the configs directory
+	Configs = &OverlayFS{embedded: configs}
+
+	// Scripts contains all files that placed under the scripts directory
+	Scripts = &OverlayFS{embedded: scripts}
+
+	// Tuned contains all files that placed under the tuned directory
+	Tuned = &OverlayFS{embedded: tuned}
 )
@@ -0,0 +1,192 @@
+package assets
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// overrides holds the files of the override directory, the assets found there take precedence
+// over the embedded ones. The directory is flat, the files are named after the base name of the
+// asset they override, e.g. "openshift-node-performance" or "hugepages-allocation.sh", so it can
+// be a mounted ConfigMap.
+var overrides = &overrideCache{}
+
+type overrideCache struct {
+	mu       sync.RWMutex
+	dir      string
+	checksum string
+	files    map[string][]byte
+	modTime  time.Time
+}
+
+// SetOverrideDir sets the directory 'dir' overriding the embedded assets and loads its files.
+// An empty directory disables the overrides.
+func SetOverrideDir(dir string) error {
+	overrides.mu.Lock()
+	overrides.dir = dir
+	overrides.checksum = ""
+	overrides.files = nil
+	overrides.mu.Unlock()
+
+	_, err := Refresh()
+	return err
+}
+
+// Refresh reloads the files of the override directory and returns true if their checksum
+// changed since the last load. A missing directory has no files, so an optional ConfigMap
+// can be created and removed at any time.
+func Refresh() (bool, error) {
+	overrides.mu.RLock()
+	dir := overrides.dir
+	overrides.mu.RUnlock()
+	if dir == "" {
+		return false, nil
+	}
+
+	files, err := readOverrideDir(dir)
+	if err != nil {
+		return false, err
+	}
+	checksum := filesChecksum(files)
+
+	overrides.mu.Lock()
+	defer overrides.mu.Unlock()
+	if checksum == overrides.checksum {
+		return false, nil
+	}
+	overrides.checksum = checksum
+	overrides.files = files
+	overrides.modTime = time.Now()
+
+	return true, nil
+}
+
+// Checksum returns the checksum of the files of the override directory, empty when no override directory is set.
+func Checksum() string {
+	overrides.mu.RLock()
+	defer overrides.mu.RUnlock()
+	return overrides.checksum
+}
+
+// readOverrideDir returns the regular files of the directory 'dir' by their name. The hidden
+// files are skipped, they are the ConfigMap volume bookkeeping, e.g. "..data".
+func readOverrideDir(dir string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return map[string][]byte{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		// the ConfigMap volume files are symlinks, stat their targets
+		name := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(name)
+		if err != nil {
+			return nil, err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		content, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		files[entry.Name()] = content
+	}
+	return files, nil
+}
+
+// filesChecksum returns the sha256 checksum of the files 'files'.
+func filesChecksum(files map[string][]byte) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write(files[name])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// override returns the content of the override of the asset 'name', if any.
+func override(name string) ([]byte, time.Time, bool) {
+	overrides.mu.RLock()
+	defer overrides.mu.RUnlock()
+	content, ok := overrides.files[path.Base(name)]
+	return content, overrides.modTime, ok
+}
+
+// OverlayFS is a file system serving the embedded assets, overridden by the files of the override directory.
+type OverlayFS struct {
+	embedded embed.FS
+}
+
+// Open opens the asset 'name'.
+func (o *OverlayFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	// only the files are overridden, the directories are the embedded ones
+	if content, modTime, ok := override(name); ok {
+		if info, err := fs.Stat(o.embedded, name); err == nil && !info.IsDir() {
+			return &overrideFile{
+				Reader: bytes.NewReader(content),
+				info:   overrideFileInfo{name: path.Base(name), size: int64(len(content)), modTime: modTime},
+			}, nil
+		}
+	}
+	return o.embedded.Open(name)
+}
+
+// ReadFile reads and returns the content of the asset 'name'.
+func (o *OverlayFS) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(fsOnly{o}, name)
+}
+
+// fsOnly hides the ReadFile method of the overlay, so fs.ReadFile reads the files it opens.
+type fsOnly struct {
+	fs.FS
+}
+
+type overrideFile struct {
+	*bytes.Reader
+	info overrideFileInfo
+}
+
+func (f *overrideFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *overrideFile) Close() error               { return nil }
+
+type overrideFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (i overrideFileInfo) Name() string       { return i.name }
+func (i overrideFileInfo) Size() int64        { return i.size }
+func (i overrideFileInfo) Mode() fs.FileMode  { return 0444 }
+func (i overrideFileInfo) ModTime() time.Time { return i.modTime }
+func (i overrideFileInfo) IsDir() bool        { return false }
+func (i overrideFileInfo) Sys() interface{}   { return nil }
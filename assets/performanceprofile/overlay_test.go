@@ -0,0 +1,66 @@
+package assets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+)
+
+func TestOverlayFS(t *testing.T) {
+	dir := t.TempDir()
+	defer SetOverrideDir("")
+
+	embedded, err := Scripts.ReadFile("scripts/hugepages-allocation.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetOverrideDir(filepath.Join(dir, "missing")); err != nil {
+		t.Fatalf("expected a missing override directory to be ignored, got %v", err)
+	}
+	if content, _ := Scripts.ReadFile("scripts/hugepages-allocation.sh"); string(content) != string(embedded) {
+		t.Errorf("expected the embedded script without an override directory")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "hugepages-allocation.sh"), []byte("#!/bin/bash\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "..data"), []byte("ignored"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetOverrideDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	checksum := Checksum()
+	if content, _ := Scripts.ReadFile("scripts/hugepages-allocation.sh"); string(content) != "#!/bin/bash\n" {
+		t.Errorf("expected the overridden script, got %q", content)
+	}
+	if _, err := Scripts.ReadFile("scripts/set-cpus-offline.sh"); err != nil {
+		t.Errorf("expected the embedded script not overridden, got %v", err)
+	}
+	if _, err := Configs.ReadFile("configs/hugepages-allocation.sh"); err == nil {
+		t.Errorf("expected the override of a missing asset not to be served")
+	}
+
+	if changed, err := Refresh(); err != nil || changed {
+		t.Errorf("expected no change of the override directory, got %v, %v", changed, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "ovs.slice"), []byte("{{.OvsSlice}} override"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := Refresh(); err != nil || !changed {
+		t.Errorf("expected the override directory to change, got %v, %v", changed, err)
+	}
+	if Checksum() == checksum {
+		t.Errorf("expected the checksum to change")
+	}
+	tmpl, err := template.ParseFS(Configs, "configs/ovs.slice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Tree.Root.String() != "{{.OvsSlice}} override" {
+		t.Errorf("expected the overridden template, got %q", tmpl.Tree.Root.String())
+	}
+}
@@ -0,0 +1,36 @@
+#!/usr/bin/bash
+
+set -euo pipefail
+
+# Runs the latency test tool on the CPUs allocated to the container and reports
+# the maximum measured latency, in microseconds, via the container termination message.
+
+if [ -f /sys/fs/cgroup/cpuset.cpus.effective ]; then
+  cpus=$(cat /sys/fs/cgroup/cpuset.cpus.effective)
+else
+  cpus=$(cat /sys/fs/cgroup/cpuset/cpuset.cpus)
+fi
+# the first CPU runs the tool main thread, the remaining ones run the measurement threads
+main_cpu=${cpus%%[,-]*}
+
+echo "running ${LATENCY_PROBE_TOOL} for ${LATENCY_PROBE_DURATION} on cpus ${cpus}"
+
+case "${LATENCY_PROBE_TOOL}" in
+  cyclictest)
+    max_latency=$(cyclictest --quiet --duration="${LATENCY_PROBE_DURATION}" --priority=95 --mlockall \
+      --histogram=30 --affinity="${cpus}" --threads --mainaffinity="${main_cpu}" |
+      awk '/^# Max Latencies:/ { for (i = 4; i <= NF; i++) if ($i + 0 > max) max = $i + 0 } END { print max + 0 }')
+    ;;
+  oslat)
+    max_latency=$(oslat --duration "${LATENCY_PROBE_DURATION}" --rtprio 1 \
+      --cpu-list "${cpus}" --cpu-main-thread "${main_cpu}" |
+      awk '/Maximum:/ { for (i = 2; i <= NF; i++) if ($i ~ /^[0-9]+$/ && $i + 0 > max) max = $i + 0 } END { print max + 0 }')
+    ;;
+  *)
+    echo "unsupported latency test tool ${LATENCY_PROBE_TOOL}" | tee /dev/termination-log
+    exit 1
+    ;;
+esac
+
+echo "maximum latency: ${max_latency}us"
+echo -n "${max_latency}" > /dev/termination-log
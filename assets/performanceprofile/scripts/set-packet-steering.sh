@@ -0,0 +1,34 @@
+#!/usr/bin/env bash
+
+# Sets the receive (RPS) and the transmit (XPS) packet steering masks of the network device queues.
+# A dash (-) instead of a mask keeps the corresponding queues untouched.
+
+function set_queues_mask() {
+  local queues=${1}
+  local file=${2}
+  local mask=${3}
+
+  [ "${mask}" != "-" ] || return 0
+  for queue in /sys/"${path}"/queues/${queues}-*; do
+    echo "${mask}" 2> /dev/null > "${queue}/${file}"
+  done
+}
+
+path=${1}
+[ -n "${path}" ] || { echo "The device path argument is missing" >&2 ; exit 1; }
+# replace x2d with hyphen (-) which is an escaped character
+# that was added by systemd-escape in order to escape the systemd unit name that invokes this script
+path=${path/x2d/-}
+
+rps_mask=${2}
+[ -n "${rps_mask}" ] || { echo "The RPS mask argument is missing" >&2 ; exit 1; }
+
+xps_mask=${3}
+[ -n "${xps_mask}" ] || { echo "The XPS mask argument is missing" >&2 ; exit 1; }
+
+set_queues_mask rx rps_cpus "${rps_mask}"
+set_queues_mask tx xps_cpus "${xps_mask}"
+
+# we return 0 because the 'echo' command might fail if the device path to which the queue belongs has changed.
+# this can happen in case of SRI-OV devices renaming.
+exit 0
@@ -0,0 +1,45 @@
+#!/usr/bin/bash
+
+set -euo pipefail
+
+# Keeps a single thread online for every core hosting isolated CPUs by setting offline
+# the remaining isolated sibling threads. Reserved CPUs are never set offline.
+
+is_isolated() {
+  [[ ",${ISOLATED_CPUS}," == *",$1,"* ]]
+}
+
+expand_cpu_list() {
+  local cpus=()
+  local range
+  for range in ${1//,/ }; do
+    if [[ "${range}" == *-* ]]; then
+      cpus+=($(seq "${range%-*}" "${range#*-}"))
+    else
+      cpus+=("${range}")
+    fi
+  done
+  echo "${cpus[@]}"
+}
+
+for cpu in ${ISOLATED_CPUS//,/ };
+  do
+    siblings_file="/sys/devices/system/cpu/cpu$cpu/topology/thread_siblings_list"
+    if [ ! -f "${siblings_file}" ]; then
+      echo "cpu num $cpu is not online, skipping"
+      continue
+    fi
+
+    # the first isolated thread of the core is kept online
+    for sibling in $(expand_cpu_list "$(cat "${siblings_file}")");
+      do
+        if is_isolated "$sibling"; then
+          break
+        fi
+      done
+
+    if [ "$sibling" != "$cpu" ]; then
+      echo 0 > "/sys/devices/system/cpu/cpu$cpu/online"
+      echo "offline cpu num $cpu, sibling of cpu num $sibling"
+    fi
+  done
@@ -15,6 +15,7 @@ import (
 	performancev1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v1"
 	performancev1alpha1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v1alpha1"
 	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	ppwebhook "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2/webhook"
 	paocontroller "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/machineconfig"
 	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
@@ -40,9 +41,13 @@ import (
 
 	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/config"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/effectivetuning"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/metrics"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/operator"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/bootstrap"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/cmd/advise"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/cmd/render"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/tracing"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/tuned/cmd/operand"
 	tunedrender "github.com/openshift/cluster-node-tuning-operator/pkg/tuned/cmd/render"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
@@ -103,6 +108,7 @@ func prepareCommands() {
 
 	if !config.InHyperShift() {
 		rootCmd.AddCommand(render.NewRenderCommand())
+		rootCmd.AddCommand(advise.NewAdviseCommand())
 		rootCmd.AddCommand(tunedrender.NewRenderBootCmdMCCommand())
 	}
 	rootCmd.AddCommand(operand.NewTunedCommand())
@@ -125,6 +131,12 @@ func operatorRun() {
 		metav1.NamespaceNone,
 	}
 
+	shutdownTracing, err := tracing.Setup(context.TODO(), version.OperatorFilename)
+	if err != nil {
+		klog.Exit(err)
+	}
+	defer shutdownTracing(context.Background())
+
 	restConfig := ctrl.GetConfigOrDie()
 	le := util.GetLeaderElectionConfig(restConfig, enableLeaderElection)
 	mgr, err := ctrl.NewManager(rest.AddUserAgent(restConfig, version.OperatorFilename), ctrl.Options{
@@ -154,6 +166,10 @@ func operatorRun() {
 		if err := migratePinnedSingleNodeInfraStatus(restConfig, scheme); err != nil {
 			klog.Fatalf("unable to migrate pinned single node infra status: %v", err)
 		}
+
+		if err := adoptBootstrapPerformanceProfiles(restConfig, scheme, ntoNamespace); err != nil {
+			klog.Fatalf("unable to adopt the bootstrap performance profiles: %v", err)
+		}
 	}
 
 	controller, err := operator.NewController()
@@ -165,6 +181,9 @@ func operatorRun() {
 		klog.Fatalf("failed to add new controller to the manager: %v", err)
 	}
 
+	if !config.InHyperShift() {
+		metrics.Handle(effectivetuning.PathPrefix, effectivetuning.NewHandler(mgr.GetAPIReader(), ntoNamespace))
+	}
 	if err := mgr.Add(metrics.Server{}); err != nil {
 		klog.Fatalf("unable to add metrics server as runnable under the manager: %v", err)
 	}
@@ -180,17 +199,50 @@ func operatorRun() {
 			Scheme:      mgr.GetScheme(),
 			Recorder:    mgr.GetEventRecorderFor("performance-profile-controller"),
 			FeatureGate: fg,
+			APIReader:   mgr.GetAPIReader(),
+
+			MachineConfigWriteQPS:   config.MachineConfigWriteQPS(),
+			MachineConfigWriteBurst: config.MachineConfigWriteBurst(),
+			MachineConfigSyncWindow: config.MachineConfigSyncWindow(),
+			AssetsOverrideDir:       config.AssetsOverrideDir(),
+			AssetsRefreshInterval:   config.AssetsRefreshInterval(),
+			ReadOnly:                config.ReadOnly(),
 		}).SetupWithManager(mgr); err != nil {
 			klog.Exitf("unable to create PerformanceProfile controller: %v", err)
 		}
 
+		if err = (&paocontroller.TuningBundleReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("tuning-bundle-controller"),
+			ReadOnly: config.ReadOnly(),
+		}).SetupWithManager(mgr); err != nil {
+			klog.Exitf("unable to create TuningBundle controller: %v", err)
+		}
+
+		if err = (&paocontroller.RuntimeClassInjectionReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("runtimeclass-injection-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			klog.Exitf("unable to create runtime class injection controller: %v", err)
+		}
+
 		if err = (&performancev1.PerformanceProfile{}).SetupWebhookWithManager(mgr); err != nil {
 			klog.Exitf("unable to create PerformanceProfile v1 webhook: %v", err)
 		}
 
-		if err = (&performancev2.PerformanceProfile{}).SetupWebhookWithManager(mgr); err != nil {
+		if err = ppwebhook.SetupWithManager(mgr); err != nil {
 			klog.Exitf("unable to create PerformanceProfile v2 webhook: %v", err)
 		}
+
+		if err = (&tunedv1.Tuned{}).SetupWebhookWithManager(mgr); err != nil {
+			klog.Exitf("unable to create Tuned webhook: %v", err)
+		}
+
+		mgr.GetWebhookServer().Register(paocontroller.RuntimeClassInjectionWebhookPath, &webhook.Admission{
+			Handler: paocontroller.NewRuntimeClassInjector(mgr.GetClient(), mgr.GetScheme()),
+		})
 	}
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		klog.Exitf("manager exited with non-zero code: %v", err)
@@ -260,6 +312,17 @@ func migratePinnedSingleNodeInfraStatus(cfg *rest.Config, scheme *apiruntime.Sch
 	return nil
 }
 
+// adoptBootstrapPerformanceProfiles creates the PerformanceProfiles delivered via the
+// bootstrap ConfigMap by installation flows that could not create them at day 0.
+func adoptBootstrapPerformanceProfiles(cfg *rest.Config, scheme *apiruntime.Scheme, namespace string) error {
+	k8sclient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	return bootstrap.Adopt(context.Background(), k8sclient, namespace)
+}
+
 func setupFeatureGates(ctx context.Context, config *rest.Config, operatorNamespace string) (featuregates.FeatureGate, error) {
 	missingVersion := "0.0.1-snapshot"
 	desiredVersion := missingVersion
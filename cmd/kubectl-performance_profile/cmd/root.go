@@ -0,0 +1,221 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2026 Red Hat, Inc.
+ */
+
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	ntoconfig "github.com/openshift/cluster-node-tuning-operator/pkg/config"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/wizard"
+)
+
+type outputOpts struct {
+	output string
+	apply  bool
+}
+
+// NewRootCommand returns entrypoint command of the kubectl and oc plugin, the plugin
+// is invoked as "oc performance-profile" once the binary is found in the PATH
+func NewRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:          "kubectl-performance_profile",
+		Short:        "A tool that walks through the creation and the edition of Performance Profiles",
+		SilenceUsage: true,
+	}
+
+	// the controller-runtime configuration registers the kubeconfig flag
+	root.PersistentFlags().AddGoFlagSet(flag.CommandLine)
+
+	root.AddCommand(newCreateCommand())
+	root.AddCommand(newEditCommand())
+	return root
+}
+
+func newCreateCommand() *cobra.Command {
+	opts := &outputOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a performance profile for the nodes of a machine config pool",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, w, err := newWizard(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			profile, warnings, err := w.Create(cmd.Context())
+			if err != nil {
+				return err
+			}
+			printWarnings(warnings)
+			return opts.write(cmd.Context(), c, profile, true)
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func newEditCommand() *cobra.Command {
+	opts := &outputOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "edit NAME",
+		Short: "Edit an existing performance profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, w, err := newWizard(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			profile, warnings, err := w.Edit(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			printWarnings(warnings)
+			return opts.write(cmd.Context(), c, profile, false)
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func (o *outputOpts) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.output, "output", o.output, "Path of the file to save the performance profile to, the standard output when not set and the profile is not applied")
+	fs.BoolVar(&o.apply, "apply", o.apply, "Create or update the performance profile in the cluster")
+}
+
+// write applies the profile to the cluster and saves it to the output file
+func (o *outputOpts) write(ctx context.Context, c client.Client, profile *performancev2.PerformanceProfile, create bool) error {
+	data, err := marshalProfile(profile)
+	if err != nil {
+		return err
+	}
+
+	if o.apply {
+		if create {
+			err = c.Create(ctx, profile)
+		} else {
+			err = c.Update(ctx, profile)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to apply the performance profile %q: %w", profile.Name, err)
+		}
+		fmt.Fprintf(os.Stderr, "Performance profile %s applied\n", profile.Name)
+	}
+
+	if len(o.output) > 0 {
+		if err := os.WriteFile(o.output, data, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Performance profile %s written to %s\n", profile.Name, o.output)
+		return nil
+	}
+
+	if !o.apply {
+		_, err = os.Stdout.Write(data)
+	}
+	return err
+}
+
+func newWizard(ctx context.Context) (client.Client, *wizard.Wizard, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		apiextensionsv1.AddToScheme,
+		mcfgv1.AddToScheme,
+		performancev2.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schema, err := wizard.LoadSchema(ctx, c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snapshots := &wizard.TunedPodSnapshots{
+		Config:    cfg,
+		Client:    clientset,
+		Namespace: ntoconfig.OperatorNamespace(),
+	}
+	// the questions go to the standard error so the standard output holds the profile only
+	return c, wizard.New(c, schema, snapshots, os.Stdin, os.Stderr), nil
+}
+
+func printWarnings(warnings admission.Warnings) {
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+}
+
+func marshalProfile(profile *performancev2.PerformanceProfile) ([]byte, error) {
+	// keep only the metadata needed to apply the profile
+	saved := profile.DeepCopy()
+	saved.ObjectMeta = metav1.ObjectMeta{
+		Name:        profile.Name,
+		Labels:      profile.Labels,
+		Annotations: profile.Annotations,
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(saved)
+	if err != nil {
+		return nil, err
+	}
+	unstructured.RemoveNestedField(obj, "status")
+	unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+
+	return yaml.Marshal(obj)
+}
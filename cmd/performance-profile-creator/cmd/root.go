@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -36,6 +37,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	kubeletconfig "k8s.io/kubelet/config/v1beta1"
+	"k8s.io/utils/cpuset"
 	"k8s.io/utils/pointer"
 )
 
@@ -77,7 +79,11 @@ const (
 	ultraLowLatency string = "ultra-low-latency"
 )
 
+// reservedEfficiencyCores is the symbolic reserved CPUs selection of the efficiency cores of hybrid processors
+const reservedEfficiencyCores = "efficiencyCores"
+
 var (
+	validReservedCPUs          = []string{reservedEfficiencyCores}
 	validTMPolicyValues        = []string{kubeletconfig.SingleNumaNodeTopologyManagerPolicy, kubeletconfig.BestEffortTopologyManagerPolicy, kubeletconfig.RestrictedTopologyManagerPolicy}
 	validInfoModes             = []string{infoModeLog, infoModeJSON}
 	validPowerConsumptionModes = []string{defaultLatency, lowLatency, ultraLowLatency}
@@ -95,6 +101,8 @@ type ProfileData struct {
 	isolatedCPUs              string
 	reservedCPUs              string
 	offlinedCPUs              string
+	efficiencyCPUs            string
+	allowMixedCoreTypes       bool
 	nodeSelector              *metav1.LabelSelector
 	mcpSelector               map[string]string
 	performanceProfileName    string
@@ -107,6 +115,7 @@ type ProfileData struct {
 	highPowerConsumptionHint  *bool
 	perPodPowerManagementHint *bool
 	enableHardwareTuning      bool
+	explanations              []string
 }
 
 // ClusterData collects the cluster wide information, each mcp points to a list of ghw node handlers
@@ -165,6 +174,13 @@ func NewRootCommand() *cobra.Command {
 				return nil
 			}
 
+			if cmd.Flag("reserved-cpus").Changed {
+				if cmd.Flag("reserved-cpu-count").Changed {
+					return fmt.Errorf("--reserved-cpus and --reserved-cpu-count are mutually exclusive")
+				}
+				// the symbolic selection replaces the reserved CPU count
+				requiredFlags = requiredFlagsExcept(requiredFlags, "reserved-cpu-count")
+			}
 			missingRequiredFlags := checkRequiredFlags(cmd, requiredFlags...)
 			if len(missingRequiredFlags) > 0 {
 				return fmt.Errorf("missing required flags: %s", strings.Join(argNameToFlag(missingRequiredFlags), ", "))
@@ -180,21 +196,37 @@ func NewRootCommand() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("failed to obtain data from flags %v", err)
 			}
-			profileData, err := getProfileData(profileCreatorArgsFromFlags, cluster)
+			mcpNames, err := parseMCPNames(profileCreatorArgsFromFlags.MCPName)
 			if err != nil {
 				return err
 			}
 
-			err = createProfile(*profileData)
-			return err
+			outputDir := cmd.Flag("output-dir").Value.String()
+			if len(mcpNames) == 1 && outputDir == "" {
+				profileData, err := getProfileData(profileCreatorArgsFromFlags, cluster)
+				if err != nil {
+					return err
+				}
+				return createProfile(*profileData, os.Stdout)
+			}
+
+			if err := ensureMCPsDoNotOverlap(cluster, mcpNames); err != nil {
+				return err
+			}
+			if outputDir == "" {
+				outputDir = "."
+			}
+			return createProfiles(profileCreatorArgsFromFlags, cluster, mcpNames, outputDir)
 		},
 	}
 
 	root.PersistentFlags().IntVar(&pcArgs.ReservedCPUCount, "reserved-cpu-count", 0, "Number of reserved CPUs (required)")
+	root.PersistentFlags().StringVar(&pcArgs.ReservedCPUs, "reserved-cpus", "", fmt.Sprintf("Symbolic selection of the reserved CPUs, instead of --reserved-cpu-count. [Valid values: %s]", strings.Join(validReservedCPUs, ", ")))
 	root.PersistentFlags().IntVar(&pcArgs.OfflinedCPUCount, "offlined-cpu-count", 0, "Number of offlined CPUs")
 	root.PersistentFlags().BoolVar(&pcArgs.SplitReservedCPUsAcrossNUMA, "split-reserved-cpus-across-numa", false, "Split the Reserved CPUs across NUMA nodes")
-	root.PersistentFlags().StringVar(&pcArgs.MCPName, "mcp-name", "", "MCP name corresponding to the target machines (required)")
+	root.PersistentFlags().StringVar(&pcArgs.MCPName, "mcp-name", "", "Comma separated list of MCP names corresponding to the target machines, one profile is created per MCP (required)")
 	root.PersistentFlags().BoolVar(&pcArgs.DisableHT, "disable-ht", false, "Disable Hyperthreading")
+	root.PersistentFlags().BoolVar(&pcArgs.AllowMixedCoreTypes, "allow-mixed-core-types", false, "Allow the isolated CPUs to mix efficiency and performance cores on hybrid processors")
 	root.PersistentFlags().BoolVar(&pcArgs.RTKernel, "rt-kernel", false, "Enable Real Time Kernel (required)")
 	root.PersistentFlags().BoolVar(pcArgs.UserLevelNetworking, "user-level-networking", false, "Run with User level Networking(DPDK) enabled")
 	root.PersistentFlags().StringVar(&pcArgs.PowerConsumptionMode, "power-consumption-mode", defaultLatency, fmt.Sprintf("The power consumption mode.  [Valid values: %s]", strings.Join(validPowerConsumptionModes, ", ")))
@@ -204,6 +236,8 @@ func NewRootCommand() *cobra.Command {
 	root.PersistentFlags().StringVar(&pcArgs.Info, "info", infoModeLog, fmt.Sprintf("Show cluster information; requires --must-gather-dir-path, ignore the other arguments. [Valid values: %s]", strings.Join(validInfoModes, ", ")))
 	root.PersistentFlags().BoolVar(pcArgs.PerPodPowerManagement, "per-pod-power-management", false, "Enable Per Pod Power Management")
 	root.PersistentFlags().BoolVar(&pcArgs.EnableHardwareTuning, "enable-hardware-tuning", false, "Enable setting maximum cpu frequencies")
+	root.PersistentFlags().BoolVar(&pcArgs.Explain, "explain", false, "Annotate the created profile with comments explaining how every value was chosen")
+	root.PersistentFlags().StringVar(&pcArgs.OutputDir, "output-dir", "", "Directory to write one <profile-name>-<mcp-name>.yaml profile per MCP into; defaults to the current directory when several MCPs are given, and to the standard output otherwise")
 
 	return root
 }
@@ -218,6 +252,16 @@ func checkRequiredFlags(cmd *cobra.Command, argNames ...string) []string {
 	return missing
 }
 
+func requiredFlagsExcept(argNames []string, except string) []string {
+	var required []string
+	for _, argName := range argNames {
+		if argName != except {
+			required = append(required, argName)
+		}
+	}
+	return required
+}
+
 func argNameToFlag(argNames []string) []string {
 	var flagNames []string
 	for _, argName := range argNames {
@@ -372,6 +416,12 @@ func getDataFromFlags(cmd *cobra.Command) (ProfileCreatorArgs, error) {
 	if err != nil {
 		return creatorArgs, fmt.Errorf("failed to parse reserved-cpu-count flag: %v", err)
 	}
+	reservedCPUs := cmd.Flag("reserved-cpus").Value.String()
+	if reservedCPUs != "" {
+		if err := validateFlag("reserved-cpus", reservedCPUs, validReservedCPUs); err != nil {
+			return creatorArgs, fmt.Errorf("invalid value for reserved-cpus flag specified: %v", err)
+		}
+	}
 	offlinedCPUCount, err := strconv.Atoi(cmd.Flag("offlined-cpu-count").Value.String())
 	if err != nil {
 		return creatorArgs, fmt.Errorf("failed to parse offlined-cpu-count flag: %v", err)
@@ -392,6 +442,9 @@ func getDataFromFlags(cmd *cobra.Command) (ProfileCreatorArgs, error) {
 	if tmPolicy == kubeletconfig.SingleNumaNodeTopologyManagerPolicy && splitReservedCPUsAcrossNUMA {
 		return creatorArgs, fmt.Errorf("not appropriate to split reserved CPUs in case of topology-manager-policy: %v", tmPolicy)
 	}
+	if reservedCPUs != "" && splitReservedCPUsAcrossNUMA {
+		return creatorArgs, fmt.Errorf("not appropriate to split reserved CPUs in case of reserved-cpus: %v", reservedCPUs)
+	}
 	powerConsumptionMode := cmd.Flag("power-consumption-mode").Value.String()
 	if err != nil {
 		return creatorArgs, fmt.Errorf("failed to parse power-consumption-mode flag: %v", err)
@@ -416,10 +469,21 @@ func getDataFromFlags(cmd *cobra.Command) (ProfileCreatorArgs, error) {
 		return creatorArgs, fmt.Errorf("failed to parse enable-hardware-tuning flag: %v", err)
 	}
 
+	explain, err := strconv.ParseBool(cmd.Flag("explain").Value.String())
+	if err != nil {
+		return creatorArgs, fmt.Errorf("failed to parse explain flag: %v", err)
+	}
+
+	allowMixedCoreTypes, err := strconv.ParseBool(cmd.Flag("allow-mixed-core-types").Value.String())
+	if err != nil {
+		return creatorArgs, fmt.Errorf("failed to parse allow-mixed-core-types flag: %v", err)
+	}
+
 	creatorArgs = ProfileCreatorArgs{
 		MustGatherDirPath:           mustGatherDirPath,
 		ProfileName:                 profileName,
 		ReservedCPUCount:            reservedCPUCount,
+		ReservedCPUs:                reservedCPUs,
 		OfflinedCPUCount:            offlinedCPUCount,
 		SplitReservedCPUsAcrossNUMA: splitReservedCPUsAcrossNUMA,
 		MCPName:                     mcpName,
@@ -428,6 +492,8 @@ func getDataFromFlags(cmd *cobra.Command) (ProfileCreatorArgs, error) {
 		PowerConsumptionMode:        powerConsumptionMode,
 		DisableHT:                   htDisabled,
 		EnableHardwareTuning:        hwEnabled,
+		Explain:                     explain,
+		AllowMixedCoreTypes:         allowMixedCoreTypes,
 	}
 
 	if cmd.Flag("user-level-networking").Changed {
@@ -496,10 +562,22 @@ func getProfileData(args ProfileCreatorArgs, cluster ClusterData) (*ProfileData,
 	if err != nil {
 		return nil, fmt.Errorf("failed to compute get system information: %v", err)
 	}
-	reservedCPUs, isolatedCPUs, offlinedCPUs, err := profilecreator.CalculateCPUSets(systemInfo, args.ReservedCPUCount, args.OfflinedCPUCount, args.SplitReservedCPUsAcrossNUMA, args.DisableHT, args.PowerConsumptionMode == ultraLowLatency)
+	var reservedCPUs, isolatedCPUs, offlinedCPUs cpuset.CPUSet
+	if args.ReservedCPUs == reservedEfficiencyCores {
+		reservedCPUs, isolatedCPUs, offlinedCPUs, err = profilecreator.CalculateCPUSetsReservingEfficiencyCores(systemInfo, args.OfflinedCPUCount, args.DisableHT, args.PowerConsumptionMode == ultraLowLatency)
+	} else {
+		reservedCPUs, isolatedCPUs, offlinedCPUs, err = profilecreator.CalculateCPUSets(systemInfo, args.ReservedCPUCount, args.OfflinedCPUCount, args.SplitReservedCPUsAcrossNUMA, args.DisableHT, args.PowerConsumptionMode == ultraLowLatency)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to compute the reserved and isolated CPUs: %v", err)
 	}
+	if !systemInfo.EfficiencyCPUs.IsEmpty() {
+		log.Infof("%d efficiency CPUs detected: %v", systemInfo.EfficiencyCPUs.Size(), systemInfo.EfficiencyCPUs.String())
+		if profilecreator.HasMixedCoreTypes(systemInfo, isolatedCPUs) && !args.AllowMixedCoreTypes {
+			return nil, fmt.Errorf("the isolated CPUs %s mix efficiency and performance cores, please use --reserved-cpus=%s to reserve the efficiency cores or --allow-mixed-core-types",
+				isolatedCPUs.String(), reservedEfficiencyCores)
+		}
+	}
 	log.Infof("%d reserved CPUs allocated: %v ", reservedCPUs.Size(), reservedCPUs.String())
 	log.Infof("%d isolated CPUs allocated: %v", isolatedCPUs.Size(), isolatedCPUs.String())
 	kernelArgs := profilecreator.GetAdditionalKernelArgs(args.DisableHT)
@@ -507,6 +585,8 @@ func getProfileData(args ProfileCreatorArgs, cluster ClusterData) (*ProfileData,
 		reservedCPUs:              reservedCPUs.String(),
 		offlinedCPUs:              offlinedCPUs.String(),
 		isolatedCPUs:              isolatedCPUs.String(),
+		efficiencyCPUs:            systemInfo.EfficiencyCPUs.String(),
+		allowMixedCoreTypes:       args.AllowMixedCoreTypes,
 		nodeSelector:              mcp.Spec.NodeSelector,
 		mcpSelector:               mcpSelector,
 		performanceProfileName:    args.ProfileName,
@@ -540,6 +620,29 @@ func getProfileData(args ProfileCreatorArgs, cluster ClusterData) (*ProfileData,
 			)
 		}
 	}
+
+	if args.Explain {
+		profileData.explanations = profilecreator.ExplainProfile(profilecreator.ExplainArgs{
+			MCPName:                     args.MCPName,
+			ReservedCPUCount:            args.ReservedCPUCount,
+			ReserveEfficiencyCores:      args.ReservedCPUs == reservedEfficiencyCores,
+			OfflinedCPUCount:            args.OfflinedCPUCount,
+			SplitReservedCPUsAcrossNUMA: args.SplitReservedCPUsAcrossNUMA,
+			DisableHT:                   args.DisableHT,
+			AllowMixedCoreTypes:         args.AllowMixedCoreTypes,
+			TMPolicy:                    args.TMPolicy,
+			PowerConsumptionMode:        args.PowerConsumptionMode,
+			RealTimeHint:                profileData.realtimeHint != nil && *profileData.realtimeHint,
+			HighPowerConsumptionHint:    profileData.highPowerConsumptionHint != nil && *profileData.highPowerConsumptionHint,
+			PerPodPowerManagement:       args.PerPodPowerManagement,
+			RTKernel:                    args.RTKernel,
+			UserLevelNetworking:         args.UserLevelNetworking,
+		}, matchedNodeNames, systemInfo, profilecreator.CPUAllocation{
+			Reserved: reservedCPUs,
+			Isolated: isolatedCPUs,
+			Offlined: offlinedCPUs,
+		})
+	}
 	return profileData, nil
 }
 
@@ -566,6 +669,7 @@ type ProfileCreatorArgs struct {
 	MustGatherDirPath           string `json:"must-gather-dir-path"`
 	ProfileName                 string `json:"profile-name"`
 	ReservedCPUCount            int    `json:"reserved-cpu-count"`
+	ReservedCPUs                string `json:"reserved-cpus,omitempty"`
 	OfflinedCPUCount            int    `json:"offlined-cpu-count"`
 	SplitReservedCPUsAcrossNUMA bool   `json:"split-reserved-cpus-across-numa"`
 	DisableHT                   bool   `json:"disable-ht"`
@@ -576,9 +680,94 @@ type ProfileCreatorArgs struct {
 	Info                        string `json:"info"`
 	PerPodPowerManagement       *bool  `json:"per-pod-power-management,omitempty"`
 	EnableHardwareTuning        bool   `json:"enable-hardware-tuning,omitempty"`
+	OutputDir                   string `json:"output-dir,omitempty"`
+	Explain                     bool   `json:"explain,omitempty"`
+	AllowMixedCoreTypes         bool   `json:"allow-mixed-core-types,omitempty"`
+}
+
+// parseMCPNames splits the comma separated MCP names of the mcp-name flag
+func parseMCPNames(value string) ([]string, error) {
+	var mcpNames []string
+	seen := map[string]bool{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("invalid value for mcp-name flag specified: %q contains an empty MCP name", value)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("invalid value for mcp-name flag specified: '%s' MCP is listed more than once", name)
+		}
+		seen[name] = true
+		mcpNames = append(mcpNames, name)
+	}
+	return mcpNames, nil
 }
 
-func createProfile(profileData ProfileData) error {
+// ensureMCPsDoNotOverlap makes sure every node is targeted by a single one of the given MCPs,
+// otherwise the node would be tuned by several of the created profiles
+func ensureMCPsDoNotOverlap(cluster ClusterData, mcpNames []string) error {
+	nodeToMCP := map[string]string{}
+	for mcp, nodeHandlers := range cluster {
+		if !isStringInSlice(mcp.Name, mcpNames) {
+			continue
+		}
+		for _, nodeHandler := range nodeHandlers {
+			nodeName := nodeHandler.Node.GetName()
+			if other, ok := nodeToMCP[nodeName]; ok {
+				return fmt.Errorf("node %q is targeted by both '%s' and '%s' MCPs", nodeName, other, mcp.Name)
+			}
+			nodeToMCP[nodeName] = mcp.Name
+		}
+	}
+	return nil
+}
+
+// createProfiles creates a profile per MCP, all of them computed with the same reserved and offlined CPUs policy,
+// and writes each one of them to the <profile-name>-<mcp-name>.yaml file of the output directory
+func createProfiles(args ProfileCreatorArgs, cluster ClusterData, mcpNames []string, outputDir string) error {
+	var profilesData []*ProfileData
+	for _, mcpName := range mcpNames {
+		mcpArgs := args
+		mcpArgs.MCPName = mcpName
+		if len(mcpNames) > 1 {
+			mcpArgs.ProfileName = fmt.Sprintf("%s-%s", args.ProfileName, mcpName)
+		}
+
+		profileData, err := getProfileData(mcpArgs, cluster)
+		if err != nil {
+			return fmt.Errorf("failed to create the profile for '%s' MCP: %w", mcpName, err)
+		}
+		profilesData = append(profilesData, profileData)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create the output directory: %w", err)
+	}
+
+	for _, profileData := range profilesData {
+		profilePath := filepath.Join(outputDir, fmt.Sprintf("%s.yaml", profileData.performanceProfileName))
+		if err := writeProfile(*profileData, profilePath); err != nil {
+			return err
+		}
+		log.Infof("Performance profile %s written to %s", profileData.performanceProfileName, profilePath)
+	}
+	return nil
+}
+
+func writeProfile(profileData ProfileData, profilePath string) error {
+	f, err := os.Create(profilePath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", profilePath, err)
+	}
+	defer f.Close()
+
+	if err := createProfile(profileData, f); err != nil {
+		return fmt.Errorf("failed to write %s: %w", profilePath, err)
+	}
+	return f.Close()
+}
+
+func createProfile(profileData ProfileData, out io.Writer) error {
 	reserved := performancev2.CPUSet(profileData.reservedCPUs)
 
 	isolated := performancev2.CPUSet(profileData.isolatedCPUs)
@@ -612,6 +801,14 @@ func createProfile(profileData ProfileData) error {
 		profile.Spec.CPU.Offlined = &offlined
 	}
 
+	if len(profileData.efficiencyCPUs) > 0 {
+		efficiencyCores := performancev2.CPUSet(profileData.efficiencyCPUs)
+		profile.Spec.CPU.EfficiencyCores = &efficiencyCores
+		if profileData.allowMixedCoreTypes {
+			profile.Spec.CPU.AllowMixedCoreTypes = pointer.Bool(true)
+		}
+	}
+
 	if len(profileData.additionalKernelArgs) > 0 {
 		profile.Spec.AdditionalKernelArgs = profileData.additionalKernelArgs
 	}
@@ -647,14 +844,20 @@ func createProfile(profileData ProfileData) error {
 		return err
 	}
 
+	if len(profileData.explanations) > 0 {
+		if err := profilecreator.WriteExplanations(profileData.explanations, &writer); err != nil {
+			return err
+		}
+	}
+
 	if profileData.enableHardwareTuning {
 		if _, err := writer.Write([]byte(hardwareTuningMessage)); err != nil {
 			return err
 		}
 	}
 
-	fmt.Printf("%s", writer.String())
-	return nil
+	_, err := fmt.Fprintf(out, "%s", writer.String())
+	return err
 }
 
 // MarshallObject mashals an object, usually a CSV into YAML
@@ -30,11 +30,10 @@ function rendersync() {
   --asset-input-dir "${INPUT_DIRS}" \
   --asset-output-dir "${ARTIFACT_DIR}"
 
-  cp "${ARTIFACT_DIR}"/*  "${WORKDIR}"/test/e2e/performanceprofile/testdata/render-expected-output/${OUTPUT_DIR}
-  for f in  "${WORKDIR}"/test/e2e/performanceprofile/testdata/render-expected-output/${OUTPUT_DIR}/*
-  do
-    sed -i "s/uid:.*/uid: \"\"/" "${f}"
-  done
+  mkdir -p "${WORKDIR}"/test/e2e/performanceprofile/testdata/render-expected-output/${OUTPUT_DIR}
+  cp -r "${ARTIFACT_DIR}"/*  "${WORKDIR}"/test/e2e/performanceprofile/testdata/render-expected-output/${OUTPUT_DIR}
+  find "${WORKDIR}"/test/e2e/performanceprofile/testdata/render-expected-output/${OUTPUT_DIR} -type f -name "*.yaml" \
+    -exec sed -i "s/uid:.*/uid: \"\"/" {} +
   rm -r "${ARTIFACT_DIR}"
 }
 
@@ -43,4 +42,6 @@ rendersync bootstrap-cluster/performance pinned-cluster/default bootstrap/no-mcp
 rendersync bootstrap-cluster/performance pinned-cluster/default bootstrap-cluster/extra-mcp bootstrap/extra-mcp
 rendersync bootstrap-cluster/performance pinned-cluster/default container-runtime-crun bootstrap/extra-ctrcfg
 rendersync --owner-ref none -- base/performance manual-cluster/performance no-ref 
-rendersync --owner-ref none -- base/performance manual-cluster/cpuFrequency default/cpuFrequency 
\ No newline at end of file
+rendersync --owner-ref none -- base/performance manual-cluster/cpuFrequency default/cpuFrequency
+rendersync hypershift-cluster/nodepool hypershift
+rendersync --output-format kustomize -- bootstrap-cluster/performance pinned-cluster/default bootstrap-cluster/extra-mcp kustomize
@@ -0,0 +1,177 @@
+package performance
+
+import (
+	"fmt"
+	"strings"
+
+	fuzz "github.com/google/gofuzz"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/utils/pointer"
+
+	performancev1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v1"
+	performancev1alpha1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v1alpha1"
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+)
+
+const fuzzIterations = 500
+
+var _ = Describe("PerformanceProfile conversion", func() {
+	var fuzzer *fuzz.Fuzzer
+
+	BeforeEach(func() {
+		fuzzer = fuzz.New().NilChance(0.3).NumElements(1, 3).Funcs(
+			// the conversions do not touch the type meta
+			func(*metav1.TypeMeta, fuzz.Continue) {},
+			func(m *metav1.ObjectMeta, c fuzz.Continue) {
+				m.Name = c.RandString()
+				c.Fuzz(&m.Labels)
+				c.Fuzz(&m.Annotations)
+			},
+			// the conversion data has the JSON precision of the times
+			func(t *metav1.Time, c fuzz.Continue) {
+				*t = metav1.Unix(c.Int63n(1<<32), 0)
+			},
+			// the raw extensions hold JSON objects
+			func(r *runtime.RawExtension, c fuzz.Continue) {
+				r.Raw = []byte(fmt.Sprintf(`{"maxPods":%d}`, c.Int31()))
+			},
+		)
+	})
+
+	It("should round-trip v2 profiles through the hub", func() {
+		for i := 0; i < fuzzIterations; i++ {
+			original := &performancev2.PerformanceProfile{}
+			fuzzer.Fuzz(original)
+
+			hub := &performancev1.PerformanceProfile{}
+			Expect(original.DeepCopy().ConvertTo(hub)).To(Succeed())
+			converted := &performancev2.PerformanceProfile{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+
+			expectEqual(original, converted)
+		}
+	})
+
+	It("should round-trip v2 profiles through v1alpha1", func() {
+		for i := 0; i < fuzzIterations; i++ {
+			original := &performancev2.PerformanceProfile{}
+			fuzzer.Fuzz(original)
+
+			hub := &performancev1.PerformanceProfile{}
+			Expect(original.DeepCopy().ConvertTo(hub)).To(Succeed())
+			spoke := &performancev1alpha1.PerformanceProfile{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			hub = &performancev1.PerformanceProfile{}
+			Expect(spoke.ConvertTo(hub)).To(Succeed())
+			converted := &performancev2.PerformanceProfile{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+
+			expectEqual(original, converted)
+		}
+	})
+
+	It("should round-trip hub profiles through v2", func() {
+		for i := 0; i < fuzzIterations; i++ {
+			original := &performancev1.PerformanceProfile{}
+			fuzzer.Fuzz(original)
+			// v2 sets an unset globallyDisableIrqLoadBalancing to the v1 default
+			if original.Spec.GloballyDisableIrqLoadBalancing == nil {
+				original.Spec.GloballyDisableIrqLoadBalancing = pointer.Bool(true)
+			}
+
+			spoke := &performancev2.PerformanceProfile{}
+			Expect(spoke.ConvertFrom(original.DeepCopy())).To(Succeed())
+			converted := &performancev1.PerformanceProfile{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+
+			expectEqual(original, withoutConversionData(converted))
+		}
+	})
+
+	It("should round-trip hub profiles through v1alpha1", func() {
+		for i := 0; i < fuzzIterations; i++ {
+			original := &performancev1.PerformanceProfile{}
+			fuzzer.Fuzz(original)
+
+			spoke := &performancev1alpha1.PerformanceProfile{}
+			Expect(spoke.ConvertFrom(original.DeepCopy())).To(Succeed())
+			converted := &performancev1.PerformanceProfile{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+
+			expectEqual(original, converted)
+		}
+	})
+
+	It("should round-trip v1alpha1 profiles through the hub", func() {
+		for i := 0; i < fuzzIterations; i++ {
+			original := &performancev1alpha1.PerformanceProfile{}
+			fuzzer.Fuzz(original)
+
+			hub := &performancev1.PerformanceProfile{}
+			Expect(original.DeepCopy().ConvertTo(hub)).To(Succeed())
+			converted := &performancev1alpha1.PerformanceProfile{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+
+			expectEqual(original, withoutConversionData(converted))
+		}
+	})
+
+	It("should keep the changes made through the hub", func() {
+		original := &performancev2.PerformanceProfile{
+			Spec: performancev2.PerformanceProfileSpec{
+				CPU: &performancev2.CPU{
+					Reserved: cpuSetV2("0-1"),
+					Isolated: cpuSetV2("2-5"),
+					Shared:   cpuSetV2("6-7"),
+				},
+				NUMA: &performancev2.NUMA{
+					TopologyPolicy:       pointer.String("single-numa-node"),
+					TopologyManagerScope: pointer.String("pod"),
+				},
+			},
+		}
+
+		hub := &performancev1.PerformanceProfile{}
+		Expect(original.ConvertTo(hub)).To(Succeed())
+		reserved := performancev1.CPUSet("0-3")
+		hub.Spec.CPU.Reserved = &reserved
+		hub.Spec.NUMA = nil
+
+		converted := &performancev2.PerformanceProfile{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		Expect(converted.Spec.CPU.Reserved).To(Equal(cpuSetV2("0-3")))
+		Expect(converted.Spec.CPU.Shared).To(Equal(cpuSetV2("6-7")))
+		Expect(converted.Spec.NUMA).To(BeNil())
+		Expect(converted.Annotations).ToNot(HaveKey(HavePrefix(performancev1.PerformanceProfileConversionDataAnnotation)))
+	})
+})
+
+func expectEqual(expected, actual interface{}) {
+	ExpectWithOffset(1, equality.Semantic.DeepEqual(expected, actual)).To(BeTrue(), diff.ObjectReflectDiff(expected, actual))
+}
+
+// withoutConversionData drops the conversion data the round-trip through a version left on the object
+func withoutConversionData(obj metav1.Object) metav1.Object {
+	annotations := obj.GetAnnotations()
+	for k := range annotations {
+		if strings.HasPrefix(k, performancev1.PerformanceProfileConversionDataAnnotation) {
+			delete(annotations, k)
+		}
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func cpuSetV2(cpus string) *performancev2.CPUSet {
+	cpuSet := performancev2.CPUSet(cpus)
+	return &cpuSet
+}
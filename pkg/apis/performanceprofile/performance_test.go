@@ -16,6 +16,15 @@ const (
 	crdFilename        = "../../../manifests/20-performance-profile.crd.yaml"
 	lastHeartbeatPath  = "/status/conditions/lastHeartbeatTime"
 	lastTransitionPath = "/status/conditions/lastTransitionTime"
+	rollbackTimePath   = "/status/rollback/rollbackTime"
+	// the kubelet config overrides are a KubeletConfiguration snippet the CRD preserves as it is
+	kubeletConfigOverridesPath = "/spec/kubeletConfigOverrides"
+	// the quantities are integers or strings in the CRD, not the structs the quantities are decoded to
+	nodeMemorySizePath = "/spec/hugepages/nodeMemorySize"
+	// the int-or-string values are integers or strings in the CRD, not the structs they are decoded to
+	machineConfigPoolMaxUnavailablePath = "/spec/machineConfigPoolMaxUnavailable"
+	// the durations are strings in the CRD, not the structs they are decoded to
+	maintenanceWindowDurationPath = "/spec/machineConfigPoolMaintenanceWindow/duration"
 )
 
 var _ = Describe("PerformanceProfile CR(D) Schema", func() {
@@ -33,6 +42,11 @@ var _ = Describe("PerformanceProfile CR(D) Schema", func() {
 		pathOmissions := []string{
 			lastHeartbeatPath,
 			lastTransitionPath,
+			rollbackTimePath,
+			kubeletConfigOverridesPath,
+			nodeMemorySizePath,
+			machineConfigPoolMaxUnavailablePath,
+			maintenanceWindowDurationPath,
 		}
 		missingEntries := getMissingEntries(schema, &performancev2.PerformanceProfile{}, pathOmissions...)
 		Expect(missingEntries).To(BeEmpty())
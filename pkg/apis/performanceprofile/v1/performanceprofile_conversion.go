@@ -1,4 +1,62 @@
 package v1
 
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PerformanceProfileConversionDataAnnotation is the prefix of the annotations holding the fields
+// a conversion could not represent in the destination version. The annotation name ends with the
+// version the data was converted from, e.g. "performance.openshift.io/conversion-data.v2", and the
+// value is the JSON encoded spec and status of the source object, so converting the object back
+// restores the fields the destination version lacks. Any later version of the API can reuse it
+// for the fields the hub does not have.
+const PerformanceProfileConversionDataAnnotation = "performance.openshift.io/conversion-data"
+
 // Hub marks this type as a conversion hub.
 func (*PerformanceProfile) Hub() {}
+
+// MarshalConversionData stores the JSON encoded data under the conversion data annotation of the
+// version on the destination object.
+func MarshalConversionData(version string, data interface{}, dst metav1.Object) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the %s conversion data: %w", version, err)
+	}
+
+	annotations := dst.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[conversionDataAnnotation(version)] = string(b)
+	dst.SetAnnotations(annotations)
+	return nil
+}
+
+// UnmarshalConversionData decodes the conversion data annotation of the version into data and
+// removes the annotation from the object. It returns false when there is no annotation.
+func UnmarshalConversionData(version string, src metav1.Object, data interface{}) (bool, error) {
+	annotations := src.GetAnnotations()
+	key := conversionDataAnnotation(version)
+	b, ok := annotations[key]
+	if !ok {
+		return false, nil
+	}
+
+	if err := json.Unmarshal([]byte(b), data); err != nil {
+		return false, fmt.Errorf("failed to unmarshal the %s conversion data: %w", version, err)
+	}
+
+	delete(annotations, key)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	src.SetAnnotations(annotations)
+	return true, nil
+}
+
+func conversionDataAnnotation(version string) string {
+	return PerformanceProfileConversionDataAnnotation + "." + version
+}
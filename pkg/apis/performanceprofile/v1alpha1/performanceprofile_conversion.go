@@ -9,12 +9,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
+// conversionData is the part of the hub profile stored in the v1 conversion data annotation of the
+// v1alpha1 object, see restoreConversionData for the fields v1alpha1 cannot represent.
+type conversionData struct {
+	Spec v1.PerformanceProfileSpec `json:"spec"`
+}
+
 // ConvertTo converts this PerformanceProfile to the Hub version (v1).
 func (curr *PerformanceProfile) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1.PerformanceProfile)
 
 	// ObjectMeta
-	dst.ObjectMeta = curr.ObjectMeta
+	dst.ObjectMeta = *curr.ObjectMeta.DeepCopy()
 
 	// Spec
 	if curr.Spec.CPU != nil {
@@ -112,6 +118,13 @@ func (curr *PerformanceProfile) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	// +kubebuilder:docs-gen:collapse=rote conversion
+
+	restored := &conversionData{}
+	ok, err := v1.UnmarshalConversionData(v1.GroupVersion.Version, dst, restored)
+	if err != nil || !ok {
+		return err
+	}
+	restoreConversionData(dst, restored)
 	return nil
 }
 
@@ -120,7 +133,7 @@ func (curr *PerformanceProfile) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1.PerformanceProfile)
 
 	// ObjectMeta
-	curr.ObjectMeta = src.ObjectMeta
+	curr.ObjectMeta = *src.ObjectMeta.DeepCopy()
 
 	// Spec
 	if src.Spec.CPU != nil {
@@ -217,5 +230,23 @@ func (curr *PerformanceProfile) ConvertFrom(srcRaw conversion.Hub) error {
 	}
 
 	// +kubebuilder:docs-gen:collapse=rote conversion
-	return nil
+
+	// Keep the fields v1alpha1 cannot represent, so converting the object back to v1 is lossless
+	return v1.MarshalConversionData(v1.GroupVersion.Version, &conversionData{Spec: src.Spec}, curr)
+}
+
+// restoreConversionData copies back the fields v1alpha1 cannot represent from the hub object the
+// v1alpha1 object was converted from. The fields v1alpha1 represents keep the converted values, so
+// the changes made through v1alpha1 win.
+func restoreConversionData(dst *v1.PerformanceProfile, restored *conversionData) {
+	spec := &restored.Spec
+
+	if dst.Spec.CPU != nil && spec.CPU != nil {
+		dst.Spec.CPU.Offlined = spec.CPU.Offlined
+	}
+
+	dst.Spec.HardwareTuning = spec.HardwareTuning
+	dst.Spec.Net = spec.Net
+	dst.Spec.GloballyDisableIrqLoadBalancing = spec.GloballyDisableIrqLoadBalancing
+	dst.Spec.WorkloadHints = spec.WorkloadHints
 }
@@ -1,6 +1,8 @@
 package v2
 
 import (
+	"reflect"
+
 	"k8s.io/utils/pointer"
 
 	v1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v1"
@@ -9,12 +11,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
+// conversionData is the part of the profile stored in the v2 conversion data annotation of the hub
+// object, see restoreConversionData for the fields v1 cannot represent.
+type conversionData struct {
+	Spec   PerformanceProfileSpec   `json:"spec"`
+	Status PerformanceProfileStatus `json:"status,omitempty"`
+}
+
 // ConvertTo converts this PerformanceProfile to the Hub version (v1).
 func (curr *PerformanceProfile) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1.PerformanceProfile)
 
 	// ObjectMeta
-	dst.ObjectMeta = curr.ObjectMeta
+	dst.ObjectMeta = *curr.ObjectMeta.DeepCopy()
 
 	// Spec
 	if curr.Spec.CPU != nil {
@@ -31,6 +40,10 @@ func (curr *PerformanceProfile) ConvertTo(dstRaw conversion.Hub) error {
 		if curr.Spec.CPU.BalanceIsolated != nil {
 			dst.Spec.CPU.BalanceIsolated = pointer.Bool(*curr.Spec.CPU.BalanceIsolated)
 		}
+		if curr.Spec.CPU.Offlined != nil {
+			offlined := v1.CPUSet(*curr.Spec.CPU.Offlined)
+			dst.Spec.CPU.Offlined = &offlined
+		}
 	}
 
 	if curr.Spec.HardwareTuning != nil {
@@ -143,6 +156,20 @@ func (curr *PerformanceProfile) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.GloballyDisableIrqLoadBalancing = pointer.Bool(*curr.Spec.GloballyDisableIrqLoadBalancing)
 	}
 
+	if curr.Spec.WorkloadHints != nil {
+		dst.Spec.WorkloadHints = new(v1.WorkloadHints)
+
+		if curr.Spec.WorkloadHints.HighPowerConsumption != nil {
+			dst.Spec.WorkloadHints.HighPowerConsumption = pointer.Bool(*curr.Spec.WorkloadHints.HighPowerConsumption)
+		}
+		if curr.Spec.WorkloadHints.RealTime != nil {
+			dst.Spec.WorkloadHints.RealTime = pointer.Bool(*curr.Spec.WorkloadHints.RealTime)
+		}
+		if curr.Spec.WorkloadHints.PerPodPowerManagement != nil {
+			dst.Spec.WorkloadHints.PerPodPowerManagement = pointer.Bool(*curr.Spec.WorkloadHints.PerPodPowerManagement)
+		}
+	}
+
 	// Status
 	if curr.Status.Conditions != nil {
 		dst.Status.Conditions = make([]conditionsv1.Condition, len(curr.Status.Conditions))
@@ -158,7 +185,9 @@ func (curr *PerformanceProfile) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	// +kubebuilder:docs-gen:collapse=rote conversion
-	return nil
+
+	// Keep the fields v1 cannot represent, so converting the object back to v2 is lossless
+	return v1.MarshalConversionData(GroupVersion.Version, &conversionData{Spec: curr.Spec, Status: curr.Status}, dst)
 }
 
 // ConvertFrom converts from the Hub version (v1) to this version.
@@ -166,7 +195,7 @@ func (curr *PerformanceProfile) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1.PerformanceProfile)
 
 	// ObjectMeta
-	curr.ObjectMeta = src.ObjectMeta
+	curr.ObjectMeta = *src.ObjectMeta.DeepCopy()
 
 	// Spec
 	if src.Spec.CPU != nil {
@@ -183,6 +212,22 @@ func (curr *PerformanceProfile) ConvertFrom(srcRaw conversion.Hub) error {
 		if src.Spec.CPU.BalanceIsolated != nil {
 			curr.Spec.CPU.BalanceIsolated = pointer.Bool(*src.Spec.CPU.BalanceIsolated)
 		}
+		if src.Spec.CPU.Offlined != nil {
+			offlined := CPUSet(*src.Spec.CPU.Offlined)
+			curr.Spec.CPU.Offlined = &offlined
+		}
+	}
+
+	if src.Spec.HardwareTuning != nil {
+		curr.Spec.HardwareTuning = new(HardwareTuning)
+		if src.Spec.HardwareTuning.IsolatedCpuFreq != nil {
+			isolatedCpuFrequency := CPUfrequency(*src.Spec.HardwareTuning.IsolatedCpuFreq)
+			curr.Spec.HardwareTuning.IsolatedCpuFreq = &isolatedCpuFrequency
+		}
+		if src.Spec.HardwareTuning.ReservedCpuFreq != nil {
+			reservedCpuFrequency := CPUfrequency(*src.Spec.HardwareTuning.ReservedCpuFreq)
+			curr.Spec.HardwareTuning.ReservedCpuFreq = &reservedCpuFrequency
+		}
 	}
 
 	if src.Spec.HugePages != nil {
@@ -285,6 +330,20 @@ func (curr *PerformanceProfile) ConvertFrom(srcRaw conversion.Hub) error {
 		curr.Spec.GloballyDisableIrqLoadBalancing = pointer.Bool(true)
 	}
 
+	if src.Spec.WorkloadHints != nil {
+		curr.Spec.WorkloadHints = new(WorkloadHints)
+
+		if src.Spec.WorkloadHints.HighPowerConsumption != nil {
+			curr.Spec.WorkloadHints.HighPowerConsumption = pointer.Bool(*src.Spec.WorkloadHints.HighPowerConsumption)
+		}
+		if src.Spec.WorkloadHints.RealTime != nil {
+			curr.Spec.WorkloadHints.RealTime = pointer.Bool(*src.Spec.WorkloadHints.RealTime)
+		}
+		if src.Spec.WorkloadHints.PerPodPowerManagement != nil {
+			curr.Spec.WorkloadHints.PerPodPowerManagement = pointer.Bool(*src.Spec.WorkloadHints.PerPodPowerManagement)
+		}
+	}
+
 	// Status
 	if src.Status.Conditions != nil {
 		curr.Status.Conditions = make([]conditionsv1.Condition, len(src.Status.Conditions))
@@ -300,5 +359,88 @@ func (curr *PerformanceProfile) ConvertFrom(srcRaw conversion.Hub) error {
 	}
 
 	// +kubebuilder:docs-gen:collapse=rote conversion
+
+	restored := &conversionData{}
+	ok, err := v1.UnmarshalConversionData(GroupVersion.Version, curr, restored)
+	if err != nil || !ok {
+		return err
+	}
+	curr.restoreConversionData(src, restored)
 	return nil
 }
+
+// restoreConversionData copies back the fields v1 cannot represent from the v2 object the hub was
+// converted from. These are the only fields that do not round-trip through v1 when the hub object
+// lost its conversion data annotation, e.g. when a client recreated it from a v1 manifest. The fields
+// v1 represents keep the hub values, so the changes made through v1 win, and a v2 struct the hub
+// object dropped is not brought back.
+func (curr *PerformanceProfile) restoreConversionData(src *v1.PerformanceProfile, restored *conversionData) {
+	spec := &restored.Spec
+
+	if curr.Spec.CPU != nil && spec.CPU != nil {
+		curr.Spec.CPU.Shared = spec.CPU.Shared
+		curr.Spec.CPU.IRQServing = spec.CPU.IRQServing
+		curr.Spec.CPU.NohzFull = spec.CPU.NohzFull
+		curr.Spec.CPU.RCUNocbs = spec.CPU.RCUNocbs
+		curr.Spec.CPU.RCUKthreadPriority = spec.CPU.RCUKthreadPriority
+		curr.Spec.CPU.PinUnboundWorkqueues = spec.CPU.PinUnboundWorkqueues
+		curr.Spec.CPU.SMTPolicy = spec.CPU.SMTPolicy
+		curr.Spec.CPU.EfficiencyCores = spec.CPU.EfficiencyCores
+		curr.Spec.CPU.AllowMixedCoreTypes = spec.CPU.AllowMixedCoreTypes
+		curr.Spec.CPU.IsolationMethod = spec.CPU.IsolationMethod
+	}
+
+	// the allocation and the memory percentage of a page are restored as long as the page was not changed through v1
+	if curr.Spec.HugePages != nil && spec.HugePages != nil {
+		curr.Spec.HugePages.NodeMemorySize = spec.HugePages.NodeMemorySize
+		for i := range curr.Spec.HugePages.Pages {
+			if i >= len(spec.HugePages.Pages) {
+				break
+			}
+			page, restoredPage := &curr.Spec.HugePages.Pages[i], spec.HugePages.Pages[i]
+			if page.Size == restoredPage.Size && page.Count == restoredPage.Count && reflect.DeepEqual(page.Node, restoredPage.Node) {
+				page.Allocation = restoredPage.Allocation
+				page.MemoryPercent = restoredPage.MemoryPercent
+			}
+		}
+	}
+
+	curr.Spec.Memory = spec.Memory
+	curr.Spec.Scheduler = spec.Scheduler
+	curr.Spec.KernelModules = spec.KernelModules
+	curr.Spec.Devices = spec.Devices
+	curr.Spec.UdevRules = spec.UdevRules
+	curr.Spec.KubeletConfigOverrides = spec.KubeletConfigOverrides
+	curr.Spec.Systemd = spec.Systemd
+	curr.Spec.TimeSync = spec.TimeSync
+	curr.Spec.Runtimes = spec.Runtimes
+	curr.Spec.DisableGenerated = spec.DisableGenerated
+	curr.Spec.NetworkStack = spec.NetworkStack
+	curr.Spec.MachineConfigPoolMaintenanceWindow = spec.MachineConfigPoolMaintenanceWindow
+
+	if curr.Spec.NUMA != nil && spec.NUMA != nil {
+		curr.Spec.NUMA.TopologyPolicyOptions = spec.NUMA.TopologyPolicyOptions
+		curr.Spec.NUMA.TopologyManagerScope = spec.NUMA.TopologyManagerScope
+		curr.Spec.NUMA.AutomaticBalancing = spec.NUMA.AutomaticBalancing
+	}
+
+	if curr.Spec.Net != nil && spec.Net != nil {
+		curr.Spec.Net.PacketSteering = spec.Net.PacketSteering
+	}
+
+	// v1 defaults an unset globallyDisableIrqLoadBalancing to true, v2 to false
+	if src.Spec.GloballyDisableIrqLoadBalancing == nil {
+		curr.Spec.GloballyDisableIrqLoadBalancing = spec.GloballyDisableIrqLoadBalancing
+	}
+
+	if curr.Spec.WorkloadHints != nil && spec.WorkloadHints != nil {
+		curr.Spec.WorkloadHints.MixedCpus = spec.WorkloadHints.MixedCpus
+	}
+
+	curr.Status.Rollout = restored.Status.Rollout
+	curr.Status.Rollback = restored.Status.Rollback
+	curr.Status.SkippedFeatures = restored.Status.SkippedFeatures
+	curr.Status.SkippedArtifacts = restored.Status.SkippedArtifacts
+	curr.Status.ExportedArtifacts = restored.Status.ExportedArtifacts
+	curr.Status.KubeletConfigSync = restored.Status.KubeletConfigSync
+}
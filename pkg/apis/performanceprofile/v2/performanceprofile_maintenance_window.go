@@ -0,0 +1,218 @@
+package v2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// cronSearchDays bounds the search for the next window start, so that a schedule matching only on
+// February 29 is found from any day.
+const cronSearchDays = 4*366 + 1
+
+// cronFields are the fields of a cron expression and the ranges of their values.
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// cronSchedule holds the values of the cron expression fields as bit masks.
+type cronSchedule struct {
+	minutes, hours, days, months, weekdays uint64
+	// when the day of month or the day of week starts with "*", a day matches both fields, otherwise either of them
+	anyDay bool
+}
+
+// IsOpen returns true if time 'now' falls into a window.
+func (w *MaintenanceWindow) IsOpen(now time.Time) (bool, error) {
+	schedule, loc, err := w.parse()
+	if err != nil {
+		return false, err
+	}
+
+	// the last window started after now-duration, if it started at all
+	start := schedule.next(now.In(loc).Add(-w.Duration.Duration))
+	return !start.IsZero() && !start.After(now), nil
+}
+
+// NextStart returns the first time after time 'now' a window starts.
+func (w *MaintenanceWindow) NextStart(now time.Time) (time.Time, error) {
+	schedule, loc, err := w.parse()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	start := schedule.next(now.In(loc))
+	if start.IsZero() {
+		return time.Time{}, fmt.Errorf("the schedule %q does not start any window", w.Schedule)
+	}
+	return start, nil
+}
+
+// String returns the window in the "0 22 * * 6 UTC for 4h0m0s" format.
+func (w *MaintenanceWindow) String() string {
+	if w == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s %s for %v", w.Schedule, w.timeZone(), w.Duration.Duration)
+}
+
+// Validate returns an error for every invalid field of the window.
+func (w *MaintenanceWindow) Validate(fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if w == nil {
+		return allErrs
+	}
+
+	schedule, err := parseCron(w.Schedule)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("schedule"), w.Schedule, err.Error()))
+	} else if schedule.next(time.Now().UTC()).IsZero() {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("schedule"), w.Schedule, "the schedule does not match any day"))
+	}
+	if w.Duration.Duration < time.Minute {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("duration"), w.Duration.Duration.String(), "the window should last at least a minute"))
+	}
+	if _, err := time.LoadLocation(w.timeZone()); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeZone"), w.TimeZone, err.Error()))
+	}
+	return allErrs
+}
+
+// parse returns the schedule and the time zone of the window.
+func (w *MaintenanceWindow) parse() (*cronSchedule, *time.Location, error) {
+	schedule, err := parseCron(w.Schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid maintenance window schedule %q: %v", w.Schedule, err)
+	}
+	loc, err := time.LoadLocation(w.timeZone())
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid maintenance window time zone %q: %v", w.TimeZone, err)
+	}
+	return schedule, loc, nil
+}
+
+func (w *MaintenanceWindow) timeZone() string {
+	if w.TimeZone == "" {
+		return "UTC"
+	}
+	return w.TimeZone
+}
+
+// parseCron parses the "minute hour day-of-month month day-of-week" cron expression 'expr'.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("expected the \"minute hour day-of-month month day-of-week\" format")
+	}
+
+	masks := make([]uint64, len(fields))
+	for i, f := range fields {
+		mask, err := parseCronField(f, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", cronFields[i].name, f, err)
+		}
+		masks[i] = mask
+	}
+	// both 0 and 7 stand for Sunday
+	if masks[4]&(1<<7) != 0 {
+		masks[4] |= 1
+	}
+
+	return &cronSchedule{
+		minutes:  masks[0],
+		hours:    masks[1],
+		days:     masks[2],
+		months:   masks[3],
+		weekdays: masks[4],
+		anyDay:   strings.HasPrefix(fields[2], "*") || strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// parseCronField returns the bit mask of the values of the comma separated list of values, ranges and steps 'value'.
+func parseCronField(value string, min, max int) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(value, ",") {
+		values, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s < 1 {
+				return 0, fmt.Errorf("the step %q should be a positive number", part[i+1:])
+			}
+			values, step = part[:i], s
+		}
+
+		first, last := min, max
+		if values != "*" {
+			bounds := strings.SplitN(values, "-", 2)
+			var err error
+			if first, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("%q is not a number", bounds[0])
+			}
+			switch {
+			case len(bounds) == 2:
+				if last, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("%q is not a number", bounds[1])
+				}
+			case step == 1:
+				last = first
+			}
+		}
+		if first < min || last > max || first > last {
+			return 0, fmt.Errorf("%q is out of the %d-%d range", values, min, max)
+		}
+
+		for v := first; v <= last; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+// next returns the first minute after time 't' the schedule matches, or the zero time if none does.
+func (c *cronSchedule) next(t time.Time) time.Time {
+	from := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+	for i := 0; i < cronSearchDays; i++ {
+		day := time.Date(from.Year(), from.Month(), from.Day()+i, 0, 0, 0, 0, from.Location())
+		if !c.matchesDay(day) {
+			continue
+		}
+		for hour := 0; hour < 24; hour++ {
+			if c.hours&(1<<uint(hour)) == 0 {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if c.minutes&(1<<uint(minute)) == 0 {
+					continue
+				}
+				start := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+				if start.After(from) {
+					return start
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay returns true if the schedule matches a minute of the day 'day'.
+func (c *cronSchedule) matchesDay(day time.Time) bool {
+	if c.months&(1<<uint(day.Month())) == 0 {
+		return false
+	}
+	matchesDay := c.days&(1<<uint(day.Day())) != 0
+	matchesWeekday := c.weekdays&(1<<uint(day.Weekday())) != 0
+	if c.anyDay {
+		return matchesDay && matchesWeekday
+	}
+	return matchesDay || matchesWeekday
+}
@@ -0,0 +1,55 @@
+package v2
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("MaintenanceWindow", func() {
+	// Saturday
+	saturday := time.Date(2024, time.January, 6, 0, 0, 0, 0, time.UTC)
+
+	window := func(schedule string, duration time.Duration) *MaintenanceWindow {
+		return &MaintenanceWindow{Schedule: schedule, Duration: metav1.Duration{Duration: duration}}
+	}
+
+	DescribeTable("should report whether the window is open",
+		func(w *MaintenanceWindow, now time.Time, open bool) {
+			isOpen, err := w.IsOpen(now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isOpen).To(Equal(open))
+		},
+		Entry("before the window", window("0 22 * * 6", 4*time.Hour), saturday.Add(21*time.Hour), false),
+		Entry("at the window start", window("0 22 * * 6", 4*time.Hour), saturday.Add(22*time.Hour), true),
+		Entry("after midnight within the window", window("0 22 * * 6", 4*time.Hour), saturday.Add(25*time.Hour), true),
+		Entry("at the window end", window("0 22 * * 6", 4*time.Hour), saturday.Add(26*time.Hour), false),
+		Entry("on another day of week", window("0 22 * * 6", 4*time.Hour), saturday.Add(-2*time.Hour), false),
+		Entry("with both days restricted on the day of month", window("0 1 10 * 1", time.Hour), time.Date(2024, time.January, 10, 1, 30, 0, 0, time.UTC), true),
+		Entry("with both days restricted on the day of week", window("0 1 10 * 1", time.Hour), time.Date(2024, time.January, 8, 1, 30, 0, 0, time.UTC), true),
+		Entry("within a stepped window", window("*/15 3-5 * * *", 5*time.Minute), saturday.Add(4*time.Hour+32*time.Minute), true),
+		Entry("between the stepped windows", window("*/15 3-5 * * *", 5*time.Minute), saturday.Add(4*time.Hour+37*time.Minute), false),
+	)
+
+	It("should return the next window start", func() {
+		w := window("0 22 * * 0,6", time.Hour)
+		start, err := w.NextStart(saturday.Add(23 * time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(start).To(BeTemporally("==", saturday.Add(46*time.Hour)))
+
+		start, err = window("0 0 29 2 *", time.Hour).NextStart(saturday)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(start).To(BeTemporally("==", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("should start the windows in the time zone of the window", func() {
+		w := window("0 22 * * 6", 4*time.Hour)
+		w.TimeZone = "Europe/Prague"
+		start, err := w.NextStart(saturday)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(start).To(BeTemporally("==", saturday.Add(21*time.Hour)))
+	})
+})
@@ -18,7 +18,10 @@ package v2
 
 import (
 	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // PerformanceProfilePauseAnnotation allows an admin to suspend the operator's
@@ -38,6 +41,143 @@ const PerformanceProfileEnableRpsAnnotation = "performance.openshift.io/enable-r
 // automatic downgrade of Cgroups version to V1 for development purposes.
 const PerformanceProfileIgnoreCgroupsVersion = "performance.openshift.io/ignore-cgroups-version"
 
+// PerformanceProfileDisableOperandPinningAnnotation opts the profile out of pinning the
+// tuned operand pods to the reserved CPUs on clusters without workload partitioning.
+const PerformanceProfileDisableOperandPinningAnnotation = "performance.openshift.io/disable-operand-pinning"
+
+// PerformanceProfileCPUPartitioningModeAnnotation declares the CPU partitioning mode
+// (AllNodes or None) of the clusters the profile is written for, e.g. by the tooling
+// generating the profiles of workload partitioned single node clusters. The profile
+// is rejected on clusters whose infrastructure CPU partitioning mode differs.
+const PerformanceProfileCPUPartitioningModeAnnotation = "performance.openshift.io/cpu-partitioning-mode"
+
+// PerformanceProfileMachineConfigNameSuffixAnnotation allows an admin to override the part
+// of the generated MachineConfig name that follows the "50-" ordering prefix,
+// so the object name can follow external naming conventions (e.g. ZTP policies).
+const PerformanceProfileMachineConfigNameSuffixAnnotation = "performance.openshift.io/machine-config-name-suffix"
+
+// PerformanceProfileMachineConfigLabelsAnnotation holds a JSON encoded map of additional
+// labels that will be set on the generated MachineConfig. Labels coming from
+// spec.machineConfigLabel take precedence over the ones provided by the annotation.
+const PerformanceProfileMachineConfigLabelsAnnotation = "performance.openshift.io/machine-config-labels"
+
+// PerformanceProfileLatencyProbeAnnotation holds a JSON encoded LatencyProbe. When set, the operator
+// runs the latency test on every node of the profile once the node is tuned and records the
+// measured maximum latency in the status of the node tuned Profile.
+const PerformanceProfileLatencyProbeAnnotation = "performance.openshift.io/latency-probe"
+
+// LatencyProbeTool is a latency test tool, it can be cyclictest or oslat.
+type LatencyProbeTool string
+
+const (
+	// LatencyProbeToolCyclictest measures the latency with cyclictest
+	LatencyProbeToolCyclictest LatencyProbeTool = "cyclictest"
+	// LatencyProbeToolOslat measures the latency with oslat
+	LatencyProbeToolOslat LatencyProbeTool = "oslat"
+)
+
+// LatencyProbe defines the latency test run on the tuned nodes.
+type LatencyProbe struct {
+	// Image is the container image providing the latency test tool.
+	Image string `json:"image"`
+	// Tool is the latency test tool to run.
+	// Defaults to "cyclictest".
+	// +optional
+	Tool LatencyProbeTool `json:"tool,omitempty"`
+	// Duration is the duration of the test.
+	// Defaults to "60s".
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// CPUs is the number of isolated CPUs the test runs on.
+	// Defaults to 2.
+	// +optional
+	CPUs *int32 `json:"cpus,omitempty"`
+}
+
+// PerformanceProfileAutoRollbackAnnotation holds a JSON encoded AutoRollback. When set, the operator
+// restores the last profile components that rolled out successfully once the rollout of new components
+// degrades the profile machine config pool, and reports the profile with the RolledBack condition.
+const PerformanceProfileAutoRollbackAnnotation = "performance.openshift.io/auto-rollback"
+
+// AutoRollback defines when the operator considers the rollout of the profile components failed.
+type AutoRollback struct {
+	// DegradedTunedProfiles is the number of nodes with a degraded tuned profile that fails the rollout.
+	// Machine config pool nodes failing to apply their configuration always fail the rollout.
+	// Defaults to 1.
+	// +optional
+	DegradedTunedProfiles *int32 `json:"degradedTunedProfiles,omitempty"`
+}
+
+// PerformanceProfileExportArtifactsAnnotation enables, when set to "true", the export of the MachineConfig,
+// the KubeletConfig and the Tuned of the profile to a ConfigMap referenced from the profile status, so they
+// can be read without access to the machine config resources.
+const PerformanceProfileExportArtifactsAnnotation = "performance.openshift.io/export-artifacts"
+
+// PerformanceProfileInjectRuntimeClassLabel opts a namespace in the high-performance RuntimeClass of the profile
+// named by the label value. The pods created in the namespace without a runtime class run with the profile
+// RuntimeClass.
+const PerformanceProfileInjectRuntimeClassLabel = "performance.openshift.io/inject-runtimeclass"
+
+// PerformanceProfileRuntimeClassAnnotation holds the RuntimeClass the operator resolved for a namespace
+// labeled with PerformanceProfileInjectRuntimeClassLabel.
+const PerformanceProfileRuntimeClassAnnotation = "performance.openshift.io/runtimeclass"
+
+// PerformanceProfileSummaryAnnotation holds a JSON encoded ProfileSummary the operator keeps in sync
+// with the profile, so the console and ACM show the profile tuning without aggregating its spec and status.
+const PerformanceProfileSummaryAnnotation = "performance.openshift.io/summary"
+
+// ProfileRolloutState is the rollout state of the profile components reported by the profile summary.
+type ProfileRolloutState string
+
+const (
+	// ProfileRolloutStateProgressing reports the components did not roll out to all the nodes yet.
+	ProfileRolloutStateProgressing ProfileRolloutState = "Progressing"
+	// ProfileRolloutStateAvailable reports the components rolled out to all the nodes.
+	ProfileRolloutStateAvailable ProfileRolloutState = "Available"
+	// ProfileRolloutStateDegraded reports the profile is degraded.
+	ProfileRolloutStateDegraded ProfileRolloutState = "Degraded"
+)
+
+// ProfileSummary is the compact summary of the profile tuning.
+type ProfileSummary struct {
+	// IsolatedCPUs is the number of the isolated CPUs.
+	IsolatedCPUs int `json:"isolatedCPUs"`
+	// ReservedCPUs is the number of the reserved CPUs.
+	ReservedCPUs int `json:"reservedCPUs"`
+	// HugepagesTotal is the total size of the huge pages of a node, e.g. "4Gi".
+	HugepagesTotal string `json:"hugepagesTotal"`
+	// RealTimeKernel is true when the profile installs the real time kernel.
+	RealTimeKernel bool `json:"realTimeKernel"`
+	// RolloutState is the rollout state of the profile components.
+	RolloutState ProfileRolloutState `json:"rolloutState"`
+	// UpdatedNodes is the number of the nodes running the profile components.
+	UpdatedNodes int32 `json:"updatedNodes"`
+	// TotalNodes is the number of the nodes of the profile machine config pool.
+	TotalNodes int32 `json:"totalNodes"`
+}
+
+// PerformanceProfileCanaryRolloutAnnotation holds a JSON encoded CanaryRollout. When set, the operator pauses
+// the profile machine config pool before it changes the profile MachineConfig or KubeletConfig, rolls the new
+// pool configuration out to a single canary node and unpauses the pool once the canary node is tuned. The pool
+// stays paused when the canary node fails, and the profile reports the failure with the Degraded condition.
+const PerformanceProfileCanaryRolloutAnnotation = "performance.openshift.io/canary-rollout"
+
+// CanaryRollout defines the canary node of the profile rollouts and when the canary node passes.
+type CanaryRollout struct {
+	// Node is the name of the canary node, it must be a node of the profile.
+	// Defaults to the first node of the profile by name.
+	// +optional
+	Node string `json:"node,omitempty"`
+	// MaxLatency is the maximum latency, in microseconds, the latency probe of the profile may measure
+	// on the canary node. It requires the profile to request the latency probe.
+	// +optional
+	MaxLatency *int64 `json:"maxLatency,omitempty"`
+	// Timeout is the time the canary node has to get tuned.
+	// Defaults to "30m".
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
 // PerformanceProfileSpec defines the desired state of PerformanceProfile.
 type PerformanceProfileSpec struct {
 	// CPU defines a set of CPU related parameters.
@@ -51,6 +191,12 @@ type PerformanceProfileSpec struct {
 	// It is important to notice that setting hugepages default size to 1G will remove all 2M related
 	// folders from the node and it will be impossible to configure 2M hugepages under the node.
 	HugePages *HugePages `json:"hugepages,omitempty"`
+	// Memory defines a set of memory management related parameters.
+	// +optional
+	Memory *Memory `json:"memory,omitempty"`
+	// Scheduler defines a set of kernel scheduler related parameters.
+	// +optional
+	Scheduler *Scheduler `json:"scheduler,omitempty"`
 	// MachineConfigLabel defines the label to add to the MachineConfigs the operator creates. It has to be
 	// used in the MachineConfigSelector of the MachineConfigPool which targets this performance profile.
 	// Defaults to "machineconfiguration.openshift.io/role=<same role as in NodeSelector label key>"
@@ -61,6 +207,19 @@ type PerformanceProfileSpec struct {
 	// Defaults to "machineconfiguration.openshift.io/role=<same role as in NodeSelector label key>"
 	// +optional
 	MachineConfigPoolSelector map[string]string `json:"machineConfigPoolSelector,omitempty"`
+	// MachineConfigPoolMaxUnavailable defines the maxUnavailable of the MachineConfigPool targeted by this
+	// performance profile while the pool rolls out the MachineConfig and the KubeletConfig changes of the profile.
+	// The maxUnavailable the pool defined before is restored once all its machines run the changes.
+	// It is an absolute number or a percentage of the pool machines.
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	MachineConfigPoolMaxUnavailable *intstr.IntOrString `json:"machineConfigPoolMaxUnavailable,omitempty"`
+	// MachineConfigPoolMaintenanceWindow defines the recurring windows the MachineConfigPool targeted by this
+	// performance profile may start rolling out the MachineConfig and the KubeletConfig changes of the profile in.
+	// Outside of the windows, the operator postpones the changes to the start of the next window, so the pools
+	// of the profiles with different windows reboot their nodes at different times.
+	// +optional
+	MachineConfigPoolMaintenanceWindow *MaintenanceWindow `json:"machineConfigPoolMaintenanceWindow,omitempty"`
 	// NodeSelector defines the Node label to use in the NodeSelectors of resources like Tuned created by the operator.
 	// It most likely should, but does not have to match the node label in the NodeSelector of the MachineConfigPool
 	// which targets this performance profile.
@@ -72,12 +231,51 @@ type PerformanceProfileSpec struct {
 	// Additional kernel arguments.
 	// +optional
 	AdditionalKernelArgs []string `json:"additionalKernelArgs,omitempty"`
+	// KernelModules defines the options of the kernel modules and the modules that should not be loaded automatically.
+	// The configuration is rendered under /etc/modprobe.d as part of the MachineConfig created for the profile.
+	// +optional
+	KernelModules []KernelModule `json:"kernelModules,omitempty"`
+	// Devices defines the PCI devices bound to a userspace I/O driver at boot, for example the devices
+	// used by DPDK applications. The bindings are rendered as part of the MachineConfig created for the profile,
+	// and the bindings removed from the profile are removed from the nodes.
+	// +optional
+	Devices *Devices `json:"devices,omitempty"`
+	// UdevRules defines the additional udev rules paired with the profile, for example the rules renaming the NICs
+	// or pinning their queues. The rules are rendered under /etc/udev/rules.d as part of the MachineConfig created
+	// for the profile, and the rules removed from the profile are removed from the nodes.
+	// +optional
+	UdevRules []UdevRule `json:"udevRules,omitempty"`
+	// KubeletConfigOverrides defines a v1beta1 KubeletConfiguration snippet strategically merged into the
+	// KubeletConfig created for the profile, after the kubeletconfig.experimental annotation snippet.
+	// The fields the operator computes out of the profile, like reservedSystemCPUs or topologyManagerPolicy,
+	// can not be overridden, use the relevant profile fields instead.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	KubeletConfigOverrides *runtime.RawExtension `json:"kubeletConfigOverrides,omitempty"`
+	// Systemd defines the properties of the systemd slices running the host processes.
+	// The properties are rendered as slice drop-ins as part of the MachineConfig created for the profile.
+	// +optional
+	Systemd *Systemd `json:"systemd,omitempty"`
+	// TimeSync defines the reserved CPUs the host time synchronization services run on, so the clock discipline
+	// does not compete with the other host processes. The affinity is rendered as service drop-ins as part of
+	// the MachineConfig created for the profile.
+	// +optional
+	TimeSync *TimeSync `json:"timeSync,omitempty"`
+	// Runtimes defines the additional CRI-O runtime handlers rendered alongside the high-performance runtime handler,
+	// for example a variant of the high-performance runtime handler running the crun OCI runtime.
+	// The pods use the handlers through RuntimeClasses referencing them.
+	// +optional
+	Runtimes []RuntimeHandler `json:"runtimes,omitempty"`
 	// NUMA defines options related to topology aware affinities
 	// +optional
 	NUMA *NUMA `json:"numa,omitempty"`
 	// Net defines a set of network related features
 	// +optional
 	Net *Net `json:"net,omitempty"`
+	// NetworkStack defines the IP stack of the nodes of the profile, the generated network sysctls are sized for.
+	// Without it, the generated network sysctls keep their IPv4 oriented defaults.
+	// +optional
+	NetworkStack *NetworkStack `json:"networkStack,omitempty"`
 	// GloballyDisableIrqLoadBalancing toggles whether IRQ load balancing will be disabled for the Isolated CPU set.
 	// When the option is set to "true" it disables IRQs load balancing for the Isolated CPU set.
 	// Setting the option to "false" allows the IRQs to be balanced across all CPUs, however the IRQs load balancing
@@ -89,8 +287,25 @@ type PerformanceProfileSpec struct {
 	// kernel arguments that should be applied on top of the node.
 	// +optional
 	WorkloadHints *WorkloadHints `json:"workloadHints,omitempty"`
+	// DisableGenerated lists the generated artifacts the operator does not create nor update,
+	// so they can be managed externally. The artifacts generated before are left in place.
+	// +optional
+	DisableGenerated []GeneratedArtifact `json:"disableGenerated,omitempty"`
 }
 
+// GeneratedArtifact names an artifact generated for the profile that can be managed externally instead.
+// +kubebuilder:validation:Enum=runtimeClass;irqbalanceConfig;rpsConfig
+type GeneratedArtifact string
+
+const (
+	// GeneratedArtifactRuntimeClass is the RuntimeClass of the high performance runtime handler.
+	GeneratedArtifactRuntimeClass GeneratedArtifact = "runtimeClass"
+	// GeneratedArtifactIRQBalanceConfig is the systemd unit clearing the irqbalance banned CPUs at boot.
+	GeneratedArtifactIRQBalanceConfig GeneratedArtifact = "irqbalanceConfig"
+	// GeneratedArtifactRPSConfig is the sysctl, the udev rule and the systemd unit setting the RPS mask of the network devices.
+	GeneratedArtifactRPSConfig GeneratedArtifact = "rpsConfig"
+)
+
 // CPUSet defines the set of CPUs(0-3,8-11).
 type CPUSet string
 
@@ -114,6 +329,16 @@ type CPU struct {
 	// Defaults to "true"
 	// +optional
 	BalanceIsolated *bool `json:"balanceIsolated,omitempty"`
+	// IsolationMethod defines how the isolated CPUs are removed from the scheduling domains of the housekeeping CPUs.
+	// "kernelArg" sets the static "isolcpus" kernel argument.
+	// "cgroupPartition" configures, at runtime, the cgroup v2 cpuset partition of the pods with the isolated CPUs
+	// instead, so the changes of the isolated CPUs are applied to the partition without a reboot, on the kernels
+	// supporting the exclusive CPUs of the cpuset partitions. The partition is an "isolated" partition, without load
+	// balancing, when balanceIsolated is "false", and a "root" partition otherwise. This method requires cgroup v2.
+	// Defaults to "kernelArg"
+	// +kubebuilder:validation:Enum=kernelArg;cgroupPartition
+	// +optional
+	IsolationMethod *CPUIsolationMethod `json:"isolationMethod,omitempty"`
 	// Offline defines a set of CPUs that will be unused and set offline
 	// +optional
 	Offlined *CPUSet `json:"offlined,omitempty"`
@@ -122,8 +347,80 @@ type CPU struct {
 	// alongside the isolated, exclusive resources that are being used already by those workloads.
 	// +optional
 	Shared *CPUSet `json:"shared,omitempty"`
+	// IRQServing defines a subset of the reserved CPUs that will serve the device interrupts.
+	// When set, the remaining reserved CPUs are banned from the IRQ load balancing, so housekeeping
+	// daemons running on them are not disturbed by interrupts.
+	// When not set, all the reserved CPUs are eligible for serving interrupts.
+	// +optional
+	IRQServing *CPUSet `json:"irqServing,omitempty"`
+	// NohzFull defines a subset of the isolated CPUs running in the adaptive-tick mode, with the "nohz_full"
+	// kernel argument, when the realtime workload hint is enabled.
+	// An empty set keeps the scheduling-clock ticks on all the CPUs, so the CPUs stay isolated without being tickless.
+	// When not set, all the isolated CPUs run in the adaptive-tick mode.
+	// +optional
+	NohzFull *CPUSet `json:"nohzFull,omitempty"`
+	// RCUNocbs defines a subset of the isolated CPUs whose RCU callbacks are offloaded to other CPUs,
+	// with the "rcu_nocbs" kernel argument.
+	// An empty set keeps the RCU callbacks on all the CPUs.
+	// When not set, the RCU callbacks of all the isolated CPUs are offloaded.
+	// +optional
+	RCUNocbs *CPUSet `json:"rcuNocbs,omitempty"`
+	// RCUKthreadPriority defines the SCHED_FIFO priority of the RCU kernel threads, with the "rcutree.kthread_prio"
+	// kernel argument. Zero runs the RCU kernel threads with the SCHED_OTHER policy.
+	// When not set, the realtime workload hint sets the priority to 11, the kernel default is kept otherwise.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	RCUKthreadPriority *int32 `json:"rcuKthreadPriority,omitempty"`
+	// PinUnboundWorkqueues pins the unbound kernel workqueues, including the writeback workqueue, to the reserved CPUs.
+	// When not set, the unbound workqueues run on all the CPUs that are not isolated, the shared CPUs included.
+	// Defaults to "false"
+	// +optional
+	PinUnboundWorkqueues *bool `json:"pinUnboundWorkqueues,omitempty"`
+	// SMTPolicy defines how simultaneous multithreading is handled on the node.
+	// "cluster-default" keeps the SMT configuration of the node untouched.
+	// "disable-all" disables SMT on all the CPUs by adding the "nosmt" kernel argument.
+	// "disable-isolated-only" sets offline, at boot, the sibling threads of the isolated cores,
+	// so that only a single thread per isolated core is kept online, while the reserved CPUs
+	// are left untouched.
+	// Defaults to "cluster-default"
+	// +kubebuilder:validation:Enum=cluster-default;disable-all;disable-isolated-only
+	// +optional
+	SMTPolicy *SMTPolicy `json:"smtPolicy,omitempty"`
+	// EfficiencyCores defines the CPUs of the efficiency cores of hybrid processors, e.g. the Intel E-cores,
+	// the other CPUs being performance cores. The performance profile creator sets it out of the node hardware.
+	// When set, the isolated CPUs must be all either efficiency or performance cores, unless allowMixedCoreTypes is "true".
+	// +optional
+	EfficiencyCores *CPUSet `json:"efficiencyCores,omitempty"`
+	// AllowMixedCoreTypes allows the isolated CPUs to mix efficiency and performance cores, so the latency of
+	// the isolated workloads depends on the cores they are pinned to.
+	// Defaults to "false"
+	// +optional
+	AllowMixedCoreTypes *bool `json:"allowMixedCoreTypes,omitempty"`
 }
 
+// SMTPolicy defines how simultaneous multithreading is handled on the node.
+type SMTPolicy string
+
+const (
+	// SMTPolicyClusterDefault keeps the node SMT configuration untouched.
+	SMTPolicyClusterDefault SMTPolicy = "cluster-default"
+	// SMTPolicyDisableAll disables SMT on all the CPUs of the node.
+	SMTPolicyDisableAll SMTPolicy = "disable-all"
+	// SMTPolicyDisableIsolatedOnly disables SMT on the isolated cores only.
+	SMTPolicyDisableIsolatedOnly SMTPolicy = "disable-isolated-only"
+)
+
+// CPUIsolationMethod defines how the isolated CPUs are removed from the scheduling domains.
+type CPUIsolationMethod string
+
+const (
+	// CPUIsolationMethodKernelArg isolates the CPUs with the "isolcpus" kernel argument.
+	CPUIsolationMethodKernelArg CPUIsolationMethod = "kernelArg"
+	// CPUIsolationMethodCgroupPartition isolates the CPUs with a cgroup v2 cpuset partition.
+	CPUIsolationMethodCgroupPartition CPUIsolationMethod = "cgroupPartition"
+)
+
 // CPUfrequency defines cpu frequencies for isolated and reserved cpus
 type CPUfrequency int
 
@@ -144,6 +441,11 @@ type HugePages struct {
 	DefaultHugePagesSize *HugePageSize `json:"defaultHugepagesSize,omitempty"`
 	// Pages defines huge pages that we want to allocate at boot time.
 	Pages []HugePage `json:"pages,omitempty"`
+	// NodeMemorySize declares the memory size of the profile nodes, the memory percentage
+	// of the pages allocated at boot is resolved against it.
+	// Required when a page allocated at boot defines its memory percentage.
+	// +optional
+	NodeMemorySize *resource.Quantity `json:"nodeMemorySize,omitempty"`
 }
 
 // HugePage defines the number of allocated huge pages of the specific size.
@@ -152,18 +454,83 @@ type HugePage struct {
 	Size HugePageSize `json:"size,omitempty"`
 	// Count defines amount of huge pages, maps to the 'hugepages' kernel boot parameter.
 	Count int32 `json:"count,omitempty"`
+	// MemoryPercent defines amount of huge pages as a percentage of the total node memory, instead of the count.
+	// The operand resolves the percentage of the pages allocated at runtime against the memory of every node,
+	// the percentage of the pages allocated at boot is resolved against the declared nodeMemorySize.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MemoryPercent *int32 `json:"memoryPercent,omitempty"`
 	// Node defines the NUMA node where hugepages will be allocated,
 	// if not specified, pages will be allocated equally between NUMA nodes
 	// +optional
 	Node *int32 `json:"node,omitempty"`
+	// Allocation defines when the huge pages are allocated.
+	// "boot" allocates the pages while the node boots, via the kernel boot parameters or a systemd unit.
+	// "runtime" allocates the pages once the node is running, without rebooting it when the count changes.
+	// Only 2M huge pages can be allocated at runtime, and the allocation may fall short on fragmented memory.
+	// Defaults to "boot".
+	// +kubebuilder:validation:Enum=boot;runtime
+	// +optional
+	Allocation *HugePageAllocation `json:"allocation,omitempty"`
 }
 
+// HugePageAllocation defines when huge pages are allocated.
+type HugePageAllocation string
+
+const (
+	// HugePageAllocationBoot allocates the huge pages while the node boots.
+	HugePageAllocationBoot HugePageAllocation = "boot"
+	// HugePageAllocationRuntime allocates the huge pages on the running node.
+	HugePageAllocationRuntime HugePageAllocation = "runtime"
+)
+
 // NUMA defines parameters related to topology awareness and affinity.
 type NUMA struct {
 	// Name of the policy applied when TopologyManager is enabled
 	// Operator defaults to "best-effort"
 	// +optional
 	TopologyPolicy *string `json:"topologyPolicy,omitempty"`
+	// TopologyPolicyOptions defines the options of the topology manager policy, for example
+	// prefer-closest-numa-nodes or max-allowable-numa-nodes.
+	// Every option requires a kubelet version supporting it by default on the nodes of the profile.
+	// +optional
+	TopologyPolicyOptions map[string]string `json:"topologyPolicyOptions,omitempty"`
+	// TopologyManagerScope defines the granularity of the topology alignment, "container" or "pod".
+	// Defaults to the kubelet default, "container".
+	// +kubebuilder:validation:Enum=container;pod
+	// +optional
+	TopologyManagerScope *string `json:"topologyManagerScope,omitempty"`
+	// AutomaticBalancing defines if the kernel automatic NUMA balancing is enabled.
+	// The balancing migrates tasks and memory pages between NUMA nodes, it is disabled by default
+	// and can not be enabled together with the real time kernel.
+	// +optional
+	AutomaticBalancing *bool `json:"automaticBalancing,omitempty"`
+}
+
+// Memory defines a set of memory management related parameters.
+type Memory struct {
+	// KernelSamePageMerging defines if the kernel merges the identical memory pages of the processes.
+	// The node default is kept when not set.
+	// +optional
+	KernelSamePageMerging *bool `json:"kernelSamePageMerging,omitempty"`
+}
+
+// Scheduler defines a set of kernel scheduler related parameters.
+type Scheduler struct {
+	// RTRuntimeUs is the time in microseconds out of every second the realtime tasks may run before the kernel
+	// throttles them, set via the kernel.sched_rt_runtime_us sysctl. The value -1 disables the throttling,
+	// for example for the poll mode workloads running realtime tasks on their isolated CPUs.
+	// Defaults to -1 with the realtime workload hint, the node default is kept otherwise.
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:validation:Maximum=1000000
+	// +optional
+	RTRuntimeUs *int32 `json:"rtRuntimeUs,omitempty"`
+	// TimerMigration defines if the kernel migrates the timers off the idle CPUs, set via the
+	// kernel.timer_migration sysctl. The migration evacuates the timers of the CPUs that start running
+	// a guaranteed workload. Defaults to true.
+	// +optional
+	TimerMigration *bool `json:"timerMigration,omitempty"`
 }
 
 // Net defines a set of network related features
@@ -174,6 +541,25 @@ type Net struct {
 	// set with a netqueue count equal to CPU.Reserved .
 	// If no devices are specified then the default is all devices.
 	Devices []Device `json:"devices,omitempty"`
+	// PacketSteering limits the receive (RPS) and the transmit (XPS) packet steering
+	// of the matching network devices queues to a set of CPUs.
+	// +optional
+	PacketSteering []PacketSteering `json:"packetSteering,omitempty"`
+}
+
+// PacketSteering defines the CPUs allowed to process the packets of the matching network devices queues.
+type PacketSteering struct {
+	// Device matches the network devices to configure.
+	Device Device `json:"device"`
+	// CPUs defines the CPUs steering the packets of the device queues. Defaults to CPU.Reserved.
+	// +optional
+	CPUs *CPUSet `json:"cpus,omitempty"`
+	// RPS defines if the receive packet steering mask of the device receive queues should be set. Defaults to "true".
+	// +optional
+	RPS *bool `json:"rps,omitempty"`
+	// XPS defines if the transmit packet steering mask of the device transmit queues should be set. Defaults to "true".
+	// +optional
+	XPS *bool `json:"xps,omitempty"`
 }
 
 // Device defines a way to represent a network device in several options:
@@ -190,12 +576,194 @@ type Device struct {
 	DeviceID *string `json:"deviceID,omitempty"`
 }
 
+// NetworkStackMode defines the IP stack of the nodes.
+// +kubebuilder:validation:Enum=ipv4-only;dual-stack;ipv6-primary
+type NetworkStackMode string
+
+const (
+	// NetworkStackIPv4Only sizes the network sysctls for nodes running IPv4 only.
+	NetworkStackIPv4Only NetworkStackMode = "ipv4-only"
+	// NetworkStackDualStack sizes the network sysctls for nodes running both IPv4 and IPv6.
+	NetworkStackDualStack NetworkStackMode = "dual-stack"
+	// NetworkStackIPv6Primary sizes the network sysctls for nodes running mostly IPv6.
+	NetworkStackIPv6Primary NetworkStackMode = "ipv6-primary"
+)
+
+// NetworkStack defines the network sysctls generated for the IP stack of the nodes.
+type NetworkStack struct {
+	// Mode is the IP stack of the nodes, it selects the defaults of the generated network sysctls.
+	Mode NetworkStackMode `json:"mode"`
+	// Sysctls overrides the generated network sysctls, or adds network sysctls, on the nodes of the
+	// profile machine config pool. Only the net.* sysctls matching the IP stack are allowed.
+	// +optional
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+}
+
 // RealTimeKernel defines the set of parameters relevant for the real time kernel.
 type RealTimeKernel struct {
 	// Enabled defines if the real time kernel packages should be installed. Defaults to "false"
 	Enabled *bool `json:"enabled,omitempty"`
 }
 
+// KernelModule defines the modprobe configuration of a kernel module.
+type KernelModule struct {
+	// Name defines the name of the kernel module, for example vfio-pci.
+	Name string `json:"name"`
+	// Options defines the parameters passed to the module when it is loaded, for example ids=8086:154c.
+	// +optional
+	Options []string `json:"options,omitempty"`
+	// Blacklist prevents the module from being loaded automatically.
+	// Defaults to "false"
+	// +optional
+	Blacklist *bool `json:"blacklist,omitempty"`
+}
+
+// DeviceDriver defines the driver a PCI device is bound to.
+// +kubebuilder:validation:Enum=vfio-pci;igb_uio
+type DeviceDriver string
+
+const (
+	// DeviceDriverVFIOPCI binds the device to the vfio-pci driver.
+	DeviceDriverVFIOPCI DeviceDriver = "vfio-pci"
+	// DeviceDriverIgbUIO binds the device to the igb_uio driver, the module is not part of the node
+	// operating system and should be provided separately.
+	DeviceDriverIgbUIO DeviceDriver = "igb_uio"
+)
+
+// Devices defines a set of PCI devices related parameters.
+type Devices struct {
+	// DriverBindings defines the drivers the PCI devices are bound to at boot, overriding the drivers
+	// the kernel binds the devices to.
+	// +optional
+	DriverBindings []DeviceDriverBinding `json:"driverBindings,omitempty"`
+}
+
+// DeviceDriverBinding defines the driver a PCI device is bound to.
+type DeviceDriverBinding struct {
+	// PCIAddress defines the PCI address of the device in the domain:bus:device.function format,
+	// for example 0000:3b:00.0.
+	PCIAddress string `json:"pciAddress"`
+	// Driver defines the driver the device is bound to.
+	Driver DeviceDriver `json:"driver"`
+}
+
+// UdevRule defines a udev rules file.
+type UdevRule struct {
+	// Name defines the name of the rules file without the .rules suffix, for example 70-nic-names.
+	// The rules file is rendered as /etc/udev/rules.d/<name>.rules.
+	Name string `json:"name"`
+	// Rules defines the content of the rules file, a udev rule per line. The empty lines and the lines
+	// starting with '#' are ignored, a line ending with '\\' is continued on the next line.
+	Rules string `json:"rules"`
+}
+
+// Systemd defines a set of systemd related parameters.
+type Systemd struct {
+	// Slices defines the resource control properties of the systemd slices, for example system.slice or ovs.slice.
+	// The kubepods slices are managed by the kubelet and can not be configured.
+	// +optional
+	Slices []SystemdSlice `json:"slices,omitempty"`
+	// Units defines the additional ordering of the tuning units generated for the profile against other units,
+	// for example to allocate the huge pages before ovs-vswitchd.service, or to set the CPUs offline after a
+	// vendor driver unit. The ordering is added to the ordering the operator generates.
+	// +optional
+	Units []SystemdUnitOrdering `json:"units,omitempty"`
+}
+
+// TuningUnit is a systemd unit generated for the profile.
+type TuningUnit string
+
+const (
+	// TuningUnitHugepagesAllocation is the unit allocating the huge pages of a NUMA node at boot.
+	TuningUnitHugepagesAllocation TuningUnit = "hugepages-allocation"
+	// TuningUnitSetCPUsOffline is the unit setting the offlined CPUs offline.
+	TuningUnitSetCPUsOffline TuningUnit = "set-cpus-offline"
+	// TuningUnitSetSMTSiblingsOffline is the unit setting the SMT siblings of the isolated CPUs offline.
+	TuningUnitSetSMTSiblingsOffline TuningUnit = "set-smt-siblings-offline"
+	// TuningUnitClearIRQBalanceBannedCPUs is the unit clearing the banned CPUs of irqbalance.
+	TuningUnitClearIRQBalanceBannedCPUs TuningUnit = "clear-irqbalance-banned-cpus"
+	// TuningUnitCPUSetConfigure is the unit moving the services to the reserved cpuset.
+	TuningUnitCPUSetConfigure TuningUnit = "cpuset-configure"
+)
+
+// SystemdUnitOrdering defines the additional ordering of a tuning unit generated for the profile.
+type SystemdUnitOrdering struct {
+	// Unit defines the tuning unit. The ordering of the hugepages-allocation unit applies to the units of all the NUMA nodes.
+	// +kubebuilder:validation:Enum=hugepages-allocation;set-cpus-offline;set-smt-siblings-offline;clear-irqbalance-banned-cpus;cpuset-configure
+	Unit TuningUnit `json:"unit"`
+	// Before defines the units the tuning unit starts before, maps to the Before= directive of the tuning unit.
+	// +optional
+	Before []string `json:"before,omitempty"`
+	// After defines the units the tuning unit starts after, maps to the After= directive of the tuning unit.
+	// The tuning units start before kubelet.service, so it can not be listed.
+	// +optional
+	After []string `json:"after,omitempty"`
+}
+
+// PerformanceProfileTimeSyncExternalServicesAnnotation lists, comma separated, the time synchronization services
+// whose units are managed by another operator, for example "ptp4l,phc2sys" by the PTP operator. The operator does
+// not render the drop-ins of the listed services, the other operator pins them to the CPUs of spec.timeSync.
+const PerformanceProfileTimeSyncExternalServicesAnnotation = "performance.openshift.io/time-sync-external-services"
+
+// TimeSync defines the CPU affinity of the host time synchronization services.
+type TimeSync struct {
+	// CPUs defines the CPUs the time synchronization services run on, it must be a subset of the reserved CPUs.
+	CPUs *CPUSet `json:"cpus"`
+	// Services defines the time synchronization services pinned to the CPUs.
+	// Defaults to ptp4l, phc2sys and chronyd.
+	// +optional
+	Services []TimeSyncService `json:"services,omitempty"`
+}
+
+// TimeSyncService is a host time synchronization service.
+// +kubebuilder:validation:Enum=ptp4l;phc2sys;chronyd
+type TimeSyncService string
+
+const (
+	// TimeSyncServicePTP4L is the PTP boundary or ordinary clock service, ptp4l.service.
+	TimeSyncServicePTP4L TimeSyncService = "ptp4l"
+	// TimeSyncServicePHC2SYS is the service synchronizing the system clock to the PTP hardware clock, phc2sys.service.
+	TimeSyncServicePHC2SYS TimeSyncService = "phc2sys"
+	// TimeSyncServiceChronyd is the NTP service, chronyd.service.
+	TimeSyncServiceChronyd TimeSyncService = "chronyd"
+)
+
+// SystemdSlice defines the resource control properties of a systemd slice.
+type SystemdSlice struct {
+	// Name defines the name of the slice, including the .slice suffix.
+	Name string `json:"name"`
+	// CPUAccounting toggles the CPU usage accounting of the slice.
+	// +optional
+	CPUAccounting *bool `json:"cpuAccounting,omitempty"`
+	// CPUWeight defines the relative share of CPU time of the slice compared to its siblings, between 1 and 10000.
+	// Defaults to the systemd default weight, 100.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10000
+	// +optional
+	CPUWeight *int32 `json:"cpuWeight,omitempty"`
+	// AllowedCPUs restricts the processes of the slice to the set of CPUs.
+	// +optional
+	AllowedCPUs *CPUSet `json:"allowedCPUs,omitempty"`
+}
+
+// RuntimeHandler defines an additional CRI-O runtime handler.
+type RuntimeHandler struct {
+	// Name defines the name of the runtime handler, referenced by the handler field of the RuntimeClass.
+	Name string `json:"name"`
+	// RuntimePath defines the absolute path of the OCI runtime binary, for example /usr/bin/crun.
+	// Defaults to the OCI runtime binary of the high-performance runtime handler.
+	// +optional
+	RuntimePath *string `json:"runtimePath,omitempty"`
+	// RuntimeRoot defines the absolute path of the directory holding the OCI runtime state, for example /run/crun.
+	// Defaults to the OCI runtime state directory of the high-performance runtime handler.
+	// +optional
+	RuntimeRoot *string `json:"runtimeRoot,omitempty"`
+	// AllowedAnnotations defines the pod annotations processed by the runtime handler, for example cpu-quota.crio.io.
+	// Defaults to the annotations allowed by the high-performance runtime handler.
+	// +optional
+	AllowedAnnotations []string `json:"allowedAnnotations,omitempty"`
+}
+
 // WorkloadHints defines the set of upper level flags for different type of workloads.
 type WorkloadHints struct {
 	// HighPowerConsumption defines if the node should be configured in high power consumption mode.
@@ -216,6 +784,19 @@ type WorkloadHints struct {
 	MixedCpus *bool `json:"mixedCpus,omitempty"`
 }
 
+// MaintenanceWindow defines recurring time windows, e.g. every Saturday night.
+type MaintenanceWindow struct {
+	// Schedule defines the starts of the windows as a cron expression in the "minute hour day-of-month month day-of-week"
+	// format, for example "0 22 * * 6" for every Saturday at 22:00. The fields accept numbers, ranges, lists and steps.
+	Schedule string `json:"schedule"`
+	// Duration defines the length of every window, for example "4h".
+	Duration metav1.Duration `json:"duration"`
+	// TimeZone defines the IANA time zone of the schedule, for example "Europe/Prague".
+	// Defaults to "UTC".
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
 // PerformanceProfileStatus defines the observed state of PerformanceProfile.
 type PerformanceProfileStatus struct {
 	// Conditions represents the latest available observations of current state.
@@ -226,12 +807,100 @@ type PerformanceProfileStatus struct {
 	Tuned *string `json:"tuned,omitempty"`
 	// RuntimeClass contains the name of the RuntimeClass resource created by the operator.
 	RuntimeClass *string `json:"runtimeClass,omitempty"`
+	// Rollout reports the progress of rolling out the generated MachineConfig
+	// on the nodes of the profile machine config pool.
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+	// Rollback records the last automatic rollback of the profile components.
+	// +optional
+	Rollback *RollbackStatus `json:"rollback,omitempty"`
+	// SkippedFeatures lists the profile features the generated components skip
+	// because the cluster feature gates enabling them are disabled.
+	// +optional
+	SkippedFeatures []string `json:"skippedFeatures,omitempty"`
+	// SkippedArtifacts lists the generated artifacts the operator skips
+	// because the profile disables them to let them be managed externally.
+	// +optional
+	SkippedArtifacts []string `json:"skippedArtifacts,omitempty"`
+	// ExportedArtifacts points to the ConfigMap the profile components are exported to,
+	// when the profile enables the export.
+	// +optional
+	ExportedArtifacts *string `json:"exportedArtifacts,omitempty"`
+	// KubeletConfigSync reports the nodes of the profile running the kubelet configuration
+	// of the generated KubeletConfig.
+	// +optional
+	KubeletConfigSync *KubeletConfigSyncStatus `json:"kubeletConfigSync,omitempty"`
+}
+
+// KubeletConfigSyncStatus defines the synchronization of the generated KubeletConfig with the kubelets of the profile nodes.
+type KubeletConfigSyncStatus struct {
+	// KubeletConfig is the name of the generated KubeletConfig.
+	KubeletConfig string `json:"kubeletConfig"`
+	// TotalNodes is the number of nodes targeted by the profile.
+	TotalNodes int32 `json:"totalNodes"`
+	// SyncedNodes is the number of nodes running the kubelet configuration of the generated KubeletConfig.
+	SyncedNodes int32 `json:"syncedNodes"`
+	// Nodes reports the kubelet configuration synchronization of every node targeted by the profile.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Nodes []NodeKubeletConfigSync `json:"nodes,omitempty"`
+}
+
+// NodeKubeletConfigSync defines the kubelet configuration synchronization of a node.
+type NodeKubeletConfigSync struct {
+	// Name is the name of the node.
+	Name string `json:"name"`
+	// Synced tells whether the kubelet of the node was restarted with the kubelet configuration
+	// of the generated KubeletConfig, as verified by the tuned daemon of the node.
+	Synced bool `json:"synced"`
+	// Reason tells why the node is not synced.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// RolloutStatus defines the rollout progress of the generated MachineConfig.
+type RolloutStatus struct {
+	// MachineConfig is the name of the generated MachineConfig being rolled out.
+	MachineConfig string `json:"machineConfig"`
+	// MachineConfigGeneration is the generation of the generated MachineConfig being rolled out.
+	MachineConfigGeneration int64 `json:"machineConfigGeneration"`
+	// MachineConfigPool is the name of the machine config pool targeted by the profile.
+	MachineConfigPool string `json:"machineConfigPool"`
+	// TotalNodes is the number of nodes in the machine config pool.
+	TotalNodes int32 `json:"totalNodes"`
+	// UpdatedNodes is the number of nodes running a configuration that includes the generated MachineConfig.
+	UpdatedNodes int32 `json:"updatedNodes"`
+	// PendingNodes is the number of nodes that did not get the generated MachineConfig yet.
+	PendingNodes int32 `json:"pendingNodes"`
+	// DegradedNodes is the number of nodes that failed to apply their configuration.
+	DegradedNodes int32 `json:"degradedNodes"`
+}
+
+// RollbackStatus defines an automatic rollback of the profile components.
+type RollbackStatus struct {
+	// ProfileGeneration is the generation of the profile whose components failed to roll out.
+	// The operator keeps the restored components until the profile changes.
+	ProfileGeneration int64 `json:"profileGeneration"`
+	// RestoredProfileGeneration is the generation of the profile the restored components were rendered for.
+	RestoredProfileGeneration int64 `json:"restoredProfileGeneration"`
+	// Reason is the reason of the rollout failure.
+	Reason string `json:"reason"`
+	// Message is the description of the rollout failure.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// RollbackTime is the time the components were restored.
+	RollbackTime metav1.Time `json:"rollbackTime"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=performanceprofiles,scope=Cluster
 // +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Pool",type="string",JSONPath=".status.rollout.machineConfigPool",priority=1
+// +kubebuilder:printcolumn:name="Updated",type="integer",JSONPath=".status.rollout.updatedNodes",priority=1
+// +kubebuilder:printcolumn:name="Pending",type="integer",JSONPath=".status.rollout.pendingNodes",priority=1
+// +kubebuilder:printcolumn:name="Degraded",type="integer",JSONPath=".status.rollout.degradedNodes",priority=1
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // PerformanceProfile is the Schema for the performanceprofiles API
@@ -0,0 +1,39 @@
+package v2
+
+import (
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetPerformanceProfile returns the performance profile of the bundle.
+func (r *TuningBundle) GetPerformanceProfile() *PerformanceProfile {
+	return &PerformanceProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   r.Name,
+			Labels: map[string]string{TuningBundleLabel: r.Name},
+		},
+		Spec: *r.Spec.PerformanceProfile.DeepCopy(),
+	}
+}
+
+// GetTuneds returns the supplemental Tuned CRs of the bundle in the namespace 'namespace'.
+func (r *TuningBundle) GetTuneds(namespace string) []*tunedv1.Tuned {
+	tuneds := make([]*tunedv1.Tuned, 0, len(r.Spec.Tuneds))
+	for _, t := range r.Spec.Tuneds {
+		labels := map[string]string{}
+		for k, v := range t.Labels {
+			labels[k] = v
+		}
+		labels[TuningBundleLabel] = r.Name
+		tuneds = append(tuneds, &tunedv1.Tuned{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      t.Name,
+				Namespace: namespace,
+				Labels:    labels,
+			},
+			Spec: *t.Spec.DeepCopy(),
+		})
+	}
+	return tuneds
+}
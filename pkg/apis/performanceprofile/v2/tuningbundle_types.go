@@ -0,0 +1,116 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TuningBundleLabel is set on the components of a tuning bundle to the name of the bundle.
+const TuningBundleLabel = "performance.openshift.io/tuning-bundle"
+
+// TuningBundleSpec defines the performance profile and the supplemental Tuned CRs applied together.
+type TuningBundleSpec struct {
+	// PerformanceProfile is the spec of the performance profile of the bundle.
+	// The performance profile is named after the bundle.
+	PerformanceProfile PerformanceProfileSpec `json:"performanceProfile"`
+	// Tuneds are the supplemental Tuned CRs of the bundle, created in the operator namespace.
+	// +optional
+	Tuneds []TuningBundleTuned `json:"tuneds,omitempty"`
+}
+
+// TuningBundleTuned defines a supplemental Tuned CR of a tuning bundle.
+type TuningBundleTuned struct {
+	// Name is the name of the Tuned CR.
+	Name string `json:"name"`
+	// Labels are the labels of the Tuned CR, e.g. its priority layer.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Spec is the spec of the Tuned CR.
+	Spec tunedv1.TunedSpec `json:"spec"`
+}
+
+// TuningBundleStatus defines the observed state of a tuning bundle.
+type TuningBundleStatus struct {
+	// Conditions represents the latest available observations of current state.
+	// +optional
+	Conditions []conditionsv1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the bundle whose components were last applied.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// AppliedGeneration is the last generation of the bundle rolled out successfully,
+	// the generation a failed rollout is rolled back to.
+	// +optional
+	AppliedGeneration int64 `json:"appliedGeneration,omitempty"`
+	// RolloutSourceConfig is the rendered configuration the machine config pool of the performance profile
+	// targeted before the components of the observed generation were applied. The rollout completes once
+	// the pool updated to another rendered configuration. Empty when the observed generation does not
+	// change the performance profile.
+	// +optional
+	RolloutSourceConfig string `json:"rolloutSourceConfig,omitempty"`
+	// Rollback records the last rollback of the bundle components.
+	// +optional
+	Rollback *TuningBundleRollback `json:"rollback,omitempty"`
+}
+
+// TuningBundleRollback defines a rollback of the bundle components.
+type TuningBundleRollback struct {
+	// Generation is the generation of the bundle whose components failed to roll out.
+	// The operator keeps the restored components until the bundle changes.
+	Generation int64 `json:"generation"`
+	// RestoredGeneration is the generation of the bundle the restored components belong to.
+	RestoredGeneration int64 `json:"restoredGeneration"`
+	// Reason is the reason of the rollout failure.
+	Reason string `json:"reason"`
+	// Message is the description of the rollout failure.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// RollbackTime is the time the components were restored.
+	RollbackTime metav1.Time `json:"rollbackTime"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=tuningbundles,scope=Cluster
+// +kubebuilder:printcolumn:name="Applied",type="integer",JSONPath=".status.appliedGeneration"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TuningBundle is the Schema for the tuningbundles API. A tuning bundle groups a performance profile and
+// its supplemental Tuned CRs, which are validated, rolled out and rolled back together.
+type TuningBundle struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TuningBundleSpec   `json:"spec,omitempty"`
+	Status TuningBundleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TuningBundleList contains a list of TuningBundle
+type TuningBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TuningBundle `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TuningBundle{}, &TuningBundleList{})
+}
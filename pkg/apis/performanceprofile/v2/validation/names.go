@@ -0,0 +1,73 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+*/
+
+package validation
+
+import "regexp"
+
+// IsValidRuntimeHandlerPath returns true when 'v' is an absolute path of a runtime handler binary or root
+func IsValidRuntimeHandlerPath(v string) bool {
+	re := regexp.MustCompile(`^/[a-zA-Z0-9/_.-]*$`)
+	return re.MatchString(v)
+}
+
+// IsValidRuntimeHandlerAnnotation returns true when 'v' is a valid annotation allowed for a runtime handler
+func IsValidRuntimeHandlerAnnotation(v string) bool {
+	re := regexp.MustCompile(`^[a-zA-Z0-9/_.-]+$`)
+	return re.MatchString(v)
+}
+
+// IsValidSliceName returns true when 'v' is a valid systemd slice name
+func IsValidSliceName(v string) bool {
+	re := regexp.MustCompile(`^[a-zA-Z0-9:_.-]+\.slice$`)
+	return re.MatchString(v)
+}
+
+// IsValidUnitName returns true when 'v' is a valid systemd unit name
+func IsValidUnitName(v string) bool {
+	re := regexp.MustCompile(`^[a-zA-Z0-9:_.@\\-]+\.(service|socket|device|mount|automount|swap|target|path|timer|slice|scope)$`)
+	return re.MatchString(v)
+}
+
+// IsValidKernelModuleName returns true when 'v' is a valid kernel module name
+func IsValidKernelModuleName(v string) bool {
+	re := regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+	return re.MatchString(v)
+}
+
+// IsValidNetSysctlName returns true when 'v' is the name of a sysctl of the net namespace
+func IsValidNetSysctlName(v string) bool {
+	re := regexp.MustCompile(`^net\.[a-zA-Z0-9_.-]+$`)
+	return re.MatchString(v)
+}
+
+// IsValidUdevRulesName returns true when 'v' is a valid udev rules file name, without the .rules suffix
+func IsValidUdevRulesName(v string) bool {
+	re := regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+	return re.MatchString(v)
+}
+
+// IsValidPCIAddress returns true when 'v' is a PCI address in the domain:bus:device.function format
+func IsValidPCIAddress(v string) bool {
+	re := regexp.MustCompile(`^[0-9a-fA-F]{4}:[0-9a-fA-F]{2}:[0-1][0-9a-fA-F]\.[0-7]$`)
+	return re.MatchString(v)
+}
+
+// IsValid16bitsHexID returns true when 'v' is a 16 bits hexadecimal ID, e.g. a PCI vendor or device ID
+func IsValid16bitsHexID(v string) bool {
+	re := regexp.MustCompile("^0x[0-9a-fA-F]+$")
+	return re.MatchString(v) && len(v) < 7
+}
@@ -0,0 +1,1159 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+*/
+
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	apiconfigv1 "github.com/openshift/api/config/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/version"
+	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+	"k8s.io/utils/cpuset"
+)
+
+const (
+	hugepagesSize2M = "2M"
+	hugepagesSize1G = "1G"
+
+	hugepagesMemoryWarningPercent = 80
+
+	// the kernel throttles the realtime tasks over a period of one second by default
+	schedRTPeriodUs = 1000000
+)
+
+const (
+	topologyPolicyOptionPreferClosestNUMANodes  = "prefer-closest-numa-nodes"
+	topologyPolicyOptionMaxAllowableNUMANodes   = "max-allowable-numa-nodes"
+	topologyPolicyMinMaxAllowableNUMANodesValue = 8
+)
+
+// topologyPolicyOptionsKubeletVersions maps the supported topology manager policy options
+// to the first kubelet version enabling them by default
+var topologyPolicyOptionsKubeletVersions = map[string]*version.Version{
+	topologyPolicyOptionPreferClosestNUMANodes: version.MustParseGeneric("1.28.0"),
+	topologyPolicyOptionMaxAllowableNUMANodes:  version.MustParseGeneric("1.31.0"),
+}
+
+// reservedUdevRulesNames are the names of the udev rules generated for the profile
+var reservedUdevRulesNames = map[string]bool{
+	"99-netdev-physical-rps":    true,
+	"99-netdev-packet-steering": true,
+}
+
+// netSysctlRPSDefaultMask is the sysctl of the generated RPS configuration
+const netSysctlRPSDefaultMask = "net.core.rps_default_mask"
+
+// netSysctlsDisablingIPv6 are the sysctls disabling IPv6 when set to 1
+var netSysctlsDisablingIPv6 = map[string]bool{
+	"net.ipv6.conf.all.disable_ipv6":     true,
+	"net.ipv6.conf.default.disable_ipv6": true,
+}
+
+// netSysctlsBusyPolling are the sysctls enabling the busy polling of the sockets
+var netSysctlsBusyPolling = map[string]bool{
+	"net.core.busy_poll": true,
+	"net.core.busy_read": true,
+}
+
+// ClusterState is the state of the cluster a performance profile or a tuning bundle is validated against.
+// The admission webhook reads it from the cluster, the clients out of the cluster provide what they know.
+type ClusterState struct {
+	// Profiles are the existing performance profiles
+	Profiles []performancev2.PerformanceProfile
+	// Nodes are the nodes matching the node selector of the validated profile
+	Nodes []corev1.Node
+	// CPUPartitioning is the CPU partitioning mode of the cluster infrastructure, nil when unknown
+	CPUPartitioning *apiconfigv1.CPUPartitioningMode
+	// SysctlPolicy is the sysctl policy of the default Tuned CR, nil when not set
+	SysctlPolicy *tunedv1.SysctlPolicy
+}
+
+// Validate runs all the admission webhook checks of the profile 'r' against the cluster state 'state'. The
+// webhook calls it with the state it reads from the cluster, so a profile valid out of the cluster for the
+// same state is admitted. The warnings are returned only when no error is found.
+func Validate(r *performancev2.PerformanceProfile, state ClusterState) (admission.Warnings, field.ErrorList) {
+	warnings, allErrs := ValidateAgainst(r, &performancev2.PerformanceProfileList{Items: state.Profiles}, state.Nodes)
+
+	// the CPU partitioning check runs once the other checks pass, the CPU sets errors are reported by the basic checks already
+	if len(allErrs) == 0 && state.CPUPartitioning != nil {
+		allErrs = ValidateCPUPartitioning(r, *state.CPUPartitioning)
+	}
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	return nil, allErrs
+}
+
+// ValidateAgainst runs the admission webhook checks of the profile against the existing performance
+// profiles 'ppList' and the nodes 'nodes' matching the profile node selector, but the cluster CPU
+// partitioning check. The warnings are returned only when no error is found.
+func ValidateAgainst(r *performancev2.PerformanceProfile, ppList *performancev2.PerformanceProfileList, nodes []corev1.Node) (admission.Warnings, field.ErrorList) {
+	var allErrs field.ErrorList
+
+	// validate node selector duplication
+	allErrs = append(allErrs, validateNodeSelectorDuplication(r, ppList)...)
+
+	// validate basic fields
+	allErrs = append(allErrs, ValidateBasicFields(r)...)
+
+	if len(allErrs) == 0 {
+		allErrs = append(allErrs, validateTopologyPolicyOptionsSupport(r, nodes)...)
+	}
+
+	if len(allErrs) == 0 {
+		return getWarnings(r, nodes), nil
+	}
+	return nil, allErrs
+}
+
+// ValidateCPUPartitioning checks the profile does not contradict the cluster CPU partitioning mode 'mode', reported by
+// the cluster infrastructure status. On clusters with the AllNodes mode, the operator pins the management workloads to
+// the reserved CPUs of the profile, so the profile CPU sets must be valid whether the profile declares the mode or not.
+func ValidateCPUPartitioning(r *performancev2.PerformanceProfile, mode apiconfigv1.CPUPartitioningMode) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if mode == "" {
+		mode = apiconfigv1.CPUPartitioningNone
+	}
+
+	expected, ok := r.Annotations[performancev2.PerformanceProfileCPUPartitioningModeAnnotation]
+	if ok && apiconfigv1.CPUPartitioningMode(expected) != mode {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("metadata.annotations").Key(performancev2.PerformanceProfileCPUPartitioningModeAnnotation), expected,
+			fmt.Sprintf("the profile expects the CPU partitioning mode %s, the cluster CPU partitioning mode is %s", expected, mode)))
+	}
+
+	if mode == apiconfigv1.CPUPartitioningAllNodes {
+		allErrs = append(allErrs, validateCPUs(r)...)
+	}
+
+	return allErrs
+}
+
+// getWarnings returns the findings that do not prevent the profile from being applied,
+// but most likely make it behave differently from what is expected
+func getWarnings(r *performancev2.PerformanceProfile, nodes []corev1.Node) admission.Warnings {
+	warnings := admission.Warnings{}
+
+	if reserved, err := cpuset.Parse(string(*r.Spec.CPU.Reserved)); err == nil && reserved.Size()%2 != 0 {
+		warnings = append(warnings, fmt.Sprintf("spec.cpu.reserved: the reserved CPUs count %d is odd, "+
+			"on nodes with SMT enabled the reserved CPUs share a physical core with the isolated CPUs", reserved.Size()))
+	}
+
+	if r.Spec.CPU.NohzFull != nil && r.Spec.WorkloadHints != nil && r.Spec.WorkloadHints.RealTime != nil && !*r.Spec.WorkloadHints.RealTime {
+		warnings = append(warnings, "spec.cpu.nohzFull: the nohz_full kernel argument is set only with the realtime workload hint, "+
+			"the nohz_full CPUs are ignored")
+	}
+
+	if r.Spec.Scheduler != nil {
+		realTimeHint := r.Spec.WorkloadHints == nil || r.Spec.WorkloadHints.RealTime == nil || *r.Spec.WorkloadHints.RealTime
+		if r.Spec.Scheduler.RTRuntimeUs != nil && *r.Spec.Scheduler.RTRuntimeUs != -1 && realTimeHint {
+			warnings = append(warnings, "spec.scheduler.rtRuntimeUs: the realtime tasks are throttled with the realtime workload hint, "+
+				"the throttled tasks pause until the end of every period")
+		}
+		if r.Spec.Scheduler.TimerMigration != nil && !*r.Spec.Scheduler.TimerMigration {
+			warnings = append(warnings, "spec.scheduler.timerMigration: the timers are not evacuated from the CPUs "+
+				"that start running a guaranteed workload")
+		}
+	}
+
+	if warning := getHugePagesWarning(r, nodes); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	return warnings
+}
+
+// getHugePagesWarning warns when the huge pages take more than 80% of the memory of the smallest node
+func getHugePagesWarning(r *performancev2.PerformanceProfile, nodes []corev1.Node) string {
+	if r.Spec.HugePages == nil || len(nodes) == 0 {
+		return ""
+	}
+
+	// the pages defined by a memory percentage take the same share of the memory of every node
+	var hugepagesBytes, hugepagesPercent int64
+	for _, page := range r.Spec.HugePages.Pages {
+		if page.MemoryPercent != nil {
+			hugepagesPercent += int64(*page.MemoryPercent)
+			continue
+		}
+		size, err := resource.ParseQuantity(string(page.Size) + "i")
+		if err != nil {
+			continue
+		}
+		hugepagesBytes += size.Value() * int64(page.Count)
+	}
+	if hugepagesBytes == 0 && hugepagesPercent == 0 {
+		return ""
+	}
+
+	var smallest *corev1.Node
+	for i := range nodes {
+		memory := nodes[i].Status.Capacity.Memory()
+		if memory.IsZero() {
+			continue
+		}
+		if smallest == nil || memory.Cmp(*smallest.Status.Capacity.Memory()) < 0 {
+			smallest = &nodes[i]
+		}
+	}
+	if smallest == nil {
+		return ""
+	}
+
+	memory := smallest.Status.Capacity.Memory().Value()
+	percent := hugepagesBytes*100/memory + hugepagesPercent
+	if percent <= hugepagesMemoryWarningPercent {
+		return ""
+	}
+	return fmt.Sprintf("spec.hugepages.pages: the huge pages take %d%% of the memory of the node %q, "+
+		"leaving little memory to the system and the workloads", percent, smallest.Name)
+}
+
+func validateNodeSelectorDuplication(r *performancev2.PerformanceProfile, ppList *performancev2.PerformanceProfileList) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// validate node selector duplication
+	for _, pp := range ppList.Items {
+		// exclude the current profile from the check
+		if pp.Name == r.Name {
+			continue
+		}
+
+		if reflect.DeepEqual(pp.Spec.NodeSelector, r.Spec.NodeSelector) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.nodeSelector"), r.Spec.NodeSelector, fmt.Sprintf("the profile has the same node selector as the performance profile %q", pp.Name)))
+		}
+	}
+
+	return allErrs
+}
+
+func ValidateBasicFields(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateCPUs(r)...)
+	allErrs = append(allErrs, validateSelectors(r)...)
+	allErrs = append(allErrs, validateHugePages(r)...)
+	allErrs = append(allErrs, validateNUMA(r)...)
+	allErrs = append(allErrs, validateNet(r)...)
+	allErrs = append(allErrs, validateNetworkStack(r)...)
+	allErrs = append(allErrs, validateKernelModules(r)...)
+	allErrs = append(allErrs, validateDevices(r)...)
+	allErrs = append(allErrs, validateUdevRules(r)...)
+	allErrs = append(allErrs, validateKubeletConfigOverrides(r)...)
+	allErrs = append(allErrs, validateSystemd(r)...)
+	allErrs = append(allErrs, validateTimeSync(r)...)
+	allErrs = append(allErrs, validateScheduler(r)...)
+	allErrs = append(allErrs, validateRuntimes(r)...)
+	allErrs = append(allErrs, validateWorkloadHints(r)...)
+	allErrs = append(allErrs, validateCpuFrequency(r)...)
+	allErrs = append(allErrs, validateAnnotations(r)...)
+	allErrs = append(allErrs, validateDisableGenerated(r)...)
+
+	return allErrs
+}
+
+func validateCPUs(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+	// shortcut
+	cpus := r.Spec.CPU
+	if cpus == nil {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec.cpu"), "cpu section required"))
+	} else {
+		allErrs = append(allErrs, ValidateCPUs(field.NewPath("spec.cpu"), CPUs{
+			Reserved:            (*string)(cpus.Reserved),
+			Isolated:            (*string)(cpus.Isolated),
+			Offlined:            (*string)(cpus.Offlined),
+			Shared:              (*string)(cpus.Shared),
+			IRQServing:          (*string)(cpus.IRQServing),
+			NohzFull:            (*string)(cpus.NohzFull),
+			RCUNocbs:            (*string)(cpus.RCUNocbs),
+			EfficiencyCores:     (*string)(cpus.EfficiencyCores),
+			AllowMixedCoreTypes: cpus.AllowMixedCoreTypes != nil && *cpus.AllowMixedCoreTypes,
+		})...)
+
+		allErrs = append(allErrs, validateSMTPolicy(r)...)
+		allErrs = append(allErrs, validateIsolationMethod(r)...)
+		allErrs = append(allErrs, validateRCUKthreadPriority(r)...)
+	}
+	return allErrs
+}
+
+// validateRCUKthreadPriority makes sure the RCU kernel threads priority is a SCHED_FIFO priority,
+// and that the additional kernel arguments do not set it a second time
+func validateRCUKthreadPriority(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+	priority := r.Spec.CPU.RCUKthreadPriority
+	if priority == nil {
+		return allErrs
+	}
+
+	fldPath := field.NewPath("spec.cpu.rcuKthreadPriority")
+	if *priority < 0 || *priority > 99 {
+		allErrs = append(allErrs, field.Invalid(fldPath, *priority, "the RCU kernel threads priority must be between 0 and 99"))
+	}
+
+	for _, args := range r.Spec.AdditionalKernelArgs {
+		for _, arg := range strings.Fields(args) {
+			if strings.HasPrefix(arg, "rcutree.kthread_prio=") {
+				allErrs = append(allErrs, field.Invalid(fldPath, *priority,
+					fmt.Sprintf("the %s additional kernel argument conflicts with the RCU kernel threads priority", arg)))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateIsolationMethod(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+	isolationMethod := r.Spec.CPU.IsolationMethod
+	if isolationMethod == nil {
+		return allErrs
+	}
+
+	switch *isolationMethod {
+	case performancev2.CPUIsolationMethodKernelArg:
+	case performancev2.CPUIsolationMethodCgroupPartition:
+		// the containers running on the shared CPUs would be outside of the pods partition
+		if r.Spec.CPU.Shared != nil && *r.Spec.CPU.Shared != "" {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec.cpu.isolationMethod"),
+				"the shared CPUs are not part of the cgroup partition of the isolated CPUs, the cgroupPartition isolation method can not be used with shared CPUs"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("spec.cpu.isolationMethod"), *isolationMethod,
+			[]string{string(performancev2.CPUIsolationMethodKernelArg), string(performancev2.CPUIsolationMethodCgroupPartition)}))
+	}
+	return allErrs
+}
+
+func validateSMTPolicy(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+	smtPolicy := r.Spec.CPU.SMTPolicy
+	if smtPolicy == nil {
+		return allErrs
+	}
+
+	switch *smtPolicy {
+	case performancev2.SMTPolicyClusterDefault, performancev2.SMTPolicyDisableAll:
+	case performancev2.SMTPolicyDisableIsolatedOnly:
+		for _, arg := range r.Spec.AdditionalKernelArgs {
+			if arg == "nosmt" {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec.cpu.smtPolicy"), *smtPolicy,
+					"the nosmt additional kernel argument disables SMT on all the CPUs and conflicts with the disable-isolated-only SMT policy"))
+			}
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("spec.cpu.smtPolicy"), *smtPolicy,
+			[]string{string(performancev2.SMTPolicyClusterDefault), string(performancev2.SMTPolicyDisableAll), string(performancev2.SMTPolicyDisableIsolatedOnly)}))
+	}
+	return allErrs
+}
+
+func validateDisableGenerated(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	supported := []string{string(performancev2.GeneratedArtifactRuntimeClass), string(performancev2.GeneratedArtifactIRQBalanceConfig), string(performancev2.GeneratedArtifactRPSConfig)}
+	seen := map[performancev2.GeneratedArtifact]bool{}
+	for i, artifact := range r.Spec.DisableGenerated {
+		path := field.NewPath("spec.disableGenerated").Index(i)
+		switch artifact {
+		case performancev2.GeneratedArtifactRuntimeClass, performancev2.GeneratedArtifactIRQBalanceConfig, performancev2.GeneratedArtifactRPSConfig:
+		default:
+			allErrs = append(allErrs, field.NotSupported(path, artifact, supported))
+		}
+		if seen[artifact] {
+			allErrs = append(allErrs, field.Duplicate(path, artifact))
+		}
+		seen[artifact] = true
+	}
+	return allErrs
+}
+
+func validateSelectors(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.MachineConfigLabel != nil && len(r.Spec.MachineConfigLabel) > 1 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec.machineConfigLabel"), r.Spec.MachineConfigLabel, "you should provide only 1 MachineConfigLabel"))
+	}
+
+	if r.Spec.MachineConfigPoolSelector != nil && len(r.Spec.MachineConfigPoolSelector) > 1 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec.machineConfigPoolSelector"), r.Spec.MachineConfigLabel, "you should provide only 1 MachineConfigPoolSelector"))
+	}
+
+	if r.Spec.MachineConfigPoolMaxUnavailable != nil {
+		// scaled against 100 machines, so a percentage is positive when it is at least 1%
+		maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(r.Spec.MachineConfigPoolMaxUnavailable, 100, false)
+		if err != nil || maxUnavailable < 1 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.machineConfigPoolMaxUnavailable"), r.Spec.MachineConfigPoolMaxUnavailable.String(), "should be a positive number or percentage"))
+		}
+	}
+
+	allErrs = append(allErrs, r.Spec.MachineConfigPoolMaintenanceWindow.Validate(field.NewPath("spec.machineConfigPoolMaintenanceWindow"))...)
+
+	if r.Spec.NodeSelector == nil {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec.nodeSelector"), "the nodeSelector required"))
+	}
+
+	if len(r.Spec.NodeSelector) > 1 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec.nodeSelector"), r.Spec.NodeSelector, "you should provide ony 1 NodeSelector"))
+	}
+
+	// in case MachineConfigLabels or MachineConfigPoolSelector are not set, we expect a certain format (domain/role)
+	// on the NodeSelector in order to be able to calculate the default values for the former metioned fields.
+	if r.Spec.MachineConfigLabel == nil || r.Spec.MachineConfigPoolSelector == nil {
+		k, _ := components.GetFirstKeyAndValue(r.Spec.NodeSelector)
+		if _, _, err := components.SplitLabelKey(k); err != nil {
+			allErrs = append(allErrs, field.Invalid(
+				field.NewPath("spec.nodeSelector"),
+				r.Spec.NodeSelector,
+				"machineConfigLabels or machineConfigPoolSelector are not set, but we  can not set it automatically because of an invalid NodeSelector label key that can't be split into domain/role"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateHugePages(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.HugePages == nil {
+		return allErrs
+	}
+
+	// validate that default hugepages size has correct value, currently we support only 2M and 1G(x86_64 architecture)
+	if r.Spec.HugePages.DefaultHugePagesSize != nil {
+		defaultSize := *r.Spec.HugePages.DefaultHugePagesSize
+		if defaultSize != hugepagesSize1G && defaultSize != hugepagesSize2M {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.hugepages.defaultHugepagesSize"), r.Spec.HugePages.DefaultHugePagesSize, fmt.Sprintf("hugepages default size should be equal to %q or %q", hugepagesSize1G, hugepagesSize2M)))
+		}
+	}
+
+	for i, page := range r.Spec.HugePages.Pages {
+		if page.Size != hugepagesSize1G && page.Size != hugepagesSize2M {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.hugepages.pages"), r.Spec.HugePages.Pages, fmt.Sprintf("the page size should be equal to %q or %q", hugepagesSize1G, hugepagesSize2M)))
+		}
+
+		// 1G pages can rarely be allocated once the memory of the running node is fragmented
+		if page.Allocation != nil && *page.Allocation == performancev2.HugePageAllocationRuntime && page.Size != hugepagesSize2M {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.hugepages.pages").Index(i).Child("allocation"), *page.Allocation, fmt.Sprintf("only the pages with the size %q can be allocated at runtime", hugepagesSize2M)))
+		}
+
+		if page.MemoryPercent != nil {
+			pagePath := field.NewPath("spec.hugepages.pages").Index(i)
+			if page.Count != 0 {
+				allErrs = append(allErrs, field.Invalid(pagePath.Child("memoryPercent"), *page.MemoryPercent, "the count and the memory percentage of the pages are mutually exclusive"))
+			}
+			if *page.MemoryPercent < 1 || *page.MemoryPercent > 100 {
+				allErrs = append(allErrs, field.Invalid(pagePath.Child("memoryPercent"), *page.MemoryPercent, "the memory percentage should be between 1 and 100"))
+			}
+			// the operand resolves the percentage of the runtime pages against the memory of the node
+			runtimeAllocated := page.Allocation != nil && *page.Allocation == performancev2.HugePageAllocationRuntime
+			if !runtimeAllocated && r.Spec.HugePages.NodeMemorySize == nil {
+				allErrs = append(allErrs, field.Required(field.NewPath("spec.hugepages.nodeMemorySize"), "the node memory size is required to allocate a memory percentage of huge pages at boot"))
+			}
+		}
+
+		allErrs = append(allErrs, validatePageDuplication(r, &page, r.Spec.HugePages.Pages[i+1:])...)
+	}
+
+	if r.Spec.HugePages.NodeMemorySize != nil && r.Spec.HugePages.NodeMemorySize.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec.hugepages.nodeMemorySize"), r.Spec.HugePages.NodeMemorySize.String(), "the node memory size should be positive"))
+	}
+
+	return allErrs
+}
+
+func validatePageDuplication(r *performancev2.PerformanceProfile, page *performancev2.HugePage, pages []performancev2.HugePage) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for _, p := range pages {
+		if page.Size != p.Size {
+			continue
+		}
+
+		if page.Node != nil && p.Node == nil {
+			continue
+		}
+
+		if page.Node == nil && p.Node != nil {
+			continue
+		}
+
+		if page.Node == nil && p.Node == nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.hugepages.pages"), r.Spec.HugePages.Pages, fmt.Sprintf("the page with the size %q and without the specified NUMA node, has duplication", page.Size)))
+			continue
+		}
+
+		if *page.Node == *p.Node {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.hugepages.pages"), r.Spec.HugePages.Pages, fmt.Sprintf("the page with the size %q and with specified NUMA node %d, has duplication", page.Size, *page.Node)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateNUMA(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.NUMA == nil {
+		return allErrs
+	}
+
+	// validate NUMA topology policy matches allowed values
+	if r.Spec.NUMA.TopologyPolicy != nil {
+		policy := *r.Spec.NUMA.TopologyPolicy
+		if policy != kubeletconfigv1beta1.NoneTopologyManagerPolicy &&
+			policy != kubeletconfigv1beta1.BestEffortTopologyManagerPolicy &&
+			policy != kubeletconfigv1beta1.RestrictedTopologyManagerPolicy &&
+			policy != kubeletconfigv1beta1.SingleNumaNodeTopologyManagerPolicy {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.numa.topologyPolicy"), r.Spec.NUMA.TopologyPolicy, "unrecognized value for topologyPolicy"))
+		}
+	}
+
+	if r.Spec.NUMA.TopologyManagerScope != nil {
+		scope := *r.Spec.NUMA.TopologyManagerScope
+		if scope != kubeletconfigv1beta1.ContainerTopologyManagerScope && scope != kubeletconfigv1beta1.PodTopologyManagerScope {
+			allErrs = append(allErrs, field.NotSupported(field.NewPath("spec.numa.topologyManagerScope"), scope,
+				[]string{kubeletconfigv1beta1.ContainerTopologyManagerScope, kubeletconfigv1beta1.PodTopologyManagerScope}))
+		}
+	}
+
+	optionsPath := field.NewPath("spec.numa.topologyPolicyOptions")
+	for _, option := range sortedTopologyPolicyOptions(r.Spec.NUMA.TopologyPolicyOptions) {
+		value := r.Spec.NUMA.TopologyPolicyOptions[option]
+		switch option {
+		case topologyPolicyOptionPreferClosestNUMANodes:
+			if _, err := strconv.ParseBool(value); err != nil {
+				allErrs = append(allErrs, field.Invalid(optionsPath.Key(option), value, "the option value should be a boolean"))
+			}
+		case topologyPolicyOptionMaxAllowableNUMANodes:
+			if count, err := strconv.Atoi(value); err != nil || count < topologyPolicyMinMaxAllowableNUMANodesValue {
+				allErrs = append(allErrs, field.Invalid(optionsPath.Key(option), value,
+					fmt.Sprintf("the option value should be an integer not lower than %d", topologyPolicyMinMaxAllowableNUMANodesValue)))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(optionsPath, option,
+				[]string{topologyPolicyOptionPreferClosestNUMANodes, topologyPolicyOptionMaxAllowableNUMANodes}))
+		}
+	}
+	if len(r.Spec.NUMA.TopologyPolicyOptions) > 0 && r.Spec.NUMA.TopologyPolicy != nil &&
+		*r.Spec.NUMA.TopologyPolicy == kubeletconfigv1beta1.NoneTopologyManagerPolicy {
+		allErrs = append(allErrs, field.Invalid(optionsPath, r.Spec.NUMA.TopologyPolicyOptions, "the options can not be set with the none topology policy"))
+	}
+
+	// the real time kernel does not support the automatic NUMA balancing, TuneD drops the setting
+	if r.Spec.NUMA.AutomaticBalancing != nil && *r.Spec.NUMA.AutomaticBalancing &&
+		r.Spec.RealTimeKernel != nil && r.Spec.RealTimeKernel.Enabled != nil && *r.Spec.RealTimeKernel.Enabled {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec.numa.automaticBalancing"), r.Spec.NUMA.AutomaticBalancing, "automatic NUMA balancing can not be enabled together with the real time kernel"))
+	}
+
+	return allErrs
+}
+
+// validateTopologyPolicyOptionsSupport checks the kubelet of every node supports the topology manager policy options
+func validateTopologyPolicyOptionsSupport(r *performancev2.PerformanceProfile, nodes []corev1.Node) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.NUMA == nil {
+		return allErrs
+	}
+
+	optionsPath := field.NewPath("spec.numa.topologyPolicyOptions")
+	for _, option := range sortedTopologyPolicyOptions(r.Spec.NUMA.TopologyPolicyOptions) {
+		minVersion, ok := topologyPolicyOptionsKubeletVersions[option]
+		if !ok {
+			continue
+		}
+		for _, node := range nodes {
+			kubeletVersion, err := version.ParseGeneric(node.Status.NodeInfo.KubeletVersion)
+			if err != nil {
+				continue
+			}
+			if kubeletVersion.LessThan(minVersion) {
+				allErrs = append(allErrs, field.Invalid(optionsPath.Key(option), r.Spec.NUMA.TopologyPolicyOptions[option],
+					fmt.Sprintf("the option requires kubelet %s or newer, the node %q runs kubelet %s", minVersion, node.Name, node.Status.NodeInfo.KubeletVersion)))
+				break
+			}
+		}
+	}
+	return allErrs
+}
+
+func sortedTopologyPolicyOptions(options map[string]string) []string {
+	names := make([]string, 0, len(options))
+	for option := range options {
+		names = append(names, option)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateNet(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.Net == nil {
+		return allErrs
+	}
+
+	if r.Spec.Net.UserLevelNetworking != nil && *r.Spec.Net.UserLevelNetworking && r.Spec.CPU.Reserved == nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec.net"), r.Spec.Net, "can not set network devices queues count without specifying spec.cpu.reserved"))
+	}
+
+	for _, device := range r.Spec.Net.Devices {
+		if device.InterfaceName != nil && *device.InterfaceName == "" {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.net.devices"), r.Spec.Net.Devices, "device name cannot be empty"))
+		}
+		if device.VendorID != nil && !IsValid16bitsHexID(*device.VendorID) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.net.devices"), r.Spec.Net.Devices, fmt.Sprintf("device vendor ID %s has an invalid format. Vendor ID should be represented as 0x<4 hexadecimal digits> (16 bit representation)", *device.VendorID)))
+		}
+		if device.DeviceID != nil && !IsValid16bitsHexID(*device.DeviceID) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.net.devices"), r.Spec.Net.Devices, fmt.Sprintf("device model ID %s has an invalid format. Model ID should be represented as 0x<4 hexadecimal digits> (16 bit representation)", *device.DeviceID)))
+		}
+		if device.DeviceID != nil && device.VendorID == nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.net.devices"), r.Spec.Net.Devices, "device model ID can not be used without specifying the device vendor ID."))
+		}
+	}
+
+	for i, steering := range r.Spec.Net.PacketSteering {
+		steeringPath := field.NewPath("spec.net.packetSteering").Index(i)
+		device := steering.Device
+		if device.InterfaceName == nil && device.VendorID == nil {
+			allErrs = append(allErrs, field.Required(steeringPath.Child("device"), "the device should specify the interface name or the vendor ID"))
+		}
+		if device.InterfaceName != nil && *device.InterfaceName == "" {
+			allErrs = append(allErrs, field.Invalid(steeringPath.Child("device", "interfaceName"), *device.InterfaceName, "device name cannot be empty"))
+		}
+		if device.VendorID != nil && !IsValid16bitsHexID(*device.VendorID) {
+			allErrs = append(allErrs, field.Invalid(steeringPath.Child("device", "vendorID"), *device.VendorID, "Vendor ID should be represented as 0x<4 hexadecimal digits> (16 bit representation)"))
+		}
+		if device.DeviceID != nil && !IsValid16bitsHexID(*device.DeviceID) {
+			allErrs = append(allErrs, field.Invalid(steeringPath.Child("device", "deviceID"), *device.DeviceID, "Model ID should be represented as 0x<4 hexadecimal digits> (16 bit representation)"))
+		}
+		if device.DeviceID != nil && device.VendorID == nil {
+			allErrs = append(allErrs, field.Invalid(steeringPath.Child("device", "deviceID"), *device.DeviceID, "device model ID can not be used without specifying the device vendor ID"))
+		}
+
+		if steering.RPS != nil && !*steering.RPS && steering.XPS != nil && !*steering.XPS {
+			allErrs = append(allErrs, field.Invalid(steeringPath, steering, "at least one of the receive or the transmit packet steering should be enabled"))
+		}
+
+		if steering.CPUs == nil {
+			if r.Spec.CPU == nil || r.Spec.CPU.Reserved == nil {
+				allErrs = append(allErrs, field.Required(steeringPath.Child("cpus"), "can not steer the packets to the reserved CPUs without specifying spec.cpu.reserved"))
+			}
+			continue
+		}
+
+		cpus, err := cpuset.Parse(string(*steering.CPUs))
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(steeringPath.Child("cpus"), *steering.CPUs, err.Error()))
+			continue
+		}
+		if cpus.IsEmpty() {
+			allErrs = append(allErrs, field.Invalid(steeringPath.Child("cpus"), *steering.CPUs, "packet steering CPUs can not be empty"))
+		}
+		if r.Spec.CPU != nil && r.Spec.CPU.Offlined != nil {
+			offlined, err := cpuset.Parse(string(*r.Spec.CPU.Offlined))
+			if err == nil && !cpus.Intersection(offlined).IsEmpty() {
+				allErrs = append(allErrs, field.Invalid(steeringPath.Child("cpus"), *steering.CPUs, "packet steering CPUs can not include offlined CPUs"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateNetworkStack(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.NetworkStack == nil {
+		return allErrs
+	}
+
+	stack := r.Spec.NetworkStack
+	switch stack.Mode {
+	case performancev2.NetworkStackIPv4Only, performancev2.NetworkStackDualStack, performancev2.NetworkStackIPv6Primary:
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("spec.networkStack.mode"), stack.Mode,
+			[]string{string(performancev2.NetworkStackIPv4Only), string(performancev2.NetworkStackDualStack), string(performancev2.NetworkStackIPv6Primary)}))
+	}
+
+	realTimeKernel := r.Spec.RealTimeKernel != nil && r.Spec.RealTimeKernel.Enabled != nil && *r.Spec.RealTimeKernel.Enabled
+	sysctlsPath := field.NewPath("spec.networkStack.sysctls")
+	names := make([]string, 0, len(stack.Sysctls))
+	for name := range stack.Sysctls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := stack.Sysctls[name]
+		switch {
+		case !IsValidNetSysctlName(name):
+			allErrs = append(allErrs, field.Invalid(sysctlsPath.Key(name), name, "only the net.* sysctls are allowed"))
+		case name == netSysctlRPSDefaultMask:
+			allErrs = append(allErrs, field.Forbidden(sysctlsPath.Key(name), "the RPS mask is generated for the profile, use spec.net.packetSteering instead"))
+		case stack.Mode == performancev2.NetworkStackIPv4Only && strings.HasPrefix(name, "net.ipv6."):
+			allErrs = append(allErrs, field.Invalid(sysctlsPath.Key(name), name, "the IPv6 sysctls can not be set on the nodes of the ipv4-only network stack"))
+		case stack.Mode != performancev2.NetworkStackIPv4Only && netSysctlsDisablingIPv6[name] && value == "1":
+			allErrs = append(allErrs, field.Invalid(sysctlsPath.Key(name), value, fmt.Sprintf("IPv6 can not be disabled on the nodes of the %s network stack", stack.Mode)))
+		case realTimeKernel && netSysctlsBusyPolling[name]:
+			allErrs = append(allErrs, field.Forbidden(sysctlsPath.Key(name), "the real time kernel does not support busy polling"))
+		}
+		if value == "" || strings.ContainsAny(value, "\n=") {
+			allErrs = append(allErrs, field.Invalid(sysctlsPath.Key(name), value, "the sysctl value can not be empty nor contain a newline or an equal sign"))
+		}
+	}
+	return allErrs
+}
+
+func validateKernelModules(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	modules := map[string]bool{}
+	for i, module := range r.Spec.KernelModules {
+		modulePath := field.NewPath("spec.kernelModules").Index(i)
+		if !IsValidKernelModuleName(module.Name) {
+			allErrs = append(allErrs, field.Invalid(modulePath.Child("name"), module.Name, "kernel module name should consist of alphanumeric characters, '-' or '_'"))
+		}
+		// modprobe treats dashes and underscores in the module names as the same character
+		name := strings.ReplaceAll(module.Name, "-", "_")
+		if modules[name] {
+			allErrs = append(allErrs, field.Duplicate(modulePath.Child("name"), module.Name))
+		}
+		modules[name] = true
+
+		for j, option := range module.Options {
+			if option == "" || strings.ContainsAny(option, " \t\n") {
+				allErrs = append(allErrs, field.Invalid(modulePath.Child("options").Index(j), option, "kernel module option should be a non-empty parameter without whitespaces"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateDevices(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.Devices == nil {
+		return allErrs
+	}
+
+	devices := map[string]bool{}
+	for i, binding := range r.Spec.Devices.DriverBindings {
+		bindingPath := field.NewPath("spec.devices.driverBindings").Index(i)
+		if !IsValidPCIAddress(binding.PCIAddress) {
+			allErrs = append(allErrs, field.Invalid(bindingPath.Child("pciAddress"), binding.PCIAddress, "PCI address should be in the domain:bus:device.function format, for example 0000:3b:00.0"))
+		}
+		// the PCI addresses of the devices are lower case under sysfs
+		address := strings.ToLower(binding.PCIAddress)
+		if devices[address] {
+			allErrs = append(allErrs, field.Duplicate(bindingPath.Child("pciAddress"), binding.PCIAddress))
+		}
+		devices[address] = true
+
+		if binding.Driver != performancev2.DeviceDriverVFIOPCI && binding.Driver != performancev2.DeviceDriverIgbUIO {
+			allErrs = append(allErrs, field.NotSupported(bindingPath.Child("driver"), binding.Driver, []string{string(performancev2.DeviceDriverVFIOPCI), string(performancev2.DeviceDriverIgbUIO)}))
+		}
+	}
+	return allErrs
+}
+
+func validateUdevRules(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := map[string]bool{}
+	for i, rule := range r.Spec.UdevRules {
+		rulePath := field.NewPath("spec.udevRules").Index(i)
+		if !IsValidUdevRulesName(rule.Name) {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("name"), rule.Name, "udev rules name should consist of alphanumeric characters, '-' or '_', without the .rules suffix"))
+		}
+		if reservedUdevRulesNames[rule.Name] {
+			allErrs = append(allErrs, field.Forbidden(rulePath.Child("name"), fmt.Sprintf("the udev rules %q are generated for the profile", rule.Name)))
+		}
+		if names[rule.Name] {
+			allErrs = append(allErrs, field.Duplicate(rulePath.Child("name"), rule.Name))
+		}
+		names[rule.Name] = true
+
+		if err := ValidateUdevRules(rule.Rules); err != nil {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("rules"), rule.Rules, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateKubeletConfigOverrides(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.KubeletConfigOverrides == nil {
+		return allErrs
+	}
+
+	overridesPath := field.NewPath("spec.kubeletConfigOverrides")
+	overrides := map[string]json.RawMessage{}
+	if err := json.Unmarshal(r.Spec.KubeletConfigOverrides.Raw, &overrides); err != nil {
+		return append(allErrs, field.Invalid(overridesPath, string(r.Spec.KubeletConfigOverrides.Raw), fmt.Sprintf("kubelet config overrides should be a KubeletConfiguration object: %v", err)))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(r.Spec.KubeletConfigOverrides.Raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&kubeletconfigv1beta1.KubeletConfiguration{}); err != nil {
+		allErrs = append(allErrs, field.Invalid(overridesPath, string(r.Spec.KubeletConfigOverrides.Raw), fmt.Sprintf("kubelet config overrides should be a v1beta1 KubeletConfiguration snippet: %v", err)))
+	}
+
+	for _, owned := range components.KubeletConfigOwnedFields {
+		if _, ok := overrides[owned]; ok {
+			allErrs = append(allErrs, field.Forbidden(overridesPath.Child(owned), "the field is computed out of the profile, use the relevant profile fields instead"))
+		}
+	}
+	return allErrs
+}
+
+func validateScheduler(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.Scheduler == nil || r.Spec.Scheduler.RTRuntimeUs == nil {
+		return allErrs
+	}
+
+	// the kernel threads the profile runs with a realtime policy never run when the runtime is zero
+	rtRuntimeUs := *r.Spec.Scheduler.RTRuntimeUs
+	if rtRuntimeUs != -1 && (rtRuntimeUs < 1 || rtRuntimeUs > schedRTPeriodUs) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec.scheduler.rtRuntimeUs"), rtRuntimeUs,
+			fmt.Sprintf("realtime runtime should be -1 or between 1 and %d microseconds", schedRTPeriodUs)))
+	}
+	return allErrs
+}
+
+func validateSystemd(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.Systemd == nil {
+		return allErrs
+	}
+
+	slices := map[string]bool{}
+	for i, slice := range r.Spec.Systemd.Slices {
+		slicePath := field.NewPath("spec.systemd.slices").Index(i)
+		if !IsValidSliceName(slice.Name) {
+			allErrs = append(allErrs, field.Invalid(slicePath.Child("name"), slice.Name, "slice name should be a valid systemd unit name with the .slice suffix"))
+		} else if slice.Name == "kubepods.slice" || strings.HasPrefix(slice.Name, "kubepods-") {
+			allErrs = append(allErrs, field.Forbidden(slicePath.Child("name"), "the kubepods slices are managed by the kubelet"))
+		}
+		if slices[slice.Name] {
+			allErrs = append(allErrs, field.Duplicate(slicePath.Child("name"), slice.Name))
+		}
+		slices[slice.Name] = true
+
+		if slice.CPUWeight != nil && (*slice.CPUWeight < 1 || *slice.CPUWeight > 10000) {
+			allErrs = append(allErrs, field.Invalid(slicePath.Child("cpuWeight"), *slice.CPUWeight, "CPU weight should be between 1 and 10000"))
+		}
+
+		if slice.AllowedCPUs != nil {
+			allowedCPUs, err := cpuset.Parse(string(*slice.AllowedCPUs))
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(slicePath.Child("allowedCPUs"), *slice.AllowedCPUs, err.Error()))
+				continue
+			}
+			if allowedCPUs.IsEmpty() {
+				allErrs = append(allErrs, field.Invalid(slicePath.Child("allowedCPUs"), *slice.AllowedCPUs, "allowed CPUs can not be empty"))
+			}
+			if r.Spec.CPU != nil && r.Spec.CPU.Offlined != nil {
+				offlined, err := cpuset.Parse(string(*r.Spec.CPU.Offlined))
+				if err == nil && !allowedCPUs.Intersection(offlined).IsEmpty() {
+					allErrs = append(allErrs, field.Invalid(slicePath.Child("allowedCPUs"), *slice.AllowedCPUs, "allowed CPUs can not include offlined CPUs"))
+				}
+			}
+		}
+	}
+
+	tuningUnits := map[performancev2.TuningUnit]bool{}
+	for i, ordering := range r.Spec.Systemd.Units {
+		unitPath := field.NewPath("spec.systemd.units").Index(i)
+		switch ordering.Unit {
+		case performancev2.TuningUnitHugepagesAllocation, performancev2.TuningUnitSetCPUsOffline, performancev2.TuningUnitSetSMTSiblingsOffline, performancev2.TuningUnitClearIRQBalanceBannedCPUs, performancev2.TuningUnitCPUSetConfigure:
+		default:
+			allErrs = append(allErrs, field.NotSupported(unitPath.Child("unit"), ordering.Unit, []string{
+				string(performancev2.TuningUnitHugepagesAllocation), string(performancev2.TuningUnitSetCPUsOffline), string(performancev2.TuningUnitSetSMTSiblingsOffline),
+				string(performancev2.TuningUnitClearIRQBalanceBannedCPUs), string(performancev2.TuningUnitCPUSetConfigure),
+			}))
+		}
+		if tuningUnits[ordering.Unit] {
+			allErrs = append(allErrs, field.Duplicate(unitPath.Child("unit"), ordering.Unit))
+		}
+		tuningUnits[ordering.Unit] = true
+
+		before := map[string]bool{}
+		for j, name := range ordering.Before {
+			if !IsValidUnitName(name) {
+				allErrs = append(allErrs, field.Invalid(unitPath.Child("before").Index(j), name, "unit name should be a valid systemd unit name with the unit type suffix"))
+			}
+			before[name] = true
+		}
+		for j, name := range ordering.After {
+			switch {
+			case !IsValidUnitName(name):
+				allErrs = append(allErrs, field.Invalid(unitPath.Child("after").Index(j), name, "unit name should be a valid systemd unit name with the unit type suffix"))
+			case name == "kubelet.service":
+				allErrs = append(allErrs, field.Forbidden(unitPath.Child("after").Index(j), "the tuning units start before kubelet.service"))
+			case before[name]:
+				allErrs = append(allErrs, field.Invalid(unitPath.Child("after").Index(j), name, "the tuning unit can not start both before and after the same unit"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateTimeSync(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.TimeSync == nil {
+		return allErrs
+	}
+
+	timeSyncPath := field.NewPath("spec.timeSync")
+	if r.Spec.TimeSync.CPUs == nil {
+		allErrs = append(allErrs, field.Required(timeSyncPath.Child("cpus"), "time synchronization CPUs are required"))
+	} else if cpus, err := cpuset.Parse(string(*r.Spec.TimeSync.CPUs)); err != nil {
+		allErrs = append(allErrs, field.Invalid(timeSyncPath.Child("cpus"), *r.Spec.TimeSync.CPUs, err.Error()))
+	} else if cpus.IsEmpty() {
+		allErrs = append(allErrs, field.Invalid(timeSyncPath.Child("cpus"), *r.Spec.TimeSync.CPUs, "time synchronization CPUs can not be empty"))
+	} else if r.Spec.CPU != nil && r.Spec.CPU.Reserved != nil {
+		// the time synchronization services are host processes, they run on the reserved CPUs
+		reserved, err := cpuset.Parse(string(*r.Spec.CPU.Reserved))
+		if err == nil && !cpus.IsSubsetOf(reserved) {
+			allErrs = append(allErrs, field.Invalid(timeSyncPath.Child("cpus"), *r.Spec.TimeSync.CPUs, "time synchronization CPUs should be a subset of the reserved CPUs"))
+		}
+	}
+
+	services := map[performancev2.TimeSyncService]bool{}
+	for i, service := range r.Spec.TimeSync.Services {
+		servicePath := timeSyncPath.Child("services").Index(i)
+		if !isTimeSyncService(service) {
+			allErrs = append(allErrs, field.NotSupported(servicePath, service, getTimeSyncServiceNames()))
+		}
+		if services[service] {
+			allErrs = append(allErrs, field.Duplicate(servicePath, service))
+		}
+		services[service] = true
+	}
+	return allErrs
+}
+
+func isTimeSyncService(service performancev2.TimeSyncService) bool {
+	switch service {
+	case performancev2.TimeSyncServicePTP4L, performancev2.TimeSyncServicePHC2SYS, performancev2.TimeSyncServiceChronyd:
+		return true
+	}
+	return false
+}
+
+func getTimeSyncServiceNames() []string {
+	return []string{string(performancev2.TimeSyncServicePTP4L), string(performancev2.TimeSyncServicePHC2SYS), string(performancev2.TimeSyncServiceChronyd)}
+}
+
+func validateRuntimes(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// the handlers rendered by the operator itself
+	reserved := map[string]bool{"runc": true, "crun": true, "high-performance": true}
+	handlers := map[string]bool{}
+	for i, handler := range r.Spec.Runtimes {
+		handlerPath := field.NewPath("spec.runtimes").Index(i)
+		if errs := utilvalidation.IsDNS1123Label(handler.Name); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(handlerPath.Child("name"), handler.Name, strings.Join(errs, ", ")))
+		} else if reserved[handler.Name] {
+			allErrs = append(allErrs, field.Forbidden(handlerPath.Child("name"), fmt.Sprintf("the %q runtime handler is managed by the operator", handler.Name)))
+		}
+		if handlers[handler.Name] {
+			allErrs = append(allErrs, field.Duplicate(handlerPath.Child("name"), handler.Name))
+		}
+		handlers[handler.Name] = true
+
+		if handler.RuntimePath != nil && !IsValidRuntimeHandlerPath(*handler.RuntimePath) {
+			allErrs = append(allErrs, field.Invalid(handlerPath.Child("runtimePath"), *handler.RuntimePath, "runtime path should be an absolute path"))
+		}
+		if handler.RuntimeRoot != nil && !IsValidRuntimeHandlerPath(*handler.RuntimeRoot) {
+			allErrs = append(allErrs, field.Invalid(handlerPath.Child("runtimeRoot"), *handler.RuntimeRoot, "runtime root should be an absolute path"))
+		}
+		for j, annotation := range handler.AllowedAnnotations {
+			if !IsValidRuntimeHandlerAnnotation(annotation) {
+				allErrs = append(allErrs, field.Invalid(handlerPath.Child("allowedAnnotations").Index(j), annotation, "annotation should consist of alphanumeric characters, '-', '_', '.' or '/'"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateWorkloadHints(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.WorkloadHints == nil {
+		return allErrs
+	}
+
+	if r.Spec.RealTimeKernel != nil {
+		if r.Spec.RealTimeKernel.Enabled != nil && *r.Spec.RealTimeKernel.Enabled {
+			if r.Spec.WorkloadHints.RealTime != nil && !*r.Spec.WorkloadHints.RealTime {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec.workloadHints.realTime"), r.Spec.WorkloadHints.RealTime, "realtime kernel is enabled, but realtime workload hint is explicitly disable"))
+			}
+		}
+	}
+
+	if r.Spec.WorkloadHints.HighPowerConsumption != nil && *r.Spec.WorkloadHints.HighPowerConsumption {
+		if r.Spec.WorkloadHints.PerPodPowerManagement != nil && *r.Spec.WorkloadHints.PerPodPowerManagement {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.workloadHints.HighPowerConsumption"), r.Spec.WorkloadHints.HighPowerConsumption, "Invalid WorkloadHints configuration: HighPowerConsumption and PerPodPowerManagement can not be both enabled"))
+		}
+	}
+
+	if r.Spec.WorkloadHints.MixedCpus != nil && *r.Spec.WorkloadHints.MixedCpus {
+		if r.Spec.CPU.Shared == nil || *r.Spec.CPU.Shared == "" {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.workloadHints.MixedCpus"), r.Spec.WorkloadHints.MixedCpus, "Invalid WorkloadHints configuration: MixedCpus enabled but no shared CPUs were specified"))
+		}
+	}
+	return allErrs
+}
+
+func validateCpuFrequency(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.HardwareTuning != nil {
+		if r.Spec.HardwareTuning.IsolatedCpuFreq != nil && r.Spec.HardwareTuning.ReservedCpuFreq != nil {
+			isolatedFreq := *r.Spec.HardwareTuning.IsolatedCpuFreq
+			if isolatedFreq == 0 {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec.hardwareTuning.isolatedCpuFreq"), r.Spec.HardwareTuning.IsolatedCpuFreq, "isolated cpu frequency can not be equal to 0"))
+			}
+
+			reservedFreq := *r.Spec.HardwareTuning.ReservedCpuFreq
+			if reservedFreq == 0 {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec.hardwareTuning.reservedCpuFreq"), r.Spec.HardwareTuning.ReservedCpuFreq, "reserved cpu frequency can not be equal to 0"))
+			}
+		} else {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.hardwareTuning"), r.Spec.HardwareTuning, "both isolated and reserved cpu frequency must be declared"))
+		}
+		return allErrs
+	}
+
+	return allErrs
+}
+
+func validateAnnotations(r *performancev2.PerformanceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Annotations == nil {
+		return allErrs
+	}
+
+	annotationsPath := field.NewPath("metadata.annotations")
+	if suffix, ok := r.Annotations[performancev2.PerformanceProfileMachineConfigNameSuffixAnnotation]; ok {
+		// the suffix is appended to the "50-" prefix, the result should be a valid object name
+		for _, msg := range utilvalidation.IsDNS1123Subdomain("50-" + suffix) {
+			allErrs = append(allErrs, field.Invalid(annotationsPath.Key(performancev2.PerformanceProfileMachineConfigNameSuffixAnnotation), suffix, msg))
+		}
+	}
+
+	if rawLabels, ok := r.Annotations[performancev2.PerformanceProfileMachineConfigLabelsAnnotation]; ok {
+		labelsPath := annotationsPath.Key(performancev2.PerformanceProfileMachineConfigLabelsAnnotation)
+		labels := map[string]string{}
+		if err := json.Unmarshal([]byte(rawLabels), &labels); err != nil {
+			allErrs = append(allErrs, field.Invalid(labelsPath, rawLabels, fmt.Sprintf("failed to parse machine config labels: %v", err)))
+			return allErrs
+		}
+
+		for k, v := range labels {
+			for _, msg := range utilvalidation.IsQualifiedName(k) {
+				allErrs = append(allErrs, field.Invalid(labelsPath, k, msg))
+			}
+			for _, msg := range utilvalidation.IsValidLabelValue(v) {
+				allErrs = append(allErrs, field.Invalid(labelsPath, v, msg))
+			}
+		}
+	}
+
+	if rawProbe, ok := r.Annotations[performancev2.PerformanceProfileLatencyProbeAnnotation]; ok {
+		probePath := annotationsPath.Key(performancev2.PerformanceProfileLatencyProbeAnnotation)
+		probe := &performancev2.LatencyProbe{}
+		if err := json.Unmarshal([]byte(rawProbe), probe); err != nil {
+			allErrs = append(allErrs, field.Invalid(probePath, rawProbe, fmt.Sprintf("failed to parse latency probe: %v", err)))
+			return allErrs
+		}
+
+		if probe.Image == "" {
+			allErrs = append(allErrs, field.Invalid(probePath, rawProbe, "latency probe image is required"))
+		}
+		if probe.Tool != "" && probe.Tool != performancev2.LatencyProbeToolCyclictest && probe.Tool != performancev2.LatencyProbeToolOslat {
+			allErrs = append(allErrs, field.NotSupported(probePath, probe.Tool,
+				[]string{string(performancev2.LatencyProbeToolCyclictest), string(performancev2.LatencyProbeToolOslat)}))
+		}
+		if probe.Duration != nil && probe.Duration.Duration < time.Second {
+			allErrs = append(allErrs, field.Invalid(probePath, probe.Duration.String(), "latency probe duration should be at least one second"))
+		}
+		if probe.CPUs != nil && *probe.CPUs < 2 {
+			allErrs = append(allErrs, field.Invalid(probePath, *probe.CPUs, "latency probe requires at least two CPUs"))
+		}
+	}
+
+	if mode, ok := r.Annotations[performancev2.PerformanceProfileCPUPartitioningModeAnnotation]; ok {
+		supported := []string{string(apiconfigv1.CPUPartitioningAllNodes), string(apiconfigv1.CPUPartitioningNone)}
+		if mode != supported[0] && mode != supported[1] {
+			allErrs = append(allErrs, field.NotSupported(annotationsPath.Key(performancev2.PerformanceProfileCPUPartitioningModeAnnotation), mode, supported))
+		}
+	}
+
+	if export, ok := r.Annotations[performancev2.PerformanceProfileExportArtifactsAnnotation]; ok && export != "true" && export != "false" {
+		allErrs = append(allErrs, field.NotSupported(annotationsPath.Key(performancev2.PerformanceProfileExportArtifactsAnnotation), export, []string{"true", "false"}))
+	}
+
+	if externalServices, ok := r.Annotations[performancev2.PerformanceProfileTimeSyncExternalServicesAnnotation]; ok {
+		for _, service := range strings.Split(externalServices, ",") {
+			if !isTimeSyncService(performancev2.TimeSyncService(strings.TrimSpace(service))) {
+				allErrs = append(allErrs, field.NotSupported(annotationsPath.Key(performancev2.PerformanceProfileTimeSyncExternalServicesAnnotation), service, getTimeSyncServiceNames()))
+			}
+		}
+	}
+
+	if rawRollback, ok := r.Annotations[performancev2.PerformanceProfileAutoRollbackAnnotation]; ok {
+		rollbackPath := annotationsPath.Key(performancev2.PerformanceProfileAutoRollbackAnnotation)
+		rollback := &performancev2.AutoRollback{}
+		if err := json.Unmarshal([]byte(rawRollback), rollback); err != nil {
+			allErrs = append(allErrs, field.Invalid(rollbackPath, rawRollback, fmt.Sprintf("failed to parse auto rollback: %v", err)))
+			return allErrs
+		}
+
+		if rollback.DegradedTunedProfiles != nil && *rollback.DegradedTunedProfiles < 1 {
+			allErrs = append(allErrs, field.Invalid(rollbackPath, *rollback.DegradedTunedProfiles, "auto rollback degraded tuned profiles should be at least one"))
+		}
+	}
+
+	return allErrs
+}
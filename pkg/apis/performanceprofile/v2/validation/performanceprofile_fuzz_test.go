@@ -0,0 +1,59 @@
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	apiconfigv1 "github.com/openshift/api/config/v1"
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const fuzzTestProfile = `{
+	"apiVersion": "performance.openshift.io/v2",
+	"kind": "PerformanceProfile",
+	"metadata": {"name": "test"},
+	"spec": {
+		"cpu": {"isolated": "2-7", "reserved": "0-1"},
+		"hugepages": {"defaultHugepagesSize": "1G", "pages": [{"size": "1G", "count": 4}]},
+		"nodeSelector": {"node-role.kubernetes.io/worker-cnf": ""}
+	}
+}`
+
+// FuzzValidate checks the properties the webhook relies on hold for any profile: the validation does not panic,
+// is deterministic, returns the warnings only for the valid profiles and finds at least the errors of the profile
+// fields. The validation runs without a client, the same way the clients validate a profile out of the cluster.
+func FuzzValidate(f *testing.F) {
+	f.Add([]byte(fuzzTestProfile))
+	f.Add([]byte(`{"spec": {"cpu": {"isolated": "0-3", "reserved": "2-5"}}}`))
+	f.Add([]byte(`{"spec": {"cpu": {"isolated": "1", "reserved": "0"}, "numa": {"topologyPolicy": "invalid"}}}`))
+	f.Add([]byte(`{"metadata": {"annotations": {"performance.openshift.io/machine-config-labels": "{"}}, "spec": {}}`))
+
+	mode := apiconfigv1.CPUPartitioningAllNodes
+	validate := func(profile *performancev2.PerformanceProfile) ([]string, field.ErrorList) {
+		return Validate(profile, ClusterState{CPUPartitioning: &mode})
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		profile := &performancev2.PerformanceProfile{}
+		if err := json.Unmarshal(data, profile); err != nil {
+			t.Skip()
+		}
+
+		fieldErrs := ValidateBasicFields(profile)
+		warnings, errs := validate(profile)
+		if len(fieldErrs) != 0 && len(warnings) != 0 {
+			t.Errorf("invalid profile returned warnings: %v", warnings)
+		}
+		if len(errs) < len(fieldErrs) {
+			t.Errorf("validation found %d errors, the profile fields have %d errors", len(errs), len(fieldErrs))
+		}
+
+		againWarnings, againErrs := validate(profile)
+		if !reflect.DeepEqual(warnings, againWarnings) || !reflect.DeepEqual(errorStrings(errs), errorStrings(againErrs)) {
+			t.Errorf("validation is not deterministic")
+		}
+	})
+}
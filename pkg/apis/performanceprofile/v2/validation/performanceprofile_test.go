@@ -0,0 +1,1456 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiconfigv1 "github.com/openshift/api/config/v1"
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	NodeSelectorRole = "barRole"
+)
+
+const (
+	// HugePageSize defines the huge page size used for tests
+	HugePageSize1G = performancev2.HugePageSize("1G")
+	// HugePagesCount defines the huge page count used for tests
+	HugePagesCount = 4
+	// IsolatedCPUs defines the isolated CPU set used for tests
+	IsolatedCPUs = performancev2.CPUSet("4-6")
+	// ReservedCPUs defines the reserved CPU set used for tests
+	ReservedCPUs = performancev2.CPUSet("0-3")
+	// ReservedCPUs defines the reserved CPU set used for tests
+	OfflinedCPUs = performancev2.CPUSet("7")
+	// SingleNUMAPolicy defines the topologyManager policy used for tests
+	SingleNUMAPolicy = "single-numa-node"
+	//MachineConfigLabelKey defines the MachineConfig label key of the test profile
+	MachineConfigLabelKey = "mcKey"
+	//MachineConfigLabelValue defines the MachineConfig label value of the test profile
+	MachineConfigLabelValue = "mcValue"
+	//MachineConfigPoolLabelKey defines the MachineConfigPool label key of the test profile
+	MachineConfigPoolLabelKey = "mcpKey"
+	//MachineConfigPoolLabelValue defines the MachineConfigPool label value of the test profile
+	MachineConfigPoolLabelValue = "mcpValue"
+	//NetDeviceName defines a net device name for the test profile
+	NetDeviceName = "enp0s4"
+	//NetDeviceVendorID defines a net device vendor ID for the test profile
+	NetDeviceVendorID = "0x1af4"
+	//NetDeviceModelID defines a net device model ID for the test profile
+	NetDeviceModelID = "0x1000"
+)
+
+// NewPerformanceProfile returns new performance profile object that used for tests
+func NewPerformanceProfile(name string) *performancev2.PerformanceProfile {
+	size := HugePageSize1G
+	isolatedCPUs := IsolatedCPUs
+	reservedCPUs := ReservedCPUs
+	offlinedCPUs := OfflinedCPUs
+	numaPolicy := SingleNUMAPolicy
+
+	netDeviceName := NetDeviceName
+	netDeviceVendorID := NetDeviceVendorID
+	netDeviceModelID := NetDeviceModelID
+
+	return &performancev2.PerformanceProfile{
+		TypeMeta: metav1.TypeMeta{Kind: "PerformanceProfile"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			UID:  "11111111-1111-1111-1111-1111111111111",
+		},
+		Spec: performancev2.PerformanceProfileSpec{
+			CPU: &performancev2.CPU{
+				Isolated: &isolatedCPUs,
+				Reserved: &reservedCPUs,
+				Offlined: &offlinedCPUs,
+			},
+			HugePages: &performancev2.HugePages{
+				DefaultHugePagesSize: &size,
+				Pages: []performancev2.HugePage{
+					{
+						Count: HugePagesCount,
+						Size:  size,
+					},
+				},
+			},
+			RealTimeKernel: &performancev2.RealTimeKernel{
+				Enabled: pointer.Bool(true),
+			},
+			NUMA: &performancev2.NUMA{
+				TopologyPolicy: &numaPolicy,
+			},
+			Net: &performancev2.Net{
+				UserLevelNetworking: pointer.Bool(true),
+				Devices: []performancev2.Device{
+					{
+						InterfaceName: &netDeviceName,
+						VendorID:      &netDeviceVendorID,
+						DeviceID:      &netDeviceModelID,
+					},
+				},
+			},
+			MachineConfigLabel: map[string]string{
+				MachineConfigLabelKey: MachineConfigLabelValue,
+			},
+			MachineConfigPoolSelector: map[string]string{
+				MachineConfigPoolLabelKey: MachineConfigPoolLabelValue,
+			},
+			NodeSelector: map[string]string{
+				"nodekey": "nodeValue",
+			},
+		},
+	}
+}
+
+var _ = Describe("PerformanceProfile", func() {
+	var profile *performancev2.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = NewPerformanceProfile("test")
+	})
+
+	Describe("CPU validation", func() {
+		It("should have CPU fields populated", func() {
+			errors := validateCPUs(profile)
+			Expect(errors).To(BeEmpty(), "should not have validation errors with populated CPU fields")
+
+			profile.Spec.CPU.Isolated = nil
+			errors = validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error with missing CPU Isolated field")
+			Expect(errors[0].Error()).To(ContainSubstring("isolated CPUs required"))
+
+			cpus := performancev2.CPUSet("0")
+			profile.Spec.CPU.Isolated = &cpus
+			profile.Spec.CPU.Reserved = nil
+			errors = validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error with missing CPU reserved field")
+			Expect(errors[0].Error()).To(ContainSubstring("reserved CPUs required"))
+
+			invalidCPUs := performancev2.CPUSet("bla")
+			profile.Spec.CPU.Isolated = &invalidCPUs
+			errors = validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error when isolated CPUs has invalid format")
+
+			profile.Spec.CPU = nil
+			errors = validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error with missing CPU")
+			Expect(errors[0].Error()).To(ContainSubstring("cpu section required"))
+		})
+
+		It("should reject cpus allocation with no reserved CPUs", func() {
+			reservedCPUs := performancev2.CPUSet("")
+			isolatedCPUs := performancev2.CPUSet("0-6")
+			offlinedCPUs := performancev2.CPUSet("7")
+			profile.Spec.CPU.Reserved = &reservedCPUs
+			profile.Spec.CPU.Isolated = &isolatedCPUs
+			profile.Spec.CPU.Offlined = &offlinedCPUs
+			errors := validateCPUs(profile)
+			Expect(errors[0].Error()).To(ContainSubstring("reserved CPUs can not be empty"))
+		})
+
+		It("should reject cpus allocation with no isolated CPUs", func() {
+			reservedCPUs := performancev2.CPUSet("0-3")
+			isolatedCPUs := performancev2.CPUSet("")
+			profile.Spec.CPU.Reserved = &reservedCPUs
+			profile.Spec.CPU.Isolated = &isolatedCPUs
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Error()).To(ContainSubstring("isolated CPUs can not be empty"))
+		})
+
+		It("should allow cpus allocation with no offlined CPUs", func() {
+			cpusIsolaled := performancev2.CPUSet("0")
+			cpusReserved := performancev2.CPUSet("1")
+			profile.Spec.CPU.Isolated = &cpusIsolaled
+			profile.Spec.CPU.Reserved = &cpusReserved
+			profile.Spec.CPU.Offlined = nil
+			errors := validateCPUs(profile)
+			Expect(errors).To(BeEmpty())
+		})
+
+		It("should reject cpus allocation with overlapping sets between reserved and isolated", func() {
+			reservedCPUs := performancev2.CPUSet("0-7")
+			isolatedCPUs := performancev2.CPUSet("0-15")
+			profile.Spec.CPU.Reserved = &reservedCPUs
+			profile.Spec.CPU.Isolated = &isolatedCPUs
+			profile.Spec.CPU.Offlined = nil
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error when reserved and isolation CPUs have overlap")
+			Expect(errors[0].Error()).To(Or(ContainSubstring("reserved and isolated cpus overlap"), ContainSubstring("isolated and reserved cpus overlap")))
+		})
+
+		It("should reject cpus allocation with overlapping sets between reserved and offlined", func() {
+			reservedCPUs := performancev2.CPUSet("0-7")
+			isolatedCPUs := performancev2.CPUSet("8-11")
+			offlinedCPUs := performancev2.CPUSet("0,12-15")
+			profile.Spec.CPU.Reserved = &reservedCPUs
+			profile.Spec.CPU.Isolated = &isolatedCPUs
+			profile.Spec.CPU.Offlined = &offlinedCPUs
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error when reserved and offlined CPUs have overlap")
+			Expect(errors[0].Error()).To(Or(ContainSubstring("reserved and offlined cpus overlap"), ContainSubstring("offlined and reserved cpus overlap")))
+		})
+
+		It("should reject cpus allocation with overlapping sets between isolated and offlined", func() {
+			reservedCPUs := performancev2.CPUSet("0-7")
+			isolatedCPUs := performancev2.CPUSet("8-11")
+			offlinedCPUs := performancev2.CPUSet("10-15")
+			profile.Spec.CPU.Reserved = &reservedCPUs
+			profile.Spec.CPU.Isolated = &isolatedCPUs
+			profile.Spec.CPU.Offlined = &offlinedCPUs
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error when isolated and offlined CPUs have overlap")
+			Expect(errors[0].Error()).To(Or(ContainSubstring("isolated and offlined cpus overlap"), ContainSubstring("offlined and isolated cpus overlap")))
+		})
+
+		It("should reject cpus allocation with overlapping sets between isolated and shared", func() {
+			reservedCPUs := performancev2.CPUSet("0-6")
+			isolatedCPUs := performancev2.CPUSet("8-11")
+			sharedCPUs := performancev2.CPUSet("10-15")
+			profile.Spec.CPU.Reserved = &reservedCPUs
+			profile.Spec.CPU.Isolated = &isolatedCPUs
+			profile.Spec.CPU.Shared = &sharedCPUs
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error when isolated and shared CPUs have overlap")
+			Expect(errors[0].Error()).To(Or(ContainSubstring("isolated and shared cpus overlap"), ContainSubstring("shared and isolated cpus overlap")))
+		})
+
+		It("should allow IRQ serving CPUs which are a subset of the reserved CPUs", func() {
+			irqServingCPUs := performancev2.CPUSet("0-1")
+			profile.Spec.CPU.IRQServing = &irqServingCPUs
+			errors := validateCPUs(profile)
+			Expect(errors).To(BeEmpty())
+		})
+
+		It("should reject IRQ serving CPUs which are not part of the reserved CPUs", func() {
+			irqServingCPUs := performancev2.CPUSet("3-4")
+			profile.Spec.CPU.IRQServing = &irqServingCPUs
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Error()).To(ContainSubstring("IRQ serving CPUs must be a subset of the reserved CPUs"))
+		})
+
+		It("should reject empty IRQ serving CPUs", func() {
+			irqServingCPUs := performancev2.CPUSet("")
+			profile.Spec.CPU.IRQServing = &irqServingCPUs
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Error()).To(ContainSubstring("IRQ serving CPUs can not be empty"))
+		})
+
+		It("should allow nohz_full and rcu_nocbs CPUs which are subsets of the isolated CPUs", func() {
+			nohzFullCPUs := performancev2.CPUSet("")
+			rcuNocbsCPUs := performancev2.CPUSet("4-5")
+			profile.Spec.CPU.NohzFull = &nohzFullCPUs
+			profile.Spec.CPU.RCUNocbs = &rcuNocbsCPUs
+			errors := validateCPUs(profile)
+			Expect(errors).To(BeEmpty())
+		})
+
+		It("should reject nohz_full CPUs which are not part of the isolated CPUs", func() {
+			nohzFullCPUs := performancev2.CPUSet("0-2")
+			profile.Spec.CPU.NohzFull = &nohzFullCPUs
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Field).To(Equal("spec.cpu.nohzFull"))
+			Expect(errors[0].Error()).To(ContainSubstring("nohz_full CPUs must be a subset of the isolated CPUs"))
+		})
+
+		It("should reject rcu_nocbs CPUs which are not part of the isolated CPUs", func() {
+			rcuNocbsCPUs := performancev2.CPUSet("1-3")
+			profile.Spec.CPU.RCUNocbs = &rcuNocbsCPUs
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Field).To(Equal("spec.cpu.rcuNocbs"))
+			Expect(errors[0].Error()).To(ContainSubstring("rcu_nocbs CPUs must be a subset of the isolated CPUs"))
+		})
+
+		It("should reject out of range RCU kernel threads priorities", func() {
+			profile.Spec.CPU.RCUKthreadPriority = pointer.Int32(99)
+			Expect(validateCPUs(profile)).To(BeEmpty())
+
+			profile.Spec.CPU.RCUKthreadPriority = pointer.Int32(100)
+			errors := validateCPUs(profile)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Field).To(Equal("spec.cpu.rcuKthreadPriority"))
+		})
+
+		It("should reject the RCU kernel threads priority set by the additional kernel arguments too", func() {
+			profile.Spec.CPU.RCUKthreadPriority = pointer.Int32(2)
+			profile.Spec.AdditionalKernelArgs = []string{"audit=0 rcutree.kthread_prio=5"}
+			errors := validateCPUs(profile)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Error()).To(ContainSubstring("rcutree.kthread_prio=5 additional kernel argument conflicts"))
+		})
+
+		It("should allow disabling SMT on the isolated CPUs only", func() {
+			smtPolicy := performancev2.SMTPolicyDisableIsolatedOnly
+			profile.Spec.CPU.SMTPolicy = &smtPolicy
+			errors := validateCPUs(profile)
+			Expect(errors).To(BeEmpty())
+		})
+
+		It("should reject an unknown SMT policy", func() {
+			smtPolicy := performancev2.SMTPolicy("disable-reserved-only")
+			profile.Spec.CPU.SMTPolicy = &smtPolicy
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Error()).To(ContainSubstring("Unsupported value"))
+		})
+
+		It("should reject the cgroup partition isolation method with the shared CPUs", func() {
+			isolationMethod := performancev2.CPUIsolationMethodCgroupPartition
+			profile.Spec.CPU.IsolationMethod = &isolationMethod
+			sharedCPUs := performancev2.CPUSet("8-9")
+			profile.Spec.CPU.Shared = &sharedCPUs
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Field).To(Equal("spec.cpu.isolationMethod"))
+		})
+
+		It("should reject an unknown isolation method", func() {
+			isolationMethod := performancev2.CPUIsolationMethod("cpuset")
+			profile.Spec.CPU.IsolationMethod = &isolationMethod
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Error()).To(ContainSubstring("Unsupported value"))
+		})
+
+		It("should allow isolated CPUs made of a single core type", func() {
+			efficiencyCores := performancev2.CPUSet("4-7")
+			profile.Spec.CPU.EfficiencyCores = &efficiencyCores
+			errors := validateCPUs(profile)
+			Expect(errors).To(BeEmpty())
+		})
+
+		It("should reject isolated CPUs mixing efficiency and performance cores", func() {
+			efficiencyCores := performancev2.CPUSet("5-7")
+			profile.Spec.CPU.EfficiencyCores = &efficiencyCores
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Field).To(Equal("spec.cpu.isolated"))
+			Expect(errors[0].Error()).To(ContainSubstring("isolated CPUs mix the efficiency cores 5-6 with performance cores 4"))
+		})
+
+		It("should allow isolated CPUs mixing efficiency and performance cores when explicitly allowed", func() {
+			efficiencyCores := performancev2.CPUSet("5-7")
+			profile.Spec.CPU.EfficiencyCores = &efficiencyCores
+			profile.Spec.CPU.AllowMixedCoreTypes = pointer.Bool(true)
+			errors := validateCPUs(profile)
+			Expect(errors).To(BeEmpty())
+		})
+
+		It("should reject invalid efficiency cores", func() {
+			efficiencyCores := performancev2.CPUSet("efficiencyCores")
+			profile.Spec.CPU.EfficiencyCores = &efficiencyCores
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Field).To(Equal("spec.cpu.efficiencyCores"))
+		})
+
+		It("should reject the nosmt kernel argument when disabling SMT on the isolated CPUs only", func() {
+			smtPolicy := performancev2.SMTPolicyDisableIsolatedOnly
+			profile.Spec.CPU.SMTPolicy = &smtPolicy
+			profile.Spec.AdditionalKernelArgs = append(profile.Spec.AdditionalKernelArgs, "nosmt")
+			errors := validateCPUs(profile)
+			Expect(errors).NotTo(BeEmpty())
+			Expect(errors[0].Error()).To(ContainSubstring("conflicts with the disable-isolated-only SMT policy"))
+		})
+	})
+
+	Describe("CPU Frequency validation", func() {
+		It("should reject if isolated CPU frequency is declared, while reserved CPU frequency is empty", func() {
+			isolatedCpuFrequency := performancev2.CPUfrequency(2500000)
+			profile.Spec.HardwareTuning = &performancev2.HardwareTuning{
+				IsolatedCpuFreq: &isolatedCpuFrequency,
+			}
+
+			errors := validateCpuFrequency(profile)
+			Expect(errors[0].Error()).To(ContainSubstring("both isolated and reserved cpu frequency must be declared"))
+		})
+
+		It("should reject if reserved CPU frequency isdeclared, while isolated CPU frequency is empty", func() {
+			reservedCpuFrequency := performancev2.CPUfrequency(2800000)
+			profile.Spec.HardwareTuning = &performancev2.HardwareTuning{
+				ReservedCpuFreq: &reservedCpuFrequency,
+			}
+
+			errors := validateCpuFrequency(profile)
+			Expect(errors[0].Error()).To(ContainSubstring("both isolated and reserved cpu frequency must be declared"))
+		})
+
+		It("should have CPU frequency fields populated", func() {
+			isolatedCpuFrequency := performancev2.CPUfrequency(2500000)
+			reservedCpuFrequency := performancev2.CPUfrequency(2800000)
+			profile.Spec.HardwareTuning = &performancev2.HardwareTuning{
+				IsolatedCpuFreq: &isolatedCpuFrequency,
+				ReservedCpuFreq: &reservedCpuFrequency,
+			}
+
+			errors := validateCpuFrequency(profile)
+			Expect(errors).To(BeEmpty(), "should not have validation errors with populated CPU fields")
+		})
+
+		It("should reject invalid(0) frequency for isolated CPUs", func() {
+			isolatedCpuFrequency := performancev2.CPUfrequency(0)
+			reservedCpuFrequency := performancev2.CPUfrequency(2800000)
+			profile.Spec.HardwareTuning = &performancev2.HardwareTuning{
+				IsolatedCpuFreq: &isolatedCpuFrequency,
+				ReservedCpuFreq: &reservedCpuFrequency,
+			}
+			errors := validateCpuFrequency(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error when isolated CPU frequency has invalid format")
+			Expect(errors[0].Error()).To(ContainSubstring("isolated cpu frequency can not be equal to 0"))
+		})
+
+		It("should reject invalid(0) frequency for reserved CPUs", func() {
+			isolatedCpuFrequency := performancev2.CPUfrequency(2500000)
+			reservedCpuFrequency := performancev2.CPUfrequency(0)
+			profile.Spec.HardwareTuning = &performancev2.HardwareTuning{
+				IsolatedCpuFreq: &isolatedCpuFrequency,
+				ReservedCpuFreq: &reservedCpuFrequency,
+			}
+			errors := validateCpuFrequency(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error when reserved CPU frequency has invalid format")
+			Expect(errors[0].Error()).To(ContainSubstring("reserved cpu frequency can not be equal to 0"))
+		})
+
+	})
+
+	Describe("Label selectors validation", func() {
+		It("should have 0 or 1 MachineConfigLabels", func() {
+			errors := validateSelectors(profile)
+			Expect(errors).To(BeEmpty(), "should not have validation errors when the profile has only 1 MachineConfigSelector")
+
+			profile.Spec.MachineConfigLabel["foo"] = "bar"
+			errors = validateSelectors(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error when the profile has two machine config selectors")
+			Expect(errors[0].Error()).To(ContainSubstring("you should provide only 1 MachineConfigLabel"))
+
+			profile.Spec.MachineConfigLabel = nil
+			setValidNodeSelector(profile)
+
+			errors = validateSelectors(profile)
+			Expect(validateSelectors(profile)).To(BeEmpty(), "should not have validation errors when machine config selector nil")
+		})
+
+		It("should should have 0 or 1 MachineConfigPoolSelector labels", func() {
+			errors := validateSelectors(profile)
+			Expect(errors).To(BeEmpty(), "should not have validation errors when the profile has only 1 MachineConfigPoolSelector")
+
+			profile.Spec.MachineConfigPoolSelector["foo"] = "bar"
+			errors = validateSelectors(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error when the profile has two machine config pool selectors")
+			Expect(errors[0].Error()).To(ContainSubstring("you should provide only 1 MachineConfigPoolSelector"))
+
+			profile.Spec.MachineConfigPoolSelector = nil
+			setValidNodeSelector(profile)
+
+			errors = validateSelectors(profile)
+			Expect(validateSelectors(profile)).To(BeEmpty(), "should not have validation errors when machine config pool selector nil")
+		})
+
+		It("should accept only a positive MachineConfigPoolMaxUnavailable", func() {
+			for _, maxUnavailable := range []intstr.IntOrString{intstr.FromInt(3), intstr.FromString("25%")} {
+				profile.Spec.MachineConfigPoolMaxUnavailable = &maxUnavailable
+				Expect(validateSelectors(profile)).To(BeEmpty(), "should not have validation errors with maxUnavailable %s", maxUnavailable.String())
+			}
+
+			for _, maxUnavailable := range []intstr.IntOrString{intstr.FromInt(0), intstr.FromInt(-1), intstr.FromString("0%"), intstr.FromString("fast")} {
+				profile.Spec.MachineConfigPoolMaxUnavailable = &maxUnavailable
+				errors := validateSelectors(profile)
+				Expect(errors).NotTo(BeEmpty(), "should have validation error with maxUnavailable %s", maxUnavailable.String())
+				Expect(errors[0].Error()).To(ContainSubstring("should be a positive number or percentage"))
+			}
+		})
+
+		It("should validate the MachineConfigPoolMaintenanceWindow", func() {
+			profile.Spec.MachineConfigPoolMaintenanceWindow = &performancev2.MaintenanceWindow{
+				Schedule: "0 22 * * 6",
+				Duration: metav1.Duration{Duration: 4 * time.Hour},
+				TimeZone: "Europe/Prague",
+			}
+			Expect(validateSelectors(profile)).To(BeEmpty(), "should not have validation errors with a valid maintenance window")
+
+			for _, window := range []performancev2.MaintenanceWindow{
+				{Schedule: "0 22 * *", Duration: metav1.Duration{Duration: time.Hour}},
+				{Schedule: "60 22 * * 6", Duration: metav1.Duration{Duration: time.Hour}},
+				{Schedule: "0 22 30 2 *", Duration: metav1.Duration{Duration: time.Hour}},
+				{Schedule: "0 22 * * 6", Duration: metav1.Duration{Duration: time.Second}},
+				{Schedule: "0 22 * * 6", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus"},
+			} {
+				profile.Spec.MachineConfigPoolMaintenanceWindow = &window
+				errors := validateSelectors(profile)
+				Expect(errors).NotTo(BeEmpty(), "should have validation error with the maintenance window %s", window.String())
+				Expect(errors[0].Field).To(HavePrefix("spec.machineConfigPoolMaintenanceWindow"))
+			}
+		})
+
+		It("should have sensible NodeSelector in case MachineConfigLabel or MachineConfigPoolSelector is empty", func() {
+			profile.Spec.MachineConfigLabel = nil
+			errors := validateSelectors(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error with invalid NodeSelector")
+			Expect(errors[0].Error()).To(ContainSubstring("invalid NodeSelector label key that can't be split into domain/role"))
+
+			setValidNodeSelector(profile)
+			errors = validateSelectors(profile)
+			Expect(errors).To(BeEmpty(), "should not have validation errors when the node selector has correct format")
+		})
+	})
+
+	Describe("Annotations validation", func() {
+		It("should accept valid machine config annotations", func() {
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileMachineConfigNameSuffixAnnotation: "du-performance",
+				performancev2.PerformanceProfileMachineConfigLabelsAnnotation:     `{"ran.openshift.io/ztp-deploy-wave": "10"}`,
+			}
+			Expect(validateAnnotations(profile)).To(BeEmpty())
+		})
+
+		It("should reject invalid machine config name suffix", func() {
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileMachineConfigNameSuffixAnnotation: "Not_Valid",
+			}
+			errors := validateAnnotations(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error with invalid name suffix")
+		})
+
+		It("should reject malformed machine config labels", func() {
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileMachineConfigLabelsAnnotation: "foo=bar",
+			}
+			errors := validateAnnotations(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error with malformed labels")
+			Expect(errors[0].Error()).To(ContainSubstring("failed to parse machine config labels"))
+
+			profile.Annotations[performancev2.PerformanceProfileMachineConfigLabelsAnnotation] = `{"foo": "bar baz"}`
+			errors = validateAnnotations(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error with invalid label value")
+		})
+
+		It("should accept a valid latency probe", func() {
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileLatencyProbeAnnotation: `{"image": "quay.io/example/latency-tools:latest", "tool": "oslat", "duration": "30s", "cpus": 4}`,
+			}
+			Expect(validateAnnotations(profile)).To(BeEmpty())
+		})
+
+		It("should reject invalid latency probes", func() {
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileLatencyProbeAnnotation: `{"tool": "cyclictest"}`,
+			}
+			errors := validateAnnotations(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error without latency probe image")
+			Expect(errors[0].Error()).To(ContainSubstring("latency probe image is required"))
+
+			profile.Annotations[performancev2.PerformanceProfileLatencyProbeAnnotation] = `{"image": "quay.io/example/latency-tools:latest", "tool": "hwlatdetect"}`
+			errors = validateAnnotations(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error with unsupported latency probe tool")
+
+			profile.Annotations[performancev2.PerformanceProfileLatencyProbeAnnotation] = `{"image": "quay.io/example/latency-tools:latest", "duration": "100ms", "cpus": 1}`
+			errors = validateAnnotations(profile)
+			Expect(errors).To(HaveLen(2), "should have validation errors with too short duration and too few CPUs")
+		})
+
+		It("should validate the auto rollback annotation", func() {
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileAutoRollbackAnnotation: `{"degradedTunedProfiles": 2}`,
+			}
+			Expect(validateAnnotations(profile)).To(BeEmpty())
+
+			profile.Annotations[performancev2.PerformanceProfileAutoRollbackAnnotation] = `{"degradedTunedProfiles": 0}`
+			errors := validateAnnotations(profile)
+			Expect(errors).To(HaveLen(1), "should have validation error with zero degraded tuned profiles")
+			Expect(errors[0].Error()).To(ContainSubstring("should be at least one"))
+
+			profile.Annotations[performancev2.PerformanceProfileAutoRollbackAnnotation] = `true`
+			errors = validateAnnotations(profile)
+			Expect(errors).To(HaveLen(1), "should have validation error with a non JSON object annotation")
+			Expect(errors[0].Error()).To(ContainSubstring("failed to parse auto rollback"))
+		})
+
+		It("should validate the export artifacts annotation", func() {
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileExportArtifactsAnnotation: "true",
+			}
+			Expect(validateAnnotations(profile)).To(BeEmpty())
+
+			profile.Annotations[performancev2.PerformanceProfileExportArtifactsAnnotation] = "yes"
+			errors := validateAnnotations(profile)
+			Expect(errors).To(HaveLen(1), "should have validation error with a non boolean annotation")
+			Expect(errors[0].Error()).To(ContainSubstring("Unsupported value"))
+		})
+
+		It("should reject unsupported CPU partitioning modes", func() {
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileCPUPartitioningModeAnnotation: string(apiconfigv1.CPUPartitioningAllNodes),
+			}
+			Expect(validateAnnotations(profile)).To(BeEmpty())
+
+			profile.Annotations[performancev2.PerformanceProfileCPUPartitioningModeAnnotation] = "ControlPlane"
+			Expect(validateAnnotations(profile)).To(HaveLen(1), "should have validation error with an unsupported CPU partitioning mode")
+		})
+	})
+
+	Describe("CPU partitioning validation", func() {
+		It("should accept profiles without CPU partitioning expectations", func() {
+			Expect(ValidateCPUPartitioning(profile, apiconfigv1.CPUPartitioningAllNodes)).To(BeEmpty())
+			Expect(ValidateCPUPartitioning(profile, "")).To(BeEmpty())
+		})
+
+		It("should reject profiles expecting another CPU partitioning mode", func() {
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileCPUPartitioningModeAnnotation: string(apiconfigv1.CPUPartitioningAllNodes),
+			}
+			Expect(ValidateCPUPartitioning(profile, apiconfigv1.CPUPartitioningAllNodes)).To(BeEmpty())
+
+			errors := ValidateCPUPartitioning(profile, "")
+			Expect(errors).To(HaveLen(1), "should have validation error on a cluster without CPU partitioning")
+			Expect(errors[0].Error()).To(ContainSubstring("the cluster CPU partitioning mode is None"))
+		})
+
+		It("should require the reserved CPUs on CPU partitioned clusters", func() {
+			profile.Spec.CPU.Reserved = nil
+			Expect(ValidateCPUPartitioning(profile, apiconfigv1.CPUPartitioningNone)).To(BeEmpty())
+			Expect(ValidateCPUPartitioning(profile, apiconfigv1.CPUPartitioningAllNodes)).To(HaveLen(1))
+		})
+
+		It("should validate the CPU sets of the profiles without CPU partitioning expectations on CPU partitioned clusters", func() {
+			isolated := performancev2.CPUSet("0-6")
+			profile.Spec.CPU.Isolated = &isolated
+			Expect(ValidateCPUPartitioning(profile, apiconfigv1.CPUPartitioningNone)).To(BeEmpty())
+
+			errors := ValidateCPUPartitioning(profile, apiconfigv1.CPUPartitioningAllNodes)
+			Expect(errors).ToNot(BeEmpty(), "should have validation error with the reserved and isolated CPUs overlapping")
+			Expect(errors[0].Error()).To(ContainSubstring("cpus overlap"))
+		})
+	})
+
+	Describe("Cluster state validation", func() {
+		It("should validate the profile against the other profiles", func() {
+			other := NewPerformanceProfile("other")
+			warnings, errors := Validate(profile, ClusterState{Profiles: []performancev2.PerformanceProfile{*profile, *other}})
+			Expect(warnings).To(BeNil())
+			Expect(errors).To(HaveLen(1), "should have validation error with the node selector of another profile")
+			Expect(errors[0].Error()).To(ContainSubstring(`the same node selector as the performance profile "other"`))
+		})
+
+		It("should validate the profile against the known CPU partitioning mode only", func() {
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileCPUPartitioningModeAnnotation: string(apiconfigv1.CPUPartitioningAllNodes),
+			}
+			_, errors := Validate(profile, ClusterState{})
+			Expect(errors).To(BeEmpty())
+
+			mode := apiconfigv1.CPUPartitioningNone
+			warnings, errors := Validate(profile, ClusterState{CPUPartitioning: &mode})
+			Expect(warnings).To(BeNil())
+			Expect(errors).To(HaveLen(1), "should have validation error on a cluster without CPU partitioning")
+		})
+
+		It("should not check the CPU partitioning of invalid profiles", func() {
+			profile.Spec.CPU.Reserved = nil
+			mode := apiconfigv1.CPUPartitioningAllNodes
+			_, errors := Validate(profile, ClusterState{CPUPartitioning: &mode})
+			var reservedErrors field.ErrorList
+			for _, err := range errors {
+				if err.Field == "spec.cpu.reserved" {
+					reservedErrors = append(reservedErrors, err)
+				}
+			}
+			Expect(reservedErrors).To(HaveLen(1), "should report the missing reserved CPUs once")
+		})
+	})
+
+	Describe("Disabled generated artifacts validation", func() {
+		It("should allow the supported artifacts", func() {
+			profile.Spec.DisableGenerated = []performancev2.GeneratedArtifact{performancev2.GeneratedArtifactRuntimeClass, performancev2.GeneratedArtifactRPSConfig}
+			Expect(validateDisableGenerated(profile)).To(BeEmpty())
+		})
+
+		It("should reject the unsupported and the duplicated artifacts", func() {
+			profile.Spec.DisableGenerated = []performancev2.GeneratedArtifact{performancev2.GeneratedArtifactRuntimeClass, "kubeletConfig", performancev2.GeneratedArtifactRuntimeClass}
+
+			errors := validateDisableGenerated(profile)
+			Expect(errors).To(HaveLen(2))
+			Expect(errors[0].Field).To(Equal("spec.disableGenerated[1]"))
+			Expect(errors[1].Error()).To(ContainSubstring("Duplicate value"))
+		})
+	})
+
+	Describe("Hugepages validation", func() {
+		It("should reject on incorrect default hugepages size", func() {
+			incorrectDefaultSize := performancev2.HugePageSize("!#@")
+			profile.Spec.HugePages.DefaultHugePagesSize = &incorrectDefaultSize
+
+			errors := validateHugePages(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error when default huge pages size has invalid value")
+			Expect(errors[0].Error()).To(ContainSubstring("hugepages default size should be equal"))
+		})
+
+		It("should reject hugepages allocation with unexpected page size", func() {
+			profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, performancev2.HugePage{
+				Count: 128,
+				Node:  pointer.Int32(0),
+				Size:  "14M",
+			})
+			errors := validateHugePages(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error when page with invalid format presents")
+			Expect(errors[0].Error()).To(ContainSubstring(fmt.Sprintf("the page size should be equal to %q or %q", hugepagesSize1G, hugepagesSize2M)))
+		})
+
+		It("should reject the runtime allocation of 1G pages", func() {
+			runtimeAllocation := performancev2.HugePageAllocationRuntime
+			profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, performancev2.HugePage{
+				Count:      4,
+				Node:       pointer.Int32(1),
+				Size:       hugepagesSize1G,
+				Allocation: &runtimeAllocation,
+			})
+			errors := validateHugePages(profile)
+			Expect(errors).NotTo(BeEmpty(), "should have validation error when 1G pages are allocated at runtime")
+			Expect(errors[0].Error()).To(ContainSubstring(fmt.Sprintf("only the pages with the size %q can be allocated at runtime", hugepagesSize2M)))
+		})
+
+		It("should allow the runtime allocation of 2M pages", func() {
+			runtimeAllocation := performancev2.HugePageAllocationRuntime
+			profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, performancev2.HugePage{
+				Count:      128,
+				Node:       pointer.Int32(1),
+				Size:       hugepagesSize2M,
+				Allocation: &runtimeAllocation,
+			})
+			Expect(validateHugePages(profile)).To(BeEmpty())
+		})
+
+		It("should allow the runtime allocation of a memory percentage without the node memory size", func() {
+			runtimeAllocation := performancev2.HugePageAllocationRuntime
+			profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, performancev2.HugePage{
+				MemoryPercent: pointer.Int32(10),
+				Size:          hugepagesSize2M,
+				Allocation:    &runtimeAllocation,
+			})
+			Expect(validateHugePages(profile)).To(BeEmpty())
+		})
+
+		It("should reject the boot allocation of a memory percentage without the node memory size", func() {
+			profile.Spec.HugePages.Pages[0].Count = 0
+			profile.Spec.HugePages.Pages[0].MemoryPercent = pointer.Int32(10)
+
+			errors := validateHugePages(profile)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Field).To(Equal("spec.hugepages.nodeMemorySize"))
+
+			nodeMemorySize := resource.MustParse("64Gi")
+			profile.Spec.HugePages.NodeMemorySize = &nodeMemorySize
+			Expect(validateHugePages(profile)).To(BeEmpty())
+		})
+
+		It("should reject the pages with both the count and the memory percentage", func() {
+			runtimeAllocation := performancev2.HugePageAllocationRuntime
+			profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, performancev2.HugePage{
+				Count:         128,
+				MemoryPercent: pointer.Int32(110),
+				Size:          hugepagesSize2M,
+				Allocation:    &runtimeAllocation,
+			})
+
+			errors := validateHugePages(profile)
+			Expect(errors).To(HaveLen(2))
+			Expect(errors[0].Error()).To(ContainSubstring("mutually exclusive"))
+			Expect(errors[1].Error()).To(ContainSubstring("should be between 1 and 100"))
+		})
+
+		When("pages have duplication", func() {
+			Context("with specified NUMA node", func() {
+				It("should raise the validation error", func() {
+					profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, performancev2.HugePage{
+						Count: 128,
+						Size:  hugepagesSize1G,
+						Node:  pointer.Int32(0),
+					})
+					profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, performancev2.HugePage{
+						Count: 64,
+						Size:  hugepagesSize1G,
+						Node:  pointer.Int32(0),
+					})
+					errors := validateHugePages(profile)
+					Expect(errors).NotTo(BeEmpty())
+					Expect(errors[0].Error()).To(ContainSubstring(fmt.Sprintf("the page with the size %q and with specified NUMA node 0, has duplication", hugepagesSize1G)))
+				})
+			})
+
+			Context("without specified NUMA node", func() {
+				It("should raise the validation error", func() {
+					profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, performancev2.HugePage{
+						Count: 128,
+						Size:  hugepagesSize1G,
+					})
+					errors := validateHugePages(profile)
+					Expect(errors).NotTo(BeEmpty())
+					Expect(errors[0].Error()).To(ContainSubstring(fmt.Sprintf("the page with the size %q and without the specified NUMA node, has duplication", hugepagesSize1G)))
+				})
+			})
+
+			Context("with not sequentially duplication blocks", func() {
+				It("should raise the validation error", func() {
+					profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, performancev2.HugePage{
+						Count: 128,
+						Size:  hugepagesSize2M,
+					})
+					profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, performancev2.HugePage{
+						Count: 128,
+						Size:  hugepagesSize1G,
+					})
+					errors := validateHugePages(profile)
+					Expect(errors).NotTo(BeEmpty())
+					Expect(errors[0].Error()).To(ContainSubstring(fmt.Sprintf("the page with the size %q and without the specified NUMA node, has duplication", hugepagesSize1G)))
+				})
+			})
+		})
+	})
+
+	Describe("Net validation", func() {
+		Context("with properly populated fields", func() {
+			It("should have net fields properly populated", func() {
+				errors := validateNet(profile)
+				Expect(errors).To(BeEmpty(), "should not have validation errors with properly populated net devices fields")
+			})
+		})
+		Context("with misconfigured fields", func() {
+			It("should raise the validation syntax errors", func() {
+				invalidVendor := "123"
+				invalidDevice := "0x12345"
+				profile.Spec.Net.Devices[0].InterfaceName = pointer.String("")
+				profile.Spec.Net.Devices[0].VendorID = pointer.String(invalidVendor)
+				profile.Spec.Net.Devices[0].DeviceID = pointer.String(invalidDevice)
+				errors := validateNet(profile)
+				Expect(len(errors)).To(Equal(3))
+				Expect(errors[0].Error()).To(ContainSubstring(fmt.Sprintf("device name cannot be empty")))
+				Expect(errors[1].Error()).To(ContainSubstring(fmt.Sprintf("device vendor ID %s has an invalid format. Vendor ID should be represented as 0x<4 hexadecimal digits> (16 bit representation)", invalidVendor)))
+				Expect(errors[2].Error()).To(ContainSubstring(fmt.Sprintf("device model ID %s has an invalid format. Model ID should be represented as 0x<4 hexadecimal digits> (16 bit representation)", invalidDevice)))
+
+			})
+			It("should raise the validation errors for missing fields", func() {
+				profile.Spec.Net.Devices[0].VendorID = nil
+				profile.Spec.Net.Devices[0].DeviceID = pointer.String("0x1")
+				errors := validateNet(profile)
+				Expect(errors).NotTo(BeEmpty())
+				Expect(errors[0].Error()).To(ContainSubstring(fmt.Sprintf("device model ID can not be used without specifying the device vendor ID.")))
+			})
+		})
+		Context("with packet steering", func() {
+			It("should allow steering the packets to the reserved CPUs", func() {
+				profile.Spec.Net.PacketSteering = []performancev2.PacketSteering{
+					{Device: performancev2.Device{InterfaceName: pointer.String("ens*")}},
+				}
+				Expect(validateNet(profile)).To(BeEmpty())
+			})
+
+			It("should reject the devices without matchers", func() {
+				profile.Spec.Net.PacketSteering = []performancev2.PacketSteering{{}}
+				errors := validateNet(profile)
+				Expect(errors).To(HaveLen(1))
+				Expect(errors[0].Error()).To(ContainSubstring("the device should specify the interface name or the vendor ID"))
+			})
+
+			It("should reject disabling both the receive and the transmit packet steering", func() {
+				profile.Spec.Net.PacketSteering = []performancev2.PacketSteering{
+					{Device: performancev2.Device{InterfaceName: pointer.String("ens*")}, RPS: pointer.Bool(false), XPS: pointer.Bool(false)},
+				}
+				errors := validateNet(profile)
+				Expect(errors).To(HaveLen(1))
+				Expect(errors[0].Error()).To(ContainSubstring("at least one of the receive or the transmit packet steering should be enabled"))
+			})
+
+			It("should reject invalid or offlined CPUs", func() {
+				invalid := performancev2.CPUSet("a-b")
+				offlined := performancev2.CPUSet("6")
+				profile.Spec.CPU.Offlined = &offlined
+				profile.Spec.Net.PacketSteering = []performancev2.PacketSteering{
+					{Device: performancev2.Device{InterfaceName: pointer.String("ens1")}, CPUs: &invalid},
+					{Device: performancev2.Device{InterfaceName: pointer.String("ens2")}, CPUs: &offlined},
+				}
+				errors := validateNet(profile)
+				Expect(errors).To(HaveLen(2))
+				Expect(errors[0].Field).To(Equal("spec.net.packetSteering[0].cpus"))
+				Expect(errors[1].Error()).To(ContainSubstring("packet steering CPUs can not include offlined CPUs"))
+			})
+		})
+
+		Describe("Network stack validation", func() {
+			It("should allow the sysctls matching the network stack", func() {
+				profile.Spec.RealTimeKernel = &performancev2.RealTimeKernel{Enabled: pointer.Bool(false)}
+				profile.Spec.NetworkStack = &performancev2.NetworkStack{
+					Mode: performancev2.NetworkStackDualStack,
+					Sysctls: map[string]string{
+						"net.ipv6.neigh.default.gc_thresh3": "32768",
+						"net.core.busy_poll":                "100",
+					},
+				}
+				Expect(validateNetworkStack(profile)).To(BeEmpty())
+			})
+
+			It("should reject an unknown network stack", func() {
+				profile.Spec.NetworkStack = &performancev2.NetworkStack{Mode: "ipv6-only"}
+				errors := validateNetworkStack(profile)
+				Expect(errors).To(HaveLen(1))
+				Expect(errors[0].Field).To(Equal("spec.networkStack.mode"))
+			})
+
+			It("should reject the sysctls not matching the network stack", func() {
+				profile.Spec.NetworkStack = &performancev2.NetworkStack{
+					Mode: performancev2.NetworkStackIPv4Only,
+					Sysctls: map[string]string{
+						"kernel.numa_balancing":             "0",
+						"net.core.rps_default_mask":         "1",
+						"net.ipv6.neigh.default.gc_thresh3": "32768",
+					},
+				}
+				errors := validateNetworkStack(profile)
+				Expect(errors).To(HaveLen(3))
+				Expect(errors[0].Error()).To(ContainSubstring("only the net.* sysctls are allowed"))
+				Expect(errors[1].Error()).To(ContainSubstring("the RPS mask is generated for the profile"))
+				Expect(errors[2].Error()).To(ContainSubstring("the IPv6 sysctls can not be set on the nodes of the ipv4-only network stack"))
+			})
+
+			It("should reject disabling IPv6 on the dual-stack nodes", func() {
+				profile.Spec.NetworkStack = &performancev2.NetworkStack{
+					Mode:    performancev2.NetworkStackIPv6Primary,
+					Sysctls: map[string]string{"net.ipv6.conf.all.disable_ipv6": "1"},
+				}
+				errors := validateNetworkStack(profile)
+				Expect(errors).To(HaveLen(1))
+				Expect(errors[0].Error()).To(ContainSubstring("IPv6 can not be disabled on the nodes of the ipv6-primary network stack"))
+			})
+
+			It("should reject busy polling with the real time kernel", func() {
+				profile.Spec.RealTimeKernel = &performancev2.RealTimeKernel{Enabled: pointer.Bool(true)}
+				profile.Spec.NetworkStack = &performancev2.NetworkStack{
+					Mode:    performancev2.NetworkStackIPv4Only,
+					Sysctls: map[string]string{"net.core.busy_read": "50"},
+				}
+				errors := validateNetworkStack(profile)
+				Expect(errors).To(HaveLen(1))
+				Expect(errors[0].Error()).To(ContainSubstring("the real time kernel does not support busy polling"))
+			})
+
+			It("should reject invalid sysctl values", func() {
+				profile.Spec.NetworkStack = &performancev2.NetworkStack{
+					Mode:    performancev2.NetworkStackIPv4Only,
+					Sysctls: map[string]string{"net.ipv4.tcp_fastopen": "3\nkernel.panic=1"},
+				}
+				errors := validateNetworkStack(profile)
+				Expect(errors).To(HaveLen(1))
+				Expect(errors[0].Field).To(Equal("spec.networkStack.sysctls[net.ipv4.tcp_fastopen]"))
+			})
+		})
+
+		Describe("Workload hints validation", func() {
+			When("realtime kernel is enabled and realtime workload hint is explicitly disabled", func() {
+				It("should raise validation error", func() {
+					profile.Spec.WorkloadHints = &performancev2.WorkloadHints{
+						RealTime: pointer.Bool(false),
+					}
+					profile.Spec.RealTimeKernel = &performancev2.RealTimeKernel{
+						Enabled: pointer.Bool(true),
+					}
+					errors := validateWorkloadHints(profile)
+					Expect(errors).NotTo(BeEmpty())
+					Expect(errors[0].Error()).To(ContainSubstring("realtime kernel is enabled, but realtime workload hint is explicitly disable"))
+				})
+			})
+			When("HighPowerConsumption hint is enabled and PerPodPowerManagement hint is enabled", func() {
+				It("should raise validation error", func() {
+					profile.Spec.WorkloadHints = &performancev2.WorkloadHints{
+						HighPowerConsumption:  pointer.Bool(true),
+						PerPodPowerManagement: pointer.Bool(true),
+					}
+					errors := validateWorkloadHints(profile)
+					Expect(errors).NotTo(BeEmpty())
+					Expect(errors[0].Error()).To(ContainSubstring("Invalid WorkloadHints configuration: HighPowerConsumption and PerPodPowerManagement can not be both enabled"))
+				})
+			})
+			When("MixedCPUs hint is enabled but no shared CPUs are specified", func() {
+				It("should raise validation error", func() {
+					profile.Spec.WorkloadHints = &performancev2.WorkloadHints{
+						MixedCpus: pointer.Bool(true),
+					}
+					errors := validateWorkloadHints(profile)
+					Expect(errors).NotTo(BeEmpty())
+					Expect(errors[0].Error()).To(ContainSubstring("Invalid WorkloadHints configuration: MixedCpus enabled but no shared CPUs were specified"))
+				})
+			})
+
+		})
+	})
+
+	Describe("NUMA validation", func() {
+		It("should accept the automatic NUMA balancing without the real time kernel", func() {
+			profile.Spec.RealTimeKernel.Enabled = pointer.Bool(false)
+			profile.Spec.NUMA.AutomaticBalancing = pointer.Bool(true)
+			Expect(validateNUMA(profile)).To(BeEmpty())
+		})
+
+		It("should reject the automatic NUMA balancing together with the real time kernel", func() {
+			profile.Spec.NUMA.AutomaticBalancing = pointer.Bool(true)
+			errors := validateNUMA(profile)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Error()).To(ContainSubstring("automatic NUMA balancing can not be enabled together with the real time kernel"))
+		})
+
+		It("should accept the topology manager scope and policy options", func() {
+			profile.Spec.NUMA.TopologyManagerScope = pointer.String("pod")
+			profile.Spec.NUMA.TopologyPolicyOptions = map[string]string{
+				"prefer-closest-numa-nodes": "true",
+				"max-allowable-numa-nodes":  "16",
+			}
+			Expect(validateNUMA(profile)).To(BeEmpty())
+		})
+
+		It("should reject invalid topology manager scope and policy options", func() {
+			profile.Spec.NUMA.TopologyManagerScope = pointer.String("node")
+			profile.Spec.NUMA.TopologyPolicyOptions = map[string]string{
+				"max-allowable-numa-nodes":  "4",
+				"prefer-closest-numa-nodes": "yes",
+				"unknown-option":            "true",
+			}
+			errors := validateNUMA(profile)
+			Expect(errors).To(HaveLen(4))
+			Expect(errors[0].Field).To(Equal("spec.numa.topologyManagerScope"))
+			Expect(errors[1].Error()).To(ContainSubstring("the option value should be an integer not lower than 8"))
+			Expect(errors[2].Error()).To(ContainSubstring("the option value should be a boolean"))
+			Expect(errors[3].Error()).To(ContainSubstring("unknown-option"))
+		})
+
+		It("should reject the topology policy options with the none topology policy", func() {
+			profile.Spec.NUMA.TopologyPolicy = pointer.String("none")
+			profile.Spec.NUMA.TopologyPolicyOptions = map[string]string{"prefer-closest-numa-nodes": "true"}
+			errors := validateNUMA(profile)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Error()).To(ContainSubstring("the options can not be set with the none topology policy"))
+		})
+
+		It("should reject the topology policy options not supported by the kubelet of the nodes", func() {
+			profile.Spec.NUMA.TopologyPolicyOptions = map[string]string{
+				"prefer-closest-numa-nodes": "true",
+				"max-allowable-numa-nodes":  "16",
+			}
+			nodes := []corev1.Node{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+					Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.29.5+4ad0f3f"}},
+				},
+			}
+			errors := validateTopologyPolicyOptionsSupport(profile, nodes)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Field).To(Equal("spec.numa.topologyPolicyOptions[max-allowable-numa-nodes]"))
+			Expect(errors[0].Error()).To(ContainSubstring(`the option requires kubelet 1.31.0 or newer, the node "node-1" runs kubelet v1.29.5+4ad0f3f`))
+		})
+	})
+
+	Describe("Kernel modules validation", func() {
+		It("should accept valid kernel modules", func() {
+			profile.Spec.KernelModules = []performancev2.KernelModule{
+				{Name: "vfio-pci", Options: []string{"ids=8086:154c"}},
+				{Name: "sctp", Blacklist: pointer.Bool(true)},
+			}
+			Expect(validateKernelModules(profile)).To(BeEmpty())
+		})
+
+		It("should reject invalid names and options", func() {
+			profile.Spec.KernelModules = []performancev2.KernelModule{
+				{Name: "vfio pci"},
+				{Name: "nvme", Options: []string{"poll_queues=4 write_queues=4"}},
+			}
+			errors := validateKernelModules(profile)
+			Expect(errors).To(HaveLen(2))
+			Expect(errors[0].Error()).To(ContainSubstring("kernel module name should consist of alphanumeric characters"))
+			Expect(errors[1].Error()).To(ContainSubstring("kernel module option should be a non-empty parameter"))
+		})
+
+		It("should reject duplicated modules", func() {
+			profile.Spec.KernelModules = []performancev2.KernelModule{
+				{Name: "vfio-pci", Options: []string{"ids=8086:154c"}},
+				{Name: "vfio_pci", Blacklist: pointer.Bool(true)},
+			}
+			errors := validateKernelModules(profile)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Error()).To(ContainSubstring("Duplicate value"))
+		})
+	})
+
+	Describe("Devices validation", func() {
+		It("should accept valid driver bindings", func() {
+			profile.Spec.Devices = &performancev2.Devices{
+				DriverBindings: []performancev2.DeviceDriverBinding{
+					{PCIAddress: "0000:3b:00.0", Driver: performancev2.DeviceDriverVFIOPCI},
+					{PCIAddress: "0000:3b:1f.7", Driver: performancev2.DeviceDriverIgbUIO},
+				},
+			}
+			Expect(validateDevices(profile)).To(BeEmpty())
+		})
+
+		It("should reject invalid PCI addresses and drivers", func() {
+			profile.Spec.Devices = &performancev2.Devices{
+				DriverBindings: []performancev2.DeviceDriverBinding{
+					{PCIAddress: "3b:00.0", Driver: performancev2.DeviceDriverVFIOPCI},
+					{PCIAddress: "0000:3b:00.1", Driver: "uio_pci_generic"},
+				},
+			}
+			errors := validateDevices(profile)
+			Expect(errors).To(HaveLen(2))
+			Expect(errors[0].Error()).To(ContainSubstring("PCI address should be in the domain:bus:device.function format"))
+			Expect(errors[1].Error()).To(ContainSubstring("Unsupported value"))
+		})
+
+		It("should reject duplicated devices", func() {
+			profile.Spec.Devices = &performancev2.Devices{
+				DriverBindings: []performancev2.DeviceDriverBinding{
+					{PCIAddress: "0000:3b:0a.0", Driver: performancev2.DeviceDriverVFIOPCI},
+					{PCIAddress: "0000:3B:0A.0", Driver: performancev2.DeviceDriverIgbUIO},
+				},
+			}
+			errors := validateDevices(profile)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Error()).To(ContainSubstring("Duplicate value"))
+		})
+	})
+
+	Describe("Udev rules validation", func() {
+		It("should accept valid udev rules", func() {
+			profile.Spec.UdevRules = []performancev2.UdevRule{
+				{
+					Name: "70-nic-names",
+					Rules: `# rename the fronthaul NIC
+SUBSYSTEM=="net", ACTION=="add", ATTR{address}=="b4:96:91:aa:bb:cc", NAME="fh0"
+SUBSYSTEM=="net", ACTION=="add", KERNEL=="ens*", \
+  RUN+="/usr/sbin/ethtool -L $name combined 4"
+`,
+				},
+			}
+			Expect(validateUdevRules(profile)).To(BeEmpty())
+		})
+
+		It("should reject invalid names", func() {
+			profile.Spec.UdevRules = []performancev2.UdevRule{
+				{Name: "70-nic-names.rules", Rules: `SUBSYSTEM=="net", NAME="fh0"`},
+				{Name: "99-netdev-packet-steering", Rules: `SUBSYSTEM=="net", NAME="fh0"`},
+				{Name: "99-netdev-packet-steering", Rules: `SUBSYSTEM=="net", NAME="fh0"`},
+			}
+			errors := validateUdevRules(profile)
+			Expect(errors).To(HaveLen(4))
+			Expect(errors[0].Error()).To(ContainSubstring("udev rules name should consist of alphanumeric characters"))
+			Expect(errors[1].Error()).To(ContainSubstring("are generated for the profile"))
+			Expect(errors[3].Error()).To(ContainSubstring("Duplicate value"))
+		})
+
+		It("should reject invalid rules syntax", func() {
+			for rules, message := range map[string]string{
+				"# nothing":                                "should define at least one rule",
+				`SUBSYSTEM=="net", FOO="bar"`:              `unknown key "FOO"`,
+				`SUBSYSTEM=="net", ATTR=="1"`:              `the key "ATTR" requires an {attribute}`,
+				`SUBSYSTEM=="net", NAME=fh0`:               `the value of the key "NAME" should be double quoted`,
+				`SUBSYSTEM=="net", NAME="fh0`:              `the value of the key "NAME" is not terminated`,
+				`SUBSYSTEM=="net" NAME~"fh0"`:              `expected a KEY=="value" or KEY="value" pair at "NAME~\"fh0\""`,
+				"SUBSYSTEM==\"net\", \\":                   "udev rule continued past the last line",
+				"# rename\nSUBSYSTEM==\"net\", NAME=\"fh0": "udev rule at line 2 is invalid",
+			} {
+				profile.Spec.UdevRules = []performancev2.UdevRule{{Name: "70-nic-names", Rules: rules}}
+				errors := validateUdevRules(profile)
+				Expect(errors).To(HaveLen(1), rules)
+				Expect(errors[0].Error()).To(ContainSubstring(message), rules)
+			}
+		})
+	})
+
+	Describe("Kubelet config overrides validation", func() {
+		It("should accept a valid KubeletConfiguration snippet", func() {
+			profile.Spec.KubeletConfigOverrides = &runtime.RawExtension{Raw: []byte(`{"maxPods": 250, "allowedUnsafeSysctls": ["net.core.somaxconn"]}`)}
+			Expect(validateKubeletConfigOverrides(profile)).To(BeEmpty())
+		})
+
+		It("should reject the unknown fields and the fields computed out of the profile", func() {
+			profile.Spec.KubeletConfigOverrides = &runtime.RawExtension{Raw: []byte(`{"maxPodz": 250, "reservedSystemCPUs": "0-1"}`)}
+			errors := validateKubeletConfigOverrides(profile)
+			Expect(errors).To(HaveLen(2))
+			Expect(errors[0].Error()).To(ContainSubstring(`unknown field "maxPodz"`))
+			Expect(errors[1].Error()).To(ContainSubstring("spec.kubeletConfigOverrides.reservedSystemCPUs: Forbidden"))
+		})
+
+		It("should reject a snippet which is not an object", func() {
+			profile.Spec.KubeletConfigOverrides = &runtime.RawExtension{Raw: []byte(`["maxPods"]`)}
+			errors := validateKubeletConfigOverrides(profile)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Error()).To(ContainSubstring("kubelet config overrides should be a KubeletConfiguration object"))
+		})
+	})
+
+	Describe("Systemd validation", func() {
+		It("should accept valid slices", func() {
+			allowedCPUs := performancev2.CPUSet("0-1")
+			profile.Spec.Systemd = &performancev2.Systemd{
+				Slices: []performancev2.SystemdSlice{
+					{Name: "system.slice", CPUWeight: pointer.Int32(50), AllowedCPUs: &allowedCPUs},
+					{Name: "ovs.slice", CPUAccounting: pointer.Bool(true)},
+				},
+			}
+			Expect(validateSystemd(profile)).To(BeEmpty())
+		})
+
+		It("should reject invalid slices", func() {
+			allowedCPUs := performancev2.CPUSet("a-b")
+			profile.Spec.Systemd = &performancev2.Systemd{
+				Slices: []performancev2.SystemdSlice{
+					{Name: "system.service"},
+					{Name: "kubepods-burstable.slice"},
+					{Name: "ovs.slice", CPUWeight: pointer.Int32(0), AllowedCPUs: &allowedCPUs},
+				},
+			}
+			errors := validateSystemd(profile)
+			Expect(errors).To(HaveLen(4))
+			Expect(errors[0].Error()).To(ContainSubstring("slice name should be a valid systemd unit name"))
+			Expect(errors[1].Error()).To(ContainSubstring("the kubepods slices are managed by the kubelet"))
+			Expect(errors[2].Error()).To(ContainSubstring("CPU weight should be between 1 and 10000"))
+			Expect(errors[3].Field).To(Equal("spec.systemd.slices[2].allowedCPUs"))
+		})
+
+		It("should accept valid tuning units ordering", func() {
+			profile.Spec.Systemd = &performancev2.Systemd{
+				Units: []performancev2.SystemdUnitOrdering{
+					{Unit: performancev2.TuningUnitHugepagesAllocation, Before: []string{"ovs-vswitchd.service"}},
+					{Unit: performancev2.TuningUnitSetCPUsOffline, After: []string{"vendor-driver@eth0.service", "network-pre.target"}},
+				},
+			}
+			Expect(validateSystemd(profile)).To(BeEmpty())
+		})
+
+		It("should reject invalid tuning units ordering", func() {
+			profile.Spec.Systemd = &performancev2.Systemd{
+				Units: []performancev2.SystemdUnitOrdering{
+					{Unit: "unknown"},
+					{Unit: performancev2.TuningUnitSetCPUsOffline, Before: []string{"ovs-vswitchd"}},
+					{Unit: performancev2.TuningUnitSetCPUsOffline, Before: []string{"crio.service"}, After: []string{"kubelet.service", "crio.service"}},
+				},
+			}
+			errors := validateSystemd(profile)
+			Expect(errors).To(HaveLen(5))
+			Expect(errors[0].Field).To(Equal("spec.systemd.units[0].unit"))
+			Expect(errors[1].Error()).To(ContainSubstring("unit name should be a valid systemd unit name"))
+			Expect(errors[2].Error()).To(ContainSubstring("Duplicate value"))
+			Expect(errors[3].Error()).To(ContainSubstring("the tuning units start before kubelet.service"))
+			Expect(errors[4].Error()).To(ContainSubstring("can not start both before and after the same unit"))
+		})
+	})
+
+	Describe("Time synchronization validation", func() {
+		It("should accept the reserved CPUs", func() {
+			cpus := performancev2.CPUSet("0-1")
+			profile.Spec.TimeSync = &performancev2.TimeSync{
+				CPUs:     &cpus,
+				Services: []performancev2.TimeSyncService{performancev2.TimeSyncServicePTP4L, performancev2.TimeSyncServicePHC2SYS},
+			}
+			Expect(validateTimeSync(profile)).To(BeEmpty())
+		})
+
+		It("should reject the CPUs out of the reserved CPUs", func() {
+			cpus := performancev2.CPUSet("3-4")
+			profile.Spec.TimeSync = &performancev2.TimeSync{CPUs: &cpus}
+			errors := validateTimeSync(profile)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Error()).To(ContainSubstring("time synchronization CPUs should be a subset of the reserved CPUs"))
+		})
+
+		It("should reject invalid services", func() {
+			cpus := performancev2.CPUSet("0")
+			profile.Spec.TimeSync = &performancev2.TimeSync{
+				CPUs:     &cpus,
+				Services: []performancev2.TimeSyncService{"ntpd", performancev2.TimeSyncServiceChronyd, performancev2.TimeSyncServiceChronyd},
+			}
+			errors := validateTimeSync(profile)
+			Expect(errors).To(HaveLen(2))
+			Expect(errors[0].Field).To(Equal("spec.timeSync.services[0]"))
+			Expect(errors[1].Error()).To(ContainSubstring("Duplicate value"))
+		})
+
+		It("should reject unknown external services", func() {
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileTimeSyncExternalServicesAnnotation: "ptp4l,ts2phc",
+			}
+			errors := validateAnnotations(profile)
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Error()).To(ContainSubstring("ts2phc"))
+		})
+	})
+
+	Describe("Scheduler validation", func() {
+		It("should accept the realtime runtime bounds", func() {
+			for _, rtRuntimeUs := range []int32{-1, 1, 950000, 1000000} {
+				profile.Spec.Scheduler = &performancev2.Scheduler{RTRuntimeUs: pointer.Int32(rtRuntimeUs)}
+				Expect(validateScheduler(profile)).To(BeEmpty(), "should accept the realtime runtime %d", rtRuntimeUs)
+			}
+		})
+
+		It("should reject the realtime runtime out of bounds", func() {
+			for _, rtRuntimeUs := range []int32{-2, 0, 1000001} {
+				profile.Spec.Scheduler = &performancev2.Scheduler{RTRuntimeUs: pointer.Int32(rtRuntimeUs)}
+				errors := validateScheduler(profile)
+				Expect(errors).To(HaveLen(1), "should reject the realtime runtime %d", rtRuntimeUs)
+				Expect(errors[0].Error()).To(ContainSubstring("realtime runtime should be -1 or between 1 and 1000000 microseconds"))
+			}
+		})
+	})
+
+	Describe("Runtimes validation", func() {
+		It("should accept valid runtime handlers", func() {
+			profile.Spec.Runtimes = []performancev2.RuntimeHandler{
+				{Name: "high-performance-crun", RuntimePath: pointer.String("/usr/bin/crun"), RuntimeRoot: pointer.String("/run/crun")},
+				{Name: "low-latency", AllowedAnnotations: []string{"cpu-quota.crio.io", "io.kubernetes.cri-o.Devices"}},
+			}
+			Expect(validateRuntimes(profile)).To(BeEmpty())
+		})
+
+		It("should reject invalid runtime handlers", func() {
+			profile.Spec.Runtimes = []performancev2.RuntimeHandler{
+				{Name: "High_Performance"},
+				{Name: "high-performance"},
+				{Name: "crun-hp", RuntimePath: pointer.String("crun")},
+				{Name: "crun-hp", AllowedAnnotations: []string{"cpu-quota.crio.io\"]"}},
+			}
+			errors := validateRuntimes(profile)
+			Expect(errors).To(HaveLen(5))
+			Expect(errors[0].Field).To(Equal("spec.runtimes[0].name"))
+			Expect(errors[1].Error()).To(ContainSubstring("runtime handler is managed by the operator"))
+			Expect(errors[2].Error()).To(ContainSubstring("runtime path should be an absolute path"))
+			Expect(errors[3].Error()).To(ContainSubstring("Duplicate value"))
+			Expect(errors[4].Field).To(Equal("spec.runtimes[3].allowedAnnotations[0]"))
+		})
+	})
+
+	Describe("Admission warnings", func() {
+		newNode := func(name, memory string) corev1.Node {
+			return corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Status: corev1.NodeStatus{
+					Capacity: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+				},
+			}
+		}
+
+		It("should not warn about a sound profile", func() {
+			Expect(getWarnings(profile, []corev1.Node{newNode("worker-0", "8Gi")})).To(BeEmpty())
+		})
+
+		It("should warn about an odd reserved CPUs count", func() {
+			reserved := performancev2.CPUSet("0-2")
+			profile.Spec.CPU.Reserved = &reserved
+			warnings := getWarnings(profile, nil)
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(ContainSubstring("the reserved CPUs count 3 is odd"))
+		})
+
+		It("should warn about nohz_full CPUs without the realtime workload hint", func() {
+			nohzFullCPUs := performancev2.CPUSet("4")
+			profile.Spec.CPU.NohzFull = &nohzFullCPUs
+			profile.Spec.WorkloadHints = &performancev2.WorkloadHints{RealTime: pointer.Bool(false)}
+			warnings := getWarnings(profile, nil)
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(ContainSubstring("the nohz_full CPUs are ignored"))
+		})
+
+		It("should warn about the realtime throttling with the realtime workload hint", func() {
+			profile.Spec.Scheduler = &performancev2.Scheduler{RTRuntimeUs: pointer.Int32(950000)}
+			warnings := getWarnings(profile, nil)
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(ContainSubstring("spec.scheduler.rtRuntimeUs"))
+
+			profile.Spec.WorkloadHints = &performancev2.WorkloadHints{RealTime: pointer.Bool(false)}
+			Expect(getWarnings(profile, nil)).To(BeEmpty())
+		})
+
+		It("should warn about the disabled timer migration", func() {
+			profile.Spec.Scheduler = &performancev2.Scheduler{TimerMigration: pointer.Bool(false)}
+			warnings := getWarnings(profile, nil)
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(ContainSubstring("the timers are not evacuated"))
+		})
+
+		It("should warn about huge pages taking most of the memory of the smallest node", func() {
+			warnings := getWarnings(profile, []corev1.Node{newNode("worker-0", "64Gi"), newNode("worker-1", "4608Mi")})
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(ContainSubstring(`the huge pages take 88% of the memory of the node "worker-1"`))
+		})
+	})
+
+	Describe("validation of validateFields function", func() {
+		It("should check all fields", func() {
+			// config all specs to rise an error in every func inside validateFields()
+			reservedCPUs := performancev2.CPUSet("")
+			isolatedCPUs := performancev2.CPUSet("0-6")
+			offlinedCPUs := performancev2.CPUSet("7")
+			profile.Spec.CPU.Reserved = &reservedCPUs
+			profile.Spec.CPU.Isolated = &isolatedCPUs
+			profile.Spec.CPU.Offlined = &offlinedCPUs
+
+			profile.Spec.MachineConfigLabel["foo"] = "bar"
+
+			incorrectDefaultSize := performancev2.HugePageSize("!#@")
+			profile.Spec.HugePages.DefaultHugePagesSize = &incorrectDefaultSize
+
+			profile.Spec.WorkloadHints = &performancev2.WorkloadHints{
+				RealTime: pointer.Bool(false),
+			}
+			profile.Spec.RealTimeKernel = &performancev2.RealTimeKernel{
+				Enabled: pointer.Bool(true),
+			}
+
+			invalidVendor := "123"
+			invalidDevice := "0x12345"
+			profile.Spec.Net.Devices[0].InterfaceName = pointer.String("")
+			profile.Spec.Net.Devices[0].VendorID = pointer.String(invalidVendor)
+			profile.Spec.Net.Devices[0].DeviceID = pointer.String(invalidDevice)
+
+			errors := ValidateBasicFields(profile)
+
+			type void struct{}
+			var member void
+			errorMsgs := make(map[string]void)
+
+			errorMsgs["reserved CPUs can not be empty"] = member
+			errorMsgs["you should provide only 1 MachineConfigLabel"] = member
+			errorMsgs[`hugepages default size should be equal to "1G" or "2M"`] = member
+			errorMsgs["device name cannot be empty"] = member
+			errorMsgs[fmt.Sprintf("device vendor ID %s has an invalid format. Vendor ID should be represented as 0x<4 hexadecimal digits> (16 bit representation)", invalidVendor)] = member
+			errorMsgs[fmt.Sprintf("device model ID %s has an invalid format. Model ID should be represented as 0x<4 hexadecimal digits> (16 bit representation)", invalidDevice)] = member
+			errorMsgs["realtime kernel is enabled, but realtime workload hint is explicitly disable"] = member
+
+			for _, err := range errors {
+				_, exists := errorMsgs[err.Detail]
+				if exists {
+					delete(errorMsgs, err.Detail)
+				}
+			}
+
+			for errorMsg, ok := range errorMsgs {
+				Expect(ok).To(BeTrue(), "missing expected error: %q", errorMsg)
+			}
+		})
+	})
+})
+
+func setValidNodeSelector(profile *performancev2.PerformanceProfile) {
+	selector := make(map[string]string)
+	selector["fooDomain/"+NodeSelectorRole] = ""
+	profile.Spec.NodeSelector = selector
+}
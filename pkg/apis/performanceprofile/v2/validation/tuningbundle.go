@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	apiconfigv1 "github.com/openshift/api/config/v1"
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ValidateTuningBundle runs all the checks of the tuning bundle 'r' against the cluster state 'state', the same
+// way Validate does for a performance profile. The warnings are returned only when no error is found.
+func ValidateTuningBundle(r *performancev2.TuningBundle, state ClusterState) (admission.Warnings, field.ErrorList) {
+	warnings, allErrs := ValidateTuningBundleAgainst(r, &performancev2.PerformanceProfileList{Items: state.Profiles}, state.Nodes, state.SysctlPolicy)
+
+	if len(allErrs) == 0 && state.CPUPartitioning != nil {
+		allErrs = ValidateTuningBundleCPUPartitioning(r, *state.CPUPartitioning)
+	}
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	return nil, allErrs
+}
+
+// ValidateTuningBundleAgainst validates the performance profile and the supplemental Tuned CRs of the bundle all together:
+// the performance profile against the other performance profiles 'ppList' and the nodes 'nodes' matching its
+// node selector, the Tuned CRs against the sysctl policy 'policy' of the default Tuned CR. The warnings are
+// returned only when no error is found.
+func ValidateTuningBundleAgainst(r *performancev2.TuningBundle, ppList *performancev2.PerformanceProfileList, nodes []corev1.Node, policy *tunedv1.SysctlPolicy) (admission.Warnings, field.ErrorList) {
+	var allErrs field.ErrorList
+
+	// the performance profile of the bundle is updated in place, so it does not conflict with itself
+	others := &performancev2.PerformanceProfileList{}
+	for _, pp := range ppList.Items {
+		if pp.Name != r.Name {
+			others.Items = append(others.Items, pp)
+		}
+	}
+	warnings, profileErrs := ValidateAgainst(r.GetPerformanceProfile(), others, nodes)
+	allErrs = append(allErrs, reRootFieldErrors(profileErrs, "spec", "spec.performanceProfile")...)
+
+	fldPath := field.NewPath("spec", "tuneds")
+	names := map[string]bool{}
+	for i, tuned := range r.GetTuneds("") {
+		tunedPath := fldPath.Index(i)
+		switch {
+		case tuned.Name == "":
+			allErrs = append(allErrs, field.Required(tunedPath.Child("name"), "the Tuned name is required"))
+		case tuned.Name == tunedv1.TunedDefaultResourceName || tuned.Name == tunedv1.TunedRenderedResourceName:
+			allErrs = append(allErrs, field.Invalid(tunedPath.Child("name"), tuned.Name, "the Tuned name is reserved for the operator"))
+		case names[tuned.Name]:
+			allErrs = append(allErrs, field.Duplicate(tunedPath.Child("name"), tuned.Name))
+		}
+		names[tuned.Name] = true
+
+		tunedErrs := tuned.ValidateSysctlPolicy(policy)
+		layerWarnings, layerErrs := tuned.ValidatePriorityLayer()
+		tunedErrs = append(tunedErrs, layerErrs...)
+		for j, recommend := range tuned.Spec.Recommend {
+			tunedErrs = append(tunedErrs, recommend.Schedule.Validate(field.NewPath("spec", "recommend").Index(j).Child("schedule"))...)
+		}
+		tunedErrs = reRootFieldErrors(tunedErrs, "spec", tunedPath.Child("spec").String())
+		allErrs = append(allErrs, reRootFieldErrors(tunedErrs, "metadata.labels", tunedPath.Child("labels").String())...)
+		for _, w := range layerWarnings {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", tunedPath, w))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	return nil, allErrs
+}
+
+// ValidateTuningBundleCPUPartitioning checks the performance profile of the bundle does not contradict the cluster CPU partitioning mode 'mode'.
+func ValidateTuningBundleCPUPartitioning(r *performancev2.TuningBundle, mode apiconfigv1.CPUPartitioningMode) field.ErrorList {
+	allErrs := ValidateCPUPartitioning(r.GetPerformanceProfile(), mode)
+	allErrs = reRootFieldErrors(allErrs, "spec", "spec.performanceProfile")
+	return reRootFieldErrors(allErrs, "metadata", "spec.performanceProfile.metadata")
+}
+
+// reRootFieldErrors moves the errors of the fields under the path 'from' under the path 'to'.
+func reRootFieldErrors(errs field.ErrorList, from string, to string) field.ErrorList {
+	for _, err := range errs {
+		if err.Field == from || strings.HasPrefix(err.Field, from+".") || strings.HasPrefix(err.Field, from+"[") {
+			err.Field = to + strings.TrimPrefix(err.Field, from)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,101 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+*/
+
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// udevRuleKeys maps the keys of the udev rules to whether they require an {attribute}
+var udevRuleKeys = map[string]bool{
+	"ACTION": false, "DEVPATH": false, "KERNEL": false, "KERNELS": false, "NAME": false, "SYMLINK": false,
+	"SUBSYSTEM": false, "SUBSYSTEMS": false, "DRIVER": false, "DRIVERS": false, "TAG": false, "TAGS": false,
+	"TEST": false, "PROGRAM": false, "RESULT": false, "OPTIONS": false, "OWNER": false, "GROUP": false,
+	"MODE": false, "RUN": false, "LABEL": false, "GOTO": false,
+	"ATTR": true, "ATTRS": true, "SYSCTL": true, "ENV": true, "CONST": true, "IMPORT": true, "SECLABEL": true,
+}
+
+// ValidateUdevRules checks the udev rules 'rules' consist of rules made of comma separated
+// KEY[{attribute}]OPERATOR"value" pairs, with known keys and operators.
+func ValidateUdevRules(rules string) error {
+	var count int
+	var rule string
+	for i, line := range strings.Split(rules, "\n") {
+		line = strings.TrimSpace(line)
+		if rule == "" && (line == "" || strings.HasPrefix(line, "#")) {
+			continue
+		}
+		if strings.HasSuffix(line, "\\") {
+			rule += strings.TrimSuffix(line, "\\") + " "
+			continue
+		}
+		rule += line
+		if err := validateUdevRule(rule); err != nil {
+			return fmt.Errorf("udev rule at line %d is invalid: %v", i+1, err)
+		}
+		count++
+		rule = ""
+	}
+	if rule != "" {
+		return fmt.Errorf("udev rule continued past the last line")
+	}
+	if count == 0 {
+		return fmt.Errorf("udev rules should define at least one rule")
+	}
+	return nil
+}
+
+// validateUdevRule checks the syntax of the single udev rule 'rule'.
+func validateUdevRule(rule string) error {
+	re := regexp.MustCompile(`^([A-Z_]+)(\{[^}]*\})?\s*(==|!=|\+=|-=|:=|=)\s*`)
+	for rest := strings.TrimLeft(rule, " \t,"); rest != ""; rest = strings.TrimLeft(rest, " \t,") {
+		match := re.FindStringSubmatch(rest)
+		if match == nil {
+			return fmt.Errorf(`expected a KEY=="value" or KEY="value" pair at %q`, rest)
+		}
+		key, attribute := match[1], match[2]
+		attributeRequired, ok := udevRuleKeys[key]
+		if !ok {
+			return fmt.Errorf("unknown key %q", key)
+		}
+		if attributeRequired && attribute == "" {
+			return fmt.Errorf("the key %q requires an {attribute}", key)
+		}
+
+		// the values of the e"..." form interpret the C-style escapes
+		rest = strings.TrimPrefix(rest[len(match[0]):], "e")
+		if !strings.HasPrefix(rest, `"`) {
+			return fmt.Errorf("the value of the key %q should be double quoted", key)
+		}
+		end := -1
+		for j := 1; j < len(rest); j++ {
+			if rest[j] == '\\' {
+				j++
+			} else if rest[j] == '"' {
+				end = j
+				break
+			}
+		}
+		if end < 0 {
+			return fmt.Errorf("the value of the key %q is not terminated", key)
+		}
+		rest = rest[end+1:]
+	}
+	return nil
+}
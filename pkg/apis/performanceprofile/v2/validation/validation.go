@@ -0,0 +1,158 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+*/
+
+// Package validation holds the checks of the performance profiles and the tuning bundles the admission webhook
+// runs. The package depends on no client, the webhook reads the cluster state and calls Validate with it, so CI
+// pipelines and policy engines can run the checks out of the cluster exactly as the webhook does.
+package validation
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/cpuset"
+)
+
+// CPUs are the CPU sets of a performance profile in the cpuset list format, a nil set is not set in the profile
+type CPUs struct {
+	Reserved        *string
+	Isolated        *string
+	Offlined        *string
+	Shared          *string
+	IRQServing      *string
+	NohzFull        *string
+	RCUNocbs        *string
+	EfficiencyCores *string
+
+	// AllowMixedCoreTypes allows the isolated CPUs to mix the efficiency and the performance cores
+	AllowMixedCoreTypes bool
+}
+
+// ValidateCPUs checks the CPU sets 'cpus' of the profile field 'fldPath': the reserved and isolated CPUs are required
+// and not empty, the reserved, isolated, offlined and shared CPUs do not overlap, and the optional CPU sets are
+// subsets of the CPUs they tune.
+func ValidateCPUs(fldPath *field.Path, cpus CPUs) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if cpus.Isolated == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("isolated"), "isolated CPUs required"))
+	}
+	if cpus.Reserved == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("reserved"), "reserved CPUs required"))
+	}
+	if cpus.Isolated == nil || cpus.Reserved == nil {
+		return allErrs
+	}
+
+	// the CPU sets are parsed in order, the first malformed set is reported
+	sets := map[string]cpuset.CPUSet{}
+	for _, cpuList := range []struct {
+		name  string
+		value *string
+	}{
+		{name: "reserved", value: cpus.Reserved},
+		{name: "isolated", value: cpus.Isolated},
+		{name: "offlined", value: cpus.Offlined},
+		{name: "shared", value: cpus.Shared},
+	} {
+		var value string
+		if cpuList.value != nil {
+			value = *cpuList.value
+		}
+		set, err := cpuset.Parse(value)
+		if err != nil {
+			return append(allErrs, field.InternalError(fldPath, err))
+		}
+		sets[cpuList.name] = set
+	}
+
+	if sets["reserved"].IsEmpty() {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("reserved"), *cpus.Reserved, "reserved CPUs can not be empty"))
+	}
+	if sets["isolated"].IsEmpty() {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("isolated"), *cpus.Isolated, "isolated CPUs can not be empty"))
+	}
+
+	for k1, cpuset1 := range sets {
+		for k2, cpuset2 := range sets {
+			if k1 == k2 {
+				continue
+			}
+			if overlap := cpuset1.Intersection(cpuset2); !overlap.IsEmpty() {
+				allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("%s and %s cpus overlap: %v", k1, k2, overlap.List())))
+			}
+		}
+	}
+
+	if cpus.IRQServing != nil {
+		irqServing, err := cpuset.Parse(*cpus.IRQServing)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("irqServing"), *cpus.IRQServing, err.Error()))
+		} else if irqServing.IsEmpty() {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("irqServing"), *cpus.IRQServing, "IRQ serving CPUs can not be empty"))
+		} else if !irqServing.IsSubsetOf(sets["reserved"]) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("irqServing"), *cpus.IRQServing, "IRQ serving CPUs must be a subset of the reserved CPUs"))
+		}
+	}
+
+	allErrs = append(allErrs, validateIsolatedSubset(fldPath.Child("nohzFull"), cpus.NohzFull, sets["isolated"], "nohz_full")...)
+	allErrs = append(allErrs, validateIsolatedSubset(fldPath.Child("rcuNocbs"), cpus.RCUNocbs, sets["isolated"], "rcu_nocbs")...)
+	allErrs = append(allErrs, validateCoreTypes(fldPath, cpus, sets["isolated"])...)
+
+	return allErrs
+}
+
+// validateIsolatedSubset validates that the optional CPU set is a subset of the isolated CPUs, an empty set is allowed
+func validateIsolatedSubset(path *field.Path, cpus *string, isolated cpuset.CPUSet, name string) field.ErrorList {
+	var allErrs field.ErrorList
+	if cpus == nil {
+		return allErrs
+	}
+
+	subset, err := cpuset.Parse(*cpus)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(path, *cpus, err.Error()))
+	} else if !subset.IsSubsetOf(isolated) {
+		allErrs = append(allErrs, field.Invalid(path, *cpus, fmt.Sprintf("%s CPUs must be a subset of the isolated CPUs", name)))
+	}
+	return allErrs
+}
+
+// validateCoreTypes makes sure the isolated CPUs 'isolated' do not mix the efficiency and the performance cores of
+// hybrid processors, which would make the latency of the isolated workloads depend on the CPUs they are pinned to.
+func validateCoreTypes(fldPath *field.Path, cpus CPUs, isolated cpuset.CPUSet) field.ErrorList {
+	var allErrs field.ErrorList
+	if cpus.EfficiencyCores == nil {
+		return allErrs
+	}
+
+	efficiencyCores, err := cpuset.Parse(*cpus.EfficiencyCores)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("efficiencyCores"), *cpus.EfficiencyCores, err.Error()))
+		return allErrs
+	}
+
+	if cpus.AllowMixedCoreTypes {
+		return allErrs
+	}
+	isolatedEfficiency := isolated.Intersection(efficiencyCores)
+	if !isolatedEfficiency.IsEmpty() && !isolatedEfficiency.Equals(isolated) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("isolated"), *cpus.Isolated,
+			fmt.Sprintf("isolated CPUs mix the efficiency cores %s with performance cores %s, set allowMixedCoreTypes to allow it",
+				isolatedEfficiency.String(), isolated.Difference(efficiencyCores).String())))
+	}
+	return allErrs
+}
@@ -0,0 +1,181 @@
+package validation
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/cpuset"
+)
+
+func TestValidateCPUs(t *testing.T) {
+	cpus := func(v string) *string { return &v }
+
+	tests := []struct {
+		name        string
+		cpus        CPUs
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			cpus: CPUs{Reserved: cpus("0-1"), Isolated: cpus("2-7"), IRQServing: cpus("1"), NohzFull: cpus("4-7"), RCUNocbs: cpus("")},
+		},
+		{
+			name:        "missing reserved",
+			cpus:        CPUs{Isolated: cpus("2-7")},
+			expectedErr: "spec.cpu.reserved: Required value",
+		},
+		{
+			name:        "malformed offlined",
+			cpus:        CPUs{Reserved: cpus("0-1"), Isolated: cpus("2-7"), Offlined: cpus("a")},
+			expectedErr: "spec.cpu: Internal error",
+		},
+		{
+			name:        "empty isolated",
+			cpus:        CPUs{Reserved: cpus("0-1"), Isolated: cpus("")},
+			expectedErr: "isolated CPUs can not be empty",
+		},
+		{
+			name:        "overlapping shared",
+			cpus:        CPUs{Reserved: cpus("0-1"), Isolated: cpus("2-7"), Shared: cpus("7")},
+			expectedErr: "isolated and shared cpus overlap: [7]",
+		},
+		{
+			name:        "IRQ serving out of the reserved CPUs",
+			cpus:        CPUs{Reserved: cpus("0-1"), Isolated: cpus("2-7"), IRQServing: cpus("2")},
+			expectedErr: "IRQ serving CPUs must be a subset of the reserved CPUs",
+		},
+		{
+			name:        "nohz_full out of the isolated CPUs",
+			cpus:        CPUs{Reserved: cpus("0-1"), Isolated: cpus("2-7"), NohzFull: cpus("1-2")},
+			expectedErr: "nohz_full CPUs must be a subset of the isolated CPUs",
+		},
+		{
+			name:        "mixed core types",
+			cpus:        CPUs{Reserved: cpus("0-1"), Isolated: cpus("2-7"), EfficiencyCores: cpus("4-7")},
+			expectedErr: "isolated CPUs mix the efficiency cores 4-7 with performance cores 2-3",
+		},
+		{
+			name: "mixed core types allowed",
+			cpus: CPUs{Reserved: cpus("0-1"), Isolated: cpus("2-7"), EfficiencyCores: cpus("4-7"), AllowMixedCoreTypes: true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateCPUs(field.NewPath("spec.cpu"), tc.cpus)
+			if tc.expectedErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if err := errs.ToAggregate(); err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Errorf("expected an error containing %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+// FuzzValidateCPUs checks the CPU sets admitted by the checks can be used as they are: the sets are well formed,
+// the reserved and isolated CPUs are not empty and do not overlap, and the checks are deterministic.
+func FuzzValidateCPUs(f *testing.F) {
+	f.Add("0-1", "2-7", "", "", "1")
+	f.Add("0-3", "2-5", "6", "7", "")
+	f.Add("0", "1-3", "1", "", "0-1")
+	f.Add("", "a", "", "0", "-")
+
+	f.Fuzz(func(t *testing.T, reserved, isolated, offlined, shared, irqServing string) {
+		cpus := CPUs{Reserved: &reserved, Isolated: &isolated, Offlined: &offlined, Shared: &shared, IRQServing: &irqServing}
+		errs := ValidateCPUs(field.NewPath("spec.cpu"), cpus)
+		if !reflect.DeepEqual(errorStrings(errs), errorStrings(ValidateCPUs(field.NewPath("spec.cpu"), cpus))) {
+			t.Errorf("validation is not deterministic")
+		}
+		if len(errs) != 0 {
+			return
+		}
+
+		reservedSet, err := cpuset.Parse(reserved)
+		if err != nil {
+			t.Fatalf("admitted malformed reserved CPUs %q: %v", reserved, err)
+		}
+		isolatedSet, err := cpuset.Parse(isolated)
+		if err != nil {
+			t.Fatalf("admitted malformed isolated CPUs %q: %v", isolated, err)
+		}
+		if reservedSet.IsEmpty() || isolatedSet.IsEmpty() {
+			t.Errorf("admitted empty reserved %q or isolated %q CPUs", reserved, isolated)
+		}
+		if !reservedSet.Intersection(isolatedSet).IsEmpty() {
+			t.Errorf("admitted overlapping reserved %q and isolated %q CPUs", reserved, isolated)
+		}
+		if irqServingSet, err := cpuset.Parse(irqServing); err != nil || !irqServingSet.IsSubsetOf(reservedSet) {
+			t.Errorf("admitted the IRQ serving CPUs %q out of the reserved CPUs %q", irqServing, reserved)
+		}
+	})
+}
+
+func TestValidateUdevRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		rules       string
+		expectedErr string
+	}{
+		{
+			name:  "rules continued over several lines",
+			rules: "# comment\nACTION==\"add\", SUBSYSTEM==\"net\", \\\n  ATTR{mtu}=\"9000\"\n",
+		},
+		{
+			name:        "unknown key",
+			rules:       `FOO=="bar"`,
+			expectedErr: `unknown key "FOO"`,
+		},
+		{
+			name:        "missing attribute",
+			rules:       `ATTR=="1"`,
+			expectedErr: `the key "ATTR" requires an {attribute}`,
+		},
+		{
+			name:        "unterminated value",
+			rules:       `KERNEL=="eth*`,
+			expectedErr: `the value of the key "KERNEL" is not terminated`,
+		},
+		{
+			name:        "dangling continuation",
+			rules:       `KERNEL=="eth*" \`,
+			expectedErr: "udev rule continued past the last line",
+		},
+		{
+			name:        "no rule",
+			rules:       "# comment only\n",
+			expectedErr: "udev rules should define at least one rule",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateUdevRules(tc.rules)
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Errorf("expected an error containing %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+// errorStrings returns the sorted messages of the errors, the errors of the map fields are found in random order
+func errorStrings(errs field.ErrorList) []string {
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	sort.Strings(msgs)
+	return msgs
+}
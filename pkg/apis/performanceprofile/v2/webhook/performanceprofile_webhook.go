@@ -0,0 +1,112 @@
+// Package webhook holds the admission webhook of the performance profiles. The webhook reads the state of the
+// cluster and leaves the checks to the validation package, so the profiles are validated the same way in and out
+// of the cluster.
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	apiconfigv1 "github.com/openshift/api/config/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	ppvalidation "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2/validation"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/metrics"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog"
+)
+
+var _ admission.CustomValidator = &PerformanceProfileValidator{}
+
+// PerformanceProfileValidator validates the performance profiles against the state of the cluster read by 'Client'
+type PerformanceProfileValidator struct {
+	Client client.Client
+}
+
+// SetupWithManager enables the validating webhook and the conversion webhook of the performance profiles
+func SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&performancev2.PerformanceProfile{}).
+		WithValidator(&PerformanceProfileValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *PerformanceProfileValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	profile, ok := obj.(*performancev2.PerformanceProfile)
+	if !ok {
+		return admission.Warnings{}, apierrors.NewBadRequest(fmt.Sprintf("expected a performance profile, got %T", obj))
+	}
+	klog.Infof("Create validation for the performance profile %q", profile.Name)
+
+	return v.validateCreateOrUpdate(ctx, profile)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *PerformanceProfileValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	profile, ok := newObj.(*performancev2.PerformanceProfile)
+	if !ok {
+		return admission.Warnings{}, apierrors.NewBadRequest(fmt.Sprintf("expected a performance profile, got %T", newObj))
+	}
+	klog.Infof("Update validation for the performance profile %q", profile.Name)
+
+	return v.validateCreateOrUpdate(ctx, profile)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *PerformanceProfileValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return admission.Warnings{}, nil
+}
+
+func (v *PerformanceProfileValidator) validateCreateOrUpdate(ctx context.Context, profile *performancev2.PerformanceProfile) (admission.Warnings, error) {
+	state, err := v.getClusterState(ctx, profile)
+	if err != nil {
+		metrics.PerformanceProfileWebhookDenied(string(metav1.StatusReasonInternalError))
+		return admission.Warnings{}, apierrors.NewInternalError(err)
+	}
+
+	warnings, allErrs := ppvalidation.Validate(profile, state)
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+
+	// the denial is accounted to the type of its first finding
+	metrics.PerformanceProfileWebhookDenied(string(allErrs[0].Type))
+	return admission.Warnings{}, apierrors.NewInvalid(
+		schema.GroupKind{Group: "performance.openshift.io", Kind: "PerformanceProfile"},
+		profile.Name, allErrs)
+}
+
+// getClusterState reads the state of the cluster the profile is validated against
+func (v *PerformanceProfileValidator) getClusterState(ctx context.Context, profile *performancev2.PerformanceProfile) (ppvalidation.ClusterState, error) {
+	ppList := &performancev2.PerformanceProfileList{}
+	if err := v.Client.List(ctx, ppList); err != nil {
+		return ppvalidation.ClusterState{}, err
+	}
+	state := ppvalidation.ClusterState{Profiles: ppList.Items}
+
+	// the node checks are best effort, the profile is admitted when the nodes can not be listed
+	nodes := &corev1.NodeList{}
+	if err := v.Client.List(ctx, nodes, client.MatchingLabels(profile.Spec.NodeSelector)); err != nil {
+		klog.Warningf("failed to list the nodes of the performance profile %q: %v", profile.Name, err)
+	}
+	state.Nodes = nodes.Items
+
+	// the cluster CPU partitioning check is best effort as well, e.g. the infrastructure does not exist on HyperShift
+	infra := &apiconfigv1.Infrastructure{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Name: "cluster"}, infra); err != nil {
+		klog.Warningf("failed to get the cluster infrastructure to validate the performance profile %q: %v", profile.Name, err)
+	} else {
+		state.CPUPartitioning = &infra.Status.CPUPartitioning
+	}
+
+	return state, nil
+}
@@ -6,10 +6,33 @@
 package v2
 
 import (
-	v1 "github.com/openshift/custom-resource-status/conditions/v1"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoRollback) DeepCopyInto(out *AutoRollback) {
+	*out = *in
+	if in.DegradedTunedProfiles != nil {
+		in, out := &in.DegradedTunedProfiles, &out.DegradedTunedProfiles
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoRollback.
+func (in *AutoRollback) DeepCopy() *AutoRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CPU) DeepCopyInto(out *CPU) {
 	*out = *in
@@ -28,6 +51,11 @@ func (in *CPU) DeepCopyInto(out *CPU) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.IsolationMethod != nil {
+		in, out := &in.IsolationMethod, &out.IsolationMethod
+		*out = new(CPUIsolationMethod)
+		**out = **in
+	}
 	if in.Offlined != nil {
 		in, out := &in.Offlined, &out.Offlined
 		*out = new(CPUSet)
@@ -38,6 +66,46 @@ func (in *CPU) DeepCopyInto(out *CPU) {
 		*out = new(CPUSet)
 		**out = **in
 	}
+	if in.IRQServing != nil {
+		in, out := &in.IRQServing, &out.IRQServing
+		*out = new(CPUSet)
+		**out = **in
+	}
+	if in.NohzFull != nil {
+		in, out := &in.NohzFull, &out.NohzFull
+		*out = new(CPUSet)
+		**out = **in
+	}
+	if in.RCUNocbs != nil {
+		in, out := &in.RCUNocbs, &out.RCUNocbs
+		*out = new(CPUSet)
+		**out = **in
+	}
+	if in.RCUKthreadPriority != nil {
+		in, out := &in.RCUKthreadPriority, &out.RCUKthreadPriority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PinUnboundWorkqueues != nil {
+		in, out := &in.PinUnboundWorkqueues, &out.PinUnboundWorkqueues
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SMTPolicy != nil {
+		in, out := &in.SMTPolicy, &out.SMTPolicy
+		*out = new(SMTPolicy)
+		**out = **in
+	}
+	if in.EfficiencyCores != nil {
+		in, out := &in.EfficiencyCores, &out.EfficiencyCores
+		*out = new(CPUSet)
+		**out = **in
+	}
+	if in.AllowMixedCoreTypes != nil {
+		in, out := &in.AllowMixedCoreTypes, &out.AllowMixedCoreTypes
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -51,6 +119,32 @@ func (in *CPU) DeepCopy() *CPU {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryRollout) DeepCopyInto(out *CanaryRollout) {
+	*out = *in
+	if in.MaxLatency != nil {
+		in, out := &in.MaxLatency, &out.MaxLatency
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryRollout.
+func (in *CanaryRollout) DeepCopy() *CanaryRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Device) DeepCopyInto(out *Device) {
 	*out = *in
@@ -82,6 +176,43 @@ func (in *Device) DeepCopy() *Device {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceDriverBinding) DeepCopyInto(out *DeviceDriverBinding) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceDriverBinding.
+func (in *DeviceDriverBinding) DeepCopy() *DeviceDriverBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceDriverBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Devices) DeepCopyInto(out *Devices) {
+	*out = *in
+	if in.DriverBindings != nil {
+		in, out := &in.DriverBindings, &out.DriverBindings
+		*out = make([]DeviceDriverBinding, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Devices.
+func (in *Devices) DeepCopy() *Devices {
+	if in == nil {
+		return nil
+	}
+	out := new(Devices)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HardwareTuning) DeepCopyInto(out *HardwareTuning) {
 	*out = *in
@@ -111,11 +242,21 @@ func (in *HardwareTuning) DeepCopy() *HardwareTuning {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HugePage) DeepCopyInto(out *HugePage) {
 	*out = *in
+	if in.MemoryPercent != nil {
+		in, out := &in.MemoryPercent, &out.MemoryPercent
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Node != nil {
 		in, out := &in.Node, &out.Node
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Allocation != nil {
+		in, out := &in.Allocation, &out.Allocation
+		*out = new(HugePageAllocation)
+		**out = **in
+	}
 	return
 }
 
@@ -144,6 +285,11 @@ func (in *HugePages) DeepCopyInto(out *HugePages) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NodeMemorySize != nil {
+		in, out := &in.NodeMemorySize, &out.NodeMemorySize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 	return
 }
 
@@ -157,6 +303,117 @@ func (in *HugePages) DeepCopy() *HugePages {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelModule) DeepCopyInto(out *KernelModule) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Blacklist != nil {
+		in, out := &in.Blacklist, &out.Blacklist
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelModule.
+func (in *KernelModule) DeepCopy() *KernelModule {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelModule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletConfigSyncStatus) DeepCopyInto(out *KubeletConfigSyncStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeKubeletConfigSync, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletConfigSyncStatus.
+func (in *KubeletConfigSyncStatus) DeepCopy() *KubeletConfigSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletConfigSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatencyProbe) DeepCopyInto(out *LatencyProbe) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CPUs != nil {
+		in, out := &in.CPUs, &out.CPUs
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatencyProbe.
+func (in *LatencyProbe) DeepCopy() *LatencyProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(LatencyProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Memory) DeepCopyInto(out *Memory) {
+	*out = *in
+	if in.KernelSamePageMerging != nil {
+		in, out := &in.KernelSamePageMerging, &out.KernelSamePageMerging
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Memory.
+func (in *Memory) DeepCopy() *Memory {
+	if in == nil {
+		return nil
+	}
+	out := new(Memory)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NUMA) DeepCopyInto(out *NUMA) {
 	*out = *in
@@ -165,6 +422,23 @@ func (in *NUMA) DeepCopyInto(out *NUMA) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.TopologyPolicyOptions != nil {
+		in, out := &in.TopologyPolicyOptions, &out.TopologyPolicyOptions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TopologyManagerScope != nil {
+		in, out := &in.TopologyManagerScope, &out.TopologyManagerScope
+		*out = new(string)
+		**out = **in
+	}
+	if in.AutomaticBalancing != nil {
+		in, out := &in.AutomaticBalancing, &out.AutomaticBalancing
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -193,6 +467,13 @@ func (in *Net) DeepCopyInto(out *Net) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PacketSteering != nil {
+		in, out := &in.PacketSteering, &out.PacketSteering
+		*out = make([]PacketSteering, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -206,6 +487,77 @@ func (in *Net) DeepCopy() *Net {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkStack) DeepCopyInto(out *NetworkStack) {
+	*out = *in
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkStack.
+func (in *NetworkStack) DeepCopy() *NetworkStack {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkStack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeKubeletConfigSync) DeepCopyInto(out *NodeKubeletConfigSync) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeKubeletConfigSync.
+func (in *NodeKubeletConfigSync) DeepCopy() *NodeKubeletConfigSync {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeKubeletConfigSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PacketSteering) DeepCopyInto(out *PacketSteering) {
+	*out = *in
+	in.Device.DeepCopyInto(&out.Device)
+	if in.CPUs != nil {
+		in, out := &in.CPUs, &out.CPUs
+		*out = new(CPUSet)
+		**out = **in
+	}
+	if in.RPS != nil {
+		in, out := &in.RPS, &out.RPS
+		*out = new(bool)
+		**out = **in
+	}
+	if in.XPS != nil {
+		in, out := &in.XPS, &out.XPS
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PacketSteering.
+func (in *PacketSteering) DeepCopy() *PacketSteering {
+	if in == nil {
+		return nil
+	}
+	out := new(PacketSteering)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PerformanceProfile) DeepCopyInto(out *PerformanceProfile) {
 	*out = *in
@@ -285,6 +637,16 @@ func (in *PerformanceProfileSpec) DeepCopyInto(out *PerformanceProfileSpec) {
 		*out = new(HugePages)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(Memory)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduler != nil {
+		in, out := &in.Scheduler, &out.Scheduler
+		*out = new(Scheduler)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MachineConfigLabel != nil {
 		in, out := &in.MachineConfigLabel, &out.MachineConfigLabel
 		*out = make(map[string]string, len(*in))
@@ -299,6 +661,16 @@ func (in *PerformanceProfileSpec) DeepCopyInto(out *PerformanceProfileSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.MachineConfigPoolMaxUnavailable != nil {
+		in, out := &in.MachineConfigPoolMaxUnavailable, &out.MachineConfigPoolMaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MachineConfigPoolMaintenanceWindow != nil {
+		in, out := &in.MachineConfigPoolMaintenanceWindow, &out.MachineConfigPoolMaintenanceWindow
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = make(map[string]string, len(*in))
@@ -316,26 +688,75 @@ func (in *PerformanceProfileSpec) DeepCopyInto(out *PerformanceProfileSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.NUMA != nil {
-		in, out := &in.NUMA, &out.NUMA
-		*out = new(NUMA)
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]KernelModule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = new(Devices)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Net != nil {
-		in, out := &in.Net, &out.Net
-		*out = new(Net)
+	if in.UdevRules != nil {
+		in, out := &in.UdevRules, &out.UdevRules
+		*out = make([]UdevRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.KubeletConfigOverrides != nil {
+		in, out := &in.KubeletConfigOverrides, &out.KubeletConfigOverrides
+		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.GloballyDisableIrqLoadBalancing != nil {
-		in, out := &in.GloballyDisableIrqLoadBalancing, &out.GloballyDisableIrqLoadBalancing
-		*out = new(bool)
-		**out = **in
+	if in.Systemd != nil {
+		in, out := &in.Systemd, &out.Systemd
+		*out = new(Systemd)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TimeSync != nil {
+		in, out := &in.TimeSync, &out.TimeSync
+		*out = new(TimeSync)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Runtimes != nil {
+		in, out := &in.Runtimes, &out.Runtimes
+		*out = make([]RuntimeHandler, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NUMA != nil {
+		in, out := &in.NUMA, &out.NUMA
+		*out = new(NUMA)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Net != nil {
+		in, out := &in.Net, &out.Net
+		*out = new(Net)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkStack != nil {
+		in, out := &in.NetworkStack, &out.NetworkStack
+		*out = new(NetworkStack)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GloballyDisableIrqLoadBalancing != nil {
+		in, out := &in.GloballyDisableIrqLoadBalancing, &out.GloballyDisableIrqLoadBalancing
+		*out = new(bool)
+		**out = **in
 	}
 	if in.WorkloadHints != nil {
 		in, out := &in.WorkloadHints, &out.WorkloadHints
 		*out = new(WorkloadHints)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DisableGenerated != nil {
+		in, out := &in.DisableGenerated, &out.DisableGenerated
+		*out = make([]GeneratedArtifact, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -354,7 +775,7 @@ func (in *PerformanceProfileStatus) DeepCopyInto(out *PerformanceProfileStatus)
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]conditionsv1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -369,6 +790,36 @@ func (in *PerformanceProfileStatus) DeepCopyInto(out *PerformanceProfileStatus)
 		*out = new(string)
 		**out = **in
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		**out = **in
+	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(RollbackStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SkippedFeatures != nil {
+		in, out := &in.SkippedFeatures, &out.SkippedFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SkippedArtifacts != nil {
+		in, out := &in.SkippedArtifacts, &out.SkippedArtifacts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExportedArtifacts != nil {
+		in, out := &in.ExportedArtifacts, &out.ExportedArtifacts
+		*out = new(string)
+		**out = **in
+	}
+	if in.KubeletConfigSync != nil {
+		in, out := &in.KubeletConfigSync, &out.KubeletConfigSync
+		*out = new(KubeletConfigSyncStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -382,6 +833,22 @@ func (in *PerformanceProfileStatus) DeepCopy() *PerformanceProfileStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileSummary) DeepCopyInto(out *ProfileSummary) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileSummary.
+func (in *ProfileSummary) DeepCopy() *ProfileSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RealTimeKernel) DeepCopyInto(out *RealTimeKernel) {
 	*out = *in
@@ -403,6 +870,379 @@ func (in *RealTimeKernel) DeepCopy() *RealTimeKernel {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackStatus) DeepCopyInto(out *RollbackStatus) {
+	*out = *in
+	in.RollbackTime.DeepCopyInto(&out.RollbackTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackStatus.
+func (in *RollbackStatus) DeepCopy() *RollbackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeHandler) DeepCopyInto(out *RuntimeHandler) {
+	*out = *in
+	if in.RuntimePath != nil {
+		in, out := &in.RuntimePath, &out.RuntimePath
+		*out = new(string)
+		**out = **in
+	}
+	if in.RuntimeRoot != nil {
+		in, out := &in.RuntimeRoot, &out.RuntimeRoot
+		*out = new(string)
+		**out = **in
+	}
+	if in.AllowedAnnotations != nil {
+		in, out := &in.AllowedAnnotations, &out.AllowedAnnotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeHandler.
+func (in *RuntimeHandler) DeepCopy() *RuntimeHandler {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeHandler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Scheduler) DeepCopyInto(out *Scheduler) {
+	*out = *in
+	if in.RTRuntimeUs != nil {
+		in, out := &in.RTRuntimeUs, &out.RTRuntimeUs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimerMigration != nil {
+		in, out := &in.TimerMigration, &out.TimerMigration
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Scheduler.
+func (in *Scheduler) DeepCopy() *Scheduler {
+	if in == nil {
+		return nil
+	}
+	out := new(Scheduler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Systemd) DeepCopyInto(out *Systemd) {
+	*out = *in
+	if in.Slices != nil {
+		in, out := &in.Slices, &out.Slices
+		*out = make([]SystemdSlice, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Units != nil {
+		in, out := &in.Units, &out.Units
+		*out = make([]SystemdUnitOrdering, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Systemd.
+func (in *Systemd) DeepCopy() *Systemd {
+	if in == nil {
+		return nil
+	}
+	out := new(Systemd)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemdSlice) DeepCopyInto(out *SystemdSlice) {
+	*out = *in
+	if in.CPUAccounting != nil {
+		in, out := &in.CPUAccounting, &out.CPUAccounting
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CPUWeight != nil {
+		in, out := &in.CPUWeight, &out.CPUWeight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AllowedCPUs != nil {
+		in, out := &in.AllowedCPUs, &out.AllowedCPUs
+		*out = new(CPUSet)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemdSlice.
+func (in *SystemdSlice) DeepCopy() *SystemdSlice {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemdSlice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemdUnitOrdering) DeepCopyInto(out *SystemdUnitOrdering) {
+	*out = *in
+	if in.Before != nil {
+		in, out := &in.Before, &out.Before
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.After != nil {
+		in, out := &in.After, &out.After
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemdUnitOrdering.
+func (in *SystemdUnitOrdering) DeepCopy() *SystemdUnitOrdering {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemdUnitOrdering)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeSync) DeepCopyInto(out *TimeSync) {
+	*out = *in
+	if in.CPUs != nil {
+		in, out := &in.CPUs, &out.CPUs
+		*out = new(CPUSet)
+		**out = **in
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]TimeSyncService, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeSync.
+func (in *TimeSync) DeepCopy() *TimeSync {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningBundle) DeepCopyInto(out *TuningBundle) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningBundle.
+func (in *TuningBundle) DeepCopy() *TuningBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TuningBundle) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningBundleList) DeepCopyInto(out *TuningBundleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TuningBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningBundleList.
+func (in *TuningBundleList) DeepCopy() *TuningBundleList {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningBundleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TuningBundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningBundleRollback) DeepCopyInto(out *TuningBundleRollback) {
+	*out = *in
+	in.RollbackTime.DeepCopyInto(&out.RollbackTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningBundleRollback.
+func (in *TuningBundleRollback) DeepCopy() *TuningBundleRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningBundleRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningBundleSpec) DeepCopyInto(out *TuningBundleSpec) {
+	*out = *in
+	in.PerformanceProfile.DeepCopyInto(&out.PerformanceProfile)
+	if in.Tuneds != nil {
+		in, out := &in.Tuneds, &out.Tuneds
+		*out = make([]TuningBundleTuned, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningBundleSpec.
+func (in *TuningBundleSpec) DeepCopy() *TuningBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningBundleStatus) DeepCopyInto(out *TuningBundleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]conditionsv1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(TuningBundleRollback)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningBundleStatus.
+func (in *TuningBundleStatus) DeepCopy() *TuningBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningBundleTuned) DeepCopyInto(out *TuningBundleTuned) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningBundleTuned.
+func (in *TuningBundleTuned) DeepCopy() *TuningBundleTuned {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningBundleTuned)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UdevRule) DeepCopyInto(out *UdevRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UdevRule.
+func (in *UdevRule) DeepCopy() *UdevRule {
+	if in == nil {
+		return nil
+	}
+	out := new(UdevRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadHints) DeepCopyInto(out *WorkloadHints) {
 	*out = *in
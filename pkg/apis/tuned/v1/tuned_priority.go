@@ -0,0 +1,99 @@
+package v1
+
+import (
+	"fmt"
+	"math"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// PriorityLayer is a reserved range of the Tuned recommend priorities, so that the Tuned CRs shipped
+// by the platform, the vendors and the cluster administrators do not collide.  The Tuned CRs of the
+// lower layers are recommended over the ones of the upper layers (the highest priority is 0).
+type PriorityLayer string
+
+const (
+	// PriorityLayerUser is the priority layer of the Tuned CRs created by the cluster administrators.
+	PriorityLayerUser PriorityLayer = "user"
+	// PriorityLayerVendor is the priority layer of the Tuned CRs shipped by third-party operators.
+	PriorityLayerVendor PriorityLayer = "vendor"
+	// PriorityLayerSystem is the priority layer of the Tuned CRs shipped by the platform, e.g. the
+	// default Tuned CR or the Tuned CRs rendered out of the performance profiles.
+	PriorityLayerSystem PriorityLayer = "system"
+)
+
+// priorityLayers maps the priority layers to their [min, max] recommend priority ranges.
+var priorityLayers = map[PriorityLayer][2]uint64{
+	PriorityLayerUser:   {0, 9},
+	PriorityLayerVendor: {10, 19},
+	PriorityLayerSystem: {20, math.MaxUint64},
+}
+
+// Range returns the [min, max] recommend priority range of the priority layer, ok is false for an unknown layer.
+func (l PriorityLayer) Range() (min uint64, max uint64, ok bool) {
+	r, ok := priorityLayers[l]
+	return r[0], r[1], ok
+}
+
+// Contains returns true if the recommend priority 'priority' falls into the range of the priority layer.
+func (l PriorityLayer) Contains(priority uint64) bool {
+	min, max, ok := l.Range()
+	return ok && min <= priority && priority <= max
+}
+
+// PriorityLayerOf returns the priority layer whose range the recommend priority 'priority' falls into.
+func PriorityLayerOf(priority uint64) PriorityLayer {
+	for _, l := range []PriorityLayer{PriorityLayerUser, PriorityLayerVendor, PriorityLayerSystem} {
+		if l.Contains(priority) {
+			return l
+		}
+	}
+	return PriorityLayerSystem
+}
+
+// ValidatePriorityLayer validates the recommend priorities of the Tuned CR against the priority layer of its
+// TunedPriorityLayerLabel label.  The Tuned CRs without the label predate the priority layers; recommending
+// them in the vendor or the system layer only warns, so that the existing Tuned CRs are still admitted.
+func (r *Tuned) ValidatePriorityLayer() ([]string, field.ErrorList) {
+	warnings := []string{}
+	allErrs := field.ErrorList{}
+
+	value, labeled := r.Labels[TunedPriorityLayerLabel]
+	layer := PriorityLayer(value)
+	if labeled {
+		if _, _, ok := layer.Range(); !ok {
+			allErrs = append(allErrs, field.NotSupported(field.NewPath("metadata", "labels").Key(TunedPriorityLayerLabel), value,
+				[]string{string(PriorityLayerUser), string(PriorityLayerVendor), string(PriorityLayerSystem)}))
+			return warnings, allErrs
+		}
+	}
+
+	fldPath := field.NewPath("spec", "recommend")
+	for i, recommend := range r.Spec.Recommend {
+		if recommend.Priority == nil {
+			continue
+		}
+		priority := *recommend.Priority
+		if labeled {
+			if !layer.Contains(priority) {
+				min, max, _ := layer.Range()
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("priority"), priority,
+					fmt.Sprintf("the priority layer %q reserves the priorities %s", layer, formatPriorityRange(min, max))))
+			}
+			continue
+		}
+		if l := PriorityLayerOf(priority); l != PriorityLayerUser {
+			warnings = append(warnings, fmt.Sprintf("spec.recommend[%d].priority %d falls into the %q priority layer; set the %s label to declare the priority layer of the Tuned",
+				i, priority, l, TunedPriorityLayerLabel))
+		}
+	}
+
+	return warnings, allErrs
+}
+
+func formatPriorityRange(min, max uint64) string {
+	if max == math.MaxUint64 {
+		return fmt.Sprintf("%d and above", min)
+	}
+	return fmt.Sprintf("%d-%d", min, max)
+}
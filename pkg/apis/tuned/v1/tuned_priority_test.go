@@ -0,0 +1,89 @@
+package v1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidatePriorityLayer(t *testing.T) {
+	priority := func(p uint64) *uint64 { return &p }
+	tests := []struct {
+		name     string
+		layer    string
+		priority *uint64
+		warnings int
+		errs     int
+	}{
+		{
+			name:     "unlabeled in the user layer",
+			priority: priority(5),
+		},
+		{
+			name:     "unlabeled in the system layer",
+			priority: priority(20),
+			warnings: 1,
+		},
+		{
+			name:     "vendor in the vendor layer",
+			layer:    "vendor",
+			priority: priority(15),
+		},
+		{
+			name:     "vendor in the user layer",
+			layer:    "vendor",
+			priority: priority(5),
+			errs:     1,
+		},
+		{
+			name:     "system at a large priority",
+			layer:    "system",
+			priority: priority(1000),
+		},
+		{
+			name:     "user in the system layer",
+			layer:    "user",
+			priority: priority(30),
+			errs:     1,
+		},
+		{
+			name:     "unknown layer",
+			layer:    "platform",
+			priority: priority(5),
+			errs:     1,
+		},
+		{
+			name:  "no priority",
+			layer: "user",
+		},
+	}
+
+	for _, tc := range tests {
+		tuned := &Tuned{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec:       TunedSpec{Recommend: []TunedRecommend{{Priority: tc.priority}}},
+		}
+		if tc.layer != "" {
+			tuned.Labels = map[string]string{TunedPriorityLayerLabel: tc.layer}
+		}
+		warnings, errs := tuned.ValidatePriorityLayer()
+		if len(warnings) != tc.warnings || len(errs) != tc.errs {
+			t.Errorf("%s: expected %d warning(s) and %d error(s), got %v and %v", tc.name, tc.warnings, tc.errs, warnings, errs)
+		}
+	}
+}
+
+func TestPriorityLayerOf(t *testing.T) {
+	for priority, expected := range map[uint64]PriorityLayer{
+		0:  PriorityLayerUser,
+		9:  PriorityLayerUser,
+		10: PriorityLayerVendor,
+		19: PriorityLayerVendor,
+		20: PriorityLayerSystem,
+		40: PriorityLayerSystem,
+	} {
+		if layer := PriorityLayerOf(priority); layer != expected {
+			t.Errorf("expected priority %d in the %q layer, got %q", priority, expected, layer)
+		}
+	}
+}
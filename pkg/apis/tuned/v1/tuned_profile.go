@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// ProfilePluginSections returns the names of the TuneD profile 'data' sections configuring the TuneD
+// plugin 'plugin', i.e. the section named after the plugin unless it sets another "type" and the
+// sections with "type=<plugin>".  Profiles TuneD fails to parse only get the section named after
+// the plugin.
+func ProfilePluginSections(data, plugin string) map[string]bool {
+	sections := map[string]bool{plugin: true}
+	cfg, err := ini.LoadSources(ini.LoadOptions{
+		AllowBooleanKeys:           true,
+		AllowPythonMultilineValues: true,
+		SpaceBeforeInlineComment:   true,
+	}, []byte(data))
+	if err != nil {
+		return sections
+	}
+
+	for _, section := range cfg.Sections() {
+		if section.HasKey("type") {
+			sections[section.Name()] = section.Key("type").String() == plugin
+		}
+	}
+	return sections
+}
+
+// ProfileSection returns the section name of the TuneD profile line 'line' if it is a section header.
+func ProfileSection(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+	return strings.TrimSpace(line[1 : len(line)-1]), true
+}
+
+// ProfileKeyValue returns the trimmed key and value of the TuneD profile line 'line' if it is a key=value line.
+func ProfileKeyValue(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+		return "", "", false
+	}
+	key, value, found := strings.Cut(line, "=")
+	if !found || strings.TrimSpace(key) == "" {
+		return "", "", false
+	}
+	return strings.TrimSpace(key), strings.TrimSpace(value), true
+}
@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProfilePluginSections(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected map[string]bool
+	}{
+		{
+			name:     "plugin section",
+			data:     "[main]\nsummary=test\n[sysctl]\nkernel.panic=1\n",
+			expected: map[string]bool{"sysctl": true},
+		},
+		{
+			name:     "typed sections",
+			data:     "[net]\ntype=sysctl\nnet.core.somaxconn=1024\n[disk]\ntype=disk # the disk plugin\n",
+			expected: map[string]bool{"sysctl": true, "net": true, "disk": false},
+		},
+		{
+			name:     "plugin section of another type",
+			data:     "[sysctl]\ntype=sysfs\n/sys/kernel/mm/ksm/run=0\n",
+			expected: map[string]bool{"sysctl": false},
+		},
+		{
+			name:     "unparsable profile",
+			data:     "[net\ntype=sysctl\n",
+			expected: map[string]bool{"sysctl": true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ProfilePluginSections(tc.data, "sysctl"); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected sections %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
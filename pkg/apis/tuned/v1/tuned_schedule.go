@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// scheduleDays maps the schedule days to the days of the week.
+var scheduleDays = map[ScheduleDay]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// IsActive returns true if time 'now' falls into the schedule window.  A nil schedule is always active.
+func (s *TunedSchedule) IsActive(now time.Time) (bool, error) {
+	if s == nil {
+		return true, nil
+	}
+	start, end, loc, err := s.parse()
+	if err != nil {
+		return false, err
+	}
+
+	now = now.In(loc)
+	minute := now.Hour()*60 + now.Minute()
+	if start < end {
+		return start <= minute && minute < end && s.startsOn(now.Weekday()), nil
+	}
+	// the window spans midnight, it started either today or yesterday
+	if minute >= start {
+		return s.startsOn(now.Weekday()), nil
+	}
+	return minute < end && s.startsOn(now.AddDate(0, 0, -1).Weekday()), nil
+}
+
+// NextBoundary returns the first time after time 'now' the schedule window starts or ends on any day.
+func (s *TunedSchedule) NextBoundary(now time.Time) (time.Time, error) {
+	start, end, loc, err := s.parse()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	now = now.In(loc)
+	var next time.Time
+	for _, minute := range []int{start, end} {
+		boundary := time.Date(now.Year(), now.Month(), now.Day(), minute/60, minute%60, 0, 0, loc)
+		if !boundary.After(now) {
+			boundary = time.Date(now.Year(), now.Month(), now.Day()+1, minute/60, minute%60, 0, 0, loc)
+		}
+		if next.IsZero() || boundary.Before(next) {
+			next = boundary
+		}
+	}
+	return next, nil
+}
+
+// String returns the schedule window in the "Mon,Tue 08:00-18:00 UTC" format.
+func (s *TunedSchedule) String() string {
+	if s == nil {
+		return ""
+	}
+	window := fmt.Sprintf("%s-%s %s", s.Start, s.End, s.timeZone())
+	if len(s.Days) == 0 {
+		return window
+	}
+	days := make([]string, 0, len(s.Days))
+	for _, day := range s.Days {
+		days = append(days, string(day))
+	}
+	return strings.Join(days, ",") + " " + window
+}
+
+// Validate returns an error for every invalid field of the schedule.
+func (s *TunedSchedule) Validate(fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if s == nil {
+		return allErrs
+	}
+
+	start, startErr := parseScheduleTime(s.Start)
+	if startErr != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("start"), s.Start, startErr.Error()))
+	}
+	end, endErr := parseScheduleTime(s.End)
+	if endErr != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("end"), s.End, endErr.Error()))
+	}
+	if startErr == nil && endErr == nil && start == end {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("end"), s.End, "the window end must differ from the window start"))
+	}
+	for i, day := range s.Days {
+		if _, ok := scheduleDays[day]; !ok {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("days").Index(i), day, []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}))
+		}
+	}
+	if _, err := time.LoadLocation(s.timeZone()); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeZone"), s.TimeZone, err.Error()))
+	}
+	return allErrs
+}
+
+// parse returns the start and the end of the window in minutes since midnight and the time zone of the window.
+func (s *TunedSchedule) parse() (int, int, *time.Location, error) {
+	start, err := parseScheduleTime(s.Start)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("invalid schedule start %q: %v", s.Start, err)
+	}
+	end, err := parseScheduleTime(s.End)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("invalid schedule end %q: %v", s.End, err)
+	}
+	loc, err := time.LoadLocation(s.timeZone())
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("invalid schedule time zone %q: %v", s.TimeZone, err)
+	}
+	return start, end, loc, nil
+}
+
+// startsOn returns true if the window starts on the day of the week 'weekday'.
+func (s *TunedSchedule) startsOn(weekday time.Weekday) bool {
+	if len(s.Days) == 0 {
+		return true
+	}
+	for _, day := range s.Days {
+		if scheduleDays[day] == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *TunedSchedule) timeZone() string {
+	if s.TimeZone == "" {
+		return "UTC"
+	}
+	return s.TimeZone
+}
+
+// parseScheduleTime returns the minutes since midnight of the "HH:MM" time 'value'.
+func parseScheduleTime(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("expected the HH:MM format")
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestScheduleIsActive(t *testing.T) {
+	// Monday
+	monday := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		schedule *TunedSchedule
+		now      time.Time
+		active   bool
+	}{
+		{
+			name:   "nil schedule",
+			now:    monday,
+			active: true,
+		},
+		{
+			name:     "within the window",
+			schedule: &TunedSchedule{Start: "08:00", End: "18:00"},
+			now:      monday.Add(8 * time.Hour),
+			active:   true,
+		},
+		{
+			name:     "at the window end",
+			schedule: &TunedSchedule{Start: "08:00", End: "18:00"},
+			now:      monday.Add(18 * time.Hour),
+			active:   false,
+		},
+		{
+			name:     "window spanning midnight before midnight",
+			schedule: &TunedSchedule{Start: "22:00", End: "06:00"},
+			now:      monday.Add(23 * time.Hour),
+			active:   true,
+		},
+		{
+			name:     "window spanning midnight after midnight",
+			schedule: &TunedSchedule{Start: "22:00", End: "06:00"},
+			now:      monday.Add(5 * time.Hour),
+			active:   true,
+		},
+		{
+			name:     "window spanning midnight started on an excluded day",
+			schedule: &TunedSchedule{Start: "22:00", End: "06:00", Days: []ScheduleDay{"Mon"}},
+			now:      monday.Add(5 * time.Hour),
+			active:   false,
+		},
+		{
+			name:     "window spanning midnight started on an included day",
+			schedule: &TunedSchedule{Start: "22:00", End: "06:00", Days: []ScheduleDay{"Sun"}},
+			now:      monday.Add(5 * time.Hour),
+			active:   true,
+		},
+		{
+			name:     "window in another time zone",
+			schedule: &TunedSchedule{Start: "08:00", End: "18:00", TimeZone: "Asia/Tokyo"},
+			now:      monday,
+			active:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active, err := tt.schedule.IsActive(tt.now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if active != tt.active {
+				t.Errorf("expected active %t, got %t", tt.active, active)
+			}
+		})
+	}
+}
+
+func TestScheduleNextBoundary(t *testing.T) {
+	schedule := &TunedSchedule{Start: "22:00", End: "06:00"}
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	next, err := schedule.NextBoundary(now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := now.Add(10 * time.Hour); !next.Equal(expected) {
+		t.Errorf("expected the boundary %v, got %v", expected, next)
+	}
+
+	next, err = schedule.NextBoundary(next)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := now.Add(18 * time.Hour); !next.Equal(expected) {
+		t.Errorf("expected the boundary %v, got %v", expected, next)
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	valid := &TunedSchedule{Start: "22:00", End: "06:00", Days: []ScheduleDay{"Fri"}, TimeZone: "Europe/Prague"}
+	if errs := valid.Validate(field.NewPath("schedule")); len(errs) != 0 {
+		t.Errorf("valid schedule failed the validation: %v", errs)
+	}
+
+	invalid := &TunedSchedule{Start: "25:00", End: "06:00", Days: []ScheduleDay{"Friday"}, TimeZone: "Mars/Olympus"}
+	if errs := invalid.Validate(field.NewPath("schedule")); len(errs) != 3 {
+		t.Errorf("expected 3 errors, got %v", errs)
+	}
+
+	empty := &TunedSchedule{Start: "06:00", End: "06:00"}
+	if errs := empty.Validate(field.NewPath("schedule")); len(errs) != 1 {
+		t.Errorf("expected 1 error, got %v", errs)
+	}
+}
@@ -0,0 +1,13 @@
+package v1
+
+import (
+	"strings"
+)
+
+// PrimaryTunedProfile returns the TuneD profile recommended for a Node out of the TuneD profile 'tunedProfile'
+// of a Profile.  The supplemental profiles (see TunedSupplementalLabel) follow the recommended profile in the
+// space-separated list of the TuneD profiles the TuneD daemon merges.
+func PrimaryTunedProfile(tunedProfile string) string {
+	primary, _, _ := strings.Cut(strings.TrimSpace(tunedProfile), " ")
+	return primary
+}
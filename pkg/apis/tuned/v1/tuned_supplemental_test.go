@@ -0,0 +1,23 @@
+package v1
+
+import (
+	"testing"
+)
+
+func TestPrimaryTunedProfile(t *testing.T) {
+	tests := []struct {
+		tunedProfile string
+		expected     string
+	}{
+		{tunedProfile: "", expected: ""},
+		{tunedProfile: "openshift-node", expected: "openshift-node"},
+		{tunedProfile: "openshift-node-performance sriov-supplemental", expected: "openshift-node-performance"},
+		{tunedProfile: "openshift-node sriov-supplemental virt-supplemental", expected: "openshift-node"},
+	}
+
+	for _, tc := range tests {
+		if got := PrimaryTunedProfile(tc.tunedProfile); got != tc.expected {
+			t.Errorf("expected the primary TuneD profile %q of %q, got %q", tc.expected, tc.tunedProfile, got)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// sysctlPluginOptions are the TuneD plugin options of the sysctl plugin sections, which are not sysctls.
+var sysctlPluginOptions = map[string]bool{
+	"devices":            true,
+	"devices_udev_regex": true,
+	"drop":               true,
+	"enabled":            true,
+	"priority":           true,
+	"replace":            true,
+	"script_post":        true,
+	"script_pre":         true,
+	"type":               true,
+}
+
+// Allows returns true if the policy allows to set the sysctl 'sysctl'.  A nil policy allows all sysctls.
+func (p *SysctlPolicy) Allows(sysctl string) bool {
+	if p == nil {
+		return true
+	}
+	sysctl = normalizeSysctl(sysctl)
+	if sysctlsMatch(p.Denied, sysctl) {
+		return false
+	}
+	return len(p.Allowed) == 0 || sysctlsMatch(p.Allowed, sysctl)
+}
+
+// FilterSysctls returns the TuneD profile 'data' without the sysctls the policy denies
+// along with the sorted names of the denied sysctls.
+func (p *SysctlPolicy) FilterSysctls(data string) (string, []string) {
+	if p == nil {
+		return data, nil
+	}
+
+	lines := strings.Split(data, "\n")
+	sysctlSections := ProfilePluginSections(data, "sysctl")
+	kept := make([]string, 0, len(lines))
+	denied := map[string]bool{}
+	section := ""
+	for _, line := range lines {
+		if name, ok := ProfileSection(line); ok {
+			section = name
+		} else if sysctl, _, ok := ProfileKeyValue(line); ok && sysctlSections[section] && !sysctlPluginOptions[sysctl] && !p.Allows(sysctl) {
+			denied[normalizeSysctl(sysctl)] = true
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if len(denied) == 0 {
+		return data, nil
+	}
+
+	sysctls := make([]string, 0, len(denied))
+	for sysctl := range denied {
+		sysctls = append(sysctls, sysctl)
+	}
+	sort.Strings(sysctls)
+
+	return strings.Join(kept, "\n"), sysctls
+}
+
+// Validate returns the errors of the policy entries, which are sysctl names optionally ending with "*".
+func (p *SysctlPolicy) Validate(fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p == nil {
+		return allErrs
+	}
+
+	validate := func(entries []string, fldPath *field.Path) {
+		for i, entry := range entries {
+			switch {
+			case entry == "":
+				allErrs = append(allErrs, field.Required(fldPath.Index(i), "the sysctl name must not be empty"))
+			case strings.ContainsAny(entry, " \t="):
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i), entry, "the sysctl name must not contain whitespace or \"=\""))
+			case strings.Contains(strings.TrimSuffix(entry, "*"), "*"):
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i), entry, "\"*\" is supported only at the end of the sysctl name"))
+			}
+		}
+	}
+	validate(p.Allowed, fldPath.Child("allowed"))
+	validate(p.Denied, fldPath.Child("denied"))
+
+	return allErrs
+}
+
+// sysctlsMatch returns true if one of the sysctl policy 'entries' matches the sysctl 'sysctl'.
+func sysctlsMatch(entries []string, sysctl string) bool {
+	for _, entry := range entries {
+		entry = normalizeSysctl(entry)
+		if entry == sysctl {
+			return true
+		}
+		if prefix, wildcard := strings.CutSuffix(entry, "*"); wildcard && strings.HasPrefix(sysctl, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeSysctl returns the sysctl 'sysctl' in the dotted form, e.g. "kernel/panic" -> "kernel.panic".
+func normalizeSysctl(sysctl string) string {
+	return strings.ReplaceAll(strings.TrimSpace(sysctl), "/", ".")
+}
@@ -15,6 +15,10 @@ const (
 	// all the other custom tuned resources.
 	TunedRenderedResourceName = "rendered"
 
+	// TunedCmdlineManifestConfigMapName is the name of the ConfigMap the operator publishes the expected
+	// kernel command line of every rendered MachineConfig of the pools in, keyed by the MachineConfig name.
+	TunedCmdlineManifestConfigMapName = "tuned-cmdline-manifest"
+
 	// TunedClusterOperatorResourceName is the name of the clusteroperator resource
 	// that reflects the node tuning operator status.
 	TunedClusterOperatorResourceName = "node-tuning"
@@ -25,11 +29,60 @@ const (
 	// TunedBootcmdlineAnnotationKey is a Node-specific annotation denoting kernel command-line parameters
 	// calculated by TuneD for the current profile applied to that Node.
 	TunedBootcmdlineAnnotationKey string = "tuned.openshift.io/bootcmdline"
+
+	// TunedKubeletConfigChecksumsAnnotationKey is a Node-specific annotation holding the JSON map of the
+	// checksums of the kubelet configuration values the PerformanceProfile sets, read from the kubelet
+	// configuration file of the Node.  The operator compares them with the generated KubeletConfig to
+	// tell the Nodes running the new kubelet configuration.
+	TunedKubeletConfigChecksumsAnnotationKey string = "tuned.openshift.io/kubelet-config-checksums"
+
+	// TunedExcludeAnnotationKey is a Node annotation which, set to "true", excludes the Node from the
+	// tuning of its pool.  The Node gets the profile the default Tuned CR recommends for it, without the
+	// custom and the supplemental profiles, and thus without their IRQ and cpuset tuning.
+	TunedExcludeAnnotationKey string = "tuned.openshift.io/exclude"
+
+	// TunedDeferredUpdate is a Tuned CR annotation requesting that changes to the TuneD profiles it
+	// recommends are not applied to an already running TuneD daemon.  The changes are applied on the
+	// next TuneD daemon start (e.g. after a node reboot) or once the annotation is removed.
+	// The operator propagates the annotation to the affected Profiles.
+	TunedDeferredUpdate string = "tuned.openshift.io/deferred"
+
+	// TunedOperandPinningAnnotation is a tuned operand pod annotation activating the CRI-O workload
+	// which pins the operand containers to the reserved CPUs of the nodes tuned by a PerformanceProfile.
+	TunedOperandPinningAnnotation string = "tuned.openshift.io/operand-pinning"
+
+	// TunedSupplementalLabel is a Tuned CR label marking the CR as a source of supplemental TuneD profiles.
+	// The label value identifies the owner (e.g. the operator) shipping the Tuned CR.  Instead of competing
+	// for the recommend priorities with the regular Tuned CRs, the matching profiles of supplemental Tuned
+	// CRs are merged on top of the profile recommended for a Node.
+	TunedSupplementalLabel string = "tuned.openshift.io/supplemental"
+
+	// TunedPriorityLayerLabel is a Tuned CR label declaring the priority layer of the CR, see PriorityLayer.
+	// The recommend priorities of a labeled Tuned CR are restricted to the priority range of its layer.
+	TunedPriorityLayerLabel string = "tuned.openshift.io/priority-layer"
+
+	// TunedNotConvergedTaintKey is the key of the NoSchedule Node taint the operator applies, when
+	// the node taint management is enabled in Tuned/default, while the Profile of the Node is not
+	// applied in the current boot of the Node or is degraded.
+	TunedNotConvergedTaintKey string = "node-tuning.openshift.io/not-converged"
+)
+
+// NodeTaintManagement is the mode of the node taint management of the operator.
+type NodeTaintManagement string
+
+const (
+	// NodeTaintManagementManaged makes the operator taint the Nodes with the
+	// TunedNotConvergedTaintKey taint until their Profile is applied.
+	NodeTaintManagementManaged NodeTaintManagement = "Managed"
+	// NodeTaintManagementUnmanaged makes the operator remove the TunedNotConvergedTaintKey
+	// taint it applied; this is the default.
+	NodeTaintManagementUnmanaged NodeTaintManagement = "Unmanaged"
 )
 
 /////////////////////////////////////////////////////////////////////////////////
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
 
 // Tuned is a collection of rules that allows cluster-wide deployment
 // of node-level sysctls and more flexibility to add custom tuning
@@ -60,6 +113,83 @@ type TunedSpec struct {
 	// Selection logic for all Tuned profiles.
 	// +optional
 	Recommend []TunedRecommend `json:"recommend"`
+	// sysctlPolicy controls which sysctls the Tuned profiles of the other Tuned
+	// resources may set.  Honored only in Tuned/default.
+	// +optional
+	SysctlPolicy *SysctlPolicy `json:"sysctlPolicy,omitempty"`
+	// nodeTaintManagement set to Managed makes the operator keep latency-sensitive workloads
+	// off the Nodes whose Profile is progressing or degraded by tainting them.  Valid values
+	// are Managed and Unmanaged (default).  Honored only in Tuned/default.
+	// +kubebuilder:validation:Enum=Managed;Unmanaged
+	// +optional
+	NodeTaintManagement NodeTaintManagement `json:"nodeTaintManagement,omitempty"`
+	// verification lists the checks the operand runs after applying the Tuned profiles,
+	// the results are reported by the Verified condition of the Profiles.
+	// +optional
+	Verification []TunedVerification `json:"verification,omitempty"`
+}
+
+// TunedVerification is a check the operand runs after applying a Tuned profile.
+// Exactly one of file, sysctl and command is set.
+type TunedVerification struct {
+	// name of the check, reported by the Verified condition of the Profiles.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// name of the Tuned profile the check verifies; the check runs on the Nodes
+	// whose applied TuneD profile is or includes this profile.
+	// +kubebuilder:validation:MinLength=1
+	Profile string `json:"profile"`
+	// file checks the content of a file of the Node.
+	// +optional
+	File *TunedFileVerification `json:"file,omitempty"`
+	// sysctl checks the value of a sysctl of the Node.
+	// +optional
+	Sysctl *TunedSysctlVerification `json:"sysctl,omitempty"`
+	// command checks the exit status of a command.
+	// +optional
+	Command *TunedCommandVerification `json:"command,omitempty"`
+}
+
+// TunedFileVerification checks the content of a file of the Node.
+type TunedFileVerification struct {
+	// absolute path of the file on the Node, "/etc/modprobe.d/ice.conf".
+	// +kubebuilder:validation:Pattern=`^/`
+	Path string `json:"path"`
+	// regular expression the content of the file matches.
+	Match string `json:"match"`
+}
+
+// TunedSysctlVerification checks the value of a sysctl of the Node.
+type TunedSysctlVerification struct {
+	// name of the sysctl, "net.core.busy_read".
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// expected value of the sysctl; the fields of multi-value sysctls are compared
+	// regardless of the whitespace separating them.
+	Value string `json:"value"`
+}
+
+// TunedCommandVerification checks the exit status of a command.  The command runs in the
+// operand container image, as an unprivileged user with an empty environment, and is
+// killed after 10 seconds.
+type TunedCommandVerification struct {
+	// command and its arguments, the command is not run in a shell.
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+	// expected exit status of the command.  If omitted, 0 is assumed.
+	// +optional
+	ExitCode int32 `json:"exitCode,omitempty"`
+}
+
+// SysctlPolicy controls which sysctls the Tuned profiles may set.  The entries are sysctl
+// names (e.g. "kernel.panic"); an entry ending with "*" matches all the sysctls with that prefix.
+type SysctlPolicy struct {
+	// allowed sysctls; when set, the sysctls not listed are denied.
+	// +optional
+	Allowed []string `json:"allowed,omitempty"`
+	// denied sysctls; takes precedence over allowed.
+	// +optional
+	Denied []string `json:"denied,omitempty"`
 }
 
 // A Tuned profile.
@@ -69,6 +199,13 @@ type TunedProfile struct {
 	Name *string `json:"name"`
 	// Specification of the Tuned profile to be consumed by the Tuned daemon.
 	Data *string `json:"data"`
+	// Architecture of the nodes the Tuned profile is for, as reported by "uname -m":
+	// [x86_64/aarch64/ppc64le/s390x].  A Tuned profile name can have one variant per
+	// architecture; the operand applies the variant of its node architecture or, if there
+	// is none, the variant without arch.
+	// +kubebuilder:validation:Enum={"x86_64","aarch64","ppc64le","s390x"}
+	// +optional
+	Arch *string `json:"arch,omitempty"`
 }
 
 // Selection logic for a single Tuned profile.
@@ -92,8 +229,34 @@ type TunedRecommend struct {
 	// Optional operand configuration.
 	// +optional
 	Operand OperandConfig `json:"operand,omitempty"`
+
+	// Optional time window the rule applies in.  Outside of the window the rule
+	// is skipped and the nodes fall back to the next matching rule.
+	// +optional
+	Schedule *TunedSchedule `json:"schedule,omitempty"`
 }
 
+// TunedSchedule is a daily time window, e.g. the business hours of the working days.
+type TunedSchedule struct {
+	// Start of the window in the 24-hour "HH:MM" format.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+	// End of the window in the 24-hour "HH:MM" format, the end is not part of the window.
+	// The window spans midnight when the end is not after the start.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+	// Days of the week the window starts on.  If omitted, the window starts every day.
+	// +optional
+	Days []ScheduleDay `json:"days,omitempty"`
+	// IANA name of the time zone of the window, "Europe/Prague".  If omitted, "UTC" is assumed.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ScheduleDay is a day of the week.
+// +kubebuilder:validation:Enum={"Mon","Tue","Wed","Thu","Fri","Sat","Sun"}
+type ScheduleDay string
+
 // Rules governing application of a Tuned profile.
 type TunedMatch struct {
 	// Node or Pod label name.
@@ -112,9 +275,53 @@ type OperandConfig struct {
 	// turn debugging on/off for the TuneD daemon: true/false (default is false)
 	// +optional
 	Debug bool `json:"debug,omitempty"`
+	// verbosity of the TuneD daemon log in the operand log: error/warning/info/debug (default is info);
+	// debug turns debugging on for the TuneD daemon
+	// +optional
+	LogLevel TuneDLogLevel `json:"logLevel,omitempty"`
+	// time-boxed capture of the TuneD daemon debug log into a ConfigMap
+	// +optional
+	DebugCapture *TuneDDebugCapture `json:"debugCapture,omitempty"`
 
 	// +optional
 	TuneDConfig TuneDConfig `json:"tunedConfig,omitempty"`
+	// huge pages allocated by the operand on the running node, without rebooting it
+	// +optional
+	Hugepages []RuntimeHugepages `json:"hugepages,omitempty"`
+}
+
+// TuneDLogLevel is the verbosity of the TuneD daemon log.
+// +kubebuilder:validation:Enum={"error","warning","info","debug"}
+type TuneDLogLevel string
+
+const (
+	TuneDLogLevelError   TuneDLogLevel = "error"
+	TuneDLogLevelWarning TuneDLogLevel = "warning"
+	TuneDLogLevelInfo    TuneDLogLevel = "info"
+	TuneDLogLevelDebug   TuneDLogLevel = "debug"
+)
+
+// TuneDDebugCapture defines a time-boxed capture of the TuneD daemon debug log.  Until the capture
+// ends, the operand runs the TuneD daemon with debugging on and uploads its log to the ConfigMap
+// "tuned-debug-capture-<node>" in the operator namespace.
+type TuneDDebugCapture struct {
+	// time the capture ends at, the TuneD daemon is restarted with the configured verbosity then
+	Until metav1.Time `json:"until"`
+}
+
+// RuntimeHugepages defines the number of huge pages of a size the operand allocates at runtime.
+type RuntimeHugepages struct {
+	// size of the huge pages, "2M"
+	Size string `json:"size"`
+	// count of huge pages to allocate
+	Count int32 `json:"count"`
+	// percentage of the total node memory to allocate as huge pages, overrides the count
+	// +optional
+	MemoryPercent *int32 `json:"memoryPercent,omitempty"`
+	// NUMA node to allocate the huge pages on; when not set, the kernel spreads
+	// the huge pages between the NUMA nodes
+	// +optional
+	Node *int32 `json:"node,omitempty"`
 }
 
 // Global configuration for the TuneD daemon as defined in tuned-main.conf
@@ -126,6 +333,50 @@ type TuneDConfig struct {
 
 // TunedStatus is the status for a Tuned resource.
 type TunedStatus struct {
+	// nodes summarizes the tuning state of the Nodes reported by their Profiles;
+	// set only in the default Tuned resource
+	// +optional
+	Nodes *TunedNodesStatus `json:"nodes,omitempty"`
+	// priorityConflicts lists the TuneD profiles recommended with the same priority for the
+	// same Nodes by different recommend rules; set only in the default Tuned resource
+	// +optional
+	PriorityConflicts []TunedPriorityConflict `json:"priorityConflicts,omitempty"`
+}
+
+// TunedPriorityConflict is a set of TuneD profiles recommended with the same priority for the same Nodes.
+type TunedPriorityConflict struct {
+	// recommend priority the TuneD profiles share
+	Priority uint64 `json:"priority"`
+	// names of the conflicting TuneD profiles, sorted
+	Profiles []string `json:"profiles"`
+	// number of Nodes the TuneD profiles are recommended for
+	Nodes int32 `json:"nodes"`
+}
+
+// TunedNodesStatus aggregates the status of the Profiles of all the Nodes.
+type TunedNodesStatus struct {
+	// number of Nodes with a Profile
+	Total int32 `json:"total"`
+	// number of Nodes with the TuneD profile applied
+	Applied int32 `json:"applied"`
+	// number of Nodes waiting for the TuneD profile to be applied
+	Progressing int32 `json:"progressing"`
+	// number of Nodes which failed to apply the TuneD profile
+	Degraded int32 `json:"degraded"`
+	// number of Nodes per TuneD profile, sorted by the TuneD profile name
+	// +optional
+	Profiles []TunedProfileNodes `json:"profiles,omitempty"`
+	// the last time the summary was updated
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// TunedProfileNodes is the number of Nodes a TuneD profile is selected for.
+type TunedProfileNodes struct {
+	// name of the TuneD profile, without the supplemental TuneD profiles merged on top of it
+	Name string `json:"name"`
+	// number of Nodes the TuneD profile is selected for
+	Nodes int32 `json:"nodes"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -156,16 +407,29 @@ type ProfileSpec struct {
 }
 
 type ProfileConfig struct {
-	// TuneD profile to apply
+	// TuneD profile to apply, followed by the supplemental TuneD profiles merged on top of it,
+	// space-separated
 	TunedProfile string `json:"tunedProfile"`
 	// option to debug TuneD daemon execution
 	// +optional
 	Debug bool `json:"debug"`
+	// verbosity of the TuneD daemon log
+	// +optional
+	LogLevel TuneDLogLevel `json:"logLevel,omitempty"`
+	// time-boxed capture of the TuneD daemon debug log
+	// +optional
+	DebugCapture *TuneDDebugCapture `json:"debugCapture,omitempty"`
 	// +optional
 	TuneDConfig TuneDConfig `json:"tunedConfig,omitempty"`
 	// Name of the cloud provider as taken from the Node providerID: <ProviderName>://<ProviderSpecificNodeID>
 	// +optional
 	ProviderName string `json:"providerName,omitempty"`
+	// huge pages to allocate at runtime
+	// +optional
+	Hugepages []RuntimeHugepages `json:"hugepages,omitempty"`
+	// time window of the recommend rule that selected the TuneD profile, if any
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
 }
 
 // ProfileStatus is the status for a Profile resource; the status is for internal use only
@@ -174,11 +438,58 @@ type ProfileStatus struct {
 	// the current profile in use by the Tuned daemon
 	TunedProfile string `json:"tunedProfile"`
 
+	// the boot ID of the Node the current profile was last applied in
+	// +optional
+	BootID string `json:"bootID,omitempty"`
+
+	// profileHashes are the SHA-256 checksums of the content of the TuneD profiles
+	// the operand extracted on the Node, by TuneD profile name
+	// +optional
+	ProfileHashes map[string]string `json:"profileHashes,omitempty"`
+
+	// operandImage is the image of the operand that applied the current profile
+	// +optional
+	OperandImage string `json:"operandImage,omitempty"`
+
+	// operandVersion is the version of the operand that applied the current profile
+	// +optional
+	OperandVersion string `json:"operandVersion,omitempty"`
+
+	// schedule is the time window of the recommend rule that selected the current profile, if any
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
 	// conditions represents the state of the per-node Profile application
 	// +patchMergeKey=type
 	// +patchStrategy=merge
 	// +optional
 	Conditions []ProfileStatusCondition `json:"conditions,omitempty"  patchStrategy:"merge" patchMergeKey:"type"`
+
+	// latencyProbe reports the outcome of the latency test run on the node after
+	// it was tuned by a PerformanceProfile requesting it
+	// +optional
+	LatencyProbe *LatencyProbeStatus `json:"latencyProbe,omitempty"`
+}
+
+// LatencyProbeStatus is the outcome of a latency test run on a tuned node.
+// +k8s:deepcopy-gen=true
+type LatencyProbeStatus struct {
+	// tool is the latency test tool that was run.
+	Tool string `json:"tool"`
+
+	// machineConfig is the rendered MachineConfig the node was running during the test.
+	MachineConfig string `json:"machineConfig"`
+
+	// maxLatency is the maximum latency measured by the tool, in microseconds.
+	// +optional
+	MaxLatency *int64 `json:"maxLatency,omitempty"`
+
+	// completionTime is the time the test completed.
+	CompletionTime metav1.Time `json:"completionTime"`
+
+	// message reports why the test failed, when it did.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // ProfileStatusCondition represents a partial state of the per-node Profile application.
@@ -221,6 +532,28 @@ const (
 	// application.  To conclude the profile application was successful,
 	// both TunedProfileApplied and TunedDegraded need to be queried.
 	TunedDegraded ProfileConditionType = "Degraded"
+	// TunedHugepagesAllocated indicates whether the Tuned daemon allocated the huge
+	// pages requested at runtime.  The condition is only reported for the Profiles
+	// requesting such huge pages.
+	TunedHugepagesAllocated ProfileConditionType = "HugepagesAllocated"
+	// TunedPathsWritable indicates whether the Tuned daemon could write all the paths
+	// the TuneD profile lines write to.  The condition is only reported for the Profiles
+	// writing paths under read-only locations, such as /usr on image mode nodes.
+	TunedPathsWritable ProfileConditionType = "PathsWritable"
+	// TunedBootcmdlineMerged indicates whether the kernel arguments of the TuneD profile
+	// were merged with the kernel arguments other MachineConfigs of the pool pass.  The
+	// condition is only reported for the Profiles whose kernel arguments overlap with them.
+	TunedBootcmdlineMerged ProfileConditionType = "BootcmdlineMerged"
+	// TunedVerified indicates whether the checks of the Tuned resources verifying the
+	// applied TuneD profile passed.  The condition is only reported for the Profiles
+	// whose applied TuneD profile has checks.
+	TunedVerified ProfileConditionType = "Verified"
+	// TunedCmdlineTracked indicates whether the kernel command line the Node booted with
+	// is the one its boot loader entry and its rendered MachineConfig define.  Untracked
+	// kernel command line modifications, e.g. edited at the boot loader prompt, are
+	// security-relevant.  The condition is only reported once the operator published the
+	// expected kernel command line of the rendered MachineConfig of the Node.
+	TunedCmdlineTracked ProfileConditionType = "CmdlineTracked"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
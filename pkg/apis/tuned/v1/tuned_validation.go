@@ -0,0 +1,99 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Tuned) ValidateCreate() (admission.Warnings, error) {
+	klog.Infof("Create validation for the Tuned %q", r.Name)
+
+	return r.validateCreateOrUpdate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Tuned) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	klog.Infof("Update validation for the Tuned %q", r.Name)
+
+	return r.validateCreateOrUpdate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Tuned) ValidateDelete() (admission.Warnings, error) {
+	klog.Infof("Delete validation for the Tuned %q", r.Name)
+
+	return admission.Warnings{}, nil
+}
+
+func (r *Tuned) validateCreateOrUpdate() (admission.Warnings, error) {
+	warnings := admission.Warnings{}
+	allErrs := field.ErrorList{}
+
+	switch r.Name {
+	case TunedDefaultResourceName:
+		allErrs = append(allErrs, r.Spec.SysctlPolicy.Validate(field.NewPath("spec", "sysctlPolicy"))...)
+	case TunedRenderedResourceName:
+		// Rendered by the operator out of the admitted Tuned resources.
+	default:
+		if r.Spec.SysctlPolicy != nil {
+			warnings = append(warnings, fmt.Sprintf("setting sysctlPolicy is supported only in Tuned/%s; ignoring sysctlPolicy", TunedDefaultResourceName))
+		}
+		if r.Spec.NodeTaintManagement != "" {
+			warnings = append(warnings, fmt.Sprintf("setting nodeTaintManagement is supported only in Tuned/%s; ignoring nodeTaintManagement", TunedDefaultResourceName))
+		}
+
+		// the policy check is best effort, the operand enforces the policy as well
+		tunedDefault := &Tuned{}
+		if err := validatorClient.Get(context.TODO(), client.ObjectKey{Namespace: r.Namespace, Name: TunedDefaultResourceName}, tunedDefault); err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.Warningf("failed to get Tuned %s to validate the Tuned %q: %v", TunedDefaultResourceName, r.Name, err)
+			}
+		} else {
+			allErrs = append(allErrs, r.ValidateSysctlPolicy(tunedDefault.Spec.SysctlPolicy)...)
+		}
+
+		layerWarnings, layerErrs := r.ValidatePriorityLayer()
+		warnings = append(warnings, layerWarnings...)
+		allErrs = append(allErrs, layerErrs...)
+	}
+
+	for i, recommend := range r.Spec.Recommend {
+		allErrs = append(allErrs, recommend.Schedule.Validate(field.NewPath("spec", "recommend").Index(i).Child("schedule"))...)
+	}
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+
+	return admission.Warnings{}, apierrors.NewInvalid(Kind("Tuned"), r.Name, allErrs)
+}
+
+// ValidateSysctlPolicy returns an error for every Tuned profile setting sysctls the sysctl policy 'policy' denies.
+func (r *Tuned) ValidateSysctlPolicy(policy *SysctlPolicy) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if policy == nil {
+		return allErrs
+	}
+
+	fldPath := field.NewPath("spec", "profile")
+	for i, profile := range r.Spec.Profile {
+		if profile.Data == nil {
+			continue
+		}
+		if _, denied := policy.FilterSysctls(*profile.Data); len(denied) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child("data"),
+				fmt.Sprintf("the sysctl policy of Tuned/%s denies the sysctl(s): %s", TunedDefaultResourceName, strings.Join(denied, ", "))))
+		}
+	}
+
+	return allErrs
+}
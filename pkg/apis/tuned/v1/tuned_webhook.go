@@ -1,4 +1,4 @@
-package v2
+package v1
 
 import (
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -6,13 +6,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
-var _ webhook.Validator = &PerformanceProfile{}
+var _ webhook.Validator = &Tuned{}
 
 // we need this variable only because our validate methods should have access to the client
 var validatorClient client.Client
 
-// SetupWebhookWithManager enables Webhooks - needed for version conversion
-func (r *PerformanceProfile) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// SetupWebhookWithManager enables the Tuned validating webhook enforcing the sysctl policy of Tuned/default
+func (r *Tuned) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	if validatorClient == nil {
 		validatorClient = mgr.GetClient()
 	}
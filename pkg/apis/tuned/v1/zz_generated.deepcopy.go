@@ -9,10 +9,44 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatencyProbeStatus) DeepCopyInto(out *LatencyProbeStatus) {
+	*out = *in
+	if in.MaxLatency != nil {
+		in, out := &in.MaxLatency, &out.MaxLatency
+		*out = new(int64)
+		**out = **in
+	}
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatencyProbeStatus.
+func (in *LatencyProbeStatus) DeepCopy() *LatencyProbeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LatencyProbeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperandConfig) DeepCopyInto(out *OperandConfig) {
 	*out = *in
+	if in.DebugCapture != nil {
+		in, out := &in.DebugCapture, &out.DebugCapture
+		*out = new(TuneDDebugCapture)
+		(*in).DeepCopyInto(*out)
+	}
 	in.TuneDConfig.DeepCopyInto(&out.TuneDConfig)
+	if in.Hugepages != nil {
+		in, out := &in.Hugepages, &out.Hugepages
+		*out = make([]RuntimeHugepages, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -57,7 +91,19 @@ func (in *Profile) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProfileConfig) DeepCopyInto(out *ProfileConfig) {
 	*out = *in
+	if in.DebugCapture != nil {
+		in, out := &in.DebugCapture, &out.DebugCapture
+		*out = new(TuneDDebugCapture)
+		(*in).DeepCopyInto(*out)
+	}
 	in.TuneDConfig.DeepCopyInto(&out.TuneDConfig)
+	if in.Hugepages != nil {
+		in, out := &in.Hugepages, &out.Hugepages
+		*out = make([]RuntimeHugepages, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -124,6 +170,13 @@ func (in *ProfileSpec) DeepCopy() *ProfileSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProfileStatus) DeepCopyInto(out *ProfileStatus) {
 	*out = *in
+	if in.ProfileHashes != nil {
+		in, out := &in.ProfileHashes, &out.ProfileHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]ProfileStatusCondition, len(*in))
@@ -131,6 +184,11 @@ func (in *ProfileStatus) DeepCopyInto(out *ProfileStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LatencyProbe != nil {
+		in, out := &in.LatencyProbe, &out.LatencyProbe
+		*out = new(LatencyProbeStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -161,6 +219,58 @@ func (in *ProfileStatusCondition) DeepCopy() *ProfileStatusCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeHugepages) DeepCopyInto(out *RuntimeHugepages) {
+	*out = *in
+	if in.MemoryPercent != nil {
+		in, out := &in.MemoryPercent, &out.MemoryPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Node != nil {
+		in, out := &in.Node, &out.Node
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeHugepages.
+func (in *RuntimeHugepages) DeepCopy() *RuntimeHugepages {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeHugepages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SysctlPolicy) DeepCopyInto(out *SysctlPolicy) {
+	*out = *in
+	if in.Allowed != nil {
+		in, out := &in.Allowed, &out.Allowed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Denied != nil {
+		in, out := &in.Denied, &out.Denied
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SysctlPolicy.
+func (in *SysctlPolicy) DeepCopy() *SysctlPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SysctlPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TuneDConfig) DeepCopyInto(out *TuneDConfig) {
 	*out = *in
@@ -182,13 +292,30 @@ func (in *TuneDConfig) DeepCopy() *TuneDConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuneDDebugCapture) DeepCopyInto(out *TuneDDebugCapture) {
+	*out = *in
+	in.Until.DeepCopyInto(&out.Until)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuneDDebugCapture.
+func (in *TuneDDebugCapture) DeepCopy() *TuneDDebugCapture {
+	if in == nil {
+		return nil
+	}
+	out := new(TuneDDebugCapture)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Tuned) DeepCopyInto(out *Tuned) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -210,6 +337,43 @@ func (in *Tuned) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunedCommandVerification) DeepCopyInto(out *TunedCommandVerification) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunedCommandVerification.
+func (in *TunedCommandVerification) DeepCopy() *TunedCommandVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(TunedCommandVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunedFileVerification) DeepCopyInto(out *TunedFileVerification) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunedFileVerification.
+func (in *TunedFileVerification) DeepCopy() *TunedFileVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(TunedFileVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TunedList) DeepCopyInto(out *TunedList) {
 	*out = *in
@@ -281,6 +445,49 @@ func (in *TunedMatch) DeepCopy() *TunedMatch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunedNodesStatus) DeepCopyInto(out *TunedNodesStatus) {
+	*out = *in
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]TunedProfileNodes, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunedNodesStatus.
+func (in *TunedNodesStatus) DeepCopy() *TunedNodesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TunedNodesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunedPriorityConflict) DeepCopyInto(out *TunedPriorityConflict) {
+	*out = *in
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunedPriorityConflict.
+func (in *TunedPriorityConflict) DeepCopy() *TunedPriorityConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(TunedPriorityConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TunedProfile) DeepCopyInto(out *TunedProfile) {
 	*out = *in
@@ -294,6 +501,11 @@ func (in *TunedProfile) DeepCopyInto(out *TunedProfile) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Arch != nil {
+		in, out := &in.Arch, &out.Arch
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -307,6 +519,22 @@ func (in *TunedProfile) DeepCopy() *TunedProfile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunedProfileNodes) DeepCopyInto(out *TunedProfileNodes) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunedProfileNodes.
+func (in *TunedProfileNodes) DeepCopy() *TunedProfileNodes {
+	if in == nil {
+		return nil
+	}
+	out := new(TunedProfileNodes)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TunedRecommend) DeepCopyInto(out *TunedRecommend) {
 	*out = *in
@@ -335,6 +563,11 @@ func (in *TunedRecommend) DeepCopyInto(out *TunedRecommend) {
 		}
 	}
 	in.Operand.DeepCopyInto(&out.Operand)
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(TunedSchedule)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -348,6 +581,27 @@ func (in *TunedRecommend) DeepCopy() *TunedRecommend {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunedSchedule) DeepCopyInto(out *TunedSchedule) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]ScheduleDay, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunedSchedule.
+func (in *TunedSchedule) DeepCopy() *TunedSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(TunedSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TunedSpec) DeepCopyInto(out *TunedSpec) {
 	*out = *in
@@ -365,6 +619,18 @@ func (in *TunedSpec) DeepCopyInto(out *TunedSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SysctlPolicy != nil {
+		in, out := &in.SysctlPolicy, &out.SysctlPolicy
+		*out = new(SysctlPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = make([]TunedVerification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -381,6 +647,18 @@ func (in *TunedSpec) DeepCopy() *TunedSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TunedStatus) DeepCopyInto(out *TunedStatus) {
 	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(TunedNodesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PriorityConflicts != nil {
+		in, out := &in.PriorityConflicts, &out.PriorityConflicts
+		*out = make([]TunedPriorityConflict, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -393,3 +671,50 @@ func (in *TunedStatus) DeepCopy() *TunedStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunedSysctlVerification) DeepCopyInto(out *TunedSysctlVerification) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunedSysctlVerification.
+func (in *TunedSysctlVerification) DeepCopy() *TunedSysctlVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(TunedSysctlVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunedVerification) DeepCopyInto(out *TunedVerification) {
+	*out = *in
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(TunedFileVerification)
+		**out = **in
+	}
+	if in.Sysctl != nil {
+		in, out := &in.Sysctl, &out.Sysctl
+		*out = new(TunedSysctlVerification)
+		**out = **in
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = new(TunedCommandVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunedVerification.
+func (in *TunedVerification) DeepCopy() *TunedVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(TunedVerification)
+	in.DeepCopyInto(out)
+	return out
+}
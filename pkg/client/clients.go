@@ -12,12 +12,12 @@ import (
 )
 
 type Clients struct {
-	Kube            *kubeset.Clientset
+	Kube            kubeset.Interface
 	ConfigClientSet *configclientset.Clientset
 	ConfigV1Client  *configv1client.ConfigV1Client
-	Tuned           *tunedset.Clientset
+	Tuned           tunedset.Interface
 	MC              *mcfgclientset.Clientset
 	Core            *coreset.CoreV1Client
 	Apps            *appsset.AppsV1Client
-	ManagementKube  *kubeset.Clientset
+	ManagementKube  kubeset.Interface
 }
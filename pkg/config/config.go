@@ -13,6 +13,11 @@ const (
 	operatorNamespaceDefault string = "openshift-cluster-node-tuning-operator"
 	resyncPeriodDefault      int64  = 600
 
+	machineConfigWriteQPSDefault   float32 = 1
+	machineConfigWriteBurstDefault int     = 5
+	machineConfigSyncWindowDefault int64   = 10
+	assetsRefreshIntervalDefault   int64   = 30
+
 	OperatorLockName string = "node-tuning-operator-lock"
 )
 
@@ -71,3 +76,80 @@ func ResyncPeriod() time.Duration {
 	}
 	return time.Second * time.Duration(resyncPeriodDuration)
 }
+
+// MachineConfigWriteQPS returns the configured or default rate of the performance profile
+// controller writes toward the MCO-controlled objects.  Zero disables the rate limiting.
+func MachineConfigWriteQPS() float32 {
+	qpsEnv := os.Getenv("MACHINECONFIG_WRITE_QPS")
+
+	if len(qpsEnv) > 0 {
+		qps, err := strconv.ParseFloat(qpsEnv, 32)
+		if err != nil || qps < 0 {
+			klog.Errorf("cannot parse MACHINECONFIG_WRITE_QPS (%s), using %v", qpsEnv, machineConfigWriteQPSDefault)
+			return machineConfigWriteQPSDefault
+		}
+		return float32(qps)
+	}
+	return machineConfigWriteQPSDefault
+}
+
+// MachineConfigWriteBurst returns the configured or default number of writes toward the
+// MCO-controlled objects the performance profile controller issues at once.
+func MachineConfigWriteBurst() int {
+	burstEnv := os.Getenv("MACHINECONFIG_WRITE_BURST")
+
+	if len(burstEnv) > 0 {
+		burst, err := strconv.Atoi(burstEnv)
+		if err != nil || burst < 1 {
+			klog.Errorf("cannot parse MACHINECONFIG_WRITE_BURST (%s), using %d", burstEnv, machineConfigWriteBurstDefault)
+			return machineConfigWriteBurstDefault
+		}
+		return burst
+	}
+	return machineConfigWriteBurstDefault
+}
+
+// MachineConfigSyncWindow returns the configured or default window during which the repeated
+// regenerations of the same machine config pool are coalesced into a single write.
+func MachineConfigSyncWindow() time.Duration {
+	syncWindow := machineConfigSyncWindowDefault
+	syncWindowEnv := os.Getenv("MACHINECONFIG_SYNC_WINDOW")
+
+	if len(syncWindowEnv) > 0 {
+		var err error
+		syncWindow, err = strconv.ParseInt(syncWindowEnv, 10, 64)
+		if err != nil || syncWindow < 0 {
+			klog.Errorf("cannot parse MACHINECONFIG_SYNC_WINDOW (%s), using %d", syncWindowEnv, machineConfigSyncWindowDefault)
+			syncWindow = machineConfigSyncWindowDefault
+		}
+	}
+	return time.Second * time.Duration(syncWindow)
+}
+
+// AssetsOverrideDir returns the directory whose files override the embedded performance profile
+// component templates, empty when the embedded templates are used as they are.
+func AssetsOverrideDir() string {
+	return os.Getenv("ASSETS_OVERRIDE_DIR")
+}
+
+// AssetsRefreshInterval returns the configured or default interval of the override directory checks.
+func AssetsRefreshInterval() time.Duration {
+	refreshInterval := assetsRefreshIntervalDefault
+	refreshIntervalEnv := os.Getenv("ASSETS_REFRESH_INTERVAL")
+
+	if len(refreshIntervalEnv) > 0 {
+		var err error
+		refreshInterval, err = strconv.ParseInt(refreshIntervalEnv, 10, 64)
+		if err != nil || refreshInterval < 1 {
+			klog.Errorf("cannot parse ASSETS_REFRESH_INTERVAL (%s), using %d", refreshIntervalEnv, assetsRefreshIntervalDefault)
+			refreshInterval = assetsRefreshIntervalDefault
+		}
+	}
+	return time.Second * time.Duration(refreshInterval)
+}
+
+// ReadOnly returns true when the operator computes and reports the artifacts it generates
+// without writing the MachineConfigs, the KubeletConfigs and the Tuneds of the profiles.
+func ReadOnly() bool {
+	return os.Getenv("READ_ONLY") == "true"
+}
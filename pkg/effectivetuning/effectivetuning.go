@@ -0,0 +1,168 @@
+// Package effectivetuning serves the effective tuning of a node, the merged view of the tuned
+// Profile, the performance profile and the configuration generated for the node, so the console
+// plugins and the support tooling do not re-implement the operator recommendation logic.
+package effectivetuning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+)
+
+const (
+	// PathPrefix is the prefix of the effective tuning endpoint path, "/api/v1/nodes/<name>/effective-tuning".
+	PathPrefix = "/api/v1/nodes/"
+	pathSuffix = "/effective-tuning"
+
+	// the machine config daemon annotation naming the rendered machine config the node runs
+	mcdCurrentConfigAnnotation = "machineconfiguration.openshift.io/currentConfig"
+)
+
+// EffectiveTuning is the effective tuning of a node.
+type EffectiveTuning struct {
+	// Node is the name of the node.
+	Node string `json:"node"`
+	// TunedProfile is the TuneD profile the operator recommends for the node.
+	TunedProfile string `json:"tunedProfile,omitempty"`
+	// AppliedTunedProfile is the TuneD profile the operand reports as applied on the node.
+	AppliedTunedProfile string `json:"appliedTunedProfile,omitempty"`
+	// TunedConditions are the conditions the operand reports for the node.
+	TunedConditions []tunedv1.ProfileStatusCondition `json:"tunedConditions,omitempty"`
+	// PerformanceProfile is the name of the performance profile selecting the node, if any.
+	PerformanceProfile string `json:"performanceProfile,omitempty"`
+	// PerformanceProfileSpec is the spec of the performance profile selecting the node, if any.
+	PerformanceProfileSpec *performancev2.PerformanceProfileSpec `json:"performanceProfileSpec,omitempty"`
+	// TunedBootcmdline are the kernel arguments the TuneD profile generates for the node.
+	TunedBootcmdline string `json:"tunedBootcmdline,omitempty"`
+	// KernelArguments are the kernel arguments of the rendered machine config the node runs.
+	KernelArguments []string `json:"kernelArguments,omitempty"`
+	// KubeletConfig are the kubelet fields the performance profile generates for the node.
+	KubeletConfig *runtime.RawExtension `json:"kubeletConfig,omitempty"`
+}
+
+// Get returns the effective tuning of the node 'nodeName', the tuned Profiles are looked up in the namespace 'namespace'.
+// The parts of the configuration not found in the cluster are omitted; only a missing node is an error.
+func Get(ctx context.Context, c client.Reader, nodeName, namespace string) (*EffectiveTuning, error) {
+	node := &corev1.Node{}
+	if err := c.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+		return nil, err
+	}
+	effective := &EffectiveTuning{
+		Node:             node.Name,
+		TunedBootcmdline: node.Annotations[tunedv1.TunedBootcmdlineAnnotationKey],
+	}
+
+	profile := &tunedv1.Profile{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: nodeName}, profile)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		effective.TunedProfile = profile.Spec.Config.TunedProfile
+		effective.AppliedTunedProfile = profile.Status.TunedProfile
+		effective.TunedConditions = profile.Status.Conditions
+	}
+
+	if mcName := node.Annotations[mcdCurrentConfigAnnotation]; mcName != "" {
+		mc := &mcov1.MachineConfig{}
+		err := c.Get(ctx, types.NamespacedName{Name: mcName}, mc)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil {
+			effective.KernelArguments = mc.Spec.KernelArguments
+		}
+	}
+
+	performanceProfile, err := nodePerformanceProfile(ctx, c, node)
+	if err != nil || performanceProfile == nil {
+		return effective, err
+	}
+	effective.PerformanceProfile = performanceProfile.Name
+	effective.PerformanceProfileSpec = &performanceProfile.Spec
+
+	kc := &mcov1.KubeletConfig{}
+	err = c.Get(ctx, types.NamespacedName{Name: components.GetComponentName(performanceProfile.Name, components.ComponentNamePrefix)}, kc)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		effective.KubeletConfig = kc.Spec.KubeletConfig
+	}
+
+	return effective, nil
+}
+
+// nodePerformanceProfile returns the performance profile selecting the node 'node', or nil when no profile selects it.
+func nodePerformanceProfile(ctx context.Context, c client.Reader, node *corev1.Node) (*performancev2.PerformanceProfile, error) {
+	profiles := &performancev2.PerformanceProfileList{}
+	if err := c.List(ctx, profiles); err != nil {
+		return nil, err
+	}
+
+	for i := range profiles.Items {
+		selector := labels.SelectorFromSet(profiles.Items[i].Spec.NodeSelector)
+		if !selector.Empty() && selector.Matches(labels.Set(node.Labels)) {
+			return &profiles.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// NewHandler returns the HTTP handler serving the effective tuning of the nodes at "/api/v1/nodes/<name>/effective-tuning".
+func NewHandler(c client.Reader, namespace string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		nodeName, ok := nodeNameFromPath(r.URL.Path)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		effective, err := Get(r.Context(), c, nodeName, namespace)
+		if errors.IsNotFound(err) {
+			http.Error(w, fmt.Sprintf("node %q not found", nodeName), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			klog.Errorf("failed to get the effective tuning of node %s: %v", nodeName, err)
+			http.Error(w, "failed to get the effective tuning", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(effective); err != nil {
+			klog.Errorf("failed to write the effective tuning of node %s: %v", nodeName, err)
+		}
+	})
+}
+
+// nodeNameFromPath returns the node name of the effective tuning endpoint path 'path'.
+func nodeNameFromPath(path string) (string, bool) {
+	if !strings.HasPrefix(path, PathPrefix) || !strings.HasSuffix(path, pathSuffix) {
+		return "", false
+	}
+	nodeName := strings.TrimSuffix(strings.TrimPrefix(path, PathPrefix), pathSuffix)
+	if nodeName == "" || strings.Contains(nodeName, "/") {
+		return "", false
+	}
+	return nodeName, true
+}
@@ -0,0 +1,112 @@
+package effectivetuning
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+)
+
+const testNamespace = "openshift-cluster-node-tuning-operator"
+
+func newTestHandler(t *testing.T) http.Handler {
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme, tunedv1.AddToScheme, mcov1.AddToScheme, performancev2.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "worker-0",
+		Labels: map[string]string{"node-role.kubernetes.io/worker-cnf": ""},
+		Annotations: map[string]string{
+			tunedv1.TunedBootcmdlineAnnotationKey: "skew_tick=1",
+			mcdCurrentConfigAnnotation:            "rendered-worker-cnf-1",
+		},
+	}}
+	plainNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}}
+	profile := &tunedv1.Profile{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Namespace: testNamespace},
+		Spec:       tunedv1.ProfileSpec{Config: tunedv1.ProfileConfig{TunedProfile: "openshift-node-performance-test"}},
+		Status:     tunedv1.ProfileStatus{TunedProfile: "openshift-node"},
+	}
+	mc := &mcov1.MachineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "rendered-worker-cnf-1"},
+		Spec:       mcov1.MachineConfigSpec{KernelArguments: []string{"nohz_full=2-7"}},
+	}
+	performanceProfile := &performancev2.PerformanceProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec:       performancev2.PerformanceProfileSpec{NodeSelector: map[string]string{"node-role.kubernetes.io/worker-cnf": ""}},
+	}
+	kc := &mcov1.KubeletConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "performance-test"},
+		Spec:       mcov1.KubeletConfigSpec{KubeletConfig: &runtime.RawExtension{Raw: []byte(`{"reservedSystemCPUs":"0-1"}`)}},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, plainNode, profile, mc, performanceProfile, kc).Build()
+	return NewHandler(c, testNamespace)
+}
+
+func TestHandler(t *testing.T) {
+	handler := newTestHandler(t)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/api/v1/nodes/worker-0/effective-tuning")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	effective := &EffectiveTuning{}
+	if err := json.Unmarshal(rec.Body.Bytes(), effective); err != nil {
+		t.Fatal(err)
+	}
+	if effective.TunedProfile != "openshift-node-performance-test" || effective.AppliedTunedProfile != "openshift-node" {
+		t.Errorf("unexpected TuneD profiles %q and %q", effective.TunedProfile, effective.AppliedTunedProfile)
+	}
+	if effective.PerformanceProfile != "test" || effective.PerformanceProfileSpec == nil {
+		t.Errorf("expected the performance profile test, got %q", effective.PerformanceProfile)
+	}
+	if effective.TunedBootcmdline != "skew_tick=1" || !reflect.DeepEqual(effective.KernelArguments, []string{"nohz_full=2-7"}) {
+		t.Errorf("unexpected kernel arguments %q and %v", effective.TunedBootcmdline, effective.KernelArguments)
+	}
+	if effective.KubeletConfig == nil || string(effective.KubeletConfig.Raw) != `{"reservedSystemCPUs":"0-1"}` {
+		t.Errorf("unexpected kubelet config %v", effective.KubeletConfig)
+	}
+
+	rec = get("/api/v1/nodes/worker-1/effective-tuning")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	effective = &EffectiveTuning{}
+	if err := json.Unmarshal(rec.Body.Bytes(), effective); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(effective, &EffectiveTuning{Node: "worker-1"}) {
+		t.Errorf("expected only the node name for the untuned node, got %+v", effective)
+	}
+
+	if rec := get("/api/v1/nodes/missing/effective-tuning"); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for a missing node, got %d", http.StatusNotFound, rec.Code)
+	}
+	if rec := get("/api/v1/nodes/worker-0/other"); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for an unknown path, got %d", http.StatusNotFound, rec.Code)
+	}
+}
@@ -45,7 +45,14 @@ func TunedRenderedResource(tunedSlice []*tunedv1.Tuned) *tunedv1.Tuned {
 			// Skip the "rendered" Tuned resource itself
 			continue
 		}
+		if tuned.Name == tunedv1.TunedDefaultResourceName {
+			// The operand enforces the sysctl policy of the default Tuned resource
+			cr.Spec.SysctlPolicy = tuned.Spec.SysctlPolicy.DeepCopy()
+		}
 		tunedRenderedProfiles(tuned, m)
+		for _, verification := range tuned.Spec.Verification {
+			cr.Spec.Verification = append(cr.Spec.Verification, *verification.DeepCopy())
+		}
 	}
 	for _, tunedProfile := range m {
 		if tunedProfile.Name == nil {
@@ -56,14 +63,23 @@ func TunedRenderedResource(tunedSlice []*tunedv1.Tuned) *tunedv1.Tuned {
 	}
 
 	// The order of Tuned resources is variable and so is the order of profiles
-	// within the resource itself.  Sort the rendered profiles by their names for
-	// simpler change detection.
+	// within the resource itself.  Sort the rendered profiles by their names and
+	// architectures for simpler change detection.
 	sort.Slice(tunedProfiles, func(i, j int) bool {
-		return *tunedProfiles[i].Name < *tunedProfiles[j].Name
+		return tunedProfileKey(tunedProfiles[i]) < tunedProfileKey(tunedProfiles[j])
 	})
 
 	cr.Spec.Profile = tunedProfiles
 
+	// Sort the checks by the Tuned profile they verify and their names for the same reason.
+	sort.SliceStable(cr.Spec.Verification, func(i, j int) bool {
+		vi, vj := cr.Spec.Verification[i], cr.Spec.Verification[j]
+		if vi.Profile != vj.Profile {
+			return vi.Profile < vj.Profile
+		}
+		return vi.Name < vj.Name
+	})
+
 	return cr
 }
 
@@ -127,18 +143,28 @@ func NewTuned(manifest io.Reader) (*tunedv1.Tuned, error) {
 	return &o, nil
 }
 
+// tunedProfileKey returns the key identifying the Tuned profile 'profile' variant: the
+// profile name for the variant without arch, or else the profile name and the architecture.
+func tunedProfileKey(profile tunedv1.TunedProfile) string {
+	if profile.Arch == nil || *profile.Arch == "" {
+		return *profile.Name
+	}
+	return *profile.Name + "/" + *profile.Arch
+}
+
 func tunedRenderedProfiles(tuned *tunedv1.Tuned, m map[string]tunedv1.TunedProfile) {
 	if tuned.Spec.Profile != nil {
 		for _, v := range tuned.Spec.Profile {
 			if v.Name != nil && v.Data != nil {
-				if existingProfile, found := m[*v.Name]; found {
+				key := tunedProfileKey(v)
+				if existingProfile, found := m[key]; found {
 					if *v.Data == *existingProfile.Data {
-						klog.Infof("duplicate profiles names %s but they have the same contents", *v.Name)
+						klog.Infof("duplicate profiles names %s but they have the same contents", key)
 					} else {
-						klog.Errorf("ERROR: duplicate profiles named %s with different contents found in Tuned CR %q", *v.Name, tuned.Name)
+						klog.Errorf("ERROR: duplicate profiles named %s with different contents found in Tuned CR %q", key, tuned.Name)
 					}
 				}
-				m[*v.Name] = v
+				m[key] = v
 			}
 		}
 	}
@@ -1,6 +1,10 @@
 package metrics
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 // When adding metric names, see https://prometheus.io/docs/practices/naming/#metric-names
 const (
@@ -8,6 +12,13 @@ const (
 	profileCalculatedQuery = "nto_profile_calculated_total"
 	buildInfoQuery         = "nto_build_info"
 	degradedInfoQuery      = "nto_degraded_info"
+	poolRebootPendingQuery = "nto_pool_reboot_pending"
+
+	performanceProfileReconcileDurationQuery   = "nto_performance_profile_reconcile_duration_seconds"
+	performanceProfileLastReconcileQuery       = "nto_performance_profile_last_reconcile_success"
+	performanceProfileLastReconcileTimeQuery   = "nto_performance_profile_last_reconcile_timestamp_seconds"
+	performanceProfileArtifactsQuery           = "nto_performance_profile_artifacts"
+	performanceProfileWebhookDenialsTotalQuery = "nto_performance_profile_webhook_denials_total"
 
 	// MetricsPort is the IP port supplied to the HTTP server used for Prometheus,
 	// and matches what is specified in the corresponding Service and ServiceMonitor.
@@ -42,6 +53,49 @@ var (
 			Help: "Indicates whether the Node Tuning Operator is degraded.",
 		},
 	)
+	poolRebootPending = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: poolRebootPendingQuery,
+			Help: "Indicates whether nodes of the machine config pool still need to reboot into the configuration generated for the performance profile.",
+		},
+		[]string{"profile", "pool"},
+	)
+	performanceProfileReconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    performanceProfileReconcileDurationQuery,
+			Help:    "The duration of the performance profile reconcile loops by result.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+		},
+		[]string{"result"},
+	)
+	performanceProfileLastReconcile = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: performanceProfileLastReconcileQuery,
+			Help: "Indicates whether the last reconcile loop of the performance profile succeeded (1) or failed (0).",
+		},
+		[]string{"profile"},
+	)
+	performanceProfileLastReconcileTime = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: performanceProfileLastReconcileTimeQuery,
+			Help: "The time of the last reconcile loop of the performance profile in seconds since the epoch.",
+		},
+		[]string{"profile"},
+	)
+	performanceProfileArtifacts = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: performanceProfileArtifactsQuery,
+			Help: "The number of the artifacts the performance profile manages by kind.",
+		},
+		[]string{"profile", "kind"},
+	)
+	performanceProfileWebhookDenials = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: performanceProfileWebhookDenialsTotalQuery,
+			Help: "The number of the performance profile creations and updates the admission webhook denied by reason.",
+		},
+		[]string{"reason"},
+	)
 )
 
 func init() {
@@ -50,6 +104,12 @@ func init() {
 		profileCalculated,
 		buildInfo,
 		degradedState,
+		poolRebootPending,
+		performanceProfileReconcileDuration,
+		performanceProfileLastReconcile,
+		performanceProfileLastReconcileTime,
+		performanceProfileArtifacts,
+		performanceProfileWebhookDenials,
 	)
 }
 
@@ -83,3 +143,57 @@ func Degraded(deg bool) {
 	}
 	degradedState.Set(0)
 }
+
+// PoolRebootPending sets the metric that indicates whether the nodes of the
+// machine config pool 'poolName' targeted by the performance profile 'profileName'
+// still need to reboot into the configuration generated for the profile.
+func PoolRebootPending(profileName, poolName string, pending bool) {
+	// the profile may have moved from another pool
+	poolRebootPending.DeletePartialMatch(map[string]string{"profile": profileName})
+	if pending {
+		poolRebootPending.WithLabelValues(profileName, poolName).Set(1)
+		return
+	}
+	poolRebootPending.WithLabelValues(profileName, poolName).Set(0)
+}
+
+// DeletePoolRebootPending removes the reboot pending metric of the performance
+// profile 'profileName'.
+func DeletePoolRebootPending(profileName string) {
+	poolRebootPending.DeletePartialMatch(map[string]string{"profile": profileName})
+}
+
+// PerformanceProfileReconciled records the duration and the outcome of the
+// reconcile loop of the performance profile 'profileName'.
+func PerformanceProfileReconciled(profileName string, duration time.Duration, err error) {
+	result, success := "success", 1.0
+	if err != nil {
+		result, success = "error", 0.0
+	}
+	performanceProfileReconcileDuration.WithLabelValues(result).Observe(duration.Seconds())
+	performanceProfileLastReconcile.WithLabelValues(profileName).Set(success)
+	performanceProfileLastReconcileTime.WithLabelValues(profileName).SetToCurrentTime()
+}
+
+// PerformanceProfileArtifacts sets the number of the artifacts the performance
+// profile 'profileName' manages, keyed by the artifact kind.
+func PerformanceProfileArtifacts(profileName string, artifacts map[string]int) {
+	performanceProfileArtifacts.DeletePartialMatch(map[string]string{"profile": profileName})
+	for kind, count := range artifacts {
+		performanceProfileArtifacts.WithLabelValues(profileName, kind).Set(float64(count))
+	}
+}
+
+// DeletePerformanceProfile removes the metrics of the performance profile 'profileName'.
+func DeletePerformanceProfile(profileName string) {
+	DeletePoolRebootPending(profileName)
+	performanceProfileLastReconcile.DeleteLabelValues(profileName)
+	performanceProfileLastReconcileTime.DeleteLabelValues(profileName)
+	performanceProfileArtifacts.DeletePartialMatch(map[string]string{"profile": profileName})
+}
+
+// PerformanceProfileWebhookDenied counts the performance profile creation or
+// update the admission webhook denied for 'reason'.
+func PerformanceProfileWebhookDenied(reason string) {
+	performanceProfileWebhookDenials.WithLabelValues(reason).Inc()
+}
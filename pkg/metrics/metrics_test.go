@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPerformanceProfileMetrics(t *testing.T) {
+	PerformanceProfileReconciled("test", time.Second, nil)
+	PerformanceProfileArtifacts("test", map[string]int{"MachineConfig": 1, "Tuned": 1})
+	PerformanceProfileWebhookDenied("FieldValueInvalid")
+
+	if got := testutil.ToFloat64(performanceProfileLastReconcile.WithLabelValues("test")); got != 1 {
+		t.Errorf("last reconcile success %v, expected 1", got)
+	}
+	if got := testutil.CollectAndCount(performanceProfileArtifacts); got != 2 {
+		t.Errorf("%d artifacts series, expected 2", got)
+	}
+
+	PerformanceProfileReconciled("test", time.Second, fmt.Errorf("failed"))
+	if got := testutil.ToFloat64(performanceProfileLastReconcile.WithLabelValues("test")); got != 0 {
+		t.Errorf("last reconcile success %v, expected 0", got)
+	}
+
+	DeletePerformanceProfile("test")
+	for name, count := range map[string]int{
+		performanceProfileLastReconcileQuery:     testutil.CollectAndCount(performanceProfileLastReconcile),
+		performanceProfileLastReconcileTimeQuery: testutil.CollectAndCount(performanceProfileLastReconcileTime),
+		performanceProfileArtifactsQuery:         testutil.CollectAndCount(performanceProfileArtifacts),
+	} {
+		if count != 0 {
+			t.Errorf("%d %s series left after the profile deletion", count, name)
+		}
+	}
+
+	problems, err := testutil.GatherAndLint(registry)
+	if err != nil {
+		t.Fatalf("failed to gather the metrics: %v", err)
+	}
+	for _, problem := range problems {
+		t.Errorf("metric %s: %s", problem.Metric, problem.Text)
+	}
+}
@@ -36,6 +36,16 @@ const (
 type Server struct {
 }
 
+// handlers are the additional handlers served next to the metrics by the pattern,
+// they share the TLS client certificate authentication of the metrics.
+var handlers = map[string]http.Handler{}
+
+// Handle registers the handler 'handler' for the pattern 'pattern' on the metrics server.
+// The handlers must be registered before the metrics server starts.
+func Handle(pattern string, handler http.Handler) {
+	handlers[pattern] = handler
+}
+
 // DumpCA writes the root certificate bundle which is used to verify client certificates
 // on incoming requests to 'authCAFile' file.
 func DumpCA(ca string) error {
@@ -100,6 +110,9 @@ func buildServer(port int) *http.Server {
 	bindAddr := fmt.Sprintf(":%d", port)
 	router := http.NewServeMux()
 	router.Handle("/metrics", handler)
+	for pattern, h := range handlers {
+		router.Handle(pattern, h)
+	}
 	srv := &http.Server{
 		Addr:      bindAddr,
 		Handler:   router,
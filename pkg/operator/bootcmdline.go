@@ -0,0 +1,138 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	ntoconfig "github.com/openshift/cluster-node-tuning-operator/pkg/config"
+	tunedpkg "github.com/openshift/cluster-node-tuning-operator/pkg/tuned"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
+
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+)
+
+const (
+	// bootcmdlineDeduplicatedReason is the TunedBootcmdlineMerged condition reason reported when
+	// other MachineConfigs of the pool already pass some of the TuneD kernel arguments.
+	bootcmdlineDeduplicatedReason = "Deduplicated"
+	// bootcmdlineConflictReason is the TunedBootcmdlineMerged condition reason reported when
+	// other MachineConfigs of the pool set some of the TuneD kernel parameters to other values.
+	bootcmdlineConflictReason = "Conflict"
+)
+
+// getKernelArgumentsForPool returns the kernel arguments of the MachineConfigs MachineConfigPool 'pool'
+// selects by MachineConfig name, except the operator-created MachineConfig 'exclude'.
+func (pc *ProfileCalculator) getKernelArgumentsForPool(pool *mcfgv1.MachineConfigPool, exclude string) (map[string][]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(pool.Spec.MachineConfigSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %s in MachineConfigPool %s: %v", util.ObjectInfo(selector), pool.ObjectMeta.Name, err)
+	}
+
+	// A pool with a nil or empty selector matches nothing.
+	if selector.Empty() {
+		return nil, nil
+	}
+
+	mcs, err := pc.listers.MachineConfigs.List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	kernelArguments := map[string][]string{}
+	for _, mc := range mcs {
+		if mc.ObjectMeta.Name == exclude || len(mc.Spec.KernelArguments) == 0 {
+			continue
+		}
+		// MachineConfig kernelArguments items may hold several space-separated kernel arguments.
+		for _, args := range mc.Spec.KernelArguments {
+			kernelArguments[mc.ObjectMeta.Name] = append(kernelArguments[mc.ObjectMeta.Name], util.SplitKernelArguments(args)...)
+		}
+	}
+
+	return kernelArguments, nil
+}
+
+// syncBootcmdlineStatusCondition reports the TuneD kernel arguments of Profile 'profile' other MachineConfigs
+// of the pool own ('owners') and the ones conflicting with them ('conflicts') by the TunedBootcmdlineMerged
+// condition of the Profile.  The condition is removed once the kernel arguments no longer overlap.
+func (c *Controller) syncBootcmdlineStatusCondition(profile *tunedv1.Profile, owners map[string]string, conflicts []util.KernelArgumentConflict) error {
+	conditions := setBootcmdlineMergedStatusCondition(profile.Status.Conditions, owners, conflicts)
+	if reflect.DeepEqual(conditions, profile.Status.Conditions) {
+		return nil
+	}
+
+	profile = profile.DeepCopy() // never update the objects from cache
+	profile.Status.Conditions = conditions
+	_, err := c.clients.Tuned.TunedV1().Profiles(ntoconfig.WatchNamespace()).UpdateStatus(context.TODO(), profile, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update Profile %s status: %v", profile.Name, err)
+	}
+	klog.Infof("updated Profile %s kernel arguments overlap with other MachineConfigs: %d owned, %d conflicting", profile.Name, len(owners), len(conflicts))
+
+	return nil
+}
+
+// setBootcmdlineMergedStatusCondition returns 'conditions' with the TunedBootcmdlineMerged condition reporting
+// the kernel argument 'conflicts' or, without conflicts, the kernel arguments other MachineConfigs own ('owners').
+// Without overlap, it returns 'conditions' without the condition.
+func setBootcmdlineMergedStatusCondition(conditions []tunedv1.ProfileStatusCondition, owners map[string]string, conflicts []util.KernelArgumentConflict) []tunedv1.ProfileStatusCondition {
+	if len(conflicts) > 0 {
+		var sb strings.Builder
+		for i, conflict := range conflicts {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(&sb, "%s (%s in MachineConfig %s)", conflict.Argument, conflict.Existing, conflict.Owner)
+		}
+		return tunedpkg.SetStatusCondition(conditions, &tunedv1.ProfileStatusCondition{
+			Type:    tunedv1.TunedBootcmdlineMerged,
+			Status:  corev1.ConditionFalse,
+			Reason:  bootcmdlineConflictReason,
+			Message: fmt.Sprintf("The TuneD kernel argument(s) %s conflict with other MachineConfigs of the pool and were not added.", sb.String()),
+		})
+	}
+
+	if len(owners) > 0 {
+		args := make([]string, 0, len(owners))
+		for arg := range owners {
+			args = append(args, arg)
+		}
+		sort.Strings(args)
+
+		var sb strings.Builder
+		for i, arg := range args {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(&sb, "%s (MachineConfig %s)", arg, owners[arg])
+		}
+		return tunedpkg.SetStatusCondition(conditions, &tunedv1.ProfileStatusCondition{
+			Type:    tunedv1.TunedBootcmdlineMerged,
+			Status:  corev1.ConditionTrue,
+			Reason:  bootcmdlineDeduplicatedReason,
+			Message: fmt.Sprintf("The TuneD kernel argument(s) %s are passed by other MachineConfigs of the pool and were not added again.", sb.String()),
+		})
+	}
+
+	found := false
+	newConditions := []tunedv1.ProfileStatusCondition{}
+	for _, c := range conditions {
+		if c.Type == tunedv1.TunedBootcmdlineMerged {
+			found = true
+			continue
+		}
+		newConditions = append(newConditions, c)
+	}
+	if !found {
+		return conditions
+	}
+	return newConditions
+}
@@ -0,0 +1,103 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	ntoconfig "github.com/openshift/cluster-node-tuning-operator/pkg/config"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
+)
+
+// getCmdlineManifest returns the expected kernel command line of the rendered MachineConfigs
+// the MachineConfigPools currently target or roll out, keyed by the MachineConfig name.
+func (c *Controller) getCmdlineManifest() (map[string]string, error) {
+	pools, err := c.listers.MachineConfigPools.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]string{}
+	for _, pool := range pools {
+		for _, mcName := range []string{pool.Spec.Configuration.Name, pool.Status.Configuration.Name} {
+			if _, ok := data[mcName]; mcName == "" || ok {
+				continue
+			}
+			mc, err := c.listers.MachineConfigs.Get(mcName)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					// The MCO has not created the rendered MachineConfig yet or already garbage-collected it.
+					continue
+				}
+				return nil, err
+			}
+
+			// MachineConfig kernelArguments items may hold several space-separated kernel arguments.
+			kernelArguments := []string{}
+			for _, args := range mc.Spec.KernelArguments {
+				kernelArguments = append(kernelArguments, util.SplitKernelArguments(args)...)
+			}
+			entry, err := json.Marshal(util.NewKernelCmdlineManifestEntry(kernelArguments))
+			if err != nil {
+				return nil, err
+			}
+			data[mcName] = string(entry)
+		}
+	}
+
+	return data, nil
+}
+
+// syncCmdlineManifest publishes the expected kernel command line of the rendered MachineConfigs
+// of the pools for the operands to detect Nodes booted with untracked kernel command line modifications.
+func (c *Controller) syncCmdlineManifest(tuned *tunedv1.Tuned) error {
+	data, err := c.getCmdlineManifest()
+	if err != nil {
+		return fmt.Errorf("failed to get the kernel command line manifest: %v", err)
+	}
+
+	cmClient := c.clients.Kube.CoreV1().ConfigMaps(ntoconfig.WatchNamespace())
+	cm, err := cmClient.Get(context.TODO(), tunedv1.TunedCmdlineManifestConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get ConfigMap %s: %v", tunedv1.TunedCmdlineManifestConfigMapName, err)
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            tunedv1.TunedCmdlineManifestConfigMapName,
+				Namespace:       ntoconfig.WatchNamespace(),
+				OwnerReferences: getDefaultTunedRefs(tuned),
+			},
+			Data: data,
+		}
+		_, err = cmClient.Create(context.TODO(), cm, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create ConfigMap %s: %v", cm.Name, err)
+		}
+		klog.Infof("created ConfigMap %s with the kernel command line of %d rendered MachineConfig(s)", cm.Name, len(data))
+		return nil
+	}
+
+	if len(cm.Data) == 0 && len(data) == 0 || reflect.DeepEqual(cm.Data, data) {
+		klog.V(2).Infof("syncCmdlineManifest(): ConfigMap %s doesn't need updating", cm.Name)
+		return nil
+	}
+
+	cm = cm.DeepCopy() // never update the objects from cache
+	cm.Data = data
+	_, err = cmClient.Update(context.TODO(), cm, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update ConfigMap %s: %v", cm.Name, err)
+	}
+	klog.Infof("updated ConfigMap %s with the kernel command line of %d rendered MachineConfig(s)", cm.Name, len(data))
+
+	return nil
+}
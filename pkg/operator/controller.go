@@ -25,6 +25,8 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	configapiv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
 	configclientset "github.com/openshift/client-go/config/clientset/versioned"
@@ -39,6 +41,7 @@ import (
 	tunedinformers "github.com/openshift/cluster-node-tuning-operator/pkg/generated/informers/externalversions"
 	ntomf "github.com/openshift/cluster-node-tuning-operator/pkg/manifests"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/metrics"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/tracing"
 	tunedpkg "github.com/openshift/cluster-node-tuning-operator/pkg/tuned"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
 	"github.com/openshift/cluster-node-tuning-operator/version"
@@ -61,6 +64,8 @@ const (
 	wqKindProfile           = "profile"
 	wqKindConfigMap         = "configmap"
 	wqKindMachineConfigPool = "machineconfigpool"
+	wqKindTunedStatus       = "tunedstatus"
+	wqKindSchedule          = "schedule"
 )
 
 // Controller is the controller implementation for Tuned resources
@@ -87,6 +92,11 @@ type Controller struct {
 	// tracked as having kernel command-line conflict due to belonging
 	// to the same MCP.
 	bootcmdlineConflict map[string]bool
+
+	// nodePoolProfileConfigMaps are the names of the ConfigMaps wrapping the
+	// MachineConfigs and KubeletConfigs rendered from the NodePool
+	// PerformanceProfiles in HyperShift.
+	nodePoolProfileConfigMaps map[string]bool
 }
 
 type wqKey struct {
@@ -199,7 +209,13 @@ func (c *Controller) eventProcessor() {
 				return
 			}
 
-			if err := c.sync(workqueueKey); err != nil {
+			ctx, span := tracing.Start(context.Background(), "Tuned.sync",
+				attribute.String("key.kind", workqueueKey.kind),
+				attribute.String("key.namespace", workqueueKey.namespace),
+				attribute.String("key.name", workqueueKey.name))
+			err := c.sync(ctx, workqueueKey)
+			tracing.End(span, err)
+			if err != nil {
 				requeued := c.workqueue.NumRequeues(workqueueKey)
 				// Limit retries to maxRetries.  After that, stop trying.
 				if requeued < maxRetries {
@@ -222,7 +238,7 @@ func (c *Controller) eventProcessor() {
 	}
 }
 
-func (c *Controller) sync(key wqKey) error {
+func (c *Controller) sync(ctx context.Context, key wqKey) error {
 	var (
 		cr           *tunedv1.Tuned
 		err, lastErr error
@@ -305,7 +321,8 @@ func (c *Controller) sync(key wqKey) error {
 			// Trigger a Profile update
 			c.workqueue.AddRateLimited(wqKey{kind: wqKindProfile, namespace: ntoconfig.WatchNamespace(), name: key.name})
 		}
-		return nil
+		// A Node reboot changes the boot ID the Profile convergence is checked against.
+		return c.syncNodeTaint(cr, key.name)
 
 	case key.kind == wqKindConfigMap && key.namespace == metrics.AuthConfigMapNamespace:
 		klog.V(2).Infof("sync(): wqKindConfigMap %s: %s/%s", key.kind, key.namespace, key.name)
@@ -350,6 +367,13 @@ func (c *Controller) sync(key wqKey) error {
 			return err
 		}
 
+		// MachineConfigPool changes may mean new rendered MachineConfigs the operands need to know
+		// the expected kernel command line of.
+		err = c.syncCmdlineManifest(cr)
+		if err != nil {
+			return err
+		}
+
 		// MachineConfigPool changes can affect all nodes and MCP is where cluster admins
 		// will adjust the operator behavior when using the MachineConfig functionality.
 		// Nodes can become part of the pool or they can lose the pool membership.
@@ -374,14 +398,36 @@ func (c *Controller) sync(key wqKey) error {
 		}
 		return nil
 
+	case key.kind == wqKindTunedStatus:
+		klog.V(2).Infof("sync(): Tuned %s status", key.name)
+
+		err = c.syncTunedStatus(cr)
+		if err != nil {
+			return fmt.Errorf("failed to sync Tuned %s status: %v", key.name, err)
+		}
+		return nil
+
+	case key.kind == wqKindSchedule:
+		klog.V(2).Infof("sync(): recommend rules time window boundary")
+
+		// a time window of the recommend rules started or ended, recalculate all profiles
+		err = c.enqueueProfileUpdates()
+		if err != nil {
+			return err
+		}
+		return c.enqueueScheduleBoundary()
+
 	case key.kind == wqKindProfile:
 		klog.V(2).Infof("sync(): Profile %s", key.name)
 
-		err = c.syncProfile(cr, key.name)
+		// the Profile status changes are summarized in the default Tuned status
+		c.enqueueTunedStatus()
+
+		err = c.syncProfile(ctx, cr, key.name)
 		if err != nil {
 			return fmt.Errorf("failed to sync Profile %s: %v", key.name, err)
 		}
-		return nil
+		return c.syncNodeTaint(cr, key.name)
 
 	default:
 	}
@@ -409,8 +455,16 @@ func (c *Controller) sync(key wqKey) error {
 			if !errors.IsNotFound(err) {
 				return err
 			}
-		} else if crTuned.Spec.ManagementState != "" {
-			klog.Warningf("setting ManagementState is supported only in Tuned/%s; ignoring ManagementState in Tuned/%s", tunedv1.TunedDefaultResourceName, key.name)
+		} else {
+			if crTuned.Spec.ManagementState != "" {
+				klog.Warningf("setting ManagementState is supported only in Tuned/%s; ignoring ManagementState in Tuned/%s", tunedv1.TunedDefaultResourceName, key.name)
+			}
+			if crTuned.Spec.SysctlPolicy != nil && key.name != tunedv1.TunedRenderedResourceName {
+				klog.Warningf("setting sysctlPolicy is supported only in Tuned/%s; ignoring sysctlPolicy in Tuned/%s", tunedv1.TunedDefaultResourceName, key.name)
+			}
+			if crTuned.Spec.NodeTaintManagement != "" {
+				klog.Warningf("setting nodeTaintManagement is supported only in Tuned/%s; ignoring nodeTaintManagement in Tuned/%s", tunedv1.TunedDefaultResourceName, key.name)
+			}
 		}
 	}
 
@@ -428,6 +482,11 @@ func (c *Controller) sync(key wqKey) error {
 		return err
 	}
 
+	err = c.enqueueScheduleBoundary()
+	if err != nil {
+		return err
+	}
+
 	if key.name == tunedv1.TunedRenderedResourceName {
 		// Do not start unused MachineConfig pruning unnecessarily for the rendered resource
 		return nil
@@ -478,12 +537,34 @@ func (c *Controller) enqueueProfileUpdates() error {
 	return nil
 }
 
+// enqueueScheduleBoundary enqueues the profile recalculations at the next start or end of a time window
+// of the recommend rules.  The earlier boundary replaces the boundary enqueued before.
+func (c *Controller) enqueueScheduleBoundary() error {
+	tunedList, err := c.listers.TunedResources.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list Tuned: %v", err)
+	}
+
+	now := time.Now()
+	next, ok := nextScheduleBoundary(TunedRecommend(tunedList), now)
+	if !ok {
+		return nil
+	}
+	klog.V(2).Infof("next recommend rules time window boundary at %s", next)
+	c.workqueue.AddAfter(wqKey{kind: wqKindSchedule}, next.Sub(now))
+	return nil
+}
+
 func (c *Controller) syncTunedDefault() (*tunedv1.Tuned, error) {
 	crMf := ntomf.TunedCustomResource()
 
 	cr, err := c.listers.TunedResources.Get(tunedv1.TunedDefaultResourceName)
 	if err != nil {
 		if errors.IsNotFound(err) {
+			if ntoconfig.ReadOnly() {
+				// The operands are owned by the default Tuned, nothing can be synced without it.
+				return nil, fmt.Errorf("read-only mode, not creating the missing Tuned %s", tunedv1.TunedDefaultResourceName)
+			}
 			klog.V(2).Infof("syncTunedDefault(): Tuned %s not found, creating one", tunedv1.TunedDefaultResourceName)
 			cr, err = c.clients.Tuned.TunedV1().Tuneds(ntoconfig.WatchNamespace()).Create(context.TODO(), crMf, metav1.CreateOptions{})
 			if err != nil {
@@ -502,6 +583,10 @@ func (c *Controller) syncTunedDefault() (*tunedv1.Tuned, error) {
 		klog.V(2).Infof("syncTunedDefault(): Tuned %s doesn't need updating", crMf.Name)
 		return cr, nil
 	}
+	if ntoconfig.ReadOnly() {
+		klog.Infof("read-only mode, not updating Tuned %s differing from the default profiles and recommendations", crMf.Name)
+		return cr, nil
+	}
 	cr = cr.DeepCopy() // never update the objects from cache
 	cr.Spec.Profile = crMf.Spec.Profile
 	cr.Spec.Recommend = crMf.Spec.Recommend
@@ -524,8 +609,9 @@ func (c *Controller) syncTunedRendered(tuned *tunedv1.Tuned) error {
 	crMf.ObjectMeta.OwnerReferences = getDefaultTunedRefs(tuned)
 	crMf.Name = tunedv1.TunedRenderedResourceName
 
+	// The node taint management needs the Node events as well.
 	nodeLabelsUsed := c.pc.tunedsUseNodeLabels(tunedList)
-	if err = c.enableNodeInformer(nodeLabelsUsed); err != nil {
+	if err = c.enableNodeInformer(nodeLabelsUsed || tuned.Spec.NodeTaintManagement == tunedv1.NodeTaintManagementManaged); err != nil {
 		return fmt.Errorf("failed to enable Node informer: %v", err)
 	}
 
@@ -539,6 +625,10 @@ func (c *Controller) syncTunedRendered(tuned *tunedv1.Tuned) error {
 	cr, err := c.listers.TunedResources.Get(tunedv1.TunedRenderedResourceName)
 	if err != nil {
 		if errors.IsNotFound(err) {
+			if ntoconfig.ReadOnly() {
+				klog.Infof("read-only mode, not creating Tuned %s", crMf.Name)
+				return nil
+			}
 			klog.V(2).Infof("syncTunedRendered(): Tuned %s not found, creating one", crMf.Name)
 			_, err = c.clients.Tuned.TunedV1().Tuneds(ntoconfig.WatchNamespace()).Create(context.TODO(), crMf, metav1.CreateOptions{})
 			if err != nil {
@@ -551,10 +641,16 @@ func (c *Controller) syncTunedRendered(tuned *tunedv1.Tuned) error {
 		return fmt.Errorf("failed to get Tuned %s: %v", tunedv1.TunedRenderedResourceName, err)
 	}
 
-	if reflect.DeepEqual(crMf.Spec.Profile, cr.Spec.Profile) {
+	if reflect.DeepEqual(crMf.Spec.Profile, cr.Spec.Profile) &&
+		reflect.DeepEqual(crMf.Spec.SysctlPolicy, cr.Spec.SysctlPolicy) &&
+		reflect.DeepEqual(crMf.Spec.Verification, cr.Spec.Verification) {
 		klog.V(2).Infof("syncTunedRendered(): Tuned %s doesn't need updating", crMf.Name)
 		return nil
 	}
+	if ntoconfig.ReadOnly() {
+		klog.Infof("read-only mode, not updating Tuned %s differing from the Tuned CRs", cr.Name)
+		return nil
+	}
 	cr = cr.DeepCopy() // never update the objects from cache
 	cr.Spec = crMf.Spec
 
@@ -624,12 +720,13 @@ func (c *Controller) syncDaemonSet(tuned *tunedv1.Tuned) error {
 	return nil
 }
 
-func (c *Controller) syncProfile(tuned *tunedv1.Tuned, nodeName string) error {
+func (c *Controller) syncProfile(ctx context.Context, tuned *tunedv1.Tuned, nodeName string) error {
 	var (
 		tunedProfileName string
 		mcLabels         map[string]string
 		operand          tunedv1.OperandConfig
 		nodePoolName     string
+		deferred         bool
 	)
 	profileMf := ntomf.TunedProfile()
 	profileMf.ObjectMeta.OwnerReferences = getDefaultTunedRefs(tuned)
@@ -660,24 +757,48 @@ func (c *Controller) syncProfile(tuned *tunedv1.Tuned, nodeName string) error {
 		return nil
 	}
 
-	if ntoconfig.InHyperShift() {
-		tunedProfileName, nodePoolName, operand, err = c.pc.calculateProfileHyperShift(nodeName)
+	computeProfile := func() (err error) {
+		_, span := tracing.Start(ctx, "compute", attribute.String("node.name", nodeName))
+		defer func() { tracing.End(span, err) }()
+
+		if c.pc.state.excluded[nodeName] {
+			// The Node is excluded from the pool tuning, fall back to the default profile without
+			// the supplemental profiles and without the MachineConfig synchronization.
+			tunedProfileName, operand, err = c.pc.calculateDefaultProfile(nodeName)
+			c.pc.scheduleSet(nodeName, nil)
+			return err
+		}
+
+		if ntoconfig.InHyperShift() {
+			tunedProfileName, nodePoolName, operand, err = c.pc.calculateProfileHyperShift(nodeName)
+			if err != nil {
+				return err
+			}
+		} else {
+			tunedProfileName, mcLabels, operand, err = c.pc.calculateProfile(nodeName)
+			if err != nil {
+				return err
+			}
+		}
+
+		deferred, err = c.pc.profileDeferred(tunedProfileName)
 		if err != nil {
 			return err
 		}
-	} else {
-		tunedProfileName, mcLabels, operand, err = c.pc.calculateProfile(nodeName)
+
+		supplemental, err := c.pc.supplementalProfiles(nodeName)
 		if err != nil {
 			return err
 		}
+		tunedProfileName = mergeProfiles(tunedProfileName, supplemental)
+		return nil
 	}
-
-	metrics.ProfileCalculated(profileMf.Name, tunedProfileName)
-
-	if err != nil {
-		return fmt.Errorf("failed to get Tuned %s: %v", tunedv1.TunedRenderedResourceName, err)
+	if err = computeProfile(); err != nil {
+		return err
 	}
 
+	metrics.ProfileCalculated(profileMf.Name, tunedv1.PrimaryTunedProfile(tunedProfileName))
+
 	profile, err := c.listers.TunedProfiles.Get(profileMf.Name)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -693,9 +814,16 @@ func (c *Controller) syncProfile(tuned *tunedv1.Tuned, nodeName string) error {
 			klog.V(2).Infof("syncProfile(): Profile %s not found, creating one [%s]", profileMf.Name, tunedProfileName)
 			profileMf.Spec.Config.TunedProfile = tunedProfileName
 			profileMf.Spec.Config.Debug = operand.Debug
+			profileMf.Spec.Config.LogLevel = operand.LogLevel
+			profileMf.Spec.Config.DebugCapture = operand.DebugCapture
 			profileMf.Spec.Config.TuneDConfig = operand.TuneDConfig
+			profileMf.Spec.Config.Hugepages = operand.Hugepages
+			profileMf.Spec.Config.Schedule = c.pc.state.schedules[nodeName]
+			setProfileDeferred(profileMf, deferred)
 			profileMf.Status.Conditions = tunedpkg.InitializeStatusConditions()
-			_, err = c.clients.Tuned.TunedV1().Profiles(ntoconfig.WatchNamespace()).Create(context.TODO(), profileMf, metav1.CreateOptions{})
+			applyCtx, span := tracing.Start(ctx, "apply", attribute.String("artifact.kind", "Profile"), attribute.String("artifact.name", profileMf.Name))
+			_, err = c.clients.Tuned.TunedV1().Profiles(ntoconfig.WatchNamespace()).Create(applyCtx, profileMf, metav1.CreateOptions{})
+			tracing.End(span, err)
 			if err != nil {
 				return fmt.Errorf("failed to create Profile %s: %v", profileMf.Name, err)
 			}
@@ -753,20 +881,32 @@ func (c *Controller) syncProfile(tuned *tunedv1.Tuned, nodeName string) error {
 	// Only update Profile if the spec needs to be changed.
 	if profile.Spec.Config.TunedProfile == tunedProfileName &&
 		profile.Spec.Config.Debug == operand.Debug &&
+		profile.Spec.Config.LogLevel == operand.LogLevel &&
+		reflect.DeepEqual(profile.Spec.Config.DebugCapture, operand.DebugCapture) &&
 		reflect.DeepEqual(profile.Spec.Config.TuneDConfig, operand.TuneDConfig) &&
-		profile.Spec.Config.ProviderName == providerName {
+		reflect.DeepEqual(profile.Spec.Config.Hugepages, operand.Hugepages) &&
+		profile.Spec.Config.ProviderName == providerName &&
+		profile.Spec.Config.Schedule == c.pc.state.schedules[nodeName] &&
+		isProfileDeferred(profile) == deferred {
 		klog.V(2).Infof("syncProfile(): no need to update Profile %s", nodeName)
 		return nil
 	}
 	profile = profile.DeepCopy() // never update the objects from cache
 	profile.Spec.Config.TunedProfile = tunedProfileName
 	profile.Spec.Config.Debug = operand.Debug
+	profile.Spec.Config.LogLevel = operand.LogLevel
+	profile.Spec.Config.DebugCapture = operand.DebugCapture
 	profile.Spec.Config.TuneDConfig = operand.TuneDConfig
+	profile.Spec.Config.Hugepages = operand.Hugepages
 	profile.Spec.Config.ProviderName = providerName
+	profile.Spec.Config.Schedule = c.pc.state.schedules[nodeName]
+	setProfileDeferred(profile, deferred)
 	profile.Status.Conditions = tunedpkg.InitializeStatusConditions()
 
 	klog.V(2).Infof("syncProfile(): updating Profile %s [%s]", profile.Name, tunedProfileName)
-	_, err = c.clients.Tuned.TunedV1().Profiles(ntoconfig.WatchNamespace()).Update(context.TODO(), profile, metav1.UpdateOptions{})
+	applyCtx, span := tracing.Start(ctx, "apply", attribute.String("artifact.kind", "Profile"), attribute.String("artifact.name", profile.Name))
+	_, err = c.clients.Tuned.TunedV1().Profiles(ntoconfig.WatchNamespace()).Update(applyCtx, profile, metav1.UpdateOptions{})
+	tracing.End(span, err)
 	if err != nil {
 		return fmt.Errorf("failed to update Profile %s: %v", profile.Name, err)
 	}
@@ -775,6 +915,24 @@ func (c *Controller) syncProfile(tuned *tunedv1.Tuned, nodeName string) error {
 	return nil
 }
 
+// isProfileDeferred returns true if Profile 'profile' carries the deferred update annotation.
+func isProfileDeferred(profile *tunedv1.Profile) bool {
+	_, ok := profile.ObjectMeta.Annotations[tunedv1.TunedDeferredUpdate]
+	return ok
+}
+
+// setProfileDeferred sets or removes the deferred update annotation on Profile 'profile'.
+func setProfileDeferred(profile *tunedv1.Profile, deferred bool) {
+	if !deferred {
+		delete(profile.ObjectMeta.Annotations, tunedv1.TunedDeferredUpdate)
+		return
+	}
+	if profile.ObjectMeta.Annotations == nil {
+		profile.ObjectMeta.Annotations = map[string]string{}
+	}
+	profile.ObjectMeta.Annotations[tunedv1.TunedDeferredUpdate] = ""
+}
+
 func (c *Controller) getProviderName(nodeName string) (string, error) {
 	node, err := c.listers.Nodes.Get(nodeName)
 	if err != nil {
@@ -786,8 +944,7 @@ func (c *Controller) getProviderName(nodeName string) (string, error) {
 
 func (c *Controller) syncMachineConfig(labels map[string]string, profile *tunedv1.Profile) error {
 	var (
-		bootcmdline     string
-		kernelArguments []string
+		bootcmdline string
 	)
 
 	pools, err := c.pc.getPoolsForMachineConfigLabelsSorted(labels)
@@ -824,7 +981,19 @@ func (c *Controller) syncMachineConfig(labels map[string]string, profile *tunedv
 		return nil
 	}
 
-	kernelArguments = util.SplitKernelArguments(bootcmdline)
+	// Other MachineConfigs of the pool may already pass some of the TuneD kernel arguments, or pass
+	// them with other values.  Leave them out rather than duplicating or contradicting them.
+	poolKernelArguments, err := c.pc.getKernelArgumentsForPool(pools[0], name)
+	if err != nil {
+		return err
+	}
+	kernelArguments, owners, conflicts := util.DeduplicateKernelArguments(util.SplitKernelArguments(bootcmdline), poolKernelArguments)
+	for _, conflict := range conflicts {
+		klog.Warningf("kernel argument %s of Profile %s conflicts with %s of MachineConfig %s, not adding it", conflict.Argument, profile.Name, conflict.Existing, conflict.Owner)
+	}
+	if err := c.syncBootcmdlineStatusCondition(profile, owners, conflicts); err != nil {
+		return err
+	}
 
 	annotations := map[string]string{GeneratedByControllerVersionAnnotationKey: version.Version}
 
@@ -832,13 +1001,17 @@ func (c *Controller) syncMachineConfig(labels map[string]string, profile *tunedv
 	if err != nil {
 		if errors.IsNotFound(err) {
 			klog.V(2).Infof("syncMachineConfig(): MachineConfig %s not found, creating one", name)
-			if len(bootcmdline) == 0 {
+			if len(kernelArguments) == 0 {
 				// Creating a new MachineConfig with empty kernelArguments only causes unnecessary node
 				// reboots.
 				klog.V(2).Infof("not creating a MachineConfig with empty kernelArguments")
 				return nil
 			}
 			mc = NewMachineConfig(name, annotations, labels, kernelArguments)
+			if ntoconfig.ReadOnly() {
+				klog.Infof("read-only mode, not creating MachineConfig %s with%s", mc.ObjectMeta.Name, MachineConfigGenerationLogLine(true, bootcmdline))
+				return nil
+			}
 			_, err = c.clients.MC.MachineconfigurationV1().MachineConfigs().Create(context.TODO(), mc, metav1.CreateOptions{})
 			if err != nil {
 				return fmt.Errorf("failed to create MachineConfig %s: %v", mc.ObjectMeta.Name, err)
@@ -863,6 +1036,10 @@ func (c *Controller) syncMachineConfig(labels map[string]string, profile *tunedv
 	mc.Spec.Config = mcNew.Spec.Config
 
 	l := MachineConfigGenerationLogLine(!kernelArgsEq, bootcmdline)
+	if ntoconfig.ReadOnly() {
+		klog.Infof("read-only mode, not updating MachineConfig %s with%s", mc.ObjectMeta.Name, l)
+		return nil
+	}
 	klog.V(2).Infof("syncMachineConfig(): updating MachineConfig %s with%s", mc.ObjectMeta.Name, l)
 	_, err = c.clients.MC.MachineconfigurationV1().MachineConfigs().Update(context.TODO(), mc, metav1.UpdateOptions{})
 	if err != nil {
@@ -1144,6 +1321,9 @@ func (c *Controller) getConfigMapNamesForTuned() (map[string]bool, error) {
 			cmNames[cmName] = true
 		}
 	}
+	for cmName := range c.nodePoolProfileConfigMaps {
+		cmNames[cmName] = true
+	}
 
 	return cmNames, nil
 }
@@ -1182,6 +1362,13 @@ func (c *Controller) informerEventHandler(workqueueKey wqKey) cache.ResourceEven
 					return
 				}
 			}
+			if oldTuned, ok := o.(*tunedv1.Tuned); ok {
+				newTuned := n.(*tunedv1.Tuned)
+				if oldTuned.Generation == newTuned.Generation && !reflect.DeepEqual(oldTuned.Status, newTuned.Status) {
+					// Don't resync all the Profiles when only the Tuned status summary changed.
+					return
+				}
+			}
 			klog.V(2).Infof("add event to workqueue due to %s (update)", util.ObjectInfo(n))
 			c.workqueue.Add(wqKey{kind: workqueueKey.kind, namespace: newAccessor.GetNamespace(), name: newAccessor.GetName()})
 		},
@@ -1325,6 +1512,11 @@ func (c *Controller) removeResources() error {
 		if err != nil {
 			lastErr = fmt.Errorf("failed to prune operator-created MachineConfigs: %v", err)
 		}
+
+		err = c.clients.Kube.CoreV1().ConfigMaps(ntoconfig.WatchNamespace()).Delete(ctx, tunedv1.TunedCmdlineManifestConfigMapName, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			lastErr = fmt.Errorf("failed to delete ConfigMap %s: %v", tunedv1.TunedCmdlineManifestConfigMapName, err)
+		}
 	}
 
 	return lastErr
@@ -1377,6 +1569,7 @@ func (c *Controller) run(ctx context.Context) error {
 
 	var tunedConfigMapInformerFactory kubeinformers.SharedInformerFactory
 	var mcfgConfigMapInformerFactory kubeinformers.SharedInformerFactory
+	var baseProfileConfigMapInformerFactory kubeinformers.SharedInformerFactory
 	var mcfgInformerFactory mcfginformers.SharedInformerFactory
 	var caConfigMapInformerFactory kubeinformers.SharedInformerFactory
 	if ntoconfig.InHyperShift() {
@@ -1404,7 +1597,19 @@ func (c *Controller) run(ctx context.Context) error {
 			return err
 		}
 
-		InformerFuncs = append(InformerFuncs, tunedConfigMapInformer.Informer().HasSynced, mcfgConfigMapInformer.Informer().HasSynced)
+		// The NodePool PerformanceProfiles override the base PerformanceProfile of the hosted cluster.
+		baseProfileConfigMapInformerFactory = kubeinformers.NewSharedInformerFactoryWithOptions(c.clients.ManagementKube,
+			ntoconfig.ResyncPeriod(),
+			kubeinformers.WithNamespace(ntoconfig.OperatorNamespace()),
+			kubeinformers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+				opts.LabelSelector = baseProfileConfigMapLabel + "=true"
+			}))
+		baseProfileConfigMapInformer := baseProfileConfigMapInformerFactory.Core().V1().ConfigMaps()
+		if _, err := baseProfileConfigMapInformer.Informer().AddEventHandler(c.informerEventHandler(wqKey{kind: wqKindConfigMap})); err != nil {
+			return err
+		}
+
+		InformerFuncs = append(InformerFuncs, tunedConfigMapInformer.Informer().HasSynced, mcfgConfigMapInformer.Informer().HasSynced, baseProfileConfigMapInformer.Informer().HasSynced)
 	} else {
 		mcfgInformerFactory = mcfginformers.NewSharedInformerFactory(c.clients.MC, ntoconfig.ResyncPeriod())
 		mcInformer := mcfgInformerFactory.Machineconfiguration().V1().MachineConfigs()
@@ -1439,6 +1644,7 @@ func (c *Controller) run(ctx context.Context) error {
 	if ntoconfig.InHyperShift() {
 		tunedConfigMapInformerFactory.Start(ctx.Done())
 		mcfgConfigMapInformerFactory.Start(ctx.Done())
+		baseProfileConfigMapInformerFactory.Start(ctx.Done())
 	} else {
 		mcfgInformerFactory.Start(ctx.Done())        // MachineConfig/MachineConfigPool
 		caConfigMapInformerFactory.Start(ctx.Done()) // Metrics client's ConfigMap CA
@@ -0,0 +1,285 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kcorelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	ntoclient "github.com/openshift/cluster-node-tuning-operator/pkg/client"
+	tunedfake "github.com/openshift/cluster-node-tuning-operator/pkg/generated/clientset/versioned/fake"
+	ntolisters "github.com/openshift/cluster-node-tuning-operator/pkg/generated/listers/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/tracing/tracingtest"
+	tunedpkg "github.com/openshift/cluster-node-tuning-operator/pkg/tuned"
+)
+
+const testNamespace = "openshift-cluster-node-tuning-operator"
+
+// newTestProfileCalculator returns a ProfileCalculator listing the Tuned CRs 'tuneds'.
+func newTestProfileCalculator(t *testing.T, tuneds ...*tunedv1.Tuned) *ProfileCalculator {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, tuned := range tuneds {
+		if err := indexer.Add(tuned); err != nil {
+			t.Fatal(err)
+		}
+	}
+	listers := &ntoclient.Listers{
+		TunedResources: ntolisters.NewTunedLister(indexer).Tuneds(testNamespace),
+	}
+	return NewProfileCalculator(listers, nil)
+}
+
+func newTestTuned(name string, annotations map[string]string, profiles ...string) *tunedv1.Tuned {
+	tuned := &tunedv1.Tuned{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   testNamespace,
+			Annotations: annotations,
+		},
+	}
+	for i := range profiles {
+		tuned.Spec.Recommend = append(tuned.Spec.Recommend, tunedv1.TunedRecommend{Profile: &profiles[i]})
+	}
+	return tuned
+}
+
+func TestProfileDeferred(t *testing.T) {
+	deferredAnnotation := map[string]string{tunedv1.TunedDeferredUpdate: ""}
+
+	tests := []struct {
+		name     string
+		tuneds   []*tunedv1.Tuned
+		profile  string
+		expected bool
+	}{
+		{
+			name:     "profile recommended by an annotated Tuned",
+			tuneds:   []*tunedv1.Tuned{newTestTuned("deferred", deferredAnnotation, "openshift-node-deferred")},
+			profile:  "openshift-node-deferred",
+			expected: true,
+		},
+		{
+			name:     "profile recommended by a Tuned without the annotation",
+			tuneds:   []*tunedv1.Tuned{newTestTuned("immediate", nil, "openshift-node-deferred")},
+			profile:  "openshift-node-deferred",
+			expected: false,
+		},
+		{
+			name: "profile not recommended by the annotated Tuned",
+			tuneds: []*tunedv1.Tuned{
+				newTestTuned("deferred", deferredAnnotation, "openshift-node-deferred"),
+				newTestTuned("immediate", nil, "openshift-node"),
+			},
+			profile:  "openshift-node",
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pc := newTestProfileCalculator(t, tc.tuneds...)
+			got, err := pc.profileDeferred(tc.profile)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSetProfileDeferred(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		deferred    bool
+		expected    map[string]string
+	}{
+		{
+			name:     "defer a Profile without annotations",
+			deferred: true,
+			expected: map[string]string{tunedv1.TunedDeferredUpdate: ""},
+		},
+		{
+			name:        "keep the other annotations",
+			annotations: map[string]string{"foo": "bar"},
+			deferred:    true,
+			expected:    map[string]string{"foo": "bar", tunedv1.TunedDeferredUpdate: ""},
+		},
+		{
+			name:        "the annotation was removed from the Tuned",
+			annotations: map[string]string{"foo": "bar", tunedv1.TunedDeferredUpdate: ""},
+			deferred:    false,
+			expected:    map[string]string{"foo": "bar"},
+		},
+		{
+			name:     "nothing to remove",
+			deferred: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			profile := &tunedv1.Profile{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			setProfileDeferred(profile, tc.deferred)
+			if len(profile.Annotations) != len(tc.expected) {
+				t.Fatalf("expected the annotations %v, got %v", tc.expected, profile.Annotations)
+			}
+			for k, v := range tc.expected {
+				if got, ok := profile.Annotations[k]; !ok || got != v {
+					t.Errorf("expected the annotations %v, got %v", tc.expected, profile.Annotations)
+				}
+			}
+			if isProfileDeferred(profile) != tc.deferred {
+				t.Errorf("expected the Profile deferred %t", tc.deferred)
+			}
+		})
+	}
+}
+
+func TestNumProfilesProgressingDegradedDeferred(t *testing.T) {
+	newProfile := func(status corev1.ConditionStatus, reason string) *tunedv1.Profile {
+		profile := &tunedv1.Profile{}
+		profile.Spec.Config.TunedProfile = "openshift-node"
+		profile.Status.TunedProfile = "openshift-node"
+		profile.Status.Conditions = []tunedv1.ProfileStatusCondition{
+			{Type: tunedv1.TunedProfileApplied, Status: status, Reason: reason},
+		}
+		return profile
+	}
+
+	tests := []struct {
+		name                string
+		profiles            []*tunedv1.Profile
+		expectedProgressing int
+	}{
+		{
+			name:                "deferred",
+			profiles:            []*tunedv1.Profile{newProfile(corev1.ConditionFalse, tunedpkg.ProfileAppliedDeferredReason)},
+			expectedProgressing: 0,
+		},
+		{
+			name:                "waiting to be applied",
+			profiles:            []*tunedv1.Profile{newProfile(corev1.ConditionFalse, "Failed")},
+			expectedProgressing: 1,
+		},
+		{
+			name:                "applied",
+			profiles:            []*tunedv1.Profile{newProfile(corev1.ConditionTrue, "AsExpected")},
+			expectedProgressing: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			progressing, degraded := numProfilesProgressingDegraded(tc.profiles)
+			if progressing != tc.expectedProgressing || degraded != 0 {
+				t.Errorf("expected %d progressing and no degraded Profiles, got %d and %d", tc.expectedProgressing, progressing, degraded)
+			}
+		})
+	}
+}
+
+func TestSyncProfileSpans(t *testing.T) {
+	sr, restore := tracingtest.Install()
+	defer restore()
+
+	tunedIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := tunedIndexer.Add(newTestTuned(tunedv1.TunedDefaultResourceName, nil, "openshift-node")); err != nil {
+		t.Fatal(err)
+	}
+	nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"kubernetes.io/os": "linux"}}}
+	if err := nodeIndexer.Add(node); err != nil {
+		t.Fatal(err)
+	}
+	listers := &ntoclient.Listers{
+		TunedResources: ntolisters.NewTunedLister(tunedIndexer).Tuneds(testNamespace),
+		TunedProfiles:  ntolisters.NewProfileLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})).Profiles(testNamespace),
+		Nodes:          kcorelisters.NewNodeLister(nodeIndexer),
+	}
+	clients := &ntoclient.Clients{Tuned: tunedfake.NewSimpleClientset()}
+	c := &Controller{
+		workqueue:           workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		listers:             listers,
+		clients:             clients,
+		pc:                  NewProfileCalculator(listers, clients),
+		bootcmdlineConflict: map[string]bool{},
+	}
+	c.pc.state.nodeLabels[node.Name] = node.Labels
+
+	// process the Profile event and stop
+	c.workqueue.Add(wqKey{kind: wqKindProfile, namespace: testNamespace, name: node.Name})
+	c.workqueue.ShutDown()
+	c.eventProcessor()
+
+	if _, err := clients.Tuned.TunedV1().Profiles(testNamespace).Get(context.TODO(), node.Name, metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected the Profile of Node %s created: %v", node.Name, err)
+	}
+
+	syncs := sr.Named("Tuned.sync")
+	if len(syncs) != 1 {
+		t.Fatalf("expected 1 Tuned.sync span, got %d", len(syncs))
+	}
+	if p := sr.Parent(syncs[0]); p != nil {
+		t.Errorf("expected Tuned.sync to be a root span, got the parent %s", p.Name())
+	}
+	for _, name := range []string{"compute", "apply"} {
+		spans := sr.Named(name)
+		if len(spans) != 1 {
+			t.Fatalf("expected 1 %s span, got %d", name, len(spans))
+		}
+		if p := sr.Parent(spans[0]); p == nil || p.SpanContext().SpanID() != syncs[0].SpanContext().SpanID() {
+			t.Errorf("expected %s to be a child of Tuned.sync, got %v", name, p)
+		}
+	}
+}
+
+func TestSyncTunedReadOnly(t *testing.T) {
+	t.Setenv("READ_ONLY", "true")
+
+	tunedIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	tunedDefault := newTestTuned(tunedv1.TunedDefaultResourceName, nil, "openshift-node")
+	if err := tunedIndexer.Add(tunedDefault); err != nil {
+		t.Fatal(err)
+	}
+	listers := &ntoclient.Listers{
+		TunedResources: ntolisters.NewTunedLister(tunedIndexer).Tuneds(testNamespace),
+	}
+	clients := &ntoclient.Clients{Tuned: tunedfake.NewSimpleClientset()}
+	c := &Controller{
+		listers: listers,
+		clients: clients,
+		pc:      NewProfileCalculator(listers, clients),
+	}
+
+	cr, err := c.syncTunedDefault()
+	if err != nil {
+		t.Fatalf("failed to sync Tuned %s: %v", tunedv1.TunedDefaultResourceName, err)
+	}
+	if cr != tunedDefault {
+		t.Errorf("expected the Tuned %s from the cache, got %v", tunedv1.TunedDefaultResourceName, cr)
+	}
+	if err := c.syncTunedRendered(cr); err != nil {
+		t.Fatalf("failed to sync Tuned %s: %v", tunedv1.TunedRenderedResourceName, err)
+	}
+	if actions := clients.Tuned.(*tunedfake.Clientset).Actions(); len(actions) != 0 {
+		t.Errorf("expected no writes in the read-only mode, got %v", actions)
+	}
+
+	if err := tunedIndexer.Delete(tunedDefault); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.syncTunedDefault(); err == nil {
+		t.Errorf("expected the missing Tuned %s to be reported in the read-only mode", tunedv1.TunedDefaultResourceName)
+	}
+	if actions := clients.Tuned.(*tunedfake.Clientset).Actions(); len(actions) != 0 {
+		t.Errorf("expected no writes in the read-only mode, got %v", actions)
+	}
+}
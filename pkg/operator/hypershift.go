@@ -4,13 +4,13 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"hash/fnv"
-	"io"
 	"reflect"
-	"strings"
 
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
 	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
 	ntoconfig "github.com/openshift/cluster-node-tuning-operator/pkg/config"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/nodepool"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
 	"github.com/openshift/cluster-node-tuning-operator/version"
 	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -18,31 +18,36 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	serializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
-	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/klog/v2"
 )
 
 const (
 	hypershiftNodeOwnerNameLabel = "cluster.x-k8s.io/owner-name"
 	hypershiftNodeOwnerKindLabel = "cluster.x-k8s.io/owner-kind"
-	hypershiftNodePoolLabel      = "hypershift.openshift.io/nodePool"
-	hypershiftNodePoolNameLabel  = "hypershift.openshift.io/nodePoolName"
+	hypershiftNodePoolLabel      = nodepool.NodePoolLabel
+	hypershiftNodePoolNameLabel  = nodepool.NodePoolNameLabel
 
-	tunedConfigMapLabel      = "hypershift.openshift.io/tuned-config"
-	tuningConfigMapConfigKey = "tuning"
-	// TODO remove once HyperShift has switched to using new key.
-	tunedConfigMapConfigKeyDeprecated = "tuned"
+	tunedConfigMapLabel       = nodepool.TuningConfigMapLabel
+	baseProfileConfigMapLabel = nodepool.BaseProfileConfigMapLabel
 
-	operatorGeneratedMachineConfig = "hypershift.openshift.io/nto-generated-machine-config"
-	mcConfigMapDataKey             = "config"
+	operatorGeneratedMachineConfig = nodepool.GeneratedMachineConfigLabel
+	mcConfigMapDataKey             = nodepool.MachineConfigKey
 	generatedConfigMapPrefix       = "nto-mc-"
 )
 
 // syncHostedClusterTuneds synchronizes Tuned objects embedded in ConfigMaps
 // in management's cluster hosted namespace with Tuned objects in the hosted
-// cluster.  Returns non-nil error only when retry/resync is needed.
+// cluster.  The PerformanceProfiles embedded in the ConfigMaps are rendered,
+// their Tuned objects are synchronized the same way and their MachineConfigs
+// and KubeletConfigs are written to ConfigMaps in the hosted namespace.
+// Returns non-nil error only when retry/resync is needed.
 func (c *Controller) syncHostedClusterTuneds() error {
-	cmTuneds, err := c.getObjFromTunedConfigMap()
+	cmTuneds, profileConfigMaps, err := c.getObjFromTunedConfigMap()
+	if err != nil {
+		return err
+	}
+
+	err = c.syncNodePoolProfileConfigMaps(profileConfigMaps)
 	if err != nil {
 		return err
 	}
@@ -113,12 +118,25 @@ func (c *Controller) syncHostedClusterTuneds() error {
 	return nil
 }
 
-// getObjFromTunedConfigMap retrieves all ConfigMaps with embedded Tuned objects
-// from management's cluster hosted namespace and returns a slice of the
-// retrieved Tuned objects.  Duplicate Tuned objects are ignored.  Returns non-nil
-// error only when retry is needed.
-func (c *Controller) getObjFromTunedConfigMap() ([]tunedv1.Tuned, error) {
-	var cmTuneds []tunedv1.Tuned
+// getObjFromTunedConfigMap retrieves all ConfigMaps with embedded Tuned and
+// PerformanceProfile objects from management's cluster hosted namespace and
+// returns a slice of the retrieved Tuned objects, including the Tuned objects
+// rendered from the PerformanceProfiles, and the ConfigMaps wrapping the
+// MachineConfigs and KubeletConfigs rendered from the PerformanceProfiles.
+// The NodePool PerformanceProfiles override the base PerformanceProfile of
+// the hosted cluster, if any, the same way the render command merges them.
+// Duplicate Tuned objects are ignored.  Returns non-nil error only when retry
+// is needed.
+func (c *Controller) getObjFromTunedConfigMap() ([]tunedv1.Tuned, []*corev1.ConfigMap, error) {
+	var (
+		cmTuneds          []tunedv1.Tuned
+		profileConfigMaps []*corev1.ConfigMap
+	)
+
+	base, err := c.getBaseProfile()
+	if err != nil {
+		return nil, nil, err
+	}
 
 	cmListOptions := metav1.ListOptions{
 		LabelSelector: tunedConfigMapLabel + "=true",
@@ -126,50 +144,163 @@ func (c *Controller) getObjFromTunedConfigMap() ([]tunedv1.Tuned, error) {
 
 	cmList, err := c.clients.ManagementKube.CoreV1().ConfigMaps(ntoconfig.OperatorNamespace()).List(context.TODO(), cmListOptions)
 	if err != nil {
-		return cmTuneds, fmt.Errorf("error listing ConfigMaps in namespace %s: %v", ntoconfig.OperatorNamespace(), err)
+		return nil, nil, fmt.Errorf("error listing ConfigMaps in namespace %s: %v", ntoconfig.OperatorNamespace(), err)
 	}
 
 	seenTunedObject := map[string]bool{}
-	for _, cm := range cmList.Items {
-		tunedConfig, ok := cm.Data[tuningConfigMapConfigKey]
-		if !ok {
-			tunedConfig, ok = cm.Data[tunedConfigMapConfigKeyDeprecated]
-			if !ok {
-				klog.Warningf("ConfigMap %s has no data in field %s or %s (deprecated). Expected Tuned manifests.", cm.ObjectMeta.Name, tuningConfigMapConfigKey, tunedConfigMapConfigKeyDeprecated)
-				continue
-			} else {
-				klog.Infof("Deprecated key %s used in ConfigMap %s", tunedConfigMapConfigKeyDeprecated, cm.ObjectMeta.Name)
-			}
-		}
-
-		cmNodePoolNamespacedName, ok := cm.Annotations[hypershiftNodePoolLabel]
-		if !ok {
-			klog.Warningf("failed to parseTunedManifests in ConfigMap %s, no label %s", cm.ObjectMeta.Name, hypershiftNodePoolLabel)
+	for i := range cmList.Items {
+		cm := &cmList.Items[i]
+		if nodepool.IsBaseProfileConfigMap(cm) {
+			// the base profile is only merged into the NodePool profiles
 			continue
 		}
-		nodePoolName := parseNamespacedName(cmNodePoolNamespacedName)
 
-		tunedsFromConfigMap, err := parseTunedManifests([]byte(tunedConfig), nodePoolName)
+		cfg, err := nodepool.ConfigFromConfigMap(cm, base)
 		if err != nil {
-			klog.Warningf("failed to parseTunedManifests in ConfigMap %s: %v", cm.ObjectMeta.Name, err)
+			klog.Warningf("failed to parse the tuning configuration in ConfigMap %s: %v", cm.ObjectMeta.Name, err)
 			continue
 		}
 
+		tunedsFromConfigMap := []*tunedv1.Tuned{}
+		for _, t := range cfg.Tuneds {
+			nodepool.SetTunedNodePool(t, cfg.NodePoolName)
+			tunedsFromConfigMap = append(tunedsFromConfigMap, t)
+		}
+
+		var cmProfileConfigMaps []*corev1.ConfigMap
+		for _, pp := range cfg.Profiles {
+			tuned, configMaps, err := renderNodePoolProfile(pp, cfg.NodePoolName)
+			if err != nil {
+				klog.Warningf("failed to render PerformanceProfile %s in ConfigMap %s: %v", pp.Name, cm.ObjectMeta.Name, err)
+				continue
+			}
+			tunedsFromConfigMap = append(tunedsFromConfigMap, tuned)
+			cmProfileConfigMaps = append(cmProfileConfigMaps, configMaps...)
+		}
+
 		tunedsFromConfigMapUnique := []tunedv1.Tuned{}
-		for j, t := range tunedsFromConfigMap {
-			tunedObjectName := tunedsFromConfigMap[j].ObjectMeta.Name
+		for _, t := range tunedsFromConfigMap {
+			tunedObjectName := t.ObjectMeta.Name
 			if seenTunedObject[tunedObjectName] {
 				klog.Warningf("ignoring duplicate Tuned Profile %s in ConfigMap %s", tunedObjectName, cm.ObjectMeta.Name)
 				continue
 			}
 			seenTunedObject[tunedObjectName] = true
-			tunedsFromConfigMapUnique = append(tunedsFromConfigMapUnique, t)
+			tunedsFromConfigMapUnique = append(tunedsFromConfigMapUnique, *t)
 		}
 
 		cmTuneds = append(cmTuneds, tunedsFromConfigMapUnique...)
+		profileConfigMaps = append(profileConfigMaps, cmProfileConfigMaps...)
 	}
 
-	return cmTuneds, nil
+	return cmTuneds, profileConfigMaps, nil
+}
+
+// getBaseProfile retrieves the base PerformanceProfile of the hosted cluster
+// the NodePool PerformanceProfiles override.  Returns nil if the hosted cluster
+// has no base PerformanceProfile.
+func (c *Controller) getBaseProfile() (*performancev2.PerformanceProfile, error) {
+	cmListOptions := metav1.ListOptions{
+		LabelSelector: baseProfileConfigMapLabel + "=true",
+	}
+
+	cmList, err := c.clients.ManagementKube.CoreV1().ConfigMaps(ntoconfig.OperatorNamespace()).List(context.TODO(), cmListOptions)
+	if err != nil {
+		return nil, fmt.Errorf("error listing ConfigMaps in namespace %s: %v", ntoconfig.OperatorNamespace(), err)
+	}
+
+	switch len(cmList.Items) {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		// Rendering the NodePool profiles without their base would change the settings of the nodes.
+		return nil, fmt.Errorf("found %d base PerformanceProfile ConfigMaps in namespace %s, expected one", len(cmList.Items), ntoconfig.OperatorNamespace())
+	}
+
+	base, err := nodepool.BaseProfileFromConfigMap(&cmList.Items[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the base PerformanceProfile: %v", err)
+	}
+	return base, nil
+}
+
+// renderNodePoolProfile renders the PerformanceProfile 'pp' of the NodePool
+// 'nodePoolName' and returns its Tuned and the ConfigMaps wrapping its
+// MachineConfig and KubeletConfig.
+func renderNodePoolProfile(pp *performancev2.PerformanceProfile, nodePoolName string) (*tunedv1.Tuned, []*corev1.ConfigMap, error) {
+	components, err := nodepool.RenderProfile(pp, nodePoolName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var configMaps []*corev1.ConfigMap
+	for kind, manifest := range map[string]interface{}{
+		components.MachineConfig.Kind: components.MachineConfig,
+		components.KubeletConfig.Kind: components.KubeletConfig,
+	} {
+		cm, err := nodepool.NewMachineConfigConfigMap(kind, pp.Name, nodePoolName, manifest)
+		if err != nil {
+			return nil, nil, err
+		}
+		cm.Annotations[GeneratedByControllerVersionAnnotationKey] = version.Version
+		configMaps = append(configMaps, cm)
+	}
+
+	return components.Tuned, configMaps, nil
+}
+
+// syncNodePoolProfileConfigMaps creates or updates the ConfigMaps 'configMaps'
+// wrapping the MachineConfigs and KubeletConfigs rendered from the NodePool
+// PerformanceProfiles and records their names, so that they are not pruned.
+func (c *Controller) syncNodePoolProfileConfigMaps(configMaps []*corev1.ConfigMap) error {
+	cmNames := map[string]bool{}
+	for _, cm := range configMaps {
+		cmNames[cm.Name] = true
+
+		existing, err := c.clients.ManagementKube.CoreV1().ConfigMaps(cm.Namespace).Get(context.TODO(), cm.Name, metav1.GetOptions{})
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return fmt.Errorf("failed to get ConfigMap %s: %v", cm.Name, err)
+			}
+			_, err = c.clients.ManagementKube.CoreV1().ConfigMaps(cm.Namespace).Create(context.TODO(), cm, metav1.CreateOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to create ConfigMap %s: %v", cm.Name, err)
+			}
+			klog.Infof("created ConfigMap %s for NodePool %s", cm.Name, cm.Labels[hypershiftNodePoolLabel])
+			continue
+		}
+
+		if reflect.DeepEqual(existing.Data, cm.Data) &&
+			util.MapOfStringsContains(existing.Labels, cm.Labels) &&
+			util.MapOfStringsContains(existing.Annotations, cm.Annotations) {
+			klog.V(2).Infof("syncNodePoolProfileConfigMaps(): ConfigMap %s doesn't need updating", cm.Name)
+			continue
+		}
+
+		updated := existing.DeepCopy() // never update the objects from cache
+		updated.Data = cm.Data
+		if updated.Labels == nil {
+			updated.Labels = map[string]string{}
+		}
+		for k, v := range cm.Labels {
+			updated.Labels[k] = v
+		}
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
+		}
+		for k, v := range cm.Annotations {
+			updated.Annotations[k] = v
+		}
+		_, err = c.clients.ManagementKube.CoreV1().ConfigMaps(cm.Namespace).Update(context.TODO(), updated, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to update ConfigMap %s: %v", cm.Name, err)
+		}
+		klog.Infof("updated ConfigMap %s for NodePool %s", cm.Name, cm.Labels[hypershiftNodePoolLabel])
+	}
+	c.nodePoolProfileConfigMaps = cmNames
+
+	return nil
 }
 
 // getNodesForNodePool uses 'hypershiftNodePoolLabel' to return all Nodes which are in
@@ -187,56 +318,10 @@ func (c *Controller) getNodesForNodePool(nodePoolName string) ([]*corev1.Node, e
 	return nodes, err
 }
 
-// parseManifests parses a YAML or JSON document that may contain one or more
-// kubernetes resources.
-func parseTunedManifests(data []byte, nodePoolName string) ([]tunedv1.Tuned, error) {
-	r := bytes.NewReader(data)
-	d := yamlutil.NewYAMLOrJSONDecoder(r, 1024)
-	var tuneds []tunedv1.Tuned
-	for {
-		t := tunedv1.Tuned{}
-		if err := d.Decode(&t); err != nil {
-			if err == io.EOF {
-				return tuneds, nil
-			}
-			return tuneds, fmt.Errorf("error parsing Tuned manifests: %v", err)
-		}
-		// Make Tuned names unique if a Tuned is duplicated across NodePools
-		// for example, if one ConfigMap is referenced in multiple NodePools
-		t.SetName(t.ObjectMeta.Name + "-" + hashStruct(nodePoolName))
-		klog.V(2).Infof("parseTunedManifests: name: %s", t.GetName())
-
-		// Propagate NodePool name from ConfigMap down to Tuned object
-		if t.Labels == nil {
-			t.Labels = make(map[string]string)
-		}
-		t.Labels[hypershiftNodePoolNameLabel] = nodePoolName
-		tuneds = append(tuneds, t)
-	}
-}
-
 func mcConfigMapName(name string) string {
 	return generatedConfigMapPrefix + name
 }
 
-func hashStruct(o interface{}) string {
-	hash := fnv.New32a()
-	hash.Write([]byte(fmt.Sprintf("%v", o)))
-	intHash := hash.Sum32()
-	return fmt.Sprintf("%08x", intHash)
-}
-
-// parseNamespacedName expects a string with the format "namespace/name"
-// and returns the name only.
-// If given a string in the format "name" returns "name".
-func parseNamespacedName(namespacedName string) string {
-	parts := strings.SplitN(namespacedName, "/", 2)
-	if len(parts) > 1 {
-		return parts[1]
-	}
-	return parts[0]
-}
-
 func (c *Controller) getMachineConfigFromConfigMap(config *corev1.ConfigMap) (*mcfgv1.MachineConfig, error) {
 	YamlSerializer := serializer.NewSerializerWithOptions(
 		serializer.DefaultMetaFactory, c.scheme, c.scheme,
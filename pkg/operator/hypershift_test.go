@@ -0,0 +1,168 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	ntoclient "github.com/openshift/cluster-node-tuning-operator/pkg/client"
+	tunedfake "github.com/openshift/cluster-node-tuning-operator/pkg/generated/clientset/versioned/fake"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/nodepool"
+)
+
+const testHostedBaseProfile = `apiVersion: performance.openshift.io/v2
+kind: PerformanceProfile
+metadata:
+  name: base
+spec:
+  cpu:
+    isolated: "2-7"
+    reserved: "0-1"
+  hugepages:
+    defaultHugepagesSize: 1G
+    pages:
+    - count: 4
+      size: 1G
+  nodeSelector:
+    node-role.kubernetes.io/worker: ""
+`
+
+const testNodePoolTuning = `apiVersion: performance.openshift.io/v2
+kind: PerformanceProfile
+metadata:
+  name: nodepool-1
+spec:
+  cpu:
+    isolated: "4-15"
+    reserved: "0-3"
+  additionalKernelArgs:
+  - nosmt
+---
+apiVersion: tuned.openshift.io/v1
+kind: Tuned
+metadata:
+  name: tuned-hugepages
+spec:
+  profile:
+  - data: |
+      [main]
+      summary=Boot time configuration for hugepages
+    name: openshift-node-hugepages
+`
+
+func newTestHostedConfigMap(name string, labels, annotations map[string]string, tuning string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   testNamespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Data: map[string]string{nodepool.TuningConfigKey: tuning},
+	}
+}
+
+func TestSyncHostedClusterTunedsPerformanceProfile(t *testing.T) {
+	hash := nodepool.HashNodePoolName("nodepool-1")
+	nodePoolConfigMap := newTestHostedConfigMap("tuned-nodepool-1",
+		map[string]string{tunedConfigMapLabel: "true"},
+		map[string]string{hypershiftNodePoolLabel: "clusters/nodepool-1"},
+		testNodePoolTuning)
+	baseConfigMap := newTestHostedConfigMap("performanceprofile-base",
+		map[string]string{baseProfileConfigMapLabel: "true"}, nil, testHostedBaseProfile)
+
+	tests := []struct {
+		name       string
+		configMaps []*corev1.ConfigMap
+		wantArgs   []string
+		noArgs     []string
+	}{
+		{
+			name:       "without a base profile",
+			configMaps: []*corev1.ConfigMap{nodePoolConfigMap},
+			wantArgs:   []string{"nosmt"},
+			noArgs:     []string{"hugepagesz=1G"},
+		},
+		{
+			name:       "with a base profile",
+			configMaps: []*corev1.ConfigMap{nodePoolConfigMap, baseConfigMap},
+			wantArgs:   []string{"nosmt", "hugepagesz=1G"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			managementKube := kubefake.NewSimpleClientset()
+			for _, cm := range tc.configMaps {
+				if _, err := managementKube.CoreV1().ConfigMaps(testNamespace).Create(context.TODO(), cm, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c := &Controller{
+				clients: &ntoclient.Clients{
+					Tuned:          tunedfake.NewSimpleClientset(),
+					ManagementKube: managementKube,
+				},
+			}
+
+			if err := c.syncHostedClusterTuneds(); err != nil {
+				t.Fatalf("failed to sync hosted cluster Tuneds: %v", err)
+			}
+
+			for _, name := range []string{"tuned-hugepages-" + hash, "openshift-node-performance-nodepool-1-" + hash} {
+				tuned, err := c.clients.Tuned.TunedV1().Tuneds(testNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+				if err != nil {
+					t.Errorf("expected the Tuned %s in the hosted cluster: %v", name, err)
+					continue
+				}
+				if tuned.Labels[hypershiftNodePoolNameLabel] != "nodepool-1" {
+					t.Errorf("expected the Tuned %s labeled with the NodePool name, got %v", name, tuned.Labels)
+				}
+				if !strings.HasPrefix(name, "openshift-node-performance-") {
+					continue
+				}
+				data := *tuned.Spec.Profile[0].Data
+				for _, arg := range tc.wantArgs {
+					if !strings.Contains(data, arg) {
+						t.Errorf("expected the kernel argument %q in the Tuned %s", arg, name)
+					}
+				}
+				for _, arg := range tc.noArgs {
+					if strings.Contains(data, arg) {
+						t.Errorf("expected no kernel argument %q in the Tuned %s", arg, name)
+					}
+				}
+			}
+
+			for _, kind := range []string{"machineconfig", "kubeletconfig"} {
+				name := kind + "-nodepool-1-" + hash
+				cm, err := managementKube.CoreV1().ConfigMaps(testNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+				if err != nil {
+					t.Errorf("expected the ConfigMap %s: %v", name, err)
+					continue
+				}
+				if cm.Labels[operatorGeneratedMachineConfig] != "true" || cm.Annotations[GeneratedByControllerVersionAnnotationKey] == "" {
+					t.Errorf("expected the ConfigMap %s labeled and annotated as generated, got %v %v", name, cm.Labels, cm.Annotations)
+				}
+				if !c.nodePoolProfileConfigMaps[name] {
+					t.Errorf("expected the ConfigMap %s kept from pruning, got %v", name, c.nodePoolProfileConfigMaps)
+				}
+			}
+
+			// a second sync finds everything up to date
+			managementKube.ClearActions()
+			if err := c.syncHostedClusterTuneds(); err != nil {
+				t.Fatalf("failed to sync hosted cluster Tuneds: %v", err)
+			}
+			for _, action := range managementKube.Actions() {
+				if action.GetVerb() != "list" && action.GetVerb() != "get" {
+					t.Errorf("expected no ConfigMap writes on the second sync, got %v", action)
+				}
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package operator
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -37,6 +39,22 @@ type tunedState struct {
 	bootcmdline map[string]string
 	// Node name:   ^^^^^^
 	// bootcmdline         ^^^^^^
+	excluded map[string]bool
+	// Node name: ^^^^^^
+	// excluded from the pool tuning ^^^^^^
+	schedules map[string]string
+	// Node name: ^^^^^^
+	// time window of the recommend rule selecting the profile ^^^^^^
+	priorityConflicts map[string]priorityConflict
+	// Node name:         ^^^^^^
+	// profiles recommended with the same priority ^^^^^^
+}
+
+// priorityConflict is a set of TuneD profiles recommended with the same priority for a Node.
+type priorityConflict struct {
+	priority uint64
+	// sorted TuneD profile names
+	profiles []string
 }
 
 type ProfileCalculator struct {
@@ -54,6 +72,9 @@ func NewProfileCalculator(listers *ntoclient.Listers, clients *ntoclient.Clients
 	pc.state.podLabels = map[string]map[string]map[string]string{}
 	pc.state.providerIDs = map[string]string{}
 	pc.state.bootcmdline = map[string]string{}
+	pc.state.excluded = map[string]bool{}
+	pc.state.schedules = map[string]string{}
+	pc.state.priorityConflicts = map[string]priorityConflict{}
 	return pc
 }
 
@@ -139,6 +160,12 @@ func (pc *ProfileCalculator) nodeChangeHandler(nodeName string) (bool, error) {
 		}
 	}
 
+	if excluded := isNodeExcluded(node); excluded != pc.state.excluded[nodeName] {
+		pc.state.excluded[nodeName] = excluded
+		klog.Infof("Node %s excluded from the pool tuning: %v", nodeName, excluded)
+		change = true
+	}
+
 	nodeLabelsNew := util.MapOfStringsCopy(node.Labels)
 
 	if !util.MapOfStringsEqual(nodeLabelsNew, pc.state.nodeLabels[nodeName]) {
@@ -167,7 +194,7 @@ func (pc *ProfileCalculator) calculateProfile(nodeName string) (string, map[stri
 		return "", nil, operand, fmt.Errorf("failed to list Tuned: %v", err)
 	}
 
-	recommendAll := TunedRecommend(tunedList)
+	recommendAll := recommendScheduled(TunedRecommend(tunedsPrimary(tunedList)), time.Now())
 	recommendProfile := func(nodeName string, iStart int) (int, string, map[string]string, tunedv1.OperandConfig, error) {
 		var i int
 		for i = iStart; i < len(recommendAll); i++ {
@@ -226,8 +253,10 @@ func (pc *ProfileCalculator) calculateProfile(nodeName string) (string, map[stri
 
 		return defaultProfile, nil, operand, fmt.Errorf("the default Tuned CR misses a catch-all profile selection")
 	}
+	pc.scheduleSet(nodeName, recommendAll[iStop].Schedule)
 
 	// Make sure we do not have multiple matching profiles with the same priority.  If so, report a warning.
+	var conflicting []string
 	for i := iStop + 1; i < len(recommendAll); i++ {
 		j, tunedProfileNameDup, _, _, err := recommendProfile(nodeName, i)
 		if err != nil {
@@ -253,16 +282,162 @@ func (pc *ProfileCalculator) calculateProfile(nodeName string) (string, map[stri
 			if tunedProfileName != tunedProfileNameDup {
 				klog.Warningf("profiles %s/%s have the same priority %d and match %s; please use a different priority for your custom profiles!",
 					tunedProfileName, tunedProfileNameDup, *recommendAll[i].Priority, nodeName)
+				if !slices.Contains(conflicting, tunedProfileNameDup) {
+					conflicting = append(conflicting, tunedProfileNameDup)
+				}
 			}
 		} else {
 			// We no longer have recommend rules with the same priority -- do not go through the entire (priority-ordered) list.
 			break
 		}
 	}
+	pc.priorityConflictSet(nodeName, recommendAll[iStop].Priority, tunedProfileName, conflicting)
 
 	return tunedProfileName, mcLabels, operand, err
 }
 
+// isNodeExcluded returns true if Node 'node' is annotated to be excluded from the pool tuning.
+func isNodeExcluded(node *corev1.Node) bool {
+	return node.ObjectMeta.Annotations[tunedv1.TunedExcludeAnnotationKey] == "true"
+}
+
+// calculateDefaultProfile calculates the tuned profile the default Tuned CR recommends for
+// Node nodeName, ignoring all the other Tuned CRs.  This is the profile of the Nodes excluded
+// from the pool tuning.
+//
+// Returns
+// * the tuned daemon profile name
+// * the operand configuration
+// * an error if any
+func (pc *ProfileCalculator) calculateDefaultProfile(nodeName string) (string, tunedv1.OperandConfig, error) {
+	var operand tunedv1.OperandConfig
+
+	klog.V(3).Infof("calculateDefaultProfile(%s)", nodeName)
+	tuned, err := pc.listers.TunedResources.Get(tunedv1.TunedDefaultResourceName)
+	if err != nil {
+		return defaultProfile, operand, fmt.Errorf("failed to get Tuned %s: %v", tunedv1.TunedDefaultResourceName, err)
+	}
+
+	for _, recommend := range TunedRecommend([]*tunedv1.Tuned{tuned}) {
+		if recommend.MachineConfigLabels != nil && recommend.Match == nil {
+			// The excluded Nodes do not take part in the MachineConfigPool tuning.
+			continue
+		}
+		if pc.profileMatches(recommend.Match, nodeName) {
+			return *recommend.Profile, recommend.Operand, nil
+		}
+	}
+
+	return defaultProfile, operand, nil
+}
+
+// profileDeferred returns true if TuneD profile 'tunedProfileName' is recommended
+// by a Tuned CR annotated with the tunedv1.TunedDeferredUpdate annotation.
+func (pc *ProfileCalculator) profileDeferred(tunedProfileName string) (bool, error) {
+	tunedList, err := pc.listers.TunedResources.List(labels.Everything())
+	if err != nil {
+		return false, fmt.Errorf("failed to list Tuned: %v", err)
+	}
+
+	for _, tuned := range tunedList {
+		if _, ok := tuned.ObjectMeta.Annotations[tunedv1.TunedDeferredUpdate]; !ok {
+			continue
+		}
+		for _, recommend := range tuned.Spec.Recommend {
+			if recommend.Profile != nil && *recommend.Profile == tunedProfileName {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// supplementalProfiles returns names of the TuneD profiles recommended for Node 'nodeName'
+// by the supplemental Tuned CRs (see tunedv1.TunedSupplementalLabel).  Each supplemental Tuned CR
+// contributes at most one profile, selected by its own recommend rules.  The profiles are ordered
+// by increasing precedence, i.e. the profiles which should win on conflicting settings come last.
+// Precedence is given by the recommend priority; ties are broken by the owner and the Tuned CR name.
+//
+// Only node/pod label matching is supported for supplemental profiles.
+func (pc *ProfileCalculator) supplementalProfiles(nodeName string) ([]string, error) {
+	type supplemental struct {
+		owner     string
+		tunedName string
+		recommend tunedv1.TunedRecommend
+	}
+
+	tunedList, err := pc.listers.TunedResources.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Tuned: %v", err)
+	}
+
+	var selected []supplemental
+	for _, tuned := range tunedList {
+		if !isTunedSupplemental(tuned) {
+			continue
+		}
+		for _, recommend := range TunedRecommend([]*tunedv1.Tuned{tuned}) {
+			if recommend.Profile == nil || len(*recommend.Profile) == 0 {
+				continue
+			}
+			if recommend.MachineConfigLabels != nil {
+				klog.V(2).Infof("ignoring machineConfigLabels of supplemental Tuned %s profile %s", tuned.Name, *recommend.Profile)
+				continue
+			}
+			if pc.profileMatches(recommend.Match, nodeName) {
+				selected = append(selected, supplemental{
+					owner:     tuned.Labels[tunedv1.TunedSupplementalLabel],
+					tunedName: tuned.Name,
+					recommend: recommend,
+				})
+				break
+			}
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		pi, pj := selected[i].recommend.Priority, selected[j].recommend.Priority
+		if pi != nil && pj != nil && *pi != *pj {
+			// Lower priority value means higher precedence; apply those profiles last.
+			return *pi > *pj
+		}
+		if (pi == nil) != (pj == nil) {
+			// Undefined priority has the lowest precedence.
+			return pi == nil
+		}
+		if selected[i].owner != selected[j].owner {
+			return selected[i].owner > selected[j].owner
+		}
+		return selected[i].tunedName > selected[j].tunedName
+	})
+
+	profiles := []string{}
+	seen := map[string]bool{}
+	for _, s := range selected {
+		if seen[*s.recommend.Profile] {
+			continue
+		}
+		seen[*s.recommend.Profile] = true
+		profiles = append(profiles, *s.recommend.Profile)
+	}
+
+	return profiles, nil
+}
+
+// mergeProfiles returns the TuneD daemon profile consisting of the recommended profile
+// 'tunedProfileName' merged with the supplemental profiles 'supplemental'.  The TuneD daemon
+// merges space-separated profiles in order, the later profiles overriding the earlier ones.
+func mergeProfiles(tunedProfileName string, supplemental []string) string {
+	profiles := []string{tunedProfileName}
+	for _, p := range supplemental {
+		if p != tunedProfileName {
+			profiles = append(profiles, p)
+		}
+	}
+	return strings.Join(profiles, " ")
+}
+
 // calculateProfileHyperShift calculates a tuned profile for Node nodeName.
 //
 // Returns
@@ -300,7 +475,7 @@ func (pc *ProfileCalculator) calculateProfileHyperShift(nodeName string) (string
 	}
 	tunedList = append(tunedList, defaultTuned)
 
-	recommendAll := TunedRecommend(tunedList)
+	recommendAll := recommendScheduled(TunedRecommend(tunedList), time.Now())
 	recommendProfile := func(nodeName string, iStart int) (int, string, string, tunedv1.OperandConfig, error) {
 		var i int
 		for i = iStart; i < len(recommendAll); i++ {
@@ -330,8 +505,10 @@ func (pc *ProfileCalculator) calculateProfileHyperShift(nodeName string) (string
 	if iStop == len(recommendAll) {
 		return defaultProfile, "", operand, fmt.Errorf("the default Tuned CR misses a catch-all profile selection")
 	}
+	pc.scheduleSet(nodeName, recommendAll[iStop].Schedule)
 
 	// Make sure we do not have multiple matching profiles with the same priority.  If so, report a warning.
+	var conflicting []string
 	for i := iStop + 1; i < len(recommendAll); i++ {
 		j, tunedProfileNameDup, _, _, err := recommendProfile(nodeName, i)
 		if err != nil {
@@ -357,12 +534,16 @@ func (pc *ProfileCalculator) calculateProfileHyperShift(nodeName string) (string
 			if tunedProfileName != tunedProfileNameDup {
 				klog.Warningf("profiles %s/%s have the same priority %d and match %s; please use a different priority for your custom profiles!",
 					tunedProfileName, tunedProfileNameDup, *recommendAll[i].Priority, nodeName)
+				if !slices.Contains(conflicting, tunedProfileNameDup) {
+					conflicting = append(conflicting, tunedProfileNameDup)
+				}
 			}
 		} else {
 			// We no longer have recommend rules with the same priority -- do not go through the entire (priority-ordered) list.
 			break
 		}
 	}
+	pc.priorityConflictSet(nodeName, recommendAll[iStop].Priority, tunedProfileName, conflicting)
 
 	return tunedProfileName, nodePoolName, operand, err
 }
@@ -518,6 +699,33 @@ func (pc *ProfileCalculator) nodeRemove(nodeName string) {
 
 	// Delete all data structures related to nodeName in podLabels
 	delete(pc.state.podLabels, nodeName)
+
+	delete(pc.state.excluded, nodeName)
+
+	delete(pc.state.schedules, nodeName)
+
+	delete(pc.state.priorityConflicts, nodeName)
+}
+
+// scheduleSet records the time window 'schedule' of the recommend rule selecting the profile of Node 'nodeName'.
+func (pc *ProfileCalculator) scheduleSet(nodeName string, schedule *tunedv1.TunedSchedule) {
+	if schedule == nil {
+		delete(pc.state.schedules, nodeName)
+		return
+	}
+	pc.state.schedules[nodeName] = schedule.String()
+}
+
+// priorityConflictSet records the TuneD profiles 'conflicting' recommended for Node 'nodeName' with the
+// same priority 'priority' as the selected profile 'tunedProfileName'.
+func (pc *ProfileCalculator) priorityConflictSet(nodeName string, priority *uint64, tunedProfileName string, conflicting []string) {
+	if priority == nil || len(conflicting) == 0 {
+		delete(pc.state.priorityConflicts, nodeName)
+		return
+	}
+	profiles := append([]string{tunedProfileName}, conflicting...)
+	sort.Strings(profiles)
+	pc.state.priorityConflicts[nodeName] = priorityConflict{priority: *priority, profiles: profiles}
 }
 
 // podRemove removes the reference of a Pod identified by namespace/name
@@ -626,6 +834,58 @@ func (pc *ProfileCalculator) getNodePoolNameForNode(node *corev1.Node) (string,
 	return nodePoolName, nil
 }
 
+// isTunedSupplemental returns true if Tuned CR 'tuned' ships supplemental TuneD profiles.
+func isTunedSupplemental(tuned *tunedv1.Tuned) bool {
+	return tuned.Labels[tunedv1.TunedSupplementalLabel] != ""
+}
+
+// tunedsPrimary returns the Tuned CRs from 'tunedSlice' which are not supplemental.
+func tunedsPrimary(tunedSlice []*tunedv1.Tuned) []*tunedv1.Tuned {
+	var primary []*tunedv1.Tuned
+	for _, tuned := range tunedSlice {
+		if !isTunedSupplemental(tuned) {
+			primary = append(primary, tuned)
+		}
+	}
+	return primary
+}
+
+// recommendScheduled returns the recommend rules 'recommendAll' without the rules whose
+// time window is not active at time 'now'.  The rules with an invalid window are skipped.
+func recommendScheduled(recommendAll []tunedv1.TunedRecommend, now time.Time) []tunedv1.TunedRecommend {
+	var recommendActive []tunedv1.TunedRecommend
+	for _, recommend := range recommendAll {
+		active, err := recommend.Schedule.IsActive(now)
+		if err != nil {
+			klog.Warningf("skipping the recommend rule of profile %s: %v", *recommend.Profile, err)
+			continue
+		}
+		if active {
+			recommendActive = append(recommendActive, recommend)
+		}
+	}
+	return recommendActive
+}
+
+// nextScheduleBoundary returns the first time after time 'now' a time window of the recommend
+// rules 'recommendAll' starts or ends, and false if none of the rules has a valid time window.
+func nextScheduleBoundary(recommendAll []tunedv1.TunedRecommend, now time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, recommend := range recommendAll {
+		if recommend.Schedule == nil {
+			continue
+		}
+		boundary, err := recommend.Schedule.NextBoundary(now)
+		if err != nil {
+			continue
+		}
+		if next.IsZero() || boundary.Before(next) {
+			next = boundary
+		}
+	}
+	return next, !next.IsZero()
+}
+
 // TunedRecommend returns a priority-sorted TunedRecommend slice out of
 // a slice of Tuned objects for profile-calculation purposes.
 func TunedRecommend(tunedSlice []*tunedv1.Tuned) []tunedv1.TunedRecommend {
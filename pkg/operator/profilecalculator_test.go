@@ -0,0 +1,264 @@
+package operator
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kcorelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+// newTestSupplementalTuned returns a supplemental Tuned of the owner 'owner' recommending the profile 'profile'
+// with the priority 'priority' for the Nodes labeled 'nodeLabel'.
+func newTestSupplementalTuned(name, owner, profile string, priority *uint64, nodeLabel string) *tunedv1.Tuned {
+	tuned := newTestTuned(name, nil)
+	tuned.Labels = map[string]string{tunedv1.TunedSupplementalLabel: owner}
+	tuned.Spec.Recommend = []tunedv1.TunedRecommend{
+		{
+			Profile:  &profile,
+			Priority: priority,
+			Match:    []tunedv1.TunedMatch{{Label: &nodeLabel}},
+		},
+	}
+	return tuned
+}
+
+func TestSupplementalProfiles(t *testing.T) {
+	priority := func(p uint64) *uint64 { return &p }
+
+	tests := []struct {
+		name     string
+		tuneds   []*tunedv1.Tuned
+		expected []string
+	}{
+		{
+			name: "no supplemental Tuned",
+			tuneds: []*tunedv1.Tuned{
+				newTestTuned("default", nil, "openshift-node"),
+			},
+			expected: []string{},
+		},
+		{
+			name: "the lower priority value wins and comes last",
+			tuneds: []*tunedv1.Tuned{
+				newTestSupplementalTuned("sriov", "sriov-network-operator", "sriov-supplemental", priority(10), "sriov"),
+				newTestSupplementalTuned("virt", "kubevirt", "virt-supplemental", priority(20), "virt"),
+			},
+			expected: []string{"virt-supplemental", "sriov-supplemental"},
+		},
+		{
+			name: "undefined priority has the lowest precedence",
+			tuneds: []*tunedv1.Tuned{
+				newTestSupplementalTuned("sriov", "sriov-network-operator", "sriov-supplemental", nil, "sriov"),
+				newTestSupplementalTuned("virt", "kubevirt", "virt-supplemental", priority(20), "virt"),
+			},
+			expected: []string{"sriov-supplemental", "virt-supplemental"},
+		},
+		{
+			name: "same priority, the alphabetically first owner wins",
+			tuneds: []*tunedv1.Tuned{
+				newTestSupplementalTuned("sriov", "sriov-network-operator", "sriov-supplemental", priority(10), "sriov"),
+				newTestSupplementalTuned("virt", "kubevirt", "virt-supplemental", priority(10), "virt"),
+			},
+			expected: []string{"sriov-supplemental", "virt-supplemental"},
+		},
+		{
+			name: "same priority and owner, the alphabetically first Tuned wins",
+			tuneds: []*tunedv1.Tuned{
+				newTestSupplementalTuned("virt-b", "kubevirt", "virt-b-supplemental", priority(10), "virt"),
+				newTestSupplementalTuned("virt-a", "kubevirt", "virt-a-supplemental", priority(10), "virt"),
+			},
+			expected: []string{"virt-b-supplemental", "virt-a-supplemental"},
+		},
+		{
+			name: "the Tuned matching other Nodes does not contribute",
+			tuneds: []*tunedv1.Tuned{
+				newTestSupplementalTuned("sriov", "sriov-network-operator", "sriov-supplemental", priority(10), "sriov"),
+				newTestSupplementalTuned("gpu", "gpu-operator", "gpu-supplemental", priority(10), "gpu"),
+			},
+			expected: []string{"sriov-supplemental"},
+		},
+		{
+			name: "a profile recommended twice is merged once",
+			tuneds: []*tunedv1.Tuned{
+				newTestSupplementalTuned("sriov", "sriov-network-operator", "shared-supplemental", priority(10), "sriov"),
+				newTestSupplementalTuned("virt", "kubevirt", "shared-supplemental", priority(20), "virt"),
+			},
+			expected: []string{"shared-supplemental"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pc := newTestProfileCalculator(t, tc.tuneds...)
+			pc.state.nodeLabels["node1"] = map[string]string{"sriov": "", "virt": ""}
+
+			got, err := pc.supplementalProfiles("node1")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected the supplemental profiles %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestMergeProfiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		supplemental []string
+		expected     string
+	}{
+		{
+			name:     "no supplemental profiles",
+			expected: "openshift-node",
+		},
+		{
+			name:         "supplemental profiles follow the recommended profile in order",
+			supplemental: []string{"virt-supplemental", "sriov-supplemental"},
+			expected:     "openshift-node virt-supplemental sriov-supplemental",
+		},
+		{
+			name:         "the recommended profile is not repeated",
+			supplemental: []string{"openshift-node", "sriov-supplemental"},
+			expected:     "openshift-node sriov-supplemental",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := mergeProfiles("openshift-node", tc.supplemental)
+			if got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+			if primary := tunedv1.PrimaryTunedProfile(got); primary != "openshift-node" {
+				t.Errorf("expected the primary profile %q, got %q", "openshift-node", primary)
+			}
+		})
+	}
+}
+
+func TestNodeChangeHandlerExclude(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+	if err := indexer.Add(node); err != nil {
+		t.Fatal(err)
+	}
+	pc := newTestProfileCalculator(t)
+	pc.listers.Nodes = kcorelisters.NewNodeLister(indexer)
+
+	tests := []struct {
+		name             string
+		annotations      map[string]string
+		expectedChange   bool
+		expectedExcluded bool
+	}{
+		{
+			name:           "not annotated",
+			expectedChange: false,
+		},
+		{
+			name:             "annotated",
+			annotations:      map[string]string{tunedv1.TunedExcludeAnnotationKey: "true"},
+			expectedChange:   true,
+			expectedExcluded: true,
+		},
+		{
+			name:             "still annotated",
+			annotations:      map[string]string{tunedv1.TunedExcludeAnnotationKey: "true"},
+			expectedChange:   false,
+			expectedExcluded: true,
+		},
+		{
+			name:           "annotated with a value other than true",
+			annotations:    map[string]string{tunedv1.TunedExcludeAnnotationKey: "false"},
+			expectedChange: true,
+		},
+		{
+			name:             "annotated again",
+			annotations:      map[string]string{tunedv1.TunedExcludeAnnotationKey: "true"},
+			expectedChange:   true,
+			expectedExcluded: true,
+		},
+		{
+			name:           "annotation removed",
+			expectedChange: true,
+		},
+	}
+
+	// the cases run in order against the same ProfileCalculator state
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			n := node.DeepCopy()
+			n.Annotations = tc.annotations
+			if err := indexer.Update(n); err != nil {
+				t.Fatal(err)
+			}
+
+			change, err := pc.nodeChangeHandler(n.Name)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if change != tc.expectedChange {
+				t.Errorf("expected the change %t, got %t", tc.expectedChange, change)
+			}
+			if pc.state.excluded[n.Name] != tc.expectedExcluded {
+				t.Errorf("expected the Node excluded %t, got %t", tc.expectedExcluded, pc.state.excluded[n.Name])
+			}
+		})
+	}
+}
+
+func TestCalculateDefaultProfile(t *testing.T) {
+	nodeLabel := "node-role.kubernetes.io/worker"
+	poolProfile, nodeProfile, customProfile := "openshift-node-pool", "openshift-node", "openshift-node-custom"
+	tuned := newTestTuned(tunedv1.TunedDefaultResourceName, nil)
+	tuned.Spec.Recommend = []tunedv1.TunedRecommend{
+		{
+			Profile:             &poolProfile,
+			MachineConfigLabels: map[string]string{"machineconfiguration.openshift.io/role": "worker-rt"},
+		},
+		{
+			Profile: &nodeProfile,
+			Match:   []tunedv1.TunedMatch{{Label: &nodeLabel}},
+		},
+	}
+	custom := newTestTuned("custom", nil)
+	custom.Spec.Recommend = []tunedv1.TunedRecommend{
+		{
+			Profile: &customProfile,
+			Match:   []tunedv1.TunedMatch{{Label: &nodeLabel}},
+		},
+	}
+
+	pc := newTestProfileCalculator(t, tuned, custom)
+	pc.state.nodeLabels["node1"] = map[string]string{nodeLabel: ""}
+	pc.state.nodeLabels["node2"] = map[string]string{}
+
+	tests := []struct {
+		node     string
+		expected string
+	}{
+		// the MachineConfigPool recommend and the other Tuned CRs are skipped
+		{node: "node1", expected: "openshift-node"},
+		// nothing matches, fall back to the operator default
+		{node: "node2", expected: defaultProfile},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.node, func(t *testing.T) {
+			got, _, err := pc.calculateDefaultProfile(tc.node)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected the profile %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
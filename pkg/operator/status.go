@@ -4,6 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
@@ -19,10 +23,14 @@ import (
 	ntoconfig "github.com/openshift/cluster-node-tuning-operator/pkg/config"
 	ntomf "github.com/openshift/cluster-node-tuning-operator/pkg/manifests"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/metrics"
+	tunedpkg "github.com/openshift/cluster-node-tuning-operator/pkg/tuned"
 )
 
 const (
 	errGenerationMismatch = "generation mismatch"
+	// tunedStatusSyncPeriod bounds the frequency of the default Tuned status updates;
+	// the status summarizes all the Profiles, which keep changing on large clusters.
+	tunedStatusSyncPeriod = 30 * time.Second
 )
 
 // syncOperatorStatus computes the operator's current status and therefrom
@@ -110,6 +118,17 @@ func profileApplied(profile *tunedv1.Profile) bool {
 	return false
 }
 
+// profileConverged returns true if Profile 'profile' of Node 'node' has been applied in the
+// current boot of the Node, or its application was deferred, and it is not Degraded.
+func profileConverged(profile *tunedv1.Profile, node *corev1.Node) bool {
+	if profileDegraded(profile) || (!profileApplied(profile) && !profileApplicationDeferred(profile)) {
+		return false
+	}
+
+	// The Profiles updated by the operands not reporting the boot ID are considered applied.
+	return profile.Status.BootID == "" || profile.Status.BootID == node.Status.NodeInfo.BootID
+}
+
 // profileDegraded returns true if Profile 'profile' is Degraded.
 // The Degraded ProfileStatusCondition occurs when a TuneD reports errors applying
 // the profile or when there is a timeout waiting for the profile to be applied.
@@ -127,9 +146,26 @@ func profileDegraded(profile *tunedv1.Profile) bool {
 	return false
 }
 
+// profileApplicationDeferred returns true if the application of the TuneD profile
+// for Profile 'profile' was deferred by the operand.
+func profileApplicationDeferred(profile *tunedv1.Profile) bool {
+	if profile == nil {
+		return false
+	}
+
+	for _, sc := range profile.Status.Conditions {
+		if sc.Type == tunedv1.TunedProfileApplied && sc.Reason == tunedpkg.ProfileAppliedDeferredReason {
+			return true
+		}
+	}
+
+	return false
+}
+
 // numProfilesProgressingDegraded returns two ints which count
 // the number of Profiles in the slice 'profileList' which are
 // waiting to be applied and in a degraded state, respectively.
+// Profiles with deferred application are not considered progressing.
 func numProfilesProgressingDegraded(profileList []*tunedv1.Profile) (int, int) {
 	numDegraded := 0
 	numProgressing := 0
@@ -138,7 +174,7 @@ func numProfilesProgressingDegraded(profileList []*tunedv1.Profile) (int, int) {
 			numDegraded++
 			continue
 		}
-		if !profileApplied(profile) {
+		if !profileApplied(profile) && !profileApplicationDeferred(profile) {
 			numProgressing++
 		}
 	}
@@ -161,6 +197,103 @@ func (c *Controller) numProfilesWithBootcmdlineConflict(profileList []*tunedv1.P
 	return numConflict
 }
 
+// enqueueTunedStatus schedules the update of the default Tuned status summary. The pending
+// updates are merged by the workqueue, so a burst of Profile changes updates the summary once.
+func (c *Controller) enqueueTunedStatus() {
+	c.workqueue.AddAfter(wqKey{kind: wqKindTunedStatus, namespace: ntoconfig.WatchNamespace(), name: tunedv1.TunedDefaultResourceName}, tunedStatusSyncPeriod)
+}
+
+// syncTunedStatus summarizes the status of the Profiles of all the Nodes in the status of
+// the default Tuned 'tuned', so the clients do not need to list all the Profiles.
+func (c *Controller) syncTunedStatus(tuned *tunedv1.Tuned) error {
+	profileList, err := c.listers.TunedProfiles.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list Tuned Profiles: %v", err)
+	}
+
+	nodes := computeNodesStatus(profileList)
+	conflicts := computePriorityConflicts(c.pc.state.priorityConflicts)
+	if tuned.Status.Nodes != nil {
+		nodes.LastUpdateTime = tuned.Status.Nodes.LastUpdateTime
+		if reflect.DeepEqual(nodes, tuned.Status.Nodes) && reflect.DeepEqual(conflicts, tuned.Status.PriorityConflicts) {
+			klog.V(2).Infof("syncTunedStatus(): Tuned %s status doesn't need updating", tuned.Name)
+			return nil
+		}
+	}
+	nodes.LastUpdateTime = metav1.Now()
+
+	tuned = tuned.DeepCopy() // never update the objects from cache
+	tuned.Status.Nodes = nodes
+	tuned.Status.PriorityConflicts = conflicts
+
+	klog.V(2).Infof("syncTunedStatus(): updating Tuned %s status", tuned.Name)
+	_, err = c.clients.Tuned.TunedV1().Tuneds(tuned.Namespace).UpdateStatus(context.TODO(), tuned, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update Tuned %s status: %v", tuned.Name, err)
+	}
+	return nil
+}
+
+// computeNodesStatus aggregates the status of the Profiles 'profileList'. The Profiles with
+// deferred application are neither applied nor progressing.
+func computeNodesStatus(profileList []*tunedv1.Profile) *tunedv1.TunedNodesStatus {
+	nodes := &tunedv1.TunedNodesStatus{
+		Total: int32(len(profileList)),
+	}
+
+	tunedProfiles := map[string]int32{}
+	for _, profile := range profileList {
+		tunedProfiles[tunedv1.PrimaryTunedProfile(profile.Spec.Config.TunedProfile)]++
+
+		switch {
+		case profileDegraded(profile):
+			nodes.Degraded++
+		case profileApplied(profile):
+			nodes.Applied++
+		case !profileApplicationDeferred(profile):
+			nodes.Progressing++
+		}
+	}
+
+	for name, count := range tunedProfiles {
+		nodes.Profiles = append(nodes.Profiles, tunedv1.TunedProfileNodes{Name: name, Nodes: count})
+	}
+	sort.Slice(nodes.Profiles, func(i, j int) bool {
+		return nodes.Profiles[i].Name < nodes.Profiles[j].Name
+	})
+
+	return nodes
+}
+
+// computePriorityConflicts aggregates the TuneD profiles recommended with the same priority for the
+// same Nodes 'nodeConflicts', sorted by priority and profile names.
+func computePriorityConflicts(nodeConflicts map[string]priorityConflict) []tunedv1.TunedPriorityConflict {
+	var conflicts []tunedv1.TunedPriorityConflict
+
+	index := map[string]int{}
+	for _, nc := range nodeConflicts {
+		key := fmt.Sprintf("%d/%s", nc.priority, strings.Join(nc.profiles, ","))
+		if i, ok := index[key]; ok {
+			conflicts[i].Nodes++
+			continue
+		}
+		index[key] = len(conflicts)
+		conflicts = append(conflicts, tunedv1.TunedPriorityConflict{
+			Priority: nc.priority,
+			Profiles: nc.profiles,
+			Nodes:    1,
+		})
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Priority != conflicts[j].Priority {
+			return conflicts[i].Priority < conflicts[j].Priority
+		}
+		return strings.Join(conflicts[i].Profiles, ",") < strings.Join(conflicts[j].Profiles, ",")
+	})
+
+	return conflicts
+}
+
 // computeStatus computes the operator's current status.
 func (c *Controller) computeStatus(tuned *tunedv1.Tuned, conditions []configv1.ClusterOperatorStatusCondition) ([]configv1.ClusterOperatorStatusCondition, string, error) {
 	const (
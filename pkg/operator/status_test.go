@@ -0,0 +1,216 @@
+package operator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	ntoclient "github.com/openshift/cluster-node-tuning-operator/pkg/client"
+	tunedfake "github.com/openshift/cluster-node-tuning-operator/pkg/generated/clientset/versioned/fake"
+	ntolisters "github.com/openshift/cluster-node-tuning-operator/pkg/generated/listers/tuned/v1"
+	tunedpkg "github.com/openshift/cluster-node-tuning-operator/pkg/tuned"
+)
+
+// newTestProfile returns the Profile of a Node recommended the TuneD profile 'tunedProfile' and the TuneD
+// daemon reporting the ProfileApplied and Degraded conditions 'applied' and 'degraded'.
+func newTestProfile(tunedProfile string, applied, degraded corev1.ConditionStatus) *tunedv1.Profile {
+	profile := &tunedv1.Profile{}
+	profile.Spec.Config.TunedProfile = tunedProfile
+	profile.Status.TunedProfile = tunedProfile
+	profile.Status.Conditions = []tunedv1.ProfileStatusCondition{
+		{Type: tunedv1.TunedProfileApplied, Status: applied},
+		{Type: tunedv1.TunedDegraded, Status: degraded},
+	}
+	return profile
+}
+
+func TestComputeNodesStatusSupplemental(t *testing.T) {
+	profiles := []*tunedv1.Profile{
+		newTestProfile("openshift-node", corev1.ConditionTrue, corev1.ConditionFalse),
+		newTestProfile("openshift-node sriov-supplemental", corev1.ConditionTrue, corev1.ConditionFalse),
+		newTestProfile("openshift-node-performance sriov-supplemental virt-supplemental", corev1.ConditionTrue, corev1.ConditionFalse),
+	}
+
+	// the Nodes are counted per recommended TuneD profile, the supplemental profiles merged on top of it aside
+	expected := []tunedv1.TunedProfileNodes{
+		{Name: "openshift-node", Nodes: 2},
+		{Name: "openshift-node-performance", Nodes: 1},
+	}
+	nodes := computeNodesStatus(profiles)
+	if !reflect.DeepEqual(nodes.Profiles, expected) {
+		t.Errorf("expected the Nodes per TuneD profile %v, got %v", expected, nodes.Profiles)
+	}
+	if nodes.Applied != 3 {
+		t.Errorf("expected the merged profiles applied on 3 Nodes, got %d", nodes.Applied)
+	}
+}
+
+func TestComputeNodesStatus(t *testing.T) {
+	deferred := newTestProfile("openshift-node", corev1.ConditionFalse, corev1.ConditionFalse)
+	deferred.Status.Conditions[0].Reason = tunedpkg.ProfileAppliedDeferredReason
+	pending := newTestProfile("openshift-node", corev1.ConditionTrue, corev1.ConditionFalse)
+	pending.Spec.Config.TunedProfile = "openshift-node-performance"
+
+	tests := []struct {
+		name     string
+		profiles []*tunedv1.Profile
+		expected tunedv1.TunedNodesStatus
+	}{
+		{
+			name:     "no Profiles",
+			expected: tunedv1.TunedNodesStatus{},
+		},
+		{
+			name: "applied, progressing and degraded",
+			profiles: []*tunedv1.Profile{
+				newTestProfile("openshift-node", corev1.ConditionTrue, corev1.ConditionFalse),
+				newTestProfile("openshift-node", corev1.ConditionFalse, corev1.ConditionFalse),
+				newTestProfile("openshift-node", corev1.ConditionTrue, corev1.ConditionTrue),
+			},
+			expected: tunedv1.TunedNodesStatus{
+				Total:       3,
+				Applied:     1,
+				Progressing: 1,
+				Degraded:    1,
+				Profiles:    []tunedv1.TunedProfileNodes{{Name: "openshift-node", Nodes: 3}},
+			},
+		},
+		{
+			name:     "deferred is neither applied nor progressing",
+			profiles: []*tunedv1.Profile{deferred},
+			expected: tunedv1.TunedNodesStatus{
+				Total:    1,
+				Profiles: []tunedv1.TunedProfileNodes{{Name: "openshift-node", Nodes: 1}},
+			},
+		},
+		{
+			name:     "the Node is counted for the recommended profile not applied yet",
+			profiles: []*tunedv1.Profile{pending},
+			expected: tunedv1.TunedNodesStatus{
+				Total:       1,
+				Progressing: 1,
+				Profiles:    []tunedv1.TunedProfileNodes{{Name: "openshift-node-performance", Nodes: 1}},
+			},
+		},
+		{
+			name: "the profiles are sorted by name",
+			profiles: []*tunedv1.Profile{
+				newTestProfile("openshift-node-performance", corev1.ConditionTrue, corev1.ConditionFalse),
+				newTestProfile("openshift-control-plane", corev1.ConditionTrue, corev1.ConditionFalse),
+				newTestProfile("openshift-node", corev1.ConditionTrue, corev1.ConditionFalse),
+			},
+			expected: tunedv1.TunedNodesStatus{
+				Total:   3,
+				Applied: 3,
+				Profiles: []tunedv1.TunedProfileNodes{
+					{Name: "openshift-control-plane", Nodes: 1},
+					{Name: "openshift-node", Nodes: 1},
+					{Name: "openshift-node-performance", Nodes: 1},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := computeNodesStatus(tc.profiles)
+			if !reflect.DeepEqual(*got, tc.expected) {
+				t.Errorf("expected the Nodes status %+v, got %+v", tc.expected, *got)
+			}
+		})
+	}
+}
+
+func TestComputePriorityConflicts(t *testing.T) {
+	nodeConflicts := map[string]priorityConflict{
+		"node1": {priority: 20, profiles: []string{"openshift-node-a", "openshift-node-b"}},
+		"node2": {priority: 10, profiles: []string{"openshift-node-c", "openshift-node-d"}},
+		"node3": {priority: 20, profiles: []string{"openshift-node-a", "openshift-node-b"}},
+		"node4": {priority: 10, profiles: []string{"openshift-node-a", "openshift-node-b"}},
+	}
+
+	// the conflicts of the same profiles with the same priority are merged, the lower priority values first
+	expected := []tunedv1.TunedPriorityConflict{
+		{Priority: 10, Profiles: []string{"openshift-node-a", "openshift-node-b"}, Nodes: 1},
+		{Priority: 10, Profiles: []string{"openshift-node-c", "openshift-node-d"}, Nodes: 1},
+		{Priority: 20, Profiles: []string{"openshift-node-a", "openshift-node-b"}, Nodes: 2},
+	}
+	got := computePriorityConflicts(nodeConflicts)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the priority conflicts %v, got %v", expected, got)
+	}
+
+	if got := computePriorityConflicts(map[string]priorityConflict{}); got != nil {
+		t.Errorf("expected no priority conflicts, got %v", got)
+	}
+}
+
+func TestSyncTunedStatus(t *testing.T) {
+	profileIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, name := range []string{"node1", "node2"} {
+		profile := newTestProfile("openshift-node", corev1.ConditionTrue, corev1.ConditionFalse)
+		profile.Name = name
+		profile.Namespace = testNamespace
+		if err := profileIndexer.Add(profile); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tuned := newTestTuned(tunedv1.TunedDefaultResourceName, nil, "openshift-node")
+	listers := &ntoclient.Listers{
+		TunedProfiles: ntolisters.NewProfileLister(profileIndexer).Profiles(testNamespace),
+	}
+	clients := &ntoclient.Clients{Tuned: tunedfake.NewSimpleClientset(tuned)}
+	c := &Controller{
+		listers: listers,
+		clients: clients,
+		pc:      NewProfileCalculator(listers, clients),
+	}
+	c.pc.state.priorityConflicts["node2"] = priorityConflict{priority: 20, profiles: []string{"openshift-node-a", "openshift-node-b"}}
+
+	if err := c.syncTunedStatus(tuned); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	updated, err := clients.Tuned.TunedV1().Tuneds(testNamespace).Get(context.TODO(), tuned.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := updated.Status.Nodes
+	if nodes == nil || nodes.Total != 2 || nodes.Applied != 2 || nodes.LastUpdateTime.IsZero() {
+		t.Errorf("expected the 2 Nodes applied reported with the update time, got %+v", nodes)
+	}
+	if len(updated.Status.PriorityConflicts) != 1 || updated.Status.PriorityConflicts[0].Nodes != 1 {
+		t.Errorf("expected the priority conflict of 1 Node, got %v", updated.Status.PriorityConflicts)
+	}
+
+	// the status is not updated when the summary did not change
+	clients.Tuned.(*tunedfake.Clientset).ClearActions()
+	if err := c.syncTunedStatus(updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actions := clients.Tuned.(*tunedfake.Clientset).Actions(); len(actions) != 0 {
+		t.Errorf("expected no status update, got %v", actions)
+	}
+
+	// the Profile changes are summarized
+	degraded := newTestProfile("openshift-node", corev1.ConditionTrue, corev1.ConditionTrue)
+	degraded.Name = "node2"
+	degraded.Namespace = testNamespace
+	if err := profileIndexer.Update(degraded); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.syncTunedStatus(updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	updated, err = clients.Tuned.TunedV1().Tuneds(testNamespace).Get(context.TODO(), tuned.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodes := updated.Status.Nodes; nodes.Applied != 1 || nodes.Degraded != 1 {
+		t.Errorf("expected 1 Node applied and 1 Node degraded, got %+v", nodes)
+	}
+}
@@ -0,0 +1,79 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+// notConvergedTaint is the taint keeping the pods not tolerating it off the Nodes whose
+// Profile is not applied in the current Node boot or is degraded.
+var notConvergedTaint = corev1.Taint{
+	Key:    tunedv1.TunedNotConvergedTaintKey,
+	Effect: corev1.TaintEffectNoSchedule,
+}
+
+// syncNodeTaint taints Node 'nodeName' with notConvergedTaint if the node taint management
+// of the default Tuned 'tuned' is enabled and the Node Profile did not converge yet.
+// Otherwise, it removes the taint.
+func (c *Controller) syncNodeTaint(tuned *tunedv1.Tuned, nodeName string) error {
+	if !c.node.informerEnabled {
+		return nil
+	}
+
+	node, err := c.listers.Nodes.Get(nodeName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get Node %s: %v", nodeName, err)
+	}
+
+	taint := false
+	if tuned.Spec.NodeTaintManagement == tunedv1.NodeTaintManagementManaged {
+		profile, err := c.listers.TunedProfiles.Get(nodeName)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// The Node has no Profile (yet), e.g. it is not a Linux Node.
+				return nil
+			}
+			return fmt.Errorf("failed to get Profile %s: %v", nodeName, err)
+		}
+		taint = !profileConverged(profile, node)
+	}
+
+	taints := []corev1.Taint{}
+	for _, t := range node.Spec.Taints {
+		if !t.MatchTaint(&notConvergedTaint) {
+			taints = append(taints, t)
+		}
+	}
+	tainted := len(taints) != len(node.Spec.Taints)
+	if taint == tainted {
+		return nil
+	}
+
+	if taint {
+		taints = append(taints, notConvergedTaint)
+	}
+	node = node.DeepCopy() // never update the objects from cache
+	node.Spec.Taints = taints
+
+	_, err = c.clients.Kube.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update Node %s taints: %v", nodeName, err)
+	}
+	if taint {
+		klog.Infof("tainted Node %s with %s as its Profile did not converge", nodeName, tunedv1.TunedNotConvergedTaintKey)
+	} else {
+		klog.Infof("removed the %s taint from Node %s", tunedv1.TunedNotConvergedTaintKey, nodeName)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package operator
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	kcorelisters "k8s.io/client-go/listers/core/v1"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	ntoclient "github.com/openshift/cluster-node-tuning-operator/pkg/client"
+	ntolisters "github.com/openshift/cluster-node-tuning-operator/pkg/generated/listers/tuned/v1"
+)
+
+func TestSyncNodeTaint(t *testing.T) {
+	otherTaint := corev1.Taint{Key: "example.com/other", Effect: corev1.TaintEffectNoExecute}
+
+	applied := newTestProfile("openshift-node", corev1.ConditionTrue, corev1.ConditionFalse)
+	degraded := newTestProfile("openshift-node", corev1.ConditionTrue, corev1.ConditionTrue)
+	previousBoot := applied.DeepCopy()
+	previousBoot.Status.BootID = "previous-boot"
+
+	tests := []struct {
+		name           string
+		management     tunedv1.NodeTaintManagement
+		profile        *tunedv1.Profile
+		taints         []corev1.Taint
+		updateErr      error
+		expectedTaints []corev1.Taint // nil when the Node is not written
+		expectedErr    bool
+	}{
+		{
+			name:           "taint the Node with a degraded Profile",
+			management:     tunedv1.NodeTaintManagementManaged,
+			profile:        degraded,
+			taints:         []corev1.Taint{otherTaint},
+			expectedTaints: []corev1.Taint{otherTaint, notConvergedTaint},
+		},
+		{
+			name:           "taint the Node with a Profile applied in a previous boot",
+			management:     tunedv1.NodeTaintManagementManaged,
+			profile:        previousBoot,
+			expectedTaints: []corev1.Taint{notConvergedTaint},
+		},
+		{
+			name:       "the tainted Node with a degraded Profile is left alone",
+			management: tunedv1.NodeTaintManagementManaged,
+			profile:    degraded,
+			taints:     []corev1.Taint{notConvergedTaint},
+		},
+		{
+			name:           "remove the taint once the Profile converged",
+			management:     tunedv1.NodeTaintManagementManaged,
+			profile:        applied,
+			taints:         []corev1.Taint{notConvergedTaint, otherTaint},
+			expectedTaints: []corev1.Taint{otherTaint},
+		},
+		{
+			name:       "the untainted Node with a converged Profile is left alone",
+			management: tunedv1.NodeTaintManagementManaged,
+			profile:    applied,
+		},
+		{
+			name:       "the Node without a Profile is left alone",
+			management: tunedv1.NodeTaintManagementManaged,
+		},
+		{
+			name:           "remove the taint when the node taint management is disabled",
+			management:     tunedv1.NodeTaintManagementUnmanaged,
+			profile:        degraded,
+			taints:         []corev1.Taint{notConvergedTaint},
+			expectedTaints: []corev1.Taint{},
+		},
+		{
+			name:           "the failed Node update is returned",
+			management:     tunedv1.NodeTaintManagementManaged,
+			profile:        degraded,
+			updateErr:      fmt.Errorf("conflict"),
+			expectedTaints: []corev1.Taint{notConvergedTaint},
+			expectedErr:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+				Spec:       corev1.NodeSpec{Taints: tc.taints},
+			}
+			nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if err := nodeIndexer.Add(node); err != nil {
+				t.Fatal(err)
+			}
+			profileIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if tc.profile != nil {
+				profile := tc.profile.DeepCopy()
+				profile.Name = node.Name
+				profile.Namespace = testNamespace
+				if err := profileIndexer.Add(profile); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			kube := kubefake.NewSimpleClientset(node)
+			if tc.updateErr != nil {
+				kube.PrependReactor("update", "nodes", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tc.updateErr
+				})
+			}
+			c := &Controller{
+				listers: &ntoclient.Listers{
+					Nodes:         kcorelisters.NewNodeLister(nodeIndexer),
+					TunedProfiles: ntolisters.NewProfileLister(profileIndexer).Profiles(testNamespace),
+				},
+				clients: &ntoclient.Clients{Kube: kube},
+			}
+			c.node.informerEnabled = true
+			tuned := newTestTuned(tunedv1.TunedDefaultResourceName, nil, "openshift-node")
+			tuned.Spec.NodeTaintManagement = tc.management
+
+			err := c.syncNodeTaint(tuned, node.Name)
+			if tc.expectedErr != (err != nil) {
+				t.Fatalf("expected an error %t, got %v", tc.expectedErr, err)
+			}
+
+			var updates []clienttesting.UpdateAction
+			for _, action := range kube.Actions() {
+				if update, ok := action.(clienttesting.UpdateAction); ok && action.GetVerb() == "update" {
+					updates = append(updates, update)
+				}
+			}
+			if tc.expectedTaints == nil {
+				if len(updates) != 0 {
+					t.Errorf("expected no Node update, got %d", len(updates))
+				}
+				return
+			}
+			if len(updates) != 1 {
+				t.Fatalf("expected 1 Node update, got %d", len(updates))
+			}
+			taints := updates[0].GetObject().(*corev1.Node).Spec.Taints
+			if !reflect.DeepEqual(taints, tc.expectedTaints) {
+				t.Errorf("expected the taints %v, got %v", tc.expectedTaints, taints)
+			}
+			// the Node from the lister cache is never modified
+			if !reflect.DeepEqual(node.Spec.Taints, tc.taints) {
+				t.Errorf("expected the cached Node taints %v, got %v", tc.taints, node.Spec.Taints)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Package advisor recommends how to split the CPUs of a performance profile between the reserved
+// and the isolated sets, based on the CPU usage measured on a node running the profile.
+package advisor
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"k8s.io/utils/cpuset"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+)
+
+const (
+	// DefaultHighWatermark is the reserved CPUs average usage above which more reserved CPUs are recommended
+	DefaultHighWatermark = 0.8
+	// DefaultLowWatermark is the reserved CPUs average usage below which less reserved CPUs are recommended
+	DefaultLowWatermark = 0.3
+
+	// isolatedInterruptsThreshold is the interrupts rate, per second, above which an isolated CPU is reported
+	isolatedInterruptsThreshold = 100
+)
+
+// Usage describes the load measured on the node CPUs.
+type Usage struct {
+	// CPUs holds the busy ratio, between 0 and 1, of every CPU over the measured window.
+	CPUs map[int]float64
+	// Interrupts holds the interrupts per second served by every CPU, it is empty when not measured.
+	Interrupts map[int]float64
+	// Cores maps every CPU to the physical core it belongs to, it is empty when the topology is unknown.
+	Cores map[int]string
+}
+
+// Watermarks defines the reserved CPUs average usage bounds within which the reserved set is left as it is.
+type Watermarks struct {
+	High float64
+	Low  float64
+}
+
+// Recommendation describes the proposed CPU split.
+type Recommendation struct {
+	Reserved cpuset.CPUSet
+	Isolated cpuset.CPUSet
+	// ReservedUsage is the average busy ratio of the current reserved CPUs.
+	ReservedUsage float64
+	// Notes explains the recommendation.
+	Notes []string
+
+	currentReserved cpuset.CPUSet
+}
+
+// Changed returns true when the recommendation differs from the profile CPU split.
+func (r *Recommendation) Changed() bool {
+	return !r.Reserved.Equals(r.currentReserved)
+}
+
+// Recommend returns the reserved and isolated CPUs that keep the reserved CPUs average usage
+// between the watermarks. CPUs are moved between the sets by whole physical cores when the node
+// topology is known, and the reserved set never becomes empty.
+func Recommend(profile *performancev2.PerformanceProfile, usage *Usage, watermarks Watermarks) (*Recommendation, error) {
+	if profile.Spec.CPU == nil || profile.Spec.CPU.Reserved == nil || profile.Spec.CPU.Isolated == nil {
+		return nil, fmt.Errorf("performance profile %q should define both reserved and isolated CPUs", profile.Name)
+	}
+	if watermarks.Low <= 0 || watermarks.High > 1 || watermarks.Low >= watermarks.High {
+		return nil, fmt.Errorf("watermarks should satisfy 0 < low (%v) < high (%v) <= 1", watermarks.Low, watermarks.High)
+	}
+
+	reserved, err := cpuset.Parse(string(*profile.Spec.CPU.Reserved))
+	if err != nil {
+		return nil, err
+	}
+	isolated, err := cpuset.Parse(string(*profile.Spec.CPU.Isolated))
+	if err != nil {
+		return nil, err
+	}
+
+	var busy float64
+	for _, cpu := range reserved.List() {
+		value, ok := usage.CPUs[cpu]
+		if !ok {
+			return nil, fmt.Errorf("no usage measured for the reserved CPU %d", cpu)
+		}
+		busy += value
+	}
+
+	rec := &Recommendation{
+		Reserved:        reserved,
+		Isolated:        isolated,
+		ReservedUsage:   busy / float64(reserved.Size()),
+		currentReserved: reserved,
+	}
+	rec.Notes = append(rec.Notes, fmt.Sprintf("reserved CPUs %s average usage is %.0f%%", reserved, rec.ReservedUsage*100))
+
+	// aim at the middle of the watermarks, so the recommendation does not flip on the next run
+	target := (watermarks.High + watermarks.Low) / 2
+	needed := int(math.Ceil(busy / target))
+	if needed < 1 {
+		needed = 1
+	}
+
+	switch {
+	case rec.ReservedUsage > watermarks.High:
+		for _, core := range coresOf(isolated, usage.Cores) {
+			if rec.Reserved.Size() >= needed {
+				break
+			}
+			rec.Reserved = rec.Reserved.Union(core)
+			rec.Isolated = rec.Isolated.Difference(core)
+		}
+		if rec.Reserved.Size() < needed {
+			rec.Notes = append(rec.Notes, fmt.Sprintf("%d reserved CPUs are needed, but there are not enough isolated CPUs left", needed))
+		}
+	case rec.ReservedUsage < watermarks.Low:
+		cores := coresOf(reserved, usage.Cores)
+		// give back the highest cores first and always keep the first one
+		for i := len(cores) - 1; i > 0; i-- {
+			if rec.Reserved.Size()-cores[i].Size() < needed {
+				break
+			}
+			rec.Reserved = rec.Reserved.Difference(cores[i])
+			rec.Isolated = rec.Isolated.Union(cores[i])
+		}
+	}
+
+	if rec.Changed() {
+		rec.Notes = append(rec.Notes, fmt.Sprintf("%d reserved CPUs keep the average usage around %.0f%%", rec.Reserved.Size(), target*100))
+	} else {
+		rec.Notes = append(rec.Notes, "the reserved CPUs count fits the measured usage")
+	}
+
+	for _, cpu := range isolated.List() {
+		if rate := usage.Interrupts[cpu]; rate > isolatedInterruptsThreshold {
+			rec.Notes = append(rec.Notes, fmt.Sprintf("isolated CPU %d serves %.0f interrupts per second", cpu, rate))
+		}
+	}
+	return rec, nil
+}
+
+// Apply returns a copy of the profile using the recommended CPU split.
+func Apply(profile *performancev2.PerformanceProfile, rec *Recommendation) *performancev2.PerformanceProfile {
+	out := profile.DeepCopy()
+	reserved := performancev2.CPUSet(rec.Reserved.String())
+	isolated := performancev2.CPUSet(rec.Isolated.String())
+	out.Spec.CPU.Reserved = &reserved
+	out.Spec.CPU.Isolated = &isolated
+	return out
+}
+
+// coresOf groups the CPUs of the set by physical core, only the cores whose CPUs all belong to the set
+// are returned, ordered by their lowest CPU. Every CPU is its own core when the topology is unknown.
+func coresOf(cpus cpuset.CPUSet, cores map[int]string) []cpuset.CPUSet {
+	siblings := map[string][]int{}
+	for cpu, core := range cores {
+		siblings[core] = append(siblings[core], cpu)
+	}
+
+	var groups []cpuset.CPUSet
+	seen := map[string]bool{}
+	for _, cpu := range cpus.List() {
+		core, ok := cores[cpu]
+		if !ok {
+			groups = append(groups, cpuset.New(cpu))
+			continue
+		}
+		if seen[core] {
+			continue
+		}
+		seen[core] = true
+
+		group := cpuset.New(siblings[core]...)
+		if group.IsSubsetOf(cpus) {
+			groups = append(groups, group)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].List()[0] < groups[j].List()[0]
+	})
+	return groups
+}
@@ -0,0 +1,13 @@
+package advisor
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAdvisor(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Advisor Suite")
+}
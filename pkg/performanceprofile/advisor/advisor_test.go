@@ -0,0 +1,121 @@
+package advisor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	testutils "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/utils/testing"
+)
+
+var watermarks = Watermarks{High: DefaultHighWatermark, Low: DefaultLowWatermark}
+
+// newUsage returns the usage of an 8 CPUs node, where CPU n and n+4 are siblings
+func newUsage(busy map[int]float64) *Usage {
+	usage := &Usage{
+		CPUs:       map[int]float64{},
+		Interrupts: map[int]float64{},
+		Cores:      map[int]string{},
+	}
+	for cpu := 0; cpu < 8; cpu++ {
+		usage.CPUs[cpu] = busy[cpu]
+		usage.Cores[cpu] = "0/" + string(rune('0'+cpu%4))
+	}
+	return usage
+}
+
+func newProfile(reserved, isolated string) *performancev2.PerformanceProfile {
+	profile := testutils.NewPerformanceProfile("test")
+	reservedSet := performancev2.CPUSet(reserved)
+	isolatedSet := performancev2.CPUSet(isolated)
+	profile.Spec.CPU.Reserved = &reservedSet
+	profile.Spec.CPU.Isolated = &isolatedSet
+	return profile
+}
+
+var _ = Describe("Advisor", func() {
+	It("should keep the reserved CPUs when the usage is within the watermarks", func() {
+		rec, err := Recommend(newProfile("0,4", "1-3,5-7"), newUsage(map[int]float64{0: 0.5, 4: 0.6}), watermarks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Changed()).To(BeFalse())
+		Expect(rec.ReservedUsage).To(BeNumerically("~", 0.55))
+	})
+
+	It("should grow the reserved CPUs by whole cores", func() {
+		rec, err := Recommend(newProfile("0,4", "1-3,5-7"), newUsage(map[int]float64{0: 0.95, 4: 0.9}), watermarks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Changed()).To(BeTrue())
+		Expect(rec.Reserved.String()).To(Equal("0-1,4-5"))
+		Expect(rec.Isolated.String()).To(Equal("2-3,6-7"))
+
+		profile := Apply(newProfile("0,4", "1-3,5-7"), rec)
+		Expect(string(*profile.Spec.CPU.Reserved)).To(Equal("0-1,4-5"))
+		Expect(string(*profile.Spec.CPU.Isolated)).To(Equal("2-3,6-7"))
+	})
+
+	It("should shrink the reserved CPUs but keep at least one core", func() {
+		rec, err := Recommend(newProfile("0-1,4-5", "2-3,6-7"), newUsage(map[int]float64{0: 0.05, 1: 0.05, 4: 0.05, 5: 0.05}), watermarks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Reserved.String()).To(Equal("0,4"))
+		Expect(rec.Isolated.String()).To(Equal("1-3,5-7"))
+
+		rec, err = Recommend(newProfile("0,4", "1-3,5-7"), newUsage(nil), watermarks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Changed()).To(BeFalse())
+	})
+
+	It("should move single CPUs when the topology is unknown", func() {
+		usage := newUsage(map[int]float64{0: 0.95, 4: 0.9})
+		usage.Cores = map[int]string{}
+		rec, err := Recommend(newProfile("0,4", "1-3,5-7"), usage, watermarks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Reserved.String()).To(Equal("0-2,4"))
+	})
+
+	It("should report the interrupts served by the isolated CPUs", func() {
+		usage := newUsage(map[int]float64{0: 0.5, 4: 0.5})
+		usage.Interrupts[3] = 2500
+		rec, err := Recommend(newProfile("0,4", "1-3,5-7"), usage, watermarks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Notes).To(ContainElement("isolated CPU 3 serves 2500 interrupts per second"))
+	})
+
+	It("should fail without the usage of the reserved CPUs", func() {
+		_, err := Recommend(newProfile("0,4", "1-3,5-7"), &Usage{}, watermarks)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should read the node usage from the Prometheus API", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal(queryPath))
+			Expect(r.Header.Get("Authorization")).To(Equal("Bearer secret"))
+
+			query := r.URL.Query().Get("query")
+			Expect(query).To(ContainSubstring(`instance="worker-0"`))
+			switch {
+			case strings.HasPrefix(query, "quantile_over_time"):
+				Expect(query).To(ContainSubstring("[3600s:5m]"))
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+					`{"metric":{"cpu":"0"},"value":[1700000000,"0.25"]},{"metric":{"cpu":"1"},"value":[1700000000,"0.5"]}]}}`))
+			case strings.HasPrefix(query, "node_cpu_info"):
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+					`{"metric":{"cpu":"0","core":"0","package":"0"},"value":[1700000000,"1"]},{"metric":{"cpu":"1","core":"0","package":"0"},"value":[1700000000,"1"]}]}}`))
+			default:
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+			}
+		}))
+		defer server.Close()
+
+		usage, err := NewPrometheusClient(server.URL, "secret", false).Usage(context.Background(), "worker-0", time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(usage.CPUs).To(Equal(map[int]float64{0: 0.25, 1: 0.5}))
+		Expect(usage.Interrupts).To(BeEmpty())
+		Expect(usage.Cores).To(Equal(map[int]string{0: "0/0", 1: "0/0"}))
+	})
+})
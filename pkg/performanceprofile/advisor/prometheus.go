@@ -0,0 +1,154 @@
+package advisor
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// the busy ratio of every CPU, the 95th percentile of the 5 minutes averages over the window
+	queryCPUUsage = `quantile_over_time(0.95, (1 - rate(node_cpu_seconds_total{mode="idle",instance=%q}[5m]))[%s:5m])`
+	// the interrupts served by every CPU per second, averaged over the window
+	queryInterrupts = `sum by (cpu) (rate(node_interrupts_total{instance=%q}[%s]))`
+	// the physical core of every CPU
+	queryCPUInfo = `node_cpu_info{instance=%q}`
+
+	labelCPU     = "cpu"
+	labelCore    = "core"
+	labelPackage = "package"
+
+	queryPath = "/api/v1/query"
+)
+
+// PrometheusClient queries the node exporter metrics from a Prometheus compatible API.
+type PrometheusClient struct {
+	URL        string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewPrometheusClient returns a client of the Prometheus API served under the URL.
+func NewPrometheusClient(address, token string, insecureSkipTLSVerify bool) *PrometheusClient {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: insecureSkipTLSVerify}
+
+	return &PrometheusClient{
+		URL:   address,
+		Token: token,
+		HTTPClient: &http.Client{
+			Transport: transport,
+			Timeout:   time.Minute,
+		},
+	}
+}
+
+type sample struct {
+	Metric map[string]string `json:"metric"`
+	Value  [2]interface{}    `json:"value"`
+}
+
+type queryResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string   `json:"resultType"`
+		Result     []sample `json:"result"`
+	} `json:"data"`
+}
+
+// Usage returns the CPUs usage of the node over the window preceding now.
+func (c *PrometheusClient) Usage(ctx context.Context, node string, window time.Duration) (*Usage, error) {
+	promWindow := fmt.Sprintf("%ds", int64(window.Seconds()))
+
+	cpuUsage, err := c.queryPerCPU(ctx, fmt.Sprintf(queryCPUUsage, node, promWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the CPU usage: %w", err)
+	}
+	if len(cpuUsage) == 0 {
+		return nil, fmt.Errorf("no CPU usage found for the node %q", node)
+	}
+
+	interrupts, err := c.queryPerCPU(ctx, fmt.Sprintf(queryInterrupts, node, promWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the interrupts rate: %w", err)
+	}
+
+	samples, err := c.query(ctx, fmt.Sprintf(queryCPUInfo, node))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the CPU topology: %w", err)
+	}
+	cores := map[int]string{}
+	for _, s := range samples {
+		cpu, err := strconv.Atoi(s.Metric[labelCPU])
+		if err != nil {
+			continue
+		}
+		cores[cpu] = s.Metric[labelPackage] + "/" + s.Metric[labelCore]
+	}
+
+	return &Usage{
+		CPUs:       cpuUsage,
+		Interrupts: interrupts,
+		Cores:      cores,
+	}, nil
+}
+
+func (c *PrometheusClient) queryPerCPU(ctx context.Context, query string) (map[int]float64, error) {
+	samples, err := c.query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[int]float64{}
+	for _, s := range samples {
+		cpu, err := strconv.Atoi(s.Metric[labelCPU])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected cpu label %q: %w", s.Metric[labelCPU], err)
+		}
+		raw, ok := s.Value[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected value %v of CPU %d", s.Value[1], cpu)
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[cpu] = value
+	}
+	return values, nil
+}
+
+func (c *PrometheusClient) query(ctx context.Context, query string) ([]sample, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL+queryPath+"?"+url.Values{"query": {query}}.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &queryResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, fmt.Errorf("failed to decode the response, status %q: %w", resp.Status, err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("query failed with %s: %s", result.ErrorType, result.Error)
+	}
+	if result.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("unexpected result type %q", result.Data.ResultType)
+	}
+	return result.Data.Result, nil
+}
@@ -0,0 +1,499 @@
+// Package artifactdiff renders a corpus of PerformanceProfiles with the current code and compares the
+// generated artifacts with the golden artifacts of a previous operator release.  The comparison results
+// in a machine-readable report listing the changed artifacts and whether applying them reboots the
+// nodes, so the reboots triggered by an operator upgrade are known before the upgrade.
+//
+// The golden artifacts are the output of the render command of the previous release run on the corpus:
+//
+//	cluster-node-tuning-operator render --owner-ref none --asset-input-dir <corpus> --asset-output-dir <golden>
+package artifactdiff
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing/fstest"
+
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/yaml"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/manifestset"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/semantic"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/utils/testing/tunedsim"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
+)
+
+// Change describes how an artifact changed since the golden artifacts.
+type Change string
+
+const (
+	// ChangeUnchanged is reported for the artifacts equal to their golden artifact
+	ChangeUnchanged Change = "Unchanged"
+	// ChangeModified is reported for the artifacts which differ from their golden artifact
+	ChangeModified Change = "Modified"
+	// ChangeAdded is reported for the artifacts without a golden artifact
+	ChangeAdded Change = "Added"
+	// ChangeRemoved is reported for the golden artifacts the current code does not render anymore
+	ChangeRemoved Change = "Removed"
+)
+
+const (
+	kindMachineConfig = "MachineConfig"
+	kindKubeletConfig = "KubeletConfig"
+	kindTuned         = "Tuned"
+	kindNode          = "Node"
+)
+
+// the annotations recording the operator version differ by definition between releases
+var ignoredAnnotations = []string{
+	components.OperatorVersionAnnotation,
+	components.ArtifactSchemaVersionAnnotation,
+}
+
+var (
+	decodeScheme   = runtime.NewScheme()
+	profileDecoder runtime.Decoder
+)
+
+func init() {
+	utilruntime.Must(performancev2.AddToScheme(decodeScheme))
+	profileDecoder = serializer.NewCodecFactory(decodeScheme).UniversalDecoder(performancev2.GroupVersion)
+}
+
+// UpgradeReport is the outcome of the comparison of the current artifacts with the golden artifacts.
+type UpgradeReport struct {
+	// Artifacts lists the current and the golden artifacts sorted by file name
+	Artifacts []ArtifactReport `json:"artifacts"`
+	// RebootingProfiles lists the profiles whose nodes reboot when the current artifacts are applied
+	RebootingProfiles []string `json:"rebootingProfiles"`
+}
+
+// ArtifactReport describes the change of an artifact.
+type ArtifactReport struct {
+	// File is the name of the file the render command writes the artifact to
+	File string `json:"file"`
+	// Profile is the name of the PerformanceProfile the artifact was rendered for
+	Profile string `json:"profile"`
+	// Kind is the kind of the artifact
+	Kind string `json:"kind"`
+	// Change describes how the artifact changed
+	Change Change `json:"change"`
+	// Reboot is true when applying the change reboots the nodes of the profile
+	Reboot bool `json:"reboot"`
+	// Details lists the changed items of the artifact, for example "file <path>" for the machine
+	// configs, "cmdline <profile>" for the Tuned profiles or the path of the other changed fields
+	Details []string `json:"details,omitempty"`
+}
+
+// LoadProfiles decodes the v2 PerformanceProfiles found in the YAML or JSON files under 'dir',
+// the other manifests, like the MachineConfigPools the render command needs, are skipped.
+func LoadProfiles(dir string) ([]*performancev2.PerformanceProfile, error) {
+	paths, err := util.ListFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []*performancev2.PerformanceProfile
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		manifests, err := util.ParseManifests(path, bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %q: %w", path, err)
+		}
+		for idx, m := range manifests {
+			obj, err := runtime.Decode(profileDecoder, m.Raw)
+			if err != nil {
+				if runtime.IsNotRegisteredError(err) {
+					continue
+				}
+				return nil, fmt.Errorf("error decoding %q [%d] manifest: %w", path, idx+1, err)
+			}
+			if profile, ok := obj.(*performancev2.PerformanceProfile); ok {
+				profiles = append(profiles, profile)
+			}
+		}
+	}
+	return profiles, nil
+}
+
+// Render renders the artifacts of the profiles with the current code, keyed by the name of the file
+// the render command writes them to.
+func Render(profiles []*performancev2.PerformanceProfile) (map[string][]byte, error) {
+	artifacts := map[string][]byte{}
+	for _, profile := range profiles {
+		// the render command uses the runc runtime unless a ContainerRuntimeConfig selects another one
+		manifests, err := manifestset.GetNewComponents(profile, &components.Options{
+			MachineConfig: components.MachineConfigOptions{
+				DefaultRuntime: mcov1.ContainerRuntimeDefaultRuntimeRunc,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to render the profile %q: %w", profile.Name, err)
+		}
+
+		for kind, manifest := range manifests.ToManifestTable() {
+			data, err := yaml.Marshal(manifest)
+			if err != nil {
+				return nil, err
+			}
+			artifacts[fmt.Sprintf("%s_%s.yaml", profile.Name, strings.ToLower(kind))] = data
+		}
+	}
+	return artifacts, nil
+}
+
+// ReadArtifacts reads the artifacts written under 'dir', keyed by file name.
+func ReadArtifacts(dir string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := map[string][]byte{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		artifacts[entry.Name()] = data
+	}
+	return artifacts, nil
+}
+
+// WriteArtifacts writes the artifacts under 'dir', for example to produce the golden artifacts.
+func WriteArtifacts(dir string, artifacts map[string][]byte) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	for file, data := range artifacts {
+		if err := os.WriteFile(filepath.Join(dir, file), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compare compares the current artifacts with the golden artifacts, both keyed by file name.
+func Compare(golden, current map[string][]byte) (*UpgradeReport, error) {
+	report := &UpgradeReport{
+		Artifacts:         []ArtifactReport{},
+		RebootingProfiles: []string{},
+	}
+	rebooting := map[string]bool{}
+	for _, file := range sortedKeys(golden, current) {
+		artifact, err := compareArtifact(file, golden[file], current[file])
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare %q: %w", file, err)
+		}
+		report.Artifacts = append(report.Artifacts, *artifact)
+		if artifact.Reboot && !rebooting[artifact.Profile] {
+			rebooting[artifact.Profile] = true
+			report.RebootingProfiles = append(report.RebootingProfiles, artifact.Profile)
+		}
+	}
+	sort.Strings(report.RebootingProfiles)
+	return report, nil
+}
+
+func compareArtifact(file string, golden, current []byte) (*ArtifactReport, error) {
+	artifact := &ArtifactReport{
+		File: file,
+	}
+	// the profile names do not contain underscores
+	if idx := strings.LastIndex(file, "_"); idx > 0 {
+		artifact.Profile = file[:idx]
+	}
+
+	goldenObj, err := decodeArtifact(golden)
+	if err != nil {
+		return nil, err
+	}
+	currentObj, err := decodeArtifact(current)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case goldenObj == nil:
+		artifact.Change = ChangeAdded
+		artifact.Kind = kindOf(currentObj)
+		artifact.Reboot, err = rebootsWhenApplied(artifact.Kind, current)
+		return artifact, err
+	case currentObj == nil:
+		artifact.Change = ChangeRemoved
+		artifact.Kind = kindOf(goldenObj)
+		artifact.Reboot, err = rebootsWhenApplied(artifact.Kind, golden)
+		return artifact, err
+	}
+
+	artifact.Kind = kindOf(currentObj)
+	switch artifact.Kind {
+	case kindMachineConfig:
+		err = compareMachineConfigs(artifact, golden, current)
+	case kindKubeletConfig:
+		err = compareKubeletConfigs(artifact, golden, current)
+	case kindTuned:
+		err = compareTuneds(artifact, golden, current)
+	default:
+		artifact.Details = fieldsDiff(goldenObj, currentObj)
+		// the MCO reboots the nodes to switch the cgroup mode
+		artifact.Reboot = artifact.Kind == kindNode && contains(artifact.Details, "spec.cgroupMode")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	artifact.Change = ChangeUnchanged
+	if len(artifact.Details) > 0 {
+		artifact.Change = ChangeModified
+	}
+	return artifact, nil
+}
+
+// rebootsWhenApplied returns true when creating or deleting the artifact reboots the nodes
+func rebootsWhenApplied(kind string, data []byte) (bool, error) {
+	switch kind {
+	case kindMachineConfig, kindKubeletConfig:
+		return true, nil
+	case kindTuned:
+		tuned := &tunedv1.Tuned{}
+		if err := yaml.Unmarshal(data, tuned); err != nil {
+			return false, err
+		}
+		cmdlines, err := RecommendedCmdlines(tuned)
+		if err != nil {
+			return false, err
+		}
+		for _, cmdline := range cmdlines {
+			if cmdline != "" {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func compareMachineConfigs(artifact *ArtifactReport, golden, current []byte) error {
+	goldenMC, currentMC := &mcov1.MachineConfig{}, &mcov1.MachineConfig{}
+	if err := yaml.Unmarshal(golden, goldenMC); err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(current, currentMC); err != nil {
+		return err
+	}
+
+	diff, err := semantic.MachineConfigSpecDiff(&goldenMC.Spec, &currentMC.Spec)
+	if err != nil {
+		return err
+	}
+	artifact.Reboot = len(diff) > 0
+	artifact.Details = append(metadataDiff(golden, current), diff...)
+	return nil
+}
+
+func compareKubeletConfigs(artifact *ArtifactReport, golden, current []byte) error {
+	goldenKC, currentKC := &mcov1.KubeletConfig{}, &mcov1.KubeletConfig{}
+	if err := yaml.Unmarshal(golden, goldenKC); err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(current, currentKC); err != nil {
+		return err
+	}
+
+	equal, err := semantic.KubeletConfigSpecEqual(&goldenKC.Spec, &currentKC.Spec)
+	if err != nil {
+		return err
+	}
+	artifact.Reboot = !equal
+	artifact.Details = metadataDiff(golden, current)
+	if !equal {
+		goldenObj, _ := decodeArtifact(golden)
+		currentObj, _ := decodeArtifact(current)
+		artifact.Details = append(artifact.Details, specDiff(goldenObj, currentObj)...)
+	}
+	return nil
+}
+
+func compareTuneds(artifact *ArtifactReport, golden, current []byte) error {
+	goldenTuned, currentTuned := &tunedv1.Tuned{}, &tunedv1.Tuned{}
+	if err := yaml.Unmarshal(golden, goldenTuned); err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(current, currentTuned); err != nil {
+		return err
+	}
+
+	artifact.Details = metadataDiff(golden, current)
+	goldenProfiles, currentProfiles := tunedProfiles(goldenTuned), tunedProfiles(currentTuned)
+	for _, name := range sortedKeys(goldenProfiles, currentProfiles) {
+		if goldenProfiles[name] != currentProfiles[name] {
+			artifact.Details = append(artifact.Details, "profile "+name)
+		}
+	}
+
+	// the operator reboots the nodes through a MachineConfig when the kernel command line of the
+	// recommended profile changes, the profiles are matched by recommendation as their names may change
+	goldenCmdlines, err := RecommendedCmdlines(goldenTuned)
+	if err != nil {
+		return err
+	}
+	currentCmdlines, err := RecommendedCmdlines(currentTuned)
+	if err != nil {
+		return err
+	}
+	for i := range currentCmdlines {
+		if i >= len(goldenCmdlines) || goldenCmdlines[i] != currentCmdlines[i] {
+			artifact.Details = append(artifact.Details, "cmdline "+*currentTuned.Spec.Recommend[i].Profile)
+			artifact.Reboot = true
+		}
+	}
+	if len(goldenCmdlines) > len(currentCmdlines) {
+		artifact.Reboot = true
+	}
+
+	if !apiequality.Semantic.DeepEqual(goldenTuned.Spec.Recommend, currentTuned.Spec.Recommend) {
+		artifact.Details = append(artifact.Details, "spec.recommend")
+	}
+	return nil
+}
+
+func tunedProfiles(tuned *tunedv1.Tuned) map[string]string {
+	profiles := map[string]string{}
+	for _, profile := range tuned.Spec.Profile {
+		if profile.Name == nil || profile.Data == nil {
+			continue
+		}
+		profiles[*profile.Name] = *profile.Data
+	}
+	return profiles
+}
+
+// RecommendedCmdlines returns the kernel command line the profile of every recommendation of the Tuned
+// sets through the TuneD bootloader plugin, out of the profile itself, the included profiles are not resolved
+func RecommendedCmdlines(tuned *tunedv1.Tuned) ([]string, error) {
+	profiles := tunedProfiles(tuned)
+	cmdlines := []string{}
+	for _, recommend := range tuned.Spec.Recommend {
+		if recommend.Profile == nil {
+			cmdlines = append(cmdlines, "")
+			continue
+		}
+		result, err := tunedsim.Simulate(profiles[*recommend.Profile], fstest.MapFS{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate the Tuned profile %q: %w", *recommend.Profile, err)
+		}
+		cmdlines = append(cmdlines, result.Cmdline)
+	}
+	return cmdlines, nil
+}
+
+// decodeArtifact decodes the artifact without the fields which do not change between two renderings
+// of the same configuration
+func decodeArtifact(data []byte) (map[string]interface{}, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	obj := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	delete(obj, "status")
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+		delete(metadata, "ownerReferences")
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			for _, annotation := range ignoredAnnotations {
+				delete(annotations, annotation)
+			}
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+	return obj, nil
+}
+
+func kindOf(obj map[string]interface{}) string {
+	kind, _ := obj["kind"].(string)
+	return kind
+}
+
+// fieldsDiff returns the paths of the changed fields, down to the second level, for example spec.handler
+func fieldsDiff(golden, current map[string]interface{}) []string {
+	var diff []string
+	for _, key := range sortedKeys(golden, current) {
+		if key == "apiVersion" || key == "kind" || apiequality.Semantic.DeepEqual(golden[key], current[key]) {
+			continue
+		}
+
+		goldenField, goldenIsMap := golden[key].(map[string]interface{})
+		currentField, currentIsMap := current[key].(map[string]interface{})
+		if !goldenIsMap || !currentIsMap {
+			diff = append(diff, key)
+			continue
+		}
+		for _, subKey := range sortedKeys(goldenField, currentField) {
+			if !apiequality.Semantic.DeepEqual(goldenField[subKey], currentField[subKey]) {
+				diff = append(diff, key+"."+subKey)
+			}
+		}
+	}
+	return diff
+}
+
+func metadataDiff(golden, current []byte) []string {
+	goldenObj, _ := decodeArtifact(golden)
+	currentObj, _ := decodeArtifact(current)
+	return fieldsDiff(
+		map[string]interface{}{"metadata": goldenObj["metadata"]},
+		map[string]interface{}{"metadata": currentObj["metadata"]},
+	)
+}
+
+func specDiff(golden, current map[string]interface{}) []string {
+	return fieldsDiff(
+		map[string]interface{}{"spec": golden["spec"]},
+		map[string]interface{}{"spec": current["spec"]},
+	)
+}
+
+func sortedKeys[T any](a, b map[string]T) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package artifactdiff
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestArtifactDiff(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Artifact Diff Suite")
+}
@@ -0,0 +1,116 @@
+package artifactdiff
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	testDataDir = "testdata"
+	// updateReportEnv rewrites the expected report instead of comparing with it, once the reboots
+	// the changes of the artifacts trigger are reviewed
+	updateReportEnv = "ARTIFACTDIFF_UPDATE_REPORT"
+)
+
+var _ = Describe("Artifacts comparison", func() {
+	It("should match the expected report of the corpus", func() {
+		profiles, err := LoadProfiles(filepath.Join(testDataDir, "corpus"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(profiles).ToNot(BeEmpty())
+
+		current, err := Render(profiles)
+		Expect(err).ToNot(HaveOccurred())
+		golden, err := ReadArtifacts(filepath.Join(testDataDir, "golden"))
+		Expect(err).ToNot(HaveOccurred())
+
+		report, err := Compare(golden, current)
+		Expect(err).ToNot(HaveOccurred())
+		data, err := json.MarshalIndent(report, "", "  ")
+		Expect(err).ToNot(HaveOccurred())
+		data = append(data, '\n')
+
+		reportPath := filepath.Join(testDataDir, "report.json")
+		if os.Getenv(updateReportEnv) != "" {
+			Expect(os.WriteFile(reportPath, data, 0644)).To(Succeed())
+		}
+		expected, err := os.ReadFile(reportPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(string(expected)), "the artifacts changed, review the reboots and run the test with %s=true", updateReportEnv)
+	})
+
+	It("should report the unchanged artifacts", func() {
+		golden, err := ReadArtifacts(filepath.Join(testDataDir, "golden"))
+		Expect(err).ToNot(HaveOccurred())
+
+		report, err := Compare(golden, golden)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Artifacts).To(HaveLen(len(golden)))
+		Expect(report.RebootingProfiles).To(BeEmpty())
+		for _, artifact := range report.Artifacts {
+			Expect(artifact.Change).To(Equal(ChangeUnchanged), artifact.File)
+			Expect(artifact.Details).To(BeEmpty(), artifact.File)
+		}
+	})
+
+	It("should report the added and removed artifacts", func() {
+		golden, err := ReadArtifacts(filepath.Join(testDataDir, "golden"))
+		Expect(err).ToNot(HaveOccurred())
+		current := map[string][]byte{
+			"added_machineconfig.yaml":  golden["minimal_machineconfig.yaml"],
+			"minimal_runtimeclass.yaml": golden["minimal_runtimeclass.yaml"],
+		}
+		golden = map[string][]byte{
+			"minimal_runtimeclass.yaml":  golden["minimal_runtimeclass.yaml"],
+			"minimal_kubeletconfig.yaml": golden["minimal_kubeletconfig.yaml"],
+		}
+
+		report, err := Compare(golden, current)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Artifacts).To(Equal([]ArtifactReport{
+			{File: "added_machineconfig.yaml", Profile: "added", Kind: "MachineConfig", Change: ChangeAdded, Reboot: true},
+			{File: "minimal_kubeletconfig.yaml", Profile: "minimal", Kind: "KubeletConfig", Change: ChangeRemoved, Reboot: true},
+			{File: "minimal_runtimeclass.yaml", Profile: "minimal", Kind: "RuntimeClass", Change: ChangeUnchanged},
+		}))
+		Expect(report.RebootingProfiles).To(Equal([]string{"added", "minimal"}))
+	})
+
+	It("should not report a reboot for the changes the nodes do not depend on", func() {
+		golden, err := ReadArtifacts(filepath.Join(testDataDir, "golden"))
+		Expect(err).ToNot(HaveOccurred())
+		relabeled := strings.Replace(string(golden["minimal_runtimeclass.yaml"]), "handler: high-performance", "handler: high-performance-crun", 1)
+		Expect(relabeled).ToNot(Equal(string(golden["minimal_runtimeclass.yaml"])))
+
+		report, err := Compare(
+			map[string][]byte{"minimal_runtimeclass.yaml": golden["minimal_runtimeclass.yaml"]},
+			map[string][]byte{"minimal_runtimeclass.yaml": []byte(relabeled)},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Artifacts).To(Equal([]ArtifactReport{
+			{File: "minimal_runtimeclass.yaml", Profile: "minimal", Kind: "RuntimeClass", Change: ChangeModified, Details: []string{"handler"}},
+		}))
+		Expect(report.RebootingProfiles).To(BeEmpty())
+	})
+
+	It("should report a reboot when the kernel command line of the recommended profile changes", func() {
+		golden, err := ReadArtifacts(filepath.Join(testDataDir, "golden"))
+		Expect(err).ToNot(HaveOccurred())
+		changed := strings.Replace(string(golden["minimal_tuned.yaml"]), "intel_iommu=on", "intel_iommu=off", 1)
+		Expect(changed).ToNot(Equal(string(golden["minimal_tuned.yaml"])))
+
+		report, err := Compare(
+			map[string][]byte{"minimal_tuned.yaml": golden["minimal_tuned.yaml"]},
+			map[string][]byte{"minimal_tuned.yaml": []byte(changed)},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Artifacts).To(Equal([]ArtifactReport{
+			{File: "minimal_tuned.yaml", Profile: "minimal", Kind: "Tuned", Change: ChangeModified, Reboot: true,
+				Details: []string{"profile openshift-node-performance-minimal", "cmdline openshift-node-performance-minimal"}},
+		}))
+		Expect(report.RebootingProfiles).To(Equal([]string{"minimal"}))
+	})
+})
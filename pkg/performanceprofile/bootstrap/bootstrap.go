@@ -0,0 +1,126 @@
+// Package bootstrap handles PerformanceProfiles delivered through a well-known ConfigMap.
+// Installation flows which cannot create PerformanceProfile CRs before the CRD exists can
+// serialize the profiles into the ConfigMap instead.  The render command consumes the profiles
+// from the ConfigMap to generate the bootstrap manifests and the operator adopts them into real
+// PerformanceProfile CRs on its first start.
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap holding the serialized PerformanceProfiles.
+	// Every data key of the ConfigMap may hold one or more YAML or JSON PerformanceProfile documents.
+	ConfigMapName = "performance-profile-bootstrap"
+	// AdoptedAnnotation is set on the ConfigMap once its PerformanceProfiles were adopted, the value
+	// is the adoption time.  Adopted ConfigMaps are ignored by the operator.
+	AdoptedAnnotation = "performance.openshift.io/bootstrap-adopted"
+)
+
+var (
+	decodeScheme  = runtime.NewScheme()
+	configDecoder runtime.Decoder
+)
+
+func init() {
+	utilruntime.Must(performancev2.AddToScheme(decodeScheme))
+	configDecoder = serializer.NewCodecFactory(decodeScheme).UniversalDecoder(performancev2.GroupVersion)
+}
+
+// IsConfigMap returns true when the ConfigMap is the well-known bootstrap ConfigMap of the namespace.
+func IsConfigMap(cm *corev1.ConfigMap, namespace string) bool {
+	return cm.Name == ConfigMapName && cm.Namespace == namespace
+}
+
+// ProfilesFromConfigMap decodes the PerformanceProfiles serialized in the ConfigMap data.
+// The data keys are processed in lexical order.
+func ProfilesFromConfigMap(cm *corev1.ConfigMap) ([]*performancev2.PerformanceProfile, error) {
+	keys := make([]string, 0, len(cm.Data))
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var profiles []*performancev2.PerformanceProfile
+	for _, key := range keys {
+		manifests, err := util.ParseManifests(key, bytes.NewBufferString(cm.Data[key]))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing ConfigMap %s/%s key %q: %w", cm.Namespace, cm.Name, key, err)
+		}
+
+		for idx, m := range manifests {
+			obj, err := runtime.Decode(configDecoder, m.Raw)
+			if err != nil {
+				return nil, fmt.Errorf("error decoding ConfigMap %s/%s key %q [%d] manifest: %w", cm.Namespace, cm.Name, key, idx+1, err)
+			}
+
+			profile, ok := obj.(*performancev2.PerformanceProfile)
+			if !ok {
+				return nil, fmt.Errorf("ConfigMap %s/%s key %q [%d] manifest is a %T, expected a PerformanceProfile", cm.Namespace, cm.Name, key, idx+1, obj)
+			}
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, nil
+}
+
+// Adopt creates the PerformanceProfiles found in the bootstrap ConfigMap of the namespace.
+// Profiles which already exist are left untouched.  Once all the profiles were adopted, the
+// ConfigMap is annotated so the profiles are not recreated on the next operator start.
+func Adopt(ctx context.Context, cli client.Client, namespace string) error {
+	cm := &corev1.ConfigMap{}
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ConfigMapName}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if _, ok := cm.Annotations[AdoptedAnnotation]; ok {
+		klog.V(4).Infof("bootstrap ConfigMap %s/%s already adopted", namespace, ConfigMapName)
+		return nil
+	}
+
+	profiles, err := ProfilesFromConfigMap(cm)
+	if err != nil {
+		return err
+	}
+
+	for _, profile := range profiles {
+		// the serialized profiles may come from the output of a live cluster
+		profile.ResourceVersion = ""
+		profile.UID = ""
+		if err := cli.Create(ctx, profile); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				klog.Infof("performance profile %q from the bootstrap ConfigMap already exists, skipping", profile.Name)
+				continue
+			}
+			return fmt.Errorf("failed to adopt performance profile %q: %w", profile.Name, err)
+		}
+		klog.Infof("adopted performance profile %q from the bootstrap ConfigMap %s/%s", profile.Name, namespace, ConfigMapName)
+	}
+
+	cmCopy := cm.DeepCopy()
+	if cmCopy.Annotations == nil {
+		cmCopy.Annotations = map[string]string{}
+	}
+	cmCopy.Annotations[AdoptedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	return cli.Update(ctx, cmCopy)
+}
@@ -0,0 +1,132 @@
+package bootstrap
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+)
+
+const (
+	testNamespace = "openshift-cluster-node-tuning-operator"
+
+	testProfiles = `apiVersion: performance.openshift.io/v2
+kind: PerformanceProfile
+metadata:
+  name: first
+spec:
+  cpu:
+    isolated: "2-3"
+    reserved: "0-1"
+  nodeSelector:
+    node-role.kubernetes.io/worker-cnf: ""
+---
+apiVersion: performance.openshift.io/v2
+kind: PerformanceProfile
+metadata:
+  name: second
+spec:
+  cpu:
+    isolated: "4-7"
+    reserved: "0-3"
+  nodeSelector:
+    node-role.kubernetes.io/worker-du: ""
+`
+)
+
+func newConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName,
+			Namespace: testNamespace,
+		},
+		Data: data,
+	}
+}
+
+func newFakeClient(objs ...runtime.Object) client.Client {
+	scheme := runtime.NewScheme()
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	Expect(performancev2.AddToScheme(scheme)).To(Succeed())
+	return fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+}
+
+var _ = Describe("Bootstrap ConfigMap", func() {
+	It("should match the well-known ConfigMap only", func() {
+		cm := newConfigMap(nil)
+		Expect(IsConfigMap(cm, testNamespace)).To(BeTrue())
+		Expect(IsConfigMap(cm, "default")).To(BeFalse())
+		cm.Name = "other"
+		Expect(IsConfigMap(cm, testNamespace)).To(BeFalse())
+	})
+
+	It("should decode the profiles of all the data keys", func() {
+		profiles, err := ProfilesFromConfigMap(newConfigMap(map[string]string{"profiles.yaml": testProfiles}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(profiles).To(HaveLen(2))
+		Expect(profiles[0].Name).To(Equal("first"))
+		Expect(string(*profiles[1].Spec.CPU.Isolated)).To(Equal("4-7"))
+	})
+
+	It("should reject objects other than PerformanceProfiles", func() {
+		_, err := ProfilesFromConfigMap(newConfigMap(map[string]string{"ns.yaml": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: foo\n"}))
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("when adopting the profiles", func() {
+		var ctx context.Context
+
+		BeforeEach(func() {
+			ctx = context.Background()
+		})
+
+		It("should do nothing without the ConfigMap", func() {
+			Expect(Adopt(ctx, newFakeClient(), testNamespace)).To(Succeed())
+		})
+
+		It("should create the profiles and mark the ConfigMap as adopted", func() {
+			cli := newFakeClient(newConfigMap(map[string]string{"profiles.yaml": testProfiles}))
+			Expect(Adopt(ctx, cli, testNamespace)).To(Succeed())
+
+			profiles := &performancev2.PerformanceProfileList{}
+			Expect(cli.List(ctx, profiles)).To(Succeed())
+			Expect(profiles.Items).To(HaveLen(2))
+
+			cm := &corev1.ConfigMap{}
+			Expect(cli.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: ConfigMapName}, cm)).To(Succeed())
+			Expect(cm.Annotations).To(HaveKey(AdoptedAnnotation))
+		})
+
+		It("should keep the existing profiles", func() {
+			existing := &performancev2.PerformanceProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "first"},
+			}
+			cli := newFakeClient(existing, newConfigMap(map[string]string{"profiles.yaml": testProfiles}))
+			Expect(Adopt(ctx, cli, testNamespace)).To(Succeed())
+
+			profile := &performancev2.PerformanceProfile{}
+			Expect(cli.Get(ctx, types.NamespacedName{Name: "first"}, profile)).To(Succeed())
+			Expect(profile.Spec.CPU).To(BeNil())
+		})
+
+		It("should not recreate the profiles of an adopted ConfigMap", func() {
+			cm := newConfigMap(map[string]string{"profiles.yaml": testProfiles})
+			cm.Annotations = map[string]string{AdoptedAnnotation: "2024-01-01T00:00:00Z"}
+			cli := newFakeClient(cm)
+			Expect(Adopt(ctx, cli, testNamespace)).To(Succeed())
+
+			profiles := &performancev2.PerformanceProfileList{}
+			Expect(cli.List(ctx, profiles)).To(Succeed())
+			Expect(profiles.Items).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,197 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package advise
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/advisor"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+)
+
+const defaultWindow = 24 * time.Hour
+
+type adviseOpts struct {
+	profile               string
+	node                  string
+	prometheusURL         string
+	token                 string
+	insecureSkipTLSVerify bool
+	window                time.Duration
+	highWatermark         float64
+	lowWatermark          float64
+	output                string
+}
+
+// NewAdviseCommand creates an advise command.
+// The advise command reads the CPU usage of a node running the supplied PerformanceProfile out of the
+// node exporter metrics, and writes the PerformanceProfile with the recommended reserved and isolated CPUs.
+func NewAdviseCommand() *cobra.Command {
+	adviseOpts := adviseOpts{}
+	adviseOpts.SetDefaults()
+
+	cmd := &cobra.Command{
+		Use:   "advise",
+		Short: "Recommend the reserved and isolated CPUs of a performance profile from the node metrics",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := adviseOpts.Validate(); err != nil {
+				klog.Fatal(err)
+			}
+
+			if err := adviseOpts.Run(cmd.Context()); err != nil {
+				klog.Fatal(err)
+			}
+		},
+	}
+
+	addKlogFlags(cmd)
+	adviseOpts.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func (a *adviseOpts) SetDefaults() {
+	a.window = defaultWindow
+	a.highWatermark = advisor.DefaultHighWatermark
+	a.lowWatermark = advisor.DefaultLowWatermark
+}
+
+func (a *adviseOpts) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&a.profile, "profile", a.profile, "Path of the PerformanceProfile manifest running on the node.")
+	fs.StringVar(&a.node, "node", a.node, "Name of the node to read the metrics of.")
+	fs.StringVar(&a.prometheusURL, "prometheus-url", a.prometheusURL, "URL of the Prometheus API serving the node exporter metrics, for example the thanos-querier route.")
+	fs.StringVar(&a.token, "token", a.token, "Bearer token used to query the Prometheus API. (Can be set with the PROMETHEUS_TOKEN environment variable.)")
+	fs.BoolVar(&a.insecureSkipTLSVerify, "insecure-skip-tls-verify", a.insecureSkipTLSVerify, "Skip the verification of the Prometheus API certificate.")
+	fs.DurationVar(&a.window, "window", a.window, "Time window of the metrics to read.")
+	fs.Float64Var(&a.highWatermark, "high-watermark", a.highWatermark, "Reserved CPUs average usage ratio above which more reserved CPUs are recommended.")
+	fs.Float64Var(&a.lowWatermark, "low-watermark", a.lowWatermark, "Reserved CPUs average usage ratio below which less reserved CPUs are recommended.")
+	fs.StringVar(&a.output, "output", a.output, "Path of the proposed PerformanceProfile manifest, the standard output when not set.")
+	// environment variables has precedence over standard input
+	a.readFlagsFromEnv()
+}
+
+func (a *adviseOpts) readFlagsFromEnv() {
+	if token := os.Getenv("PROMETHEUS_TOKEN"); len(token) > 0 {
+		a.token = token
+	}
+}
+
+func (a *adviseOpts) Validate() error {
+	if len(a.profile) == 0 {
+		return fmt.Errorf("profile must be specified")
+	}
+	if len(a.node) == 0 {
+		return fmt.Errorf("node must be specified")
+	}
+	if len(a.prometheusURL) == 0 {
+		return fmt.Errorf("prometheus-url must be specified")
+	}
+	if a.window < 10*time.Minute {
+		return fmt.Errorf("window should be at least 10 minutes long")
+	}
+	return nil
+}
+
+func (a *adviseOpts) Run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	profile, err := readProfile(a.profile)
+	if err != nil {
+		return err
+	}
+
+	client := advisor.NewPrometheusClient(a.prometheusURL, a.token, a.insecureSkipTLSVerify)
+	usage, err := client.Usage(ctx, a.node, a.window)
+	if err != nil {
+		return err
+	}
+
+	rec, err := advisor.Recommend(profile, usage, advisor.Watermarks{High: a.highWatermark, Low: a.lowWatermark})
+	if err != nil {
+		return err
+	}
+	for _, note := range rec.Notes {
+		klog.Info(note)
+	}
+	if rec.Changed() {
+		klog.Infof("proposed reserved CPUs %q and isolated CPUs %q", rec.Reserved, rec.Isolated)
+	}
+
+	return writeProfile(advisor.Apply(profile, rec), a.output)
+}
+
+func readProfile(path string) (*performancev2.PerformanceProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &performancev2.PerformanceProfile{}
+	if err := yaml.UnmarshalStrict(data, profile); err != nil {
+		return nil, fmt.Errorf("failed to parse the performance profile %q: %w", path, err)
+	}
+	if profile.APIVersion != performancev2.GroupVersion.String() || profile.Kind != "PerformanceProfile" {
+		return nil, fmt.Errorf("%q should hold a %s PerformanceProfile", path, performancev2.GroupVersion)
+	}
+	return profile, nil
+}
+
+func writeProfile(profile *performancev2.PerformanceProfile, path string) error {
+	// keep only the metadata needed to apply the proposed profile
+	profile.ObjectMeta = metav1.ObjectMeta{
+		Name:        profile.Name,
+		Labels:      profile.Labels,
+		Annotations: profile.Annotations,
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(profile)
+	if err != nil {
+		return err
+	}
+	unstructured.RemoveNestedField(obj, "status")
+	unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	if len(path) == 0 {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func addKlogFlags(cmd *cobra.Command) {
+	fs := flag.NewFlagSet("", flag.PanicOnError)
+	klog.InitFlags(fs)
+	cmd.Flags().AddGoFlagSet(fs)
+}
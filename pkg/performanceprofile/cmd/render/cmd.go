@@ -16,11 +16,13 @@ limitations under the License.
 package render
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/ociartifact"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -32,6 +34,14 @@ type renderOpts struct {
 	assetsInDir  string
 	assetsOutDir string
 	ownerRefMode string
+	outputFormat string
+	// push is the oci:// reference the rendered manifests are pushed to as an OCI artifact
+	push          string
+	pushAuthFile  string
+	pushPlainHTTP bool
+	// pinImageDigests pins the image references to the digests imported by the input ImageStreams and fails the
+	// render unless they resolve to digests of mirrored repositories
+	pinImageDigests bool
 }
 
 // NewRenderCommand creates a render command.
@@ -63,12 +73,18 @@ func NewRenderCommand() *cobra.Command {
 
 func (r *renderOpts) SetDefaults() {
 	r.ownerRefMode = ownerRefModeLabelName
+	r.outputFormat = outputFormatFlat
 }
 
 func (r *renderOpts) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&r.assetsInDir, "asset-input-dir", components.AssetsDir, "Input path for the assets directory. (Can be a comma separated list of directories.)")
 	fs.StringVar(&r.assetsOutDir, "asset-output-dir", r.assetsOutDir, "Output path for the rendered manifests.")
 	fs.StringVar(&r.ownerRefMode, "owner-ref", r.ownerRefMode, "Add Owner Reference to rendered manifests. Accepted values: 'none' to disable; 'k8s' for proper owner reference; 'label-name' to use just a label.")
+	fs.StringVar(&r.outputFormat, "output-format", r.outputFormat, "Layout of the rendered manifests. Accepted values: 'flat' for all the manifests in the output directory; 'kustomize' for a kustomize base with per-pool overlays.")
+	fs.StringVar(&r.push, "push", r.push, "Push the rendered manifests as an OCI artifact to the reference, e.g. 'oci://quay.io/ztp/tuning:v1'.")
+	fs.StringVar(&r.pushAuthFile, "push-authfile", r.pushAuthFile, "Path of the registry credentials file used by --push, in the containers auth.json format.")
+	fs.BoolVar(&r.pushPlainHTTP, "push-plain-http", r.pushPlainHTTP, "Access the registry of --push over HTTP instead of HTTPS.")
+	fs.BoolVar(&r.pinImageDigests, "pin-image-digests", r.pinImageDigests, "Pin the image references of the rendered manifests to the digests imported by the ImageStream manifests of the input directory, and require them to be digests of the repositories mirrored by the ImageDigestMirrorSet and ImageContentSourcePolicy manifests of the input directory, for the disconnected image-based installs and upgrades.")
 	// environment variables has precedence over standard input
 	r.readFlagsFromEnv()
 }
@@ -84,20 +100,61 @@ func (r *renderOpts) readFlagsFromEnv() {
 	if ownerRefMode, ok := os.LookupEnv("OWNER_REF"); ok {
 		r.ownerRefMode = ownerRefMode
 	}
+	if outputFormat, ok := os.LookupEnv("OUTPUT_FORMAT"); ok {
+		r.outputFormat = outputFormat
+	}
+	if push, ok := os.LookupEnv("PUSH"); ok {
+		r.push = push
+	}
+	if pushAuthFile, ok := os.LookupEnv("PUSH_AUTHFILE"); ok {
+		r.pushAuthFile = pushAuthFile
+	}
+	if pushPlainHTTP, ok := os.LookupEnv("PUSH_PLAIN_HTTP"); ok {
+		r.pushPlainHTTP = pushPlainHTTP == "true"
+	}
+	if pinImageDigests, ok := os.LookupEnv("PIN_IMAGE_DIGESTS"); ok {
+		r.pinImageDigests = pinImageDigests == "true"
+	}
 }
 
 func (r *renderOpts) Validate() error {
 	if !isValidOwnerRefMode(r.ownerRefMode) {
 		return fmt.Errorf("unsupported owner reference: %q", r.ownerRefMode)
 	}
+	if !isValidOutputFormat(r.outputFormat) {
+		return fmt.Errorf("unsupported output format: %q", r.outputFormat)
+	}
 	if len(r.assetsOutDir) == 0 {
 		return fmt.Errorf("asset-output-dir must be specified")
 	}
+	if len(r.push) > 0 {
+		if _, err := ociartifact.ParseReference(r.push); err != nil {
+			return fmt.Errorf("invalid push reference: %w", err)
+		}
+	}
 	return nil
 }
 
 func (r *renderOpts) Run() error {
-	return render(r.ownerRefMode, r.assetsInDir, r.assetsOutDir)
+	output := newRenderOutput(r.assetsOutDir, r.outputFormat)
+	output.pinImageDigests = r.pinImageDigests
+	if err := render(r.ownerRefMode, r.assetsInDir, output); err != nil {
+		return err
+	}
+	if len(r.push) == 0 {
+		return nil
+	}
+
+	pushed, err := pushOutput(context.TODO(), output, r.push, ociartifact.PushOptions{
+		AuthFile:  r.pushAuthFile,
+		PlainHTTP: r.pushPlainHTTP,
+	})
+	if err != nil {
+		return err
+	}
+	// the pipelines pull the artifact by its digest
+	fmt.Println(pushed)
+	return nil
 }
 
 func addKlogFlags(cmd *cobra.Command) {
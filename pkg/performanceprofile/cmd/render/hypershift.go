@@ -0,0 +1,73 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/nodepool"
+)
+
+// renderNodePoolConfig writes the manifests the operator generates for the NodePool configuration.  The hosted cluster
+// objects are labeled with the NodePool name, the MachineConfig and the KubeletConfig are wrapped in the ConfigMaps
+// the NodePool controller merges into the NodePool ignition payload.
+func renderNodePoolConfig(ownerRefMode string, output *renderOutput, cfg *nodepool.Config) error {
+	for _, t := range cfg.Tuneds {
+		nodepool.SetTunedNodePool(t, cfg.NodePoolName)
+		if err := output.writeObject("", fmt.Sprintf("%s_%s_tuned.yaml", cfg.NodePoolName, t.Name), t); err != nil {
+			return err
+		}
+	}
+
+	for _, pp := range cfg.Profiles {
+		components, err := nodepool.RenderProfile(pp, cfg.NodePoolName)
+		if err != nil {
+			return err
+		}
+
+		if ownerRefMode == ownerRefModeK8S {
+			err = addOwnerReference(components, pp)
+			if err != nil {
+				return err
+			}
+		} else if ownerRefMode == ownerRefModeLabelName {
+			err = addWeakOwnerReferenceLabel(components, pp)
+			if err != nil {
+				return err
+			}
+		}
+
+		output.addProfile(pp.Name)
+		for kind, manifest := range components.ToManifestTable() {
+			var obj interface{} = manifest
+			pool := ""
+			if kind == components.MachineConfig.Kind || kind == components.KubeletConfig.Kind {
+				obj, err = nodepool.NewMachineConfigConfigMap(kind, pp.Name, cfg.NodePoolName, manifest)
+				if err != nil {
+					return err
+				}
+				pool = cfg.NodePoolName
+			}
+
+			err = output.writeObject(pool, fmt.Sprintf("%s_%s_%s.yaml", cfg.NodePoolName, pp.Name, strings.ToLower(kind)), obj)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
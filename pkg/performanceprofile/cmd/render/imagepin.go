@@ -0,0 +1,178 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	apicfgv1 "github.com/openshift/api/config/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	operatorv1alpha1 "github.com/openshift/api/operator/v1alpha1"
+)
+
+// imageFields are the fields of the rendered manifests holding an image reference
+var imageFields = map[string]bool{
+	"image":                          true,
+	"osImageURL":                     true,
+	"baseOSExtensionsContainerImage": true,
+}
+
+// imageDigestMirrors are the repositories mirrored by the ImageDigestMirrorSet and ImageContentSourcePolicy
+// manifests of the render input.  The mirrors only serve the images referenced by digest, so the image references
+// of the manifests rendered for the disconnected image-based installs and upgrades are pinned to the digests the
+// ImageStream manifests of the render input imported, and must resolve to a digest of a mirrored repository.
+type imageDigestMirrors struct {
+	// sources are the mirrored repositories, or their registries or namespaces
+	sources []string
+	// digests maps the image references by tag to the image references by digest they were imported as
+	digests map[string]string
+}
+
+func newImageDigestMirrors(idmss []*apicfgv1.ImageDigestMirrorSet, icsps []*operatorv1alpha1.ImageContentSourcePolicy, imageStreams []*imagev1.ImageStream) *imageDigestMirrors {
+	m := &imageDigestMirrors{digests: map[string]string{}}
+	for _, idms := range idmss {
+		for _, mirror := range idms.Spec.ImageDigestMirrors {
+			if len(mirror.Mirrors) > 0 {
+				m.sources = append(m.sources, mirror.Source)
+			}
+		}
+	}
+	for _, icsp := range icsps {
+		for _, mirror := range icsp.Spec.RepositoryDigestMirrors {
+			if len(mirror.Mirrors) > 0 {
+				m.sources = append(m.sources, mirror.Source)
+			}
+		}
+	}
+	for _, is := range imageStreams {
+		m.addImageStreamDigests(is)
+	}
+	return m
+}
+
+// addImageStreamDigests records the digests the ImageStream 'is' imported its tags from the image references by tag
+// as.  The latest image of a tag status is the image the tag points to.
+func (m *imageDigestMirrors) addImageStreamDigests(is *imagev1.ImageStream) {
+	imported := map[string]string{}
+	for _, tag := range is.Status.Tags {
+		if len(tag.Items) > 0 && isPinned(tag.Items[0].DockerImageReference) {
+			imported[tag.Tag] = tag.Items[0].DockerImageReference
+		}
+	}
+	for _, tag := range is.Spec.Tags {
+		if tag.From == nil || tag.From.Kind != "DockerImage" || isPinned(tag.From.Name) {
+			continue
+		}
+		if digest, ok := imported[tag.Name]; ok {
+			m.digests[tag.From.Name] = digest
+		}
+	}
+}
+
+// isMirrored returns true when the repository 'repository' is mirrored.  The sources match the repositories the
+// same way the registries configuration of the nodes does, see containers-registries.conf(5).
+func (m *imageDigestMirrors) isMirrored(repository string) bool {
+	for _, source := range m.sources {
+		if wildcard, ok := strings.CutPrefix(source, "*."); ok {
+			host, _, _ := strings.Cut(repository, "/")
+			if strings.HasSuffix(host, "."+wildcard) {
+				return true
+			}
+			continue
+		}
+		if repository == source || strings.HasPrefix(repository, source+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isPinned returns true when the image reference 'image' is pinned to a digest
+func isPinned(image string) bool {
+	_, digest, ok := strings.Cut(image, "@")
+	return ok && strings.Contains(digest, ":")
+}
+
+// pinImage returns the image reference 'image' pinned to the digest it was imported as, if known
+func (m *imageDigestMirrors) pinImage(image string) string {
+	if digest, ok := m.digests[image]; ok && !isPinned(image) {
+		return digest
+	}
+	return image
+}
+
+// checkImage returns an error when the image reference 'image' is not pinned to a digest of a mirrored repository
+func (m *imageDigestMirrors) checkImage(image string) error {
+	if !isPinned(image) {
+		return fmt.Errorf("image %q is not pinned to a digest", image)
+	}
+	repository, _, _ := strings.Cut(image, "@")
+	if !m.isMirrored(repository) {
+		return fmt.Errorf("image %q is not mirrored by any ImageDigestMirrorSet or ImageContentSourcePolicy", image)
+	}
+	return nil
+}
+
+// pinManifest returns the manifest with the image references pinned to the digests they were imported as, or an
+// error listing the image references of the manifest that do not resolve to a digest of a mirrored repository
+func (m *imageDigestMirrors) pinManifest(fileName string, manifest interface{}) (interface{}, error) {
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	var obj interface{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+
+	var errs []string
+	pinImages(obj, func(image string) string {
+		pinned := m.pinImage(image)
+		if err := m.checkImage(pinned); err != nil {
+			errs = append(errs, err.Error())
+		}
+		return pinned
+	})
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return nil, fmt.Errorf("%s: %s", fileName, strings.Join(errs, "; "))
+	}
+	return obj, nil
+}
+
+// pinImages replaces the non-empty image references of the decoded JSON object 'obj' with the references 'pin'
+// returns for them
+func pinImages(obj interface{}, pin func(string) string) {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		for key, value := range o {
+			if image, ok := value.(string); ok && imageFields[key] {
+				if image != "" {
+					o[key] = pin(image)
+				}
+				continue
+			}
+			pinImages(value, pin)
+		}
+	case []interface{}:
+		for _, value := range o {
+			pinImages(value, pin)
+		}
+	}
+}
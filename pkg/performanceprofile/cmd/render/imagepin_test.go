@@ -0,0 +1,127 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	apicfgv1 "github.com/openshift/api/config/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	operatorv1alpha1 "github.com/openshift/api/operator/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func newTestImageDigestMirrors() *imageDigestMirrors {
+	idms := &apicfgv1.ImageDigestMirrorSet{
+		Spec: apicfgv1.ImageDigestMirrorSetSpec{
+			ImageDigestMirrors: []apicfgv1.ImageDigestMirrors{
+				{Source: "quay.io/openshift-release-dev", Mirrors: []apicfgv1.ImageMirror{"mirror.local/ocp"}},
+				{Source: "quay.io/unmirrored"},
+				{Source: "*.redhat.io", Mirrors: []apicfgv1.ImageMirror{"mirror.local/redhat"}},
+			},
+		},
+	}
+	icsp := &operatorv1alpha1.ImageContentSourcePolicy{
+		Spec: operatorv1alpha1.ImageContentSourcePolicySpec{
+			RepositoryDigestMirrors: []operatorv1alpha1.RepositoryDigestMirrors{
+				{Source: "registry.example.com/tools/latency", Mirrors: []string{"mirror.local/latency"}},
+			},
+		},
+	}
+	// the ImageStream imported the tag v1 of the latency tools, the tag v2 is not imported yet
+	is := &imagev1.ImageStream{
+		Spec: imagev1.ImageStreamSpec{
+			Tags: []imagev1.TagReference{
+				{Name: "latency-v1", From: &corev1.ObjectReference{Kind: "DockerImage", Name: "registry.example.com/tools/latency:v1"}},
+				{Name: "latency-v2", From: &corev1.ObjectReference{Kind: "DockerImage", Name: "registry.example.com/tools/latency:v2"}},
+			},
+		},
+		Status: imagev1.ImageStreamStatus{
+			Tags: []imagev1.NamedTagEventList{
+				{Tag: "latency-v1", Items: []imagev1.TagEvent{{DockerImageReference: "registry.example.com/tools/latency@" + testDigest}}},
+			},
+		},
+	}
+	return newImageDigestMirrors([]*apicfgv1.ImageDigestMirrorSet{idms}, []*operatorv1alpha1.ImageContentSourcePolicy{icsp}, []*imagev1.ImageStream{is})
+}
+
+func TestCheckImage(t *testing.T) {
+	mirrors := newTestImageDigestMirrors()
+
+	tests := []struct {
+		image       string
+		expectedErr string
+	}{
+		{image: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + testDigest},
+		{image: "registry.redhat.io/rhel9/rhel-guest-image@" + testDigest},
+		{image: "registry.example.com/tools/latency@" + testDigest},
+		{image: "quay.io/openshift-release-dev/ocp-v4.0-art-dev:latest", expectedErr: "not pinned to a digest"},
+		{image: "quay.io/unmirrored/tools@" + testDigest, expectedErr: "not mirrored"},
+		{image: "quay.io/openshift-release-dev-fork/tools@" + testDigest, expectedErr: "not mirrored"},
+		{image: "registry.example.com/tools/latency-other@" + testDigest, expectedErr: "not mirrored"},
+		{image: "redhat.io.example.com/tools@" + testDigest, expectedErr: "not mirrored"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.image, func(t *testing.T) {
+			err := mirrors.checkImage(tc.image)
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Errorf("expected an error containing %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestPinManifest(t *testing.T) {
+	mirrors := newTestImageDigestMirrors()
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{Name: "init", Image: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + testDigest},
+			},
+			Containers: []corev1.Container{
+				{Name: "probe", Image: "registry.example.com/tools/latency:v2"},
+			},
+		},
+	}
+	_, err := mirrors.pinManifest("pod.yaml", pod)
+	if err == nil || !strings.Contains(err.Error(), "registry.example.com/tools/latency:v2") {
+		t.Errorf("expected an error for the image of the probe container, got %v", err)
+	}
+
+	// the imported tag is pinned to its digest
+	pod.Spec.Containers[0].Image = "registry.example.com/tools/latency:v1"
+	pinned, err := mirrors.pinManifest("pod.yaml", pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := json.Marshal(pinned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinnedPod := &corev1.Pod{}
+	if err := json.Unmarshal(b, pinnedPod); err != nil {
+		t.Fatal(err)
+	}
+	if image := pinnedPod.Spec.Containers[0].Image; image != "registry.example.com/tools/latency@"+testDigest {
+		t.Errorf("expected the probe image pinned to its digest, got %q", image)
+	}
+	if image := pinnedPod.Spec.InitContainers[0].Image; image != pod.Spec.InitContainers[0].Image {
+		t.Errorf("expected the init image left alone, got %q", image)
+	}
+
+	// the rendered manifests without image references are left alone
+	if _, err := mirrors.pinManifest("configmap.yaml", &corev1.ConfigMap{Data: map[string]string{"image": ""}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
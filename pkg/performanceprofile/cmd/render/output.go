@@ -0,0 +1,171 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/ociartifact"
+	"github.com/openshift/cluster-node-tuning-operator/version"
+)
+
+const (
+	// outputFormatFlat writes all the rendered manifests to the output directory.
+	outputFormatFlat = "flat"
+	// outputFormatKustomize writes the rendered manifests as a kustomize base and per-pool overlays.
+	outputFormatKustomize = "kustomize"
+
+	kustomizeBaseDir     = "base"
+	kustomizeOverlaysDir = "overlays"
+	kustomizationFile    = "kustomization.yaml"
+)
+
+// kustomization is the subset of the kustomize Kustomization the render command writes
+type kustomization struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Resources  []string `json:"resources"`
+}
+
+// renderOutput writes the rendered manifests to the output directory in the output format
+type renderOutput struct {
+	dir    string
+	format string
+	// resources are the manifest files written by kustomization directory relative to dir
+	resources map[string][]string
+	// profiles are the names of the rendered performance profiles
+	profiles []string
+	// pinImageDigests pins the image references of the manifests to the digests imported by imageMirrors and
+	// requires them to resolve to a digest of a repository mirrored by imageMirrors
+	pinImageDigests bool
+	imageMirrors    *imageDigestMirrors
+}
+
+func newRenderOutput(dir, format string) *renderOutput {
+	return &renderOutput{
+		dir:       dir,
+		format:    format,
+		resources: map[string][]string{},
+	}
+}
+
+func isValidOutputFormat(val string) bool {
+	return val == outputFormatFlat || val == outputFormatKustomize
+}
+
+// addProfile records the performance profile 'name' as rendered
+func (o *renderOutput) addProfile(name string) {
+	o.profiles = append(o.profiles, name)
+}
+
+// pushOutput pushes the manifests of the output directory as an OCI artifact to the reference 'ref'
+// and returns the pushed artifact reference
+func pushOutput(ctx context.Context, o *renderOutput, ref string, opts ociartifact.PushOptions) (string, error) {
+	files, err := ociartifact.ReadDir(o.dir)
+	if err != nil {
+		return "", err
+	}
+
+	artifact, err := ociartifact.New(files, o.profiles, version.Version)
+	if err != nil {
+		return "", err
+	}
+	klog.Infof("Pushing %d files of %s as the OCI artifact %s (digest %s)", len(files), o.dir, ref, artifact.Digest)
+
+	return ociartifact.Push(ctx, ref, artifact, opts)
+}
+
+// writeObject writes the manifest to the file 'fileName'
+func (o *renderOutput) writeObject(pool, fileName string, manifest interface{}) error {
+	if o.pinImageDigests {
+		pinned, err := o.imageMirrors.pinManifest(fileName, manifest)
+		if err != nil {
+			return err
+		}
+		manifest = pinned
+	}
+
+	b, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	return o.writeFile(pool, fileName, b)
+}
+
+// writeFile writes 'data' to the file 'fileName'.  With the kustomize output format, the file is
+// written to the overlay of the MachineConfigPool (or the NodePool) 'pool', or to the base if 'pool'
+// is empty; the flat output format ignores 'pool'.
+func (o *renderOutput) writeFile(pool, fileName string, data []byte) error {
+	dir := o.dir
+	if o.format == outputFormatKustomize {
+		kustomizationDir := kustomizeBaseDir
+		if pool != "" {
+			kustomizationDir = filepath.Join(kustomizeOverlaysDir, pool)
+		}
+		o.resources[kustomizationDir] = append(o.resources[kustomizationDir], fileName)
+		dir = filepath.Join(o.dir, kustomizationDir)
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	fullFilePath := filepath.Join(dir, fileName)
+	klog.Infof("Writing file: %s -> %s", fileName, fullFilePath)
+
+	return os.WriteFile(fullFilePath, data, 0644)
+}
+
+// finish writes the kustomization files of the kustomize output format, the overlays build on the base
+func (o *renderOutput) finish() error {
+	if o.format != outputFormatKustomize {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Join(o.dir, kustomizeBaseDir), os.ModePerm); err != nil {
+		return err
+	}
+	if err := o.writeKustomization(kustomizeBaseDir, o.resources[kustomizeBaseDir]); err != nil {
+		return err
+	}
+
+	for kustomizationDir, resources := range o.resources {
+		if kustomizationDir == kustomizeBaseDir {
+			continue
+		}
+		base, err := filepath.Rel(kustomizationDir, kustomizeBaseDir)
+		if err != nil {
+			return err
+		}
+		if err := o.writeKustomization(kustomizationDir, append([]string{base}, resources...)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *renderOutput) writeKustomization(kustomizationDir string, resources []string) error {
+	k := kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Resources:  append([]string{}, resources...),
+	}
+	// the manifests are written in the map order, sort them for a stable output
+	sort.Strings(k.Resources)
+
+	b, err := yaml.Marshal(k)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the %s kustomization: %w", kustomizationDir, err)
+	}
+
+	fullFilePath := filepath.Join(o.dir, kustomizationDir, kustomizationFile)
+	klog.Infof("Writing file: %s -> %s", kustomizationFile, fullFilePath)
+
+	return os.WriteFile(fullFilePath, b, 0644)
+}
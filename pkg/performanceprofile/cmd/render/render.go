@@ -19,24 +19,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
-	"sigs.k8s.io/yaml"
-
 	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
 	apicfgv1 "github.com/openshift/api/config/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	operatorv1alpha1 "github.com/openshift/api/operator/v1alpha1"
 	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 
 	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	ppvalidation "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2/validation"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	ntoconfig "github.com/openshift/cluster-node-tuning-operator/pkg/config"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/bootstrap"
 	performanceprofilecomponents "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/machineconfig"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/manifestset"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/nodepool"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
 
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -63,24 +69,47 @@ var (
 	codecFactory    serializer.CodecFactory
 	runtimeDecoder  runtime.Decoder
 	defaultMCPNames = []string{"master", "worker"}
+
+	// renderInputKinds lists the kinds the render command reads out of the input directory
+	renderInputKinds = util.ManifestFilterOptions{
+		Include: []schema.GroupVersionKind{
+			{Group: performancev2.GroupVersion.Group, Kind: "PerformanceProfile"},
+			mcfgv1.SchemeGroupVersion.WithKind("MachineConfigPool"),
+			mcfgv1.SchemeGroupVersion.WithKind("MachineConfig"),
+			mcfgv1.SchemeGroupVersion.WithKind("ContainerRuntimeConfig"),
+			apicfgv1.GroupVersion.WithKind("Infrastructure"),
+			apicfgv1.GroupVersion.WithKind("ImageDigestMirrorSet"),
+			operatorv1alpha1.GroupVersion.WithKind("ImageContentSourcePolicy"),
+			imagev1.GroupVersion.WithKind("ImageStream"),
+			corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+		},
+	}
 )
 
 func init() {
 	utilruntime.Must(performancev2.AddToScheme(manifestScheme))
 	utilruntime.Must(apicfgv1.Install(manifestScheme))
+	utilruntime.Must(operatorv1alpha1.Install(manifestScheme))
+	utilruntime.Must(imagev1.Install(manifestScheme))
 	utilruntime.Must(mcfgv1.Install(manifestScheme))
+	utilruntime.Must(corev1.AddToScheme(manifestScheme))
+	utilruntime.Must(tunedv1.AddToScheme(manifestScheme))
 	codecFactory = serializer.NewCodecFactory(manifestScheme)
 	runtimeDecoder = codecFactory.UniversalDecoder(
 		performancev2.GroupVersion,
 		apicfgv1.GroupVersion,
+		operatorv1alpha1.GroupVersion,
+		imagev1.GroupVersion,
 		mcfgv1.GroupVersion,
+		corev1.SchemeGroupVersion,
+		tunedv1.SchemeGroupVersion,
 	)
 }
 
 // Render will traverse the input directory and generate the proper performance profile files
 // in to the output dir based on PerformanceProfile manifests contained in the input directory.
-func render(ownerRefMode, inputDir, outputDir string) error {
-	klog.Infof("Rendering files into: %s (ownerRefMode=%v)", outputDir, ownerRefMode)
+func render(ownerRefMode, inputDir string, output *renderOutput) error {
+	klog.Infof("Rendering files into: %s (ownerRefMode=%v, outputFormat=%v)", output.dir, ownerRefMode, output.format)
 
 	// Read asset directory fileInfo
 	filePaths, err := util.ListFiles(inputDir)
@@ -90,7 +119,7 @@ func render(ownerRefMode, inputDir, outputDir string) error {
 	}
 
 	// Make output dir if not present
-	err = os.MkdirAll(outputDir, os.ModePerm)
+	err = os.MkdirAll(output.dir, os.ModePerm)
 	if err != nil {
 		return err
 	}
@@ -101,6 +130,12 @@ func render(ownerRefMode, inputDir, outputDir string) error {
 		mcConfigs    []*mcfgv1.MachineConfig
 		infra        *apicfgv1.Infrastructure
 		ctrcfgs      []*mcfgv1.ContainerRuntimeConfig
+		idmss        []*apicfgv1.ImageDigestMirrorSet
+		icsps        []*operatorv1alpha1.ImageContentSourcePolicy
+		imageStreams []*imagev1.ImageStream
+		nodePoolCMs  []*corev1.ConfigMap
+		baseProfile  *performancev2.PerformanceProfile
+		ignored      = util.IgnoredManifests{}
 	)
 	// Iterate through the file paths and read in desired files
 	for _, path := range filePaths {
@@ -114,6 +149,11 @@ func render(ownerRefMode, inputDir, outputDir string) error {
 		if err != nil {
 			return fmt.Errorf("error parsing manifests from %s: %w", file.Name(), err)
 		}
+		manifests, fileIgnored := util.FilterManifests(manifests, renderInputKinds)
+		if len(fileIgnored) > 0 {
+			klog.V(4).Infof("skipping %q manifests: %s", file.Name(), fileIgnored)
+		}
+		ignored.Add(fileIgnored)
 
 		// Decode manifest files
 		for idx, m := range manifests {
@@ -139,16 +179,72 @@ func render(ownerRefMode, inputDir, outputDir string) error {
 				}
 			case *mcfgv1.ContainerRuntimeConfig:
 				ctrcfgs = append(ctrcfgs, obj)
+			case *apicfgv1.ImageDigestMirrorSet:
+				idmss = append(idmss, obj)
+			case *operatorv1alpha1.ImageContentSourcePolicy:
+				icsps = append(icsps, obj)
+			case *imagev1.ImageStream:
+				imageStreams = append(imageStreams, obj)
+			case *corev1.ConfigMap:
+				if nodepool.IsBaseProfileConfigMap(obj) {
+					if baseProfile != nil {
+						return fmt.Errorf("found more than one base profile ConfigMap, %q [%d] ConfigMap %s/%s", file.Name(), idx+1, obj.Namespace, obj.Name)
+					}
+					baseProfile, err = nodepool.BaseProfileFromConfigMap(obj)
+					if err != nil {
+						return err
+					}
+					klog.Infof("found the base performance profile %q in the %q [%d] ConfigMap", baseProfile.Name, file.Name(), idx+1)
+					continue
+				}
+				if nodepool.IsConfigMap(obj) {
+					// decoded once the base profile of the hosted cluster is known
+					nodePoolCMs = append(nodePoolCMs, obj)
+					continue
+				}
+				if !bootstrap.IsConfigMap(obj, ntoconfig.WatchNamespace()) {
+					klog.V(4).Infof("skipping %q [%d] ConfigMap %s/%s", file.Name(), idx+1, obj.Namespace, obj.Name)
+					continue
+				}
+				profiles, err := bootstrap.ProfilesFromConfigMap(obj)
+				if err != nil {
+					return err
+				}
+				klog.Infof("found %d performance profiles in the %q [%d] bootstrap ConfigMap", len(profiles), file.Name(), idx+1)
+				perfProfiles = append(perfProfiles, profiles...)
 			default:
 				klog.Infof("skipping %q [%d] manifest because of unhandled %T", file.Name(), idx+1, obji)
 			}
 		}
 	}
 
-	if len(perfProfiles) == 0 {
+	if len(ignored) > 0 {
+		klog.Infof("ignored the unsupported manifests: %s", ignored)
+	}
+
+	nodePools := make([]*nodepool.Config, 0, len(nodePoolCMs))
+	for _, cm := range nodePoolCMs {
+		cfg, err := nodepool.ConfigFromConfigMap(cm, baseProfile)
+		if err != nil {
+			return err
+		}
+		klog.Infof("found %d performance profiles and %d tuneds for the NodePool %q in the ConfigMap %s/%s", len(cfg.Profiles), len(cfg.Tuneds), cfg.NodePoolName, cm.Namespace, cm.Name)
+		nodePools = append(nodePools, cfg)
+	}
+	if baseProfile != nil && len(nodePools) == 0 {
+		klog.Warningf("the base performance profile %q is not overridden by any NodePool", baseProfile.Name)
+	}
+
+	if len(perfProfiles) == 0 && len(nodePools) == 0 {
 		klog.Warning("zero performance profiles were found")
 	}
 
+	if output.pinImageDigests {
+		output.imageMirrors = newImageDigestMirrors(idmss, icsps, imageStreams)
+		klog.Infof("pinning the image references to the %d digests imported by the input, requiring digests of the %d repositories mirrored by the input",
+			len(output.imageMirrors.digests), len(output.imageMirrors.sources))
+	}
+
 	var partitioningMode *apicfgv1.CPUPartitioningMode
 	if infra != nil {
 		partitioningMode = &infra.Status.CPUPartitioning
@@ -159,13 +255,13 @@ func render(ownerRefMode, inputDir, outputDir string) error {
 		partitioningMode = &legacyAllNodes
 	}
 
-	if err := genBootstrapWorkloadPinningManifests(partitioningMode, outputDir, defaultMCPNames...); err != nil {
+	if err := genBootstrapWorkloadPinningManifests(partitioningMode, output, defaultMCPNames...); err != nil {
 		return err
 	}
 
 	// If the user supplies extra machine pools, we ingest them here
 	for _, pool := range mcPools {
-		if err := genBootstrapWorkloadPinningManifests(partitioningMode, outputDir, pool.Name); err != nil {
+		if err := genBootstrapWorkloadPinningManifests(partitioningMode, output, pool.Name); err != nil {
 			return err
 		}
 	}
@@ -184,6 +280,12 @@ func render(ownerRefMode, inputDir, outputDir string) error {
 			continue
 		}
 
+		if partitioningMode != nil {
+			if errs := ppvalidation.ValidateCPUPartitioning(pp, *partitioningMode); len(errs) > 0 {
+				return fmt.Errorf("render: PerformanceProfile %s contradicts the cluster CPU partitioning: %w", pp.Name, errs.ToAggregate())
+			}
+		}
+
 		defaultRuntime, err := getContainerRuntimeName(pp, mcp, ctrcfgs)
 		if err != nil {
 			return fmt.Errorf("render: could not determine high-performance runtime class container-runtime for profile %q; %w", pp.Name, err)
@@ -212,27 +314,27 @@ func render(ownerRefMode, inputDir, outputDir string) error {
 			}
 		}
 
+		output.addProfile(pp.Name)
 		for kind, manifest := range components.ToManifestTable() {
-			err = writeObject(outputDir, fmt.Sprintf("%s_%s.yaml", pp.Name, strings.ToLower(kind)), manifest)
+			// the MachineConfigPool rolls out the MachineConfig and the KubeletConfig
+			pool := ""
+			if kind == components.MachineConfig.Kind || kind == components.KubeletConfig.Kind {
+				pool = mcp.Name
+			}
+			err = output.writeObject(pool, fmt.Sprintf("%s_%s.yaml", pp.Name, strings.ToLower(kind)), manifest)
 			if err != nil {
 				return err
 			}
 		}
 	}
 
-	return nil
-}
-
-func writeObject(outputDir, fileName string, manifest interface{}) error {
-	fullFilePath := filepath.Join(outputDir, fileName)
-	klog.Infof("Writing file: %s -> %s", fileName, fullFilePath)
-
-	b, err := yaml.Marshal(manifest)
-	if err != nil {
-		return err
+	for _, cfg := range nodePools {
+		if err := renderNodePoolConfig(ownerRefMode, output, cfg); err != nil {
+			return err
+		}
 	}
 
-	return os.WriteFile(fullFilePath, b, 0644)
+	return output.finish()
 }
 
 func addOwnerReference(components *manifestset.ManifestResultSet, pp *performancev2.PerformanceProfile) error {
@@ -328,7 +430,7 @@ func isLegacySNOWorkloadPinningMethod(mcs []*mcfgv1.MachineConfig, infra *apicfg
 // priority and override the values in this file. This file is intended to always be present in a CPU Partitioned cluster.
 // Since we currently do not support a user reverting a CPU partitioned cluster to a regular cluster, in the event that a
 // PerformanceProfile does not exist, these manifests will be the fallback.
-func genBootstrapWorkloadPinningManifests(partitioningMode *apicfgv1.CPUPartitioningMode, outputDir string, mcpNames ...string) error {
+func genBootstrapWorkloadPinningManifests(partitioningMode *apicfgv1.CPUPartitioningMode, output *renderOutput, mcpNames ...string) error {
 	if partitioningMode == nil || *partitioningMode != apicfgv1.CPUPartitioningAllNodes {
 		return nil
 	}
@@ -339,13 +441,8 @@ func genBootstrapWorkloadPinningManifests(partitioningMode *apicfgv1.CPUPartitio
 			return err
 		}
 
-		b, err := yaml.Marshal(mc)
-		if err != nil {
-			return err
-		}
-
 		fileName := fmt.Sprintf("01_%s_workload_pinning_%s.yaml", mc.Name, strings.ToLower(mc.Kind))
-		err = os.WriteFile(filepath.Join(outputDir, fileName), b, 0644)
+		err = output.writeObject(name, fileName, mc)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	assets "github.com/openshift/cluster-node-tuning-operator/assets/performanceprofile"
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+
+	"k8s.io/klog"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// assetsOverrideSource loads the component templates of the assets override directory and returns
+// the source of the events reconciling all the performance profiles once the checksum of the
+// directory files changes, or nil when no override directory is configured.
+func (r *PerformanceProfileReconciler) assetsOverrideSource(mgr ctrl.Manager) (source.Source, error) {
+	if r.AssetsOverrideDir == "" {
+		return nil, nil
+	}
+
+	// an unreadable directory must not block the operator, the embedded templates are used until it is fixed
+	if err := assets.SetOverrideDir(r.AssetsOverrideDir); err != nil {
+		klog.Errorf("failed to load the assets override directory %q: %v", r.AssetsOverrideDir, err)
+	} else if checksum := assets.Checksum(); checksum != "" {
+		klog.Infof("Loaded the assets override directory %q, checksum %s", r.AssetsOverrideDir, checksum)
+	}
+
+	events := make(chan event.GenericEvent)
+	err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		ticker := time.NewTicker(r.AssetsRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				r.refreshAssets(ctx, events)
+			}
+		}
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &source.Channel{Source: events}, nil
+}
+
+// refreshAssets reloads the assets override directory and sends the reconcile events of all
+// the performance profiles to 'events' when its files changed.
+func (r *PerformanceProfileReconciler) refreshAssets(ctx context.Context, events chan<- event.GenericEvent) {
+	changed, err := assets.Refresh()
+	if err != nil {
+		klog.Errorf("failed to reload the assets override directory %q: %v", r.AssetsOverrideDir, err)
+		return
+	}
+	if !changed {
+		return
+	}
+	klog.Infof("The assets override directory %q changed, checksum %q, reconciling all the performance profiles", r.AssetsOverrideDir, assets.Checksum())
+
+	profiles := &performancev2.PerformanceProfileList{}
+	if err := r.List(ctx, profiles); err != nil {
+		klog.Errorf("failed to get performance profiles: %v", err)
+		return
+	}
+	for i := range profiles.Items {
+		select {
+		case <-ctx.Done():
+			return
+		case events <- event.GenericEvent{Object: &profiles.Items[i]}:
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	profileutil "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/profile"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// canaryNodeAnnotation records on the machine config pool the canary node of the profile rollout
+	canaryNodeAnnotation = "performance.openshift.io/canary-node"
+	// canarySourceConfigAnnotation records on the machine config pool the rendered configuration the pool targeted
+	// when the canary rollout started, the canary node waits for the pool to render a newer one
+	canarySourceConfigAnnotation = "performance.openshift.io/canary-source-config"
+	// canaryConfigAnnotation records on the machine config pool the rendered configuration the canary node runs
+	canaryConfigAnnotation = "performance.openshift.io/canary-config"
+	// canaryStartTimeAnnotation records on the machine config pool the time the canary node got the configuration
+	canaryStartTimeAnnotation = "performance.openshift.io/canary-start-time"
+	// canaryOriginalPausedAnnotation records on the machine config pool whether the pool was paused before the canary rollout
+	canaryOriginalPausedAnnotation = "performance.openshift.io/canary-original-paused"
+	// canaryFailureAnnotation records on the machine config pool why the canary node failed the canary configuration
+	canaryFailureAnnotation = "performance.openshift.io/canary-failure"
+
+	// the machine config daemon annotation holding the reason of the degraded state
+	mcdReasonAnnotation = "machineconfiguration.openshift.io/reason"
+	mcdStateDegraded    = "Degraded"
+
+	conditionTypeCanaryRollout      conditionsv1.ConditionType = "CanaryRollout"
+	conditionReasonCanaryInProgress                            = "CanaryInProgress"
+	conditionReasonCanaryFailed                                = "CanaryFailed"
+
+	canaryRequeueInterval = 30 * time.Second
+)
+
+// startCanaryRollout pauses the profile pool before the profile components change, so the pool rolls the new
+// configuration out to the canary node only. It is a no-op unless the profile opts in the canary rollout.
+func (r *PerformanceProfileReconciler) startCanaryRollout(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool) error {
+	canary, err := profileutil.GetCanaryRollout(profile)
+	if err != nil || canary == nil {
+		return err
+	}
+
+	// a new change during the canary rollout is rolled out to the same canary node
+	if _, ok := profileMCP.Annotations[canaryNodeAnnotation]; ok {
+		return nil
+	}
+
+	nodeName, err := r.getCanaryNode(ctx, profile, canary)
+	if err != nil {
+		return err
+	}
+
+	mcpCopy := profileMCP.DeepCopy()
+	if mcpCopy.Annotations == nil {
+		mcpCopy.Annotations = map[string]string{}
+	}
+	mcpCopy.Annotations[canaryNodeAnnotation] = nodeName
+	mcpCopy.Annotations[canarySourceConfigAnnotation] = profileMCP.Spec.Configuration.Name
+	mcpCopy.Annotations[canaryStartTimeAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	mcpCopy.Annotations[canaryOriginalPausedAnnotation] = strconv.FormatBool(profileMCP.Spec.Paused)
+	mcpCopy.Spec.Paused = true
+
+	klog.Infof("Pause the machine config pool %q to roll out performance profile %q on the canary node %q", profileMCP.Name, profile.Name, nodeName)
+	return r.patchMachineConfigPool(ctx, profileMCP, mcpCopy)
+}
+
+// getCanaryNode returns the canary node requested by the profile, or the first node of the profile by name
+func (r *PerformanceProfileReconciler) getCanaryNode(ctx context.Context, profile *performancev2.PerformanceProfile, canary *performancev2.CanaryRollout) (string, error) {
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, &client.ListOptions{LabelSelector: labels.SelectorFromSet(profile.Spec.NodeSelector)}); err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(nodes.Items))
+	for i := range nodes.Items {
+		if canary.Node == "" || nodes.Items[i].Name == canary.Node {
+			names = append(names, nodes.Items[i].Name)
+		}
+	}
+	if len(names) == 0 {
+		if canary.Node != "" {
+			return "", fmt.Errorf("the canary node %q is not a node of the profile", canary.Node)
+		}
+		return "", fmt.Errorf("the profile has no node to run the canary rollout on")
+	}
+
+	sort.Strings(names)
+	return names[0], nil
+}
+
+// reconcileCanaryRollout rolls the configuration the pool rendered out to the canary node and unpauses the pool once
+// the canary node is tuned. It returns the condition reporting the canary rollout in progress or failed, and nil once
+// the pool rolls the configuration out to all its nodes.
+func (r *PerformanceProfileReconciler) reconcileCanaryRollout(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool) (*conditionsv1.Condition, error) {
+	nodeName, ok := profileMCP.Annotations[canaryNodeAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	canary, err := profileutil.GetCanaryRollout(profile)
+	if err != nil {
+		return nil, err
+	}
+	if canary == nil {
+		// the profile opted out, let the pool roll the configuration out
+		return nil, r.finishCanaryRollout(ctx, profile, profileMCP)
+	}
+
+	target := profileMCP.Spec.Configuration.Name
+	if failure, ok := profileMCP.Annotations[canaryFailureAnnotation]; ok && profileMCP.Annotations[canaryConfigAnnotation] == target {
+		condition := getCanaryCondition(corev1.ConditionFalse, conditionReasonCanaryFailed, failure)
+		return &condition, nil
+	}
+
+	startTime, err := time.Parse(time.RFC3339, profileMCP.Annotations[canaryStartTimeAnnotation])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %q annotation of the machine config pool %q: %w", canaryStartTimeAnnotation, profileMCP.Name, err)
+	}
+	elapsed := time.Since(startTime)
+
+	if target == profileMCP.Annotations[canarySourceConfigAnnotation] {
+		if elapsed > canary.Timeout.Duration {
+			return r.failCanaryRollout(ctx, profile, profileMCP, fmt.Sprintf("the machine config pool %q did not render a new configuration within %v", profileMCP.Name, canary.Timeout.Duration))
+		}
+		condition := getCanaryCondition(corev1.ConditionTrue, conditionReasonCanaryInProgress,
+			fmt.Sprintf("Waiting for the machine config pool %q to render the new configuration", profileMCP.Name))
+		return &condition, nil
+	}
+
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+		if errors.IsNotFound(err) {
+			return r.failCanaryRollout(ctx, profile, profileMCP, fmt.Sprintf("the canary node %q was deleted", nodeName))
+		}
+		return nil, err
+	}
+
+	if profileMCP.Annotations[canaryConfigAnnotation] != target {
+		return r.updateCanaryNode(ctx, profile, profileMCP, node, target)
+	}
+
+	failure, passed, err := r.getCanaryNodeResult(ctx, node, canary, target)
+	if err != nil {
+		return nil, err
+	}
+	if failure != "" {
+		return r.failCanaryRollout(ctx, profile, profileMCP, failure)
+	}
+	if passed {
+		r.Recorder.Eventf(profile, corev1.EventTypeNormal, "Canary succeeded", "The canary node %q is tuned with the configuration %q", nodeName, target)
+		return nil, r.finishCanaryRollout(ctx, profile, profileMCP)
+	}
+
+	if elapsed > canary.Timeout.Duration {
+		return r.failCanaryRollout(ctx, profile, profileMCP, fmt.Sprintf("the canary node %q did not get tuned with the configuration %q within %v", nodeName, target, canary.Timeout.Duration))
+	}
+	condition := getCanaryCondition(corev1.ConditionTrue, conditionReasonCanaryInProgress,
+		fmt.Sprintf("Rolling out the configuration %q to the canary node %q", target, nodeName))
+	return &condition, nil
+}
+
+// updateCanaryNode makes the machine config daemon of the canary node update the node to the configuration 'target'
+// of the paused pool, and restarts the canary timeout. When the canary node can not be updated, the pool is unpaused
+// so it does not stay paused with no node rolling the configuration out.
+func (r *PerformanceProfileReconciler) updateCanaryNode(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool, node *corev1.Node, target string) (*conditionsv1.Condition, error) {
+	if node.Annotations[mcdDesiredConfigAnnotation] != target {
+		nodeCopy := node.DeepCopy()
+		if nodeCopy.Annotations == nil {
+			nodeCopy.Annotations = map[string]string{}
+		}
+		nodeCopy.Annotations[mcdDesiredConfigAnnotation] = target
+		klog.Infof("Update the canary node %q to the configuration %q", node.Name, target)
+		if err := r.Patch(ctx, nodeCopy, client.MergeFrom(node)); err != nil {
+			r.Recorder.Eventf(profile, corev1.EventTypeWarning, "Canary failed", "Failed to update the canary node %q, unpaused the machine config pool %q: %v", node.Name, profileMCP.Name, err)
+			if finishErr := r.finishCanaryRollout(ctx, profile, profileMCP); finishErr != nil {
+				return nil, finishErr
+			}
+			return nil, fmt.Errorf("failed to update the canary node %q to the configuration %q: %w", node.Name, target, err)
+		}
+	}
+
+	mcpCopy := profileMCP.DeepCopy()
+	mcpCopy.Annotations[canaryConfigAnnotation] = target
+	mcpCopy.Annotations[canaryStartTimeAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	delete(mcpCopy.Annotations, canaryFailureAnnotation)
+	if err := r.patchMachineConfigPool(ctx, profileMCP, mcpCopy); err != nil {
+		return nil, err
+	}
+
+	condition := getCanaryCondition(corev1.ConditionTrue, conditionReasonCanaryInProgress,
+		fmt.Sprintf("Rolling out the configuration %q to the canary node %q", target, node.Name))
+	return &condition, nil
+}
+
+// getCanaryNodeResult returns why the canary node failed the configuration 'target', or whether it passed
+func (r *PerformanceProfileReconciler) getCanaryNodeResult(ctx context.Context, node *corev1.Node, canary *performancev2.CanaryRollout, target string) (string, bool, error) {
+	if node.Annotations[mcdStateAnnotation] == mcdStateDegraded {
+		return fmt.Sprintf("the canary node %q failed to apply the configuration %q: %s", node.Name, target, node.Annotations[mcdReasonAnnotation]), false, nil
+	}
+
+	if node.Annotations[mcdCurrentConfigAnnotation] != target ||
+		node.Annotations[mcdDesiredConfigAnnotation] != target ||
+		node.Annotations[mcdStateAnnotation] != mcdStateDone {
+		return "", false, nil
+	}
+
+	tunedProfile := &tunedv1.Profile{}
+	key := types.NamespacedName{Namespace: components.NamespaceNodeTuningOperator, Name: node.Name}
+	if err := r.Get(ctx, key, tunedProfile); err != nil {
+		if errors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	if condition := getTunedDegradedCondition(tunedProfile); condition != nil {
+		return fmt.Sprintf("the tuned profile of the canary node %q is degraded: %s", node.Name, condition.Message), false, nil
+	}
+	if !isTunedProfileApplied(tunedProfile) {
+		return "", false, nil
+	}
+
+	if canary.MaxLatency == nil {
+		return "", true, nil
+	}
+
+	probe := tunedProfile.Status.LatencyProbe
+	if probe == nil || probe.MachineConfig != target {
+		return "", false, nil
+	}
+	if probe.MaxLatency == nil {
+		return fmt.Sprintf("the latency probe of the canary node %q failed: %s", node.Name, probe.Message), false, nil
+	}
+	if *probe.MaxLatency > *canary.MaxLatency {
+		return fmt.Sprintf("the latency probe of the canary node %q measured %dus, more than the maximum %dus", node.Name, *probe.MaxLatency, *canary.MaxLatency), false, nil
+	}
+	return "", true, nil
+}
+
+// failCanaryRollout keeps the pool paused and records the canary failure until the pool renders a new configuration
+func (r *PerformanceProfileReconciler) failCanaryRollout(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool, failure string) (*conditionsv1.Condition, error) {
+	mcpCopy := profileMCP.DeepCopy()
+	mcpCopy.Annotations[canaryConfigAnnotation] = profileMCP.Spec.Configuration.Name
+	mcpCopy.Annotations[canaryFailureAnnotation] = failure
+	if err := r.patchMachineConfigPool(ctx, profileMCP, mcpCopy); err != nil {
+		return nil, err
+	}
+
+	klog.Infof("Canary rollout of performance profile %q failed, keep the machine config pool %q paused: %s", profile.Name, profileMCP.Name, failure)
+	r.Recorder.Eventf(profile, corev1.EventTypeWarning, "Canary failed", "Kept the machine config pool %q paused: %s", profileMCP.Name, failure)
+
+	condition := getCanaryCondition(corev1.ConditionFalse, conditionReasonCanaryFailed, failure)
+	return &condition, nil
+}
+
+// finishCanaryRollout restores the paused state of the pool, so the pool rolls the configuration out to all its nodes
+func (r *PerformanceProfileReconciler) finishCanaryRollout(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool) error {
+	mcpCopy := profileMCP.DeepCopy()
+	mcpCopy.Spec.Paused = profileMCP.Annotations[canaryOriginalPausedAnnotation] == "true"
+	for _, annotation := range []string{
+		canaryNodeAnnotation,
+		canarySourceConfigAnnotation,
+		canaryConfigAnnotation,
+		canaryStartTimeAnnotation,
+		canaryOriginalPausedAnnotation,
+		canaryFailureAnnotation,
+	} {
+		delete(mcpCopy.Annotations, annotation)
+	}
+
+	klog.Infof("Finish the canary rollout of performance profile %q on the machine config pool %q", profile.Name, profileMCP.Name)
+	return r.patchMachineConfigPool(ctx, profileMCP, mcpCopy)
+}
+
+func getCanaryCondition(status corev1.ConditionStatus, reason, message string) conditionsv1.Condition {
+	now := metav1.Now()
+	return conditionsv1.Condition{
+		Type:               conditionTypeCanaryRollout,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: now,
+		LastHeartbeatTime:  now,
+	}
+}
+
+// restoreCanaryRollout restores the paused state of the pool of the deleted profile
+func (r *PerformanceProfileReconciler) restoreCanaryRollout(ctx context.Context, profile *performancev2.PerformanceProfile) error {
+	profileMCP, err := r.getMachineConfigPoolByProfile(ctx, profile)
+	if err != nil {
+		// the pool was deleted or no longer selects the profile, there is nothing to restore
+		klog.Warningf("failed to get the machine config pool of performance profile %q: %v", profile.Name, err)
+		return nil
+	}
+
+	if _, ok := profileMCP.Annotations[canaryNodeAnnotation]; !ok {
+		return nil
+	}
+	return r.finishCanaryRollout(ctx, profile, profileMCP)
+}
+
+// patchMachineConfigPool patches the pool instead of updating it, so the writes do not conflict with the
+// status updates of the machine config operator
+func (r *PerformanceProfileReconciler) patchMachineConfigPool(ctx context.Context, mcp, mcpCopy *mcov1.MachineConfigPool) error {
+	if err := r.Patch(ctx, mcpCopy, client.MergeFrom(mcp)); err != nil {
+		return err
+	}
+	// the following steps of the reconcile see the patched pool
+	mcpCopy.DeepCopyInto(mcp)
+	return nil
+}
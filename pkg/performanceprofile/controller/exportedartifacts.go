@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/machineconfig"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// exportedArtifactsPrefix names the config map the profile components are exported to
+	exportedArtifactsPrefix = "exported-artifacts"
+	// exportedArtifactsProfileGenerationAnnotation holds the profile generation the exported components were rendered for
+	exportedArtifactsProfileGenerationAnnotation = "performance.openshift.io/exported-profile-generation"
+)
+
+// reconcileExportedArtifacts exports the MachineConfig, the KubeletConfig and the Tuned of the profile, as they
+// exist in the cluster, to a config map in the operator namespace, so the GitOps tools and the auditors read the
+// rendered components with a role on the config map only. The config map is deleted once the profile disables
+// the export.
+func (r *PerformanceProfileReconciler) reconcileExportedArtifacts(ctx context.Context, profile *performancev2.PerformanceProfile) error {
+	if !isExportArtifactsEnabled(profile) {
+		return r.deleteExportedArtifacts(ctx, profile)
+	}
+
+	var artifacts []client.Object
+	mc, err := r.getMachineConfig(ctx, machineconfig.GetMachineConfigName(profile))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		artifacts = append(artifacts, &mcov1.MachineConfig{
+			TypeMeta:   metav1.TypeMeta{APIVersion: mcov1.GroupVersion.String(), Kind: "MachineConfig"},
+			ObjectMeta: getRollbackObjectMeta(&mc.ObjectMeta),
+			Spec:       mc.Spec,
+		})
+	}
+
+	kc, err := r.getKubeletConfig(components.GetComponentName(profile.Name, components.ComponentNamePrefix))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		artifacts = append(artifacts, &mcov1.KubeletConfig{
+			TypeMeta:   metav1.TypeMeta{APIVersion: mcov1.GroupVersion.String(), Kind: "KubeletConfig"},
+			ObjectMeta: getRollbackObjectMeta(&kc.ObjectMeta),
+			Spec:       kc.Spec,
+		})
+	}
+
+	tuned, err := r.getTuned(components.GetComponentName(profile.Name, components.ProfileNamePerformance), components.NamespaceNodeTuningOperator)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		artifacts = append(artifacts, &tunedv1.Tuned{
+			TypeMeta:   metav1.TypeMeta{APIVersion: tunedv1.SchemeGroupVersion.String(), Kind: "Tuned"},
+			ObjectMeta: getRollbackObjectMeta(&tuned.ObjectMeta),
+			Spec:       tuned.Spec,
+		})
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getExportedArtifactsName(profile),
+			Namespace: components.NamespaceNodeTuningOperator,
+			Annotations: map[string]string{
+				exportedArtifactsProfileGenerationAnnotation: strconv.FormatInt(profile.Generation, 10),
+			},
+		},
+		Data: map[string]string{},
+	}
+	if err := controllerutil.SetControllerReference(profile, cm, r.Scheme); err != nil {
+		return err
+	}
+
+	for _, artifact := range artifacts {
+		data, err := yaml.Marshal(artifact)
+		if err != nil {
+			return err
+		}
+		cm.Data[artifact.GetObjectKind().GroupVersionKind().Kind+"_"+artifact.GetName()+".yaml"] = string(data)
+	}
+
+	existing := &corev1.ConfigMap{}
+	err = r.Get(ctx, namespacedName(cm), existing)
+	if errors.IsNotFound(err) {
+		klog.Infof("Create the exported artifacts %q of the performance profile %q", cm.Name, profile.Name)
+		return r.Create(ctx, cm)
+	}
+	if err != nil {
+		return err
+	}
+
+	if apiequality.Semantic.DeepEqual(existing.Data, cm.Data) &&
+		reflect.DeepEqual(existing.Annotations, cm.Annotations) &&
+		reflect.DeepEqual(existing.OwnerReferences, cm.OwnerReferences) {
+		return nil
+	}
+
+	klog.Infof("Update the exported artifacts %q of the performance profile %q", cm.Name, profile.Name)
+	existing.Annotations = cm.Annotations
+	existing.OwnerReferences = cm.OwnerReferences
+	existing.Data = cm.Data
+	return r.Update(ctx, existing)
+}
+
+func (r *PerformanceProfileReconciler) deleteExportedArtifacts(ctx context.Context, profile *performancev2.PerformanceProfile) error {
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{
+		Name:      getExportedArtifactsName(profile),
+		Namespace: components.NamespaceNodeTuningOperator,
+	}
+	if err := r.Get(ctx, key, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	klog.Infof("Delete the exported artifacts %q of the performance profile %q", cm.Name, profile.Name)
+	if err := r.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// getExportedArtifactsStatus returns the reference of the config map the profile components are exported to,
+// or nil when the profile does not export them
+func getExportedArtifactsStatus(profile *performancev2.PerformanceProfile) *string {
+	if !isExportArtifactsEnabled(profile) {
+		return nil
+	}
+
+	key := types.NamespacedName{
+		Name:      getExportedArtifactsName(profile),
+		Namespace: components.NamespaceNodeTuningOperator,
+	}
+	reference := key.String()
+	return &reference
+}
+
+func getExportedArtifactsName(profile *performancev2.PerformanceProfile) string {
+	return components.GetComponentName(profile.Name, exportedArtifactsPrefix)
+}
+
+func isExportArtifactsEnabled(profile *performancev2.PerformanceProfile) bool {
+	return profile.Annotations[performancev2.PerformanceProfileExportArtifactsAnnotation] == "true"
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the top-level fields of the generated objects the generators do not own
+var generatedObjectMetaFields = map[string]bool{
+	"apiVersion": true,
+	"kind":       true,
+	"metadata":   true,
+	"status":     true,
+}
+
+func getObjectContent(obj client.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return runtime.DeepCopyJSON(u.UnstructuredContent()), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+func setObjectContent(obj client.Object, content map[string]interface{}) error {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		u.SetUnstructuredContent(content)
+		return nil
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(content, obj)
+}
+
+// getMutatedGeneratedObject returns the object of a registered generator updated with the content the generator
+// owns, that is the top-level fields it sets besides the metadata and the status, or nil when the object is up to date
+func (r *PerformanceProfileReconciler) getMutatedGeneratedObject(ctx context.Context, obj client.Object) (client.Object, error) {
+	// get into a zero object, decoding into the generated one would keep the fields the existing one lacks
+	existing := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+	existing.GetObjectKind().SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		if errors.IsNotFound(err) {
+			return obj, nil
+		}
+		return nil, err
+	}
+
+	desiredContent, err := getObjectContent(obj)
+	if err != nil {
+		return nil, err
+	}
+	existingContent, err := getObjectContent(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	mutatedContent := runtime.DeepCopyJSON(existingContent)
+	for field, value := range desiredContent {
+		if !generatedObjectMetaFields[field] {
+			mutatedContent[field] = value
+		}
+	}
+
+	mutated := existing.DeepCopyObject().(client.Object)
+	if err := setObjectContent(mutated, mutatedContent); err != nil {
+		return nil, err
+	}
+	mutated.GetObjectKind().SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	mutated.SetAnnotations(mergeMaps(obj.GetAnnotations(), mutated.GetAnnotations()))
+	mutated.SetLabels(mergeMaps(obj.GetLabels(), mutated.GetLabels()))
+	mutated.SetOwnerReferences(obj.GetOwnerReferences())
+
+	// we do not need to update if it no change between mutated and existing object
+	if apiequality.Semantic.DeepEqual(existingContent, mutatedContent) &&
+		apiequality.Semantic.DeepEqual(existing.GetLabels(), mutated.GetLabels()) &&
+		apiequality.Semantic.DeepEqual(existing.GetAnnotations(), mutated.GetAnnotations()) &&
+		apiequality.Semantic.DeepEqual(existing.GetOwnerReferences(), mutated.GetOwnerReferences()) {
+		return nil, nil
+	}
+
+	return mutated, nil
+}
+
+// getMutatedGeneratedObjects returns the objects of the registered generators that differ from the cluster state
+func (r *PerformanceProfileReconciler) getMutatedGeneratedObjects(ctx context.Context, objs []client.Object) ([]client.Object, error) {
+	var mutated []client.Object
+	for _, obj := range objs {
+		m, err := r.getMutatedGeneratedObject(ctx, obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the generated %s %q: %w", getGeneratedObjectKind(obj), obj.GetName(), err)
+		}
+		if m != nil {
+			mutated = append(mutated, m)
+		}
+	}
+	return mutated, nil
+}
+
+func (r *PerformanceProfileReconciler) createOrUpdateGeneratedObject(ctx context.Context, obj client.Object) error {
+	kind := getGeneratedObjectKind(obj)
+	if obj.GetResourceVersion() == "" {
+		klog.Infof("Create %s %q", kind, obj.GetName())
+		return r.Create(ctx, obj)
+	}
+
+	klog.Infof("Update %s %q", kind, obj.GetName())
+	return r.Update(ctx, obj)
+}
+
+func getGeneratedObjectKind(obj client.Object) string {
+	return obj.GetObjectKind().GroupVersionKind().Kind
+}
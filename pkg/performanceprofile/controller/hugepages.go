@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	profileutil "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/profile"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	conditionTypeHugepagesPending conditionsv1.ConditionType = "HugepagesPending"
+
+	conditionReasonHugepagesNotAllocated = "HugepagesNotAllocated"
+)
+
+// getHugepagesPendingCondition returns the HugepagesPending condition when the tuned operand
+// of some nodes could not allocate the huge pages requested at runtime, nil otherwise.
+func (r *PerformanceProfileReconciler) getHugepagesPendingCondition(ctx context.Context, profile *performancev2.PerformanceProfile) (*conditionsv1.Condition, error) {
+	if !hasRuntimeHugepages(profile) {
+		return nil, nil
+	}
+
+	tunedProfiles := &tunedv1.ProfileList{}
+	if err := r.List(ctx, tunedProfiles); err != nil {
+		return nil, err
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, &client.ListOptions{LabelSelector: labels.SelectorFromSet(profile.Spec.NodeSelector)}); err != nil {
+		return nil, err
+	}
+
+	filtered := removeUnMatchedTunedProfiles(nodes.Items, tunedProfiles.Items)
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].Name < filtered[j].Name
+	})
+
+	var messages []string
+	for i := range filtered {
+		for _, condition := range filtered[i].Status.Conditions {
+			if condition.Type == tunedv1.TunedHugepagesAllocated && condition.Status == corev1.ConditionFalse {
+				messages = append(messages, fmt.Sprintf("%s: %s", filtered[i].Name, condition.Message))
+			}
+		}
+	}
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	now := metav1.Now()
+	return &conditionsv1.Condition{
+		Type:               conditionTypeHugepagesPending,
+		Status:             corev1.ConditionTrue,
+		Reason:             conditionReasonHugepagesNotAllocated,
+		Message:            fmt.Sprintf("%d nodes did not allocate the huge pages requested at runtime: %s", len(messages), strings.Join(messages, " ")),
+		LastTransitionTime: now,
+		LastHeartbeatTime:  now,
+	}, nil
+}
+
+func hasRuntimeHugepages(profile *performancev2.PerformanceProfile) bool {
+	if profile.Spec.HugePages == nil {
+		return false
+	}
+
+	for i := range profile.Spec.HugePages.Pages {
+		if profileutil.IsRuntimeAllocated(&profile.Spec.HugePages.Pages[i]) {
+			return true
+		}
+	}
+	return false
+}
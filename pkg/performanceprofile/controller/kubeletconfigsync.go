@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// the reasons a node does not run the kubelet configuration of the generated kubelet config
+	kubeletConfigSyncReasonMachineConfigPending = "MachineConfigPending"
+	kubeletConfigSyncReasonChecksumsMissing     = "ChecksumsMissing"
+	kubeletConfigSyncReasonChecksumMismatch     = "ChecksumMismatch"
+)
+
+// getKubeletConfigSyncStatus returns the nodes of the profile running the kubelet configuration of the generated
+// kubelet config. The MCO may batch the kubelet config with other changes, so a node is synced once the machine
+// config daemon finished updating it to the pool target configuration, which restarts the kubelet, and the
+// kubelet configuration checksums the tuned daemon reports on the node match the generated kubelet config.
+func (r *PerformanceProfileReconciler) getKubeletConfigSyncStatus(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool) (*performancev2.KubeletConfigSyncStatus, error) {
+	kc, err := r.getKubeletConfig(components.GetComponentName(profile.Name, components.ComponentNamePrefix))
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	expected, err := util.KubeletConfigChecksums(kc.Spec.KubeletConfig.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, &client.ListOptions{LabelSelector: labels.SelectorFromSet(profile.Spec.NodeSelector)}); err != nil {
+		return nil, err
+	}
+
+	syncStatus := &performancev2.KubeletConfigSyncStatus{
+		KubeletConfig: kc.Name,
+		TotalNodes:    int32(len(nodes.Items)),
+	}
+	for i := range nodes.Items {
+		nodeSync := getNodeKubeletConfigSync(&nodes.Items[i], profileMCP, expected)
+		if nodeSync.Synced {
+			syncStatus.SyncedNodes++
+		}
+		syncStatus.Nodes = append(syncStatus.Nodes, nodeSync)
+	}
+
+	// keep the node order stable to avoid status updates
+	sort.Slice(syncStatus.Nodes, func(i, j int) bool {
+		return syncStatus.Nodes[i].Name < syncStatus.Nodes[j].Name
+	})
+
+	return syncStatus, nil
+}
+
+func getNodeKubeletConfigSync(node *corev1.Node, profileMCP *mcov1.MachineConfigPool, expected map[string]string) performancev2.NodeKubeletConfigSync {
+	nodeSync := performancev2.NodeKubeletConfigSync{Name: node.Name}
+
+	if _, tuned := isNodeTuned(node, profileMCP); !tuned {
+		nodeSync.Reason = kubeletConfigSyncReasonMachineConfigPending
+		return nodeSync
+	}
+
+	value, ok := node.Annotations[tunedv1.TunedKubeletConfigChecksumsAnnotationKey]
+	if !ok {
+		nodeSync.Reason = kubeletConfigSyncReasonChecksumsMissing
+		return nodeSync
+	}
+
+	actual := map[string]string{}
+	if err := json.Unmarshal([]byte(value), &actual); err != nil {
+		nodeSync.Reason = kubeletConfigSyncReasonChecksumsMissing
+		return nodeSync
+	}
+
+	if key := util.KubeletConfigChecksumsMismatch(expected, actual); key != "" {
+		nodeSync.Reason = fmt.Sprintf("%s: %s", kubeletConfigSyncReasonChecksumMismatch, key)
+		return nodeSync
+	}
+
+	nodeSync.Synced = true
+	return nodeSync
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/latencyprobe"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// the machine config daemon annotations describing the node configuration state
+	mcdCurrentConfigAnnotation = "machineconfiguration.openshift.io/currentConfig"
+	mcdDesiredConfigAnnotation = "machineconfiguration.openshift.io/desiredConfig"
+	mcdStateAnnotation         = "machineconfiguration.openshift.io/state"
+	mcdStateDone               = "Done"
+
+	jobNameLabel = "job-name"
+)
+
+// reconcileLatencyProbes runs the latency probe requested by the profile on every node which runs
+// the pool target configuration and records the probe result under the node tuned Profile status.
+// Every node is probed once per rendered machine config.
+func (r *PerformanceProfileReconciler) reconcileLatencyProbes(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool) error {
+	probe, err := latencyprobe.Get(profile)
+	if err != nil {
+		return err
+	}
+	if probe == nil {
+		return nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, &client.ListOptions{LabelSelector: labels.SelectorFromSet(profile.Spec.NodeSelector)}); err != nil {
+		return err
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		machineConfig, tuned := isNodeTuned(node, profileMCP)
+		if !tuned {
+			continue
+		}
+
+		tunedProfile := &tunedv1.Profile{}
+		key := types.NamespacedName{Namespace: components.NamespaceNodeTuningOperator, Name: node.Name}
+		if err := r.Get(ctx, key, tunedProfile); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		if !isTunedProfileApplied(tunedProfile) {
+			continue
+		}
+
+		if tunedProfile.Status.LatencyProbe != nil && tunedProfile.Status.LatencyProbe.MachineConfig == machineConfig {
+			continue
+		}
+
+		if err := r.syncLatencyProbeJob(ctx, profile, probe, tunedProfile, machineConfig); err != nil {
+			return fmt.Errorf("failed to sync latency probe of node %q: %w", node.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *PerformanceProfileReconciler) syncLatencyProbeJob(ctx context.Context, profile *performancev2.PerformanceProfile, probe *performancev2.LatencyProbe, tunedProfile *tunedv1.Profile, machineConfig string) error {
+	job := &batchv1.Job{}
+	key := types.NamespacedName{
+		Namespace: components.NamespaceNodeTuningOperator,
+		Name:      latencyprobe.JobName(tunedProfile.Name, machineConfig),
+	}
+	err := r.Get(ctx, key, job)
+	if errors.IsNotFound(err) {
+		job, err = latencyprobe.NewJob(profile, probe, tunedProfile.Name, machineConfig, components.NamespaceNodeTuningOperator)
+		if err != nil {
+			return err
+		}
+		if err := controllerutil.SetControllerReference(profile, job, r.Scheme); err != nil {
+			return err
+		}
+
+		klog.Infof("Create latency probe job %q for node %q", job.Name, tunedProfile.Name)
+		return r.Create(ctx, job)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !latencyprobe.IsFinished(job) {
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{jobNameLabel: job.Name}); err != nil {
+		return err
+	}
+
+	status := &tunedv1.LatencyProbeStatus{
+		Tool:           string(probe.Tool),
+		MachineConfig:  machineConfig,
+		CompletionTime: metav1.Now(),
+		Message:        "latency probe pod not found",
+	}
+	if len(pods.Items) > 0 {
+		status.MaxLatency, status.Message = latencyprobe.GetResult(&pods.Items[0])
+	}
+
+	tunedProfile = tunedProfile.DeepCopy()
+	tunedProfile.Status.LatencyProbe = status
+	if err := r.Status().Update(ctx, tunedProfile); err != nil {
+		return err
+	}
+	klog.Infof("Recorded latency probe result for node %q", tunedProfile.Name)
+
+	return r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+}
+
+// isNodeTuned returns the rendered machine config of the node when the node runs the pool target configuration
+func isNodeTuned(node *corev1.Node, profileMCP *mcov1.MachineConfigPool) (string, bool) {
+	current := node.Annotations[mcdCurrentConfigAnnotation]
+	if current == "" || current != profileMCP.Spec.Configuration.Name {
+		return "", false
+	}
+
+	if node.Annotations[mcdDesiredConfigAnnotation] != current || node.Annotations[mcdStateAnnotation] != mcdStateDone {
+		return "", false
+	}
+	return current, true
+}
+
+func isTunedProfileApplied(tunedProfile *tunedv1.Profile) bool {
+	for _, condition := range tunedProfile.Status.Conditions {
+		if condition.Type == tunedv1.TunedProfileApplied {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/artifactdiff"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// drainHintAnnotation records on the machine config pool the JSON encoded drainHint of the coming rollout of the
+	// profile changes, so the tools draining the pool nodes can prepare the workloads before the nodes reboot
+	drainHintAnnotation = "performance.openshift.io/drain-hint"
+
+	drainHintReasonKernelArguments = "KernelArgumentsChanged"
+	drainHintReasonConfiguration   = "ConfigurationChanged"
+)
+
+// drainHint describes the coming rollout of the profile changes rebooting the pool nodes
+type drainHint struct {
+	// Profiles are the performance profiles of the pool
+	Profiles []string `json:"profiles"`
+	// Reason is why the pool nodes reboot
+	Reason string `json:"reason"`
+	// SourceConfig is the rendered configuration the pool targeted before the rollout, the hint lasts until the pool
+	// rolled out a newer one
+	SourceConfig string `json:"sourceConfig"`
+	// NotBefore is the start of the maintenance window the rollout is postponed to
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// BlockingPodDisruptionBudgets are the PodDisruptionBudgets, in the namespace/name format, allowing no disruption
+	// of their pods running on the pool nodes, they block the drain of those nodes
+	BlockingPodDisruptionBudgets []string `json:"blockingPodDisruptionBudgets,omitempty"`
+}
+
+// getMaintenanceWindowStart returns the start of the next maintenance window of the profile pool, or the zero time
+// when the profile defines no maintenance window or the window is open at time 'now'
+func getMaintenanceWindowStart(profile *performancev2.PerformanceProfile, now time.Time) (time.Time, error) {
+	window := profile.Spec.MachineConfigPoolMaintenanceWindow
+	if window == nil {
+		return time.Time{}, nil
+	}
+
+	open, err := window.IsOpen(now)
+	if err != nil || open {
+		return time.Time{}, err
+	}
+	return window.NextStart(now)
+}
+
+// isKernelArgumentsChange returns true when the machine config 'mc' or the tuned 'tuned' change the kernel arguments
+// of the existing ones. The tuned sets the kernel arguments through the TuneD bootloader plugin, the operator
+// renders them into a machine config of the pool.
+func (r *PerformanceProfileReconciler) isKernelArgumentsChange(ctx context.Context, mc *mcov1.MachineConfig, tuned *tunedv1.Tuned) (bool, error) {
+	if mc != nil {
+		existing, err := r.getMachineConfig(ctx, mc.Name)
+		if err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+		var existingArgs []string
+		if err == nil {
+			existingArgs = existing.Spec.KernelArguments
+		}
+		if !util.StringSlicesEqual(existingArgs, mc.Spec.KernelArguments) {
+			return true, nil
+		}
+	}
+
+	if tuned == nil {
+		return false, nil
+	}
+	cmdlines, err := artifactdiff.RecommendedCmdlines(tuned)
+	if err != nil {
+		return false, err
+	}
+	existing, err := r.getTuned(tuned.Name, tuned.Namespace)
+	if errors.IsNotFound(err) {
+		return strings.Join(cmdlines, "") != "", nil
+	}
+	if err != nil {
+		return false, err
+	}
+	existingCmdlines, err := artifactdiff.RecommendedCmdlines(existing)
+	if err != nil {
+		return false, err
+	}
+	return !util.StringSlicesEqual(existingCmdlines, cmdlines), nil
+}
+
+// publishDrainHint records the drain hint of the coming pool rollout on the machine config pool, and warns with an
+// event of the profile when PodDisruptionBudgets block the drain
+func (r *PerformanceProfileReconciler) publishDrainHint(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool, reason string, notBefore time.Time) error {
+	hint := &drainHint{
+		Reason:       reason,
+		SourceConfig: profileMCP.Spec.Configuration.Name,
+	}
+	if !notBefore.IsZero() {
+		hint.NotBefore = &metav1.Time{Time: notBefore.UTC()}
+	}
+
+	var err error
+	if hint.Profiles, err = r.getPoolProfiles(ctx, profileMCP); err != nil {
+		return err
+	}
+	if hint.BlockingPodDisruptionBudgets, err = r.getBlockingPodDisruptionBudgets(ctx, profile); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(hint)
+	if err != nil {
+		return err
+	}
+	if profileMCP.Annotations[drainHintAnnotation] == string(value) {
+		return nil
+	}
+
+	mcpCopy := profileMCP.DeepCopy()
+	if mcpCopy.Annotations == nil {
+		mcpCopy.Annotations = map[string]string{}
+	}
+	mcpCopy.Annotations[drainHintAnnotation] = string(value)
+	if err := r.patchMachineConfigPool(ctx, profileMCP, mcpCopy); err != nil {
+		return err
+	}
+
+	klog.Infof("Published the drain hint of performance profile %q on the machine config pool %q: %s", profile.Name, profileMCP.Name, value)
+	if len(hint.BlockingPodDisruptionBudgets) > 0 {
+		r.Recorder.Eventf(profile, corev1.EventTypeWarning, "Drain blocked", "The machine config pool %q nodes will reboot (%s), the PodDisruptionBudgets %v allow no disruption",
+			profileMCP.Name, reason, hint.BlockingPodDisruptionBudgets)
+	}
+	return nil
+}
+
+// getPoolProfiles returns the names of the performance profiles of the pool
+func (r *PerformanceProfileReconciler) getPoolProfiles(ctx context.Context, profileMCP *mcov1.MachineConfigPool) ([]string, error) {
+	profiles := &performancev2.PerformanceProfileList{}
+	if err := r.List(ctx, profiles); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, request := range mcpToPerformanceProfileReconcileRequests(profiles, profileMCP) {
+		names = append(names, request.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// getBlockingPodDisruptionBudgets returns the PodDisruptionBudgets allowing no disruption of their pods running
+// on the profile nodes. The pods and the PodDisruptionBudgets of all the namespaces are not cached, so they are
+// read from the API server.
+func (r *PerformanceProfileReconciler) getBlockingPodDisruptionBudgets(ctx context.Context, profile *performancev2.PerformanceProfile) ([]string, error) {
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, &client.ListOptions{LabelSelector: labels.SelectorFromSet(profile.Spec.NodeSelector)}); err != nil {
+		return nil, err
+	}
+	nodeNames := sets.New[string]()
+	for i := range nodes.Items {
+		nodeNames.Insert(nodes.Items[i].Name)
+	}
+
+	pdbs := &policyv1.PodDisruptionBudgetList{}
+	if err := r.APIReader.List(ctx, pdbs); err != nil {
+		return nil, err
+	}
+
+	var blocking []string
+	for i := range pdbs.Items {
+		pdb := &pdbs.Items[i]
+		if pdb.Status.DisruptionsAllowed > 0 || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			klog.Warningf("failed to parse the selector of the PodDisruptionBudget %s/%s: %v", pdb.Namespace, pdb.Name, err)
+			continue
+		}
+
+		pods := &corev1.PodList{}
+		if err := r.APIReader.List(ctx, pods, &client.ListOptions{Namespace: pdb.Namespace, LabelSelector: selector}); err != nil {
+			return nil, err
+		}
+		for j := range pods.Items {
+			if nodeNames.Has(pods.Items[j].Spec.NodeName) {
+				blocking = append(blocking, fmt.Sprintf("%s/%s", pdb.Namespace, pdb.Name))
+				break
+			}
+		}
+	}
+	sort.Strings(blocking)
+	return blocking, nil
+}
+
+// reconcileDrainHint removes the drain hint from the pool once the pool rolled out the profile changes
+func (r *PerformanceProfileReconciler) reconcileDrainHint(ctx context.Context, profileMCP *mcov1.MachineConfigPool) error {
+	value, ok := profileMCP.Annotations[drainHintAnnotation]
+	if !ok {
+		return nil
+	}
+
+	hint := &drainHint{}
+	if err := json.Unmarshal([]byte(value), hint); err != nil {
+		klog.Warningf("failed to parse the %q annotation of the machine config pool %q: %v", drainHintAnnotation, profileMCP.Name, err)
+	} else if isUpgradeRolloutInProgress(profileMCP, hint.SourceConfig) {
+		return nil
+	}
+
+	return r.removeDrainHint(ctx, profileMCP)
+}
+
+// restoreDrainHint removes the drain hint from the pool of the deleted profile
+func (r *PerformanceProfileReconciler) restoreDrainHint(ctx context.Context, profile *performancev2.PerformanceProfile) error {
+	profileMCP, err := r.getMachineConfigPoolByProfile(ctx, profile)
+	if err != nil {
+		// the pool was deleted or no longer selects the profile, there is nothing to remove
+		klog.Warningf("failed to get the machine config pool of performance profile %q: %v", profile.Name, err)
+		return nil
+	}
+
+	if _, ok := profileMCP.Annotations[drainHintAnnotation]; !ok {
+		return nil
+	}
+	return r.removeDrainHint(ctx, profileMCP)
+}
+
+func (r *PerformanceProfileReconciler) removeDrainHint(ctx context.Context, profileMCP *mcov1.MachineConfigPool) error {
+	mcpCopy := profileMCP.DeepCopy()
+	delete(mcpCopy.Annotations, drainHintAnnotation)
+
+	klog.Infof("Remove the drain hint of the machine config pool %q", profileMCP.Name)
+	return r.patchMachineConfigPool(ctx, profileMCP, mcpCopy)
+}
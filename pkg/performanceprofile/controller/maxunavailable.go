@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog"
+)
+
+const (
+	// originalMaxUnavailableAnnotation records on the machine config pool the maxUnavailable the pool defined
+	// before the profile overrode it, an empty value stands for the unset maxUnavailable
+	originalMaxUnavailableAnnotation = "performance.openshift.io/original-max-unavailable"
+	// maxUnavailableSourceConfigAnnotation records on the machine config pool the rendered configuration the pool
+	// targeted when the profile overrode its maxUnavailable, the override lasts until the pool rolled out a newer one
+	maxUnavailableSourceConfigAnnotation = "performance.openshift.io/max-unavailable-source-config"
+)
+
+// overrideMaxUnavailable sets the maxUnavailable of the profile pool to the one the profile defines, before the
+// profile components changes roll out. The pool maxUnavailable is recorded, so it can be restored after the rollout.
+func (r *PerformanceProfileReconciler) overrideMaxUnavailable(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool) error {
+	if profile.Spec.MachineConfigPoolMaxUnavailable == nil {
+		return nil
+	}
+
+	mcpCopy := profileMCP.DeepCopy()
+	if mcpCopy.Annotations == nil {
+		mcpCopy.Annotations = map[string]string{}
+	}
+	if _, ok := mcpCopy.Annotations[originalMaxUnavailableAnnotation]; !ok {
+		mcpCopy.Annotations[originalMaxUnavailableAnnotation] = formatMaxUnavailable(profileMCP.Spec.MaxUnavailable)
+	}
+	mcpCopy.Annotations[maxUnavailableSourceConfigAnnotation] = profileMCP.Spec.Configuration.Name
+	mcpCopy.Spec.MaxUnavailable = profile.Spec.MachineConfigPoolMaxUnavailable
+
+	klog.Infof("Set the maxUnavailable of the machine config pool %q to %s for the rollout of performance profile %q",
+		profileMCP.Name, profile.Spec.MachineConfigPoolMaxUnavailable.String(), profile.Name)
+	return r.patchMachineConfigPool(ctx, profileMCP, mcpCopy)
+}
+
+// reconcileMaxUnavailable restores the maxUnavailable of the profile pool once the pool rolled out the profile
+// components changes, or once the profile no longer defines it. Until then, the pool follows the profile value.
+func (r *PerformanceProfileReconciler) reconcileMaxUnavailable(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool) error {
+	original, ok := profileMCP.Annotations[originalMaxUnavailableAnnotation]
+	if !ok {
+		return nil
+	}
+
+	mcpCopy := profileMCP.DeepCopy()
+	sourceConfig := profileMCP.Annotations[maxUnavailableSourceConfigAnnotation]
+	if profile.Spec.MachineConfigPoolMaxUnavailable != nil && isUpgradeRolloutInProgress(profileMCP, sourceConfig) {
+		if profileMCP.Spec.MaxUnavailable != nil && *profileMCP.Spec.MaxUnavailable == *profile.Spec.MachineConfigPoolMaxUnavailable {
+			return nil
+		}
+		mcpCopy.Spec.MaxUnavailable = profile.Spec.MachineConfigPoolMaxUnavailable
+	} else {
+		delete(mcpCopy.Annotations, originalMaxUnavailableAnnotation)
+		delete(mcpCopy.Annotations, maxUnavailableSourceConfigAnnotation)
+		mcpCopy.Spec.MaxUnavailable = parseMaxUnavailable(original)
+		klog.Infof("Restore the maxUnavailable of the machine config pool %q after the rollout of performance profile %q", profileMCP.Name, profile.Name)
+	}
+
+	return r.patchMachineConfigPool(ctx, profileMCP, mcpCopy)
+}
+
+// restoreMaxUnavailable restores the maxUnavailable of the pool of the deleted profile
+func (r *PerformanceProfileReconciler) restoreMaxUnavailable(ctx context.Context, profile *performancev2.PerformanceProfile) error {
+	profileMCP, err := r.getMachineConfigPoolByProfile(ctx, profile)
+	if err != nil {
+		// the pool was deleted or no longer selects the profile, there is nothing to restore
+		klog.Warningf("failed to get the machine config pool of performance profile %q: %v", profile.Name, err)
+		return nil
+	}
+
+	profileCopy := profile.DeepCopy()
+	profileCopy.Spec.MachineConfigPoolMaxUnavailable = nil
+	return r.reconcileMaxUnavailable(ctx, profileCopy, profileMCP)
+}
+
+func formatMaxUnavailable(maxUnavailable *intstr.IntOrString) string {
+	if maxUnavailable == nil {
+		return ""
+	}
+	return maxUnavailable.String()
+}
+
+func parseMaxUnavailable(value string) *intstr.IntOrString {
+	if value == "" {
+		return nil
+	}
+	maxUnavailable := intstr.Parse(value)
+	return &maxUnavailable
+}
@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/machineconfig"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// migrationSourcePoolLabel marks the copies of the profile components kept for the machine config pool
+	// the profile targeted before its node selector changed, the label value is the pool name
+	migrationSourcePoolLabel = "performance.openshift.io/migration-source-pool"
+	migrationNameSuffix      = "-migration"
+
+	conditionTypeMigrating conditionsv1.ConditionType = "Migrating"
+)
+
+// reconcileMigration keeps the nodes of the previous machine config pool tuned while the profile moves
+// to a new pool. Once the profile targets a new pool, the machine config, kubelet config and tuned
+// of the previous pool are copied, and the copies are removed when the new pool runs the profile.
+// It returns the name of the previous pool while the migration is in progress.
+func (r *PerformanceProfileReconciler) reconcileMigration(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool) (string, error) {
+	sourcePool, err := r.getMigrationSourcePool(ctx, profile)
+	if err != nil {
+		return "", err
+	}
+
+	if sourcePool == "" {
+		if profile.Status.Rollout == nil || profile.Status.Rollout.MachineConfigPool == "" || profile.Status.Rollout.MachineConfigPool == profileMCP.Name {
+			return "", nil
+		}
+
+		// the machine config operator renders the pools with a short delay, the copies and the updated
+		// components are created under the same reconcile, so the previous pool never renders without them
+		sourcePool = profile.Status.Rollout.MachineConfigPool
+		klog.Infof("Performance profile %q moves from the machine config pool %q to %q", profile.Name, sourcePool, profileMCP.Name)
+		if err := r.createMigrationComponents(ctx, profile, sourcePool); err != nil {
+			return "", err
+		}
+		return sourcePool, nil
+	}
+
+	// the profile targets the previous pool again, its components cover the pool nodes
+	if sourcePool != profileMCP.Name && !isMachineConfigPoolUpdated(profileMCP, machineconfig.GetMachineConfigName(profile)) {
+		return sourcePool, nil
+	}
+
+	klog.Infof("Machine config pool %q runs the performance profile %q, remove the components kept for the pool %q", profileMCP.Name, profile.Name, sourcePool)
+	return "", r.deleteMigrationComponents(profile)
+}
+
+// getMigrationSourcePool returns the name of the pool the migration components were kept for,
+// or an empty string when there is no migration in progress
+func (r *PerformanceProfileReconciler) getMigrationSourcePool(ctx context.Context, profile *performancev2.PerformanceProfile) (string, error) {
+	mc, err := r.getMachineConfig(ctx, getMigrationName(machineconfig.GetMachineConfigName(profile)))
+	if err == nil {
+		return mc.Labels[migrationSourcePoolLabel], nil
+	}
+	if !errors.IsNotFound(err) {
+		return "", err
+	}
+
+	kc, err := r.getKubeletConfig(getMigrationName(components.GetComponentName(profile.Name, components.ComponentNamePrefix)))
+	if err == nil {
+		return kc.Labels[migrationSourcePoolLabel], nil
+	}
+	if !errors.IsNotFound(err) {
+		return "", err
+	}
+
+	tuned, err := r.getTuned(getMigrationName(components.GetComponentName(profile.Name, components.ProfileNamePerformance)), components.NamespaceNodeTuningOperator)
+	if err == nil {
+		return tuned.Labels[migrationSourcePoolLabel], nil
+	}
+	if !errors.IsNotFound(err) {
+		return "", err
+	}
+	return "", nil
+}
+
+func (r *PerformanceProfileReconciler) createMigrationComponents(ctx context.Context, profile *performancev2.PerformanceProfile, sourcePool string) error {
+	mc, err := r.getMachineConfig(ctx, machineconfig.GetMachineConfigName(profile))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		mcCopy := &mcov1.MachineConfig{
+			TypeMeta:   mc.TypeMeta,
+			ObjectMeta: getMigrationObjectMeta(&mc.ObjectMeta, sourcePool),
+			Spec:       *mc.Spec.DeepCopy(),
+		}
+		if err := r.createMigrationComponent(ctx, mcCopy); err != nil {
+			return err
+		}
+	}
+
+	kc, err := r.getKubeletConfig(components.GetComponentName(profile.Name, components.ComponentNamePrefix))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		// the copy keeps the machine config name suffix annotation, so it owns the kubelet machine config
+		// generated for the previous pool instead of rendering a new one
+		kcCopy := &mcov1.KubeletConfig{
+			TypeMeta:   kc.TypeMeta,
+			ObjectMeta: getMigrationObjectMeta(&kc.ObjectMeta, sourcePool),
+			Spec:       *kc.Spec.DeepCopy(),
+		}
+		if err := r.createMigrationComponent(ctx, kcCopy); err != nil {
+			return err
+		}
+	}
+
+	tuned, err := r.getTuned(components.GetComponentName(profile.Name, components.ProfileNamePerformance), components.NamespaceNodeTuningOperator)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		// the copy keeps the tuned profiles names, so the nodes of the previous pool keep running the same profile
+		tunedCopy := &tunedv1.Tuned{
+			TypeMeta:   tuned.TypeMeta,
+			ObjectMeta: getMigrationObjectMeta(&tuned.ObjectMeta, sourcePool),
+			Spec:       *tuned.Spec.DeepCopy(),
+		}
+		if err := r.createMigrationComponent(ctx, tunedCopy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PerformanceProfileReconciler) createMigrationComponent(ctx context.Context, obj client.Object) error {
+	klog.Infof("Create migration copy %q for the machine config pool %q", obj.GetName(), obj.GetLabels()[migrationSourcePoolLabel])
+	if err := r.Create(ctx, obj); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *PerformanceProfileReconciler) deleteMigrationComponents(profile *performancev2.PerformanceProfile) error {
+	tunedName := getMigrationName(components.GetComponentName(profile.Name, components.ProfileNamePerformance))
+	if err := r.deleteTuned(tunedName, components.NamespaceNodeTuningOperator); err != nil {
+		return err
+	}
+
+	if err := r.deleteKubeletConfig(getMigrationName(components.GetComponentName(profile.Name, components.ComponentNamePrefix))); err != nil {
+		return err
+	}
+
+	return r.deleteMachineConfig(getMigrationName(machineconfig.GetMachineConfigName(profile)))
+}
+
+func (r *PerformanceProfileReconciler) isMigrationComponentsExist(profile *performancev2.PerformanceProfile) bool {
+	sourcePool, err := r.getMigrationSourcePool(context.TODO(), profile)
+	if err != nil || sourcePool != "" {
+		klog.Infof("Migration components of the performance profile %q still exist", profile.Name)
+		return true
+	}
+	return false
+}
+
+// isMachineConfigPoolUpdated returns true when all the pool nodes run a rendered configuration
+// which includes the machine config
+func isMachineConfigPoolUpdated(mcp *mcov1.MachineConfigPool, machineConfigName string) bool {
+	rendered := false
+	for _, source := range mcp.Spec.Configuration.Source {
+		if source.Name == machineConfigName {
+			rendered = true
+			break
+		}
+	}
+	if !rendered {
+		return false
+	}
+
+	return mcp.Status.Configuration.Name == mcp.Spec.Configuration.Name &&
+		mcp.Status.UpdatedMachineCount == mcp.Status.MachineCount &&
+		mcp.Status.DegradedMachineCount == 0
+}
+
+func isMigrationComponent(obj metav1.Object) bool {
+	_, ok := obj.GetLabels()[migrationSourcePoolLabel]
+	return ok
+}
+
+func getMigrationName(name string) string {
+	return name + migrationNameSuffix
+}
+
+func getMigrationObjectMeta(meta *metav1.ObjectMeta, sourcePool string) metav1.ObjectMeta {
+	labels := map[string]string{}
+	for key, value := range meta.Labels {
+		labels[key] = value
+	}
+	labels[migrationSourcePoolLabel] = sourcePool
+
+	annotations := map[string]string{}
+	for key, value := range meta.Annotations {
+		annotations[key] = value
+	}
+
+	return metav1.ObjectMeta{
+		Name:            getMigrationName(meta.Name),
+		Namespace:       meta.Namespace,
+		Labels:          labels,
+		Annotations:     annotations,
+		OwnerReferences: meta.OwnerReferences,
+	}
+}
+
+func getMigratingCondition(sourcePool, targetPool string) conditionsv1.Condition {
+	now := metav1.Now()
+	return conditionsv1.Condition{
+		Type:               conditionTypeMigrating,
+		Status:             corev1.ConditionTrue,
+		Reason:             conditionReasonNodeSelectorChanged,
+		Message:            fmt.Sprintf("Keeping the components of the machine config pool %q until the machine config pool %q is updated", sourcePool, targetPool),
+		LastTransitionTime: now,
+		LastHeartbeatTime:  now,
+	}
+}
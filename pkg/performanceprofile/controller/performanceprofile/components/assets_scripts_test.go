@@ -127,6 +127,42 @@ var _ = Describe("Assets scripts", func() {
 			Expect(ok).To(BeTrue())
 			Expect(updatedBannedCPUs).To(Equal("0"))
 		})
+
+		It("should set the requested ban list", func() {
+			bannedCPUsMask := "00000000,0000000c"
+			confName, err := writeTempFile(confTemplate)
+			Expect(err).ToNot(HaveOccurred())
+			defer os.Remove(confName)
+
+			restoreConf, err := os.CreateTemp("", "test-irqbalance-orig")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.Remove(restoreConf.Name())
+
+			cmdline := []string{
+				filepath.Join(scriptsPath, ClearIRQBalanceBannedCPUs),
+				confName,
+				restoreConf.Name(),
+			}
+			fmt.Fprintf(GinkgoWriter, "running: %v\n", cmdline)
+
+			cmd := exec.Command(cmdline[0], cmdline[1:]...)
+			cmd.Env = append(os.Environ(), "IRQBALANCE_BANNED_CPUS_MASK="+bannedCPUsMask)
+			cmd.Stderr = GinkgoWriter
+
+			_, err = cmd.Output()
+			Expect(err).ToNot(HaveOccurred())
+
+			data, err := os.ReadFile(confName)
+			Expect(err).ToNot(HaveOccurred())
+
+			updatedBannedCPUs, ok := extractBannedCPUs(string(data))
+			Expect(ok).To(BeTrue())
+			Expect(updatedBannedCPUs).To(Equal(bannedCPUsMask))
+
+			restoreData, err := os.ReadFile(restoreConf.Name())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strings.TrimSpace(string(restoreData))).To(Equal(bannedCPUsMask))
+		})
 	})
 })
 
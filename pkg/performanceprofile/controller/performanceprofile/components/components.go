@@ -24,6 +24,8 @@ import (
 type Options struct {
 	ProfileMCP    *mcov1.MachineConfigPool
 	MachineConfig MachineConfigOptions
+	// DisabledFeatures lists the profile features disabled by the cluster feature gates
+	DisabledFeatures []ProfileFeature
 }
 
 type MachineConfigOptions struct {
@@ -35,4 +37,5 @@ type MachineConfigOptions struct {
 type KubeletConfigOptions struct {
 	MachineConfigPoolSelector map[string]string
 	MixedCPUsEnabled          bool
+	DisabledFeatures          []ProfileFeature
 }
@@ -31,3 +31,25 @@ const (
 	// HugepagesSize1G contains the size of 1G hugepages
 	HugepagesSize1G = "1G"
 )
+
+const (
+	// OperatorVersionAnnotation records the release version of the operator which generated a component
+	OperatorVersionAnnotation = "performance.openshift.io/operator-version"
+	// ArtifactSchemaVersionAnnotation records the schema version of the generated component
+	ArtifactSchemaVersionAnnotation = "performance.openshift.io/artifact-schema-version"
+	// ArtifactSchemaVersion is the schema version of the components generated by this operator,
+	// it should be bumped when the rendering of the components changes in a way older operators can not handle
+	ArtifactSchemaVersion = 1
+)
+
+// KubeletConfigOwnedFields lists the kubelet configuration fields the operator computes out of the profile,
+// the profile kubelet config overrides can not set them
+var KubeletConfigOwnedFields = []string{
+	"cpuManagerPolicy",
+	"cpuManagerReconcilePeriod",
+	"memoryManagerPolicy",
+	"reservedSystemCPUs",
+	"topologyManagerPolicy",
+	"topologyManagerPolicyOptions",
+	"topologyManagerScope",
+}
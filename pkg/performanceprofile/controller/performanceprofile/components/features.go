@@ -0,0 +1,36 @@
+package components
+
+import (
+	apiconfigv1 "github.com/openshift/api/config/v1"
+)
+
+// ProfileFeature names a feature of the performance profile whose components depend on a cluster feature gate
+type ProfileFeature string
+
+const (
+	// ProfileFeatureMixedCPUs shares the spec.cpu.shared CPUs with the workloads
+	ProfileFeatureMixedCPUs ProfileFeature = "MixedCPUs"
+	// ProfileFeatureNodeSwap lets the kubelet snippet annotation configure the workloads swap
+	ProfileFeatureNodeSwap ProfileFeature = "NodeSwap"
+	// ProfileFeatureEventedPLEG lets the kubelet snippet annotation enable the evented pod lifecycle event generator
+	ProfileFeatureEventedPLEG ProfileFeature = "EventedPLEG"
+)
+
+// ProfileFeatureGates maps the profile features to the cluster feature gates enabling them,
+// the components skip the features whose feature gate is disabled instead of generating
+// configurations the kubelet rejects
+var ProfileFeatureGates = map[ProfileFeature]apiconfigv1.FeatureGateName{
+	ProfileFeatureMixedCPUs:   apiconfigv1.FeatureGateMixedCPUsAllocation,
+	ProfileFeatureNodeSwap:    apiconfigv1.FeatureGateNodeSwap,
+	ProfileFeatureEventedPLEG: apiconfigv1.FeatureGateEventedPLEG,
+}
+
+// IsFeatureDisabled returns true when the feature is part of the disabled features
+func IsFeatureDisabled(disabledFeatures []ProfileFeature, feature ProfileFeature) bool {
+	for _, disabled := range disabledFeatures {
+		if disabled == feature {
+			return true
+		}
+	}
+	return false
+}
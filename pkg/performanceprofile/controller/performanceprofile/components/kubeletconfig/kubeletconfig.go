@@ -2,12 +2,16 @@ package kubeletconfig
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
 	"k8s.io/utils/cpuset"
 
@@ -41,16 +45,16 @@ const (
 	evictionHardNodefsAvaialble                  = "nodefs.available"
 	evictionHardImagefsAvailable                 = "imagefs.available"
 	evictionHardNodefsInodesFree                 = "nodefs.inodesFree"
+	featureGateNodeSwap                          = "NodeSwap"
+	featureGateEventedPLEG                       = "EventedPLEG"
 )
 
 // New returns new KubeletConfig object for performance sensetive workflows
 func New(profile *performancev2.PerformanceProfile, opts *components.KubeletConfigOptions) (*machineconfigv1.KubeletConfig, error) {
 	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
-	kubeletConfig := &kubeletconfigv1beta1.KubeletConfiguration{}
-	if v, ok := profile.Annotations[experimentalKubeletSnippetAnnotation]; ok {
-		if err := json.Unmarshal([]byte(v), kubeletConfig); err != nil {
-			return nil, err
-		}
+	kubeletConfig, err := getKubeletSnippet(profile)
+	if err != nil {
+		return nil, err
 	}
 
 	kubeletConfig.TypeMeta = metav1.TypeMeta{
@@ -112,6 +116,19 @@ func New(profile *performancev2.PerformanceProfile, opts *components.KubeletConf
 	}
 
 	if profile.Spec.NUMA != nil {
+		if profile.Spec.NUMA.TopologyManagerScope != nil {
+			kubeletConfig.TopologyManagerScope = *profile.Spec.NUMA.TopologyManagerScope
+		}
+
+		if len(profile.Spec.NUMA.TopologyPolicyOptions) > 0 {
+			if kubeletConfig.TopologyManagerPolicyOptions == nil {
+				kubeletConfig.TopologyManagerPolicyOptions = make(map[string]string)
+			}
+			for option, value := range profile.Spec.NUMA.TopologyPolicyOptions {
+				kubeletConfig.TopologyManagerPolicyOptions[option] = value
+			}
+		}
+
 		if profile.Spec.NUMA.TopologyPolicy != nil {
 			topologyPolicy := *profile.Spec.NUMA.TopologyPolicy
 			kubeletConfig.TopologyManagerPolicy = topologyPolicy
@@ -160,6 +177,21 @@ func New(profile *performancev2.PerformanceProfile, opts *components.KubeletConf
 		}
 	}
 
+	kubeletConfig, err = applyKubeletConfigOverrides(kubeletConfig, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// skip the snippet options the disabled cluster feature gates make the kubelet reject
+	if components.IsFeatureDisabled(opts.DisabledFeatures, components.ProfileFeatureNodeSwap) {
+		kubeletConfig.FailSwapOn = nil
+		kubeletConfig.MemorySwap = kubeletconfigv1beta1.MemorySwapConfiguration{}
+		delete(kubeletConfig.FeatureGates, featureGateNodeSwap)
+	}
+	if components.IsFeatureDisabled(opts.DisabledFeatures, components.ProfileFeatureEventedPLEG) {
+		delete(kubeletConfig.FeatureGates, featureGateEventedPLEG)
+	}
+
 	raw, err := json.Marshal(kubeletConfig)
 	if err != nil {
 		return nil, err
@@ -184,6 +216,108 @@ func New(profile *performancev2.PerformanceProfile, opts *components.KubeletConf
 	}, nil
 }
 
+// GetRequestedFeatures returns the feature gated profile features requested by the kubelet snippet annotation
+// and the kubelet config overrides
+func GetRequestedFeatures(profile *performancev2.PerformanceProfile) ([]components.ProfileFeature, error) {
+	kubeletConfig, err := getKubeletSnippet(profile)
+	if err != nil {
+		return nil, err
+	}
+	kubeletConfig, err = mergeKubeletConfigOverrides(kubeletConfig, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var features []components.ProfileFeature
+	if kubeletConfig.FeatureGates[featureGateNodeSwap] ||
+		kubeletConfig.MemorySwap.SwapBehavior != "" ||
+		(kubeletConfig.FailSwapOn != nil && !*kubeletConfig.FailSwapOn) {
+		features = append(features, components.ProfileFeatureNodeSwap)
+	}
+	if kubeletConfig.FeatureGates[featureGateEventedPLEG] {
+		features = append(features, components.ProfileFeatureEventedPLEG)
+	}
+	return features, nil
+}
+
+// getKubeletSnippet returns the kubelet configuration provided by the kubelet snippet annotation
+func getKubeletSnippet(profile *performancev2.PerformanceProfile) (*kubeletconfigv1beta1.KubeletConfiguration, error) {
+	kubeletConfig := &kubeletconfigv1beta1.KubeletConfiguration{}
+	if v, ok := profile.Annotations[experimentalKubeletSnippetAnnotation]; ok {
+		if err := json.Unmarshal([]byte(v), kubeletConfig); err != nil {
+			return nil, err
+		}
+	}
+	return kubeletConfig, nil
+}
+
+// applyKubeletConfigOverrides merges the profile kubelet config overrides into the generated kubelet configuration,
+// it fails when the overrides change the fields the operator owns
+func applyKubeletConfigOverrides(kubeletConfig *kubeletconfigv1beta1.KubeletConfiguration, profile *performancev2.PerformanceProfile) (*kubeletconfigv1beta1.KubeletConfiguration, error) {
+	if profile.Spec.KubeletConfigOverrides == nil {
+		return kubeletConfig, nil
+	}
+
+	merged, err := mergeKubeletConfigOverrides(kubeletConfig, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	generatedFields, err := kubeletConfigFields(kubeletConfig)
+	if err != nil {
+		return nil, err
+	}
+	mergedFields, err := kubeletConfigFields(merged)
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []string
+	for _, owned := range components.KubeletConfigOwnedFields {
+		if !equality.Semantic.DeepEqual(generatedFields[owned], mergedFields[owned]) {
+			conflicts = append(conflicts, owned)
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("the kubelet config overrides conflict with the fields computed out of the profile: %s", strings.Join(conflicts, ", "))
+	}
+	return merged, nil
+}
+
+// mergeKubeletConfigOverrides returns the kubelet configuration with the profile kubelet config overrides
+// strategically merged into it
+func mergeKubeletConfigOverrides(kubeletConfig *kubeletconfigv1beta1.KubeletConfiguration, profile *performancev2.PerformanceProfile) (*kubeletconfigv1beta1.KubeletConfiguration, error) {
+	if profile.Spec.KubeletConfigOverrides == nil {
+		return kubeletConfig, nil
+	}
+
+	original, err := json.Marshal(kubeletConfig)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := strategicpatch.StrategicMergePatch(original, profile.Spec.KubeletConfigOverrides.Raw, kubeletconfigv1beta1.KubeletConfiguration{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge the kubelet config overrides: %w", err)
+	}
+
+	mergedConfig := &kubeletconfigv1beta1.KubeletConfiguration{}
+	if err := json.Unmarshal(merged, mergedConfig); err != nil {
+		return nil, err
+	}
+	return mergedConfig, nil
+}
+
+func kubeletConfigFields(kubeletConfig *kubeletconfigv1beta1.KubeletConfiguration) (map[string]interface{}, error) {
+	raw, err := json.Marshal(kubeletConfig)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
 func addStringToQuantity(q *resource.Quantity, value string) error {
 	v, err := resource.ParseQuantity(value)
 	if err != nil {
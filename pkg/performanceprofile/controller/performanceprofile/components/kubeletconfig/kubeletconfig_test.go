@@ -5,6 +5,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
 	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
 	"k8s.io/kubernetes/pkg/kubelet/eviction"
 	"k8s.io/utils/pointer"
@@ -89,6 +90,38 @@ var _ = Describe("Kubelet Config", func() {
 		})
 	})
 
+	Context("with topology manager scope and policy options", func() {
+		It("should set the topology manager scope and policy options", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.NUMA.TopologyManagerScope = pointer.String(kubeletconfigv1beta1.PodTopologyManagerScope)
+			profile.Spec.NUMA.TopologyPolicyOptions = map[string]string{"prefer-closest-numa-nodes": "true"}
+			selectorKey, selectorValue := components.GetFirstKeyAndValue(profile.Spec.MachineConfigPoolSelector)
+			kc, err := New(profile, &components.KubeletConfigOptions{MachineConfigPoolSelector: map[string]string{selectorKey: selectorValue}})
+			Expect(err).ToNot(HaveOccurred())
+
+			y, err := yaml.Marshal(kc)
+			Expect(err).ToNot(HaveOccurred())
+
+			manifest := string(y)
+			Expect(manifest).To(ContainSubstring("topologyManagerScope: pod"))
+			Expect(manifest).To(ContainSubstring("prefer-closest-numa-nodes: \"true\""))
+		})
+
+		It("should not set the topology manager scope and policy options by default", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			selectorKey, selectorValue := components.GetFirstKeyAndValue(profile.Spec.MachineConfigPoolSelector)
+			kc, err := New(profile, &components.KubeletConfigOptions{MachineConfigPoolSelector: map[string]string{selectorKey: selectorValue}})
+			Expect(err).ToNot(HaveOccurred())
+
+			y, err := yaml.Marshal(kc)
+			Expect(err).ToNot(HaveOccurred())
+
+			manifest := string(y)
+			Expect(manifest).ToNot(ContainSubstring("topologyManagerScope"))
+			Expect(manifest).ToNot(ContainSubstring("topologyManagerPolicyOptions"))
+		})
+	})
+
 	Context("with additional kubelet arguments", func() {
 		It("should not override CPU manager parameters", func() {
 			profile := testutils.NewPerformanceProfile("test")
@@ -191,4 +224,101 @@ var _ = Describe("Kubelet Config", func() {
 		})
 
 	})
+
+	Context("with kubelet config overrides", func() {
+		It("should merge the overrides on top of the kubelet snippet", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Annotations = map[string]string{
+				experimentalKubeletSnippetAnnotation: `{"allowedUnsafeSysctls": ["net.core.somaxconn"], "maxPods": 100}`,
+			}
+			profile.Spec.KubeletConfigOverrides = &runtime.RawExtension{
+				Raw: []byte(`{"maxPods": 250, "evictionHard": {"memory.available": "300Mi"}}`),
+			}
+			kc, err := New(profile, &components.KubeletConfigOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			y, err := yaml.Marshal(kc)
+			Expect(err).ToNot(HaveOccurred())
+
+			manifest := string(y)
+			Expect(manifest).To(ContainSubstring("net.core.somaxconn"))
+			Expect(manifest).To(ContainSubstring("maxPods: 250"))
+			Expect(manifest).To(ContainSubstring("memory.available: 300Mi"))
+			// the other eviction thresholds keep their defaults
+			Expect(manifest).To(ContainSubstring("nodefs.available: " + defaultHardEvictionThresholdNodefs))
+			Expect(manifest).To(ContainSubstring("reservedSystemCPUs: 0-3"))
+		})
+
+		It("should accept overrides matching the fields computed out of the profile", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.KubeletConfigOverrides = &runtime.RawExtension{
+				Raw: []byte(`{"cpuManagerPolicy": "static", "reservedSystemCPUs": "0-3"}`),
+			}
+			_, err := New(profile, &components.KubeletConfigOptions{})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail on overrides conflicting with the fields computed out of the profile", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.KubeletConfigOverrides = &runtime.RawExtension{
+				Raw: []byte(`{"reservedSystemCPUs": "0-1", "topologyManagerPolicy": "none", "maxPods": 250}`),
+			}
+			_, err := New(profile, &components.KubeletConfigOptions{})
+			Expect(err).To(MatchError(ContainSubstring("reservedSystemCPUs, topologyManagerPolicy")))
+		})
+
+		It("should report the features requested by the overrides", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.KubeletConfigOverrides = &runtime.RawExtension{
+				Raw: []byte(`{"featureGates": {"EventedPLEG": true}}`),
+			}
+			features, err := GetRequestedFeatures(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(features).To(ConsistOf(components.ProfileFeatureEventedPLEG))
+
+			kc, err := New(profile, &components.KubeletConfigOptions{
+				DisabledFeatures: []components.ProfileFeature{components.ProfileFeatureEventedPLEG},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(kc.Spec.KubeletConfig.Raw)).ToNot(ContainSubstring("EventedPLEG"))
+		})
+	})
+
+	Context("with feature gated kubelet options", func() {
+		const swapSnippet = `{"failSwapOn": false, "memorySwap": {"swapBehavior": "LimitedSwap"}, "featureGates": {"NodeSwap": true, "EventedPLEG": true}}`
+
+		It("should report the features requested by the kubelet snippet", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Annotations = map[string]string{experimentalKubeletSnippetAnnotation: swapSnippet}
+			features, err := GetRequestedFeatures(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(features).To(ConsistOf(components.ProfileFeatureNodeSwap, components.ProfileFeatureEventedPLEG))
+		})
+
+		It("should keep the options of the enabled features", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Annotations = map[string]string{experimentalKubeletSnippetAnnotation: swapSnippet}
+			kc, err := New(profile, &components.KubeletConfigOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			manifest := string(kc.Spec.KubeletConfig.Raw)
+			Expect(manifest).To(ContainSubstring(`"failSwapOn":false`))
+			Expect(manifest).To(ContainSubstring(`"swapBehavior":"LimitedSwap"`))
+			Expect(manifest).To(ContainSubstring(`"EventedPLEG":true`))
+		})
+
+		It("should skip the options of the disabled features", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Annotations = map[string]string{experimentalKubeletSnippetAnnotation: swapSnippet}
+			kc, err := New(profile, &components.KubeletConfigOptions{
+				DisabledFeatures: []components.ProfileFeature{components.ProfileFeatureNodeSwap, components.ProfileFeatureEventedPLEG},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			manifest := string(kc.Spec.KubeletConfig.Raw)
+			Expect(manifest).ToNot(ContainSubstring("failSwapOn"))
+			Expect(manifest).ToNot(ContainSubstring("LimitedSwap"))
+			Expect(manifest).ToNot(ContainSubstring("NodeSwap"))
+			Expect(manifest).ToNot(ContainSubstring("EventedPLEG"))
+		})
+	})
 })
@@ -0,0 +1,189 @@
+package latencyprobe
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	assets "github.com/openshift/cluster-node-tuning-operator/assets/performanceprofile"
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// LabelProfile is the label holding the name of the performance profile that requested the probe
+	LabelProfile = "performance.openshift.io/latency-probe-profile"
+	// AnnotationNode is the annotation holding the name of the node the probe runs on
+	AnnotationNode = "performance.openshift.io/latency-probe-node"
+	// AnnotationMachineConfig is the annotation holding the rendered machine config the node runs during the probe
+	AnnotationMachineConfig = "performance.openshift.io/latency-probe-machine-config"
+
+	defaultDuration   = time.Minute
+	defaultCPUs       = 2
+	defaultMemory     = "256Mi"
+	serviceAccount    = "tuned"
+	containerName     = "latency-probe"
+	scriptName        = "latency-probe"
+	jobNamePrefix     = "latency-probe-"
+	nameHashLength    = 10
+	jobBackoffLimit   = 0
+	jobTTLAfterFinish = 3600
+
+	environmentTool     = "LATENCY_PROBE_TOOL"
+	environmentDuration = "LATENCY_PROBE_DURATION"
+)
+
+// Get returns the latency probe requested by the profile annotation with the defaults applied,
+// it returns nil when the profile does not request a latency probe
+func Get(profile *performancev2.PerformanceProfile) (*performancev2.LatencyProbe, error) {
+	rawProbe, ok := profile.Annotations[performancev2.PerformanceProfileLatencyProbeAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	probe := &performancev2.LatencyProbe{}
+	if err := json.Unmarshal([]byte(rawProbe), probe); err != nil {
+		return nil, fmt.Errorf("failed to parse %q annotation: %w", performancev2.PerformanceProfileLatencyProbeAnnotation, err)
+	}
+
+	if probe.Tool == "" {
+		probe.Tool = performancev2.LatencyProbeToolCyclictest
+	}
+	if probe.Duration == nil {
+		probe.Duration = &metav1.Duration{Duration: defaultDuration}
+	}
+	if probe.CPUs == nil {
+		probe.CPUs = pointer.Int32(defaultCPUs)
+	}
+	return probe, nil
+}
+
+// JobName returns the name of the job probing the node running the rendered machine config
+func JobName(nodeName, machineConfig string) string {
+	hash := sha256.Sum256([]byte(nodeName + "/" + machineConfig))
+	return jobNamePrefix + hex.EncodeToString(hash[:])[:nameHashLength]
+}
+
+// NewJob returns the job running the latency probe on the node
+func NewJob(profile *performancev2.PerformanceProfile, probe *performancev2.LatencyProbe, nodeName, machineConfig, namespace string) (*batchv1.Job, error) {
+	script, err := assets.Scripts.ReadFile(fmt.Sprintf("scripts/%s.sh", scriptName))
+	if err != nil {
+		return nil, err
+	}
+
+	// requests and limits are equal, so the pod gets the guaranteed QoS and exclusive isolated CPUs
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewQuantity(int64(*probe.CPUs), resource.DecimalSI),
+		corev1.ResourceMemory: resource.MustParse(defaultMemory),
+	}
+	runtimeClass := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: batchv1.SchemeGroupVersion.String(),
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      JobName(nodeName, machineConfig),
+			Namespace: namespace,
+			Labels: map[string]string{
+				LabelProfile: profile.Name,
+			},
+			Annotations: map[string]string{
+				AnnotationNode:          nodeName,
+				AnnotationMachineConfig: machineConfig,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            pointer.Int32(jobBackoffLimit),
+			TTLSecondsAfterFinished: pointer.Int32(jobTTLAfterFinish),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						LabelProfile: profile.Name,
+					},
+					Annotations: map[string]string{
+						"cpu-load-balancing.crio.io": "disable",
+						"cpu-quota.crio.io":          "disable",
+						"irq-load-balancing.crio.io": "disable",
+					},
+				},
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{
+						corev1.LabelHostname: nodeName,
+					},
+					Tolerations: []corev1.Toleration{
+						{Operator: corev1.TolerationOpExists},
+					},
+					RuntimeClassName:   &runtimeClass,
+					ServiceAccountName: serviceAccount,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    containerName,
+							Image:   probe.Image,
+							Command: []string{"/bin/bash", "-c", string(script)},
+							Env: []corev1.EnvVar{
+								{Name: environmentTool, Value: string(probe.Tool)},
+								{Name: environmentDuration, Value: strconv.Itoa(int(probe.Duration.Seconds())) + "s"},
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: resources,
+								Limits:   resources,
+							},
+							SecurityContext: &corev1.SecurityContext{
+								Privileged: pointer.Bool(true),
+							},
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// IsFinished returns true when the job completed or failed
+func IsFinished(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// GetResult returns the maximum latency reported by the probe pod, or the reason the probe failed
+func GetResult(pod *corev1.Pod) (*int64, string) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != containerName || status.State.Terminated == nil {
+			continue
+		}
+
+		terminated := status.State.Terminated
+		message := strings.TrimSpace(terminated.Message)
+		if terminated.ExitCode != 0 {
+			if message == "" {
+				message = terminated.Reason
+			}
+			return nil, fmt.Sprintf("latency probe failed with exit code %d: %s", terminated.ExitCode, message)
+		}
+
+		maxLatency, err := strconv.ParseInt(message, 10, 64)
+		if err != nil {
+			return nil, fmt.Sprintf("failed to parse the latency probe result %q: %v", message, err)
+		}
+		return &maxLatency, ""
+	}
+	return nil, "latency probe pod did not report a result"
+}
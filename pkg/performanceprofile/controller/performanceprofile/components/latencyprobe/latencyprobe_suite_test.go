@@ -0,0 +1,13 @@
+package latencyprobe
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestLatencyProbe(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Latency Probe Suite")
+}
@@ -0,0 +1,110 @@
+package latencyprobe
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	testutils "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/utils/testing"
+)
+
+const (
+	testNode          = "worker-0"
+	testMachineConfig = "rendered-worker-cnf-0123456789"
+	testNamespace     = "openshift-cluster-node-tuning-operator"
+)
+
+var _ = Describe("Latency probe", func() {
+	var profile *performancev2.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+	})
+
+	It("should not return a probe without the annotation", func() {
+		probe, err := Get(profile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(probe).To(BeNil())
+	})
+
+	It("should apply the probe defaults", func() {
+		profile.Annotations = map[string]string{
+			performancev2.PerformanceProfileLatencyProbeAnnotation: `{"image": "quay.io/example/latency-tools:latest"}`,
+		}
+		probe, err := Get(profile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(probe.Tool).To(Equal(performancev2.LatencyProbeToolCyclictest))
+		Expect(probe.Duration.Duration).To(Equal(time.Minute))
+		Expect(*probe.CPUs).To(BeEquivalentTo(2))
+	})
+
+	It("should name the job after the node and the machine config", func() {
+		name := JobName(testNode, testMachineConfig)
+		Expect(name).To(HavePrefix(jobNamePrefix))
+		Expect(name).To(Equal(JobName(testNode, testMachineConfig)))
+		Expect(name).ToNot(Equal(JobName(testNode, "rendered-worker-cnf-9876543210")))
+	})
+
+	It("should create a guaranteed job pinned to the node", func() {
+		profile.Annotations = map[string]string{
+			performancev2.PerformanceProfileLatencyProbeAnnotation: `{"image": "quay.io/example/latency-tools:latest", "tool": "oslat", "duration": "30s", "cpus": 4}`,
+		}
+		probe, err := Get(profile)
+		Expect(err).ToNot(HaveOccurred())
+
+		job, err := NewJob(profile, probe, testNode, testMachineConfig, testNamespace)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Namespace).To(Equal(testNamespace))
+		Expect(job.Annotations).To(HaveKeyWithValue(AnnotationMachineConfig, testMachineConfig))
+
+		podSpec := job.Spec.Template.Spec
+		Expect(podSpec.NodeSelector).To(HaveKeyWithValue(corev1.LabelHostname, testNode))
+		Expect(*podSpec.RuntimeClassName).To(Equal("performance-test"))
+
+		container := podSpec.Containers[0]
+		Expect(container.Resources.Requests).To(Equal(container.Resources.Limits))
+		Expect(container.Resources.Limits.Cpu().Equal(resource.MustParse("4"))).To(BeTrue())
+		Expect(container.Env).To(ContainElements(
+			corev1.EnvVar{Name: environmentTool, Value: "oslat"},
+			corev1.EnvVar{Name: environmentDuration, Value: "30s"},
+		))
+	})
+
+	Context("when reading the probe result", func() {
+		newPod := func(terminated *corev1.ContainerStateTerminated) *corev1.Pod {
+			return &corev1.Pod{
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name:  containerName,
+							State: corev1.ContainerState{Terminated: terminated},
+						},
+					},
+				},
+			}
+		}
+
+		It("should return the maximum latency", func() {
+			maxLatency, message := GetResult(newPod(&corev1.ContainerStateTerminated{Message: "17"}))
+			Expect(message).To(BeEmpty())
+			Expect(*maxLatency).To(BeEquivalentTo(17))
+		})
+
+		It("should report the failure reason", func() {
+			maxLatency, message := GetResult(newPod(&corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}))
+			Expect(maxLatency).To(BeNil())
+			Expect(message).To(ContainSubstring("exit code 1: Error"))
+		})
+
+		It("should report a missing result", func() {
+			maxLatency, message := GetResult(newPod(nil))
+			Expect(maxLatency).To(BeNil())
+			Expect(message).ToNot(BeEmpty())
+		})
+	})
+})
@@ -8,6 +8,8 @@ import (
 	"io"
 	"io/fs"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"text/template"
 
 	assets "github.com/openshift/cluster-node-tuning-operator/assets/performanceprofile"
@@ -21,6 +23,7 @@ import (
 
 	apiconfigv1 "github.com/openshift/api/config/v1"
 	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
 	profilecomponent "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/profile"
 	profileutil "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/profile"
@@ -50,9 +53,10 @@ const (
 	sysctlTemplateRPSMask = "RPSMask"
 
 	// Workload partitioning configs
-	kubernetesConfDir      = "/etc/kubernetes"
-	crioPartitioningConfig = "99-workload-pinning.conf"
-	ocpPartitioningConfig  = "openshift-workload-pinning"
+	kubernetesConfDir        = "/etc/kubernetes"
+	crioPartitioningConfig   = "99-workload-pinning.conf"
+	ocpPartitioningConfig    = "openshift-workload-pinning"
+	crioOperandPinningConfig = "99-node-tuning-pinning.conf"
 
 	// MixedCPUs config
 	mixedCPUsConfig = "openshift-workload-mixed-cpus"
@@ -61,11 +65,21 @@ const (
 
 	udevRulesDir         = "/etc/udev/rules.d"
 	udevPhysicalRpsRules = "99-netdev-physical-rps.rules"
+	udevPacketSteering   = "99-netdev-packet-steering.rules"
+
+	modprobeConfDir     = "/etc/modprobe.d"
+	kernelModulesConfig = "99-performance-kernel-modules.conf"
+
+	driverctlConfDir    = "/etc/driverctl.d"
+	modulesLoadConfDir  = "/etc/modules-load.d"
+	deviceDriversConfig = "99-performance-device-drivers.conf"
 	// scripts
 	hugepagesAllocation       = "hugepages-allocation"
 	setCPUsOffline            = "set-cpus-offline"
 	setRPSMask                = "set-rps-mask"
 	clearIRQBalanceBannedCPUs = "clear-irqbalance-banned-cpus"
+	setSMTSiblingsOffline     = "set-smt-siblings-offline"
+	setPacketSteering         = "set-packet-steering"
 
 	ovsSliceName                     = "ovs.slice"
 	ovsDynamicPinningTriggerFile     = "ovs-enable-dynamic-cpu-affinity"
@@ -77,14 +91,20 @@ const (
 const (
 	systemdSectionUnit     = "Unit"
 	systemdSectionService  = "Service"
+	systemdSectionSlice    = "Slice"
 	systemdSectionInstall  = "Install"
 	systemdDescription     = "Description"
 	systemdBefore          = "Before"
+	systemdAfter           = "After"
 	systemdEnvironment     = "Environment"
 	systemdType            = "Type"
 	systemdRemainAfterExit = "RemainAfterExit"
 	systemdExecStart       = "ExecStart"
 	systemdWantedBy        = "WantedBy"
+	systemdCPUAccounting   = "CPUAccounting"
+	systemdCPUWeight       = "CPUWeight"
+	systemdAllowedCPUs     = "AllowedCPUs"
+	systemdCPUAffinity     = "CPUAffinity"
 )
 
 const (
@@ -95,6 +115,10 @@ const (
 	systemdTargetMultiUser     = "multi-user.target"
 	systemdTargetNetworkOnline = "network-online.target"
 	systemdTrue                = "true"
+	systemdFalse               = "false"
+	systemdSystemDir           = "/etc/systemd/system"
+	systemdSliceDropIn         = "99-performance-profile.conf"
+	systemdTimeSyncDropIn      = "99-performance-time-sync.conf"
 )
 
 const (
@@ -102,24 +126,33 @@ const (
 	environmentHugepagesCount = "HUGEPAGES_COUNT"
 	environmentNUMANode       = "NUMA_NODE"
 	environmentOfflineCpus    = "OFFLINE_CPUS"
+	environmentIsolatedCpus   = "ISOLATED_CPUS"
+	environmentIRQBannedCPUs  = "IRQBALANCE_BANNED_CPUS_MASK"
 )
 
 const (
-	templateReservedCpus             = "ReservedCpus"
-	templateSharedCpus               = "SharedCpus"
-	templateContainersLimit          = "ContainersLimit"
-	templateOvsSliceName             = "OvsSliceName"
-	templateOvsSliceDefinitionFile   = "ovs.slice"
-	templateOvsSliceUsageFile        = "01-use-ovs-slice.conf"
-	templateWorkload                 = "Workload"
-	templateRuntimePath              = "RuntimePath"
-	templateRuntimeRoot              = "RuntimeRoot"
-	templateCrioSharedCPUsAnnotation = "CrioSharedCPUsAnnotation"
+	templateReservedCpus           = "ReservedCpus"
+	templateSharedCpus             = "SharedCpus"
+	templateContainersLimit        = "ContainersLimit"
+	templateOvsSliceName           = "OvsSliceName"
+	templateOvsSliceDefinitionFile = "ovs.slice"
+	templateOvsSliceUsageFile      = "01-use-ovs-slice.conf"
+	templateWorkload               = "Workload"
+	templateRuntimePath            = "RuntimePath"
+	templateRuntimeRoot            = "RuntimeRoot"
+	templateAllowedAnnotations     = "AllowedAnnotations"
+	templateActivationAnnotation   = "ActivationAnnotation"
+	templateAdditionalRuntimes     = "AdditionalRuntimes"
 )
 
 // New returns new machine configuration object for performance sensitive workloads
 func New(profile *performancev2.PerformanceProfile, opts *components.MachineConfigOptions) (*machineconfigv1.MachineConfig, error) {
 	name := GetMachineConfigName(profile)
+	labels, err := getMachineConfigLabels(profile)
+	if err != nil {
+		return nil, err
+	}
+
 	mc := &machineconfigv1.MachineConfig{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: machineconfigv1.GroupVersion.String(),
@@ -127,7 +160,7 @@ func New(profile *performancev2.PerformanceProfile, opts *components.MachineConf
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   name,
-			Labels: profilecomponent.GetMachineConfigLabel(profile),
+			Labels: labels,
 		},
 		Spec: machineconfigv1.MachineConfigSpec{},
 	}
@@ -158,10 +191,41 @@ func New(profile *performancev2.PerformanceProfile, opts *components.MachineConf
 
 // GetMachineConfigName generates machine config name from the performance profile
 func GetMachineConfigName(profile *performancev2.PerformanceProfile) string {
+	if suffix := profilecomponent.GetMachineConfigNameSuffix(profile); suffix != "" {
+		return fmt.Sprintf("50-%s", suffix)
+	}
+
 	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
 	return fmt.Sprintf("50-%s", name)
 }
 
+// getMachineConfigLabels merges the extra labels from the profile annotation with the
+// machine config label, the latter wins so the MCP selection can not be broken
+func getMachineConfigLabels(profile *performancev2.PerformanceProfile) (map[string]string, error) {
+	extraLabels, err := profilecomponent.GetMachineConfigExtraLabels(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]string, len(extraLabels))
+	for k, v := range extraLabels {
+		labels[k] = v
+	}
+
+	for k, v := range profilecomponent.GetMachineConfigLabel(profile) {
+		labels[k] = v
+	}
+
+	return labels, nil
+}
+
+// HasRPSConfig checks if the RPS handling is generated for the profile, it is not added
+// when the realtime is explicitly disabled by the workload hint
+func HasRPSConfig(profile *performancev2.PerformanceProfile) bool {
+	return profileutil.IsRpsEnabled(profile) || profile.Spec.WorkloadHints == nil ||
+		profile.Spec.WorkloadHints.RealTime == nil || *profile.Spec.WorkloadHints.RealTime
+}
+
 func getIgnitionConfig(profile *performancev2.PerformanceProfile, opts *components.MachineConfigOptions) (*igntypes.Config, error) {
 	var scripts []string
 	ignitionConfig := &igntypes.Config{
@@ -173,13 +237,23 @@ func getIgnitionConfig(profile *performancev2.PerformanceProfile, opts *componen
 		},
 	}
 
+	rpsConfig := HasRPSConfig(profile) && !profileutil.IsGeneratedArtifactDisabled(profile, performancev2.GeneratedArtifactRPSConfig)
+	irqBalanceConfig := !profileutil.IsGeneratedArtifactDisabled(profile, performancev2.GeneratedArtifactIRQBalanceConfig)
+
 	// add script files under the node /usr/local/bin directory
-	if profileutil.IsRpsEnabled(profile) || profile.Spec.WorkloadHints == nil ||
-		profile.Spec.WorkloadHints.RealTime == nil || *profile.Spec.WorkloadHints.RealTime {
-		scripts = []string{hugepagesAllocation, setRPSMask, setCPUsOffline, clearIRQBalanceBannedCPUs}
-	} else {
-		// realtime is explicitly disabled by workload hint
-		scripts = []string{hugepagesAllocation, setCPUsOffline, clearIRQBalanceBannedCPUs}
+	scripts = []string{hugepagesAllocation}
+	if rpsConfig {
+		scripts = append(scripts, setRPSMask)
+	}
+	scripts = append(scripts, setCPUsOffline)
+	if irqBalanceConfig {
+		scripts = append(scripts, clearIRQBalanceBannedCPUs)
+	}
+	if profile.Spec.CPU.SMTPolicy != nil && *profile.Spec.CPU.SMTPolicy == performancev2.SMTPolicyDisableIsolatedOnly {
+		scripts = append(scripts, setSMTSiblingsOffline)
+	}
+	if hasPacketSteering(profile) {
+		scripts = append(scripts, setPacketSteering)
 	}
 	mode := 0700
 	for _, script := range scripts {
@@ -200,9 +274,7 @@ func getIgnitionConfig(profile *performancev2.PerformanceProfile, opts *componen
 	crioConfSnippetDst := filepath.Join(crioConfd, crioRuntimesConfig)
 	addContent(ignitionConfig, crioConfigSnippetContent, crioConfSnippetDst, &crioConfdRuntimesMode)
 
-	// do not add RPS handling when realtime is explicitly disabled by workload hint
-	if profileutil.IsRpsEnabled(profile) || profile.Spec.WorkloadHints == nil ||
-		profile.Spec.WorkloadHints.RealTime == nil || *profile.Spec.WorkloadHints.RealTime {
+	if rpsConfig {
 		// configure default rps mask applied to all network devices
 		sysctlConfContent, err := renderSysctlConf(profile, filepath.Join("configs", defaultRPSMaskConfig))
 		if err != nil {
@@ -237,10 +309,19 @@ func getIgnitionConfig(profile *performancev2.PerformanceProfile, opts *componen
 		}
 	}
 
+	// limit the packet steering of the matching devices, once the default RPS mask was applied
+	if hasPacketSteering(profile) {
+		if err := addPacketSteering(ignitionConfig, profile); err != nil {
+			return nil, err
+		}
+	}
+
 	if profile.Spec.HugePages != nil {
-		for _, page := range profile.Spec.HugePages.Pages {
-			// we already allocated non NUMA specific hugepages via kernel arguments
-			if page.Node == nil {
+		for i := range profile.Spec.HugePages.Pages {
+			page := &profile.Spec.HugePages.Pages[i]
+			// we already allocated non NUMA specific hugepages via kernel arguments,
+			// and the operand allocates the runtime hugepages
+			if page.Node == nil || profilecomponent.IsRuntimeAllocated(page) {
 				continue
 			}
 
@@ -249,11 +330,16 @@ func getIgnitionConfig(profile *performancev2.PerformanceProfile, opts *componen
 				return nil, err
 			}
 
-			hugepagesService, err := getSystemdContent(getHugepagesAllocationUnitOptions(
+			count, err := profilecomponent.GetBootHugepagesCount(profile, page)
+			if err != nil {
+				return nil, err
+			}
+
+			hugepagesService, err := getSystemdContent(withUnitOrdering(profile, performancev2.TuningUnitHugepagesAllocation, getHugepagesAllocationUnitOptions(
 				hugepagesSize,
-				page.Count,
+				count,
 				*page.Node,
-			))
+			)))
 			if err != nil {
 				return nil, err
 			}
@@ -283,10 +369,18 @@ func getIgnitionConfig(profile *performancev2.PerformanceProfile, opts *componen
 			}
 			ocpPartitionDst := filepath.Join(kubernetesConfDir, ocpPartitioningConfig)
 			addContent(ignitionConfig, ocpPartitionFileData, ocpPartitionDst, &crioConfdRuntimesMode)
+		} else if !profilecomponent.IsOperandPinningDisabled(profile) {
+			// Without workload partitioning, restrict the tuned operand pods to the reserved CPUs on their own
+			crioOperandPinningFileData, err := renderOperandCPUPinningConfig(profile.Spec.CPU)
+			if err != nil {
+				return nil, err
+			}
+			crioOperandPinningDst := filepath.Join(crioConfd, crioOperandPinningConfig)
+			addContent(ignitionConfig, crioOperandPinningFileData, crioOperandPinningDst, &crioConfdRuntimesMode)
 		}
 
 		// Support for cpu balancing configuration on RHEL 9 with cgroupv1
-		cpusetConfigureService, err := getSystemdContent(getCpusetConfigureServiceOptions())
+		cpusetConfigureService, err := getSystemdContent(withUnitOrdering(profile, performancev2.TuningUnitCPUSetConfigure, getCpusetConfigureServiceOptions()))
 		if err != nil {
 			return nil, err
 		}
@@ -311,7 +405,7 @@ func getIgnitionConfig(profile *performancev2.PerformanceProfile, opts *componen
 			return nil, err
 		}
 		offlinedCPUSstring := components.ListToString(offlinedCPUSList.List())
-		offlineCPUsService, err := getSystemdContent(getOfflineCPUs(offlinedCPUSstring))
+		offlineCPUsService, err := getSystemdContent(withUnitOrdering(profile, performancev2.TuningUnitSetCPUsOffline, getOfflineCPUs(offlinedCPUSstring)))
 		if err != nil {
 			return nil, err
 		}
@@ -323,16 +417,46 @@ func getIgnitionConfig(profile *performancev2.PerformanceProfile, opts *componen
 		})
 	}
 
-	clearIRQBalanceBannedCPUsService, err := getSystemdContent(getIRQBalanceBannedCPUsOptions())
-	if err != nil {
-		return nil, err
+	if profile.Spec.CPU.SMTPolicy != nil && *profile.Spec.CPU.SMTPolicy == performancev2.SMTPolicyDisableIsolatedOnly {
+		isolatedCPUs, err := cpuset.Parse(string(*profile.Spec.CPU.Isolated))
+		if err != nil {
+			return nil, err
+		}
+		smtSiblingsOfflineService, err := getSystemdContent(withUnitOrdering(profile, performancev2.TuningUnitSetSMTSiblingsOffline, getSMTSiblingsOffline(components.ListToString(isolatedCPUs.List()))))
+		if err != nil {
+			return nil, err
+		}
+
+		ignitionConfig.Systemd.Units = append(ignitionConfig.Systemd.Units, igntypes.Unit{
+			Contents: &smtSiblingsOfflineService,
+			Enabled:  pointer.Bool(true),
+			Name:     getSystemdService(setSMTSiblingsOffline),
+		})
 	}
 
-	ignitionConfig.Systemd.Units = append(ignitionConfig.Systemd.Units, igntypes.Unit{
-		Contents: &clearIRQBalanceBannedCPUsService,
-		Enabled:  pointer.Bool(true),
-		Name:     getSystemdService(clearIRQBalanceBannedCPUs),
-	})
+	if irqBalanceConfig {
+		irqBannedCPUs, err := profilecomponent.GetIRQBannedReservedCPUs(profile)
+		if err != nil {
+			return nil, err
+		}
+		var irqBannedCPUsMask string
+		if !irqBannedCPUs.IsEmpty() {
+			irqBannedCPUsMask, err = components.CPUListToMaskList(irqBannedCPUs.String())
+			if err != nil {
+				return nil, err
+			}
+		}
+		clearIRQBalanceBannedCPUsService, err := getSystemdContent(withUnitOrdering(profile, performancev2.TuningUnitClearIRQBalanceBannedCPUs, getIRQBalanceBannedCPUsOptions(irqBannedCPUsMask)))
+		if err != nil {
+			return nil, err
+		}
+
+		ignitionConfig.Systemd.Units = append(ignitionConfig.Systemd.Units, igntypes.Unit{
+			Contents: &clearIRQBalanceBannedCPUsService,
+			Enabled:  pointer.Bool(true),
+			Name:     getSystemdService(clearIRQBalanceBannedCPUs),
+		})
+	}
 
 	if ok, ovsSliceName := MoveOvsIntoOwnSlice(); ok {
 		// Create the OVS slice that will lift the cpu restrictions for better kernel networking performance
@@ -374,6 +498,54 @@ func getIgnitionConfig(profile *performancev2.PerformanceProfile, opts *componen
 		}
 		addContent(ignitionConfig, content, filepath.Join(kubernetesConfDir, mixedCPUsConfig), pointer.Int(0644))
 	}
+
+	if profile.Spec.Systemd != nil {
+		for i := range profile.Spec.Systemd.Slices {
+			slice := &profile.Spec.Systemd.Slices[i]
+			options := getSliceUnitOptions(slice)
+			if len(options) == 0 {
+				continue
+			}
+			content, err := getSystemdContent(options)
+			if err != nil {
+				return nil, err
+			}
+			dst := filepath.Join(systemdSystemDir, slice.Name+".d", systemdSliceDropIn)
+			addContent(ignitionConfig, []byte(content), dst, pointer.Int(0644))
+		}
+	}
+
+	// the services managed by another operator are left to it, the drop-ins removed from the profile are
+	// removed from the nodes along with the MachineConfig files
+	for _, service := range profilecomponent.GetPinnedTimeSyncServices(profile) {
+		content, err := getSystemdContent(getTimeSyncUnitOptions(profile.Spec.TimeSync.CPUs))
+		if err != nil {
+			return nil, err
+		}
+		dst := filepath.Join(systemdSystemDir, getSystemdService(string(service))+".d", systemdTimeSyncDropIn)
+		addContent(ignitionConfig, []byte(content), dst, pointer.Int(0644))
+	}
+
+	if len(profile.Spec.KernelModules) > 0 {
+		content := renderKernelModulesConfig(profile.Spec.KernelModules)
+		addContent(ignitionConfig, content, filepath.Join(modprobeConfDir, kernelModulesConfig), pointer.Int(0644))
+	}
+
+	// the driverctl udev rule binds the devices to the override drivers at boot, the overrides
+	// of the bindings removed from the profile are removed along with the MachineConfig files
+	if profile.Spec.Devices != nil && len(profile.Spec.Devices.DriverBindings) > 0 {
+		for _, binding := range profile.Spec.Devices.DriverBindings {
+			dst := filepath.Join(driverctlConfDir, "pci-"+strings.ToLower(binding.PCIAddress))
+			addContent(ignitionConfig, []byte(string(binding.Driver)+"\n"), dst, pointer.Int(0644))
+		}
+		content := renderDeviceDriversConfig(profile.Spec.Devices.DriverBindings)
+		addContent(ignitionConfig, content, filepath.Join(modulesLoadConfDir, deviceDriversConfig), pointer.Int(0644))
+	}
+
+	// the rules removed from the profile are removed from the nodes along with the MachineConfig files
+	for _, rule := range profile.Spec.UdevRules {
+		addContent(ignitionConfig, renderUdevRules(rule.Rules), filepath.Join(udevRulesDir, rule.Name+".rules"), pointer.Int(0644))
+	}
 	return ignitionConfig, nil
 }
 
@@ -399,6 +571,26 @@ func getSystemdService(serviceName string) string {
 	return fmt.Sprintf("%s.service", serviceName)
 }
 
+// withUnitOrdering returns the options of the tuning unit 'tuningUnit' with the additional
+// Before= and After= directives the profile defines for the unit
+func withUnitOrdering(profile *performancev2.PerformanceProfile, tuningUnit performancev2.TuningUnit, options []*unit.UnitOption) []*unit.UnitOption {
+	if profile.Spec.Systemd == nil {
+		return options
+	}
+	for _, ordering := range profile.Spec.Systemd.Units {
+		if ordering.Unit != tuningUnit {
+			continue
+		}
+		for _, name := range ordering.Before {
+			options = append(options, unit.NewUnitOption(systemdSectionUnit, systemdBefore, name))
+		}
+		for _, name := range ordering.After {
+			options = append(options, unit.NewUnitOption(systemdSectionUnit, systemdAfter, name))
+		}
+	}
+	return options
+}
+
 func getSystemdContent(options []*unit.UnitOption) (string, error) {
 	outReader := unit.Serialize(options)
 	outBytes, err := io.ReadAll(outReader)
@@ -462,8 +654,8 @@ func getCpusetConfigureServiceOptions() []*unit.UnitOption {
 	}
 }
 
-func getIRQBalanceBannedCPUsOptions() []*unit.UnitOption {
-	return []*unit.UnitOption{
+func getIRQBalanceBannedCPUsOptions(bannedCPUsMask string) []*unit.UnitOption {
+	opts := []*unit.UnitOption{
 		// [Unit]
 		// Description
 		unit.NewUnitOption(systemdSectionUnit, systemdDescription, "Clear the IRQBalance Banned CPU mask early in the boot"),
@@ -471,6 +663,12 @@ func getIRQBalanceBannedCPUsOptions() []*unit.UnitOption {
 		unit.NewUnitOption(systemdSectionUnit, systemdBefore, systemdServiceKubelet),
 		unit.NewUnitOption(systemdSectionUnit, systemdBefore, systemdServiceIRQBalance),
 		// [Service]
+	}
+	// Environment
+	if bannedCPUsMask != "" {
+		opts = append(opts, unit.NewUnitOption(systemdSectionService, systemdEnvironment, getSystemdEnvironment(environmentIRQBannedCPUs, bannedCPUsMask)))
+	}
+	return append(opts,
 		// Type
 		unit.NewUnitOption(systemdSectionService, systemdType, systemdServiceTypeOneshot),
 		// RemainAfterExit
@@ -480,7 +678,7 @@ func getIRQBalanceBannedCPUsOptions() []*unit.UnitOption {
 		// [Install]
 		// WantedBy
 		unit.NewUnitOption(systemdSectionInstall, systemdWantedBy, systemdTargetMultiUser),
-	}
+	)
 }
 
 func getHugepagesAllocationUnitOptions(hugepagesSize string, hugepagesCount int32, numaNode int32) []*unit.UnitOption {
@@ -528,6 +726,57 @@ func getOfflineCPUs(offlineCpus string) []*unit.UnitOption {
 	}
 }
 
+func getSMTSiblingsOffline(isolatedCpus string) []*unit.UnitOption {
+	return []*unit.UnitOption{
+		// [Unit]
+		// Description
+		unit.NewUnitOption(systemdSectionUnit, systemdDescription, "Set offline the SMT siblings of the isolated cpus"),
+		// Before
+		unit.NewUnitOption(systemdSectionUnit, systemdBefore, systemdServiceKubelet),
+		// Environment
+		unit.NewUnitOption(systemdSectionService, systemdEnvironment, getSystemdEnvironment(environmentIsolatedCpus, isolatedCpus)),
+		// Type
+		unit.NewUnitOption(systemdSectionService, systemdType, systemdServiceTypeOneshot),
+		// RemainAfterExit
+		unit.NewUnitOption(systemdSectionService, systemdRemainAfterExit, systemdTrue),
+		// ExecStart
+		unit.NewUnitOption(systemdSectionService, systemdExecStart, getBashScriptPath(setSMTSiblingsOffline)),
+		// [Install]
+		// WantedBy
+		unit.NewUnitOption(systemdSectionInstall, systemdWantedBy, systemdTargetMultiUser),
+	}
+}
+
+func getSliceUnitOptions(slice *performancev2.SystemdSlice) []*unit.UnitOption {
+	var options []*unit.UnitOption
+	// [Slice]
+	if slice.CPUAccounting != nil {
+		value := systemdFalse
+		if *slice.CPUAccounting {
+			value = systemdTrue
+		}
+		// CPUAccounting
+		options = append(options, unit.NewUnitOption(systemdSectionSlice, systemdCPUAccounting, value))
+	}
+	if slice.CPUWeight != nil {
+		// CPUWeight
+		options = append(options, unit.NewUnitOption(systemdSectionSlice, systemdCPUWeight, strconv.Itoa(int(*slice.CPUWeight))))
+	}
+	if slice.AllowedCPUs != nil {
+		// AllowedCPUs
+		options = append(options, unit.NewUnitOption(systemdSectionSlice, systemdAllowedCPUs, string(*slice.AllowedCPUs)))
+	}
+	return options
+}
+
+func getTimeSyncUnitOptions(cpus *performancev2.CPUSet) []*unit.UnitOption {
+	return []*unit.UnitOption{
+		// [Service]
+		// CPUAffinity
+		unit.NewUnitOption(systemdSectionService, systemdCPUAffinity, string(*cpus)),
+	}
+}
+
 func getRPSUnitOptions(rpsMask string) []*unit.UnitOption {
 	cmd := fmt.Sprintf("%s %%I %s", getBashScriptPath(setRPSMask), rpsMask)
 	return []*unit.UnitOption{
@@ -542,6 +791,93 @@ func getRPSUnitOptions(rpsMask string) []*unit.UnitOption {
 	}
 }
 
+func hasPacketSteering(profile *performancev2.PerformanceProfile) bool {
+	return profile.Spec.Net != nil && len(profile.Spec.Net.PacketSteering) > 0
+}
+
+// addPacketSteering adds the udev rules and the systemd template units setting the packet steering masks
+// of the network devices matching the profile packet steering entries
+func addPacketSteering(ignitionConfig *igntypes.Config, profile *performancev2.PerformanceProfile) error {
+	var rules []string
+	for i := range profile.Spec.Net.PacketSteering {
+		steering := &profile.Spec.Net.PacketSteering[i]
+
+		cpus := profile.Spec.CPU.Reserved
+		if steering.CPUs != nil {
+			cpus = steering.CPUs
+		}
+		mask, err := components.CPUListToMaskList(string(*cpus))
+		if err != nil {
+			return err
+		}
+
+		rpsMask, xpsMask := mask, mask
+		if steering.RPS != nil && !*steering.RPS {
+			rpsMask = "-"
+		}
+		if steering.XPS != nil && !*steering.XPS {
+			xpsMask = "-"
+		}
+
+		serviceName := fmt.Sprintf("%s-%d@", setPacketSteering, i)
+		service, err := getSystemdContent(getPacketSteeringUnitOptions(rpsMask, xpsMask))
+		if err != nil {
+			return err
+		}
+		ignitionConfig.Systemd.Units = append(ignitionConfig.Systemd.Units, igntypes.Unit{
+			Contents: &service,
+			Name:     getSystemdService(serviceName),
+		})
+		rules = append(rules, getPacketSteeringUdevRule(&steering.Device, getSystemdService(serviceName)))
+	}
+
+	rulesMode := 0644
+	rulesContent := []byte(strings.Join(rules, "\n") + "\n")
+	addContent(ignitionConfig, rulesContent, filepath.Join(udevRulesDir, udevPacketSteering), &rulesMode)
+	return nil
+}
+
+// getPacketSteeringUdevRule returns the udev rule starting the service on the network devices matching the device,
+// SR-IOV devices are moved (renamed), hence the rule catches this event as well
+func getPacketSteeringUdevRule(device *performancev2.Device, service string) string {
+	matches := []string{`SUBSYSTEM=="net"`, `ACTION=="add|move"`, `ENV{DEVPATH}!="/devices/virtual/net/*"`}
+	if device.InterfaceName != nil {
+		if strings.HasPrefix(*device.InterfaceName, "!") {
+			matches = append(matches, fmt.Sprintf(`KERNEL!="%s"`, strings.TrimPrefix(*device.InterfaceName, "!")))
+		} else {
+			matches = append(matches, fmt.Sprintf(`KERNEL=="%s"`, *device.InterfaceName))
+		}
+	}
+	if device.VendorID != nil {
+		matches = append(matches, fmt.Sprintf(`ATTRS{vendor}=="%s"`, *device.VendorID))
+	}
+	if device.DeviceID != nil {
+		matches = append(matches, fmt.Sprintf(`ATTRS{device}=="%s"`, *device.DeviceID))
+	}
+	return strings.Join(append(matches,
+		`TAG+="systemd"`,
+		fmt.Sprintf(`PROGRAM="/bin/systemd-escape --path --template=%s $env{DEVPATH}"`, service),
+		`ENV{SYSTEMD_WANTS}="%c"`,
+	), ", ")
+}
+
+func getPacketSteeringUnitOptions(rpsMask string, xpsMask string) []*unit.UnitOption {
+	cmd := fmt.Sprintf("%s %%I %s %s", getBashScriptPath(setPacketSteering), rpsMask, xpsMask)
+	return []*unit.UnitOption{
+		// [Unit]
+		// Description
+		unit.NewUnitOption(systemdSectionUnit, systemdDescription, "Sets network devices packet steering masks"),
+		// [Unit]
+		// After, the physical devices RPS mask is reverted first when the device moves
+		unit.NewUnitOption(systemdSectionUnit, systemdAfter, getSystemdService("update-rps@%i")),
+		// [Service]
+		// Type
+		unit.NewUnitOption(systemdSectionService, systemdType, systemdServiceTypeOneshot),
+		// ExecStart
+		unit.NewUnitOption(systemdSectionService, systemdExecStart, cmd),
+	}
+}
+
 func addContent(ignitionConfig *igntypes.Config, content []byte, dst string, mode *int) {
 	contentBase64 := base64.StdEncoding.EncodeToString(content)
 	ignitionConfig.Storage.Files = append(ignitionConfig.Storage.Files, igntypes.File{
@@ -557,25 +893,56 @@ func addContent(ignitionConfig *igntypes.Config, content []byte, dst string, mod
 	})
 }
 
+// crioRuntimeHandler holds the template values of an additional CRI-O runtime handler
+type crioRuntimeHandler struct {
+	Name               string
+	RuntimePath        string
+	RuntimeRoot        string
+	AllowedAnnotations []string
+}
+
 func renderCrioConfigSnippet(profile *performancev2.PerformanceProfile, src string, opts *components.MachineConfigOptions) ([]byte, error) {
-	templateArgs := map[string]string{
-		templateRuntimePath: "/bin/runc",
-		templateRuntimeRoot: "/run/runc",
-	}
+	templateArgs := map[string]interface{}{}
 
 	if profile.Spec.CPU.Reserved != nil {
 		templateArgs[templateReservedCpus] = string(*profile.Spec.CPU.Reserved)
 	}
 
+	// the annotations allowed by the high-performance runtime handler
+	allowedAnnotations := []string{"cpu-load-balancing.crio.io", "cpu-quota.crio.io", "irq-load-balancing.crio.io", "cpu-c-states.crio.io", "cpu-freq-governor.crio.io"}
 	if opts.MixedCPUsEnabled {
 		templateArgs[templateSharedCpus] = string(*profile.Spec.CPU.Shared)
-		templateArgs[templateCrioSharedCPUsAnnotation] = "cpu-shared.crio.io"
+		allowedAnnotations = append(allowedAnnotations, "cpu-shared.crio.io")
 	}
 
+	runtimePath, runtimeRoot := "/bin/runc", "/run/runc"
 	if opts.DefaultRuntime == machineconfigv1.ContainerRuntimeDefaultRuntimeCrun {
-		templateArgs[templateRuntimePath] = "/usr/bin/crun"
-		templateArgs[templateRuntimeRoot] = "/run/crun"
+		runtimePath, runtimeRoot = "/usr/bin/crun", "/run/crun"
+	}
+	templateArgs[templateRuntimePath] = runtimePath
+	templateArgs[templateRuntimeRoot] = runtimeRoot
+
+	var handlers []crioRuntimeHandler
+	for _, runtime := range profile.Spec.Runtimes {
+		handler := crioRuntimeHandler{
+			Name:               runtime.Name,
+			RuntimePath:        runtimePath,
+			RuntimeRoot:        runtimeRoot,
+			AllowedAnnotations: allowedAnnotations,
+		}
+		if runtime.RuntimePath != nil {
+			handler.RuntimePath = *runtime.RuntimePath
+		}
+		if runtime.RuntimeRoot != nil {
+			handler.RuntimeRoot = *runtime.RuntimeRoot
+		}
+		if len(runtime.AllowedAnnotations) > 0 {
+			handler.AllowedAnnotations = runtime.AllowedAnnotations
+		}
+		handlers = append(handlers, handler)
 	}
+	templateArgs[templateAllowedAnnotations] = allowedAnnotations
+	templateArgs[templateAdditionalRuntimes] = handlers
 
 	profileTemplate, err := template.ParseFS(assets.Configs, src)
 	if err != nil {
@@ -626,6 +993,31 @@ func renderManagementCPUPinningConfig(cpuv2 *performancev2.CPU, src string) ([]b
 	return pinningConfigData.Bytes(), nil
 }
 
+// renderOperandCPUPinningConfig renders the CRI-O workload pinning the containers of the pods
+// annotated with the tuned operand pinning annotation to the reserved CPUs.
+// The template file is [./assets/performanceprofile/configs/99-node-tuning-pinning.conf].
+func renderOperandCPUPinningConfig(cpuv2 *performancev2.CPU) ([]byte, error) {
+	if cpuv2 == nil || cpuv2.Reserved == nil {
+		return nil, fmt.Errorf("reserved cpus are required, skipping generating file")
+	}
+	vars := map[string]string{
+		templateActivationAnnotation: tunedv1.TunedOperandPinningAnnotation,
+		templateReservedCpus:         string(*cpuv2.Reserved),
+	}
+
+	profileTemplate, err := template.ParseFS(assets.Configs, filepath.Join("configs", crioOperandPinningConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	pinningConfigData := &bytes.Buffer{}
+	if err := profileTemplate.Execute(pinningConfigData, vars); err != nil {
+		return nil, err
+	}
+
+	return pinningConfigData.Bytes(), nil
+}
+
 // BootstrapWorkloadPinningMC creates an initial state MachineConfig resource that establishes an empty CPU set for both
 // CRIO config and Kubelet config. The purpose is provide empty state initialization for both CRIO and Kubelet so that the nodes
 // always start and join the cluster in a Workload Pinning configuration.
@@ -741,3 +1133,39 @@ func renderMixedCPUsConfig(containersLimitValue string, src string) ([]byte, err
 	}
 	return mixedCpusConfig.Bytes(), nil
 }
+
+// renderKernelModulesConfig renders the modprobe options and blacklist directives of the kernel modules
+func renderKernelModulesConfig(modules []performancev2.KernelModule) []byte {
+	kernelModulesConfig := &bytes.Buffer{}
+	for _, module := range modules {
+		if len(module.Options) > 0 {
+			fmt.Fprintf(kernelModulesConfig, "options %s %s\n", module.Name, strings.Join(module.Options, " "))
+		}
+		if module.Blacklist != nil && *module.Blacklist {
+			fmt.Fprintf(kernelModulesConfig, "blacklist %s\n", module.Name)
+		}
+	}
+	return kernelModulesConfig.Bytes()
+}
+
+// renderDeviceDriversConfig renders the modules-load configuration loading the drivers the devices are bound to
+func renderDeviceDriversConfig(bindings []performancev2.DeviceDriverBinding) []byte {
+	deviceDriversConfig := &bytes.Buffer{}
+	drivers := map[performancev2.DeviceDriver]bool{}
+	for _, binding := range bindings {
+		if drivers[binding.Driver] {
+			continue
+		}
+		drivers[binding.Driver] = true
+		fmt.Fprintf(deviceDriversConfig, "%s\n", binding.Driver)
+	}
+	return deviceDriversConfig.Bytes()
+}
+
+// renderUdevRules renders the udev rules file content, terminated by a newline
+func renderUdevRules(rules string) []byte {
+	if strings.HasSuffix(rules, "\n") {
+		return []byte(rules)
+	}
+	return []byte(rules + "\n")
+}
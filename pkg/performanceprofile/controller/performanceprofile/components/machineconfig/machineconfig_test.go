@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
 
 	"k8s.io/utils/pointer"
@@ -79,6 +80,24 @@ const clearBannedCPUs = `
         name: clear-irqbalance-banned-cpus.service
 `
 
+const smtSiblingsOffline = `
+      - contents: |
+          [Unit]
+          Description=Set offline the SMT siblings of the isolated cpus
+          Before=kubelet.service
+
+          [Service]
+          Environment=ISOLATED_CPUS=4,5
+          Type=oneshot
+          RemainAfterExit=true
+          ExecStart=/usr/local/bin/set-smt-siblings-offline.sh
+
+          [Install]
+          WantedBy=multi-user.target
+        enabled: true
+        name: set-smt-siblings-offline.service
+`
+
 var CPUs = []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
 var CPUstring = "1,2,3,4,5,6,7,8,9"
 
@@ -94,6 +113,33 @@ var _ = Describe("Machine Config", func() {
 		})
 	})
 
+	Context("with machine config annotations", func() {
+		It("should use the name suffix and the extra labels", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileMachineConfigNameSuffixAnnotation: "du-performance",
+				performancev2.PerformanceProfileMachineConfigLabelsAnnotation: fmt.Sprintf(`{"foo": "bar", %q: "other"}`,
+					testutils.MachineConfigLabelKey),
+			}
+
+			mc, err := New(profile, &components.MachineConfigOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mc.Name).To(Equal("50-du-performance"))
+			Expect(mc.Labels).To(HaveKeyWithValue("foo", "bar"))
+			Expect(mc.Labels).To(HaveKeyWithValue(testutils.MachineConfigLabelKey, testutils.MachineConfigLabelValue))
+		})
+
+		It("should fail on malformed extra labels", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileMachineConfigLabelsAnnotation: "foo=bar",
+			}
+
+			_, err := New(profile, &components.MachineConfigOptions{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Context("with hugepages with specified NUMA node and offlinedCPUs", func() {
 		var manifest string
 
@@ -132,6 +178,28 @@ var _ = Describe("Machine Config", func() {
 		})
 	})
 
+	Context("with hugepages allocated at runtime on the specified NUMA node", func() {
+		It("should not add systemd unit to allocate hugepages", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			runtimeAllocation := performancev2.HugePageAllocationRuntime
+			profile.Spec.HugePages.Pages = []performancev2.HugePage{
+				{
+					Size:       components.HugepagesSize2M,
+					Count:      128,
+					Node:       pointer.Int32(0),
+					Allocation: &runtimeAllocation,
+				},
+			}
+
+			mc, err := New(profile, &components.MachineConfigOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			y, err := yaml.Marshal(mc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(y)).ToNot(ContainSubstring("hugepages-allocation-2048kB-NUMA0.service"))
+		})
+	})
+
 	Context("check listToString ", func() {
 		It("should create string from CPUSet", func() {
 			res := components.ListToString(CPUs)
@@ -141,7 +209,7 @@ var _ = Describe("Machine Config", func() {
 
 	Context("check systemd units", func() {
 		It("should generate clear-banned-cpus unit", func() {
-			unit, err := getSystemdContent(getIRQBalanceBannedCPUsOptions())
+			unit, err := getSystemdContent(getIRQBalanceBannedCPUsOptions(""))
 			Expect(err).ToNot(HaveOccurred())
 			expected := `[Unit]
 Description=Clear the IRQBalance Banned CPU mask early in the boot
@@ -158,6 +226,355 @@ WantedBy=multi-user.target
 `
 			Expect(unit).To(Equal(expected))
 		})
+
+		It("should generate clear-banned-cpus unit with the banned CPUs mask", func() {
+			unit, err := getSystemdContent(getIRQBalanceBannedCPUsOptions("0000000c"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(unit).To(ContainSubstring("Environment=IRQBALANCE_BANNED_CPUS_MASK=0000000c\n"))
+		})
+	})
+
+	Context("with IRQ serving CPUs", func() {
+		It("should ban the reserved CPUs not serving IRQs", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			irqServing := performancev2.CPUSet("0-1")
+			profile.Spec.CPU.IRQServing = &irqServing
+
+			mc, err := New(profile, &components.MachineConfigOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			y, err := yaml.Marshal(mc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(y)).To(ContainSubstring("Environment=IRQBALANCE_BANNED_CPUS_MASK=0000000c"))
+		})
+	})
+})
+
+var _ = Describe("SMT policy", func() {
+	It("should not set offline the SMT siblings by default", func() {
+		profile := testutils.NewPerformanceProfile("test")
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).ToNot(ContainSubstring(setSMTSiblingsOffline))
+	})
+
+	It("should set offline the SMT siblings of the isolated CPUs", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		smtPolicy := performancev2.SMTPolicyDisableIsolatedOnly
+		profile.Spec.CPU.SMTPolicy = &smtPolicy
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).To(ContainSubstring(smtSiblingsOffline))
+		Expect(string(y)).To(ContainSubstring("path: /usr/local/bin/set-smt-siblings-offline.sh"))
+	})
+})
+
+var _ = Describe("Disabled generated artifacts", func() {
+	It("should not add the irqbalance and the RPS configuration when disabled", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		profile.Spec.DisableGenerated = []performancev2.GeneratedArtifact{
+			performancev2.GeneratedArtifactIRQBalanceConfig,
+			performancev2.GeneratedArtifactRPSConfig,
+		}
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).ToNot(ContainSubstring(clearIRQBalanceBannedCPUs))
+		Expect(string(y)).ToNot(ContainSubstring(setRPSMask))
+		Expect(string(y)).ToNot(ContainSubstring(defaultRPSMaskConfig))
+		Expect(string(y)).ToNot(ContainSubstring(udevPhysicalRpsRules))
+		Expect(string(y)).To(ContainSubstring(setCPUsOffline))
+	})
+})
+
+var _ = Describe("Tuning units ordering", func() {
+	It("should add the ordering of the tuning units", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		profile.Spec.HugePages.Pages[0].Node = pointer.Int32(0)
+		profile.Spec.Systemd = &performancev2.Systemd{
+			Units: []performancev2.SystemdUnitOrdering{
+				{Unit: performancev2.TuningUnitHugepagesAllocation, Before: []string{"ovs-vswitchd.service"}},
+				{Unit: performancev2.TuningUnitSetCPUsOffline, After: []string{"vendor-driver.service"}},
+			},
+		}
+
+		ignitionConfig, err := getIgnitionConfig(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		units := map[string]string{}
+		for _, u := range ignitionConfig.Systemd.Units {
+			if u.Contents != nil {
+				units[u.Name] = *u.Contents
+			}
+		}
+		hugepagesUnit := units[getSystemdService(fmt.Sprintf("%s-%skB-NUMA0", hugepagesAllocation, "1048576"))]
+		Expect(hugepagesUnit).To(ContainSubstring("Before=kubelet.service\nBefore=ovs-vswitchd.service"))
+		Expect(units[getSystemdService(setCPUsOffline)]).To(ContainSubstring("After=vendor-driver.service"))
+		Expect(units[getSystemdService(clearIRQBalanceBannedCPUs)]).ToNot(ContainSubstring("vendor-driver.service"))
+	})
+})
+
+var _ = Describe("Kernel modules", func() {
+	It("should not add the modprobe configuration by default", func() {
+		profile := testutils.NewPerformanceProfile("test")
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).ToNot(ContainSubstring(kernelModulesConfig))
+	})
+
+	It("should add the modprobe configuration of the kernel modules", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		profile.Spec.KernelModules = []performancev2.KernelModule{
+			{Name: "vfio-pci", Options: []string{"ids=8086:154c,8086:10ed", "disable_idle_d3=1"}},
+			{Name: "nvme", Options: []string{"poll_queues=4"}},
+			{Name: "sctp", Blacklist: pointer.Bool(true)},
+		}
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).To(ContainSubstring("path: /etc/modprobe.d/99-performance-kernel-modules.conf"))
+
+		Expect(string(renderKernelModulesConfig(profile.Spec.KernelModules))).To(Equal(
+			"options vfio-pci ids=8086:154c,8086:10ed disable_idle_d3=1\n" +
+				"options nvme poll_queues=4\n" +
+				"blacklist sctp\n"))
+	})
+
+	It("should add the udev rules of the profile", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		profile.Spec.UdevRules = []performancev2.UdevRule{
+			{Name: "70-nic-names", Rules: `SUBSYSTEM=="net", ACTION=="add", ATTR{address}=="b4:96:91:aa:bb:cc", NAME="fh0"`},
+		}
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).To(ContainSubstring("path: /etc/udev/rules.d/70-nic-names.rules"))
+
+		Expect(string(renderUdevRules(profile.Spec.UdevRules[0].Rules))).To(HaveSuffix("NAME=\"fh0\"\n"))
+	})
+
+	It("should not add the device driver bindings by default", func() {
+		profile := testutils.NewPerformanceProfile("test")
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).ToNot(ContainSubstring(driverctlConfDir))
+		Expect(string(y)).ToNot(ContainSubstring(deviceDriversConfig))
+	})
+
+	It("should add the driverctl overrides of the device driver bindings", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		profile.Spec.Devices = &performancev2.Devices{
+			DriverBindings: []performancev2.DeviceDriverBinding{
+				{PCIAddress: "0000:3B:00.0", Driver: performancev2.DeviceDriverVFIOPCI},
+				{PCIAddress: "0000:3b:00.1", Driver: performancev2.DeviceDriverVFIOPCI},
+				{PCIAddress: "0000:af:00.0", Driver: performancev2.DeviceDriverIgbUIO},
+			},
+		}
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).To(ContainSubstring("path: /etc/driverctl.d/pci-0000:3b:00.0"))
+		Expect(string(y)).To(ContainSubstring("path: /etc/driverctl.d/pci-0000:3b:00.1"))
+		Expect(string(y)).To(ContainSubstring("path: /etc/driverctl.d/pci-0000:af:00.0"))
+		Expect(string(y)).To(ContainSubstring("path: /etc/modules-load.d/99-performance-device-drivers.conf"))
+
+		Expect(string(renderDeviceDriversConfig(profile.Spec.Devices.DriverBindings))).To(Equal("vfio-pci\nigb_uio\n"))
+	})
+
+	It("should not add the packet steering configuration by default", func() {
+		profile := testutils.NewPerformanceProfile("test")
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).ToNot(ContainSubstring(udevPacketSteering))
+		Expect(string(y)).ToNot(ContainSubstring(setPacketSteering))
+	})
+
+	It("should steer the packets of the matching devices to the configured CPUs", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		steeringCPUs := performancev2.CPUSet("4-5")
+		profile.Spec.Net = &performancev2.Net{
+			PacketSteering: []performancev2.PacketSteering{
+				{Device: performancev2.Device{InterfaceName: pointer.String("ens*")}},
+				{Device: performancev2.Device{VendorID: pointer.String("0x8086"), DeviceID: pointer.String("0x1593")}, CPUs: &steeringCPUs, XPS: pointer.Bool(false)},
+			},
+		}
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).To(ContainSubstring("path: /etc/udev/rules.d/99-netdev-packet-steering.rules"))
+		Expect(string(y)).To(ContainSubstring("path: /usr/local/bin/set-packet-steering.sh"))
+		Expect(string(y)).To(ContainSubstring("name: set-packet-steering-0@.service"))
+		Expect(string(y)).To(ContainSubstring("/usr/local/bin/set-packet-steering.sh %I 0000000f 0000000f"))
+		Expect(string(y)).To(ContainSubstring("/usr/local/bin/set-packet-steering.sh %I 00000030 -"))
+
+		Expect(getPacketSteeringUdevRule(&profile.Spec.Net.PacketSteering[1].Device, "set-packet-steering-1@.service")).To(Equal(
+			`SUBSYSTEM=="net", ACTION=="add|move", ENV{DEVPATH}!="/devices/virtual/net/*", ATTRS{vendor}=="0x8086", ATTRS{device}=="0x1593", ` +
+				`TAG+="systemd", PROGRAM="/bin/systemd-escape --path --template=set-packet-steering-1@.service $env{DEVPATH}", ENV{SYSTEMD_WANTS}="%c"`))
+		Expect(getPacketSteeringUdevRule(&performancev2.Device{InterfaceName: pointer.String("!eno1")}, "set-packet-steering-0@.service")).To(ContainSubstring(`KERNEL!="eno1"`))
+	})
+})
+
+var _ = Describe("Systemd slices", func() {
+	It("should add the drop-ins of the configured slices", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		allowedCPUs := performancev2.CPUSet("0-1")
+		profile.Spec.Systemd = &performancev2.Systemd{
+			Slices: []performancev2.SystemdSlice{
+				{Name: "system.slice", CPUAccounting: pointer.Bool(true), CPUWeight: pointer.Int32(50), AllowedCPUs: &allowedCPUs},
+				{Name: "ovs.slice", CPUWeight: pointer.Int32(300)},
+				{Name: "user.slice"},
+			},
+		}
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).To(ContainSubstring("path: /etc/systemd/system/system.slice.d/99-performance-profile.conf"))
+		Expect(string(y)).To(ContainSubstring("path: /etc/systemd/system/ovs.slice.d/99-performance-profile.conf"))
+		Expect(string(y)).ToNot(ContainSubstring("user.slice"))
+
+		content, err := getSystemdContent(getSliceUnitOptions(&profile.Spec.Systemd.Slices[0]))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal("[Slice]\nCPUAccounting=true\nCPUWeight=50\nAllowedCPUs=0-1\n"))
+	})
+})
+
+var _ = Describe("Time synchronization", func() {
+	var profile *performancev2.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		cpus := performancev2.CPUSet("1")
+		profile.Spec.TimeSync = &performancev2.TimeSync{CPUs: &cpus}
+	})
+
+	It("should pin the time synchronization services to the configured CPUs", func() {
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).To(ContainSubstring("path: /etc/systemd/system/ptp4l.service.d/99-performance-time-sync.conf"))
+		Expect(string(y)).To(ContainSubstring("path: /etc/systemd/system/phc2sys.service.d/99-performance-time-sync.conf"))
+		Expect(string(y)).To(ContainSubstring("path: /etc/systemd/system/chronyd.service.d/99-performance-time-sync.conf"))
+
+		content, err := getSystemdContent(getTimeSyncUnitOptions(profile.Spec.TimeSync.CPUs))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal("[Service]\nCPUAffinity=1\n"))
+	})
+
+	It("should leave the services managed by the PTP operator", func() {
+		profile.Annotations = map[string]string{
+			performancev2.PerformanceProfileTimeSyncExternalServicesAnnotation: "ptp4l, phc2sys",
+		}
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).ToNot(ContainSubstring("ptp4l.service.d"))
+		Expect(string(y)).ToNot(ContainSubstring("phc2sys.service.d"))
+		Expect(string(y)).To(ContainSubstring("path: /etc/systemd/system/chronyd.service.d/99-performance-time-sync.conf"))
+	})
+})
+
+var _ = Describe("Runtime handlers", func() {
+	It("should render the additional runtime handlers", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		profile.Spec.Runtimes = []performancev2.RuntimeHandler{
+			{Name: "high-performance-crun", RuntimePath: pointer.String("/usr/bin/crun"), RuntimeRoot: pointer.String("/run/crun")},
+			{Name: "low-latency", AllowedAnnotations: []string{"cpu-quota.crio.io"}},
+		}
+
+		content, err := renderCrioConfigSnippet(profile, filepath.Join("configs", crioRuntimesConfig), &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring(`allowed_annotations = ["cpu-load-balancing.crio.io", "cpu-quota.crio.io", "irq-load-balancing.crio.io", "cpu-c-states.crio.io", "cpu-freq-governor.crio.io"]
+`))
+		Expect(string(content)).To(HaveSuffix(`
+[crio.runtime.runtimes.high-performance-crun]
+runtime_path = "/usr/bin/crun"
+runtime_type = "oci"
+runtime_root = "/run/crun"
+allowed_annotations = ["cpu-load-balancing.crio.io", "cpu-quota.crio.io", "irq-load-balancing.crio.io", "cpu-c-states.crio.io", "cpu-freq-governor.crio.io"]
+
+[crio.runtime.runtimes.low-latency]
+runtime_path = "/bin/runc"
+runtime_type = "oci"
+runtime_root = "/run/runc"
+allowed_annotations = ["cpu-quota.crio.io"]
+`))
+	})
+})
+
+var _ = Describe("Operand pinning", func() {
+	operandPinningPath := "path: /etc/crio/crio.conf.d/99-node-tuning-pinning.conf"
+
+	It("should pin the operand pods to the reserved CPUs by default", func() {
+		profile := testutils.NewPerformanceProfile("test")
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).To(ContainSubstring(operandPinningPath))
+
+		content, err := renderOperandCPUPinningConfig(profile.Spec.CPU)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removeAllWhiteSpace(string(content))).To(Equal(removeAllWhiteSpace(`
+			[crio.runtime.workloads.node-tuning]
+			activation_annotation = "tuned.openshift.io/operand-pinning"
+			annotation_prefix = "resources.node-tuning.openshift.io"
+			resources = { "cpushares" = 0, "cpuset" = "0-3" }
+		`)))
+	})
+
+	It("should not pin the operand pods when opted out", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		profile.Annotations = map[string]string{
+			performancev2.PerformanceProfileDisableOperandPinningAnnotation: "true",
+		}
+
+		mc, err := New(profile, &components.MachineConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).ToNot(ContainSubstring(operandPinningPath))
+	})
+
+	It("should rely on the management workload with workload partitioning", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		allNodes := configv1.CPUPartitioningAllNodes
+
+		mc, err := New(profile, &components.MachineConfigOptions{PinningMode: &allNodes})
+		Expect(err).ToNot(HaveOccurred())
+		y, err := yaml.Marshal(mc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).To(ContainSubstring("path: /etc/crio/crio.conf.d/99-workload-pinning.conf"))
+		Expect(string(y)).ToNot(ContainSubstring(operandPinningPath))
 	})
 })
 
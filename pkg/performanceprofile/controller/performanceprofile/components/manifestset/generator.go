@@ -0,0 +1,135 @@
+package manifestset
+
+import (
+	"fmt"
+	"sync"
+
+	apiconfigv1 "github.com/openshift/api/config/v1"
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/kubeletconfig"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/machineconfig"
+	profilecomponent "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/profile"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/runtimeclass"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/tuned"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/node"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the names of the built-in generators
+const (
+	GeneratorMachineConfig = "machineconfig"
+	GeneratorKubeletConfig = "kubeletconfig"
+	GeneratorTuned         = "tuned"
+	GeneratorRuntimeClass  = "runtimeclass"
+	GeneratorNodeConfig    = "nodeconfig"
+)
+
+// Generator generates artifacts of a performance profile. Downstream distributions register their own generators,
+// for example for vendor-specific custom resources, with RegisterGenerator from the init function of a package the
+// operator binary imports. The controller creates and updates the objects of the registered generators along the
+// built-in ones, and sets the profile as their controller owner, so the garbage collector deletes them with the profile.
+type Generator interface {
+	// Name returns the name identifying the generator in the registry
+	Name() string
+	// Generate returns the objects of the profile, with their kind set, or none when the profile does not need any;
+	// the render command writes the objects to files named after the profile and the kind, one object per kind
+	Generate(profile *performancev2.PerformanceProfile, opts *components.Options) ([]client.Object, error)
+}
+
+// GenerateFunc generates the objects of a performance profile
+type GenerateFunc func(profile *performancev2.PerformanceProfile, opts *components.Options) ([]client.Object, error)
+
+type generator struct {
+	name     string
+	generate GenerateFunc
+}
+
+func (g *generator) Name() string {
+	return g.name
+}
+
+func (g *generator) Generate(profile *performancev2.PerformanceProfile, opts *components.Options) ([]client.Object, error) {
+	return g.generate(profile, opts)
+}
+
+// NewGenerator returns the generator named 'name' generating the objects of a performance profile with 'generate'
+func NewGenerator(name string, generate GenerateFunc) Generator {
+	return &generator{name: name, generate: generate}
+}
+
+var registry struct {
+	sync.RWMutex
+	generators []Generator
+}
+
+// RegisterGenerator adds the generator 'g' to the registry, after the generators already registered.
+// It panics if a generator with the same name is already registered.
+func RegisterGenerator(g Generator) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, registered := range registry.generators {
+		if registered.Name() == g.Name() {
+			panic(fmt.Sprintf("generator %q is already registered", g.Name()))
+		}
+	}
+	registry.generators = append(registry.generators, g)
+}
+
+// Generators returns the registered generators in their registration order, the built-in ones first
+func Generators() []Generator {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return append([]Generator{}, registry.generators...)
+}
+
+func init() {
+	RegisterGenerator(NewGenerator(GeneratorMachineConfig, func(profile *performancev2.PerformanceProfile, opts *components.Options) ([]client.Object, error) {
+		mc, err := machineconfig.New(profile, &opts.MachineConfig)
+		if err != nil {
+			return nil, err
+		}
+		return []client.Object{mc}, nil
+	}))
+
+	RegisterGenerator(NewGenerator(GeneratorKubeletConfig, func(profile *performancev2.PerformanceProfile, opts *components.Options) ([]client.Object, error) {
+		kc, err := kubeletconfig.New(profile,
+			&components.KubeletConfigOptions{
+				MachineConfigPoolSelector: profilecomponent.GetMachineConfigPoolSelector(profile, opts.ProfileMCP),
+				MixedCPUsEnabled:          opts.MachineConfig.MixedCPUsEnabled,
+				DisabledFeatures:          opts.DisabledFeatures,
+			})
+		if err != nil {
+			return nil, err
+		}
+		return []client.Object{kc}, nil
+	}))
+
+	RegisterGenerator(NewGenerator(GeneratorTuned, func(profile *performancev2.PerformanceProfile, opts *components.Options) ([]client.Object, error) {
+		performanceTuned, err := tuned.NewNodePerformance(profile)
+		if err != nil {
+			return nil, err
+		}
+		return []client.Object{performanceTuned}, nil
+	}))
+
+	RegisterGenerator(NewGenerator(GeneratorRuntimeClass, func(profile *performancev2.PerformanceProfile, opts *components.Options) ([]client.Object, error) {
+		// the runtime class is not generated when the profile lets it be managed externally
+		if profilecomponent.IsGeneratedArtifactDisabled(profile, performancev2.GeneratedArtifactRuntimeClass) {
+			return nil, nil
+		}
+		return []client.Object{runtimeclass.New(profile, machineconfig.HighPerformanceRuntime)}, nil
+	}))
+
+	RegisterGenerator(NewGenerator(GeneratorNodeConfig, func(profile *performancev2.PerformanceProfile, opts *components.Options) ([]client.Object, error) {
+		cgroupMode := apiconfigv1.CgroupModeV1
+		if profilecomponent.IsCgroupPartitionIsolationEnabled(profile) {
+			// the cpuset partitions are a cgroup v2 feature
+			cgroupMode = apiconfigv1.CgroupModeV2
+		}
+		return []client.Object{node.NewNodeConfig(cgroupMode)}, nil
+	}))
+}
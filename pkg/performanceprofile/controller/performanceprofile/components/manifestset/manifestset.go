@@ -1,6 +1,8 @@
 package manifestset
 
 import (
+	"fmt"
+
 	apiconfigv1 "github.com/openshift/api/config/v1"
 	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
 	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
@@ -8,13 +10,11 @@ import (
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/kubeletconfig"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/machineconfig"
 	profilecomponent "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/profile"
-	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/runtimeclass"
-	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/tuned"
-	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/node"
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 
 	nodev1 "k8s.io/api/node/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // ManifestResultSet contains all component's instances that should be created according to performance-profile
@@ -24,6 +24,8 @@ type ManifestResultSet struct {
 	NodeConfig    *apiconfigv1.Node
 	Tuned         *tunedv1.Tuned
 	RuntimeClass  *nodev1.RuntimeClass
+	// Extra are the objects of the generators registered on top of the built-in ones
+	Extra []client.Object
 }
 
 // ManifestTable is map with Kind name as key and component's instance as value
@@ -37,9 +39,15 @@ func (ms *ManifestResultSet) ToObjects() []metav1.Object {
 		ms.MachineConfig.GetObjectMeta(),
 		ms.KubeletConfig.GetObjectMeta(),
 		ms.Tuned.GetObjectMeta(),
-		ms.RuntimeClass.GetObjectMeta(),
-		ms.NodeConfig.GetObjectMeta(),
 	)
+	// the runtime class is not generated when the profile lets it be managed externally
+	if ms.RuntimeClass != nil {
+		objs = append(objs, ms.RuntimeClass.GetObjectMeta())
+	}
+	objs = append(objs, ms.NodeConfig.GetObjectMeta())
+	for _, obj := range ms.Extra {
+		objs = append(objs, obj)
+	}
 	return objs
 }
 
@@ -49,43 +57,89 @@ func (ms *ManifestResultSet) ToManifestTable() ManifestTable {
 	manifests[ms.MachineConfig.Kind] = ms.MachineConfig
 	manifests[ms.KubeletConfig.Kind] = ms.KubeletConfig
 	manifests[ms.Tuned.Kind] = ms.Tuned
-	manifests[ms.RuntimeClass.Kind] = ms.RuntimeClass
+	if ms.RuntimeClass != nil {
+		manifests[ms.RuntimeClass.Kind] = ms.RuntimeClass
+	}
 	manifests[ms.NodeConfig.Kind] = ms.NodeConfig
+	for _, obj := range ms.Extra {
+		manifests[obj.GetObjectKind().GroupVersionKind().Kind] = obj
+	}
 	return manifests
 }
 
-// GetNewComponents return a list of all component's instances that should be created according to profile
+// GetNewComponents return a list of all component's instances that should be created according to profile,
+// generated by the registered generators
 func GetNewComponents(profile *performancev2.PerformanceProfile, opts *components.Options) (*ManifestResultSet, error) {
-	machineConfigPoolSelector := profilecomponent.GetMachineConfigPoolSelector(profile, opts.ProfileMCP)
+	manifestResultSet := ManifestResultSet{}
+	for _, g := range Generators() {
+		objs, err := g.Generate(profile, opts)
+		if err != nil {
+			return nil, fmt.Errorf("generator %q failed: %w", g.Name(), err)
+		}
 
-	mc, err := machineconfig.New(profile, &opts.MachineConfig)
-	if err != nil {
-		return nil, err
+		for _, obj := range objs {
+			switch o := obj.(type) {
+			case *mcov1.MachineConfig:
+				if manifestResultSet.MachineConfig == nil {
+					manifestResultSet.MachineConfig = o
+					continue
+				}
+			case *mcov1.KubeletConfig:
+				if manifestResultSet.KubeletConfig == nil {
+					manifestResultSet.KubeletConfig = o
+					continue
+				}
+			case *tunedv1.Tuned:
+				if manifestResultSet.Tuned == nil {
+					manifestResultSet.Tuned = o
+					continue
+				}
+			case *nodev1.RuntimeClass:
+				if manifestResultSet.RuntimeClass == nil {
+					manifestResultSet.RuntimeClass = o
+					continue
+				}
+			case *apiconfigv1.Node:
+				if manifestResultSet.NodeConfig == nil {
+					manifestResultSet.NodeConfig = o
+					continue
+				}
+			}
+			manifestResultSet.Extra = append(manifestResultSet.Extra, obj)
+		}
 	}
+	return &manifestResultSet, nil
+}
 
-	kc, err := kubeletconfig.New(profile,
-		&components.KubeletConfigOptions{
-			MachineConfigPoolSelector: machineConfigPoolSelector,
-			MixedCPUsEnabled:          opts.MachineConfig.MixedCPUsEnabled,
-		})
+// GetSkippedFeatures returns the features requested by the profile that the components skip
+// because the cluster feature gates disable them
+func GetSkippedFeatures(profile *performancev2.PerformanceProfile, disabledFeatures []components.ProfileFeature) ([]components.ProfileFeature, error) {
+	requested, err := kubeletconfig.GetRequestedFeatures(profile)
 	if err != nil {
 		return nil, err
 	}
-
-	performanceTuned, err := tuned.NewNodePerformance(profile)
-	if err != nil {
-		return nil, err
+	if profilecomponent.IsMixedCPUsEnabled(profile) {
+		requested = append(requested, components.ProfileFeatureMixedCPUs)
 	}
 
-	nodeConfig := node.NewNodeConfig(apiconfigv1.CgroupModeV1)
-	runtimeClass := runtimeclass.New(profile, machineconfig.HighPerformanceRuntime)
+	var skipped []components.ProfileFeature
+	for _, feature := range requested {
+		if components.IsFeatureDisabled(disabledFeatures, feature) {
+			skipped = append(skipped, feature)
+		}
+	}
+	return skipped, nil
+}
 
-	manifestResultSet := ManifestResultSet{
-		MachineConfig: mc,
-		KubeletConfig: kc,
-		Tuned:         performanceTuned,
-		RuntimeClass:  runtimeClass,
-		NodeConfig:    nodeConfig,
+// GetSkippedArtifacts returns the artifacts the components would generate for the profile
+// that are skipped because the profile lets them be managed externally
+func GetSkippedArtifacts(profile *performancev2.PerformanceProfile) []string {
+	var skipped []string
+	for _, artifact := range profile.Spec.DisableGenerated {
+		if artifact == performancev2.GeneratedArtifactRPSConfig && !machineconfig.HasRPSConfig(profile) {
+			continue
+		}
+		skipped = append(skipped, string(artifact))
 	}
-	return &manifestResultSet, nil
+	return skipped
 }
@@ -0,0 +1,101 @@
+package poolresources
+
+import (
+	"encoding/json"
+	"fmt"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+)
+
+const (
+	// LabelMachineConfigPool is the label holding the name of the machine config pool the resources belong to
+	LabelMachineConfigPool = "performance.openshift.io/pool-resources"
+	// LabelProfile is the label holding the name of the performance profile the resources are derived from
+	LabelProfile = "performance.openshift.io/pool-resources-profile"
+
+	// KeyProfile holds the name of the performance profile
+	KeyProfile = "performanceProfile"
+	// KeyReservedCPUs holds the CPUs reserved for the system, the shared CPUs included
+	KeyReservedCPUs = "reservedCPUs"
+	// KeyIsolatedCPUs holds the CPUs available to the guaranteed workloads
+	KeyIsolatedCPUs = "isolatedCPUs"
+	// KeySharedCPUs holds the CPUs shared by the workloads requesting the shared CPUs
+	KeySharedCPUs = "sharedCPUs"
+	// KeyTopologyManagerPolicy holds the kubelet topology manager policy
+	KeyTopologyManagerPolicy = "topologyManagerPolicy"
+	// KeySystemReservedMemory holds the memory reserved for the system daemons
+	KeySystemReservedMemory = "systemReservedMemory"
+	// KeyKubeReservedMemory holds the memory reserved for the kubernetes daemons
+	KeyKubeReservedMemory = "kubeReservedMemory"
+	// KeyEvictionHardMemory holds the memory available threshold of the hard eviction
+	KeyEvictionHardMemory = "evictionHardMemory"
+	// KeyReservedMemory holds the JSON list of the memory reserved per NUMA node by the memory manager
+	KeyReservedMemory = "reservedMemory"
+
+	namePrefix = "pool-resources"
+)
+
+// Name returns the name of the config map publishing the resources of the machine config pool 'poolName'
+func Name(poolName string) string {
+	return components.GetComponentName(poolName, namePrefix)
+}
+
+// New returns the config map publishing the CPUs and the memory reservations the profile configures on the nodes
+// of the machine config pool 'poolName', the reservations are read from the kubelet config generated for the profile
+func New(profile *performancev2.PerformanceProfile, poolName string, kc *mcov1.KubeletConfig, namespace string) (*corev1.ConfigMap, error) {
+	kubeletConfig := &kubeletconfigv1beta1.KubeletConfiguration{}
+	if kc.Spec.KubeletConfig != nil {
+		if err := json.Unmarshal(kc.Spec.KubeletConfig.Raw, kubeletConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse the kubelet configuration of %q: %w", kc.Name, err)
+		}
+	}
+
+	data := map[string]string{
+		KeyProfile:               profile.Name,
+		KeyReservedCPUs:          kubeletConfig.ReservedSystemCPUs,
+		KeyTopologyManagerPolicy: kubeletConfig.TopologyManagerPolicy,
+		KeySystemReservedMemory:  kubeletConfig.SystemReserved[string(corev1.ResourceMemory)],
+		KeyKubeReservedMemory:    kubeletConfig.KubeReserved[string(corev1.ResourceMemory)],
+		KeyEvictionHardMemory:    kubeletConfig.EvictionHard["memory.available"],
+	}
+	if profile.Spec.CPU != nil && profile.Spec.CPU.Isolated != nil {
+		data[KeyIsolatedCPUs] = string(*profile.Spec.CPU.Isolated)
+	}
+	if profile.Spec.CPU != nil && profile.Spec.CPU.Shared != nil {
+		data[KeySharedCPUs] = string(*profile.Spec.CPU.Shared)
+	}
+	if len(kubeletConfig.ReservedMemory) > 0 {
+		reservedMemory, err := json.Marshal(kubeletConfig.ReservedMemory)
+		if err != nil {
+			return nil, err
+		}
+		data[KeyReservedMemory] = string(reservedMemory)
+	}
+	for key, value := range data {
+		if value == "" {
+			delete(data, key)
+		}
+	}
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(poolName),
+			Namespace: namespace,
+			Labels: map[string]string{
+				LabelMachineConfigPool: poolName,
+				LabelProfile:           profile.Name,
+			},
+		},
+		Data: data,
+	}, nil
+}
@@ -0,0 +1,13 @@
+package poolresources
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPoolResources(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Pool Resources Suite")
+}
@@ -0,0 +1,60 @@
+package poolresources
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/kubeletconfig"
+	testutils "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/utils/testing"
+)
+
+const (
+	testPool      = "worker-cnf"
+	testNamespace = "openshift-cluster-node-tuning-operator"
+)
+
+var _ = Describe("Pool resources", func() {
+	It("should publish the CPUs and the memory reservations of the profile", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		kc, err := kubeletconfig.New(profile, &components.KubeletConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		cm, err := New(profile, testPool, kc, testNamespace)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cm.Name).To(Equal("pool-resources-worker-cnf"))
+		Expect(cm.Labels).To(HaveKeyWithValue(LabelMachineConfigPool, testPool))
+		Expect(cm.Labels).To(HaveKeyWithValue(LabelProfile, "test"))
+
+		Expect(cm.Data).To(HaveKeyWithValue(KeyProfile, "test"))
+		Expect(cm.Data).To(HaveKeyWithValue(KeyReservedCPUs, "0-3"))
+		Expect(cm.Data).To(HaveKeyWithValue(KeyIsolatedCPUs, "4-5"))
+		Expect(cm.Data).To(HaveKeyWithValue(KeySharedCPUs, "8-9"))
+		Expect(cm.Data).To(HaveKeyWithValue(KeyTopologyManagerPolicy, testutils.SingleNUMAPolicy))
+		Expect(cm.Data).To(HaveKeyWithValue(KeySystemReservedMemory, "500Mi"))
+		Expect(cm.Data).To(HaveKeyWithValue(KeyKubeReservedMemory, "500Mi"))
+		Expect(cm.Data).To(HaveKeyWithValue(KeyEvictionHardMemory, "100Mi"))
+
+		var reservedMemory []kubeletconfigv1beta1.MemoryReservation
+		Expect(json.Unmarshal([]byte(cm.Data[KeyReservedMemory]), &reservedMemory)).To(Succeed())
+		Expect(reservedMemory).To(HaveLen(1))
+		Expect(reservedMemory[0].Limits.Memory().String()).To(Equal("1100Mi"))
+	})
+
+	It("should not publish the missing values", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		profile.Spec.CPU.Shared = nil
+		profile.Spec.NUMA = nil
+		kc, err := kubeletconfig.New(profile, &components.KubeletConfigOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		cm, err := New(profile, testPool, kc, testNamespace)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cm.Data).ToNot(HaveKey(KeySharedCPUs))
+		Expect(cm.Data).ToNot(HaveKey(KeyReservedMemory))
+	})
+})
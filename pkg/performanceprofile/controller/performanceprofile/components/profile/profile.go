@@ -1,10 +1,24 @@
 package profile
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
 	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
 
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/cpuset"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	defaultAutoRollbackDegradedTunedProfiles = 1
+	defaultCanaryRolloutTimeout              = 30 * time.Minute
 )
 
 // GetMachineConfigPoolSelector returns the MachineConfigPoolSelector from the CR or a default value calculated based on NodeSelector
@@ -31,6 +45,79 @@ func GetMachineConfigLabel(profile *performancev2.PerformanceProfile) map[string
 	return getDefaultLabel(profile)
 }
 
+// GetMachineConfigNameSuffix returns the MachineConfig name suffix requested via the profile annotation,
+// or an empty string when the default naming should be used
+func GetMachineConfigNameSuffix(profile *performancev2.PerformanceProfile) string {
+	if profile.Annotations == nil {
+		return ""
+	}
+
+	return profile.Annotations[performancev2.PerformanceProfileMachineConfigNameSuffixAnnotation]
+}
+
+// GetMachineConfigExtraLabels returns the additional MachineConfig labels requested via the profile annotation
+func GetMachineConfigExtraLabels(profile *performancev2.PerformanceProfile) (map[string]string, error) {
+	if profile.Annotations == nil {
+		return nil, nil
+	}
+
+	rawLabels, ok := profile.Annotations[performancev2.PerformanceProfileMachineConfigLabelsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	labels := map[string]string{}
+	if err := json.Unmarshal([]byte(rawLabels), &labels); err != nil {
+		return nil, fmt.Errorf("failed to parse %q annotation: %w", performancev2.PerformanceProfileMachineConfigLabelsAnnotation, err)
+	}
+
+	return labels, nil
+}
+
+// GetAutoRollback returns the auto rollback settings requested via the profile annotation with the defaults applied,
+// it returns nil when the profile does not opt in the automatic rollback
+func GetAutoRollback(profile *performancev2.PerformanceProfile) (*performancev2.AutoRollback, error) {
+	rawRollback, ok := profile.Annotations[performancev2.PerformanceProfileAutoRollbackAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	rollback := &performancev2.AutoRollback{}
+	if err := json.Unmarshal([]byte(rawRollback), rollback); err != nil {
+		return nil, fmt.Errorf("failed to parse %q annotation: %w", performancev2.PerformanceProfileAutoRollbackAnnotation, err)
+	}
+
+	if rollback.DegradedTunedProfiles == nil {
+		rollback.DegradedTunedProfiles = pointer.Int32(defaultAutoRollbackDegradedTunedProfiles)
+	}
+	return rollback, nil
+}
+
+// GetCanaryRollout returns the canary rollout settings requested via the profile annotation with the defaults applied,
+// it returns nil when the profile does not opt in the canary rollout
+func GetCanaryRollout(profile *performancev2.PerformanceProfile) (*performancev2.CanaryRollout, error) {
+	rawCanary, ok := profile.Annotations[performancev2.PerformanceProfileCanaryRolloutAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	canary := &performancev2.CanaryRollout{}
+	if err := json.Unmarshal([]byte(rawCanary), canary); err != nil {
+		return nil, fmt.Errorf("failed to parse %q annotation: %w", performancev2.PerformanceProfileCanaryRolloutAnnotation, err)
+	}
+
+	if canary.MaxLatency != nil {
+		if _, ok := profile.Annotations[performancev2.PerformanceProfileLatencyProbeAnnotation]; !ok {
+			return nil, fmt.Errorf("the canary rollout maxLatency requires the %q annotation", performancev2.PerformanceProfileLatencyProbeAnnotation)
+		}
+	}
+
+	if canary.Timeout == nil {
+		canary.Timeout = &metav1.Duration{Duration: defaultCanaryRolloutTimeout}
+	}
+	return canary, nil
+}
+
 func getDefaultLabel(profile *performancev2.PerformanceProfile) map[string]string {
 	nodeSelectorKey, _ := components.GetFirstKeyAndValue(profile.Spec.NodeSelector)
 	// no error handling needed, it's validated already
@@ -97,6 +184,19 @@ func IsRpsEnabled(profile *performancev2.PerformanceProfile) bool {
 	return false
 }
 
+// IsOperandPinningDisabled checks if the tuned operand pods should be left unpinned
+func IsOperandPinningDisabled(profile *performancev2.PerformanceProfile) bool {
+	if profile.Annotations == nil {
+		return false
+	}
+	isDisabled, ok := profile.Annotations[performancev2.PerformanceProfileDisableOperandPinningAnnotation]
+	if ok && isDisabled == "true" {
+		return true
+	}
+
+	return false
+}
+
 func IsMixedCPUsEnabled(profile *performancev2.PerformanceProfile) bool {
 	if profile.Spec.CPU.Shared == nil || *profile.Spec.CPU.Shared == "" {
 		return false
@@ -106,3 +206,110 @@ func IsMixedCPUsEnabled(profile *performancev2.PerformanceProfile) bool {
 	}
 	return *profile.Spec.WorkloadHints.MixedCpus
 }
+
+// GetIRQBannedReservedCPUs returns the reserved CPUs that should not serve device interrupts,
+// an empty set is returned when the IRQ serving CPUs are not specified
+func GetIRQBannedReservedCPUs(profile *performancev2.PerformanceProfile) (cpuset.CPUSet, error) {
+	if profile.Spec.CPU == nil || profile.Spec.CPU.IRQServing == nil || profile.Spec.CPU.Reserved == nil {
+		return cpuset.New(), nil
+	}
+
+	reserved, err := cpuset.Parse(string(*profile.Spec.CPU.Reserved))
+	if err != nil {
+		return cpuset.New(), err
+	}
+
+	irqServing, err := cpuset.Parse(string(*profile.Spec.CPU.IRQServing))
+	if err != nil {
+		return cpuset.New(), err
+	}
+
+	return reserved.Difference(irqServing), nil
+}
+
+// IsRuntimeAllocated checks if the huge pages are allocated by the operand on the running node
+// instead of the kernel boot parameters or the allocation systemd units
+func IsRuntimeAllocated(page *performancev2.HugePage) bool {
+	return page.Allocation != nil && *page.Allocation == performancev2.HugePageAllocationRuntime
+}
+
+// GetBootHugepagesCount returns the count of the huge pages 'page' allocated at boot, the memory percentage
+// of the page is resolved against the node memory size the profile declares
+func GetBootHugepagesCount(profile *performancev2.PerformanceProfile, page *performancev2.HugePage) (int32, error) {
+	if page.MemoryPercent == nil {
+		return page.Count, nil
+	}
+
+	if profile.Spec.HugePages == nil || profile.Spec.HugePages.NodeMemorySize == nil {
+		return 0, fmt.Errorf("the profile %q does not declare the node memory size to resolve the %d%% of %s huge pages", profile.Name, *page.MemoryPercent, page.Size)
+	}
+
+	size, err := resource.ParseQuantity(string(page.Size) + "i")
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the huge pages size %q: %v", page.Size, err)
+	}
+	return int32(profile.Spec.HugePages.NodeMemorySize.Value() * int64(*page.MemoryPercent) / 100 / size.Value()), nil
+}
+
+// IsCgroupPartitionIsolationEnabled checks if the profile isolates the CPUs with a cgroup v2 cpuset partition
+// instead of the isolcpus kernel argument
+func IsCgroupPartitionIsolationEnabled(profile *performancev2.PerformanceProfile) bool {
+	return profile.Spec.CPU != nil && profile.Spec.CPU.IsolationMethod != nil && *profile.Spec.CPU.IsolationMethod == performancev2.CPUIsolationMethodCgroupPartition
+}
+
+// IsRealTimeHintEnabled checks if the profile tunes the nodes for the low latency real time workloads
+func IsRealTimeHintEnabled(profile *performancev2.PerformanceProfile) bool {
+	return profile.Spec.WorkloadHints == nil || profile.Spec.WorkloadHints.RealTime == nil || *profile.Spec.WorkloadHints.RealTime
+}
+
+// IsHighPowerConsumptionHintEnabled checks if the profile trades the power consumption for the latency
+func IsHighPowerConsumptionHintEnabled(profile *performancev2.PerformanceProfile) bool {
+	return profile.Spec.WorkloadHints != nil && profile.Spec.WorkloadHints.HighPowerConsumption != nil && *profile.Spec.WorkloadHints.HighPowerConsumption
+}
+
+// IsPerPodPowerManagementEnabled checks if the profile lets the pods opt in the power saving
+func IsPerPodPowerManagementEnabled(profile *performancev2.PerformanceProfile) bool {
+	return profile.Spec.WorkloadHints != nil && profile.Spec.WorkloadHints.PerPodPowerManagement != nil && *profile.Spec.WorkloadHints.PerPodPowerManagement
+}
+
+// IsGeneratedArtifactDisabled checks if the profile lets the artifact 'artifact' be managed externally
+func IsGeneratedArtifactDisabled(profile *performancev2.PerformanceProfile, artifact performancev2.GeneratedArtifact) bool {
+	for _, disabled := range profile.Spec.DisableGenerated {
+		if disabled == artifact {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPinnedTimeSyncServices returns the time synchronization services the operator pins to the time synchronization
+// CPUs, that is the services of the profile, ptp4l, phc2sys and chronyd by default, but the ones managed externally
+func GetPinnedTimeSyncServices(profile *performancev2.PerformanceProfile) []performancev2.TimeSyncService {
+	if profile.Spec.TimeSync == nil {
+		return nil
+	}
+
+	services := profile.Spec.TimeSync.Services
+	if len(services) == 0 {
+		services = []performancev2.TimeSyncService{
+			performancev2.TimeSyncServicePTP4L,
+			performancev2.TimeSyncServicePHC2SYS,
+			performancev2.TimeSyncServiceChronyd,
+		}
+	}
+
+	external := map[performancev2.TimeSyncService]bool{}
+	if value, ok := profile.Annotations[performancev2.PerformanceProfileTimeSyncExternalServicesAnnotation]; ok {
+		for _, service := range strings.Split(value, ",") {
+			external[performancev2.TimeSyncService(strings.TrimSpace(service))] = true
+		}
+	}
+
+	var pinned []performancev2.TimeSyncService
+	for _, service := range services {
+		if !external[service] {
+			pinned = append(pinned, service)
+		}
+	}
+	return pinned
+}
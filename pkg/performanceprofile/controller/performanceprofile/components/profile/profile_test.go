@@ -80,6 +80,29 @@ var _ = Describe("PerformanceProfile", func() {
 			performancev2.PerformanceProfileIgnoreCgroupsVersion: "true",
 		}, true),
 	)
+
+	DescribeTable("GetMachineConfigExtraLabels", func(anns map[string]string, exp map[string]string, expErr bool) {
+		profile := testutils.NewPerformanceProfile("test")
+		profile.Annotations = anns
+		got, err := GetMachineConfigExtraLabels(profile)
+		if expErr {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got).To(Equal(exp))
+	},
+		Entry("nil anns", nil, nil, false),
+		Entry("unrelated anns", map[string]string{
+			performancev2.PerformanceProfilePauseAnnotation: "true",
+		}, nil, false),
+		Entry("valid labels", map[string]string{
+			performancev2.PerformanceProfileMachineConfigLabelsAnnotation: `{"foo": "bar"}`,
+		}, map[string]string{"foo": "bar"}, false),
+		Entry("malformed labels", map[string]string{
+			performancev2.PerformanceProfileMachineConfigLabelsAnnotation: `foo=bar`,
+		}, nil, true),
+	)
 })
 
 func setValidNodeSelector(profile *performancev2.PerformanceProfile) {
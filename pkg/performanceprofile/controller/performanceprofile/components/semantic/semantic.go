@@ -0,0 +1,259 @@
+// Package semantic compares the generated machine configuration artifacts field by field,
+// ignoring the differences which do not change the configuration applied on the nodes,
+// like the ordering of the ignition files and units or the encoding of the files content.
+// Artifacts serialized by different operator versions are considered equal as long as
+// the MachineConfigOperator would render the same node configuration out of them.
+package semantic
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"github.com/vincent-petithory/dataurl"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// defaultFileMode is the mode ignition sets on the files without an explicit mode
+	defaultFileMode = 0644
+	// defaultKernelType is the kernel type the MCO uses when the kernel type is not set
+	defaultKernelType = "default"
+)
+
+// MachineConfigSpecEqual returns true when both specs result in the same node configuration.
+func MachineConfigSpecEqual(a, b *mcov1.MachineConfigSpec) (bool, error) {
+	normalizedA, err := normalizeMachineConfigSpec(a)
+	if err != nil {
+		return false, err
+	}
+	normalizedB, err := normalizeMachineConfigSpec(b)
+	if err != nil {
+		return false, err
+	}
+	return apiequality.Semantic.DeepEqual(normalizedA, normalizedB), nil
+}
+
+// MachineConfigSpecDiff returns the sorted items of the node configuration which differ between both
+// specs: the ignition files as "file <path>", the systemd units as "unit <name>", the remaining
+// ignition config as "ignition" and the other spec fields by their JSON name.
+func MachineConfigSpecDiff(a, b *mcov1.MachineConfigSpec) ([]string, error) {
+	normalizedA, err := normalizeMachineConfigSpec(a)
+	if err != nil {
+		return nil, err
+	}
+	normalizedB, err := normalizeMachineConfigSpec(b)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := []string{}
+	ignitionA, filesA, unitsA := splitIgnition(normalizedA.Ignition)
+	ignitionB, filesB, unitsB := splitIgnition(normalizedB.Ignition)
+	for _, path := range unionKeys(filesA, filesB) {
+		if !apiequality.Semantic.DeepEqual(filesA[path], filesB[path]) {
+			diff = append(diff, "file "+path)
+		}
+	}
+	for _, name := range unionKeys(unitsA, unitsB) {
+		if !apiequality.Semantic.DeepEqual(unitsA[name], unitsB[name]) {
+			diff = append(diff, "unit "+name)
+		}
+	}
+	if !apiequality.Semantic.DeepEqual(ignitionA, ignitionB) {
+		diff = append(diff, "ignition")
+	}
+
+	fieldsA, err := specFields(&normalizedA.Spec)
+	if err != nil {
+		return nil, err
+	}
+	fieldsB, err := specFields(&normalizedB.Spec)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range unionKeys(fieldsA, fieldsB) {
+		if !apiequality.Semantic.DeepEqual(fieldsA[field], fieldsB[field]) {
+			diff = append(diff, field)
+		}
+	}
+
+	sort.Strings(diff)
+	return diff, nil
+}
+
+// KubeletConfigSpecEqual returns true when both specs result in the same kubelet configuration.
+func KubeletConfigSpecEqual(a, b *mcov1.KubeletConfigSpec) (bool, error) {
+	normalizedA, err := normalizeKubeletConfigSpec(a)
+	if err != nil {
+		return false, err
+	}
+	normalizedB, err := normalizeKubeletConfigSpec(b)
+	if err != nil {
+		return false, err
+	}
+	return apiequality.Semantic.DeepEqual(normalizedA, normalizedB), nil
+}
+
+// the fields are exported, so the equality functions registered for the API types can be used on them
+type machineConfigSpec struct {
+	Spec     mcov1.MachineConfigSpec
+	Ignition *igntypes.Config
+}
+
+type kubeletConfigSpec struct {
+	Spec          mcov1.KubeletConfigSpec
+	KubeletConfig *kubeletconfigv1beta1.KubeletConfiguration
+}
+
+func normalizeMachineConfigSpec(in *mcov1.MachineConfigSpec) (*machineConfigSpec, error) {
+	out := &machineConfigSpec{
+		Spec: *in.DeepCopy(),
+	}
+
+	if len(out.Spec.Config.Raw) > 0 {
+		out.Ignition = &igntypes.Config{}
+		if err := json.Unmarshal(out.Spec.Config.Raw, out.Ignition); err != nil {
+			return nil, err
+		}
+		normalizeIgnition(out.Ignition)
+	}
+	out.Spec.Config.Raw = nil
+	out.Spec.Config.Object = nil
+
+	if out.Spec.KernelType == "" {
+		out.Spec.KernelType = defaultKernelType
+	}
+	// the MCO installs the extensions as a set
+	sort.Strings(out.Spec.Extensions)
+
+	return out, nil
+}
+
+func normalizeIgnition(cfg *igntypes.Config) {
+	// the config is converted to the latest spec version by the MCO anyway
+	cfg.Ignition.Version = ""
+
+	for i := range cfg.Storage.Files {
+		file := &cfg.Storage.Files[i]
+		normalizeResource(&file.Contents)
+		for j := range file.Append {
+			normalizeResource(&file.Append[j])
+		}
+		if file.Mode == nil {
+			file.Mode = pointer.Int(defaultFileMode)
+		}
+		if file.Overwrite != nil && !*file.Overwrite {
+			file.Overwrite = nil
+		}
+	}
+	sort.SliceStable(cfg.Storage.Files, func(i, j int) bool {
+		return cfg.Storage.Files[i].Path < cfg.Storage.Files[j].Path
+	})
+	sort.SliceStable(cfg.Storage.Directories, func(i, j int) bool {
+		return cfg.Storage.Directories[i].Path < cfg.Storage.Directories[j].Path
+	})
+	sort.SliceStable(cfg.Storage.Links, func(i, j int) bool {
+		return cfg.Storage.Links[i].Path < cfg.Storage.Links[j].Path
+	})
+
+	for i := range cfg.Systemd.Units {
+		dropins := cfg.Systemd.Units[i].Dropins
+		sort.SliceStable(dropins, func(i, j int) bool {
+			return dropins[i].Name < dropins[j].Name
+		})
+	}
+	sort.SliceStable(cfg.Systemd.Units, func(i, j int) bool {
+		return cfg.Systemd.Units[i].Name < cfg.Systemd.Units[j].Name
+	})
+}
+
+// splitIgnition returns the normalized ignition config without its files and units, along with
+// the files by path and the units by name
+func splitIgnition(cfg *igntypes.Config) (*igntypes.Config, map[string]igntypes.File, map[string]igntypes.Unit) {
+	files := map[string]igntypes.File{}
+	units := map[string]igntypes.Unit{}
+	if cfg == nil {
+		return nil, files, units
+	}
+
+	rest := *cfg
+	for _, file := range cfg.Storage.Files {
+		files[file.Path] = file
+	}
+	for _, unit := range cfg.Systemd.Units {
+		units[unit.Name] = unit
+	}
+	rest.Storage.Files = nil
+	rest.Systemd.Units = nil
+	return &rest, files, units
+}
+
+// specFields returns the fields of the normalized spec by their JSON name, the ignition config excluded
+func specFields(spec *mcov1.MachineConfigSpec) (map[string]interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "config")
+	return fields, nil
+}
+
+func unionKeys[T any](a, b map[string]T) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalizeResource re-encodes the data URL sources with base64, so sources carrying the same data
+// with different media types or encodings are equal.
+func normalizeResource(resource *igntypes.Resource) {
+	if resource.Source == nil {
+		return
+	}
+
+	data, err := dataurl.DecodeString(*resource.Source)
+	if err != nil {
+		// not a data URL, compare the source as it is
+		return
+	}
+	resource.Source = pointer.String("data:;base64," + base64.StdEncoding.EncodeToString(data.Data))
+}
+
+func normalizeKubeletConfigSpec(in *mcov1.KubeletConfigSpec) (*kubeletConfigSpec, error) {
+	out := &kubeletConfigSpec{
+		Spec: *in.DeepCopy(),
+	}
+
+	if out.Spec.KubeletConfig != nil && len(out.Spec.KubeletConfig.Raw) > 0 {
+		out.KubeletConfig = &kubeletconfigv1beta1.KubeletConfiguration{}
+		if err := json.Unmarshal(out.Spec.KubeletConfig.Raw, out.KubeletConfig); err != nil {
+			return nil, err
+		}
+
+		reservedMemory := out.KubeletConfig.ReservedMemory
+		sort.SliceStable(reservedMemory, func(i, j int) bool {
+			return reservedMemory[i].NumaNode < reservedMemory[j].NumaNode
+		})
+	}
+	out.Spec.KubeletConfig = nil
+
+	return out, nil
+}
@@ -0,0 +1,105 @@
+package semantic
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const testDataDir = "testdata"
+
+func loadMachineConfig(name string) *mcov1.MachineConfig {
+	data, err := os.ReadFile(filepath.Join(testDataDir, name))
+	Expect(err).ToNot(HaveOccurred())
+	mc := &mcov1.MachineConfig{}
+	Expect(yaml.Unmarshal(data, mc)).To(Succeed())
+	return mc
+}
+
+func loadKubeletConfig(name string) *mcov1.KubeletConfig {
+	data, err := os.ReadFile(filepath.Join(testDataDir, name))
+	Expect(err).ToNot(HaveOccurred())
+	kc := &mcov1.KubeletConfig{}
+	Expect(yaml.Unmarshal(data, kc)).To(Succeed())
+	return kc
+}
+
+var _ = Describe("Semantic comparison", func() {
+	// machineconfig.yaml and kubeletconfig.yaml are artifacts rendered by a previous operator version,
+	// the other fixtures are re-serializations or modifications of them
+	DescribeTable("of machine configs",
+		func(fixture string, expectedEqual bool) {
+			existing := loadMachineConfig("machineconfig.yaml")
+			desired := loadMachineConfig(fixture)
+
+			equal, err := MachineConfigSpecEqual(&existing.Spec, &desired.Spec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(equal).To(Equal(expectedEqual))
+
+			// the comparison is symmetric
+			equal, err = MachineConfigSpecEqual(&desired.Spec, &existing.Spec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(equal).To(Equal(expectedEqual))
+		},
+		Entry("identical", "machineconfig.yaml", true),
+		Entry("with reordered files and units", "machineconfig-reordered.yaml", true),
+		Entry("with re-encoded files content", "machineconfig-reencoded.yaml", true),
+		Entry("with explicit default values", "machineconfig-defaulted.yaml", true),
+		Entry("with a changed file content", "machineconfig-content-changed.yaml", false),
+		Entry("with a changed kernel type", "machineconfig-kernel-type-changed.yaml", false),
+	)
+
+	It("should not modify the compared machine configs", func() {
+		existing := loadMachineConfig("machineconfig.yaml")
+		desired := loadMachineConfig("machineconfig-reordered.yaml")
+		desiredCopy := desired.DeepCopy()
+
+		_, err := MachineConfigSpecEqual(&existing.Spec, &desired.Spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(desired).To(Equal(desiredCopy))
+	})
+
+	It("should fail on a malformed ignition config", func() {
+		existing := loadMachineConfig("machineconfig.yaml")
+		desired := existing.DeepCopy()
+		desired.Spec.Config.Raw = []byte("{")
+
+		_, err := MachineConfigSpecEqual(&existing.Spec, &desired.Spec)
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("listing the machine config differences",
+		func(fixture string, expectedDiff []string) {
+			existing := loadMachineConfig("machineconfig.yaml")
+			desired := loadMachineConfig(fixture)
+
+			diff, err := MachineConfigSpecDiff(&existing.Spec, &desired.Spec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff).To(Equal(expectedDiff))
+		},
+		Entry("identical", "machineconfig.yaml", []string{}),
+		Entry("with reordered files and units", "machineconfig-reordered.yaml", []string{}),
+		Entry("with re-encoded files content", "machineconfig-reencoded.yaml", []string{}),
+		Entry("with a changed file content", "machineconfig-content-changed.yaml", []string{"file /usr/local/bin/hugepages-allocation.sh"}),
+		Entry("with a changed kernel type", "machineconfig-kernel-type-changed.yaml", []string{"kernelType"}),
+	)
+
+	DescribeTable("of kubelet configs",
+		func(fixture string, expectedEqual bool) {
+			existing := loadKubeletConfig("kubeletconfig.yaml")
+			desired := loadKubeletConfig(fixture)
+
+			equal, err := KubeletConfigSpecEqual(&existing.Spec, &desired.Spec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(equal).To(Equal(expectedEqual))
+		},
+		Entry("identical", "kubeletconfig.yaml", true),
+		Entry("re-serialized", "kubeletconfig-reserialized.yaml", true),
+		Entry("with changed reserved CPUs", "kubeletconfig-changed.yaml", false),
+	)
+})
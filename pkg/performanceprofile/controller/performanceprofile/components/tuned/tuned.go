@@ -2,8 +2,11 @@ package tuned
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -11,18 +14,19 @@ import (
 	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/cpuset"
-	"k8s.io/utils/pointer"
 
 	assets "github.com/openshift/cluster-node-tuning-operator/assets/performanceprofile"
 	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
 	profilecomponent "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/profile"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/kernelargs"
 )
 
 const (
 	cmdlineDelimiter                        = " "
 	templateIsolatedCpus                    = "IsolatedCpus"
 	templateStaticIsolation                 = "StaticIsolation"
+	templateCgroupPartitionType             = "CgroupPartitionType"
 	templateDefaultHugepagesSize            = "DefaultHugepagesSize"
 	templateHugepages                       = "Hugepages"
 	templateAdditionalArgs                  = "AdditionalArgs"
@@ -38,6 +42,23 @@ const (
 	templateIsolatedCpuList                 = "IsolatedCpuList"
 	templateReservedCpuList                 = "ReservedCpuList"
 	templatePerformanceProfileName          = "PerformanceProfileName"
+	templateIrqBannedReservedCpus           = "IrqBannedReservedCpus"
+	templateDisableSMT                      = "DisableSMT"
+	templateNumaBalancing                   = "NumaBalancing"
+	templateKernelSamePageMerging           = "KernelSamePageMerging"
+	templateNohzFullCpus                    = "NohzFullCpus"
+	templateRcuNocbsCpus                    = "RcuNocbsCpus"
+	templateRcuKthreadPrio                  = "RcuKthreadPrio"
+	templateWorkqueueCpus                   = "WorkqueueCpus"
+	templateSchedRtRuntimeUs                = "SchedRtRuntimeUs"
+	templateTimerMigration                  = "TimerMigration"
+	templateRealTimeProfileName             = "RealTimeProfileName"
+	templateNetworkStackMode                = "NetworkStackMode"
+	templateNetworkStackSysctls             = "NetworkStackSysctls"
+	// profileHashLength is the number of hex characters of the content hash suffixing the profile names
+	profileHashLength = 10
+	// isolatedCoresVariable references the isolated_cores variable of the tuned profile
+	isolatedCoresVariable = "${isolated_cores}"
 )
 
 func new(name string, profiles []tunedv1.TunedProfile, recommends []tunedv1.TunedRecommend) *tunedv1.Tuned {
@@ -49,6 +70,9 @@ func new(name string, profiles []tunedv1.TunedProfile, recommends []tunedv1.Tune
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: components.NamespaceNodeTuningOperator,
+			Labels: map[string]string{
+				tunedv1.TunedPriorityLayerLabel: string(tunedv1.PriorityLayerSystem),
+			},
 		},
 		Spec: tunedv1.TunedSpec{
 			Profile:   profiles,
@@ -57,6 +81,59 @@ func new(name string, profiles []tunedv1.TunedProfile, recommends []tunedv1.Tune
 	}
 }
 
+// networkStackSysctls are the network sysctls generated for each network stack. The neighbour tables of the
+// stacks the nodes run are sized up, an IPv6 neighbour usually holding a link-local and a global entry, and the
+// RFS flow table is sized for the flows of both stacks. The busy polling keeps the network-latency defaults.
+var networkStackSysctls = map[performancev2.NetworkStackMode]map[string]string{
+	performancev2.NetworkStackIPv4Only: {
+		"net.ipv4.tcp_fastopen":             "3",
+		"net.core.rps_sock_flow_entries":    "32768",
+		"net.ipv4.neigh.default.gc_thresh1": "1024",
+		"net.ipv4.neigh.default.gc_thresh2": "4096",
+		"net.ipv4.neigh.default.gc_thresh3": "8192",
+	},
+	performancev2.NetworkStackDualStack: {
+		"net.ipv4.tcp_fastopen":             "3",
+		"net.core.rps_sock_flow_entries":    "65536",
+		"net.ipv4.neigh.default.gc_thresh1": "1024",
+		"net.ipv4.neigh.default.gc_thresh2": "4096",
+		"net.ipv4.neigh.default.gc_thresh3": "8192",
+		"net.ipv6.neigh.default.gc_thresh1": "2048",
+		"net.ipv6.neigh.default.gc_thresh2": "8192",
+		"net.ipv6.neigh.default.gc_thresh3": "16384",
+	},
+	performancev2.NetworkStackIPv6Primary: {
+		"net.ipv4.tcp_fastopen":             "3",
+		"net.core.rps_sock_flow_entries":    "65536",
+		"net.ipv4.neigh.default.gc_thresh1": "256",
+		"net.ipv4.neigh.default.gc_thresh2": "1024",
+		"net.ipv4.neigh.default.gc_thresh3": "2048",
+		"net.ipv6.neigh.default.gc_thresh1": "4096",
+		"net.ipv6.neigh.default.gc_thresh2": "16384",
+		"net.ipv6.neigh.default.gc_thresh3": "32768",
+		"net.ipv6.route.max_size":           "65536",
+	},
+}
+
+// getNetworkStackSysctls returns the network sysctls of the network stack 'stack' with its overrides applied,
+// one per line and sorted by name
+func getNetworkStackSysctls(stack *performancev2.NetworkStack) string {
+	sysctls := map[string]string{}
+	for name, value := range networkStackSysctls[stack.Mode] {
+		sysctls[name] = value
+	}
+	for name, value := range stack.Sysctls {
+		sysctls[name] = value
+	}
+
+	lines := make([]string, 0, len(sysctls))
+	for name, value := range sysctls {
+		lines = append(lines, fmt.Sprintf("%s=%s", name, value))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
 // NewNodePerformance returns tuned profile for performance sensitive workflows
 func NewNodePerformance(profile *performancev2.PerformanceProfile) (*tunedv1.Tuned, error) {
 	templateArgs := make(map[string]interface{})
@@ -68,6 +145,44 @@ func NewNodePerformance(profile *performancev2.PerformanceProfile) (*tunedv1.Tun
 		if profile.Spec.CPU.BalanceIsolated != nil && !*profile.Spec.CPU.BalanceIsolated {
 			templateArgs[templateStaticIsolation] = strconv.FormatBool(true)
 		}
+		// an isolated partition disables the load balancing, a root partition keeps it within the partition
+		if profilecomponent.IsCgroupPartitionIsolationEnabled(profile) {
+			templateArgs[templateCgroupPartitionType] = "root"
+			if templateArgs[templateStaticIsolation] != nil {
+				templateArgs[templateCgroupPartitionType] = "isolated"
+			}
+		}
+	}
+
+	// the kernel arguments cover the isolated CPUs unless the profile narrows them down
+	templateArgs[templateNohzFullCpus] = isolatedCoresVariable
+	if profile.Spec.CPU.NohzFull != nil {
+		nohzFullCpus, err := cpuset.Parse(string(*profile.Spec.CPU.NohzFull))
+		if err != nil {
+			return nil, err
+		}
+		templateArgs[templateNohzFullCpus] = nohzFullCpus.String()
+	}
+
+	templateArgs[templateRcuNocbsCpus] = isolatedCoresVariable
+	if profile.Spec.CPU.RCUNocbs != nil {
+		rcuNocbsCpus, err := cpuset.Parse(string(*profile.Spec.CPU.RCUNocbs))
+		if err != nil {
+			return nil, err
+		}
+		templateArgs[templateRcuNocbsCpus] = rcuNocbsCpus.String()
+	}
+
+	if profile.Spec.CPU.RCUKthreadPriority != nil {
+		templateArgs[templateRcuKthreadPrio] = strconv.Itoa(int(*profile.Spec.CPU.RCUKthreadPriority))
+	}
+
+	if profile.Spec.CPU.PinUnboundWorkqueues != nil && *profile.Spec.CPU.PinUnboundWorkqueues {
+		reservedSet, err := cpuset.Parse(string(*profile.Spec.CPU.Reserved))
+		if err != nil {
+			return nil, err
+		}
+		templateArgs[templateWorkqueueCpus] = reservedSet.String()
 	}
 
 	if profile.Spec.HardwareTuning != nil {
@@ -95,55 +210,60 @@ func NewNodePerformance(profile *performancev2.PerformanceProfile) (*tunedv1.Tun
 	}
 
 	if profile.Spec.HugePages != nil {
-		var defaultHugepageSize performancev2.HugePageSize
 		if profile.Spec.HugePages.DefaultHugePagesSize != nil {
-			defaultHugepageSize = *profile.Spec.HugePages.DefaultHugePagesSize
-			templateArgs[templateDefaultHugepagesSize] = string(defaultHugepageSize)
+			templateArgs[templateDefaultHugepagesSize] = string(*profile.Spec.HugePages.DefaultHugePagesSize)
 		}
 
-		var is2MHugepagesRequested *bool
-		var hugepages []string
-		for _, page := range profile.Spec.HugePages.Pages {
-			// we can not allocate huge pages on the specific NUMA node via kernel boot arguments
-			if page.Node != nil {
-				// a user requested to allocate 2M huge pages on the specific NUMA node,
-				// append dummy kernel arguments
-				if page.Size == components.HugepagesSize2M && is2MHugepagesRequested == nil {
-					is2MHugepagesRequested = pointer.Bool(true)
-				}
-				continue
-			}
+		hugepagesArgs, err := kernelargs.GetHugepages(profile)
+		if err != nil {
+			return nil, err
+		}
+		hugepages := kernelargs.NewSet(hugepagesArgs...)
+		templateArgs[templateHugepages] = strings.Join(hugepages.Strings(), cmdlineDelimiter)
+	}
 
-			// a user requested to allocated 2M huge pages without specifying the node
-			// we need to append 2M hugepages kernel arguments anyway, no need to add dummy
-			// kernel arguments
-			if page.Size == components.HugepagesSize2M {
-				is2MHugepagesRequested = pointer.Bool(false)
-			}
+	if profile.Spec.AdditionalKernelArgs != nil {
+		templateArgs[templateAdditionalArgs] = strings.Join(profile.Spec.AdditionalKernelArgs, cmdlineDelimiter)
+	}
 
-			hugepages = append(hugepages, fmt.Sprintf("hugepagesz=%s", string(page.Size)))
-			hugepages = append(hugepages, fmt.Sprintf("hugepages=%d", page.Count))
-		}
+	if profile.Spec.CPU.SMTPolicy != nil && *profile.Spec.CPU.SMTPolicy == performancev2.SMTPolicyDisableAll {
+		templateArgs[templateDisableSMT] = strconv.FormatBool(true)
+	}
 
-		// append dummy 2M huge pages kernel arguments to guarantee that the kernel will create 2M related files
-		// and directories under the filesystem
-		if is2MHugepagesRequested != nil && *is2MHugepagesRequested {
-			if defaultHugepageSize == components.HugepagesSize1G {
-				hugepages = append(hugepages, fmt.Sprintf("hugepagesz=%s", components.HugepagesSize2M))
-				hugepages = append(hugepages, fmt.Sprintf("hugepages=%d", 0))
-			}
-		}
+	if profile.Spec.NUMA != nil && profile.Spec.NUMA.AutomaticBalancing != nil {
+		templateArgs[templateNumaBalancing] = boolToSwitch(*profile.Spec.NUMA.AutomaticBalancing)
+	}
 
-		hugepagesArgs := strings.Join(hugepages, cmdlineDelimiter)
-		templateArgs[templateHugepages] = hugepagesArgs
+	// OCP evacuates the scheduled timers when starting a guaranteed workload
+	templateArgs[templateTimerMigration] = boolToSwitch(true)
+	if profile.Spec.Scheduler != nil {
+		if profile.Spec.Scheduler.RTRuntimeUs != nil {
+			templateArgs[templateSchedRtRuntimeUs] = strconv.Itoa(int(*profile.Spec.Scheduler.RTRuntimeUs))
+		}
+		if profile.Spec.Scheduler.TimerMigration != nil {
+			templateArgs[templateTimerMigration] = boolToSwitch(*profile.Spec.Scheduler.TimerMigration)
+		}
 	}
 
-	if profile.Spec.AdditionalKernelArgs != nil {
-		templateArgs[templateAdditionalArgs] = strings.Join(profile.Spec.AdditionalKernelArgs, cmdlineDelimiter)
+	if profile.Spec.Memory != nil && profile.Spec.Memory.KernelSamePageMerging != nil {
+		templateArgs[templateKernelSamePageMerging] = boolToSwitch(*profile.Spec.Memory.KernelSamePageMerging)
 	}
 
 	if IsIRQBalancingGloballyDisabled(profile) {
 		templateArgs[templateGloballyDisableIrqLoadBalancing] = strconv.FormatBool(true)
+
+		irqBannedReservedCpus, err := profilecomponent.GetIRQBannedReservedCPUs(profile)
+		if err != nil {
+			return nil, err
+		}
+		if !irqBannedReservedCpus.IsEmpty() {
+			templateArgs[templateIrqBannedReservedCpus] = irqBannedReservedCpus.String()
+		}
+	}
+
+	if profile.Spec.NetworkStack != nil {
+		templateArgs[templateNetworkStackMode] = string(profile.Spec.NetworkStack.Mode)
+		templateArgs[templateNetworkStackSysctls] = getNetworkStackSysctls(profile.Spec.NetworkStack)
 	}
 
 	//set default [net] field first, override if needed.
@@ -213,17 +333,19 @@ func NewNodePerformance(profile *performancev2.PerformanceProfile) (*tunedv1.Tun
 		templateArgs[templatePerPodPowerManagement] = "true"
 	}
 
-	profileData, err := getProfileData(filepath.Join("tuned", components.ProfileNamePerformance), templateArgs)
+	// the real time profile is rendered first, the performance profile includes it by its hashed name
+	RealTimeKernelProfileData, err := getProfileData(filepath.Join("tuned", components.ProfileNamePerformanceRT), templateArgs)
 	if err != nil {
 		return nil, err
 	}
+	RealTimeKernelProfileName := GetProfileName(profile.Name, components.ProfileNamePerformanceRT, RealTimeKernelProfileData)
+	templateArgs[templateRealTimeProfileName] = RealTimeKernelProfileName
 
-	RealTimeKernelProfileData, err := getProfileData(filepath.Join("tuned", components.ProfileNamePerformanceRT), templateArgs)
+	profileData, err := getProfileData(filepath.Join("tuned", components.ProfileNamePerformance), templateArgs)
 	if err != nil {
 		return nil, err
 	}
-	name := components.GetComponentName(profile.Name, components.ProfileNamePerformance)
-	RealTimeKernelProfileName := components.GetComponentName(profile.Name, components.ProfileNamePerformanceRT)
+	name := GetProfileName(profile.Name, components.ProfileNamePerformance, profileData)
 	profiles := []tunedv1.TunedProfile{
 		{
 			Name: &name,
@@ -233,6 +355,7 @@ func NewNodePerformance(profile *performancev2.PerformanceProfile) (*tunedv1.Tun
 			Name: &RealTimeKernelProfileName,
 			Data: &RealTimeKernelProfileData,
 		},
+		newStableProfile(profile.Name, name),
 	}
 
 	priority := uint64(20)
@@ -241,9 +364,58 @@ func NewNodePerformance(profile *performancev2.PerformanceProfile) (*tunedv1.Tun
 			Profile:             &name,
 			Priority:            &priority,
 			MachineConfigLabels: profilecomponent.GetMachineConfigLabel(profile),
+			Operand: tunedv1.OperandConfig{
+				Hugepages: getRuntimeHugepages(profile),
+			},
 		},
 	}
-	return new(name, profiles, recommends), nil
+	return new(components.GetComponentName(profile.Name, components.ProfileNamePerformance), profiles, recommends), nil
+}
+
+// newStableProfile returns the profile keeping the name of the profiles generated before they were content
+// addressed, it includes the latest generation so that the custom profiles can keep including
+// openshift-node-performance-<PerformanceProfile name>
+func newStableProfile(profileName string, generationName string) tunedv1.TunedProfile {
+	name := components.GetComponentName(profileName, components.ProfileNamePerformance)
+	data := fmt.Sprintf("[main]\nsummary=The latest generation of the performance profile %s\ninclude=%s\n", profileName, generationName)
+	return tunedv1.TunedProfile{
+		Name: &name,
+		Data: &data,
+	}
+}
+
+// GetProfileName returns the content addressed name of the tuned profile generated for the performance profile,
+// the name changes together with the profile data, so nodes never reference a profile whose content changed under them
+func GetProfileName(profileName string, prefix string, data string) string {
+	return fmt.Sprintf("%s-%s", components.GetComponentName(profileName, prefix), GetProfileHash(data))
+}
+
+// GetProfileHash returns the short deterministic hash of the tuned profile data
+func GetProfileHash(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])[:profileHashLength]
+}
+
+// getRuntimeHugepages returns the huge pages the operand allocates on the running nodes
+func getRuntimeHugepages(profile *performancev2.PerformanceProfile) []tunedv1.RuntimeHugepages {
+	if profile.Spec.HugePages == nil {
+		return nil
+	}
+
+	var hugepages []tunedv1.RuntimeHugepages
+	for i := range profile.Spec.HugePages.Pages {
+		page := &profile.Spec.HugePages.Pages[i]
+		if !profilecomponent.IsRuntimeAllocated(page) {
+			continue
+		}
+		hugepages = append(hugepages, tunedv1.RuntimeHugepages{
+			Size:          string(page.Size),
+			Count:         page.Count,
+			MemoryPercent: page.MemoryPercent,
+			Node:          page.Node,
+		})
+	}
+	return hugepages
 }
 
 func getProfileData(tunedTemplate string, data interface{}) (string, error) {
@@ -264,13 +436,21 @@ func IsIRQBalancingGloballyDisabled(profile *performancev2.PerformanceProfile) b
 }
 
 func IsRealTimeHintEnabled(profile *performancev2.PerformanceProfile) bool {
-	return profile.Spec.WorkloadHints == nil || profile.Spec.WorkloadHints.RealTime == nil || *profile.Spec.WorkloadHints.RealTime
+	return profilecomponent.IsRealTimeHintEnabled(profile)
 }
 
 func IsHighPowerConsumptionHintEnabled(profile *performancev2.PerformanceProfile) bool {
-	return profile.Spec.WorkloadHints != nil && profile.Spec.WorkloadHints.HighPowerConsumption != nil && *profile.Spec.WorkloadHints.HighPowerConsumption
+	return profilecomponent.IsHighPowerConsumptionHintEnabled(profile)
 }
 
 func IsPerPodPowerManagementEnabled(profile *performancev2.PerformanceProfile) bool {
-	return profile.Spec.WorkloadHints != nil && profile.Spec.WorkloadHints.PerPodPowerManagement != nil && *profile.Spec.WorkloadHints.PerPodPowerManagement
+	return profilecomponent.IsPerPodPowerManagementEnabled(profile)
+}
+
+// boolToSwitch returns the kernel tunable value switching the feature on or off
+func boolToSwitch(enabled bool) string {
+	if enabled {
+		return "1"
+	}
+	return "0"
 }
@@ -4,12 +4,15 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"testing/fstest"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
 	testutils "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/utils/testing"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/utils/testing/tunedsim"
 	"gopkg.in/ini.v1"
 	"sigs.k8s.io/yaml"
 
@@ -155,6 +158,63 @@ var _ = Describe("Tuned", func() {
 			})
 		})
 
+		When("nohz_full and rcu_nocbs CPUs are set", func() {
+			It("should use them instead of the isolated CPUs", func() {
+				nohzFull := performancev2.CPUSet("4")
+				rcuNocbs := performancev2.CPUSet("4-5")
+				profile.Spec.CPU.NohzFull = &nohzFull
+				profile.Spec.CPU.RCUNocbs = &rcuNocbs
+				tunedData := getTunedStructuredData(profile)
+				bootLoader, err := tunedData.GetSection("bootloader")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bootLoader.Key("cmdline_realtime").String()).To(HavePrefix("+nohz_full=4 tsc=reliable"))
+				Expect(bootLoader.Key("cmdline_cpu_part").String()).To(HavePrefix("+nohz=on rcu_nocbs=4-5 tuned.non_isolcpus"))
+			})
+
+			It("should drop the kernel arguments of empty sets", func() {
+				empty := performancev2.CPUSet("")
+				profile.Spec.CPU.NohzFull = &empty
+				profile.Spec.CPU.RCUNocbs = &empty
+				tunedData := getTunedStructuredData(profile)
+				bootLoader, err := tunedData.GetSection("bootloader")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bootLoader.Key("cmdline_realtime").String()).To(HavePrefix("+tsc=reliable"))
+				Expect(bootLoader.Key("cmdline_cpu_part").String()).To(HavePrefix("+nohz=on tuned.non_isolcpus"))
+			})
+		})
+
+		When("the RCU kernel threads priority is set", func() {
+			It("should override the priority of the realtime workload hint", func() {
+				profile.Spec.CPU.RCUKthreadPriority = pointer.Int32(2)
+				tunedData := getTunedStructuredData(profile)
+				bootLoader, err := tunedData.GetSection("bootloader")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bootLoader.Key("cmdline_realtime").String()).To(HaveSuffix("skew_tick=1 rcutree.kthread_prio=2"))
+				Expect(bootLoader.HasKey("cmdline_rcu")).To(BeFalse())
+			})
+
+			It("should set the priority without the realtime workload hint", func() {
+				profile.Spec.CPU.RCUKthreadPriority = pointer.Int32(2)
+				profile.Spec.WorkloadHints.RealTime = pointer.Bool(false)
+				tunedData := getTunedStructuredData(profile)
+				bootLoader, err := tunedData.GetSection("bootloader")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bootLoader.HasKey("cmdline_realtime")).To(BeFalse())
+				Expect(bootLoader.Key("cmdline_rcu").String()).To(Equal("+rcutree.kthread_prio=2"))
+			})
+		})
+
+		When("the unbound workqueues are pinned", func() {
+			It("should write the reserved CPUs to the workqueue CPU masks", func() {
+				profile.Spec.CPU.PinUnboundWorkqueues = pointer.Bool(true)
+				tunedData := getTunedStructuredData(profile)
+				sysfs, err := tunedData.GetSection("sysfs")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sysfs.Key("/sys/devices/virtual/workqueue/cpumask").String()).To(Equal("${f:cpulist2hex:0-3}"))
+				Expect(sysfs.Key("/sys/bus/workqueue/devices/writeback/cpumask").String()).To(Equal("${f:cpulist2hex:0-3}"))
+			})
+		})
+
 		Context("high power consumption hint enabled", func() {
 			When("default realtime workload settings", func() {
 				It("should contain high power consumption related parameters", func() {
@@ -269,6 +329,192 @@ var _ = Describe("Tuned", func() {
 			Expect(bootLoader.Key("cmdline_isolation").String()).To(Equal(cmdlineWithoutStaticIsolation))
 		})
 
+		Context("with the cgroup partition isolation method", func() {
+			BeforeEach(func() {
+				isolationMethod := performancev2.CPUIsolationMethodCgroupPartition
+				profile.Spec.CPU.IsolationMethod = &isolationMethod
+			})
+
+			It("should isolate the CPUs with a root cgroup partition instead of the kernel arguments", func() {
+				profile.Spec.CPU.BalanceIsolated = pointer.Bool(true)
+				tunedData := getTunedStructuredData(profile)
+				bootLoader, err := tunedData.GetSection("bootloader")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bootLoader.HasKey("cmdline_isolation")).To(BeFalse())
+				sysfs, err := tunedData.GetSection("sysfs")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sysfs.Key("/sys/fs/cgroup/kubepods.slice/cpuset.cpus.exclusive").String()).To(Equal("${isolated_cores}"))
+				Expect(sysfs.Key("/sys/fs/cgroup/kubepods.slice/cpuset.cpus.partition").String()).To(Equal("root"))
+			})
+
+			It("should isolate the CPUs with an isolated cgroup partition for Isolated balancing disabled", func() {
+				profile.Spec.CPU.BalanceIsolated = pointer.Bool(false)
+				tunedData := getTunedStructuredData(profile)
+				sysfs, err := tunedData.GetSection("sysfs")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sysfs.Key("/sys/fs/cgroup/kubepods.slice/cpuset.cpus.partition").String()).To(Equal("isolated"))
+			})
+		})
+
+		Context("with IRQ serving CPUs", func() {
+			BeforeEach(func() {
+				irqServing := performancev2.CPUSet("0-1")
+				profile.Spec.CPU.IRQServing = &irqServing
+			})
+
+			It("should not set irqbalance banned CPUs when IRQ load balancing is dynamic", func() {
+				tunedData := getTunedStructuredData(profile)
+				irqbalance, err := tunedData.GetSection("irqbalance")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(irqbalance.Key("enabled").String()).To(Equal("false"))
+				Expect(irqbalance.HasKey("banned_cpus")).To(BeFalse())
+			})
+
+			It("should ban the reserved CPUs not serving IRQs when IRQ load balancing is globally disabled", func() {
+				profile.Spec.GloballyDisableIrqLoadBalancing = pointer.Bool(true)
+				tunedData := getTunedStructuredData(profile)
+				irqbalance, err := tunedData.GetSection("irqbalance")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(irqbalance.HasKey("enabled")).To(BeFalse())
+				Expect(irqbalance.Key("banned_cpus").String()).To(Equal("${isolated_cores},2-3"))
+			})
+		})
+
+		Context("with SMT policy", func() {
+			It("should not disable SMT by default", func() {
+				tunedData := getTunedStructuredData(profile)
+				bootLoader, err := tunedData.GetSection("bootloader")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bootLoader.HasKey("cmdline_smt")).To(BeFalse())
+			})
+
+			It("should disable SMT on all the CPUs", func() {
+				smtPolicy := performancev2.SMTPolicyDisableAll
+				profile.Spec.CPU.SMTPolicy = &smtPolicy
+				tunedData := getTunedStructuredData(profile)
+				bootLoader, err := tunedData.GetSection("bootloader")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bootLoader.Key("cmdline_smt").String()).To(Equal("+nosmt"))
+			})
+
+			It("should not add the nosmt kernel argument when disabling SMT on the isolated CPUs only", func() {
+				smtPolicy := performancev2.SMTPolicyDisableIsolatedOnly
+				profile.Spec.CPU.SMTPolicy = &smtPolicy
+				tunedData := getTunedStructuredData(profile)
+				bootLoader, err := tunedData.GetSection("bootloader")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bootLoader.HasKey("cmdline_smt")).To(BeFalse())
+			})
+		})
+
+		Context("with memory and NUMA balancing controls", func() {
+			It("should keep the inherited values by default", func() {
+				tunedData := getTunedStructuredData(profile)
+				sysctl, err := tunedData.GetSection("sysctl")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sysctl.HasKey("kernel.numa_balancing")).To(BeFalse())
+				Expect(tunedData.HasSection("sysfs")).To(BeFalse())
+			})
+
+			It("should enable the automatic NUMA balancing", func() {
+				profile.Spec.NUMA.AutomaticBalancing = pointer.Bool(true)
+				tunedData := getTunedStructuredData(profile)
+				sysctl, err := tunedData.GetSection("sysctl")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sysctl.Key("kernel.numa_balancing").String()).To(Equal("1"))
+			})
+
+			It("should switch the kernel samepage merging", func() {
+				profile.Spec.Memory = &performancev2.Memory{KernelSamePageMerging: pointer.Bool(false)}
+				tunedData := getTunedStructuredData(profile)
+				sysfs, err := tunedData.GetSection("sysfs")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sysfs.Key("/sys/kernel/mm/ksm/run").String()).To(Equal("0"))
+			})
+
+			It("should switch the kernel samepage merging together with the hardware tuning", func() {
+				profile.Spec.Memory = &performancev2.Memory{KernelSamePageMerging: pointer.Bool(true)}
+				profile.Spec.HardwareTuning = &performancev2.HardwareTuning{
+					IsolatedCpuFreq: (*performancev2.CPUfrequency)(pointer.Int(2500000)),
+					ReservedCpuFreq: (*performancev2.CPUfrequency)(pointer.Int(2800000)),
+				}
+				tunedData := getTunedStructuredData(profile)
+				sysfs, err := tunedData.GetSection("sysfs")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sysfs.Key("/sys/kernel/mm/ksm/run").String()).To(Equal("1"))
+				Expect(sysfs.Key("/sys/devices/system/cpu/cpufreq/policy4/scaling_max_freq").String()).To(Equal("2500000"))
+			})
+		})
+
+		Context("with realtime scheduler controls", func() {
+			It("should keep the default values", func() {
+				tunedData := getTunedStructuredData(profile)
+				sysctl, err := tunedData.GetSection("sysctl")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sysctl.Key("kernel.timer_migration").String()).To(Equal("1"))
+				Expect(sysctl.Key("kernel.sched_rt_runtime_us").String()).To(Equal("-1"))
+			})
+
+			It("should throttle the realtime tasks with the realtime hint", func() {
+				profile.Spec.Scheduler = &performancev2.Scheduler{RTRuntimeUs: pointer.Int32(950000)}
+				tunedData := getTunedStructuredData(profile)
+				sysctl, err := tunedData.GetSection("sysctl")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sysctl.Key("kernel.sched_rt_runtime_us").String()).To(Equal("950000"))
+			})
+
+			It("should disable the realtime throttling without the realtime hint", func() {
+				profile.Spec.WorkloadHints = &performancev2.WorkloadHints{RealTime: pointer.Bool(false)}
+				profile.Spec.Scheduler = &performancev2.Scheduler{
+					RTRuntimeUs:    pointer.Int32(-1),
+					TimerMigration: pointer.Bool(false),
+				}
+				tunedData := getTunedStructuredData(profile)
+				sysctl, err := tunedData.GetSection("sysctl")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sysctl.Key("kernel.sched_rt_runtime_us").String()).To(Equal("-1"))
+				Expect(sysctl.Key("kernel.timer_migration").String()).To(Equal("0"))
+				Expect(sysctl.HasKey("kernel.hung_task_timeout_secs")).To(BeFalse())
+			})
+		})
+
+		Context("with a network stack", func() {
+			It("should keep the IPv4 oriented defaults without a network stack", func() {
+				tunedData := getTunedStructuredData(profile)
+				sysctl, err := tunedData.GetSection("sysctl")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sysctl.Key("net.ipv4.tcp_fastopen").String()).To(Equal("3"))
+				Expect(sysctl.HasKey("net.ipv4.neigh.default.gc_thresh3")).To(BeFalse())
+			})
+
+			It("should size the network sysctls for the network stack", func() {
+				profile.Spec.NetworkStack = &performancev2.NetworkStack{Mode: performancev2.NetworkStackIPv6Primary}
+				tunedData := getTunedStructuredData(profile)
+				sysctl, err := tunedData.GetSection("sysctl")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sysctl.Key("net.ipv4.tcp_fastopen").String()).To(Equal("3"))
+				Expect(sysctl.Key("net.core.rps_sock_flow_entries").String()).To(Equal("65536"))
+				Expect(sysctl.Key("net.ipv4.neigh.default.gc_thresh3").String()).To(Equal("2048"))
+				Expect(sysctl.Key("net.ipv6.neigh.default.gc_thresh3").String()).To(Equal("32768"))
+			})
+
+			It("should apply the network sysctls overrides of the profile", func() {
+				profile.Spec.NetworkStack = &performancev2.NetworkStack{
+					Mode: performancev2.NetworkStackIPv4Only,
+					Sysctls: map[string]string{
+						"net.ipv4.neigh.default.gc_thresh3": "16384",
+						"net.ipv4.tcp_rmem":                 "4096 131072 6291456",
+					},
+				}
+				tunedData := getTunedStructuredData(profile)
+				sysctl, err := tunedData.GetSection("sysctl")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sysctl.Key("net.ipv4.neigh.default.gc_thresh3").String()).To(Equal("16384"))
+				Expect(sysctl.Key("net.ipv4.tcp_rmem").String()).To(Equal("4096 131072 6291456"))
+				Expect(sysctl.HasKey("net.ipv6.neigh.default.gc_thresh3")).To(BeFalse())
+			})
+		})
+
 		// This tests checking Additional arguments is an example of how additional kernel args could look like
 		// they have been selected randomly with no concrete purpose
 		It("should contain additional additional parameters", func() {
@@ -328,6 +574,28 @@ var _ = Describe("Tuned", func() {
 				})
 			})
 
+			Context("with requested 2M huge pages allocation at runtime", func() {
+				It("should append the dummy 2M huge pages kernel arguments and pass the pages to the operand", func() {
+					runtimeAllocation := performancev2.HugePageAllocationRuntime
+					profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, performancev2.HugePage{
+						Size:       components.HugepagesSize2M,
+						Count:      128,
+						Allocation: &runtimeAllocation,
+					})
+
+					tunedData := getTunedStructuredData(profile)
+					bootLoader, err := tunedData.GetSection("bootloader")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(bootLoader.Key("cmdline_hugepages").String()).To(Equal(cmdlineDummy2MHugePages))
+
+					tuned, err := NewNodePerformance(profile)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(tuned.Spec.Recommend[0].Operand.Hugepages).To(Equal([]tunedv1.RuntimeHugepages{
+						{Size: components.HugepagesSize2M, Count: 128},
+					}))
+				})
+			})
+
 			Context("without requested 2M hugepages", func() {
 				It("should not append dummy 2M huge pages kernel arguments", func() {
 					tunedData := getTunedStructuredData(profile)
@@ -527,5 +795,124 @@ var _ = Describe("Tuned", func() {
 				})
 			})
 		})
+
+		Context("with a simulated node", func() {
+			var node fstest.MapFS
+
+			BeforeEach(func() {
+				node = fstest.MapFS{
+					"proc/sys/kernel/sched_rt_runtime_us":                     {Data: []byte("950000\n")},
+					"proc/sys/kernel/timer_migration":                         {Data: []byte("1\n")},
+					"proc/sys/vm/swappiness":                                  {Data: []byte("60\n")},
+					"sys/kernel/mm/transparent_hugepage/enabled":              {Data: []byte("[always] madvise never\n")},
+					"sys/devices/system/cpu/cpu0/cpufreq/scaling_governor":    {Data: []byte("powersave\n")},
+					"sys/devices/system/cpu/cpu1/cpufreq/scaling_governor":    {Data: []byte("performance\n")},
+					"sys/devices/system/cpu/cpufreq/policy4/scaling_max_freq": {Data: []byte("3000000\n")},
+				}
+			})
+
+			simulate := func(profile *performancev2.PerformanceProfile) *tunedsim.Result {
+				tuned, err := NewNodePerformance(profile)
+				Expect(err).ToNot(HaveOccurred())
+				result, err := tunedsim.Simulate(*tuned.Spec.Profile[0].Data, node)
+				Expect(err).ToNot(HaveOccurred())
+				return result
+			}
+
+			It("should compute the node writes", func() {
+				result := simulate(profile)
+
+				write, ok := result.Get("proc/sys/kernel/sched_rt_runtime_us")
+				Expect(ok).To(BeTrue())
+				Expect(write.Plugin).To(Equal(tunedsim.PluginSysctl))
+				Expect(write.Value).To(Equal("-1"))
+				Expect(write.Changed()).To(BeTrue())
+
+				write, ok = result.Get("proc/sys/kernel/timer_migration")
+				Expect(ok).To(BeTrue())
+				Expect(write.Changed()).To(BeFalse())
+
+				write, ok = result.Get("proc/sys/vm/swappiness")
+				Expect(ok).To(BeTrue())
+				Expect(write.Value).To(Equal("10"))
+
+				write, ok = result.Get("sys/kernel/mm/transparent_hugepage/enabled")
+				Expect(ok).To(BeTrue())
+				Expect(write.Current).To(Equal("always"))
+				Expect(write.Value).To(Equal("never"))
+
+				write, ok = result.Get("sys/devices/system/cpu/cpu0/cpufreq/scaling_governor")
+				Expect(ok).To(BeTrue())
+				Expect(write.Changed()).To(BeTrue())
+				write, ok = result.Get("sys/devices/system/cpu/cpu1/cpufreq/scaling_governor")
+				Expect(ok).To(BeTrue())
+				Expect(write.Changed()).To(BeFalse())
+
+				Expect(result.Skipped).To(ContainElement("/proc/sys/kernel/hung_task_timeout_secs"))
+			})
+
+			It("should expand the profile variables into the kernel command line", func() {
+				result := simulate(profile)
+				Expect(result.Cmdline).To(ContainSubstring("isolcpus=managed_irq,4-5"))
+				Expect(result.Cmdline).To(ContainSubstring("nohz_full=4-5"))
+				Expect(result.Cmdline).ToNot(ContainSubstring("${isolated_cores}"))
+			})
+
+			When("hardware tuning is set", func() {
+				It("should write the isolated CPUs max frequency", func() {
+					profile.Spec.HardwareTuning = &performancev2.HardwareTuning{
+						IsolatedCpuFreq: (*performancev2.CPUfrequency)(pointer.Int(2500000)),
+						ReservedCpuFreq: (*performancev2.CPUfrequency)(pointer.Int(2800000)),
+					}
+					result := simulate(profile)
+
+					write, ok := result.Get("sys/devices/system/cpu/cpufreq/policy4/scaling_max_freq")
+					Expect(ok).To(BeTrue())
+					Expect(write.Plugin).To(Equal(tunedsim.PluginSysfs))
+					Expect(write.Value).To(Equal("2500000"))
+					Expect(result.Skipped).To(ContainElement("/sys/devices/system/cpu/cpufreq/policy5/scaling_max_freq"))
+				})
+			})
+		})
+	})
+
+	Context("with content addressed profile names", func() {
+		It("should suffix the profile names with the hash of their data", func() {
+			tuned, err := NewNodePerformance(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tuned.Name).To(Equal(components.GetComponentName(profile.Name, components.ProfileNamePerformance)))
+			for _, tunedProfile := range tuned.Spec.Profile[:2] {
+				Expect(*tunedProfile.Name).To(HaveSuffix("-" + GetProfileHash(*tunedProfile.Data)))
+			}
+			Expect(*tuned.Spec.Recommend[0].Profile).To(Equal(*tuned.Spec.Profile[0].Name))
+		})
+
+		It("should keep the profile name without the hash including the latest generation", func() {
+			tuned, err := NewNodePerformance(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tuned.Spec.Profile).To(HaveLen(3))
+			Expect(*tuned.Spec.Profile[2].Name).To(Equal(components.GetComponentName(profile.Name, components.ProfileNamePerformance)))
+			Expect(*tuned.Spec.Profile[2].Data).To(ContainSubstring("\ninclude=" + *tuned.Spec.Profile[0].Name + "\n"))
+		})
+
+		It("should include the real time profile by its hashed name", func() {
+			tuned, err := NewNodePerformance(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*tuned.Spec.Profile[0].Data).To(ContainSubstring(":rt:," + *tuned.Spec.Profile[1].Name + ":}"))
+		})
+
+		It("should keep the names stable across renders and change them with the content", func() {
+			first, err := NewNodePerformance(profile)
+			Expect(err).ToNot(HaveOccurred())
+			second, err := NewNodePerformance(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*second.Spec.Profile[0].Name).To(Equal(*first.Spec.Profile[0].Name))
+
+			profile.Spec.AdditionalKernelArgs = []string{"audit=0"}
+			changed, err := NewNodePerformance(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*changed.Spec.Profile[0].Name).ToNot(Equal(*first.Spec.Profile[0].Name))
+			Expect(*changed.Spec.Profile[1].Name).To(Equal(*first.Spec.Profile[1].Name))
+		})
 	})
 })
@@ -22,19 +22,25 @@ import (
 	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
 	"os"
 	"reflect"
+	"sort"
 	"time"
 
 	apiconfigv1 "github.com/openshift/api/config/v1"
 	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	ppvalidation "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2/validation"
 	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/config"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/metrics"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/machineconfig"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/manifestset"
 	profileutil "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/profile"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/tracing"
 	operatorv1helpers "github.com/openshift/library-go/pkg/operator/v1helpers"
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"go.opentelemetry.io/otel/attribute"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	nodev1 "k8s.io/api/node/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -46,6 +52,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
+	"k8s.io/utils/clock"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -68,11 +75,30 @@ type PerformanceProfileReconciler struct {
 	Scheme      *runtime.Scheme
 	Recorder    record.EventRecorder
 	FeatureGate featuregates.FeatureGate
+	// APIReader reads the objects the manager does not cache, like the pods and the PodDisruptionBudgets of all the namespaces
+	APIReader client.Reader
+
+	// MachineConfigWriteQPS and MachineConfigWriteBurst rate limit the writes toward the MCO-controlled objects
+	// across all the profiles, zero QPS disables the rate limiting
+	MachineConfigWriteQPS   float32
+	MachineConfigWriteBurst int
+	// MachineConfigSyncWindow coalesces the repeated regenerations of the same machine config pool
+	MachineConfigSyncWindow time.Duration
+	// AssetsOverrideDir is the directory whose files override the embedded component templates, checked
+	// for changes every AssetsRefreshInterval; empty uses the embedded templates as they are
+	AssetsOverrideDir     string
+	AssetsRefreshInterval time.Duration
+	// ReadOnly computes and reports the profile components without writing them, nor the machine config pools
+	ReadOnly bool
+
+	writeThrottle *machineConfigWriteThrottle
 }
 
 // SetupWithManager creates a new PerformanceProfile Controller and adds it to the Manager.
 // The Manager will set fields on the Controller and Start it when the Manager is Started.
 func (r *PerformanceProfileReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.writeThrottle = newMachineConfigWriteThrottle(r.MachineConfigWriteQPS, r.MachineConfigWriteBurst, r.MachineConfigSyncWindow, clock.RealClock{})
+
 	// we want to initate reconcile loop only on change under labels or spec of the object
 	p := predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
@@ -108,7 +134,10 @@ func (r *PerformanceProfileReconciler) SetupWithManager(mgr ctrl.Manager) error
 			mcpOld := e.ObjectOld.(*mcov1.MachineConfigPool)
 			mcpNew := e.ObjectNew.(*mcov1.MachineConfigPool)
 
-			return !reflect.DeepEqual(mcpOld.Status.Conditions, mcpNew.Status.Conditions)
+			return !reflect.DeepEqual(mcpOld.Status.Conditions, mcpNew.Status.Conditions) ||
+				mcpOld.Status.MachineCount != mcpNew.Status.MachineCount ||
+				mcpOld.Status.UpdatedMachineCount != mcpNew.Status.UpdatedMachineCount ||
+				mcpOld.Status.DegradedMachineCount != mcpNew.Status.DegradedMachineCount
 		},
 	}
 
@@ -121,7 +150,39 @@ func (r *PerformanceProfileReconciler) SetupWithManager(mgr ctrl.Manager) error
 			tunedProfileOld := e.ObjectOld.(*tunedv1.Profile)
 			tunedProfileNew := e.ObjectNew.(*tunedv1.Profile)
 
-			return !reflect.DeepEqual(tunedProfileOld.Status.Conditions, tunedProfileNew.Status.Conditions)
+			// the applied tuned profile changes once the node converged to a new profile generation
+			return tunedProfileOld.Status.TunedProfile != tunedProfileNew.Status.TunedProfile ||
+				!reflect.DeepEqual(tunedProfileOld.Status.Conditions, tunedProfileNew.Status.Conditions)
+		},
+	}
+
+	// the nodes are interesting once the machine config daemon finished updating them
+	// or the tuned daemon reported their kubelet configuration checksums
+	nodePredicates := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !validateUpdateEvent(&e) {
+				return false
+			}
+
+			nodeOld := e.ObjectOld.(*corev1.Node)
+			nodeNew := e.ObjectNew.(*corev1.Node)
+			return nodeOld.Annotations[mcdStateAnnotation] != nodeNew.Annotations[mcdStateAnnotation] ||
+				nodeOld.Annotations[mcdCurrentConfigAnnotation] != nodeNew.Annotations[mcdCurrentConfigAnnotation] ||
+				nodeOld.Annotations[tunedv1.TunedKubeletConfigChecksumsAnnotationKey] != nodeNew.Annotations[tunedv1.TunedKubeletConfigChecksumsAnnotationKey] ||
+				!reflect.DeepEqual(nodeOld.Labels, nodeNew.Labels)
+		},
+	}
+
+	// the latency probe jobs are interesting only once they are finished
+	jobPredicates := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !validateUpdateEvent(&e) {
+				return false
+			}
+
+			jobOld := e.ObjectOld.(*batchv1.Job)
+			jobNew := e.ObjectNew.(*batchv1.Job)
+			return !reflect.DeepEqual(jobOld.Status.Conditions, jobNew.Status.Conditions)
 		},
 	}
 
@@ -147,12 +208,18 @@ func (r *PerformanceProfileReconciler) SetupWithManager(mgr ctrl.Manager) error
 		},
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	assetsSource, err := r.assetsOverrideSource(mgr)
+	if err != nil {
+		return err
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&performancev2.PerformanceProfile{}).
 		Owns(&mcov1.MachineConfig{}, builder.WithPredicates(p)).
 		Owns(&mcov1.KubeletConfig{}, builder.WithPredicates(kubeletPredicates)).
 		Owns(&tunedv1.Tuned{}, builder.WithPredicates(p)).
 		Owns(&nodev1.RuntimeClass{}, builder.WithPredicates(p)).
+		Owns(&batchv1.Job{}, builder.WithPredicates(jobPredicates)).
 		Watches(&mcov1.MachineConfigPool{},
 			handler.EnqueueRequestsFromMapFunc(r.mcpToPerformanceProfile),
 			builder.WithPredicates(mcpPredicates)).
@@ -160,10 +227,16 @@ func (r *PerformanceProfileReconciler) SetupWithManager(mgr ctrl.Manager) error
 			handler.EnqueueRequestsFromMapFunc(r.tunedProfileToPerformanceProfile),
 			builder.WithPredicates(tunedProfilePredicates),
 		).
+		Watches(&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.nodeToPerformanceProfile),
+			builder.WithPredicates(nodePredicates)).
 		Watches(&mcov1.ContainerRuntimeConfig{},
 			handler.EnqueueRequestsFromMapFunc(r.ctrRuntimeConfToPerformanceProfile),
-			builder.WithPredicates(ctrcfgPredicates)).
-		Complete(r)
+			builder.WithPredicates(ctrcfgPredicates))
+	if assetsSource != nil {
+		b = b.WatchesRawSource(assetsSource, &handler.EnqueueRequestForObject{})
+	}
+	return b.Complete(r)
 }
 
 func (r *PerformanceProfileReconciler) mcpToPerformanceProfile(ctx context.Context, mcpObj client.Object) []reconcile.Request {
@@ -223,10 +296,25 @@ func (r *PerformanceProfileReconciler) tunedProfileToPerformanceProfile(ctx cont
 		return nil
 	}
 
+	return nodeToPerformanceProfileReconcileRequests(profiles, node)
+}
+
+func (r *PerformanceProfileReconciler) nodeToPerformanceProfile(ctx context.Context, nodeObj client.Object) []reconcile.Request {
+	profiles := &performancev2.PerformanceProfileList{}
+	if err := r.List(ctx, profiles); err != nil {
+		klog.Errorf("failed to get performance profiles: %v", err)
+		return nil
+	}
+
+	// the deleted nodes are no longer available, so match the event object labels
+	return nodeToPerformanceProfileReconcileRequests(profiles, nodeObj)
+}
+
+func nodeToPerformanceProfileReconcileRequests(profiles *performancev2.PerformanceProfileList, node client.Object) []reconcile.Request {
 	var requests []reconcile.Request
 	for i, profile := range profiles.Items {
 		profileNodeSelector := labels.Set(profile.Spec.NodeSelector)
-		nodeLabels := labels.Set(node.Labels)
+		nodeLabels := labels.Set(node.GetLabels())
 		if profileNodeSelector.AsSelector().Matches(nodeLabels) {
 			requests = append(requests, reconcile.Request{NamespacedName: namespacedName(&profiles.Items[i])})
 		}
@@ -356,10 +444,13 @@ func validateUpdateEvent(e *event.UpdateEvent) bool {
 }
 
 // +kubebuilder:rbac:groups="",resources=events,verbs=*
-// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list
 // +kubebuilder:rbac:groups=performance.openshift.io,resources=performanceprofiles;performanceprofiles/status;performanceprofiles/finalizers,verbs=*
 // +kubebuilder:rbac:groups=machineconfiguration.openshift.io,resources=machineconfigs;kubeletconfigs,verbs=*
-// +kubebuilder:rbac:groups=machineconfiguration.openshift.io,resources=machineconfigpools;containerruntimeconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=machineconfiguration.openshift.io,resources=machineconfigpools,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=machineconfiguration.openshift.io,resources=containerruntimeconfigs,verbs=get;list;watch
 // +kubebuilder:rbac:groups=tuned.openshift.io,resources=tuneds;profiles,verbs=*
 // +kubebuilder:rbac:groups=node.k8s.io,resources=runtimeclasses,verbs=*
 // +kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures,verbs=get;list;watch
@@ -373,7 +464,10 @@ func validateUpdateEvent(e *event.UpdateEvent) bool {
 // Note:
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
-func (r *PerformanceProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *PerformanceProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "PerformanceProfile.Reconcile", attribute.String("performanceprofile.name", req.Name))
+	defer func() { tracing.End(span, err) }()
+
 	co, err := r.getClusterOperator()
 	if err != nil {
 		klog.Errorf("failed to get ClusterOperator: %v", err)
@@ -391,7 +485,9 @@ func (r *PerformanceProfileReconciler) Reconcile(ctx context.Context, req ctrl.R
 	klog.Info("Reconciling PerformanceProfile")
 	// Fetch the PerformanceProfile instance
 	instance := &performancev2.PerformanceProfile{}
-	err = r.Get(ctx, req.NamespacedName, instance)
+	fetchCtx, fetchSpan := tracing.Start(ctx, "fetch")
+	err = r.Get(fetchCtx, req.NamespacedName, instance)
+	tracing.End(fetchSpan, client.IgnoreNotFound(err))
 	if err != nil {
 		if k8serros.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -403,7 +499,35 @@ func (r *PerformanceProfileReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return reconcile.Result{}, err
 	}
 
+	if r.ReadOnly {
+		return r.reconcileReadOnly(ctx, instance)
+	}
+
+	// the metrics of the profile are dropped once it is deleted
+	recordMetrics := true
+	defer func(start time.Time) {
+		if recordMetrics {
+			metrics.PerformanceProfileReconciled(instance.Name, time.Since(start), err)
+		}
+	}(time.Now())
+
 	if instance.DeletionTimestamp != nil {
+		recordMetrics = false
+		if err := r.restoreMaxUnavailable(ctx, instance); err != nil {
+			klog.Errorf("failed to restore the machine config pool maxUnavailable: %v", err)
+			return reconcile.Result{}, err
+		}
+
+		if err := r.restoreCanaryRollout(ctx, instance); err != nil {
+			klog.Errorf("failed to restore the machine config pool paused by the canary rollout: %v", err)
+			return reconcile.Result{}, err
+		}
+
+		if err := r.restoreDrainHint(ctx, instance); err != nil {
+			klog.Errorf("failed to remove the machine config pool drain hint: %v", err)
+			return reconcile.Result{}, err
+		}
+
 		// delete components
 		if err := r.deleteComponents(instance); err != nil {
 			klog.Errorf("failed to delete components: %v", err)
@@ -411,6 +535,7 @@ func (r *PerformanceProfileReconciler) Reconcile(ctx context.Context, req ctrl.R
 			return reconcile.Result{}, err
 		}
 		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "Deletion succeeded", "Succeeded to delete all components")
+		metrics.DeletePerformanceProfile(instance.Name)
 
 		if r.isComponentsExist(instance) {
 			return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
@@ -439,7 +564,11 @@ func (r *PerformanceProfileReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return reconcile.Result{}, nil
 	}
 
-	if !profileutil.IsCgroupsVersionIgnored(instance) {
+	if profileutil.IsCgroupPartitionIsolationEnabled(instance) {
+		if res, err, done := r.reconcileCgroupsV2(ctx, instance); done {
+			return res, err
+		}
+	} else if !profileutil.IsCgroupsVersionIgnored(instance) {
 		if res, err, done := r.reconcileCgroupsV1(ctx, instance); done {
 			return res, err
 		}
@@ -455,6 +584,17 @@ func (r *PerformanceProfileReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, err
 	}
 
+	// the profile may have been admitted before the cluster CPU partitioning mode changed or without the webhook
+	if errs := ppvalidation.ValidateCPUPartitioning(instance, pinningMode); len(errs) > 0 {
+		conditions := r.getDegradedConditions(conditionReasonCPUPartitioningMismatch, errs.ToAggregate().Error())
+		if err := r.updateStatus(instance, conditions); err != nil {
+			klog.Errorf("failed to update performance profile %q status: %v", instance.Name, err)
+			return reconcile.Result{}, err
+		}
+
+		return reconcile.Result{}, nil
+	}
+
 	ctrRuntime, err := r.getContainerRuntimeName(ctx, instance)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("could not determine high-performance runtime class container-runtime for profile %q; %w", instance.Name, err)
@@ -472,26 +612,59 @@ func (r *PerformanceProfileReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return reconcile.Result{}, nil
 	}
 
-	// apply components
-	result, err := r.applyComponents(instance, &components.Options{
-		ProfileMCP: profileMCP,
-		MachineConfig: components.MachineConfigOptions{
-			PinningMode:      &pinningMode,
-			DefaultRuntime:   ctrRuntime,
-			MixedCPUsEnabled: r.isMixedCPUsEnabled(instance),
-		},
-	})
+	// keep the previous pool components until the new pool runs the profile
+	migrationSourcePool, err := r.reconcileMigration(ctx, instance, profileMCP)
 	if err != nil {
-		klog.Errorf("failed to deploy performance profile %q components: %v", instance.Name, err)
-		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "Creation failed", "Failed to create all components: %v", err)
-		conditions := r.getDegradedConditions(conditionReasonComponentsCreationFailed, err.Error())
+		klog.Errorf("failed to migrate performance profile %q components: %v", instance.Name, err)
+		conditions := r.getDegradedConditions(conditionReasonMigrationFailed, err.Error())
 		if err := r.updateStatus(instance, conditions); err != nil {
 			klog.Errorf("failed to update performance profile %q status: %v", instance.Name, err)
+		}
+		return reconcile.Result{}, err
+	}
+
+	// apply components, the components restored by an automatic rollback are kept until the profile changes
+	var result *reconcile.Result
+	disabledFeatures := r.getDisabledFeatures()
+	if !isRolledBack(instance) {
+		result, err = r.applyComponents(ctx, instance, &components.Options{
+			ProfileMCP: profileMCP,
+			MachineConfig: components.MachineConfigOptions{
+				PinningMode:      &pinningMode,
+				DefaultRuntime:   ctrRuntime,
+				MixedCPUsEnabled: r.isMixedCPUsEnabled(instance),
+			},
+			DisabledFeatures: disabledFeatures,
+		})
+		if err != nil {
+			klog.Errorf("failed to deploy performance profile %q components: %v", instance.Name, err)
+			r.Recorder.Eventf(instance, corev1.EventTypeWarning, "Creation failed", "Failed to create all components: %v", err)
+			conditions := r.getDegradedConditions(conditionReasonComponentsCreationFailed, err.Error())
+			if err := r.updateStatus(instance, conditions); err != nil {
+				klog.Errorf("failed to update performance profile %q status: %v", instance.Name, err)
+				return reconcile.Result{}, err
+			}
 			return reconcile.Result{}, err
 		}
+	}
+
+	if err := r.reconcileMaxUnavailable(ctx, instance, profileMCP); err != nil {
+		klog.Errorf("failed to reconcile performance profile %q machine config pool maxUnavailable: %v", instance.Name, err)
 		return reconcile.Result{}, err
 	}
 
+	canaryCondition, err := r.reconcileCanaryRollout(ctx, instance, profileMCP)
+	if err != nil {
+		klog.Errorf("failed to reconcile performance profile %q canary rollout: %v", instance.Name, err)
+		return reconcile.Result{}, err
+	}
+
+	if err := r.reconcileDrainHint(ctx, profileMCP); err != nil {
+		klog.Errorf("failed to reconcile performance profile %q drain hint: %v", instance.Name, err)
+		return reconcile.Result{}, err
+	}
+	metrics.PerformanceProfileArtifacts(instance.Name, r.getManagedArtifacts(instance))
+
 	// get kubelet false condition
 	conditions, err := r.getKubeletConditionsByProfile(instance)
 	if err != nil {
@@ -516,11 +689,73 @@ func (r *PerformanceProfileReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	// if conditions were not added due to machine config pool status change then set as available
 	if conditions == nil {
-		message := "cgroup=" + string(apiconfigv1.CgroupModeV1) + ";"
+		cgroupMode := apiconfigv1.CgroupModeV1
+		if profileutil.IsCgroupPartitionIsolationEnabled(instance) {
+			cgroupMode = apiconfigv1.CgroupModeV2
+		}
+		message := "cgroup=" + string(cgroupMode) + ";"
 		conditions = r.getAvailableConditions(message)
 	}
 
-	if err := r.updateStatus(instance, conditions); err != nil {
+	if migrationSourcePool != "" {
+		conditions = append(conditions, getMigratingCondition(migrationSourcePool, profileMCP.Name))
+	}
+
+	// the pool stays paused after a canary failure, the profile is degraded until it changes
+	if canaryCondition != nil {
+		if canaryCondition.Reason == conditionReasonCanaryFailed {
+			conditions = r.getDegradedConditions(conditionReasonCanaryFailed, canaryCondition.Message)
+		}
+		conditions = append(conditions, *canaryCondition)
+	}
+
+	if err := r.reconcileRollback(ctx, instance, profileMCP, conditions, result != nil); err != nil {
+		klog.Errorf("failed to reconcile performance profile %q rollback: %v", instance.Name, err)
+		return r.updateDegradedCondition(instance, conditionReasonRollbackFailed, err)
+	}
+
+	if isRolledBack(instance) {
+		conditions = append(conditions, getRolledBackCondition(instance.Status.Rollback))
+	}
+
+	if err := r.reconcileExportedArtifacts(ctx, instance); err != nil {
+		klog.Errorf("failed to export performance profile %q artifacts: %v", instance.Name, err)
+		return r.updateDegradedCondition(instance, conditionReasonExportArtifactsFailed, err)
+	}
+
+	rollout, err := r.getRolloutStatus(ctx, instance, profileMCP)
+	if err != nil {
+		return r.updateDegradedCondition(instance, conditionFailedGettingRolloutStatus, err)
+	}
+
+	kubeletConfigSync, err := r.getKubeletConfigSyncStatus(ctx, instance, profileMCP)
+	if err != nil {
+		return r.updateDegradedCondition(instance, conditionFailedGettingKubeletConfigSync, err)
+	}
+
+	rebootPending, err := r.getRebootPendingCondition(ctx, instance, profileMCP, rollout)
+	if err != nil {
+		return r.updateDegradedCondition(instance, conditionFailedGettingRebootStatus, err)
+	}
+	if rebootPending != nil {
+		conditions = append(conditions, *rebootPending)
+	}
+	metrics.PoolRebootPending(instance.Name, profileMCP.Name, rebootPending != nil)
+
+	hugepagesPending, err := r.getHugepagesPendingCondition(ctx, instance)
+	if err != nil {
+		return r.updateDegradedCondition(instance, conditionFailedGettingTunedProfileStatus, err)
+	}
+	if hugepagesPending != nil {
+		conditions = append(conditions, *hugepagesPending)
+	}
+
+	skippedFeatures, err := manifestset.GetSkippedFeatures(instance, disabledFeatures)
+	if err != nil {
+		return r.updateDegradedCondition(instance, conditionReasonComponentsCreationFailed, err)
+	}
+
+	if err := r.updateStatusWithRollout(instance, conditions, rollout, kubeletConfigSync, getFeatureNames(skippedFeatures)); err != nil {
 		klog.Errorf("failed to update performance profile %q status: %v", instance.Name, err)
 		// we still want to requeue after some, also in case of error, to avoid chance of multiple reboots
 		if result != nil {
@@ -530,10 +765,30 @@ func (r *PerformanceProfileReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return reconcile.Result{}, err
 	}
 
+	if err := r.reconcileSummary(ctx, instance, conditions, rollout); err != nil {
+		klog.Errorf("failed to update performance profile %q summary: %v", instance.Name, err)
+		return reconcile.Result{}, err
+	}
+
+	if err := r.reconcilePoolResources(ctx, instance, profileMCP); err != nil {
+		klog.Errorf("failed to publish performance profile %q pool resources: %v", instance.Name, err)
+		return reconcile.Result{}, err
+	}
+
+	if err := r.reconcileLatencyProbes(ctx, instance, profileMCP); err != nil {
+		klog.Errorf("failed to reconcile performance profile %q latency probes: %v", instance.Name, err)
+		return reconcile.Result{}, err
+	}
+
 	if result != nil {
 		return *result, nil
 	}
 
+	// check the canary node progress and timeout
+	if canaryCondition != nil && canaryCondition.Reason == conditionReasonCanaryInProgress {
+		return reconcile.Result{RequeueAfter: canaryRequeueInterval}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -612,6 +867,30 @@ func (r *PerformanceProfileReconciler) reconcileCgroupsV1(ctx context.Context, i
 	return reconcile.Result{}, nil, false
 }
 
+// reconcileCgroupsV2 degrades the profile isolating the CPUs with a cgroup partition while the cluster
+// runs cgroup v1. The cluster is not switched to cgroup v2, the other profiles may need cgroup v1.
+func (r *PerformanceProfileReconciler) reconcileCgroupsV2(ctx context.Context, instance *performancev2.PerformanceProfile) (ctrl.Result, error, bool) {
+	nodeCfg := &apiconfigv1.Node{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: nodeCfgName}, nodeCfg); err != nil {
+		klog.Errorf("failed to get cluster nodeconfig %q: %v", nodeCfgName, err)
+		return reconcile.Result{}, err, true
+	}
+
+	if nodeCfg.Spec.CgroupMode == apiconfigv1.CgroupModeV1 {
+		message := fmt.Sprintf("the cgroupPartition CPU isolation method requires cgroup v2, the cluster nodeconfig %q sets cgroup v1", nodeCfgName)
+		conditions := r.getDegradedConditions(conditionReasonCgroupsV2NotEnabled, message)
+		if err := r.updateStatus(instance, conditions); err != nil {
+			klog.Errorf("failed to update performance profile %q status: %v", instance.Name, err)
+			return reconcile.Result{}, err, true
+		}
+
+		klog.Errorf("performance profile %q: %s", instance.Name, message)
+		return reconcile.Result{}, nil, true
+	}
+
+	return reconcile.Result{}, nil, false
+}
+
 func (r *PerformanceProfileReconciler) deleteDeprecatedComponents(instance *performancev2.PerformanceProfile) error {
 	// remove the machine config with the deprecated name
 	name := components.GetComponentName(instance.Name, components.ComponentNamePrefix)
@@ -627,75 +906,145 @@ func (r *PerformanceProfileReconciler) updateDegradedCondition(instance *perform
 	return reconcile.Result{}, conditionError
 }
 
-func (r *PerformanceProfileReconciler) applyComponents(profile *performancev2.PerformanceProfile, opts *components.Options) (*reconcile.Result, error) {
+func (r *PerformanceProfileReconciler) applyComponents(ctx context.Context, profile *performancev2.PerformanceProfile, opts *components.Options) (*reconcile.Result, error) {
 	if profileutil.IsPaused(profile) {
 		klog.Infof("Ignoring reconcile loop for pause performance profile %s", profile.Name)
 		return nil, nil
 	}
-	components, err := manifestset.GetNewComponents(profile, opts)
+
+	computeCtx, computeSpan := tracing.Start(ctx, "compute")
+	mcMutated, kcMutated, performanceTunedMutated, runtimeClassMutated, extraMutated, err := r.getMutatedComponents(computeCtx, profile, opts)
+	tracing.End(computeSpan, err)
 	if err != nil {
 		return nil, err
 	}
-	for _, componentObj := range components.ToObjects() {
-		if err := controllerutil.SetControllerReference(profile, componentObj, r.Scheme); err != nil {
-			return nil, err
-		}
+
+	updated := mcMutated != nil ||
+		kcMutated != nil ||
+		performanceTunedMutated != nil ||
+		runtimeClassMutated != nil ||
+		len(extraMutated) > 0
+
+	// does not update any resources, if it no changes to relevant objects and just continue to the status update
+	if !updated {
+		return nil, nil
 	}
 
-	// get mutated machine config
-	mcMutated, err := r.getMutatedMachineConfig(context.TODO(), components.MachineConfig)
+	rerender, err := r.isUpgradeRerender(ctx, mcMutated, kcMutated)
 	if err != nil {
 		return nil, err
 	}
 
-	// get mutated kubelet config
-	kcMutated, err := r.getMutatedKubeletConfig(components.KubeletConfig)
-	if err != nil {
-		return nil, err
+	// roll out the components re-rendered by an operator upgrade one pool at a time
+	if rerender {
+		blocker, err := r.getUpgradeRolloutBlocker(ctx, profile, opts.ProfileMCP)
+		if err != nil {
+			return nil, err
+		}
+		if blocker != "" {
+			klog.Infof("Postpone the upgrade of performance profile %q components: %s", profile.Name, blocker)
+			r.Recorder.Eventf(profile, corev1.EventTypeNormal, "Upgrade postponed", "Postponed the upgrade of the components: %s", blocker)
+			return &reconcile.Result{RequeueAfter: upgradeRolloutRequeueInterval}, nil
+		}
+
+		if mcMutated != nil {
+			mcMutated.Annotations[upgradeSourceConfigAnnotation] = opts.ProfileMCP.Spec.Configuration.Name
+		}
+		klog.Infof("Roll out the upgrade of performance profile %q components on the machine config pool %q", profile.Name, opts.ProfileMCP.Name)
 	}
 
-	// get mutated performance tuned
-	performanceTunedMutated, err := r.getMutatedTuned(components.Tuned)
+	// hint the pool rollout rebooting the nodes, and postpone it to the maintenance window of the pool
+	kernelArgumentsChanged, err := r.isKernelArgumentsChange(ctx, mcMutated, performanceTunedMutated)
 	if err != nil {
 		return nil, err
 	}
+	if mcMutated != nil || kcMutated != nil || kernelArgumentsChanged {
+		notBefore, err := getMaintenanceWindowStart(profile, time.Now())
+		if err != nil {
+			return nil, err
+		}
 
-	// get mutated RuntimeClass
-	runtimeClassMutated, err := r.getMutatedRuntimeClass(components.RuntimeClass)
-	if err != nil {
-		return nil, err
+		if kernelArgumentsChanged || !notBefore.IsZero() {
+			reason := drainHintReasonConfiguration
+			if kernelArgumentsChanged {
+				reason = drainHintReasonKernelArguments
+			}
+			if err := r.publishDrainHint(ctx, profile, opts.ProfileMCP, reason, notBefore); err != nil {
+				return nil, err
+			}
+		}
+
+		if !notBefore.IsZero() {
+			klog.Infof("Postpone the update of performance profile %q components to the maintenance window starting at %v", profile.Name, notBefore)
+			r.Recorder.Eventf(profile, corev1.EventTypeNormal, "Rollout postponed", "Postponed the rollout on the machine config pool %q to the maintenance window starting at %s",
+				opts.ProfileMCP.Name, notBefore.UTC().Format(time.RFC3339))
+			return &reconcile.Result{RequeueAfter: time.Until(notBefore)}, nil
+		}
 	}
 
-	updated := mcMutated != nil ||
-		kcMutated != nil ||
-		performanceTunedMutated != nil ||
-		runtimeClassMutated != nil
+	// batch the writes toward the MCO-controlled objects, the components are regenerated on the next attempt
+	// so the repeated changes of the pool within the sync window land in a single write
+	pool := getWriteThrottlePool(profile, opts.ProfileMCP)
+	if mcMutated != nil || kcMutated != nil {
+		if delay := r.writeThrottle.tryWrite(pool); delay > 0 {
+			klog.V(4).Infof("Postpone the update of performance profile %q components for %v", profile.Name, delay)
+			return &reconcile.Result{RequeueAfter: delay}, nil
+		}
+	}
 
-	// does not update any resources, if it no changes to relevant objects and just continue to the status update
-	if !updated {
-		return nil, nil
+	// the pool rolls out the machine config and the kubelet config changes to the canary node first,
+	// and then to the other nodes with the maxUnavailable of the profile
+	if mcMutated != nil || kcMutated != nil {
+		if err := r.startCanaryRollout(ctx, profile, opts.ProfileMCP); err != nil {
+			r.writeThrottle.forget(pool)
+			return nil, err
+		}
+		if err := r.overrideMaxUnavailable(ctx, profile, opts.ProfileMCP); err != nil {
+			r.writeThrottle.forget(pool)
+			return nil, err
+		}
+	}
+
+	// apply traces the creation or the update of a single artifact
+	apply := func(kind string, obj client.Object, createOrUpdate func() error) error {
+		_, span := tracing.Start(ctx, "apply",
+			attribute.String("artifact.kind", kind),
+			attribute.String("artifact.name", obj.GetName()))
+		err := createOrUpdate()
+		tracing.End(span, err)
+		if err != nil {
+			// retry the failed write without waiting for the sync window
+			r.writeThrottle.forget(pool)
+		}
+		return err
 	}
 
 	if mcMutated != nil {
-		if err := r.createOrUpdateMachineConfig(mcMutated); err != nil {
+		if err := apply("MachineConfig", mcMutated, func() error { return r.createOrUpdateMachineConfig(mcMutated, profile.Name) }); err != nil {
 			return nil, err
 		}
 	}
 
 	if performanceTunedMutated != nil {
-		if err := r.createOrUpdateTuned(performanceTunedMutated, profile.Name); err != nil {
+		if err := apply("Tuned", performanceTunedMutated, func() error { return r.createOrUpdateTuned(performanceTunedMutated, profile.Name) }); err != nil {
 			return nil, err
 		}
 	}
 
 	if kcMutated != nil {
-		if err := r.createOrUpdateKubeletConfig(kcMutated); err != nil {
+		if err := apply("KubeletConfig", kcMutated, func() error { return r.createOrUpdateKubeletConfig(kcMutated) }); err != nil {
 			return nil, err
 		}
 	}
 
 	if runtimeClassMutated != nil {
-		if err := r.createOrUpdateRuntimeClass(runtimeClassMutated); err != nil {
+		if err := apply("RuntimeClass", runtimeClassMutated, func() error { return r.createOrUpdateRuntimeClass(runtimeClassMutated) }); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, obj := range extraMutated {
+		if err := apply(getGeneratedObjectKind(obj), obj, func() error { return r.createOrUpdateGeneratedObject(ctx, obj) }); err != nil {
 			return nil, err
 		}
 	}
@@ -704,6 +1053,72 @@ func (r *PerformanceProfileReconciler) applyComponents(profile *performancev2.Pe
 	return &reconcile.Result{}, nil
 }
 
+// getMutatedComponents renders the profile components and returns the ones that differ from the cluster state
+func (r *PerformanceProfileReconciler) getMutatedComponents(ctx context.Context, profile *performancev2.PerformanceProfile, opts *components.Options) (
+	*mcov1.MachineConfig, *mcov1.KubeletConfig, *tunedv1.Tuned, *nodev1.RuntimeClass, []client.Object, error) {
+	components, err := manifestset.GetNewComponents(profile, opts)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	for _, componentObj := range components.ToObjects() {
+		if err := controllerutil.SetControllerReference(profile, componentObj, r.Scheme); err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+	}
+
+	if operatorVersion := getOperatorVersion(); operatorVersion != "" {
+		for _, componentObj := range []metav1.Object{components.MachineConfig, components.KubeletConfig, components.Tuned} {
+			setArtifactVersions(componentObj, operatorVersion)
+		}
+		if components.RuntimeClass != nil {
+			setArtifactVersions(components.RuntimeClass, operatorVersion)
+		}
+		for _, componentObj := range components.Extra {
+			setArtifactVersions(componentObj, operatorVersion)
+		}
+	}
+
+	// get mutated machine config
+	mcMutated, err := r.getMutatedMachineConfig(ctx, components.MachineConfig)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	// get mutated kubelet config
+	kcMutated, err := r.getMutatedKubeletConfig(components.KubeletConfig)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	// keep the previous tuned profile generation until the nodes converge to the new one
+	if err := r.retainPreviousTunedProfiles(ctx, profile, components.Tuned); err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	// get mutated performance tuned
+	performanceTunedMutated, err := r.getMutatedTuned(components.Tuned)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	// get mutated RuntimeClass, unless the profile lets it be managed externally
+	var runtimeClassMutated *nodev1.RuntimeClass
+	if components.RuntimeClass != nil {
+		runtimeClassMutated, err = r.getMutatedRuntimeClass(components.RuntimeClass)
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+	}
+
+	// get the mutated objects of the generators registered on top of the built-in ones
+	extraMutated, err := r.getMutatedGeneratedObjects(ctx, components.Extra)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	return mcMutated, kcMutated, performanceTunedMutated, runtimeClassMutated, extraMutated, nil
+}
+
 func (r *PerformanceProfileReconciler) deleteComponents(profile *performancev2.PerformanceProfile) error {
 	tunedName := components.GetComponentName(profile.Name, components.ProfileNamePerformance)
 	if err := r.deleteTuned(tunedName, components.NamespaceNodeTuningOperator); err != nil {
@@ -715,15 +1130,29 @@ func (r *PerformanceProfileReconciler) deleteComponents(profile *performancev2.P
 		return err
 	}
 
-	if err := r.deleteRuntimeClass(name); err != nil {
-		return err
+	if !profileutil.IsGeneratedArtifactDisabled(profile, performancev2.GeneratedArtifactRuntimeClass) {
+		if err := r.deleteRuntimeClass(name); err != nil {
+			return err
+		}
 	}
 
 	if err := r.deleteMachineConfig(machineconfig.GetMachineConfigName(profile)); err != nil {
 		return err
 	}
 
-	return nil
+	if err := r.deleteMigrationComponents(profile); err != nil {
+		return err
+	}
+
+	if err := r.deletePoolResources(context.TODO(), profile, ""); err != nil {
+		return err
+	}
+
+	if err := r.deleteExportedArtifacts(context.TODO(), profile); err != nil {
+		return err
+	}
+
+	return r.deleteRollbackSnapshot(profile)
 }
 
 func (r *PerformanceProfileReconciler) isComponentsExist(profile *performancev2.PerformanceProfile) bool {
@@ -739,9 +1168,11 @@ func (r *PerformanceProfileReconciler) isComponentsExist(profile *performancev2.
 		return true
 	}
 
-	if _, err := r.getRuntimeClass(name); !k8serros.IsNotFound(err) {
-		klog.Infof("Runtime class %q exists under the cluster", name)
-		return true
+	if !profileutil.IsGeneratedArtifactDisabled(profile, performancev2.GeneratedArtifactRuntimeClass) {
+		if _, err := r.getRuntimeClass(name); !k8serros.IsNotFound(err) {
+			klog.Infof("Runtime class %q exists under the cluster", name)
+			return true
+		}
 	}
 
 	if _, err := r.getMachineConfig(context.TODO(), machineconfig.GetMachineConfigName(profile)); !k8serros.IsNotFound(err) {
@@ -749,11 +1180,35 @@ func (r *PerformanceProfileReconciler) isComponentsExist(profile *performancev2.
 		return true
 	}
 
-	return false
+	return r.isMigrationComponentsExist(profile)
+}
+
+// getManagedArtifacts returns the number of the profile components found in the cluster by kind
+func (r *PerformanceProfileReconciler) getManagedArtifacts(profile *performancev2.PerformanceProfile) map[string]int {
+	artifacts := map[string]int{}
+	count := func(kind string, err error) {
+		if err == nil {
+			artifacts[kind]++
+		}
+	}
+
+	tunedName := components.GetComponentName(profile.Name, components.ProfileNamePerformance)
+	_, err := r.getTuned(tunedName, components.NamespaceNodeTuningOperator)
+	count("Tuned", err)
+
+	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+	_, err = r.getKubeletConfig(name)
+	count("KubeletConfig", err)
+	_, err = r.getRuntimeClass(name)
+	count("RuntimeClass", err)
+
+	_, err = r.getMachineConfig(context.TODO(), machineconfig.GetMachineConfigName(profile))
+	count("MachineConfig", err)
+	return artifacts
 }
 
 func (r *PerformanceProfileReconciler) isMixedCPUsEnabled(profile *performancev2.PerformanceProfile) bool {
-	if !r.FeatureGate.Enabled(apiconfigv1.FeatureGateMixedCPUsAllocation) {
+	if components.IsFeatureDisabled(r.getDisabledFeatures(), components.ProfileFeatureMixedCPUs) {
 		return false
 	}
 	if config.InHyperShift() {
@@ -762,6 +1217,32 @@ func (r *PerformanceProfileReconciler) isMixedCPUsEnabled(profile *performancev2
 	return profileutil.IsMixedCPUsEnabled(profile)
 }
 
+// getDisabledFeatures returns the profile features whose cluster feature gate is disabled,
+// the feature gates unknown to the cluster count as disabled
+func (r *PerformanceProfileReconciler) getDisabledFeatures() []components.ProfileFeature {
+	known := make(map[apiconfigv1.FeatureGateName]bool)
+	for _, featureGate := range r.FeatureGate.KnownFeatures() {
+		known[featureGate] = true
+	}
+
+	var disabled []components.ProfileFeature
+	for feature, featureGate := range components.ProfileFeatureGates {
+		if !known[featureGate] || !r.FeatureGate.Enabled(featureGate) {
+			disabled = append(disabled, feature)
+		}
+	}
+	sort.Slice(disabled, func(i, j int) bool { return disabled[i] < disabled[j] })
+	return disabled
+}
+
+func getFeatureNames(features []components.ProfileFeature) []string {
+	var names []string
+	for _, feature := range features {
+		names = append(names, string(feature))
+	}
+	return names
+}
+
 func hasFinalizer(profile *performancev2.PerformanceProfile, finalizer string) bool {
 	for _, f := range profile.Finalizers {
 		if f == finalizer {
@@ -790,10 +1271,15 @@ func namespacedName(obj metav1.Object) types.NamespacedName {
 }
 
 func (r *PerformanceProfileReconciler) getMachineConfigPoolByProfile(ctx context.Context, profile *performancev2.PerformanceProfile) (*mcov1.MachineConfigPool, error) {
+	return getMachineConfigPoolByProfile(ctx, r.Client, profile)
+}
+
+// getMachineConfigPoolByProfile returns the only machine config pool selecting the nodes of the profile
+func getMachineConfigPoolByProfile(ctx context.Context, cli client.Client, profile *performancev2.PerformanceProfile) (*mcov1.MachineConfigPool, error) {
 	nodeSelector := labels.Set(profile.Spec.NodeSelector)
 
 	mcpList := &mcov1.MachineConfigPoolList{}
-	if err := r.Client.List(ctx, mcpList); err != nil {
+	if err := cli.List(ctx, mcpList); err != nil {
 		return nil, err
 	}
 
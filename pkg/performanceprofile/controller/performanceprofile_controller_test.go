@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -20,23 +23,30 @@ import (
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/kubeletconfig"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/machineconfig"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/manifestset"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/poolresources"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/runtimeclass"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/tuned"
 	testutils "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/utils/testing"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/tracing/tracingtest"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
 	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
 	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 
 	corev1 "k8s.io/api/core/v1"
 	nodev1 "k8s.io/api/node/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
 	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
+	testingclock "k8s.io/utils/clock/testing"
 	"k8s.io/utils/pointer"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -46,6 +56,9 @@ import (
 )
 
 var (
+	// the generators are registered once per process
+	registerTestGenerator sync.Once
+
 	kernelArgsv1 []string = []string{
 		"systemd.unified_cgroup_hierarchy=0",
 		"systemd.legacy_systemd_cgroup_controller=1",
@@ -218,6 +231,31 @@ var _ = Describe("Controller", func() {
 			Expect(degradedCondition.Reason).To(Equal(conditionKubeletFailed))
 		})
 
+		It("should set the profile expecting another CPU partitioning mode as degraded", func() {
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileCPUPartitioningModeAnnotation: string(apiconfigv1.CPUPartitioningAllNodes),
+			}
+
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			updatedProfile := &performancev2.PerformanceProfile{}
+			key := types.NamespacedName{
+				Name:      profile.Name,
+				Namespace: metav1.NamespaceNone,
+			}
+			Expect(r.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+
+			degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+			Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(degradedCondition.Reason).To(Equal(conditionReasonCPUPartitioningMismatch))
+
+			// no components are created for the contradicting profile
+			mc := &mcov1.MachineConfig{}
+			key.Name = machineconfig.GetMachineConfigName(profile)
+			Expect(errors.IsNotFound(r.Get(context.TODO(), key, mc))).To(BeTrue())
+		})
+
 		It("should not promote old failure condition", func() {
 			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
 			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
@@ -307,6 +345,37 @@ var _ = Describe("Controller", func() {
 			Expect(tunedList.Items[0].Name).To(Equal(tunedName))
 		})
 
+		It("should remove outdated machine config when the name suffix changes", func() {
+			mcOutdated, err := machineconfig.New(profile, &components.MachineConfigOptions{PinningMode: &infra.Status.CPUPartitioning})
+			Expect(err).ToNot(HaveOccurred())
+			mcOutdated.OwnerReferences = []metav1.OwnerReference{
+				{Kind: "PerformanceProfile", Name: profile.Name},
+			}
+
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileMachineConfigNameSuffixAnnotation: "ztp-performance",
+				performancev2.PerformanceProfileMachineConfigLabelsAnnotation:     `{"ran.openshift.io/ztp-deploy-wave": "10"}`,
+			}
+			r := newFakeReconciler(profile, mcOutdated, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			mc := &mcov1.MachineConfig{}
+			err = r.Get(context.TODO(), types.NamespacedName{Name: mcOutdated.Name}, mc)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			key := types.NamespacedName{
+				Name:      "50-ztp-performance",
+				Namespace: metav1.NamespaceNone,
+			}
+			Expect(r.Get(context.TODO(), key, mc)).ToNot(HaveOccurred())
+			Expect(mc.Labels).To(HaveKeyWithValue("ran.openshift.io/ztp-deploy-wave", "10"))
+			Expect(mc.Labels).To(HaveKeyWithValue(testutils.MachineConfigLabelKey, testutils.MachineConfigLabelValue))
+
+			// the machine config that is not owned by the profile should stay untouched
+			Expect(r.Get(context.TODO(), types.NamespacedName{Name: profileMC.Name}, mc)).ToNot(HaveOccurred())
+		})
+
 		It("should create nothing when pause annotation is set", func() {
 			profile.Annotations = map[string]string{performancev2.PerformanceProfilePauseAnnotation: "true"}
 			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
@@ -438,6 +507,54 @@ var _ = Describe("Controller", func() {
 				Expect(*t.Spec.Profile[0].Data).To(ContainSubstring("isolated_cores=" + string(*profile.Spec.CPU.Isolated)))
 			})
 
+			It("should keep the previous tuned profile generation until the nodes converge", func() {
+				previousName := *tunedPerformance.Spec.Profile[0].Name
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "node-test",
+						Labels: profile.Spec.NodeSelector,
+					},
+				}
+				tunedProfile := &tunedv1.Profile{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      node.Name,
+						Namespace: components.NamespaceNodeTuningOperator,
+					},
+					Status: tunedv1.ProfileStatus{
+						TunedProfile: previousName,
+						Conditions: []tunedv1.ProfileStatusCondition{
+							{
+								Type:   tunedv1.TunedProfileApplied,
+								Status: corev1.ConditionTrue,
+							},
+						},
+					},
+				}
+
+				isolated := performancev2.CPUSet("2-3")
+				profile.Spec.CPU.Isolated = &isolated
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, profileMCP, infra, clusterOperator, nodeConfig, profileMC, node, tunedProfile)
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				t := &tunedv1.Tuned{}
+				key := types.NamespacedName{Name: tunedPerformance.Name, Namespace: components.NamespaceNodeTuningOperator}
+				Expect(r.Get(context.TODO(), key, t)).ToNot(HaveOccurred())
+				// the real time profile content did not change, only the previous performance profile is kept
+				Expect(t.Spec.Profile).To(HaveLen(4))
+				newName := *t.Spec.Recommend[0].Profile
+				Expect(newName).ToNot(Equal(previousName))
+				Expect(*t.Spec.Profile[3].Name).To(Equal(previousName))
+
+				By("Converging the node to the new generation merged with a supplemental profile")
+				tunedProfile.Status.TunedProfile = newName + " sriov-supplemental"
+				Expect(r.Update(context.TODO(), tunedProfile)).ToNot(HaveOccurred())
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				Expect(r.Get(context.TODO(), key, t)).ToNot(HaveOccurred())
+				Expect(t.Spec.Profile).To(HaveLen(3))
+				Expect(*t.Spec.Profile[0].Name).To(Equal(newName))
+			})
+
 			It("should add isolcpus with managed_irq flag to tuned profile when balanced set to true", func() {
 				reserved := performancev2.CPUSet("0-1")
 				isolated := performancev2.CPUSet("2-3")
@@ -608,6 +725,380 @@ var _ = Describe("Controller", func() {
 				Expect(*updatedProfile.Status.RuntimeClass).To(Equal(runtimeClass.Name))
 			})
 
+			It("should update status with the rollout progress", func() {
+				profileMCP.Spec.Configuration.Source = []corev1.ObjectReference{{Name: mc.Name}}
+				profileMCP.Status.MachineCount = 3
+				profileMCP.Status.UpdatedMachineCount = 1
+				profileMCP.Status.DegradedMachineCount = 1
+
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev2.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceAll,
+				}
+				Expect(r.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+				Expect(updatedProfile.Status.Rollout).To(Equal(&performancev2.RolloutStatus{
+					MachineConfig:     mc.Name,
+					MachineConfigPool: profileMCP.Name,
+					TotalNodes:        3,
+					UpdatedNodes:      1,
+					PendingNodes:      2,
+					DegradedNodes:     1,
+				}))
+			})
+
+			It("should keep the previous pool components while the profile moves to a new pool", func() {
+				profile.Status.Rollout = &performancev2.RolloutStatus{MachineConfigPool: "previous"}
+
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+				Expect(reconcileTimes(r, request, 2)).To(Equal(reconcile.Result{}))
+
+				mcCopy := &mcov1.MachineConfig{}
+				Expect(r.Get(context.TODO(), types.NamespacedName{Name: mc.Name + migrationNameSuffix}, mcCopy)).ToNot(HaveOccurred())
+				Expect(mcCopy.Labels).To(HaveKeyWithValue(migrationSourcePoolLabel, "previous"))
+				Expect(mcCopy.Spec.KernelArguments).To(Equal(mc.Spec.KernelArguments))
+
+				kcCopy := &mcov1.KubeletConfig{}
+				Expect(r.Get(context.TODO(), types.NamespacedName{Name: kc.Name + migrationNameSuffix}, kcCopy)).ToNot(HaveOccurred())
+				Expect(kcCopy.Spec.MachineConfigPoolSelector).To(Equal(kc.Spec.MachineConfigPoolSelector))
+
+				tunedCopy := &tunedv1.Tuned{}
+				key := types.NamespacedName{Name: tunedPerformance.Name + migrationNameSuffix, Namespace: components.NamespaceNodeTuningOperator}
+				Expect(r.Get(context.TODO(), key, tunedCopy)).ToNot(HaveOccurred())
+				Expect(tunedCopy.Spec.Profile).To(Equal(tunedPerformance.Spec.Profile))
+
+				updatedProfile := &performancev2.PerformanceProfile{}
+				key = types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceAll,
+				}
+				Expect(r.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+				Expect(updatedProfile.Status.Rollout.MachineConfigPool).To(Equal(profileMCP.Name))
+				migratingCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionTypeMigrating)
+				Expect(migratingCondition).ToNot(BeNil())
+				Expect(migratingCondition.Status).To(Equal(corev1.ConditionTrue))
+				Expect(migratingCondition.Reason).To(Equal(conditionReasonNodeSelectorChanged))
+			})
+
+			It("should remove the previous pool components once the new pool is updated", func() {
+				profile.Status.Rollout = &performancev2.RolloutStatus{MachineConfigPool: "previous"}
+
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				mcp := &mcov1.MachineConfigPool{}
+				Expect(r.Get(context.TODO(), types.NamespacedName{Name: profileMCP.Name}, mcp)).ToNot(HaveOccurred())
+				mcp.Spec.Configuration.Name = profileMC.Name
+				mcp.Spec.Configuration.Source = []corev1.ObjectReference{{Name: mc.Name}}
+				mcp.Status.Configuration.Name = profileMC.Name
+				mcp.Status.MachineCount = 3
+				mcp.Status.UpdatedMachineCount = 3
+				Expect(r.Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				err := r.Get(context.TODO(), types.NamespacedName{Name: mc.Name + migrationNameSuffix}, &mcov1.MachineConfig{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+
+				err = r.Get(context.TODO(), types.NamespacedName{Name: kc.Name + migrationNameSuffix}, &mcov1.KubeletConfig{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+
+				key := types.NamespacedName{Name: tunedPerformance.Name + migrationNameSuffix, Namespace: components.NamespaceNodeTuningOperator}
+				err = r.Get(context.TODO(), key, &tunedv1.Tuned{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+
+				updatedProfile := &performancev2.PerformanceProfile{}
+				key = types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceAll,
+				}
+				Expect(r.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+				Expect(conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionTypeMigrating)).To(BeNil())
+			})
+
+			Context("with the automatic rollback", func() {
+				var r *PerformanceProfileReconciler
+
+				// updatePool reports the machine config pool targeting the rendered configuration
+				updatePool := func(renderedConfig string, updated, degraded int32) {
+					mcp := &mcov1.MachineConfigPool{}
+					ExpectWithOffset(1, r.Get(context.TODO(), types.NamespacedName{Name: profileMCP.Name}, mcp)).ToNot(HaveOccurred())
+					mcp.Spec.Configuration.Name = renderedConfig
+					mcp.Spec.Configuration.Source = []corev1.ObjectReference{{Name: mc.Name}}
+					if updated == 3 {
+						mcp.Status.Configuration.Name = renderedConfig
+					}
+					mcp.Status.MachineCount = 3
+					mcp.Status.UpdatedMachineCount = updated
+					mcp.Status.DegradedMachineCount = degraded
+					ExpectWithOffset(1, r.Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+				}
+
+				// updateProfileCPUs changes the profile CPUs, so all the components are rendered again
+				updateProfileCPUs := func() {
+					updatedProfile := &performancev2.PerformanceProfile{}
+					ExpectWithOffset(1, r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+					reserved := performancev2.CPUSet("0-1")
+					isolated := performancev2.CPUSet("2-3")
+					updatedProfile.Spec.CPU = &performancev2.CPU{
+						Reserved: &reserved,
+						Isolated: &isolated,
+					}
+					updatedProfile.Generation++
+					ExpectWithOffset(1, r.Update(context.TODO(), updatedProfile)).ToNot(HaveOccurred())
+				}
+
+				getMachineConfigSpec := func() mcov1.MachineConfigSpec {
+					updatedMC := &mcov1.MachineConfig{}
+					ExpectWithOffset(1, r.Get(context.TODO(), types.NamespacedName{Name: mc.Name}, updatedMC)).ToNot(HaveOccurred())
+					return updatedMC.Spec
+				}
+
+				BeforeEach(func() {
+					profile.Generation = 1
+					profile.Annotations = map[string]string{
+						performancev2.PerformanceProfileAutoRollbackAnnotation: "{}",
+					}
+
+					r = newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+					updatePool(profileMC.Name, 3, 0)
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+				})
+
+				It("should snapshot the components once the pool runs them", func() {
+					cm := &corev1.ConfigMap{}
+					key := types.NamespacedName{Name: getRollbackName(profile), Namespace: components.NamespaceNodeTuningOperator}
+					Expect(r.Get(context.TODO(), key, cm)).ToNot(HaveOccurred())
+					Expect(cm.Annotations).To(HaveKeyWithValue(rollbackProfileGenerationAnnotation, "1"))
+					Expect(cm.Annotations).To(HaveKeyWithValue(rollbackRenderedConfigAnnotation, profileMC.Name))
+					Expect(cm.Data).To(HaveKey(rollbackMachineConfigKey))
+					Expect(cm.Data).To(HaveKey(rollbackKubeletConfigKey))
+					Expect(cm.Data).To(HaveKey(rollbackTunedKey))
+				})
+
+				It("should restore the snapshot when the rollout degrades the pool", func() {
+					mcSpec := getMachineConfigSpec()
+
+					updateProfileCPUs()
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+					Expect(getMachineConfigSpec()).ToNot(Equal(mcSpec))
+
+					updatePool("rendered-test-2", 1, 1)
+					Expect(reconcileTimes(r, request, 2)).To(Equal(reconcile.Result{}))
+					Expect(getMachineConfigSpec()).To(Equal(mcSpec))
+
+					updatedProfile := &performancev2.PerformanceProfile{}
+					Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+					Expect(updatedProfile.Status.Rollback).ToNot(BeNil())
+					Expect(updatedProfile.Status.Rollback.ProfileGeneration).To(Equal(int64(2)))
+					Expect(updatedProfile.Status.Rollback.RestoredProfileGeneration).To(Equal(int64(1)))
+					Expect(updatedProfile.Status.Rollback.Reason).To(Equal(conditionReasonMCPDegraded))
+					rolledBackCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionTypeRolledBack)
+					Expect(rolledBackCondition).ToNot(BeNil())
+					Expect(rolledBackCondition.Status).To(Equal(corev1.ConditionTrue))
+					Expect(rolledBackCondition.Message).To(ContainSubstring("rendered-test-2"))
+				})
+
+				It("should restore the snapshot when the tuned profiles are degraded", func() {
+					tunedProfile := &tunedv1.Profile{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "node-test",
+						},
+						Status: tunedv1.ProfileStatus{
+							Conditions: []tunedv1.ProfileStatusCondition{
+								{
+									Type:   tunedv1.TunedDegraded,
+									Status: corev1.ConditionTrue,
+									Reason: "TunedError",
+								},
+								{
+									Type:   tunedv1.TunedProfileApplied,
+									Status: corev1.ConditionFalse,
+									Reason: "TunedError",
+								},
+							},
+						},
+					}
+					node := &corev1.Node{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "node-test",
+							Labels: map[string]string{"nodekey": "nodeValue"},
+						},
+					}
+
+					updateProfileCPUs()
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+					Expect(r.Create(context.TODO(), node)).ToNot(HaveOccurred())
+					Expect(r.Create(context.TODO(), tunedProfile)).ToNot(HaveOccurred())
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+					t := &tunedv1.Tuned{}
+					key := types.NamespacedName{Name: tunedPerformance.Name, Namespace: components.NamespaceNodeTuningOperator}
+					Expect(r.Get(context.TODO(), key, t)).ToNot(HaveOccurred())
+					Expect(*t.Spec.Profile[0].Data).ToNot(ContainSubstring("isolated_cores=2-3"))
+
+					updatedProfile := &performancev2.PerformanceProfile{}
+					Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+					Expect(updatedProfile.Status.Rollback).ToNot(BeNil())
+					Expect(updatedProfile.Status.Rollback.Reason).To(Equal(conditionReasonTunedDegraded))
+					Expect(updatedProfile.Status.Rollback.Message).To(ContainSubstring("node-test"))
+				})
+
+				It("should render the components again once the profile changes", func() {
+					updateProfileCPUs()
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+					updatePool("rendered-test-2", 1, 1)
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+					mcSpec := getMachineConfigSpec()
+
+					// the pool runs the restored components again
+					updatePool(profileMC.Name, 3, 0)
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+					Expect(getMachineConfigSpec()).To(Equal(mcSpec))
+
+					updatedProfile := &performancev2.PerformanceProfile{}
+					Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+					updatedProfile.Spec.RealTimeKernel = &performancev2.RealTimeKernel{Enabled: pointer.Bool(false)}
+					updatedProfile.Generation++
+					Expect(r.Update(context.TODO(), updatedProfile)).ToNot(HaveOccurred())
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+					Expect(getMachineConfigSpec()).ToNot(Equal(mcSpec))
+
+					Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+					Expect(conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionTypeRolledBack)).To(BeNil())
+				})
+
+				It("should not restore the snapshot without the annotation", func() {
+					updatedProfile := &performancev2.PerformanceProfile{}
+					Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+					delete(updatedProfile.Annotations, performancev2.PerformanceProfileAutoRollbackAnnotation)
+					Expect(r.Update(context.TODO(), updatedProfile)).ToNot(HaveOccurred())
+
+					updateProfileCPUs()
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+					mcSpec := getMachineConfigSpec()
+					updatePool("rendered-test-2", 1, 1)
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+					Expect(getMachineConfigSpec()).To(Equal(mcSpec))
+
+					Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+					Expect(updatedProfile.Status.Rollback).To(BeNil())
+				})
+			})
+
+			Context("with an operator upgrade", func() {
+				const previousVersion = "4.15.0"
+				const currentVersion = "4.16.0"
+
+				BeforeEach(func() {
+					Expect(os.Setenv("RELEASE_VERSION", currentVersion)).To(Succeed())
+					DeferCleanup(os.Unsetenv, "RELEASE_VERSION")
+					clusterOperator.Status.Versions[0].Version = currentVersion
+
+					mc.Annotations = map[string]string{components.OperatorVersionAnnotation: previousVersion}
+					kc.Annotations = map[string]string{components.OperatorVersionAnnotation: previousVersion}
+					profileMCP.Spec.Configuration.Name = "rendered-test"
+
+					// the new operator renders the machine config differently
+					profile.Spec.RealTimeKernel.Enabled = pointer.Bool(false)
+				})
+
+				getMachineConfig := func(r *PerformanceProfileReconciler) *mcov1.MachineConfig {
+					updatedMC := &mcov1.MachineConfig{}
+					ExpectWithOffset(1, r.Get(context.TODO(), types.NamespacedName{Name: mc.Name}, updatedMC)).ToNot(HaveOccurred())
+					return updatedMC
+				}
+
+				It("should record the versions and roll out the re-rendered components", func() {
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+					updatedMC := getMachineConfig(r)
+					Expect(updatedMC.Spec.KernelType).To(Equal(machineconfig.MCKernelDefault))
+					Expect(updatedMC.Annotations).To(HaveKeyWithValue(components.OperatorVersionAnnotation, currentVersion))
+					Expect(updatedMC.Annotations).To(HaveKeyWithValue(components.ArtifactSchemaVersionAnnotation, "1"))
+					Expect(updatedMC.Annotations).To(HaveKeyWithValue(upgradeSourceConfigAnnotation, "rendered-test"))
+
+					updatedTuned := &tunedv1.Tuned{}
+					key := types.NamespacedName{Name: tunedPerformance.Name, Namespace: components.NamespaceNodeTuningOperator}
+					Expect(r.Get(context.TODO(), key, updatedTuned)).ToNot(HaveOccurred())
+					Expect(updatedTuned.Annotations).To(HaveKeyWithValue(components.OperatorVersionAnnotation, currentVersion))
+				})
+
+				It("should postpone the re-rendered components while another pool rolls out its upgrade", func() {
+					otherProfile := testutils.NewPerformanceProfile("other")
+					otherProfile.UID = "22222222-2222-2222-2222-2222222222222"
+					otherProfile.Status.Rollout = &performancev2.RolloutStatus{MachineConfigPool: "other"}
+
+					otherMC, err := machineconfig.New(otherProfile, &components.MachineConfigOptions{PinningMode: &infra.Status.CPUPartitioning})
+					Expect(err).ToNot(HaveOccurred())
+					otherMC.Annotations = map[string]string{upgradeSourceConfigAnnotation: "rendered-other-1"}
+
+					otherMCP := testutils.NewProfileMCP()
+					otherMCP.Name = "other"
+					otherMCP.Labels = map[string]string{"pool": "other"}
+					otherMCP.Spec.NodeSelector.MatchLabels = map[string]string{"pool": "other"}
+					otherMCP.Spec.Configuration.Name = "rendered-other-1"
+
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, profileMCP, infra, clusterOperator, nodeConfig, profileMC, otherProfile, otherMC, otherMCP)
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{RequeueAfter: upgradeRolloutRequeueInterval}))
+
+					updatedMC := getMachineConfig(r)
+					Expect(updatedMC.Spec.KernelType).To(Equal(machineconfig.MCKernelRT))
+					Expect(updatedMC.Annotations).To(HaveKeyWithValue(components.OperatorVersionAnnotation, previousVersion))
+
+					// the other pool rendered the upgraded configuration and all its machines run it
+					Expect(r.Get(context.TODO(), types.NamespacedName{Name: otherMCP.Name}, otherMCP)).ToNot(HaveOccurred())
+					otherMCP.Spec.Configuration.Name = "rendered-other-2"
+					otherMCP.Status.Configuration.Name = "rendered-other-2"
+					Expect(r.Update(context.TODO(), otherMCP)).ToNot(HaveOccurred())
+
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+					Expect(getMachineConfig(r).Spec.KernelType).To(Equal(machineconfig.MCKernelDefault))
+				})
+
+				It("should postpone the re-rendered components while the pool has unavailable machines", func() {
+					profileMCP.Status.MachineCount = 3
+					profileMCP.Status.UnavailableMachineCount = 1
+
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{RequeueAfter: upgradeRolloutRequeueInterval}))
+					Expect(getMachineConfig(r).Spec.KernelType).To(Equal(machineconfig.MCKernelRT))
+				})
+
+				It("should not overwrite components generated with a newer artifact schema", func() {
+					mc.Annotations[components.ArtifactSchemaVersionAnnotation] = strconv.Itoa(components.ArtifactSchemaVersion + 1)
+
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+					_, err := r.Reconcile(context.TODO(), request)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("newer than the supported version"))
+					Expect(getMachineConfig(r).Spec.KernelType).To(Equal(machineconfig.MCKernelRT))
+				})
+			})
+
+			It("should report all nodes as pending until the machine config is rendered", func() {
+				profileMCP.Status.MachineCount = 3
+				profileMCP.Status.UpdatedMachineCount = 3
+
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev2.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceAll,
+				}
+				Expect(r.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+				Expect(updatedProfile.Status.Rollout).NotTo(BeNil())
+				Expect(updatedProfile.Status.Rollout.PendingNodes).To(Equal(int32(3)))
+				Expect(updatedProfile.Status.Rollout.UpdatedNodes).To(Equal(int32(0)))
+			})
+
 			It("should update status when MCP is degraded", func() {
 				mcpReason := "mcpReason"
 				mcpMessage := "MCP message"
@@ -702,6 +1193,10 @@ var _ = Describe("Controller", func() {
 					},
 				}
 
+				// the node rebooted into the pool configuration, no reboot is pending
+				profileMCP.Spec.Configuration.Name = "rendered-performance"
+				profileMCP.Spec.Configuration.Source = []corev1.ObjectReference{{Name: mc.Name}}
+
 				nodes := &corev1.NodeList{
 					Items: []corev1.Node{
 						{
@@ -710,6 +1205,11 @@ var _ = Describe("Controller", func() {
 								Labels: map[string]string{
 									"nodekey": "nodeValue",
 								},
+								Annotations: map[string]string{
+									mcdCurrentConfigAnnotation: "rendered-performance",
+									mcdDesiredConfigAnnotation: "rendered-performance",
+									mcdStateAnnotation:         mcdStateDone,
+								},
 							},
 						},
 						{
@@ -741,6 +1241,185 @@ var _ = Describe("Controller", func() {
 				Expect(degradedCondition.Reason).To(Equal(conditionReasonTunedDegraded))
 				Expect(degradedCondition.Message).To(ContainSubstring(tunedMessage))
 			})
+
+			When("the profile nodes did not reboot into the generated machine config", func() {
+				newNode := func(name, currentConfig string) *corev1.Node {
+					return &corev1.Node{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   name,
+							Labels: map[string]string{"nodekey": "nodeValue"},
+							Annotations: map[string]string{
+								mcdCurrentConfigAnnotation: currentConfig,
+								mcdDesiredConfigAnnotation: currentConfig,
+								mcdStateAnnotation:         mcdStateDone,
+							},
+						},
+					}
+				}
+
+				getRebootPending := func(r *PerformanceProfileReconciler) *conditionsv1.Condition {
+					updatedProfile := &performancev2.PerformanceProfile{}
+					key := types.NamespacedName{
+						Name:      profile.Name,
+						Namespace: metav1.NamespaceNone,
+					}
+					Expect(r.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+					return conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionTypeRebootPending)
+				}
+
+				It("should report all nodes pending until the machine config is rendered", func() {
+					profileMCP.Spec.Configuration.Name = "rendered-old"
+
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, newNode("node-a", "rendered-old"), newNode("node-b", "rendered-old"), profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+					rebootPending := getRebootPending(r)
+					Expect(rebootPending).ToNot(BeNil())
+					Expect(rebootPending.Status).To(Equal(corev1.ConditionTrue))
+					Expect(rebootPending.Reason).To(Equal(conditionReasonMachineConfigNotRendered))
+					Expect(rebootPending.Message).To(ContainSubstring("2 nodes will reboot"))
+				})
+
+				It("should report the nodes running a previous configuration", func() {
+					profileMCP.Spec.Configuration.Name = "rendered-new"
+					profileMCP.Spec.Configuration.Source = []corev1.ObjectReference{{Name: mc.Name}}
+
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, newNode("node-a", "rendered-new"), newNode("node-b", "rendered-old"), profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+					rebootPending := getRebootPending(r)
+					Expect(rebootPending).ToNot(BeNil())
+					Expect(rebootPending.Reason).To(Equal(conditionReasonNodesNotRebooted))
+					Expect(rebootPending.Message).To(ContainSubstring("1 of 2 nodes"))
+					Expect(rebootPending.Message).To(ContainSubstring("node-b"))
+					Expect(rebootPending.Message).ToNot(ContainSubstring("node-a"))
+				})
+
+				It("should remove the condition once all nodes rebooted", func() {
+					profileMCP.Spec.Configuration.Name = "rendered-new"
+					profileMCP.Spec.Configuration.Source = []corev1.ObjectReference{{Name: mc.Name}}
+
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, newNode("node-a", "rendered-new"), newNode("node-b", "rendered-new"), profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+					Expect(getRebootPending(r)).To(BeNil())
+				})
+			})
+
+			When("the profile nodes report their kubelet configuration", func() {
+				newNode := func(name, currentConfig string, kubeletConfig []byte) *corev1.Node {
+					node := &corev1.Node{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   name,
+							Labels: map[string]string{"nodekey": "nodeValue"},
+							Annotations: map[string]string{
+								mcdCurrentConfigAnnotation: currentConfig,
+								mcdDesiredConfigAnnotation: currentConfig,
+								mcdStateAnnotation:         mcdStateDone,
+							},
+						},
+					}
+					if kubeletConfig != nil {
+						checksums, err := util.KubeletConfigChecksums(kubeletConfig)
+						Expect(err).ToNot(HaveOccurred())
+						value, err := json.Marshal(checksums)
+						Expect(err).ToNot(HaveOccurred())
+						node.Annotations[tunedv1.TunedKubeletConfigChecksumsAnnotationKey] = string(value)
+					}
+					return node
+				}
+
+				getKubeletConfigSync := func(r *PerformanceProfileReconciler) *performancev2.KubeletConfigSyncStatus {
+					updatedProfile := &performancev2.PerformanceProfile{}
+					key := types.NamespacedName{
+						Name:      profile.Name,
+						Namespace: metav1.NamespaceNone,
+					}
+					Expect(r.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+					return updatedProfile.Status.KubeletConfigSync
+				}
+
+				BeforeEach(func() {
+					profileMCP.Spec.Configuration.Name = "rendered-new"
+					profileMCP.Spec.Configuration.Source = []corev1.ObjectReference{{Name: mc.Name}}
+				})
+
+				It("should report the nodes running the generated kubelet config", func() {
+					stale := []byte(`{"cpuManagerPolicy":"none"}`)
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance,
+						newNode("node-a", "rendered-new", kc.Spec.KubeletConfig.Raw),
+						newNode("node-b", "rendered-new", stale),
+						newNode("node-c", "rendered-new", nil),
+						newNode("node-d", "rendered-old", kc.Spec.KubeletConfig.Raw),
+						profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+					kubeletConfigSync := getKubeletConfigSync(r)
+					Expect(kubeletConfigSync).ToNot(BeNil())
+					Expect(kubeletConfigSync.KubeletConfig).To(Equal(kc.Name))
+					Expect(kubeletConfigSync.TotalNodes).To(Equal(int32(4)))
+					Expect(kubeletConfigSync.SyncedNodes).To(Equal(int32(1)))
+					Expect(kubeletConfigSync.Nodes).To(HaveLen(4))
+					Expect(kubeletConfigSync.Nodes[0]).To(Equal(performancev2.NodeKubeletConfigSync{Name: "node-a", Synced: true}))
+					Expect(kubeletConfigSync.Nodes[1].Reason).To(HavePrefix(kubeletConfigSyncReasonChecksumMismatch))
+					Expect(kubeletConfigSync.Nodes[2].Reason).To(Equal(kubeletConfigSyncReasonChecksumsMissing))
+					Expect(kubeletConfigSync.Nodes[3].Reason).To(Equal(kubeletConfigSyncReasonMachineConfigPending))
+				})
+
+				It("should enqueue the profiles of the node", func() {
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+
+					requests := r.nodeToPerformanceProfile(context.TODO(), newNode("node-a", "rendered-new", nil))
+					Expect(requests).To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Name: profile.Name}}))
+				})
+			})
+
+			It("should report the nodes which did not allocate the runtime huge pages", func() {
+				runtimeAllocation := performancev2.HugePageAllocationRuntime
+				profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, performancev2.HugePage{
+					Size:       "2M",
+					Count:      128,
+					Node:       pointer.Int32(0),
+					Allocation: &runtimeAllocation,
+				})
+
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "node-a",
+						Labels: map[string]string{"nodekey": "nodeValue"},
+					},
+				}
+				tunedProfile := &tunedv1.Profile{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "node-a",
+					},
+					Status: tunedv1.ProfileStatus{
+						Conditions: []tunedv1.ProfileStatusCondition{
+							{
+								Type:    tunedv1.TunedHugepagesAllocated,
+								Status:  corev1.ConditionFalse,
+								Reason:  "Fragmented",
+								Message: "The memory is too fragmented, allocated only 100 of 128 2M huge pages on the NUMA node 0.",
+							},
+						},
+					},
+				}
+
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, node, tunedProfile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev2.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+
+				hugepagesPending := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionTypeHugepagesPending)
+				Expect(hugepagesPending).ToNot(BeNil())
+				Expect(hugepagesPending.Reason).To(Equal(conditionReasonHugepagesNotAllocated))
+				Expect(hugepagesPending.Message).To(ContainSubstring("node-a: The memory is too fragmented"))
+			})
 		})
 
 		When("the provided machine config labels are different from one specified under the machine config pool", func() {
@@ -973,6 +1652,55 @@ var _ = Describe("Controller", func() {
 			message := strings.ToLower(availableCondition.Message)
 			Expect(message).To(ContainSubstring("cgroup=v1")) // quite ugly. Should we add an explicit condition?
 		})
+
+		When("the profile isolates the CPUs with a cgroup partition", func() {
+			BeforeEach(func() {
+				isolationMethod := performancev2.CPUIsolationMethodCgroupPartition
+				profile.Spec.CPU.IsolationMethod = &isolationMethod
+				profileMC = testutils.NewProfileMachineConfig("test", []string{})
+			})
+
+			It("should not switch the cluster to cgroup v1", func() {
+				nodeConfig = testutils.NewNodeConfig(apiconfigv1.CgroupModeEmpty)
+
+				r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+				Expect(reconcileTimes(r, request, 2)).To(Equal(reconcile.Result{}))
+
+				updatedNodeConfig := &apiconfigv1.Node{}
+				Expect(r.Get(context.TODO(), types.NamespacedName{Name: nodeCfgName}, updatedNodeConfig)).To(Succeed())
+				Expect(updatedNodeConfig.Spec.CgroupMode).To(Equal(apiconfigv1.CgroupModeEmpty))
+
+				updatedProfile := &performancev2.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.Get(context.TODO(), key, updatedProfile)).To(Succeed())
+
+				availableCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionAvailable)
+				Expect(availableCondition).ToNot(BeNil())
+				Expect(availableCondition.Status).To(Equal(corev1.ConditionTrue))
+				Expect(strings.ToLower(availableCondition.Message)).To(ContainSubstring("cgroup=v2"))
+			})
+
+			It("should set performance profile as degraded while the cluster runs cgroup v1", func() {
+				nodeConfig = testutils.NewNodeConfig(apiconfigv1.CgroupModeV1)
+
+				r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+				Expect(reconcileTimes(r, request, 2)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev2.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.Get(context.TODO(), key, updatedProfile)).To(Succeed())
+
+				degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+				Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+				Expect(degradedCondition.Reason).To(Equal(conditionReasonCgroupsV2NotEnabled))
+			})
+		})
 	})
 
 	Context("with infrastructure cpuPartitioning", func() {
@@ -1137,6 +1865,590 @@ var _ = Describe("Controller", func() {
 
 	})
 
+	Context("with feature gated profile features", func() {
+		It("should report the features skipped due to the disabled feature gates", func() {
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			profile.Annotations = map[string]string{
+				"kubeletconfig.experimental": `{"failSwapOn": false, "memorySwap": {"swapBehavior": "LimitedSwap"}}`,
+			}
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			updatedProfile := &performancev2.PerformanceProfile{}
+			Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			// the test profile requests the shared CPUs, and the fake feature gates disable the mixed CPUs allocation
+			Expect(updatedProfile.Status.SkippedFeatures).To(ConsistOf(string(components.ProfileFeatureNodeSwap), string(components.ProfileFeatureMixedCPUs)))
+
+			kc := &mcov1.KubeletConfig{}
+			key := types.NamespacedName{Name: components.GetComponentName(profile.Name, components.ComponentNamePrefix)}
+			Expect(r.Get(context.TODO(), key, kc)).ToNot(HaveOccurred())
+			Expect(string(kc.Spec.KubeletConfig.Raw)).ToNot(ContainSubstring("LimitedSwap"))
+		})
+	})
+
+	Context("with pool resources", func() {
+		It("should publish the pool resources of the profile", func() {
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			cm := &corev1.ConfigMap{}
+			key := types.NamespacedName{
+				Name:      poolresources.Name(profileMCP.Name),
+				Namespace: components.NamespaceNodeTuningOperator,
+			}
+			Expect(r.Get(context.TODO(), key, cm)).ToNot(HaveOccurred())
+			Expect(cm.Data).To(HaveKeyWithValue(poolresources.KeyProfile, profile.Name))
+			Expect(cm.Data).To(HaveKeyWithValue(poolresources.KeyReservedCPUs, string(*profile.Spec.CPU.Reserved)))
+			Expect(cm.Data).To(HaveKeyWithValue(poolresources.KeyIsolatedCPUs, string(*profile.Spec.CPU.Isolated)))
+		})
+	})
+
+	Context("with the profile summary", func() {
+		It("should annotate the profile with its summary", func() {
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			updatedProfile := &performancev2.PerformanceProfile{}
+			Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			Expect(updatedProfile.Annotations).To(HaveKey(performancev2.PerformanceProfileSummaryAnnotation))
+
+			summary := &performancev2.ProfileSummary{}
+			Expect(json.Unmarshal([]byte(updatedProfile.Annotations[performancev2.PerformanceProfileSummaryAnnotation]), summary)).To(Succeed())
+			Expect(summary).To(Equal(getProfileSummary(profile, updatedProfile.Status.Conditions, updatedProfile.Status.Rollout)))
+			Expect(summary.ReservedCPUs).To(Equal(cpuSetSize(profile.Spec.CPU.Reserved)))
+			Expect(summary.IsolatedCPUs).To(Equal(cpuSetSize(profile.Spec.CPU.Isolated)))
+		})
+
+		It("should sum the huge pages and report the degraded rollout", func() {
+			profile.Spec.HugePages = &performancev2.HugePages{
+				Pages: []performancev2.HugePage{
+					{Size: "1G", Count: 4},
+					{Size: "2M", Count: 512},
+				},
+			}
+			rollout := &performancev2.RolloutStatus{TotalNodes: 3, UpdatedNodes: 3}
+			conditions := (&PerformanceProfileReconciler{}).getDegradedConditions(conditionReasonMCPDegraded, "degraded")
+
+			summary := getProfileSummary(profile, conditions, rollout)
+			Expect(summary.HugepagesTotal).To(Equal("5Gi"))
+			Expect(summary.RolloutState).To(Equal(performancev2.ProfileRolloutStateDegraded))
+			Expect(summary.UpdatedNodes).To(Equal(int32(3)))
+
+			summary = getProfileSummary(profile, nil, rollout)
+			Expect(summary.RolloutState).To(Equal(performancev2.ProfileRolloutStateAvailable))
+		})
+	})
+
+	Context("with disabled generated artifacts", func() {
+		It("should not create the runtime class and report it as skipped", func() {
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			profile.Spec.DisableGenerated = []performancev2.GeneratedArtifact{performancev2.GeneratedArtifactRuntimeClass}
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			runtimeClass := &nodev1.RuntimeClass{}
+			key := types.NamespacedName{Name: components.GetComponentName(profile.Name, components.ComponentNamePrefix)}
+			Expect(errors.IsNotFound(r.Get(context.TODO(), key, runtimeClass))).To(BeTrue())
+
+			updatedProfile := &performancev2.PerformanceProfile{}
+			Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			Expect(updatedProfile.Status.SkippedArtifacts).To(ConsistOf(string(performancev2.GeneratedArtifactRuntimeClass)))
+		})
+	})
+
+	Context("with the exported artifacts", func() {
+		BeforeEach(func() {
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			profile.Annotations = map[string]string{performancev2.PerformanceProfileExportArtifactsAnnotation: "true"}
+		})
+
+		It("should export the components and reference them from the status", func() {
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			cm := &corev1.ConfigMap{}
+			key := types.NamespacedName{
+				Name:      getExportedArtifactsName(profile),
+				Namespace: components.NamespaceNodeTuningOperator,
+			}
+			Expect(r.Get(context.TODO(), key, cm)).To(Succeed())
+			Expect(metav1.IsControlledBy(cm, profile)).To(BeTrue())
+			Expect(cm.Data).To(HaveKey("MachineConfig_" + machineconfig.GetMachineConfigName(profile) + ".yaml"))
+			Expect(cm.Data).To(HaveKey("KubeletConfig_" + components.GetComponentName(profile.Name, components.ComponentNamePrefix) + ".yaml"))
+			Expect(cm.Data).To(HaveKey("Tuned_" + components.GetComponentName(profile.Name, components.ProfileNamePerformance) + ".yaml"))
+
+			updatedProfile := &performancev2.PerformanceProfile{}
+			Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			Expect(updatedProfile.Status.ExportedArtifacts).To(HaveValue(Equal(key.String())))
+		})
+
+		It("should delete the exported components once the export is disabled", func() {
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			updatedProfile := &performancev2.PerformanceProfile{}
+			Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			updatedProfile.Annotations[performancev2.PerformanceProfileExportArtifactsAnnotation] = "false"
+			Expect(r.Update(context.TODO(), updatedProfile)).To(Succeed())
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			key := types.NamespacedName{
+				Name:      getExportedArtifactsName(profile),
+				Namespace: components.NamespaceNodeTuningOperator,
+			}
+			Expect(errors.IsNotFound(r.Get(context.TODO(), key, &corev1.ConfigMap{}))).To(BeTrue())
+			Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			Expect(updatedProfile.Status.ExportedArtifacts).To(BeNil())
+		})
+	})
+
+	Context("with throttled machine config writes", func() {
+		var fakeClock *testingclock.FakeClock
+
+		BeforeEach(func() {
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			fakeClock = testingclock.NewFakeClock(time.Now())
+		})
+
+		getMachineConfigKernelType := func(r *PerformanceProfileReconciler) string {
+			mc := &mcov1.MachineConfig{}
+			key := types.NamespacedName{Name: machineconfig.GetMachineConfigName(profile)}
+			ExpectWithOffset(1, r.Get(context.TODO(), key, mc)).ToNot(HaveOccurred())
+			return mc.Spec.KernelType
+		}
+
+		It("should coalesce the regenerations of the pool within the sync window", func() {
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			r.writeThrottle = newMachineConfigWriteThrottle(0, 0, time.Minute, fakeClock)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+			Expect(getMachineConfigKernelType(r)).To(Equal(machineconfig.MCKernelRT))
+
+			updatedProfile := &performancev2.PerformanceProfile{}
+			Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			updatedProfile.Spec.RealTimeKernel = &performancev2.RealTimeKernel{Enabled: pointer.Bool(false)}
+			updatedProfile.Generation++
+			Expect(r.Update(context.TODO(), updatedProfile)).ToNot(HaveOccurred())
+
+			fakeClock.Step(10 * time.Second)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{RequeueAfter: 50 * time.Second}))
+			Expect(getMachineConfigKernelType(r)).To(Equal(machineconfig.MCKernelRT))
+
+			fakeClock.Step(50 * time.Second)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+			Expect(getMachineConfigKernelType(r)).To(Equal(machineconfig.MCKernelDefault))
+		})
+
+		It("should postpone the writes exceeding the rate limit", func() {
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			r.writeThrottle = newMachineConfigWriteThrottle(0.1, 1, 0, fakeClock)
+			// another profile consumed the burst
+			Expect(r.writeThrottle.tryWrite("other")).To(BeZero())
+
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Second}))
+			mc := &mcov1.MachineConfig{}
+			key := types.NamespacedName{Name: machineconfig.GetMachineConfigName(profile)}
+			Expect(errors.IsNotFound(r.Get(context.TODO(), key, mc))).To(BeTrue())
+
+			fakeClock.Step(10 * time.Second)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+			Expect(r.Get(context.TODO(), key, mc)).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("with the machine config pool maxUnavailable", func() {
+		getMCP := func(r *PerformanceProfileReconciler) *mcov1.MachineConfigPool {
+			mcp := &mcov1.MachineConfigPool{}
+			ExpectWithOffset(1, r.Get(context.TODO(), types.NamespacedName{Name: profileMCP.Name}, mcp)).ToNot(HaveOccurred())
+			return mcp
+		}
+
+		It("should override the pool maxUnavailable until the pool rolls out the profile", func() {
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			maxUnavailable := intstr.FromString("50%")
+			profile.Spec.MachineConfigPoolMaxUnavailable = &maxUnavailable
+			originalMaxUnavailable := intstr.FromInt(2)
+			profileMCP.Spec.MaxUnavailable = &originalMaxUnavailable
+			profileMCP.Spec.Configuration.Name = profileMC.Name
+
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			mcp := getMCP(r)
+			Expect(mcp.Spec.MaxUnavailable).To(Equal(&maxUnavailable))
+			Expect(mcp.Annotations).To(HaveKeyWithValue(originalMaxUnavailableAnnotation, "2"))
+			Expect(mcp.Annotations).To(HaveKeyWithValue(maxUnavailableSourceConfigAnnotation, profileMC.Name))
+
+			// the pool is still rolling out the new configuration
+			renderedMC := testutils.NewProfileMachineConfig("test-rendered", kernelArgsv1)
+			Expect(r.Create(context.TODO(), renderedMC)).ToNot(HaveOccurred())
+			mcp.Spec.Configuration.Name = renderedMC.Name
+			Expect(r.Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+			Expect(getMCP(r).Spec.MaxUnavailable).To(Equal(&maxUnavailable))
+
+			mcp = getMCP(r)
+			mcp.Status.Configuration.Name = renderedMC.Name
+			Expect(r.Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			mcp = getMCP(r)
+			Expect(mcp.Spec.MaxUnavailable).To(Equal(&originalMaxUnavailable))
+			Expect(mcp.Annotations).ToNot(HaveKey(originalMaxUnavailableAnnotation))
+			Expect(mcp.Annotations).ToNot(HaveKey(maxUnavailableSourceConfigAnnotation))
+		})
+
+		It("should not touch the pool when the profile does not define the maxUnavailable", func() {
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			mcp := getMCP(r)
+			Expect(mcp.Spec.MaxUnavailable).To(BeNil())
+			Expect(mcp.Annotations).ToNot(HaveKey(originalMaxUnavailableAnnotation))
+		})
+	})
+
+	Context("in the read-only mode", func() {
+		It("should report the components without writing them", func() {
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			r.ReadOnly = true
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			mc := &mcov1.MachineConfig{}
+			err := r.Get(context.TODO(), types.NamespacedName{Name: machineconfig.GetMachineConfigName(profile)}, mc)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			kc := &mcov1.KubeletConfig{}
+			err = r.Get(context.TODO(), types.NamespacedName{Name: components.GetComponentName(profile.Name, components.ComponentNamePrefix)}, kc)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			cm := &corev1.ConfigMap{}
+			key := types.NamespacedName{
+				Name:      components.GetComponentName(profile.Name, readOnlyComponentsPrefix),
+				Namespace: components.NamespaceNodeTuningOperator,
+			}
+			Expect(r.Get(context.TODO(), key, cm)).ToNot(HaveOccurred())
+			Expect(cm.Data).To(HaveKey("MachineConfig_" + machineconfig.GetMachineConfigName(profile) + ".yaml"))
+			Expect(cm.Data).To(HaveLen(4))
+
+			updatedProfile := &performancev2.PerformanceProfile{}
+			Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			readOnlyCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionTypeReadOnly)
+			Expect(readOnlyCondition).ToNot(BeNil())
+			Expect(readOnlyCondition.Reason).To(Equal(conditionReasonReadOnlyChangesPending))
+			Expect(conditionsv1.IsStatusConditionTrue(updatedProfile.Status.Conditions, conditionsv1.ConditionProgressing)).To(BeTrue())
+		})
+
+		It("should leave the components of the deleted profile in place", func() {
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			profile.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			mcpSelectorKey, mcpSelectorValue := components.GetFirstKeyAndValue(profile.Spec.MachineConfigPoolSelector)
+			kc, err := kubeletconfig.New(profile, &components.KubeletConfigOptions{MachineConfigPoolSelector: map[string]string{mcpSelectorKey: mcpSelectorValue}})
+			Expect(err).ToNot(HaveOccurred())
+
+			r := newFakeReconciler(profile, profileMCP, kc, infra, clusterOperator, nodeConfig, profileMC)
+			r.ReadOnly = true
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			Expect(r.Get(context.TODO(), types.NamespacedName{Name: kc.Name}, &mcov1.KubeletConfig{})).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("with a registered generator", func() {
+		const generatorAnnotation = "test.performance.openshift.io/extra-generator"
+
+		BeforeEach(func() {
+			registerTestGenerator.Do(func() {
+				manifestset.RegisterGenerator(manifestset.NewGenerator("test-extra", func(profile *performancev2.PerformanceProfile, opts *components.Options) ([]client.Object, error) {
+					if _, ok := profile.Annotations[generatorAnnotation]; !ok {
+						return nil, nil
+					}
+					return []client.Object{&corev1.ConfigMap{
+						TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "bios-" + profile.Name,
+							Namespace: components.NamespaceNodeTuningOperator,
+						},
+						Data: map[string]string{"isolated": string(*profile.Spec.CPU.Isolated)},
+					}}, nil
+				}))
+			})
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			profile.Annotations = map[string]string{generatorAnnotation: ""}
+		})
+
+		It("should create and update the objects of the generator", func() {
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			cm := &corev1.ConfigMap{}
+			key := types.NamespacedName{Name: "bios-" + profile.Name, Namespace: components.NamespaceNodeTuningOperator}
+			Expect(r.Get(context.TODO(), key, cm)).ToNot(HaveOccurred())
+			Expect(cm.Data).To(HaveKeyWithValue("isolated", string(*profile.Spec.CPU.Isolated)))
+			Expect(cm.OwnerReferences).To(HaveLen(1))
+			Expect(cm.OwnerReferences[0].Name).To(Equal(profile.Name))
+
+			By("Restoring the content the generator owns")
+			cm.Data["isolated"] = "0"
+			cm.Labels = map[string]string{"custom": "label"}
+			Expect(r.Update(context.TODO(), cm)).ToNot(HaveOccurred())
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			Expect(r.Get(context.TODO(), key, cm)).ToNot(HaveOccurred())
+			Expect(cm.Data).To(HaveKeyWithValue("isolated", string(*profile.Spec.CPU.Isolated)))
+			Expect(cm.Labels).To(HaveKeyWithValue("custom", "label"))
+
+			By("Not updating the up to date objects")
+			resourceVersion := cm.ResourceVersion
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+			Expect(r.Get(context.TODO(), key, cm)).ToNot(HaveOccurred())
+			Expect(cm.ResourceVersion).To(Equal(resourceVersion))
+		})
+	})
+
+	Context("with the canary rollout", func() {
+		const renderedConfig = "rendered-canary"
+
+		var r *PerformanceProfileReconciler
+
+		getMCP := func() *mcov1.MachineConfigPool {
+			mcp := &mcov1.MachineConfigPool{}
+			ExpectWithOffset(1, r.Get(context.TODO(), types.NamespacedName{Name: profileMCP.Name}, mcp)).ToNot(HaveOccurred())
+			return mcp
+		}
+
+		getNode := func(name string) *corev1.Node {
+			node := &corev1.Node{}
+			ExpectWithOffset(1, r.Get(context.TODO(), types.NamespacedName{Name: name}, node)).ToNot(HaveOccurred())
+			return node
+		}
+
+		getCondition := func(conditionType conditionsv1.ConditionType) *conditionsv1.Condition {
+			updatedProfile := &performancev2.PerformanceProfile{}
+			ExpectWithOffset(1, r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			return conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionType)
+		}
+
+		// updateCanaryNode reports the machine config daemon state of the canary node
+		updateCanaryNode := func(config, state string) {
+			node := getNode("node-a")
+			node.Annotations[mcdCurrentConfigAnnotation] = config
+			node.Annotations[mcdStateAnnotation] = state
+			ExpectWithOffset(1, r.Update(context.TODO(), node)).ToNot(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			profile.Annotations = map[string]string{
+				performancev2.PerformanceProfileCanaryRolloutAnnotation: "{}",
+			}
+			profileMCP.Spec.Configuration.Name = profileMC.Name
+
+			var nodes []runtime.Object
+			for _, name := range []string{"node-b", "node-a"} {
+				nodes = append(nodes, &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   name,
+						Labels: profile.Spec.NodeSelector,
+						Annotations: map[string]string{
+							mcdCurrentConfigAnnotation: profileMC.Name,
+							mcdDesiredConfigAnnotation: profileMC.Name,
+							mcdStateAnnotation:         mcdStateDone,
+						},
+					},
+				})
+			}
+			objs := append([]runtime.Object{profileMCP, infra, clusterOperator, nodeConfig, profileMC}, nodes...)
+			r = newFakeReconciler(profile, objs...)
+
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+			mcp := getMCP()
+			Expect(mcp.Spec.Paused).To(BeTrue())
+			Expect(mcp.Annotations).To(HaveKeyWithValue(canaryNodeAnnotation, "node-a"))
+			Expect(mcp.Annotations).To(HaveKeyWithValue(canarySourceConfigAnnotation, profileMC.Name))
+			Expect(mcp.Annotations).To(HaveKeyWithValue(canaryOriginalPausedAnnotation, "false"))
+
+			// the pool renders the new configuration
+			mcp.Spec.Configuration.Name = renderedConfig
+			Expect(r.Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{RequeueAfter: canaryRequeueInterval}))
+			Expect(getMCP().Spec.Paused).To(BeTrue())
+			Expect(getNode("node-a").Annotations).To(HaveKeyWithValue(mcdDesiredConfigAnnotation, renderedConfig))
+			Expect(getNode("node-b").Annotations).To(HaveKeyWithValue(mcdDesiredConfigAnnotation, profileMC.Name))
+
+			condition := getCondition(conditionTypeCanaryRollout)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Reason).To(Equal(conditionReasonCanaryInProgress))
+		})
+
+		It("should unpause the pool once the canary node is tuned", func() {
+			updateCanaryNode(renderedConfig, mcdStateDone)
+			tunedProfile := &tunedv1.Profile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "node-a",
+					Namespace: components.NamespaceNodeTuningOperator,
+				},
+				Status: tunedv1.ProfileStatus{
+					Conditions: []tunedv1.ProfileStatusCondition{
+						{
+							Type:   tunedv1.TunedProfileApplied,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			}
+			Expect(r.Create(context.TODO(), tunedProfile)).ToNot(HaveOccurred())
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			mcp := getMCP()
+			Expect(mcp.Spec.Paused).To(BeFalse())
+			Expect(mcp.Annotations).ToNot(HaveKey(canaryNodeAnnotation))
+			Expect(mcp.Annotations).ToNot(HaveKey(canaryConfigAnnotation))
+			Expect(getCondition(conditionTypeCanaryRollout)).To(BeNil())
+		})
+
+		It("should keep the pool paused and degrade the profile when the canary node fails", func() {
+			updateCanaryNode(profileMC.Name, mcdStateDegraded)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			mcp := getMCP()
+			Expect(mcp.Spec.Paused).To(BeTrue())
+			Expect(mcp.Annotations).To(HaveKey(canaryFailureAnnotation))
+
+			degradedCondition := getCondition(conditionsv1.ConditionDegraded)
+			Expect(degradedCondition).ToNot(BeNil())
+			Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(degradedCondition.Reason).To(Equal(conditionReasonCanaryFailed))
+		})
+
+		It("should unpause the pool when the canary node can not be updated", func() {
+			r.Client = interceptor.NewClient(r.Client.(client.WithWatch), interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					if node, ok := obj.(*corev1.Node); ok {
+						return errors.NewForbidden(corev1.Resource("nodes"), node.Name, fmt.Errorf("patch is not allowed"))
+					}
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			})
+
+			// the pool renders a newer configuration during the canary rollout
+			mcp := getMCP()
+			mcp.Spec.Configuration.Name = renderedConfig + "-newer"
+			Expect(r.Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+			_, err := r.Reconcile(context.TODO(), request)
+			Expect(err).To(HaveOccurred())
+
+			mcp = getMCP()
+			Expect(mcp.Spec.Paused).To(BeFalse())
+			Expect(mcp.Annotations).ToNot(HaveKey(canaryNodeAnnotation))
+			Expect(mcp.Annotations).ToNot(HaveKey(canaryConfigAnnotation))
+			Expect(getNode("node-a").Annotations).To(HaveKeyWithValue(mcdDesiredConfigAnnotation, renderedConfig))
+		})
+
+		It("should restore the pool when the profile is deleted", func() {
+			updatedProfile := &performancev2.PerformanceProfile{}
+			Expect(r.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			Expect(r.Delete(context.TODO(), updatedProfile)).ToNot(HaveOccurred())
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			mcp := getMCP()
+			Expect(mcp.Spec.Paused).To(BeFalse())
+			Expect(mcp.Annotations).ToNot(HaveKey(canaryNodeAnnotation))
+		})
+	})
+
+	Context("with the maintenance window and the drain hints", func() {
+		var r *PerformanceProfileReconciler
+
+		getMCP := func() *mcov1.MachineConfigPool {
+			mcp := &mcov1.MachineConfigPool{}
+			ExpectWithOffset(1, r.Get(context.TODO(), types.NamespacedName{Name: profileMCP.Name}, mcp)).ToNot(HaveOccurred())
+			return mcp
+		}
+
+		getDrainHint := func() *drainHint {
+			mcp := getMCP()
+			ExpectWithOffset(1, mcp.Annotations).To(HaveKey(drainHintAnnotation))
+			hint := &drainHint{}
+			ExpectWithOffset(1, json.Unmarshal([]byte(mcp.Annotations[drainHintAnnotation]), hint)).ToNot(HaveOccurred())
+			return hint
+		}
+
+		BeforeEach(func() {
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			profileMCP.Spec.Configuration.Name = "rendered-source"
+
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: profile.Spec.NodeSelector}}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "db", Labels: map[string]string{"app": "db"}},
+				Spec:       corev1.PodSpec{NodeName: "node-a"},
+			}
+			pdb := &policyv1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "db"},
+				Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+				Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0},
+			}
+			r = newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC, node, pod, pdb)
+		})
+
+		It("should postpone the rollout to the maintenance window and hint the drain", func() {
+			// a window that opened 30 minutes ago and lasted a minute
+			minute := (time.Now().UTC().Minute() + 30) % 60
+			profile.Spec.MachineConfigPoolMaintenanceWindow = &performancev2.MaintenanceWindow{
+				Schedule: fmt.Sprintf("%d * * * *", minute),
+				Duration: metav1.Duration{Duration: time.Minute},
+			}
+			Expect(r.Update(context.TODO(), profile)).ToNot(HaveOccurred())
+
+			result := reconcileTimes(r, request, 1)
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", time.Hour))
+
+			// the kernel arguments of the new tuned reboot the nodes
+			key := types.NamespacedName{Name: machineconfig.GetMachineConfigName(profile)}
+			err := r.Get(context.TODO(), key, &mcov1.MachineConfig{})
+			Expect(errors.IsNotFound(err)).To(BeTrue(), "the machine config should not be created outside of the window")
+
+			hint := getDrainHint()
+			Expect(hint.Profiles).To(Equal([]string{profile.Name}))
+			Expect(hint.Reason).To(Equal(drainHintReasonKernelArguments))
+			Expect(hint.SourceConfig).To(Equal("rendered-source"))
+			Expect(hint.NotBefore).ToNot(BeNil())
+			Expect(hint.BlockingPodDisruptionBudgets).To(Equal([]string{"db/db"}))
+		})
+
+		It("should roll out within the maintenance window and remove the hint after the rollout", func() {
+			profile.Spec.MachineConfigPoolMaintenanceWindow = &performancev2.MaintenanceWindow{
+				Schedule: "* * * * *",
+				Duration: metav1.Duration{Duration: time.Hour},
+			}
+			Expect(r.Update(context.TODO(), profile)).ToNot(HaveOccurred())
+
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			key := types.NamespacedName{Name: machineconfig.GetMachineConfigName(profile)}
+			Expect(r.Get(context.TODO(), key, &mcov1.MachineConfig{})).ToNot(HaveOccurred())
+
+			hint := getDrainHint()
+			Expect(hint.Reason).To(Equal(drainHintReasonKernelArguments))
+			Expect(hint.NotBefore).To(BeNil())
+
+			// the pool rolls the new configuration out to all its machines
+			Expect(r.Create(context.TODO(), testutils.NewProfileMachineConfig("rendered-target", kernelArgsv1))).ToNot(HaveOccurred())
+			mcp := getMCP()
+			mcp.Spec.Configuration.Name = "rendered-target"
+			mcp.Status.Configuration.Name = "rendered-target"
+			mcp.Status.MachineCount = 1
+			mcp.Status.UpdatedMachineCount = 1
+			Expect(r.Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+
+			reconcileTimes(r, request, 1)
+			Expect(getMCP().Annotations).ToNot(HaveKey(drainHintAnnotation))
+		})
+	})
+
 	Context("with ContainerRuntimeConfig enabling crun", func() {
 		BeforeEach(func() {
 			ctrcfg = testutils.NewContainerRuntimeConfig(mcov1.ContainerRuntimeDefaultRuntimeCrun, profile.Spec.MachineConfigPoolSelector)
@@ -1182,6 +2494,40 @@ var _ = Describe("Controller", func() {
 			Expect(config.Storage.Files).To(ContainElements(containFiles))
 		})
 	})
+
+	Context("with tracing", func() {
+		var sr *tracingtest.SpanRecorder
+		var restore func()
+
+		BeforeEach(func() {
+			profile.Finalizers = append(profile.Finalizers, finalizer)
+			sr, restore = tracingtest.Install()
+		})
+
+		AfterEach(func() {
+			restore()
+		})
+
+		It("should trace the reconcile steps as children of the reconcile span", func() {
+			r := newFakeReconciler(profile, profileMCP, infra, clusterOperator, nodeConfig, profileMC)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			reconciles := sr.Named("PerformanceProfile.Reconcile")
+			Expect(reconciles).To(HaveLen(1))
+			Expect(sr.Parent(reconciles[0])).To(BeNil())
+			reconcileID := reconciles[0].SpanContext().SpanID()
+
+			for _, name := range []string{"fetch", "compute", "apply"} {
+				spans := sr.Named(name)
+				Expect(spans).ToNot(BeEmpty(), "expected a %s span", name)
+				for _, span := range spans {
+					parent := sr.Parent(span)
+					Expect(parent).ToNot(BeNil(), "expected the %s span to have a parent", name)
+					Expect(parent.SpanContext().SpanID()).To(Equal(reconcileID), "expected the %s span to be a child of the reconcile span", name)
+				}
+			}
+		})
+	})
 })
 
 func reconcileTimes(reconciler *PerformanceProfileReconciler, request reconcile.Request, times int) reconcile.Result {
@@ -1243,5 +2589,6 @@ func newFakeReconciler(profile client.Object, initObjects ...runtime.Object) *Pe
 		Scheme:      scheme.Scheme,
 		Recorder:    fakeRecorder,
 		FeatureGate: fg,
+		APIReader:   fakeClient,
 	}
 }
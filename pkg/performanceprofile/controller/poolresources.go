@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/poolresources"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// reconcilePoolResources publishes the CPUs and the memory reservations the profile configures on the nodes of
+// its pool, so the topology aware scheduling components consume them without parsing the kubelet configs.
+// The resources are read from the kubelet config in the cluster, the restored one after an automatic rollback,
+// and nothing is published until the kubelet config exists.
+func (r *PerformanceProfileReconciler) reconcilePoolResources(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool) error {
+	kc, err := r.getKubeletConfig(components.GetComponentName(profile.Name, components.ComponentNamePrefix))
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	cm, err := poolresources.New(profile, profileMCP.Name, kc, components.NamespaceNodeTuningOperator)
+	if err != nil {
+		return err
+	}
+	if err := controllerutil.SetControllerReference(profile, cm, r.Scheme); err != nil {
+		return err
+	}
+
+	// remove the resources published for the previous pool of the profile
+	if err := r.deletePoolResources(ctx, profile, cm.Name); err != nil {
+		return err
+	}
+
+	existing := &corev1.ConfigMap{}
+	err = r.Get(ctx, namespacedName(cm), existing)
+	if errors.IsNotFound(err) {
+		klog.Infof("Create pool resources %q of the performance profile %q", cm.Name, profile.Name)
+		return r.Create(ctx, cm)
+	}
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(existing.Data, cm.Data) &&
+		reflect.DeepEqual(existing.Labels, cm.Labels) &&
+		reflect.DeepEqual(existing.OwnerReferences, cm.OwnerReferences) {
+		return nil
+	}
+
+	klog.Infof("Update pool resources %q of the performance profile %q", cm.Name, profile.Name)
+	existing.Labels = cm.Labels
+	existing.OwnerReferences = cm.OwnerReferences
+	existing.Data = cm.Data
+	return r.Update(ctx, existing)
+}
+
+// deletePoolResources deletes the pool resources published for the profile, except the config map 'keep'
+func (r *PerformanceProfileReconciler) deletePoolResources(ctx context.Context, profile *performancev2.PerformanceProfile, keep string) error {
+	cms := &corev1.ConfigMapList{}
+	if err := r.List(ctx, cms,
+		client.InNamespace(components.NamespaceNodeTuningOperator),
+		client.MatchingLabels{poolresources.LabelProfile: profile.Name}); err != nil {
+		return err
+	}
+
+	for i := range cms.Items {
+		if cms.Items[i].Name == keep {
+			continue
+		}
+		klog.Infof("Delete pool resources %q of the performance profile %q", cms.Items[i].Name, profile.Name)
+		if err := r.Delete(ctx, &cms.Items[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
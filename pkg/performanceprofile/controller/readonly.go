@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	conditionTypeReadOnly conditionsv1.ConditionType = "ReadOnly"
+
+	conditionReasonReadOnlyChangesPending = "ChangesPending"
+	conditionReasonReadOnlyUpToDate       = "UpToDate"
+
+	// readOnlyComponentsPrefix names the config map holding the components the controller would write
+	readOnlyComponentsPrefix = "read-only-components"
+)
+
+// readOnlyComponent is a profile component the controller would create or update outside of the read-only mode
+type readOnlyComponent struct {
+	kind string
+	obj  client.Object
+}
+
+// reconcileReadOnly computes the components of the profile without writing any of them, nor the machine config
+// pool of the profile. The components differing from the cluster state are reported by the ReadOnly condition
+// and dumped to a config map, so they can be audited or handed over to an external config pipeline.
+func (r *PerformanceProfileReconciler) reconcileReadOnly(ctx context.Context, instance *performancev2.PerformanceProfile) (ctrl.Result, error) {
+	if instance.DeletionTimestamp != nil {
+		// the components are left to whoever manages them, only the finalizer added outside of the read-only mode is removed
+		if hasFinalizer(instance, finalizer) {
+			klog.Infof("Read-only mode, leaving the components of the deleted performance profile %q in place", instance.Name)
+			removeFinalizer(instance, finalizer)
+			return ctrl.Result{}, r.Update(ctx, instance)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	pinningMode, err := r.getInfraPartitioningMode()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	ctrRuntime, err := r.getContainerRuntimeName(ctx, instance)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not determine high-performance runtime class container-runtime for profile %q; %w", instance.Name, err)
+	}
+
+	profileMCP, err := r.getMachineConfigPoolByProfile(ctx, instance)
+	if err != nil {
+		conditions := r.getDegradedConditions(conditionFailedToFindMachineConfigPool, err.Error())
+		return ctrl.Result{}, r.updateStatus(instance, conditions)
+	}
+
+	mcMutated, kcMutated, performanceTunedMutated, runtimeClassMutated, extraMutated, err := r.getMutatedComponents(ctx, instance, &components.Options{
+		ProfileMCP: profileMCP,
+		MachineConfig: components.MachineConfigOptions{
+			PinningMode:      &pinningMode,
+			DefaultRuntime:   ctrRuntime,
+			MixedCPUsEnabled: r.isMixedCPUsEnabled(instance),
+		},
+		DisabledFeatures: r.getDisabledFeatures(),
+	})
+	if err != nil {
+		klog.Errorf("failed to compute performance profile %q components: %v", instance.Name, err)
+		conditions := r.getDegradedConditions(conditionReasonComponentsCreationFailed, err.Error())
+		return ctrl.Result{}, r.updateStatus(instance, conditions)
+	}
+
+	var pending []readOnlyComponent
+	if mcMutated != nil {
+		pending = append(pending, readOnlyComponent{kind: "MachineConfig", obj: mcMutated})
+	}
+	if kcMutated != nil {
+		pending = append(pending, readOnlyComponent{kind: "KubeletConfig", obj: kcMutated})
+	}
+	if performanceTunedMutated != nil {
+		pending = append(pending, readOnlyComponent{kind: "Tuned", obj: performanceTunedMutated})
+	}
+	if runtimeClassMutated != nil {
+		pending = append(pending, readOnlyComponent{kind: "RuntimeClass", obj: runtimeClassMutated})
+	}
+	for _, obj := range extraMutated {
+		pending = append(pending, readOnlyComponent{kind: getGeneratedObjectKind(obj), obj: obj})
+	}
+
+	cmName, err := r.dumpReadOnlyComponents(ctx, instance, pending)
+	if err != nil {
+		klog.Errorf("failed to dump performance profile %q components: %v", instance.Name, err)
+		return ctrl.Result{}, err
+	}
+
+	condition := getReadOnlyCondition(pending, cmName)
+	conditions := r.getAvailableConditions("read-only;")
+	if len(pending) > 0 {
+		conditions = r.getProgressingConditions(conditionReasonReadOnlyChangesPending, condition.Message)
+	}
+	conditions = append(conditions, condition)
+
+	return ctrl.Result{}, r.updateStatus(instance, conditions)
+}
+
+// dumpReadOnlyComponents stores the components 'pending' in the read-only config map of the profile and returns its name
+func (r *PerformanceProfileReconciler) dumpReadOnlyComponents(ctx context.Context, profile *performancev2.PerformanceProfile, pending []readOnlyComponent) (string, error) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      components.GetComponentName(profile.Name, readOnlyComponentsPrefix),
+			Namespace: components.NamespaceNodeTuningOperator,
+		},
+		Data: map[string]string{},
+	}
+	if err := controllerutil.SetControllerReference(profile, cm, r.Scheme); err != nil {
+		return "", err
+	}
+
+	for _, component := range pending {
+		data, err := yaml.Marshal(component.obj)
+		if err != nil {
+			return "", err
+		}
+		cm.Data[component.kind+"_"+component.obj.GetName()+".yaml"] = string(data)
+	}
+
+	existing := &corev1.ConfigMap{}
+	err := r.Get(ctx, namespacedName(cm), existing)
+	if errors.IsNotFound(err) {
+		klog.Infof("Create the read-only components %q of the performance profile %q", cm.Name, profile.Name)
+		return cm.Name, r.Create(ctx, cm)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if apiequality.Semantic.DeepEqual(existing.Data, cm.Data) &&
+		reflect.DeepEqual(existing.OwnerReferences, cm.OwnerReferences) {
+		return cm.Name, nil
+	}
+
+	klog.Infof("Update the read-only components %q of the performance profile %q", cm.Name, profile.Name)
+	existing.OwnerReferences = cm.OwnerReferences
+	existing.Data = cm.Data
+	return cm.Name, r.Update(ctx, existing)
+}
+
+func getReadOnlyCondition(pending []readOnlyComponent, cmName string) conditionsv1.Condition {
+	now := metav1.Now()
+	condition := conditionsv1.Condition{
+		Type:               conditionTypeReadOnly,
+		Status:             corev1.ConditionTrue,
+		Reason:             conditionReasonReadOnlyUpToDate,
+		Message:            "The operator runs in the read-only mode, the components are up to date",
+		LastTransitionTime: now,
+		LastHeartbeatTime:  now,
+	}
+	if len(pending) == 0 {
+		return condition
+	}
+
+	var names []string
+	for _, component := range pending {
+		names = append(names, component.kind+" "+component.obj.GetName())
+	}
+	condition.Reason = conditionReasonReadOnlyChangesPending
+	condition.Message = fmt.Sprintf("The operator runs in the read-only mode, the components %s are not written, see the config map %s/%s",
+		strings.Join(names, ", "), components.NamespaceNodeTuningOperator, cmName)
+	return condition
+}
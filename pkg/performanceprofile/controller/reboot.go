@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	conditionTypeRebootPending conditionsv1.ConditionType = "RebootPending"
+
+	conditionReasonMachineConfigNotRendered = "MachineConfigNotRendered"
+	conditionReasonNodesNotRebooted         = "NodesNotRebooted"
+
+	// rebootPendingNodesInMessage limits the node names listed by the RebootPending condition
+	rebootPendingNodesInMessage = 5
+)
+
+// getRebootPendingCondition returns the RebootPending condition when some nodes of the profile did not
+// reboot yet into the pool configuration rendering the generated machine config, nil otherwise.
+// The nodes report the configuration they booted with under the machine config daemon current config
+// annotation, which the MCO updates once the node rebooted into the pool target configuration.
+func (r *PerformanceProfileReconciler) getRebootPendingCondition(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool, rollout *performancev2.RolloutStatus) (*conditionsv1.Condition, error) {
+	// nothing to reboot into before the generated machine config exists
+	if rollout == nil {
+		return nil, nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, &client.ListOptions{LabelSelector: labels.SelectorFromSet(profile.Spec.NodeSelector)}); err != nil {
+		return nil, err
+	}
+	if len(nodes.Items) == 0 {
+		return nil, nil
+	}
+
+	now := metav1.Now()
+	condition := &conditionsv1.Condition{
+		Type:               conditionTypeRebootPending,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: now,
+		LastHeartbeatTime:  now,
+	}
+
+	if !isMachineConfigRendered(profileMCP, rollout.MachineConfig) {
+		condition.Reason = conditionReasonMachineConfigNotRendered
+		condition.Message = fmt.Sprintf("The machine config %q is not rendered into the machine config pool %q configuration yet, %d nodes will reboot",
+			rollout.MachineConfig, profileMCP.Name, len(nodes.Items))
+		return condition, nil
+	}
+
+	var pending []string
+	for i := range nodes.Items {
+		if _, tuned := isNodeTuned(&nodes.Items[i], profileMCP); !tuned {
+			pending = append(pending, nodes.Items[i].Name)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	sort.Strings(pending)
+
+	listed := pending
+	if len(listed) > rebootPendingNodesInMessage {
+		listed = listed[:rebootPendingNodesInMessage]
+		listed = append(listed, "...")
+	}
+	condition.Reason = conditionReasonNodesNotRebooted
+	condition.Message = fmt.Sprintf("%d of %d nodes did not reboot into the configuration %q yet: %s",
+		len(pending), len(nodes.Items), profileMCP.Spec.Configuration.Name, strings.Join(listed, ", "))
+	return condition, nil
+}
+
+// isMachineConfigRendered returns true when the pool target configuration renders the machine config 'mcName'
+func isMachineConfigRendered(profileMCP *mcov1.MachineConfigPool, mcName string) bool {
+	for _, source := range profileMCP.Spec.Configuration.Source {
+		if source.Name == mcName {
+			return true
+		}
+	}
+	return false
+}
@@ -2,12 +2,11 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"reflect"
 
 	apiconfigv1 "github.com/openshift/api/config/v1"
 	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/semantic"
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 
 	nodev1 "k8s.io/api/node/v1"
@@ -16,14 +15,17 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog"
-	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
 )
 
-func mergeMaps(src map[string]string, dst map[string]string) {
+func mergeMaps(src map[string]string, dst map[string]string) map[string]string {
+	if dst == nil && len(src) > 0 {
+		dst = make(map[string]string, len(src))
+	}
 	for k, v := range src {
 		// NOTE: it will override destination values
 		dst[k] = v
 	}
+	return dst
 }
 
 // TODO: we should merge all create, get and delete methods
@@ -66,12 +68,17 @@ func (r *PerformanceProfileReconciler) getMutatedMachineConfig(ctx context.Conte
 	}
 
 	mutated := existing.DeepCopy()
-	mergeMaps(mc.Annotations, mutated.Annotations)
-	mergeMaps(mc.Labels, mutated.Labels)
+	mutated.Annotations = mergeMaps(mc.Annotations, mutated.Annotations)
+	mutated.Labels = mergeMaps(mc.Labels, mutated.Labels)
 	mutated.Spec = mc.Spec
 
+	specEqual, err := semantic.MachineConfigSpecEqual(&existing.Spec, &mutated.Spec)
+	if err != nil {
+		return nil, err
+	}
+
 	// we do not need to update if it no change between mutated and existing object
-	if reflect.DeepEqual(existing.Spec, mutated.Spec) &&
+	if specEqual &&
 		apiequality.Semantic.DeepEqual(existing.Labels, mutated.Labels) &&
 		apiequality.Semantic.DeepEqual(existing.Annotations, mutated.Annotations) {
 		return nil, nil
@@ -92,9 +99,15 @@ func (r *PerformanceProfileReconciler) getClusterOperator() (*apiconfigv1.Cluste
 	return co, nil
 }
 
-func (r *PerformanceProfileReconciler) createOrUpdateMachineConfig(mc *mcov1.MachineConfig) error {
+func (r *PerformanceProfileReconciler) createOrUpdateMachineConfig(mc *mcov1.MachineConfig, profileName string) error {
 	_, err := r.getMachineConfig(context.TODO(), mc.Name)
 	if errors.IsNotFound(err) {
+		// the profile machine config is created for the first time or under a new name, only then the
+		// machine configs generated under the previous name can exist
+		if err := r.removeOutdatedMachineConfigs(mc, profileName); err != nil {
+			return err
+		}
+
 		klog.Infof("Create machine-config %q", mc.Name)
 		if err := r.Create(context.TODO(), mc); err != nil {
 			return err
@@ -110,6 +123,34 @@ func (r *PerformanceProfileReconciler) createOrUpdateMachineConfig(mc *mcov1.Mac
 	return r.Update(context.TODO(), mc)
 }
 
+// removeOutdatedMachineConfigs removes machine configs owned by the profile that were generated
+// under a different name, for example before the name suffix annotation was changed
+func (r *PerformanceProfileReconciler) removeOutdatedMachineConfigs(mc *mcov1.MachineConfig, profileName string) error {
+	mcList := &mcov1.MachineConfigList{}
+	if err := r.List(context.TODO(), mcList); err != nil {
+		klog.Errorf("Unable to list machine config objects for outdated removal procedure: %v", err)
+		return err
+	}
+
+	for i := range mcList.Items {
+		mcItem := mcList.Items[i]
+		if mcItem.Name == mc.Name || isMigrationComponent(&mcItem) {
+			continue
+		}
+
+		for _, ownerReference := range mcItem.OwnerReferences {
+			if ownerReference.Kind == "PerformanceProfile" && ownerReference.Name == profileName {
+				klog.Infof("Delete outdated machine-config %q", mcItem.Name)
+				if err := r.deleteMachineConfig(mcItem.Name); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
 func (r *PerformanceProfileReconciler) deleteMachineConfig(name string) error {
 	mc, err := r.getMachineConfig(context.TODO(), name)
 	if errors.IsNotFound(err) {
@@ -144,25 +185,17 @@ func (r *PerformanceProfileReconciler) getMutatedKubeletConfig(kc *mcov1.Kubelet
 	}
 
 	mutated := existing.DeepCopy()
-	mergeMaps(kc.Annotations, mutated.Annotations)
-	mergeMaps(kc.Labels, mutated.Labels)
+	mutated.Annotations = mergeMaps(kc.Annotations, mutated.Annotations)
+	mutated.Labels = mergeMaps(kc.Labels, mutated.Labels)
 	mutated.Spec = kc.Spec
 
-	existingKubeletConfig := &kubeletconfigv1beta1.KubeletConfiguration{}
-	err = json.Unmarshal(existing.Spec.KubeletConfig.Raw, existingKubeletConfig)
-	if err != nil {
-		return nil, err
-	}
-
-	mutatedKubeletConfig := &kubeletconfigv1beta1.KubeletConfiguration{}
-	err = json.Unmarshal(mutated.Spec.KubeletConfig.Raw, mutatedKubeletConfig)
+	specEqual, err := semantic.KubeletConfigSpecEqual(&existing.Spec, &mutated.Spec)
 	if err != nil {
 		return nil, err
 	}
 
 	// we do not need to update if it no change between mutated and existing object
-	if apiequality.Semantic.DeepEqual(existingKubeletConfig, mutatedKubeletConfig) &&
-		apiequality.Semantic.DeepEqual(existing.Spec.MachineConfigPoolSelector, mutated.Spec.MachineConfigPoolSelector) &&
+	if specEqual &&
 		apiequality.Semantic.DeepEqual(existing.Labels, mutated.Labels) &&
 		apiequality.Semantic.DeepEqual(existing.Annotations, mutated.Annotations) {
 		return nil, nil
@@ -223,8 +256,8 @@ func (r *PerformanceProfileReconciler) getMutatedTuned(tuned *tunedv1.Tuned) (*t
 	}
 
 	mutated := existing.DeepCopy()
-	mergeMaps(tuned.Annotations, mutated.Annotations)
-	mergeMaps(tuned.Labels, mutated.Labels)
+	mutated.Annotations = mergeMaps(tuned.Annotations, mutated.Annotations)
+	mutated.Labels = mergeMaps(tuned.Labels, mutated.Labels)
 	mutated.Spec = tuned.Spec
 
 	// we do not need to update if it no change between mutated and existing object
@@ -268,6 +301,9 @@ func (r *PerformanceProfileReconciler) removeOutdatedTuned(tuned *tunedv1.Tuned,
 
 	for t := range tunedList.Items {
 		tunedItem := tunedList.Items[t]
+		if isMigrationComponent(&tunedItem) {
+			continue
+		}
 		ownerReferences := tunedItem.ObjectMeta.OwnerReferences
 		for o := range ownerReferences {
 			if ownerReferences[o].Name == profileName && tunedItem.Name != tuned.Name {
@@ -313,8 +349,8 @@ func (r *PerformanceProfileReconciler) getMutatedRuntimeClass(runtimeClass *node
 	}
 
 	mutated := existing.DeepCopy()
-	mergeMaps(runtimeClass.Annotations, mutated.Annotations)
-	mergeMaps(runtimeClass.Labels, mutated.Labels)
+	mutated.Annotations = mergeMaps(runtimeClass.Annotations, mutated.Annotations)
+	mutated.Labels = mergeMaps(runtimeClass.Labels, mutated.Labels)
 	mutated.Handler = runtimeClass.Handler
 	mutated.Scheduling = runtimeClass.Scheduling
 
@@ -0,0 +1,392 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/machineconfig"
+	profileutil "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/profile"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// rollbackProfileGenerationAnnotation holds the profile generation the components of the rollback snapshot were rendered for
+	rollbackProfileGenerationAnnotation = "performance.openshift.io/rollback-profile-generation"
+	// rollbackRenderedConfigAnnotation holds the rendered configuration the profile pool ran when the snapshot was taken
+	rollbackRenderedConfigAnnotation = "performance.openshift.io/rollback-rendered-config"
+	rollbackNamePrefix               = "rollback"
+
+	rollbackMachineConfigKey = "machineconfig.json"
+	rollbackKubeletConfigKey = "kubeletconfig.json"
+	rollbackTunedKey         = "tuned.json"
+
+	conditionTypeRolledBack conditionsv1.ConditionType = "RolledBack"
+)
+
+// rollbackSnapshot holds the profile components that were rolled out successfully
+type rollbackSnapshot struct {
+	profileGeneration int64
+	renderedConfig    string
+	machineConfig     *mcov1.MachineConfig
+	kubeletConfig     *mcov1.KubeletConfig
+	tuned             *tunedv1.Tuned
+}
+
+// reconcileRollback keeps a snapshot of the profile components once all the nodes of the profile pool run them,
+// and restores the snapshot when the rollout of new components degrades the pool or the nodes tuned profiles.
+// It is a no-op unless the profile opts in the automatic rollback. The profile status is updated in place
+// when the components are restored.
+func (r *PerformanceProfileReconciler) reconcileRollback(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool, conditions []conditionsv1.Condition, applied bool) error {
+	autoRollback, err := profileutil.GetAutoRollback(profile)
+	if err != nil || autoRollback == nil || isRolledBack(profile) {
+		return err
+	}
+
+	snapshot, err := r.getRollbackSnapshot(ctx, profile)
+	if err != nil {
+		return err
+	}
+
+	current, err := r.getCurrentRollbackSnapshot(ctx, profile, profileMCP)
+	if err != nil {
+		return err
+	}
+
+	// the pool reports the previous rendered configuration as updated until the machine config operator
+	// renders the updated components, so a snapshot is taken only once the pool runs a new configuration
+	if !applied &&
+		conditionsv1.IsStatusConditionTrue(conditions, conditionsv1.ConditionAvailable) &&
+		isMachineConfigPoolUpdated(profileMCP, machineconfig.GetMachineConfigName(profile)) {
+		if snapshot == nil || snapshot.renderedConfig != current.renderedConfig {
+			return r.saveRollbackSnapshot(ctx, profile, current)
+		}
+		return nil
+	}
+
+	if snapshot == nil || isRollbackSnapshotEqual(snapshot, current) {
+		return nil
+	}
+
+	reason, message, err := r.getRolloutFailure(profile, profileMCP, *autoRollback.DegradedTunedProfiles)
+	if err != nil || reason == "" {
+		return err
+	}
+
+	klog.Infof("Rollout of performance profile %q components failed, restore the components of the profile generation %d: %s", profile.Name, snapshot.profileGeneration, message)
+	if err := r.restoreRollbackSnapshot(ctx, profile, snapshot); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(profile, corev1.EventTypeWarning, "Rolled back", "Restored the components of the profile generation %d: %s", snapshot.profileGeneration, message)
+
+	profileCopy := profile.DeepCopy()
+	profileCopy.Status.Rollback = &performancev2.RollbackStatus{
+		ProfileGeneration:         profile.Generation,
+		RestoredProfileGeneration: snapshot.profileGeneration,
+		Reason:                    reason,
+		Message:                   message,
+		RollbackTime:              metav1.Now(),
+	}
+	if err := r.Status().Update(ctx, profileCopy); err != nil {
+		return err
+	}
+	*profile = *profileCopy
+	return nil
+}
+
+// getRolloutFailure returns the reason and the description of the rollout failure,
+// the reason is empty when the rollout did not fail
+func (r *PerformanceProfileReconciler) getRolloutFailure(profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool, degradedTunedProfilesThreshold int32) (string, string, error) {
+	if profileMCP.Status.DegradedMachineCount > 0 {
+		return conditionReasonMCPDegraded, fmt.Sprintf("%d nodes of the machine config pool %q failed to apply the configuration %q",
+			profileMCP.Status.DegradedMachineCount, profileMCP.Name, profileMCP.Spec.Configuration.Name), nil
+	}
+
+	degraded, err := r.getDegradedTunedProfiles(profile)
+	if err != nil {
+		return "", "", err
+	}
+	if len(degraded) == 0 || int32(len(degraded)) < degradedTunedProfilesThreshold {
+		return "", "", nil
+	}
+
+	names := make([]string, 0, len(degraded))
+	for _, tunedProfile := range degraded {
+		names = append(names, tunedProfile.Name)
+	}
+	return conditionReasonTunedDegraded, fmt.Sprintf("%d nodes have a degraded tuned profile: %s", len(degraded), strings.Join(names, ", ")), nil
+}
+
+// getCurrentRollbackSnapshot returns the profile components that exist in the cluster
+func (r *PerformanceProfileReconciler) getCurrentRollbackSnapshot(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool) (*rollbackSnapshot, error) {
+	snapshot := &rollbackSnapshot{
+		profileGeneration: profile.Generation,
+		renderedConfig:    profileMCP.Spec.Configuration.Name,
+	}
+
+	mc, err := r.getMachineConfig(ctx, machineconfig.GetMachineConfigName(profile))
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		snapshot.machineConfig = &mcov1.MachineConfig{
+			TypeMeta:   mc.TypeMeta,
+			ObjectMeta: getRollbackObjectMeta(&mc.ObjectMeta),
+			Spec:       *mc.Spec.DeepCopy(),
+		}
+	}
+
+	kc, err := r.getKubeletConfig(components.GetComponentName(profile.Name, components.ComponentNamePrefix))
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		snapshot.kubeletConfig = &mcov1.KubeletConfig{
+			TypeMeta:   kc.TypeMeta,
+			ObjectMeta: getRollbackObjectMeta(&kc.ObjectMeta),
+			Spec:       *kc.Spec.DeepCopy(),
+		}
+	}
+
+	tuned, err := r.getTuned(components.GetComponentName(profile.Name, components.ProfileNamePerformance), components.NamespaceNodeTuningOperator)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		snapshot.tuned = &tunedv1.Tuned{
+			TypeMeta:   tuned.TypeMeta,
+			ObjectMeta: getRollbackObjectMeta(&tuned.ObjectMeta),
+			Spec:       *tuned.Spec.DeepCopy(),
+		}
+	}
+	return snapshot, nil
+}
+
+// getRollbackSnapshot returns the profile components saved by the last successful rollout,
+// or nil when there is no snapshot yet
+func (r *PerformanceProfileReconciler) getRollbackSnapshot(ctx context.Context, profile *performancev2.PerformanceProfile) (*rollbackSnapshot, error) {
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{
+		Name:      getRollbackName(profile),
+		Namespace: components.NamespaceNodeTuningOperator,
+	}
+	if err := r.Get(ctx, key, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	generation, err := strconv.ParseInt(cm.Annotations[rollbackProfileGenerationAnnotation], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %q annotation of the config map %q: %w", rollbackProfileGenerationAnnotation, key.String(), err)
+	}
+
+	snapshot := &rollbackSnapshot{
+		profileGeneration: generation,
+		renderedConfig:    cm.Annotations[rollbackRenderedConfigAnnotation],
+	}
+	if data, ok := cm.Data[rollbackMachineConfigKey]; ok {
+		snapshot.machineConfig = &mcov1.MachineConfig{}
+		if err := json.Unmarshal([]byte(data), snapshot.machineConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse the machine config of the config map %q: %w", key.String(), err)
+		}
+	}
+	if data, ok := cm.Data[rollbackKubeletConfigKey]; ok {
+		snapshot.kubeletConfig = &mcov1.KubeletConfig{}
+		if err := json.Unmarshal([]byte(data), snapshot.kubeletConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse the kubelet config of the config map %q: %w", key.String(), err)
+		}
+	}
+	if data, ok := cm.Data[rollbackTunedKey]; ok {
+		snapshot.tuned = &tunedv1.Tuned{}
+		if err := json.Unmarshal([]byte(data), snapshot.tuned); err != nil {
+			return nil, fmt.Errorf("failed to parse the tuned of the config map %q: %w", key.String(), err)
+		}
+	}
+	return snapshot, nil
+}
+
+func (r *PerformanceProfileReconciler) saveRollbackSnapshot(ctx context.Context, profile *performancev2.PerformanceProfile, snapshot *rollbackSnapshot) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getRollbackName(profile),
+			Namespace: components.NamespaceNodeTuningOperator,
+			Annotations: map[string]string{
+				rollbackProfileGenerationAnnotation: strconv.FormatInt(snapshot.profileGeneration, 10),
+				rollbackRenderedConfigAnnotation:    snapshot.renderedConfig,
+			},
+		},
+		Data: map[string]string{},
+	}
+	if err := controllerutil.SetControllerReference(profile, cm, r.Scheme); err != nil {
+		return err
+	}
+
+	objects := map[string]interface{}{}
+	if snapshot.machineConfig != nil {
+		objects[rollbackMachineConfigKey] = snapshot.machineConfig
+	}
+	if snapshot.kubeletConfig != nil {
+		objects[rollbackKubeletConfigKey] = snapshot.kubeletConfig
+	}
+	if snapshot.tuned != nil {
+		objects[rollbackTunedKey] = snapshot.tuned
+	}
+	for key, obj := range objects {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		cm.Data[key] = string(data)
+	}
+
+	existing := &corev1.ConfigMap{}
+	err := r.Get(ctx, namespacedName(cm), existing)
+	if errors.IsNotFound(err) {
+		klog.Infof("Create rollback snapshot %q of the performance profile %q for the configuration %q", cm.Name, profile.Name, snapshot.renderedConfig)
+		return r.Create(ctx, cm)
+	}
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("Update rollback snapshot %q of the performance profile %q for the configuration %q", cm.Name, profile.Name, snapshot.renderedConfig)
+	existing.Annotations = cm.Annotations
+	existing.OwnerReferences = cm.OwnerReferences
+	existing.Data = cm.Data
+	return r.Update(ctx, existing)
+}
+
+func (r *PerformanceProfileReconciler) restoreRollbackSnapshot(ctx context.Context, profile *performancev2.PerformanceProfile, snapshot *rollbackSnapshot) error {
+	if snapshot.machineConfig != nil {
+		mc := snapshot.machineConfig.DeepCopy()
+		existing, err := r.getMachineConfig(ctx, mc.Name)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			mc.ObjectMeta = *existing.ObjectMeta.DeepCopy()
+			mc.Labels = snapshot.machineConfig.Labels
+			mc.Annotations = snapshot.machineConfig.Annotations
+		}
+		if err := r.createOrUpdateMachineConfig(mc, profile.Name); err != nil {
+			return err
+		}
+	}
+
+	if snapshot.tuned != nil {
+		tuned := snapshot.tuned.DeepCopy()
+		existing, err := r.getTuned(tuned.Name, tuned.Namespace)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			tuned.ObjectMeta = *existing.ObjectMeta.DeepCopy()
+			tuned.Labels = snapshot.tuned.Labels
+			tuned.Annotations = snapshot.tuned.Annotations
+		}
+		if err := r.createOrUpdateTuned(tuned, profile.Name); err != nil {
+			return err
+		}
+	}
+
+	if snapshot.kubeletConfig != nil {
+		kc := snapshot.kubeletConfig.DeepCopy()
+		existing, err := r.getKubeletConfig(kc.Name)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			kc.ObjectMeta = *existing.ObjectMeta.DeepCopy()
+			kc.Labels = snapshot.kubeletConfig.Labels
+			kc.Annotations = snapshot.kubeletConfig.Annotations
+		}
+		if err := r.createOrUpdateKubeletConfig(kc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PerformanceProfileReconciler) deleteRollbackSnapshot(profile *performancev2.PerformanceProfile) error {
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{
+		Name:      getRollbackName(profile),
+		Namespace: components.NamespaceNodeTuningOperator,
+	}
+	if err := r.Get(context.TODO(), key, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return r.Delete(context.TODO(), cm)
+}
+
+// isRolledBack returns true while the profile runs the restored components, the components
+// are rendered again once the profile generation changes
+func isRolledBack(profile *performancev2.PerformanceProfile) bool {
+	return profile.Status.Rollback != nil && profile.Status.Rollback.ProfileGeneration == profile.Generation
+}
+
+// isRollbackSnapshotEqual returns true when both snapshots hold the same components,
+// the metadata changes do not impact the nodes
+func isRollbackSnapshotEqual(a, b *rollbackSnapshot) bool {
+	if (a.machineConfig == nil) != (b.machineConfig == nil) ||
+		(a.kubeletConfig == nil) != (b.kubeletConfig == nil) ||
+		(a.tuned == nil) != (b.tuned == nil) {
+		return false
+	}
+
+	if a.machineConfig != nil && (a.machineConfig.Name != b.machineConfig.Name ||
+		!apiequality.Semantic.DeepEqual(a.machineConfig.Spec, b.machineConfig.Spec)) {
+		return false
+	}
+	if a.kubeletConfig != nil && !apiequality.Semantic.DeepEqual(a.kubeletConfig.Spec, b.kubeletConfig.Spec) {
+		return false
+	}
+	if a.tuned != nil && !apiequality.Semantic.DeepEqual(a.tuned.Spec, b.tuned.Spec) {
+		return false
+	}
+	return true
+}
+
+func getRollbackName(profile *performancev2.PerformanceProfile) string {
+	return components.GetComponentName(profile.Name, rollbackNamePrefix)
+}
+
+func getRollbackObjectMeta(meta *metav1.ObjectMeta) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            meta.Name,
+		Namespace:       meta.Namespace,
+		Labels:          meta.Labels,
+		Annotations:     meta.Annotations,
+		OwnerReferences: meta.OwnerReferences,
+	}
+}
+
+func getRolledBackCondition(rollback *performancev2.RollbackStatus) conditionsv1.Condition {
+	return conditionsv1.Condition{
+		Type:               conditionTypeRolledBack,
+		Status:             corev1.ConditionTrue,
+		Reason:             rollback.Reason,
+		Message:            fmt.Sprintf("Restored the components of the profile generation %d: %s", rollback.RestoredProfileGeneration, rollback.Message),
+		LastTransitionTime: rollback.RollbackTime,
+		LastHeartbeatTime:  metav1.Now(),
+	}
+}
@@ -0,0 +1,239 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// RuntimeClassInjectionWebhookPath is the path the runtime class injection webhook is served at
+const RuntimeClassInjectionWebhookPath = "/mutate-v1-pod-runtimeclass"
+
+// RuntimeClassInjectionReconciler resolves the RuntimeClass of the performance profile the namespaces
+// opt in with the performance.openshift.io/inject-runtimeclass label, and records it on the namespace
+// for the runtime class injection webhook.
+type RuntimeClassInjectionReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// SetupWithManager creates a new runtime class injection Controller and adds it to the Manager.
+func (r *RuntimeClassInjectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the namespaces which opted out still carry the resolved runtime class until the reconcile drops it
+	namespacePredicates := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		_, labeled := object.GetLabels()[performancev2.PerformanceProfileInjectRuntimeClassLabel]
+		_, annotated := object.GetAnnotations()[performancev2.PerformanceProfileRuntimeClassAnnotation]
+		return labeled || annotated
+	})
+
+	profilePredicates := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !validateUpdateEvent(&e) {
+				return false
+			}
+
+			profileOld := e.ObjectOld.(*performancev2.PerformanceProfile)
+			profileNew := e.ObjectNew.(*performancev2.PerformanceProfile)
+			return getInjectedRuntimeClassName(profileOld) != getInjectedRuntimeClassName(profileNew)
+		},
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("runtimeclass-injection").
+		For(&corev1.Namespace{}, builder.WithPredicates(namespacePredicates)).
+		Watches(&performancev2.PerformanceProfile{},
+			handler.EnqueueRequestsFromMapFunc(r.profileToNamespaces),
+			builder.WithPredicates(profilePredicates)).
+		Complete(r)
+}
+
+// profileToNamespaces enqueues the namespaces which opted in the runtime class of the profile
+func (r *RuntimeClassInjectionReconciler) profileToNamespaces(ctx context.Context, object client.Object) []reconcile.Request {
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces, client.MatchingLabels{performancev2.PerformanceProfileInjectRuntimeClassLabel: object.GetName()}); err != nil {
+		klog.Errorf("failed to list the namespaces of the performance profile %q: %v", object.GetName(), err)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, ns := range namespaces.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ns.Name}})
+	}
+	return requests
+}
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;update;patch
+
+// Reconcile records the RuntimeClass of the performance profile named by the namespace label on the namespace,
+// and drops it once the namespace opts out or the profile is gone.
+func (r *RuntimeClassInjectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, req.NamespacedName, ns); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	runtimeClassName := ""
+	if profileName, ok := ns.Labels[performancev2.PerformanceProfileInjectRuntimeClassLabel]; ok {
+		profile := &performancev2.PerformanceProfile{}
+		err := r.Get(ctx, types.NamespacedName{Name: profileName}, profile)
+		if err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		if errors.IsNotFound(err) || profile.DeletionTimestamp != nil {
+			r.Recorder.Eventf(ns, corev1.EventTypeWarning, "ProfileNotFound",
+				"The performance profile %q of the runtime class injection does not exist, the pods run with the default runtime class", profileName)
+		} else {
+			runtimeClassName = getInjectedRuntimeClassName(profile)
+		}
+	}
+
+	if ns.Annotations[performancev2.PerformanceProfileRuntimeClassAnnotation] == runtimeClassName {
+		return reconcile.Result{}, nil
+	}
+
+	patch := client.MergeFrom(ns.DeepCopy())
+	if runtimeClassName == "" {
+		klog.Infof("Stop the runtime class injection in the namespace %q", ns.Name)
+		delete(ns.Annotations, performancev2.PerformanceProfileRuntimeClassAnnotation)
+	} else {
+		klog.Infof("Inject the runtime class %q in the namespace %q", runtimeClassName, ns.Name)
+		if ns.Annotations == nil {
+			ns.Annotations = map[string]string{}
+		}
+		ns.Annotations[performancev2.PerformanceProfileRuntimeClassAnnotation] = runtimeClassName
+	}
+	return reconcile.Result{}, r.Patch(ctx, ns, patch)
+}
+
+// getInjectedRuntimeClassName returns the name of the RuntimeClass of the profile, the status reports it once
+// the profile components are created
+func getInjectedRuntimeClassName(profile *performancev2.PerformanceProfile) string {
+	if profile.Status.RuntimeClass != nil {
+		return *profile.Status.RuntimeClass
+	}
+	return components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+}
+
+// RuntimeClassInjector defaults the runtime class of the pods created in the namespaces which opted in
+// the RuntimeClass of a performance profile
+type RuntimeClassInjector struct {
+	reader  client.Reader
+	decoder *admission.Decoder
+}
+
+// NewRuntimeClassInjector returns a new RuntimeClassInjector reading the namespaces and the runtime classes
+// with the reader 'reader'
+func NewRuntimeClassInjector(reader client.Reader, scheme *runtime.Scheme) *RuntimeClassInjector {
+	return &RuntimeClassInjector{
+		reader:  reader,
+		decoder: admission.NewDecoder(scheme),
+	}
+}
+
+// Handle sets the runtime class the namespace of the pod resolved, unless the pod sets its own.
+// The RuntimeClass admission plugin runs before the webhooks, so the scheduling constraints of the
+// runtime class are merged into the pod as well.
+func (i *RuntimeClassInjector) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create {
+		return admission.Allowed("")
+	}
+
+	pod := &corev1.Pod{}
+	if err := i.decoder.Decode(req, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if pod.Spec.RuntimeClassName != nil {
+		return admission.Allowed("the pod sets its runtime class")
+	}
+
+	ns := &corev1.Namespace{}
+	if err := i.reader.Get(ctx, types.NamespacedName{Name: req.Namespace}, ns); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	runtimeClassName := ns.Annotations[performancev2.PerformanceProfileRuntimeClassAnnotation]
+	if runtimeClassName == "" {
+		if profileName, ok := ns.Labels[performancev2.PerformanceProfileInjectRuntimeClassLabel]; ok {
+			return admission.Allowed("").WithWarnings(
+				fmt.Sprintf("the runtime class of the performance profile %q is not resolved yet, the pod runs with the default runtime class", profileName))
+		}
+		return admission.Allowed("")
+	}
+
+	runtimeClass := &nodev1.RuntimeClass{}
+	if err := i.reader.Get(ctx, types.NamespacedName{Name: runtimeClassName}, runtimeClass); err != nil {
+		if errors.IsNotFound(err) {
+			return admission.Allowed("").WithWarnings(
+				fmt.Sprintf("the runtime class %q does not exist, the pod runs with the default runtime class", runtimeClassName))
+		}
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if err := setPodRuntimeClass(pod, runtimeClass); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	marshaled, err := json.Marshal(pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// setPodRuntimeClass sets the runtime class 'runtimeClass' to the pod 'pod' and merges its scheduling
+// constraints the way the RuntimeClass admission plugin does
+func setPodRuntimeClass(pod *corev1.Pod, runtimeClass *nodev1.RuntimeClass) error {
+	pod.Spec.RuntimeClassName = &runtimeClass.Name
+	if runtimeClass.Scheduling == nil {
+		return nil
+	}
+
+	for key, value := range runtimeClass.Scheduling.NodeSelector {
+		if podValue, ok := pod.Spec.NodeSelector[key]; ok && podValue != value {
+			return fmt.Errorf("conflict: runtimeClass.scheduling.nodeSelector[%s] = %s; pod.spec.nodeSelector[%s] = %s", key, value, key, podValue)
+		}
+		if pod.Spec.NodeSelector == nil {
+			pod.Spec.NodeSelector = map[string]string{}
+		}
+		pod.Spec.NodeSelector[key] = value
+	}
+
+	for _, toleration := range runtimeClass.Scheduling.Tolerations {
+		if !hasToleration(pod.Spec.Tolerations, &toleration) {
+			pod.Spec.Tolerations = append(pod.Spec.Tolerations, toleration)
+		}
+	}
+	return nil
+}
+
+func hasToleration(tolerations []corev1.Toleration, toleration *corev1.Toleration) bool {
+	for i := range tolerations {
+		if tolerations[i].MatchToleration(toleration) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/runtimeclass"
+	testutils "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/utils/testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("Runtime class injection", func() {
+	var profile *performancev2.PerformanceProfile
+	var ns *corev1.Namespace
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		profile.Status.RuntimeClass = pointer.String(getInjectedRuntimeClassName(profile))
+		ns = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "app",
+				Labels: map[string]string{
+					performancev2.PerformanceProfileInjectRuntimeClassLabel: profile.Name,
+				},
+			},
+		}
+	})
+
+	Context("with the namespace controller", func() {
+		reconcileNamespace := func(r *RuntimeClassInjectionReconciler) *corev1.Namespace {
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: ns.Name}}
+			_, err := r.Reconcile(context.TODO(), request)
+			Expect(err).ToNot(HaveOccurred())
+
+			updated := &corev1.Namespace{}
+			Expect(r.Get(context.TODO(), request.NamespacedName, updated)).To(Succeed())
+			return updated
+		}
+
+		It("should record the runtime class of the profile on the namespace", func() {
+			r := newFakeRuntimeClassInjectionReconciler(ns, profile)
+
+			updated := reconcileNamespace(r)
+			Expect(updated.Annotations).To(HaveKeyWithValue(performancev2.PerformanceProfileRuntimeClassAnnotation, *profile.Status.RuntimeClass))
+		})
+
+		It("should drop the runtime class once the namespace opts out", func() {
+			ns.Annotations = map[string]string{performancev2.PerformanceProfileRuntimeClassAnnotation: *profile.Status.RuntimeClass}
+			delete(ns.Labels, performancev2.PerformanceProfileInjectRuntimeClassLabel)
+			r := newFakeRuntimeClassInjectionReconciler(ns, profile)
+
+			updated := reconcileNamespace(r)
+			Expect(updated.Annotations).ToNot(HaveKey(performancev2.PerformanceProfileRuntimeClassAnnotation))
+		})
+
+		It("should report the missing profile", func() {
+			ns.Labels[performancev2.PerformanceProfileInjectRuntimeClassLabel] = "missing"
+			r := newFakeRuntimeClassInjectionReconciler(ns, profile)
+
+			updated := reconcileNamespace(r)
+			Expect(updated.Annotations).ToNot(HaveKey(performancev2.PerformanceProfileRuntimeClassAnnotation))
+
+			fakeRecorder, ok := r.Recorder.(*record.FakeRecorder)
+			Expect(ok).To(BeTrue())
+			Expect(<-fakeRecorder.Events).To(ContainSubstring("ProfileNotFound"))
+		})
+
+		It("should enqueue the namespaces of the profile", func() {
+			other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+			r := newFakeRuntimeClassInjectionReconciler(ns, other, profile)
+
+			requests := r.profileToNamespaces(context.TODO(), profile)
+			Expect(requests).To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Name: ns.Name}}))
+		})
+	})
+
+	Context("with the pod webhook", func() {
+		var pod *corev1.Pod
+
+		BeforeEach(func() {
+			ns.Annotations = map[string]string{performancev2.PerformanceProfileRuntimeClassAnnotation: *profile.Status.RuntimeClass}
+			pod = &corev1.Pod{
+				TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app",
+					Namespace: ns.Name,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "app"}},
+				},
+			}
+		})
+
+		handle := func(injector *RuntimeClassInjector) admission.Response {
+			raw, err := json.Marshal(pod)
+			Expect(err).ToNot(HaveOccurred())
+
+			return injector.Handle(context.TODO(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Namespace: ns.Name,
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+			})
+		}
+
+		It("should set the runtime class and its node selector", func() {
+			injector := newFakeRuntimeClassInjector(ns, runtimeclass.New(profile, "high-performance"))
+
+			res := handle(injector)
+			Expect(res.Allowed).To(BeTrue())
+
+			paths := map[string]interface{}{}
+			for _, op := range res.Patches {
+				paths[op.Path] = op.Value
+			}
+			Expect(paths).To(HaveKeyWithValue("/spec/runtimeClassName", *profile.Status.RuntimeClass))
+			Expect(paths).To(HaveKey("/spec/nodeSelector"))
+		})
+
+		It("should keep the runtime class of the pod", func() {
+			pod.Spec.RuntimeClassName = pointer.String("other")
+			injector := newFakeRuntimeClassInjector(ns, runtimeclass.New(profile, "high-performance"))
+
+			res := handle(injector)
+			Expect(res.Allowed).To(BeTrue())
+			Expect(res.Patches).To(BeEmpty())
+		})
+
+		It("should reject the pod with a conflicting node selector", func() {
+			rc := runtimeclass.New(profile, "high-performance")
+			for key := range rc.Scheduling.NodeSelector {
+				pod.Spec.NodeSelector = map[string]string{key: "conflict"}
+			}
+			injector := newFakeRuntimeClassInjector(ns, rc)
+
+			res := handle(injector)
+			Expect(res.Allowed).To(BeFalse())
+			Expect(res.Result.Message).To(ContainSubstring("conflict"))
+		})
+
+		It("should warn when the runtime class does not exist", func() {
+			injector := newFakeRuntimeClassInjector(ns)
+
+			res := handle(injector)
+			Expect(res.Allowed).To(BeTrue())
+			Expect(res.Patches).To(BeEmpty())
+			Expect(res.Warnings).To(HaveLen(1))
+		})
+	})
+})
+
+func newFakeRuntimeClassInjectionReconciler(initObjects ...runtime.Object) *RuntimeClassInjectionReconciler {
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(initObjects...).Build()
+	return &RuntimeClassInjectionReconciler{
+		Client:   fakeClient,
+		Scheme:   scheme.Scheme,
+		Recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func newFakeRuntimeClassInjector(initObjects ...runtime.Object) *RuntimeClassInjector {
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(initObjects...).Build()
+	return NewRuntimeClassInjector(fakeClient, scheme.Scheme)
+}
@@ -3,11 +3,14 @@ package controller
 import (
 	"bytes"
 	"context"
+	"reflect"
 	"time"
 
 	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
 	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/machineconfig"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/manifestset"
 	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -30,9 +33,22 @@ const (
 	conditionReasonTunedDegraded             = "TunedProfileDegraded"
 	conditionFailedGettingTunedProfileStatus = "GettingTunedStatusFailed"
 	conditionReasonCgroupsV1NotEnabled       = "CgroupsV1NotEnabled"
+	conditionReasonCgroupsV2NotEnabled       = "CgroupsV2NotEnabled"
+	conditionFailedGettingRolloutStatus      = "GettingRolloutStatusFailed"
+	conditionFailedGettingRebootStatus       = "GettingRebootStatusFailed"
+	conditionFailedGettingKubeletConfigSync  = "GettingKubeletConfigSyncFailed"
+	conditionReasonNodeSelectorChanged       = "NodeSelectorChanged"
+	conditionReasonMigrationFailed           = "MigrationFailed"
+	conditionReasonRollbackFailed            = "RollbackFailed"
+	conditionReasonCPUPartitioningMismatch   = "CPUPartitioningMismatch"
+	conditionReasonExportArtifactsFailed     = "ExportArtifactsFailed"
 )
 
 func (r *PerformanceProfileReconciler) updateStatus(profile *performancev2.PerformanceProfile, conditions []conditionsv1.Condition) error {
+	return r.updateStatusWithRollout(profile, conditions, profile.Status.Rollout, profile.Status.KubeletConfigSync, profile.Status.SkippedFeatures)
+}
+
+func (r *PerformanceProfileReconciler) updateStatusWithRollout(profile *performancev2.PerformanceProfile, conditions []conditionsv1.Condition, rollout *performancev2.RolloutStatus, kubeletConfigSync *performancev2.KubeletConfigSyncStatus, skippedFeatures []string) error {
 	profileCopy := profile.DeepCopy()
 
 	if conditions != nil {
@@ -42,7 +58,31 @@ func (r *PerformanceProfileReconciler) updateStatus(profile *performancev2.Perfo
 	// check if we need to update the status
 	modified := false
 
-	// since we always set the same four conditions, we don't need to check if we need to remove old conditions
+	if !reflect.DeepEqual(profile.Status.Rollout, rollout) {
+		profileCopy.Status.Rollout = rollout.DeepCopy()
+		modified = true
+	}
+
+	if !reflect.DeepEqual(profile.Status.KubeletConfigSync, kubeletConfigSync) {
+		profileCopy.Status.KubeletConfigSync = kubeletConfigSync.DeepCopy()
+		modified = true
+	}
+
+	if !reflect.DeepEqual(profile.Status.SkippedFeatures, skippedFeatures) {
+		profileCopy.Status.SkippedFeatures = skippedFeatures
+		modified = true
+	}
+
+	if skippedArtifacts := manifestset.GetSkippedArtifacts(profile); !reflect.DeepEqual(profile.Status.SkippedArtifacts, skippedArtifacts) {
+		profileCopy.Status.SkippedArtifacts = skippedArtifacts
+		modified = true
+	}
+
+	// the Migrating condition is set only while the profile moves between pools, so check for its removal
+	if len(profile.Status.Conditions) != len(profileCopy.Status.Conditions) {
+		modified = true
+	}
+
 	for _, newCondition := range profileCopy.Status.Conditions {
 		oldCondition := conditionsv1.FindStatusCondition(profile.Status.Conditions, newCondition.Type)
 		if oldCondition == nil {
@@ -75,6 +115,11 @@ func (r *PerformanceProfileReconciler) updateStatus(profile *performancev2.Perfo
 		modified = true
 	}
 
+	if exportedArtifacts := getExportedArtifactsStatus(profile); !reflect.DeepEqual(profile.Status.ExportedArtifacts, exportedArtifacts) {
+		profileCopy.Status.ExportedArtifacts = exportedArtifacts
+		modified = true
+	}
+
 	if !modified {
 		return nil
 	}
@@ -196,6 +241,36 @@ func (r *PerformanceProfileReconciler) getMCPDegradedCondition(profileMCP *mcov1
 	return r.getDegradedConditions(conditionReasonMCPDegraded, messageString), nil
 }
 
+// getRolloutStatus returns the rollout progress of the generated machine config on the nodes of the profile MCP,
+// the MCO updates the MCP machine counts based on the nodes current and desired configuration annotations
+func (r *PerformanceProfileReconciler) getRolloutStatus(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool) (*performancev2.RolloutStatus, error) {
+	mcName := machineconfig.GetMachineConfigName(profile)
+	mc, err := r.getMachineConfig(ctx, mcName)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rollout := &performancev2.RolloutStatus{
+		MachineConfig:           mc.Name,
+		MachineConfigGeneration: mc.Generation,
+		MachineConfigPool:       profileMCP.Name,
+		TotalNodes:              profileMCP.Status.MachineCount,
+		DegradedNodes:           profileMCP.Status.DegradedMachineCount,
+	}
+
+	// the updated machine count refers to the MCP target configuration, it is relevant only
+	// once the MCO rendered the generated machine config into it
+	if isMachineConfigRendered(profileMCP, mc.Name) {
+		rollout.UpdatedNodes = profileMCP.Status.UpdatedMachineCount
+	}
+	rollout.PendingNodes = rollout.TotalNodes - rollout.UpdatedNodes
+
+	return rollout, nil
+}
+
 func (r *PerformanceProfileReconciler) getKubeletConditionsByProfile(profile *performancev2.PerformanceProfile) ([]conditionsv1.Condition, error) {
 	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
 	kc, err := r.getKubeletConfig(name)
@@ -222,6 +297,32 @@ func (r *PerformanceProfileReconciler) getKubeletConditionsByProfile(profile *pe
 }
 
 func (r *PerformanceProfileReconciler) getTunedConditionsByProfile(profile *performancev2.PerformanceProfile) ([]conditionsv1.Condition, error) {
+	degraded, err := r.getDegradedTunedProfiles(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	message := bytes.Buffer{}
+	for i := range degraded {
+		tunedDegradedCondition := getTunedDegradedCondition(&degraded[i])
+		if len(tunedDegradedCondition.Reason) > 0 {
+			message.WriteString("Tuned " + degraded[i].GetName() + " Degraded Reason: " + tunedDegradedCondition.Reason + ".\n")
+		}
+		if len(tunedDegradedCondition.Message) > 0 {
+			message.WriteString("Tuned " + degraded[i].GetName() + " Degraded Message: " + tunedDegradedCondition.Message + ".\n")
+		}
+	}
+
+	messageString := message.String()
+	if len(messageString) == 0 {
+		return nil, nil
+	}
+
+	return r.getDegradedConditions(conditionReasonTunedDegraded, messageString), nil
+}
+
+// getDegradedTunedProfiles returns the degraded tuned profiles of the nodes targeted by the profile
+func (r *PerformanceProfileReconciler) getDegradedTunedProfiles(profile *performancev2.PerformanceProfile) ([]tunedv1.Profile, error) {
 	tunedProfileList := &tunedv1.ProfileList{}
 	if err := r.List(context.TODO(), tunedProfileList); err != nil {
 		klog.Errorf("Cannot list Tuned Profiles to match with profile %q : %v", profile.Name, err)
@@ -237,41 +338,37 @@ func (r *PerformanceProfileReconciler) getTunedConditionsByProfile(profile *perf
 	// remove Tuned profiles that are not associate with this performance profile
 	// Tuned profile's name and node's name should be equal
 	filtered := removeUnMatchedTunedProfiles(nodes.Items, tunedProfileList.Items)
-	message := bytes.Buffer{}
-	for _, tunedProfile := range filtered {
-		isDegraded := false
-		isApplied := true
-		var tunedDegradedCondition *tunedv1.ProfileStatusCondition
-
-		for i := 0; i < len(tunedProfile.Status.Conditions); i++ {
-			condition := &tunedProfile.Status.Conditions[i]
-			if (condition.Type == tunedv1.TunedDegraded) && condition.Status == corev1.ConditionTrue {
-				isDegraded = true
-				tunedDegradedCondition = condition
-			}
+	var degraded []tunedv1.Profile
+	for i := range filtered {
+		if getTunedDegradedCondition(&filtered[i]) != nil {
+			degraded = append(degraded, filtered[i])
+		}
+	}
+	return degraded, nil
+}
 
-			if (condition.Type == tunedv1.TunedProfileApplied) && condition.Status == corev1.ConditionFalse {
-				isApplied = false
-			}
+// getTunedDegradedCondition returns the degraded condition of the tuned profile,
+// or nil when the tuned profile is not degraded
+func getTunedDegradedCondition(tunedProfile *tunedv1.Profile) *tunedv1.ProfileStatusCondition {
+	isApplied := true
+	var tunedDegradedCondition *tunedv1.ProfileStatusCondition
+
+	for i := 0; i < len(tunedProfile.Status.Conditions); i++ {
+		condition := &tunedProfile.Status.Conditions[i]
+		if (condition.Type == tunedv1.TunedDegraded) && condition.Status == corev1.ConditionTrue {
+			tunedDegradedCondition = condition
 		}
-		// We need both conditions to exist,
-		// since there is a scenario where both Degraded & Applied conditions are true
-		if isDegraded && !isApplied {
-			if len(tunedDegradedCondition.Reason) > 0 {
-				message.WriteString("Tuned " + tunedProfile.GetName() + " Degraded Reason: " + tunedDegradedCondition.Reason + ".\n")
-			}
-			if len(tunedDegradedCondition.Message) > 0 {
-				message.WriteString("Tuned " + tunedProfile.GetName() + " Degraded Message: " + tunedDegradedCondition.Message + ".\n")
-			}
+
+		if (condition.Type == tunedv1.TunedProfileApplied) && condition.Status == corev1.ConditionFalse {
+			isApplied = false
 		}
 	}
-
-	messageString := message.String()
-	if len(messageString) == 0 {
-		return nil, nil
+	// We need both conditions to exist,
+	// since there is a scenario where both Degraded & Applied conditions are true
+	if isApplied {
+		return nil
 	}
-
-	return r.getDegradedConditions(conditionReasonTunedDegraded, messageString), nil
+	return tunedDegradedCondition
 }
 
 func getLatestKubeletConfigCondition(conditions []mcov1.KubeletConfigCondition) *mcov1.KubeletConfigCondition {
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	profileutil "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/profile"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog"
+	"k8s.io/utils/cpuset"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileSummary keeps the summary annotation of the profile in sync with the profile spec and
+// the rollout of its components. The annotation is patched only when the summary changes; unlike
+// an update, the patch does not conflict with the status update preceding it.
+func (r *PerformanceProfileReconciler) reconcileSummary(ctx context.Context, profile *performancev2.PerformanceProfile, conditions []conditionsv1.Condition, rollout *performancev2.RolloutStatus) error {
+	summary, err := json.Marshal(getProfileSummary(profile, conditions, rollout))
+	if err != nil {
+		return err
+	}
+	if profile.Annotations[performancev2.PerformanceProfileSummaryAnnotation] == string(summary) {
+		return nil
+	}
+
+	profileCopy := profile.DeepCopy()
+	if profileCopy.Annotations == nil {
+		profileCopy.Annotations = map[string]string{}
+	}
+	profileCopy.Annotations[performancev2.PerformanceProfileSummaryAnnotation] = string(summary)
+
+	klog.Infof("Updating the performance profile %q summary", profile.Name)
+	return r.Patch(ctx, profileCopy, client.MergeFrom(profile))
+}
+
+// getProfileSummary returns the summary of the profile tuning and of the rollout of its components.
+func getProfileSummary(profile *performancev2.PerformanceProfile, conditions []conditionsv1.Condition, rollout *performancev2.RolloutStatus) *performancev2.ProfileSummary {
+	summary := &performancev2.ProfileSummary{
+		HugepagesTotal: "0",
+		RealTimeKernel: profile.Spec.RealTimeKernel != nil && profile.Spec.RealTimeKernel.Enabled != nil && *profile.Spec.RealTimeKernel.Enabled,
+		RolloutState:   performancev2.ProfileRolloutStateProgressing,
+	}
+
+	if profile.Spec.CPU != nil {
+		summary.IsolatedCPUs = cpuSetSize(profile.Spec.CPU.Isolated)
+		summary.ReservedCPUs = cpuSetSize(profile.Spec.CPU.Reserved)
+	}
+
+	if profile.Spec.HugePages != nil {
+		total := resource.NewQuantity(0, resource.BinarySI)
+		for i := range profile.Spec.HugePages.Pages {
+			page := &profile.Spec.HugePages.Pages[i]
+			count, err := profileutil.GetBootHugepagesCount(profile, page)
+			if err != nil {
+				// the percentage of the pages allocated at runtime is resolved per node
+				continue
+			}
+			size, err := resource.ParseQuantity(string(page.Size) + "i")
+			if err != nil {
+				continue
+			}
+			total.Add(*resource.NewQuantity(size.Value()*int64(count), resource.BinarySI))
+		}
+		summary.HugepagesTotal = total.String()
+	}
+
+	if rollout != nil {
+		summary.UpdatedNodes = rollout.UpdatedNodes
+		summary.TotalNodes = rollout.TotalNodes
+		if rollout.TotalNodes > 0 && rollout.PendingNodes == 0 {
+			summary.RolloutState = performancev2.ProfileRolloutStateAvailable
+		}
+	}
+	if conditionsv1.IsStatusConditionTrue(conditions, conditionsv1.ConditionDegraded) {
+		summary.RolloutState = performancev2.ProfileRolloutStateDegraded
+	} else if conditionsv1.IsStatusConditionTrue(conditions, conditionsv1.ConditionProgressing) {
+		summary.RolloutState = performancev2.ProfileRolloutStateProgressing
+	}
+
+	return summary
+}
+
+// cpuSetSize returns the number of CPUs of the CPU set 'cpus', an invalid CPU set has no CPUs
+func cpuSetSize(cpus *performancev2.CPUSet) int {
+	if cpus == nil {
+		return 0
+	}
+	set, err := cpuset.Parse(string(*cpus))
+	if err != nil {
+		return 0
+	}
+	return set.Size()
+}
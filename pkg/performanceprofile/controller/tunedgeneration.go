@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// retainPreviousTunedProfiles appends to the generated tuned the profiles of the existing tuned generation
+// that are not part of the new one, as long as some nodes of the profile did not apply the new recommended
+// profile yet. The profile names are content addressed, so the nodes keep referencing the previous
+// profile content until the operand switched them to the new profile, and
+// the previous generation is dropped once all nodes converged.
+func (r *PerformanceProfileReconciler) retainPreviousTunedProfiles(ctx context.Context, profile *performancev2.PerformanceProfile, tuned *tunedv1.Tuned) error {
+	existing, err := r.getTuned(tuned.Name, tuned.Namespace)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	previous := getPreviousTunedProfiles(existing, tuned)
+	if len(previous) == 0 {
+		return nil
+	}
+
+	converged, err := r.isTunedProfileConverged(ctx, profile, getRecommendedTunedProfile(tuned))
+	if err != nil {
+		return err
+	}
+	if converged {
+		klog.Infof("All nodes of the performance profile %q applied the tuned %q generation, removing the previous one", profile.Name, tuned.Name)
+		return nil
+	}
+
+	klog.Infof("Keeping %d profiles of the previous tuned %q generation until the nodes of the performance profile %q converge", len(previous), tuned.Name, profile.Name)
+	tuned.Spec.Profile = append(tuned.Spec.Profile, previous...)
+	return nil
+}
+
+// isTunedProfileConverged returns true when all nodes targeted by the performance profile applied the tuned profile
+func (r *PerformanceProfileReconciler) isTunedProfileConverged(ctx context.Context, profile *performancev2.PerformanceProfile, tunedProfileName string) (bool, error) {
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, &client.ListOptions{LabelSelector: labels.SelectorFromSet(profile.Spec.NodeSelector)}); err != nil {
+		return false, err
+	}
+
+	tunedProfileList := &tunedv1.ProfileList{}
+	if err := r.List(ctx, tunedProfileList); err != nil {
+		return false, err
+	}
+
+	// Tuned profile's name and node's name should be equal
+	tunedProfiles := removeUnMatchedTunedProfiles(nodes.Items, tunedProfileList.Items)
+	if len(tunedProfiles) != len(nodes.Items) {
+		return false, nil
+	}
+
+	for i := range tunedProfiles {
+		// the supplemental TuneD profiles merged on top of the profile tuned do not change the tuning it generated
+		if tunedv1.PrimaryTunedProfile(tunedProfiles[i].Status.TunedProfile) != tunedProfileName || !isTunedProfileApplied(&tunedProfiles[i]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// getPreviousTunedProfiles returns the profiles of the existing tuned missing from the generated one
+func getPreviousTunedProfiles(existing *tunedv1.Tuned, generated *tunedv1.Tuned) []tunedv1.TunedProfile {
+	names := make(map[string]bool, len(generated.Spec.Profile))
+	for _, tunedProfile := range generated.Spec.Profile {
+		if tunedProfile.Name != nil {
+			names[*tunedProfile.Name] = true
+		}
+	}
+
+	var previous []tunedv1.TunedProfile
+	for _, tunedProfile := range existing.Spec.Profile {
+		if tunedProfile.Name == nil || names[*tunedProfile.Name] {
+			continue
+		}
+		previous = append(previous, tunedProfile)
+	}
+	return previous
+}
+
+// getRecommendedTunedProfile returns the name of the tuned profile recommended by the generated tuned
+func getRecommendedTunedProfile(tuned *tunedv1.Tuned) string {
+	for _, recommend := range tuned.Spec.Recommend {
+		if recommend.Profile != nil {
+			return *recommend.Profile
+		}
+	}
+	return ""
+}
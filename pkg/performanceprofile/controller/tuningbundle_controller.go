@@ -0,0 +1,651 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	apiconfigv1 "github.com/openshift/api/config/v1"
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	ppvalidation "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2/validation"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// tuningBundleGenerationAnnotation holds the bundle generation the components were applied for
+	tuningBundleGenerationAnnotation = "performance.openshift.io/tuning-bundle-generation"
+	tuningBundleSnapshotPrefix       = "tuning-bundle"
+	tuningBundleSpecKey              = "spec.json"
+	// tuningBundleRolloutResync is the period the rollout of a bundle is checked at, on top of the component events
+	tuningBundleRolloutResync = time.Minute
+
+	conditionReasonBundleValidationFailed = "ValidationFailed"
+	conditionReasonBundleApplyFailed      = "ComponentsApplyFailed"
+	conditionReasonBundleRollingOut       = "RollingOut"
+	conditionReasonBundleRolloutFailed    = "RolloutFailed"
+	conditionReasonBundleRolledBack       = "RolledBack"
+)
+
+// TuningBundleReconciler reconciles a TuningBundle object
+type TuningBundleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// ReadOnly reports the components of the bundle differing from the cluster state without writing them
+	ReadOnly bool
+}
+
+// SetupWithManager creates a new TuningBundle Controller and adds it to the Manager.
+func (r *TuningBundleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the rollout of the bundle follows the status of its performance profile
+	profilePredicates := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !validateUpdateEvent(&e) {
+				return false
+			}
+
+			profileOld := e.ObjectOld.(*performancev2.PerformanceProfile)
+			profileNew := e.ObjectNew.(*performancev2.PerformanceProfile)
+			return profileOld.GetGeneration() != profileNew.GetGeneration() ||
+				!reflect.DeepEqual(profileOld.Status.Rollout, profileNew.Status.Rollout) ||
+				!equalConditionStatuses(profileOld.Status.Conditions, profileNew.Status.Conditions)
+		},
+	}
+
+	tunedProfilePredicates := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !validateUpdateEvent(&e) {
+				return false
+			}
+
+			tunedProfileOld := e.ObjectOld.(*tunedv1.Profile)
+			tunedProfileNew := e.ObjectNew.(*tunedv1.Profile)
+			return tunedProfileOld.Status.TunedProfile != tunedProfileNew.Status.TunedProfile ||
+				!reflect.DeepEqual(tunedProfileOld.Status.Conditions, tunedProfileNew.Status.Conditions)
+		},
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&performancev2.TuningBundle{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&performancev2.PerformanceProfile{}, builder.WithPredicates(profilePredicates)).
+		Owns(&tunedv1.Tuned{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&tunedv1.Profile{},
+			handler.EnqueueRequestsFromMapFunc(r.tunedProfileToTuningBundle),
+			builder.WithPredicates(tunedProfilePredicates)).
+		Complete(r)
+}
+
+// tunedProfileToTuningBundle enqueues the bundles rolling out, the nodes report the rollout through their tuned profiles
+func (r *TuningBundleReconciler) tunedProfileToTuningBundle(ctx context.Context, _ client.Object) []reconcile.Request {
+	bundles := &performancev2.TuningBundleList{}
+	if err := r.List(ctx, bundles); err != nil {
+		klog.Errorf("failed to get tuning bundles: %v", err)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range bundles.Items {
+		if bundles.Items[i].Status.AppliedGeneration != bundles.Items[i].Generation {
+			requests = append(requests, reconcile.Request{NamespacedName: namespacedName(&bundles.Items[i])})
+		}
+	}
+	return requests
+}
+
+// +kubebuilder:rbac:groups=performance.openshift.io,resources=tuningbundles;tuningbundles/status,verbs=*
+
+// Reconcile validates the performance profile and the supplemental Tuned CRs of the bundle together, applies them
+// together and follows their rollout. A generation of the bundle whose rollout completes is saved, and restored
+// when the rollout of a later generation fails.
+func (r *TuningBundleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	klog.V(4).Infof("Reconciling the tuning bundle %q", req.Name)
+
+	bundle := &performancev2.TuningBundle{}
+	if err := r.Get(ctx, req.NamespacedName, bundle); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// the components and the saved generation are garbage collected with the bundle
+	if bundle.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	status := bundle.Status.DeepCopy()
+
+	// the restored components are kept until the bundle changes
+	if isTuningBundleRolledBack(bundle) {
+		status.Conditions = getTuningBundleDegradedConditions(conditionReasonBundleRolledBack, getTuningBundleRolledBackMessage(bundle.Status.Rollback))
+		return ctrl.Result{}, r.updateTuningBundleStatus(ctx, bundle, status)
+	}
+
+	warnings, allErrs, err := r.validateTuningBundle(ctx, bundle)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(allErrs) > 0 {
+		klog.Errorf("tuning bundle %q is invalid: %v", bundle.Name, allErrs.ToAggregate())
+		r.Recorder.Eventf(bundle, corev1.EventTypeWarning, "ValidationFailed", "None of the components were applied: %v", allErrs.ToAggregate())
+		status.Conditions = getTuningBundleDegradedConditions(conditionReasonBundleValidationFailed, allErrs.ToAggregate().Error())
+		return ctrl.Result{}, r.updateTuningBundleStatus(ctx, bundle, status)
+	}
+	for _, warning := range warnings {
+		r.Recorder.Event(bundle, corev1.EventTypeWarning, "ValidationWarning", warning)
+	}
+
+	// the rollout of a generation is followed from the reconcile after the one applying it,
+	// once the components had a chance to report on the generation
+	rollingOut := bundle.Status.ObservedGeneration == bundle.Generation
+	if !rollingOut && !r.ReadOnly {
+		// the pool configuration is recorded before applying, the rollout waits for the pool to update to a new one
+		status.RolloutSourceConfig, err = r.getTuningBundleSourceConfig(ctx, bundle)
+	}
+	var pending []string
+	if err == nil {
+		pending, err = r.applyTuningBundle(ctx, bundle, &bundle.Spec, bundle.Generation)
+	}
+	if err != nil {
+		klog.Errorf("failed to apply tuning bundle %q components: %v", bundle.Name, err)
+		status.Conditions = getTuningBundleDegradedConditions(conditionReasonBundleApplyFailed, err.Error())
+		if err := r.updateTuningBundleStatus(ctx, bundle, status); err != nil {
+			klog.Errorf("failed to update tuning bundle %q status: %v", bundle.Name, err)
+		}
+		return ctrl.Result{}, err
+	}
+	if len(pending) > 0 {
+		// nothing rolls out in the read-only mode, the bundle reports the components it would write
+		status.Conditions = getTuningBundleConditions(corev1.ConditionFalse, corev1.ConditionTrue, corev1.ConditionFalse, conditionReasonReadOnlyChangesPending,
+			fmt.Sprintf("The operator runs in the read-only mode, the components %s of the generation %d are not written", strings.Join(pending, ", "), bundle.Generation))
+		return ctrl.Result{}, r.updateTuningBundleStatus(ctx, bundle, status)
+	}
+	status.ObservedGeneration = bundle.Generation
+
+	if !rollingOut {
+		status.Conditions = getTuningBundleProgressingConditions(fmt.Sprintf("Applied the components of the generation %d", bundle.Generation))
+		return ctrl.Result{RequeueAfter: tuningBundleRolloutResync}, r.updateTuningBundleStatus(ctx, bundle, status)
+	}
+
+	done, reason, message, err := r.getTuningBundleRolloutState(ctx, bundle)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	switch {
+	case reason != "" && status.AppliedGeneration != bundle.Generation:
+		return ctrl.Result{}, r.rollbackTuningBundle(ctx, bundle, status, reason, message)
+	case reason != "":
+		status.Conditions = getTuningBundleDegradedConditions(reason, message)
+	case done:
+		if status.AppliedGeneration != bundle.Generation {
+			if err := r.saveTuningBundleSnapshot(ctx, bundle); err != nil {
+				return ctrl.Result{}, err
+			}
+			klog.Infof("Tuning bundle %q generation %d rolled out", bundle.Name, bundle.Generation)
+			r.Recorder.Eventf(bundle, corev1.EventTypeNormal, "RolledOut", "The components of the generation %d rolled out", bundle.Generation)
+			status.AppliedGeneration = bundle.Generation
+		}
+		status.Conditions = getTuningBundleAvailableConditions()
+	default:
+		status.Conditions = getTuningBundleProgressingConditions(fmt.Sprintf("Rolling out the components of the generation %d", bundle.Generation))
+		return ctrl.Result{RequeueAfter: tuningBundleRolloutResync}, r.updateTuningBundleStatus(ctx, bundle, status)
+	}
+
+	return ctrl.Result{}, r.updateTuningBundleStatus(ctx, bundle, status)
+}
+
+// validateTuningBundle validates the components of the bundle all together, the same way the admission webhooks
+// validate them one by one
+func (r *TuningBundleReconciler) validateTuningBundle(ctx context.Context, bundle *performancev2.TuningBundle) ([]string, field.ErrorList, error) {
+	ppList := &performancev2.PerformanceProfileList{}
+	if err := r.List(ctx, ppList); err != nil {
+		return nil, nil, err
+	}
+	state := ppvalidation.ClusterState{Profiles: ppList.Items}
+
+	// the node checks are best effort, the same as in the admission webhook
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, client.MatchingLabels(bundle.Spec.PerformanceProfile.NodeSelector)); err != nil {
+		klog.Warningf("failed to list the nodes of the tuning bundle %q: %v", bundle.Name, err)
+	}
+	state.Nodes = nodes.Items
+
+	tunedDefault := &tunedv1.Tuned{}
+	key := types.NamespacedName{Name: tunedv1.TunedDefaultResourceName, Namespace: components.NamespaceNodeTuningOperator}
+	if err := r.Get(ctx, key, tunedDefault); err == nil {
+		state.SysctlPolicy = tunedDefault.Spec.SysctlPolicy
+	} else if !errors.IsNotFound(err) {
+		return nil, nil, err
+	}
+
+	infra := &apiconfigv1.Infrastructure{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "cluster"}, infra); err != nil {
+		klog.Warningf("failed to get the cluster infrastructure to validate the tuning bundle %q: %v", bundle.Name, err)
+	} else {
+		state.CPUPartitioning = &infra.Status.CPUPartitioning
+	}
+
+	warnings, allErrs := ppvalidation.ValidateTuningBundle(bundle, state)
+	return warnings, allErrs, nil
+}
+
+// applyTuningBundle creates or updates the components of the bundle spec 'spec' applied for the bundle generation
+// 'generation', and deletes the Tuned CRs of the bundle missing from the spec. In the read-only mode nothing is
+// written and the components differing from the cluster state are returned instead.
+func (r *TuningBundleReconciler) applyTuningBundle(ctx context.Context, bundle *performancev2.TuningBundle, spec *performancev2.TuningBundleSpec, generation int64) ([]string, error) {
+	source := bundle.DeepCopy()
+	source.Spec = *spec
+
+	var pending []string
+	profile := source.GetPerformanceProfile()
+	changed, err := r.applyTuningBundleProfile(ctx, bundle, profile, generation)
+	if err != nil {
+		return nil, err
+	}
+	if changed && r.ReadOnly {
+		pending = append(pending, "PerformanceProfile "+profile.Name)
+	}
+
+	names := map[string]bool{}
+	for _, tuned := range source.GetTuneds(components.NamespaceNodeTuningOperator) {
+		names[tuned.Name] = true
+		changed, err := r.applyTuningBundleTuned(ctx, bundle, tuned, generation)
+		if err != nil {
+			return nil, err
+		}
+		if changed && r.ReadOnly {
+			pending = append(pending, "Tuned "+tuned.Name)
+		}
+	}
+
+	tunedList := &tunedv1.TunedList{}
+	if err := r.List(ctx, tunedList,
+		client.InNamespace(components.NamespaceNodeTuningOperator),
+		client.MatchingLabels{performancev2.TuningBundleLabel: bundle.Name}); err != nil {
+		return nil, err
+	}
+	for i := range tunedList.Items {
+		tuned := &tunedList.Items[i]
+		if names[tuned.Name] || !metav1.IsControlledBy(tuned, bundle) {
+			continue
+		}
+		if r.ReadOnly {
+			klog.Infof("Read-only mode, not deleting the tuned %q removed from the tuning bundle %q", tuned.Name, bundle.Name)
+			pending = append(pending, "Tuned "+tuned.Name)
+			continue
+		}
+		klog.Infof("Delete the tuned %q removed from the tuning bundle %q", tuned.Name, bundle.Name)
+		if err := r.Delete(ctx, tuned); err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return pending, nil
+}
+
+// applyTuningBundleProfile creates or updates the performance profile of the bundle, it returns whether the
+// profile differed from the cluster state
+func (r *TuningBundleReconciler) applyTuningBundleProfile(ctx context.Context, bundle *performancev2.TuningBundle, profile *performancev2.PerformanceProfile, generation int64) (bool, error) {
+	setTuningBundleGeneration(profile, generation)
+	if err := controllerutil.SetControllerReference(bundle, profile, r.Scheme); err != nil {
+		return false, err
+	}
+
+	existing := &performancev2.PerformanceProfile{}
+	err := r.Get(ctx, namespacedName(profile), existing)
+	if errors.IsNotFound(err) {
+		if r.ReadOnly {
+			klog.Infof("Read-only mode, not creating the performance profile %q of the tuning bundle", profile.Name)
+			return true, nil
+		}
+		klog.Infof("Create the performance profile %q of the tuning bundle", profile.Name)
+		return true, r.Create(ctx, profile)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	mutated := existing.DeepCopy()
+	if err := controllerutil.SetControllerReference(bundle, mutated, r.Scheme); err != nil {
+		return false, err
+	}
+	mutated.Labels = mergeMaps(profile.Labels, mutated.Labels)
+	mutated.Annotations = mergeMaps(profile.Annotations, mutated.Annotations)
+	mutated.Spec = profile.Spec
+	if apiequality.Semantic.DeepEqual(existing, mutated) {
+		return false, nil
+	}
+
+	if r.ReadOnly {
+		klog.Infof("Read-only mode, not updating the performance profile %q of the tuning bundle", profile.Name)
+		return true, nil
+	}
+	klog.Infof("Update the performance profile %q of the tuning bundle", profile.Name)
+	return true, r.Update(ctx, mutated)
+}
+
+// applyTuningBundleTuned creates or updates a Tuned CR of the bundle, it returns whether the Tuned CR differed
+// from the cluster state
+func (r *TuningBundleReconciler) applyTuningBundleTuned(ctx context.Context, bundle *performancev2.TuningBundle, tuned *tunedv1.Tuned, generation int64) (bool, error) {
+	setTuningBundleGeneration(tuned, generation)
+	if err := controllerutil.SetControllerReference(bundle, tuned, r.Scheme); err != nil {
+		return false, err
+	}
+
+	existing := &tunedv1.Tuned{}
+	err := r.Get(ctx, namespacedName(tuned), existing)
+	if errors.IsNotFound(err) {
+		if r.ReadOnly {
+			klog.Infof("Read-only mode, not creating the tuned %q of the tuning bundle %q", tuned.Name, bundle.Name)
+			return true, nil
+		}
+		klog.Infof("Create the tuned %q of the tuning bundle %q", tuned.Name, bundle.Name)
+		return true, r.Create(ctx, tuned)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	mutated := existing.DeepCopy()
+	if err := controllerutil.SetControllerReference(bundle, mutated, r.Scheme); err != nil {
+		return false, err
+	}
+	mutated.Labels = mergeMaps(tuned.Labels, mutated.Labels)
+	mutated.Annotations = mergeMaps(tuned.Annotations, mutated.Annotations)
+	mutated.Spec = tuned.Spec
+	if apiequality.Semantic.DeepEqual(existing, mutated) {
+		return false, nil
+	}
+
+	if r.ReadOnly {
+		klog.Infof("Read-only mode, not updating the tuned %q of the tuning bundle %q", tuned.Name, bundle.Name)
+		return true, nil
+	}
+	klog.Infof("Update the tuned %q of the tuning bundle %q", tuned.Name, bundle.Name)
+	return true, r.Update(ctx, mutated)
+}
+
+// getTuningBundleSourceConfig returns the rendered configuration the machine config pool of the bundle performance
+// profile targets before the bundle spec is applied, or an empty string when the bundle spec does not change the
+// performance profile, so the pool is not expected to render a new configuration
+func (r *TuningBundleReconciler) getTuningBundleSourceConfig(ctx context.Context, bundle *performancev2.TuningBundle) (string, error) {
+	existing := &performancev2.PerformanceProfile{}
+	err := r.Get(ctx, types.NamespacedName{Name: bundle.Name}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	if err == nil && apiequality.Semantic.DeepEqual(existing.Spec, bundle.Spec.PerformanceProfile) {
+		return "", nil
+	}
+
+	profileMCP, err := getMachineConfigPoolByProfile(ctx, r.Client, bundle.GetPerformanceProfile())
+	if err != nil {
+		return "", err
+	}
+	return profileMCP.Spec.Configuration.Name, nil
+}
+
+// getTuningBundleRolloutState returns whether the components of the bundle rolled out, or the reason and the
+// message of the rollout failure. The rollout completes once the performance profile is available, its machine
+// config pool moved from the configuration recorded before applying the bundle to a new one and all the pool nodes
+// run it, and all the profile nodes applied the tuned profile calculated for them.
+func (r *TuningBundleReconciler) getTuningBundleRolloutState(ctx context.Context, bundle *performancev2.TuningBundle) (bool, string, string, error) {
+	profile := &performancev2.PerformanceProfile{}
+	if err := r.Get(ctx, types.NamespacedName{Name: bundle.Name}, profile); err != nil {
+		return false, "", "", err
+	}
+
+	if degraded := conditionsv1.FindStatusCondition(profile.Status.Conditions, conditionsv1.ConditionDegraded); degraded != nil && degraded.Status == corev1.ConditionTrue {
+		return false, degraded.Reason, degraded.Message, nil
+	}
+	if rollout := profile.Status.Rollout; rollout != nil && rollout.DegradedNodes > 0 {
+		return false, conditionReasonMCPDegraded, fmt.Sprintf("%d nodes of the machine config pool %q failed to apply their configuration", rollout.DegradedNodes, rollout.MachineConfigPool), nil
+	}
+
+	if !conditionsv1.IsStatusConditionTrue(profile.Status.Conditions, conditionsv1.ConditionAvailable) {
+		return false, "", "", nil
+	}
+	if rollout := profile.Status.Rollout; rollout != nil && rollout.PendingNodes > 0 {
+		return false, "", "", nil
+	}
+	if sourceConfig := bundle.Status.RolloutSourceConfig; sourceConfig != "" {
+		profileMCP, err := getMachineConfigPoolByProfile(ctx, r.Client, profile)
+		if err != nil {
+			return false, "", "", err
+		}
+		if !isMachineConfigPoolUpdatedFrom(profileMCP, sourceConfig) {
+			return false, "", "", nil
+		}
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, &client.ListOptions{LabelSelector: labels.SelectorFromSet(profile.Spec.NodeSelector)}); err != nil {
+		return false, "", "", err
+	}
+	tunedProfileList := &tunedv1.ProfileList{}
+	if err := r.List(ctx, tunedProfileList); err != nil {
+		return false, "", "", err
+	}
+	tunedProfiles := removeUnMatchedTunedProfiles(nodes.Items, tunedProfileList.Items)
+	if len(tunedProfiles) != len(nodes.Items) {
+		return false, "", "", nil
+	}
+	for i := range tunedProfiles {
+		if tunedProfiles[i].Status.TunedProfile != tunedProfiles[i].Spec.Config.TunedProfile || !isTunedProfileApplied(&tunedProfiles[i]) {
+			return false, "", "", nil
+		}
+	}
+	return true, "", "", nil
+}
+
+// rollbackTuningBundle restores the components of the last generation of the bundle rolled out successfully.
+// Without such a generation, the failed components are kept and the bundle only reports the failure.
+func (r *TuningBundleReconciler) rollbackTuningBundle(ctx context.Context, bundle *performancev2.TuningBundle, status *performancev2.TuningBundleStatus, reason string, message string) error {
+	generation, spec, err := r.getTuningBundleSnapshot(ctx, bundle)
+	if err != nil {
+		return err
+	}
+	if spec == nil {
+		klog.Errorf("Tuning bundle %q generation %d failed to roll out, no previous generation to roll back to: %s", bundle.Name, bundle.Generation, message)
+		status.Conditions = getTuningBundleDegradedConditions(conditionReasonBundleRolloutFailed, fmt.Sprintf("%s: %s", reason, message))
+		return r.updateTuningBundleStatus(ctx, bundle, status)
+	}
+
+	klog.Warningf("Tuning bundle %q generation %d failed to roll out, restoring the generation %d: %s", bundle.Name, bundle.Generation, generation, message)
+	if _, err := r.applyTuningBundle(ctx, bundle, spec, generation); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(bundle, corev1.EventTypeWarning, "RolledBack", "Restored the components of the generation %d: %s", generation, message)
+
+	status.Rollback = &performancev2.TuningBundleRollback{
+		Generation:         bundle.Generation,
+		RestoredGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+		RollbackTime:       metav1.Now(),
+	}
+	status.Conditions = getTuningBundleDegradedConditions(conditionReasonBundleRolledBack, getTuningBundleRolledBackMessage(status.Rollback))
+	return r.updateTuningBundleStatus(ctx, bundle, status)
+}
+
+// getTuningBundleSnapshot returns the last generation of the bundle rolled out successfully and its spec,
+// the spec is nil until a generation rolled out
+func (r *TuningBundleReconciler) getTuningBundleSnapshot(ctx context.Context, bundle *performancev2.TuningBundle) (int64, *performancev2.TuningBundleSpec, error) {
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{
+		Name:      components.GetComponentName(bundle.Name, tuningBundleSnapshotPrefix),
+		Namespace: components.NamespaceNodeTuningOperator,
+	}
+	if err := r.Get(ctx, key, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return 0, nil, nil
+		}
+		return 0, nil, err
+	}
+
+	generation, err := strconv.ParseInt(cm.Annotations[tuningBundleGenerationAnnotation], 10, 64)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to parse %q annotation of the config map %q: %w", tuningBundleGenerationAnnotation, key.String(), err)
+	}
+	if generation == bundle.Generation {
+		return 0, nil, nil
+	}
+
+	spec := &performancev2.TuningBundleSpec{}
+	if err := json.Unmarshal([]byte(cm.Data[tuningBundleSpecKey]), spec); err != nil {
+		return 0, nil, fmt.Errorf("failed to parse the tuning bundle spec of the config map %q: %w", key.String(), err)
+	}
+	return generation, spec, nil
+}
+
+// saveTuningBundleSnapshot saves the spec of the bundle generation which rolled out successfully
+func (r *TuningBundleReconciler) saveTuningBundleSnapshot(ctx context.Context, bundle *performancev2.TuningBundle) error {
+	data, err := json.Marshal(bundle.Spec)
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      components.GetComponentName(bundle.Name, tuningBundleSnapshotPrefix),
+			Namespace: components.NamespaceNodeTuningOperator,
+			Labels:    map[string]string{performancev2.TuningBundleLabel: bundle.Name},
+		},
+		Data: map[string]string{tuningBundleSpecKey: string(data)},
+	}
+	setTuningBundleGeneration(cm, bundle.Generation)
+	if err := controllerutil.SetControllerReference(bundle, cm, r.Scheme); err != nil {
+		return err
+	}
+
+	existing := &corev1.ConfigMap{}
+	err = r.Get(ctx, namespacedName(cm), existing)
+	if errors.IsNotFound(err) {
+		klog.Infof("Save the generation %d of the tuning bundle %q", bundle.Generation, bundle.Name)
+		return r.Create(ctx, cm)
+	}
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("Save the generation %d of the tuning bundle %q", bundle.Generation, bundle.Name)
+	existing.Labels = cm.Labels
+	existing.Annotations = cm.Annotations
+	existing.OwnerReferences = cm.OwnerReferences
+	existing.Data = cm.Data
+	return r.Update(ctx, existing)
+}
+
+func (r *TuningBundleReconciler) updateTuningBundleStatus(ctx context.Context, bundle *performancev2.TuningBundle, status *performancev2.TuningBundleStatus) error {
+	// ignore the condition timestamps to avoid infinite reconcile loops
+	if bundle.Status.ObservedGeneration == status.ObservedGeneration &&
+		bundle.Status.AppliedGeneration == status.AppliedGeneration &&
+		bundle.Status.RolloutSourceConfig == status.RolloutSourceConfig &&
+		reflect.DeepEqual(bundle.Status.Rollback, status.Rollback) &&
+		len(bundle.Status.Conditions) == len(status.Conditions) &&
+		equalConditionStatuses(bundle.Status.Conditions, status.Conditions) {
+		return nil
+	}
+
+	bundleCopy := bundle.DeepCopy()
+	bundleCopy.Status = *status
+	klog.Infof("Updating the tuning bundle %q status", bundle.Name)
+	return r.Status().Update(ctx, bundleCopy)
+}
+
+// isMachineConfigPoolUpdatedFrom returns true when the pool targets a rendered configuration other than
+// 'sourceConfig' and all the pool nodes run it
+func isMachineConfigPoolUpdatedFrom(mcp *mcov1.MachineConfigPool, sourceConfig string) bool {
+	return mcp.Spec.Configuration.Name != sourceConfig &&
+		mcp.Status.Configuration.Name == mcp.Spec.Configuration.Name &&
+		mcp.Status.UpdatedMachineCount == mcp.Status.MachineCount
+}
+
+// equalConditionStatuses returns true if the conditions 'new' have the same status, reason and message as the conditions 'old'
+func equalConditionStatuses(old []conditionsv1.Condition, new []conditionsv1.Condition) bool {
+	for _, newCondition := range new {
+		oldCondition := conditionsv1.FindStatusCondition(old, newCondition.Type)
+		if oldCondition == nil ||
+			oldCondition.Status != newCondition.Status ||
+			oldCondition.Reason != newCondition.Reason ||
+			oldCondition.Message != newCondition.Message {
+			return false
+		}
+	}
+	return true
+}
+
+func isTuningBundleRolledBack(bundle *performancev2.TuningBundle) bool {
+	return bundle.Status.Rollback != nil && bundle.Status.Rollback.Generation == bundle.Generation
+}
+
+func getTuningBundleRolledBackMessage(rollback *performancev2.TuningBundleRollback) string {
+	return fmt.Sprintf("Restored the components of the generation %d: %s", rollback.RestoredGeneration, rollback.Message)
+}
+
+func setTuningBundleGeneration(obj metav1.Object, generation int64) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[tuningBundleGenerationAnnotation] = strconv.FormatInt(generation, 10)
+	obj.SetAnnotations(annotations)
+}
+
+func getTuningBundleAvailableConditions() []conditionsv1.Condition {
+	return getTuningBundleConditions(corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionFalse, "", "")
+}
+
+func getTuningBundleProgressingConditions(message string) []conditionsv1.Condition {
+	return getTuningBundleConditions(corev1.ConditionFalse, corev1.ConditionTrue, corev1.ConditionFalse, conditionReasonBundleRollingOut, message)
+}
+
+func getTuningBundleDegradedConditions(reason string, message string) []conditionsv1.Condition {
+	return getTuningBundleConditions(corev1.ConditionFalse, corev1.ConditionFalse, corev1.ConditionTrue, reason, message)
+}
+
+// getTuningBundleConditions returns the Available, Progressing and Degraded conditions, the reason and the
+// message are set on the Progressing or the Degraded condition, whichever is true
+func getTuningBundleConditions(available, progressing, degraded corev1.ConditionStatus, reason string, message string) []conditionsv1.Condition {
+	now := metav1.Time{Time: time.Now()}
+	conditions := []conditionsv1.Condition{
+		{Type: conditionsv1.ConditionAvailable, Status: available},
+		{Type: conditionsv1.ConditionProgressing, Status: progressing},
+		{Type: conditionsv1.ConditionDegraded, Status: degraded},
+	}
+	for i := range conditions {
+		conditions[i].LastTransitionTime = now
+		conditions[i].LastHeartbeatTime = now
+		if conditions[i].Type != conditionsv1.ConditionAvailable && conditions[i].Status == corev1.ConditionTrue {
+			conditions[i].Reason = reason
+			conditions[i].Message = message
+		}
+	}
+	return conditions
+}
@@ -0,0 +1,293 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	testutils "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/utils/testing"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("TuningBundle Controller", func() {
+	var bundle *performancev2.TuningBundle
+	var request reconcile.Request
+
+	BeforeEach(func() {
+		bundle = &performancev2.TuningBundle{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "TuningBundle",
+				APIVersion: performancev2.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "bundle",
+				UID:        "bundle-uid",
+				Generation: 1,
+			},
+			Spec: performancev2.TuningBundleSpec{
+				PerformanceProfile: testutils.NewPerformanceProfile("bundle").Spec,
+				Tuneds: []performancev2.TuningBundleTuned{
+					{
+						Name: "bundle-ran",
+						Spec: tunedv1.TunedSpec{
+							Profile: []tunedv1.TunedProfile{
+								{
+									Name: pointer.String("bundle-ran"),
+									Data: pointer.String("[main]\ninclude=openshift-node-performance-bundle\n"),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}}
+	})
+
+	It("should apply the performance profile and the tuneds of the bundle", func() {
+		r := newFakeTuningBundleReconciler(bundle)
+		result, err := r.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(tuningBundleRolloutResync))
+
+		profile := &performancev2.PerformanceProfile{}
+		Expect(r.Get(context.TODO(), types.NamespacedName{Name: bundle.Name}, profile)).To(Succeed())
+		Expect(profile.Spec).To(Equal(bundle.Spec.PerformanceProfile))
+		Expect(profile.Labels).To(HaveKeyWithValue(performancev2.TuningBundleLabel, bundle.Name))
+		Expect(profile.Annotations).To(HaveKeyWithValue(tuningBundleGenerationAnnotation, "1"))
+		Expect(metav1.IsControlledBy(profile, bundle)).To(BeTrue())
+
+		tuned := &tunedv1.Tuned{}
+		key := types.NamespacedName{Name: "bundle-ran", Namespace: components.NamespaceNodeTuningOperator}
+		Expect(r.Get(context.TODO(), key, tuned)).To(Succeed())
+		Expect(tuned.Spec).To(Equal(bundle.Spec.Tuneds[0].Spec))
+		Expect(tuned.Labels).To(HaveKeyWithValue(performancev2.TuningBundleLabel, bundle.Name))
+		Expect(metav1.IsControlledBy(tuned, bundle)).To(BeTrue())
+
+		updated := &performancev2.TuningBundle{}
+		Expect(r.Get(context.TODO(), request.NamespacedName, updated)).To(Succeed())
+		Expect(updated.Status.ObservedGeneration).To(Equal(int64(1)))
+		Expect(updated.Status.RolloutSourceConfig).To(Equal("rendered-1"))
+		Expect(conditionsv1.IsStatusConditionTrue(updated.Status.Conditions, conditionsv1.ConditionProgressing)).To(BeTrue())
+	})
+
+	It("should not wait for a new pool configuration when the performance profile does not change", func() {
+		profile := bundle.GetPerformanceProfile()
+		bundle.Spec.Tuneds[0].Spec.Profile[0].Data = pointer.String("[main]\ninclude=openshift-node-performance-bundle\n[sysctl]\nkernel.panic=1\n")
+
+		r := newFakeTuningBundleReconciler(bundle, profile)
+		_, err := r.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		updated := &performancev2.TuningBundle{}
+		Expect(r.Get(context.TODO(), request.NamespacedName, updated)).To(Succeed())
+		Expect(updated.Status.ObservedGeneration).To(Equal(int64(1)))
+		Expect(updated.Status.RolloutSourceConfig).To(BeEmpty())
+	})
+
+	It("should delete the tuneds removed from the bundle", func() {
+		removed := &tunedv1.Tuned{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bundle-removed",
+				Namespace: components.NamespaceNodeTuningOperator,
+				Labels:    map[string]string{performancev2.TuningBundleLabel: bundle.Name},
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: performancev2.GroupVersion.String(),
+						Kind:       "TuningBundle",
+						Name:       bundle.Name,
+						UID:        bundle.UID,
+						Controller: pointer.Bool(true),
+					},
+				},
+			},
+		}
+
+		r := newFakeTuningBundleReconciler(bundle, removed)
+		_, err := r.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = r.Get(context.TODO(), namespacedName(removed), &tunedv1.Tuned{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should report the components of the bundle without writing them in the read-only mode", func() {
+		r := newFakeTuningBundleReconciler(bundle)
+		r.ReadOnly = true
+		_, err := r.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = r.Get(context.TODO(), types.NamespacedName{Name: bundle.Name}, &performancev2.PerformanceProfile{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		key := types.NamespacedName{Name: "bundle-ran", Namespace: components.NamespaceNodeTuningOperator}
+		err = r.Get(context.TODO(), key, &tunedv1.Tuned{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		updated := &performancev2.TuningBundle{}
+		Expect(r.Get(context.TODO(), request.NamespacedName, updated)).To(Succeed())
+		Expect(updated.Status.ObservedGeneration).To(BeZero())
+		progressing := conditionsv1.FindStatusCondition(updated.Status.Conditions, conditionsv1.ConditionProgressing)
+		Expect(progressing).ToNot(BeNil())
+		Expect(progressing.Status).To(Equal(corev1.ConditionTrue))
+		Expect(progressing.Reason).To(Equal(conditionReasonReadOnlyChangesPending))
+		Expect(progressing.Message).To(ContainSubstring("PerformanceProfile bundle, Tuned bundle-ran"))
+	})
+
+	It("should not apply any component of an invalid bundle", func() {
+		bundle.Spec.Tuneds = append(bundle.Spec.Tuneds, bundle.Spec.Tuneds[0])
+
+		r := newFakeTuningBundleReconciler(bundle)
+		_, err := r.Reconcile(context.TODO(), request)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = r.Get(context.TODO(), types.NamespacedName{Name: bundle.Name}, &performancev2.PerformanceProfile{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		key := types.NamespacedName{Name: "bundle-ran", Namespace: components.NamespaceNodeTuningOperator}
+		err = r.Get(context.TODO(), key, &tunedv1.Tuned{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		updated := &performancev2.TuningBundle{}
+		Expect(r.Get(context.TODO(), request.NamespacedName, updated)).To(Succeed())
+		degraded := conditionsv1.FindStatusCondition(updated.Status.Conditions, conditionsv1.ConditionDegraded)
+		Expect(degraded).ToNot(BeNil())
+		Expect(degraded.Status).To(Equal(corev1.ConditionTrue))
+		Expect(degraded.Reason).To(Equal(conditionReasonBundleValidationFailed))
+		Expect(degraded.Message).To(ContainSubstring("spec.tuneds[1].name"))
+	})
+
+	Context("with a previous generation rolled out", func() {
+		var snapshot *corev1.ConfigMap
+		var previous performancev2.TuningBundleSpec
+
+		BeforeEach(func() {
+			previous = *bundle.Spec.DeepCopy()
+			bundle.Spec.PerformanceProfile.AdditionalKernelArgs = append(bundle.Spec.PerformanceProfile.AdditionalKernelArgs, "nosmt")
+			bundle.Generation = 2
+			bundle.Status.ObservedGeneration = 2
+			bundle.Status.AppliedGeneration = 1
+
+			data, err := json.Marshal(previous)
+			Expect(err).ToNot(HaveOccurred())
+			snapshot = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        components.GetComponentName(bundle.Name, tuningBundleSnapshotPrefix),
+					Namespace:   components.NamespaceNodeTuningOperator,
+					Annotations: map[string]string{tuningBundleGenerationAnnotation: "1"},
+				},
+				Data: map[string]string{tuningBundleSpecKey: string(data)},
+			}
+		})
+
+		It("should restore the previous generation when the performance profile is degraded", func() {
+			profile := bundle.GetPerformanceProfile()
+			profile.Status.Conditions = getTuningBundleDegradedConditions(conditionReasonMCPDegraded, "1 nodes failed")
+
+			r := newFakeTuningBundleReconciler(bundle, snapshot, profile)
+			_, err := r.Reconcile(context.TODO(), request)
+			Expect(err).ToNot(HaveOccurred())
+
+			restored := &performancev2.PerformanceProfile{}
+			Expect(r.Get(context.TODO(), types.NamespacedName{Name: bundle.Name}, restored)).To(Succeed())
+			Expect(restored.Spec).To(Equal(previous.PerformanceProfile))
+			Expect(restored.Annotations).To(HaveKeyWithValue(tuningBundleGenerationAnnotation, "1"))
+
+			updated := &performancev2.TuningBundle{}
+			Expect(r.Get(context.TODO(), request.NamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.Rollback).ToNot(BeNil())
+			Expect(updated.Status.Rollback.Generation).To(Equal(int64(2)))
+			Expect(updated.Status.Rollback.RestoredGeneration).To(Equal(int64(1)))
+			Expect(updated.Status.Rollback.Reason).To(Equal(conditionReasonMCPDegraded))
+			degraded := conditionsv1.FindStatusCondition(updated.Status.Conditions, conditionsv1.ConditionDegraded)
+			Expect(degraded).ToNot(BeNil())
+			Expect(degraded.Reason).To(Equal(conditionReasonBundleRolledBack))
+
+			// the restored components are kept until the bundle changes
+			_, err = r.Reconcile(context.TODO(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Get(context.TODO(), types.NamespacedName{Name: bundle.Name}, restored)).To(Succeed())
+			Expect(restored.Spec).To(Equal(previous.PerformanceProfile))
+		})
+
+		It("should wait for the pool to update to a new configuration", func() {
+			bundle.Status.RolloutSourceConfig = "rendered-1"
+			profile := bundle.GetPerformanceProfile()
+			profile.Status.Conditions = getTuningBundleAvailableConditions()
+
+			r := newFakeTuningBundleReconciler(bundle, snapshot, profile)
+			result, err := r.Reconcile(context.TODO(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(tuningBundleRolloutResync))
+
+			updated := &performancev2.TuningBundle{}
+			Expect(r.Get(context.TODO(), request.NamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.AppliedGeneration).To(Equal(int64(1)))
+			Expect(conditionsv1.IsStatusConditionTrue(updated.Status.Conditions, conditionsv1.ConditionProgressing)).To(BeTrue())
+
+			mcp := &mcov1.MachineConfigPool{}
+			Expect(r.Get(context.TODO(), types.NamespacedName{Name: "test"}, mcp)).To(Succeed())
+			mcp.Spec.Configuration.Name = "rendered-2"
+			Expect(r.Update(context.TODO(), mcp)).To(Succeed())
+			_, err = r.Reconcile(context.TODO(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Get(context.TODO(), request.NamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.AppliedGeneration).To(Equal(int64(1)), "the pool nodes still run the previous configuration")
+
+			mcp.Status.Configuration.Name = "rendered-2"
+			Expect(r.Status().Update(context.TODO(), mcp)).To(Succeed())
+			_, err = r.Reconcile(context.TODO(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Get(context.TODO(), request.NamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.AppliedGeneration).To(Equal(int64(2)))
+			Expect(conditionsv1.IsStatusConditionTrue(updated.Status.Conditions, conditionsv1.ConditionAvailable)).To(BeTrue())
+		})
+
+		It("should save the generation once the rollout completes", func() {
+			profile := bundle.GetPerformanceProfile()
+			profile.Status.Conditions = getTuningBundleAvailableConditions()
+
+			r := newFakeTuningBundleReconciler(bundle, snapshot, profile)
+			_, err := r.Reconcile(context.TODO(), request)
+			Expect(err).ToNot(HaveOccurred())
+
+			updated := &performancev2.TuningBundle{}
+			Expect(r.Get(context.TODO(), request.NamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.AppliedGeneration).To(Equal(int64(2)))
+			Expect(conditionsv1.IsStatusConditionTrue(updated.Status.Conditions, conditionsv1.ConditionAvailable)).To(BeTrue())
+
+			saved := &corev1.ConfigMap{}
+			Expect(r.Get(context.TODO(), namespacedName(snapshot), saved)).To(Succeed())
+			Expect(saved.Annotations).To(HaveKeyWithValue(tuningBundleGenerationAnnotation, "2"))
+		})
+	})
+})
+
+// newFakeTuningBundleReconciler returns a new TuningBundleReconciler with a fake client
+func newFakeTuningBundleReconciler(bundle *performancev2.TuningBundle, initObjects ...runtime.Object) *TuningBundleReconciler {
+	mcp := testutils.NewProfileMCP()
+	mcp.Spec.Configuration.Name = "rendered-1"
+	mcp.Status.Configuration.Name = "rendered-1"
+	initObjects = append(initObjects, bundle, mcp)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithStatusSubresource(bundle, mcp).WithRuntimeObjects(initObjects...).Build()
+	return &TuningBundleReconciler{
+		Client:   fakeClient,
+		Scheme:   scheme.Scheme,
+		Recorder: record.NewFakeRecorder(10),
+	}
+}
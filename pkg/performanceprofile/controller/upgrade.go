@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/machineconfig"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/semantic"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// upgradeSourceConfigAnnotation records on the profile machine config the rendered configuration the pool
+	// targeted before the machine config was re-rendered by a new operator version
+	upgradeSourceConfigAnnotation = "performance.openshift.io/upgrade-source-config"
+
+	upgradeRolloutRequeueInterval = 30 * time.Second
+)
+
+func getOperatorVersion() string {
+	return os.Getenv("RELEASE_VERSION")
+}
+
+// setArtifactVersions records the operator version and the artifact schema version in the component annotations
+func setArtifactVersions(obj metav1.Object, operatorVersion string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[components.OperatorVersionAnnotation] = operatorVersion
+	annotations[components.ArtifactSchemaVersionAnnotation] = strconv.Itoa(components.ArtifactSchemaVersion)
+	obj.SetAnnotations(annotations)
+}
+
+// checkArtifactSchema fails when the component was generated with a newer artifact schema than the one
+// this operator renders, so an older operator instance never downgrades the components
+func checkArtifactSchema(obj metav1.Object) error {
+	rawVersion, ok := obj.GetAnnotations()[components.ArtifactSchemaVersionAnnotation]
+	if !ok {
+		return nil
+	}
+
+	version, err := strconv.Atoi(rawVersion)
+	if err != nil {
+		return fmt.Errorf("failed to parse %q annotation of %q: %w", components.ArtifactSchemaVersionAnnotation, obj.GetName(), err)
+	}
+	if version > components.ArtifactSchemaVersion {
+		return fmt.Errorf("%q was generated with the artifact schema version %d, newer than the supported version %d",
+			obj.GetName(), version, components.ArtifactSchemaVersion)
+	}
+	return nil
+}
+
+func isGeneratedByOtherOperator(obj metav1.Object, operatorVersion string) bool {
+	annotations := obj.GetAnnotations()
+	return annotations[components.OperatorVersionAnnotation] != operatorVersion ||
+		annotations[components.ArtifactSchemaVersionAnnotation] != strconv.Itoa(components.ArtifactSchemaVersion)
+}
+
+// isUpgradeRerender returns true when the mutated machine config or kubelet config change the spec of components
+// generated by another operator version, so applying them re-renders the pool and reboots its nodes
+// because of the operator upgrade.
+func (r *PerformanceProfileReconciler) isUpgradeRerender(ctx context.Context, mc *mcov1.MachineConfig, kc *mcov1.KubeletConfig) (bool, error) {
+	operatorVersion := getOperatorVersion()
+	if operatorVersion == "" {
+		return false, nil
+	}
+
+	rerender := false
+	if mc != nil {
+		existing, err := r.getMachineConfig(ctx, mc.Name)
+		if err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+		if err == nil {
+			if err := checkArtifactSchema(existing); err != nil {
+				return false, err
+			}
+			specEqual, err := semantic.MachineConfigSpecEqual(&existing.Spec, &mc.Spec)
+			if err != nil {
+				return false, err
+			}
+			rerender = !specEqual && isGeneratedByOtherOperator(existing, operatorVersion)
+		}
+	}
+
+	if kc != nil {
+		existing, err := r.getKubeletConfig(kc.Name)
+		if err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+		if err == nil {
+			if err := checkArtifactSchema(existing); err != nil {
+				return false, err
+			}
+			specEqual, err := semantic.KubeletConfigSpecEqual(&existing.Spec, &kc.Spec)
+			if err != nil {
+				return false, err
+			}
+			rerender = rerender || (!specEqual && isGeneratedByOtherOperator(existing, operatorVersion))
+		}
+	}
+	return rerender, nil
+}
+
+// getUpgradeRolloutBlocker returns the reason to postpone the upgrade re-render of the profile pool, or an empty
+// string when the pool can roll it out. The upgrade re-renders roll out one pool at a time, and a pool starts
+// only when fewer than maxUnavailable of its machines are unavailable.
+func (r *PerformanceProfileReconciler) getUpgradeRolloutBlocker(ctx context.Context, profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool) (string, error) {
+	maxUnavailable, err := getMaxUnavailable(profileMCP)
+	if err != nil {
+		return "", err
+	}
+	if profileMCP.Status.UnavailableMachineCount >= maxUnavailable {
+		return fmt.Sprintf("machine config pool %q has %d unavailable machines", profileMCP.Name, profileMCP.Status.UnavailableMachineCount), nil
+	}
+
+	profiles := &performancev2.PerformanceProfileList{}
+	if err := r.List(ctx, profiles); err != nil {
+		return "", err
+	}
+
+	for i := range profiles.Items {
+		other := &profiles.Items[i]
+		if other.Name == profile.Name || other.Status.Rollout == nil || other.Status.Rollout.MachineConfigPool == "" {
+			continue
+		}
+
+		mc, err := r.getMachineConfig(ctx, machineconfig.GetMachineConfigName(other))
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		sourceConfig, ok := mc.Annotations[upgradeSourceConfigAnnotation]
+		if !ok {
+			continue
+		}
+
+		mcp := &mcov1.MachineConfigPool{}
+		if err := r.Get(ctx, types.NamespacedName{Name: other.Status.Rollout.MachineConfigPool}, mcp); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+
+		if isUpgradeRolloutInProgress(mcp, sourceConfig) {
+			return fmt.Sprintf("machine config pool %q rolls out the upgrade of the performance profile %q", mcp.Name, other.Name), nil
+		}
+	}
+	return "", nil
+}
+
+// isUpgradeRolloutInProgress returns true until the pool rendered a new configuration after the upgrade
+// re-render and all its machines run it
+func isUpgradeRolloutInProgress(mcp *mcov1.MachineConfigPool, sourceConfig string) bool {
+	return mcp.Spec.Configuration.Name == sourceConfig ||
+		mcp.Status.Configuration.Name != mcp.Spec.Configuration.Name ||
+		mcp.Status.UpdatedMachineCount != mcp.Status.MachineCount
+}
+
+func getMaxUnavailable(mcp *mcov1.MachineConfigPool) (int32, error) {
+	if mcp.Spec.MaxUnavailable == nil {
+		return 1, nil
+	}
+
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(mcp.Spec.MaxUnavailable, int(mcp.Status.MachineCount), false)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maxUnavailable of the machine config pool %q: %w", mcp.Name, err)
+	}
+	// the machine config operator updates at least one machine at a time
+	if maxUnavailable < 1 {
+		maxUnavailable = 1
+	}
+	return int32(maxUnavailable), nil
+}
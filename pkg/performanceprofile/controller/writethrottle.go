@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/utils/clock"
+)
+
+// machineConfigWriteRequeueInterval is the minimal interval after which a throttled reconcile is retried
+const machineConfigWriteRequeueInterval = time.Second
+
+// machineConfigWriteThrottle batches and rate limits the writes toward the MCO-controlled objects
+// issued by all the performance profiles, and coalesces the repeated regenerations of the same
+// machine config pool that happen within the sync window into a single write.
+type machineConfigWriteThrottle struct {
+	limiter    flowcontrol.PassiveRateLimiter
+	syncWindow time.Duration
+	clock      clock.PassiveClock
+
+	lock sync.Mutex
+	// lastWrites keeps the time of the last write toward the MCO-controlled objects per machine config pool
+	lastWrites map[string]time.Time
+}
+
+// newMachineConfigWriteThrottle returns a throttle that allows qps writes per second with bursts of burst writes,
+// the rate limiting is disabled when qps is zero, the coalescing when syncWindow is zero.
+func newMachineConfigWriteThrottle(qps float32, burst int, syncWindow time.Duration, c clock.PassiveClock) *machineConfigWriteThrottle {
+	t := &machineConfigWriteThrottle{
+		syncWindow: syncWindow,
+		clock:      c,
+		lastWrites: map[string]time.Time{},
+	}
+	if qps > 0 {
+		t.limiter = flowcontrol.NewTokenBucketPassiveRateLimiterWithClock(qps, burst, c)
+	}
+	return t
+}
+
+// tryWrite returns zero when the caller can write the components of the pool right away, otherwise
+// the delay after which the pool components should be regenerated and the write retried.
+// The pool is considered written once tryWrite allows the write.
+func (t *machineConfigWriteThrottle) tryWrite(pool string) time.Duration {
+	if t == nil {
+		return 0
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Now()
+	if lastWrite, ok := t.lastWrites[pool]; ok && t.syncWindow > 0 {
+		if elapsed := now.Sub(lastWrite); elapsed < t.syncWindow {
+			return t.syncWindow - elapsed
+		}
+	}
+
+	if t.limiter != nil && !t.limiter.TryAccept() {
+		delay := time.Duration(float32(time.Second) / t.limiter.QPS())
+		if delay < machineConfigWriteRequeueInterval {
+			delay = machineConfigWriteRequeueInterval
+		}
+		return delay
+	}
+
+	t.lastWrites[pool] = now
+	return 0
+}
+
+// forget drops the pool write history, so the next regeneration of the pool is not coalesced
+func (t *machineConfigWriteThrottle) forget(pool string) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.lastWrites, pool)
+}
+
+// getWriteThrottlePool returns the key under which the profile writes are coalesced
+func getWriteThrottlePool(profile *performancev2.PerformanceProfile, profileMCP *mcov1.MachineConfigPool) string {
+	if profileMCP != nil {
+		return profileMCP.Name
+	}
+	return profile.Name
+}
@@ -0,0 +1,229 @@
+// Package kernelargs models the kernel command line arguments the performance profiles set on the nodes.
+//
+// A Set keeps the arguments in the order they are added and resolves the conflicts the same way the kernel does:
+// the last value of a key wins, and the keys that differ only by dashes and underscores are the same key.
+// The keys the kernel consumes positionally, like the hugepagesz and hugepages pairs, keep every occurrence.
+package kernelargs
+
+import (
+	"strings"
+
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
+)
+
+// repeatedKeys lists the keys for which the kernel consumes every occurrence in order
+var repeatedKeys = map[string]bool{
+	"hugepagesz": true,
+	"hugepages":  true,
+}
+
+// Arg is a single kernel command line argument, either a flag like "nosmt" or a key/value pair like "nohz=on"
+type Arg struct {
+	Key   string
+	Value string
+	// HasValue distinguishes the key with an empty value "key=" from the flag "key"
+	HasValue bool
+}
+
+// Flag returns the argument without a value
+func Flag(key string) Arg {
+	return Arg{Key: key}
+}
+
+// KeyValue returns the key/value argument
+func KeyValue(key string, value string) Arg {
+	return Arg{Key: key, Value: value, HasValue: true}
+}
+
+// ParseArg parses a single kernel command line argument
+func ParseArg(arg string) Arg {
+	key, value, hasValue := strings.Cut(arg, "=")
+	return Arg{Key: key, Value: value, HasValue: hasValue}
+}
+
+// String returns the argument as it appears on the kernel command line
+func (a Arg) String() string {
+	if !a.HasValue {
+		return a.Key
+	}
+	return a.Key + "=" + a.Value
+}
+
+// IsRepeated returns true when the kernel consumes every occurrence of the argument key
+func (a Arg) IsRepeated() bool {
+	return repeatedKeys[normalizeKey(a.Key)]
+}
+
+// Equal returns true when both arguments have the same key and value
+func (a Arg) Equal(other Arg) bool {
+	return normalizeKey(a.Key) == normalizeKey(other.Key) && a.HasValue == other.HasValue && a.Value == other.Value
+}
+
+// normalizeKey returns the key the kernel matches the parameter against, it treats dashes and underscores the same
+func normalizeKey(key string) string {
+	return strings.ReplaceAll(key, "-", "_")
+}
+
+// Set is an ordered set of kernel command line arguments
+type Set struct {
+	args []Arg
+}
+
+// NewSet returns the set of the arguments, added in order
+func NewSet(args ...Arg) *Set {
+	s := &Set{}
+	s.Add(args...)
+	return s
+}
+
+// Parse returns the set of the arguments of the kernel command line, for example the content of /proc/cmdline
+func Parse(cmdline string) *Set {
+	s := &Set{}
+	for _, arg := range util.SplitKernelArguments(cmdline) {
+		s.Add(ParseArg(arg))
+	}
+	return s
+}
+
+// Add adds the arguments to the set, an argument overrides the value of the same key already in the set and keeps
+// its position, the repeated keys are appended
+func (s *Set) Add(args ...Arg) {
+	for _, arg := range args {
+		if arg.Key == "" {
+			continue
+		}
+
+		if arg.IsRepeated() {
+			s.args = append(s.args, arg)
+			continue
+		}
+
+		if i := s.index(arg.Key); i >= 0 {
+			s.args[i] = arg
+			continue
+		}
+		s.args = append(s.args, arg)
+	}
+}
+
+// Merge adds all the arguments of the other set, the other set wins the conflicts
+func (s *Set) Merge(other *Set) {
+	if other == nil {
+		return
+	}
+	s.Add(other.args...)
+}
+
+// Remove removes all the occurrences of the key
+func (s *Set) Remove(key string) {
+	args := s.args[:0]
+	for _, arg := range s.args {
+		if normalizeKey(arg.Key) != normalizeKey(key) {
+			args = append(args, arg)
+		}
+	}
+	s.args = args
+}
+
+// Get returns the argument of the key, the last occurrence for the repeated keys
+func (s *Set) Get(key string) (Arg, bool) {
+	for i := len(s.args) - 1; i >= 0; i-- {
+		if normalizeKey(s.args[i].Key) == normalizeKey(key) {
+			return s.args[i], true
+		}
+	}
+	return Arg{}, false
+}
+
+// Has returns true when the set carries the same argument
+func (s *Set) Has(arg Arg) bool {
+	for _, a := range s.args {
+		if a.Equal(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of the arguments
+func (s *Set) Len() int {
+	return len(s.args)
+}
+
+// Args returns a copy of the arguments in order
+func (s *Set) Args() []Arg {
+	return append([]Arg{}, s.args...)
+}
+
+// Strings returns the arguments in order, one argument per string
+func (s *Set) Strings() []string {
+	args := make([]string, 0, len(s.args))
+	for _, arg := range s.args {
+		args = append(args, arg.String())
+	}
+	return args
+}
+
+// String returns the arguments as they appear on the kernel command line
+func (s *Set) String() string {
+	return strings.Join(s.Strings(), " ")
+}
+
+func (s *Set) index(key string) int {
+	for i, arg := range s.args {
+		if normalizeKey(arg.Key) == normalizeKey(key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// repeated returns the repeated arguments in order
+func (s *Set) repeated() []Arg {
+	var args []Arg
+	for _, arg := range s.args {
+		if arg.IsRepeated() {
+			args = append(args, arg)
+		}
+	}
+	return args
+}
+
+// Diff compares the expected arguments with the actual ones, for example the arguments computed for a profile with
+// the command line of a live node. It returns the expected arguments missing from the actual set, and the actual
+// arguments that conflict with the expected ones. The order matters only for the repeated keys, the actual arguments
+// the expected set does not mention are not reported.
+func Diff(expected *Set, actual *Set) (missing []Arg, conflicting []Arg) {
+	for _, arg := range expected.args {
+		if arg.IsRepeated() {
+			continue
+		}
+		if actual.Has(arg) {
+			continue
+		}
+		missing = append(missing, arg)
+		if actualArg, ok := actual.Get(arg.Key); ok {
+			conflicting = append(conflicting, actualArg)
+		}
+	}
+
+	expectedRepeated := expected.repeated()
+	actualRepeated := actual.repeated()
+	if len(expectedRepeated) > 0 && !argsEqual(expectedRepeated, actualRepeated) {
+		missing = append(missing, expectedRepeated...)
+		conflicting = append(conflicting, actualRepeated...)
+	}
+	return missing, conflicting
+}
+
+func argsEqual(args1 []Arg, args2 []Arg) bool {
+	if len(args1) != len(args2) {
+		return false
+	}
+	for i := range args1 {
+		if !args1[i].Equal(args2[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,13 @@
+package kernelargs
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestKernelArgs(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Kernel Arguments Suite")
+}
@@ -0,0 +1,184 @@
+package kernelargs
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	testutils "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/utils/testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/cpuset"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("Kernel arguments set", func() {
+	It("should parse the flags and the key/value arguments", func() {
+		args := Parse(`nosmt  isolcpus=managed_irq,2-3 quiet= dyndbg="file drivers/usb/* +p"`)
+		Expect(args.Args()).To(Equal([]Arg{
+			Flag("nosmt"),
+			KeyValue("isolcpus", "managed_irq,2-3"),
+			KeyValue("quiet", ""),
+			KeyValue("dyndbg", `"file drivers/usb/* +p"`),
+		}))
+		Expect(args.String()).To(Equal(`nosmt isolcpus=managed_irq,2-3 quiet= dyndbg="file drivers/usb/* +p"`))
+	})
+
+	It("should keep the last value of the conflicting keys at the position of the first one", func() {
+		args := Parse("nohz=on rcu-nocbs=2 iommu=pt rcu_nocbs=2-3 nohz=off")
+		Expect(args.String()).To(Equal("nohz=off rcu_nocbs=2-3 iommu=pt"))
+
+		arg, ok := args.Get("rcu-nocbs")
+		Expect(ok).To(BeTrue())
+		Expect(arg).To(Equal(KeyValue("rcu_nocbs", "2-3")))
+	})
+
+	It("should keep every occurrence of the repeated keys", func() {
+		args := Parse("hugepagesz=1G hugepages=4 hugepagesz=2M hugepages=0")
+		Expect(args.Len()).To(Equal(4))
+
+		args.Remove("hugepages")
+		Expect(args.String()).To(Equal("hugepagesz=1G hugepagesz=2M"))
+	})
+
+	It("should let the merged set win the conflicts", func() {
+		args := Parse("nohz=on nosoftlockup")
+		args.Merge(Parse("nohz=off audit=0"))
+		Expect(args.String()).To(Equal("nohz=off nosoftlockup audit=0"))
+	})
+
+	It("should report the missing and the conflicting arguments", func() {
+		expected := Parse("nohz=on nosmt isolcpus=managed_irq,2-3 hugepagesz=1G hugepages=4")
+
+		missing, conflicting := Diff(expected, Parse("BOOT_IMAGE=/vmlinuz isolcpus=managed_irq,2-3 nosmt hugepagesz=1G hugepages=4 nohz=on"))
+		Expect(missing).To(BeEmpty())
+		Expect(conflicting).To(BeEmpty())
+
+		missing, conflicting = Diff(expected, Parse("nohz=off isolcpus=managed_irq,2-3 hugepagesz=1G hugepages=2"))
+		Expect(missing).To(Equal([]Arg{
+			KeyValue("nohz", "on"),
+			Flag("nosmt"),
+			KeyValue("hugepagesz", "1G"),
+			KeyValue("hugepages", "4"),
+		}))
+		Expect(conflicting).To(Equal([]Arg{
+			KeyValue("nohz", "off"),
+			KeyValue("hugepagesz", "1G"),
+			KeyValue("hugepages", "2"),
+		}))
+	})
+})
+
+var _ = Describe("Profile kernel arguments", func() {
+	var profile *performancev2.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+	})
+
+	It("should compute the kernel arguments of the profile", func() {
+		args, err := New(profile, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(args.Strings()).To(Equal([]string{
+			"nohz=on",
+			"rcu_nocbs=4-5",
+			"tuned.non_isolcpus=000003cf",
+			"systemd.cpu_affinity=0,1,2,3,6,7,8,9",
+			"intel_iommu=on",
+			"iommu=pt",
+			"isolcpus=managed_irq,4-5",
+			"nohz_full=4-5",
+			"tsc=reliable",
+			"nosoftlockup",
+			"nmi_watchdog=0",
+			"mce=off",
+			"skew_tick=1",
+			"rcutree.kthread_prio=11",
+			"default_hugepagesz=1G",
+			"hugepagesz=1G",
+			"hugepages=4",
+			"audit=0",
+			"processor.max_cstate=1",
+			"idle=poll",
+			"intel_idle.max_cstate=0",
+			"intel_pstate=disable",
+		}))
+	})
+
+	It("should compute the CPU masks against the node CPUs", func() {
+		args, err := New(profile, &Options{CPUs: cpuset.New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(args.Has(KeyValue("tuned.non_isolcpus", "00000fcf"))).To(BeTrue())
+		Expect(args.Has(KeyValue("systemd.cpu_affinity", "0,1,2,3,6,7,8,9,10,11"))).To(BeTrue())
+	})
+
+	It("should let the additional kernel arguments override the generated ones", func() {
+		profile.Spec.AdditionalKernelArgs = []string{"nmi_watchdog=1", "nosmt"}
+		profile.Spec.CPU.BalanceIsolated = pointer.Bool(false)
+		profile.Spec.WorkloadHints.RealTime = pointer.Bool(false)
+		profile.Spec.WorkloadHints.PerPodPowerManagement = pointer.Bool(true)
+
+		args, err := New(profile, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(args.Has(KeyValue("isolcpus", "domain,managed_irq,4-5"))).To(BeTrue())
+		Expect(args.Has(KeyValue("nmi_watchdog", "1"))).To(BeTrue())
+		Expect(args.Has(Flag("nosoftlockup"))).To(BeFalse())
+		Expect(args.Has(KeyValue("intel_pstate", "passive"))).To(BeTrue())
+	})
+
+	It("should set the RCU kernel threads priority of the profile", func() {
+		profile.Spec.CPU.RCUKthreadPriority = pointer.Int32(2)
+		args, err := New(profile, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(args.Has(KeyValue("rcutree.kthread_prio", "2"))).To(BeTrue())
+
+		profile.Spec.WorkloadHints.RealTime = pointer.Bool(false)
+		args, err = New(profile, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(args.Has(KeyValue("rcutree.kthread_prio", "2"))).To(BeTrue())
+		Expect(args.Has(Flag("nosoftlockup"))).To(BeFalse())
+	})
+
+	It("should not isolate the CPUs with the kernel arguments for the cgroup partition isolation method", func() {
+		isolationMethod := performancev2.CPUIsolationMethodCgroupPartition
+		profile.Spec.CPU.IsolationMethod = &isolationMethod
+
+		args, err := New(profile, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(args.Has(KeyValue("isolcpus", "managed_irq,4-5"))).To(BeFalse())
+		Expect(args.Has(KeyValue("nohz_full", "4-5"))).To(BeTrue())
+	})
+
+	It("should append the dummy 2M huge pages arguments for the pages allocated at runtime", func() {
+		profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, performancev2.HugePage{
+			Size:  "2M",
+			Count: 128,
+			Node:  pointer.Int32(0),
+		})
+
+		hugepages, err := GetHugepages(profile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(NewSet(hugepages...).String()).To(Equal("hugepagesz=1G hugepages=4 hugepagesz=2M hugepages=0"))
+	})
+
+	It("should resolve the memory percentage of the huge pages against the declared node memory size", func() {
+		nodeMemorySize := resource.MustParse("64Gi")
+		profile.Spec.HugePages.NodeMemorySize = &nodeMemorySize
+		profile.Spec.HugePages.Pages[0].Count = 0
+		profile.Spec.HugePages.Pages[0].MemoryPercent = pointer.Int32(25)
+
+		hugepages, err := GetHugepages(profile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(NewSet(hugepages...).String()).To(Equal("hugepagesz=1G hugepages=16"))
+
+		profile.Spec.HugePages.NodeMemorySize = nil
+		_, err = GetHugepages(profile)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail without the isolated CPUs", func() {
+		profile.Spec.CPU.Isolated = nil
+		_, err := New(profile, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,223 @@
+package kernelargs
+
+import (
+	"fmt"
+	"strconv"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	profilecomponent "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/profile"
+
+	"k8s.io/utils/cpuset"
+)
+
+// Options carries the node details the profile kernel arguments depend on
+type Options struct {
+	// CPUs is the set of the node CPUs, when empty it defaults to the union of the profile CPU sets
+	CPUs cpuset.CPUSet
+}
+
+// New returns the kernel arguments the profile sets on the nodes through the TuneD bootloader plugin, in the order
+// the generated TuneD profile lists them. The arguments added by the parent TuneD profiles or by other MachineConfigs
+// are not part of the set.
+func New(profile *performancev2.PerformanceProfile, opts *Options) (*Set, error) {
+	if profile.Spec.CPU == nil || profile.Spec.CPU.Isolated == nil {
+		return nil, fmt.Errorf("the profile %q does not specify the isolated CPUs", profile.Name)
+	}
+
+	isolated := string(*profile.Spec.CPU.Isolated)
+	isolatedSet, err := cpuset.Parse(isolated)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeCPUs, err := getNodeCPUs(profile, opts)
+	if err != nil {
+		return nil, err
+	}
+	notIsolatedSet := nodeCPUs.Difference(isolatedSet)
+	notIsolatedMask, err := components.CPUListToMaskList(notIsolatedSet.String())
+	if err != nil {
+		return nil, err
+	}
+
+	rcuNocbs := isolated
+	if profile.Spec.CPU.RCUNocbs != nil {
+		rcuNocbsSet, err := cpuset.Parse(string(*profile.Spec.CPU.RCUNocbs))
+		if err != nil {
+			return nil, err
+		}
+		rcuNocbs = rcuNocbsSet.String()
+	}
+
+	args := NewSet(KeyValue("nohz", "on"))
+	if rcuNocbs != "" {
+		args.Add(KeyValue("rcu_nocbs", rcuNocbs))
+	}
+	args.Add(
+		KeyValue("tuned.non_isolcpus", notIsolatedMask),
+		KeyValue("systemd.cpu_affinity", components.ListToString(notIsolatedSet.List())),
+		KeyValue("intel_iommu", "on"),
+		KeyValue("iommu", "pt"),
+	)
+
+	switch {
+	case profilecomponent.IsCgroupPartitionIsolationEnabled(profile):
+		// the cgroup partition of the pods isolates the CPUs instead of the isolcpus kernel argument
+	case profile.Spec.CPU.BalanceIsolated != nil && !*profile.Spec.CPU.BalanceIsolated:
+		args.Add(KeyValue("isolcpus", "domain,managed_irq,"+isolated))
+	default:
+		args.Add(KeyValue("isolcpus", "managed_irq,"+isolated))
+	}
+
+	// the realtime workload hint raises the RCU kernel threads priority unless the profile sets it
+	rcuKthreadPriority := "11"
+	if profile.Spec.CPU.RCUKthreadPriority != nil {
+		rcuKthreadPriority = strconv.Itoa(int(*profile.Spec.CPU.RCUKthreadPriority))
+	}
+
+	realTime := profilecomponent.IsRealTimeHintEnabled(profile)
+	highPowerConsumption := profilecomponent.IsHighPowerConsumptionHintEnabled(profile)
+	if realTime {
+		nohzFull := isolated
+		if profile.Spec.CPU.NohzFull != nil {
+			nohzFullSet, err := cpuset.Parse(string(*profile.Spec.CPU.NohzFull))
+			if err != nil {
+				return nil, err
+			}
+			nohzFull = nohzFullSet.String()
+		}
+		if nohzFull != "" {
+			args.Add(KeyValue("nohz_full", nohzFull))
+		}
+		args.Add(
+			KeyValue("tsc", "reliable"),
+			Flag("nosoftlockup"),
+			KeyValue("nmi_watchdog", "0"),
+			KeyValue("mce", "off"),
+			KeyValue("skew_tick", "1"),
+			KeyValue("rcutree.kthread_prio", rcuKthreadPriority),
+		)
+	} else if profile.Spec.CPU.RCUKthreadPriority != nil {
+		args.Add(KeyValue("rcutree.kthread_prio", rcuKthreadPriority))
+	}
+
+	if highPowerConsumption {
+		args.Add(
+			KeyValue("processor.max_cstate", "1"),
+			KeyValue("intel_idle.max_cstate", "0"),
+		)
+		if realTime {
+			args.Add(KeyValue("idle", "poll"))
+		}
+	}
+
+	if profile.Spec.HugePages != nil && profile.Spec.HugePages.DefaultHugePagesSize != nil {
+		args.Add(KeyValue("default_hugepagesz", string(*profile.Spec.HugePages.DefaultHugePagesSize)))
+	}
+	hugepages, err := GetHugepages(profile)
+	if err != nil {
+		return nil, err
+	}
+	args.Add(hugepages...)
+	args.Add(GetAdditional(profile)...)
+
+	if profile.Spec.CPU.SMTPolicy != nil && *profile.Spec.CPU.SMTPolicy == performancev2.SMTPolicyDisableAll {
+		args.Add(Flag("nosmt"))
+	}
+
+	if profilecomponent.IsPerPodPowerManagementEnabled(profile) {
+		args.Add(KeyValue("intel_pstate", "passive"))
+	} else if profile.Spec.HardwareTuning != nil {
+		args.Add(KeyValue("intel_pstate", "active"))
+	} else if realTime {
+		args.Add(KeyValue("intel_pstate", "disable"))
+	}
+
+	return args, nil
+}
+
+// GetHugepages returns the hugepagesz and hugepages kernel arguments allocating the profile huge pages at boot
+func GetHugepages(profile *performancev2.PerformanceProfile) ([]Arg, error) {
+	if profile.Spec.HugePages == nil {
+		return nil, nil
+	}
+
+	var args []Arg
+	var defaultHugepageSize performancev2.HugePageSize
+	if profile.Spec.HugePages.DefaultHugePagesSize != nil {
+		defaultHugepageSize = *profile.Spec.HugePages.DefaultHugePagesSize
+	}
+
+	var is2MHugepagesRequested *bool
+	for i := range profile.Spec.HugePages.Pages {
+		page := &profile.Spec.HugePages.Pages[i]
+		// we can not allocate huge pages on the specific NUMA node via kernel boot arguments,
+		// and the operand allocates the runtime huge pages
+		if page.Node != nil || profilecomponent.IsRuntimeAllocated(page) {
+			// a user requested to allocate 2M huge pages on the specific NUMA node or at runtime,
+			// append dummy kernel arguments
+			if page.Size == components.HugepagesSize2M && is2MHugepagesRequested == nil {
+				requested := true
+				is2MHugepagesRequested = &requested
+			}
+			continue
+		}
+
+		// a user requested to allocated 2M huge pages without specifying the node
+		// we need to append 2M hugepages kernel arguments anyway, no need to add dummy
+		// kernel arguments
+		if page.Size == components.HugepagesSize2M {
+			requested := false
+			is2MHugepagesRequested = &requested
+		}
+
+		count, err := profilecomponent.GetBootHugepagesCount(profile, page)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args,
+			KeyValue("hugepagesz", string(page.Size)),
+			KeyValue("hugepages", strconv.Itoa(int(count))),
+		)
+	}
+
+	// append dummy 2M huge pages kernel arguments to guarantee that the kernel will create 2M related files
+	// and directories under the filesystem
+	if is2MHugepagesRequested != nil && *is2MHugepagesRequested && defaultHugepageSize == components.HugepagesSize1G {
+		args = append(args,
+			KeyValue("hugepagesz", string(components.HugepagesSize2M)),
+			KeyValue("hugepages", "0"),
+		)
+	}
+	return args, nil
+}
+
+// GetAdditional returns the additional kernel arguments the profile requests
+func GetAdditional(profile *performancev2.PerformanceProfile) []Arg {
+	var args []Arg
+	for _, arg := range profile.Spec.AdditionalKernelArgs {
+		args = append(args, Parse(arg).Args()...)
+	}
+	return args
+}
+
+// getNodeCPUs returns the node CPUs from the options, or the union of the profile CPU sets
+func getNodeCPUs(profile *performancev2.PerformanceProfile, opts *Options) (cpuset.CPUSet, error) {
+	if opts != nil && opts.CPUs.Size() > 0 {
+		return opts.CPUs, nil
+	}
+
+	cpus := cpuset.New()
+	for _, set := range []*performancev2.CPUSet{profile.Spec.CPU.Reserved, profile.Spec.CPU.Isolated, profile.Spec.CPU.Offlined, profile.Spec.CPU.Shared} {
+		if set == nil {
+			continue
+		}
+		parsed, err := cpuset.Parse(string(*set))
+		if err != nil {
+			return cpuset.CPUSet{}, err
+		}
+		cpus = cpus.Union(parsed)
+	}
+	return cpus, nil
+}
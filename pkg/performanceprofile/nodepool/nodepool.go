@@ -0,0 +1,281 @@
+// Package nodepool handles the tuning configuration HyperShift hands over to the operator for every NodePool.
+// The NodePool ConfigMaps wrap Tuned and PerformanceProfile manifests, the PerformanceProfiles may override a base
+// PerformanceProfile of the hosted cluster.  Both the render command and the operator running in the hosted control
+// plane decode, merge and render the NodePool configuration through this package, so they generate the same objects.
+package nodepool
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	ntoconfig "github.com/openshift/cluster-node-tuning-operator/pkg/config"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components/manifestset"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/klog"
+)
+
+// The labels, annotations and data keys below are the encoding HyperShift uses to hand the NodePool tuning
+// configuration over to the operator.
+const (
+	// TuningConfigMapLabel marks the ConfigMaps holding the tuning configuration of a NodePool
+	TuningConfigMapLabel = "hypershift.openshift.io/tuned-config"
+	// NodePoolLabel annotates the NodePool ConfigMaps with the namespaced name of their NodePool
+	NodePoolLabel = "hypershift.openshift.io/nodePool"
+	// NodePoolNameLabel labels the hosted cluster objects with the name of their NodePool
+	NodePoolNameLabel = "hypershift.openshift.io/nodePoolName"
+	// GeneratedMachineConfigLabel marks the ConfigMaps wrapping the generated MachineConfigs and KubeletConfigs
+	GeneratedMachineConfigLabel = "hypershift.openshift.io/nto-generated-machine-config"
+	// BaseProfileConfigMapLabel marks the ConfigMap holding the base PerformanceProfile of the hosted cluster,
+	// the PerformanceProfiles of the NodePool ConfigMaps override it
+	BaseProfileConfigMapLabel = "hypershift.openshift.io/performanceprofile-base"
+
+	// TuningConfigKey is the data key of the manifests of the tuning configuration ConfigMaps
+	TuningConfigKey = "tuning"
+	// TuningConfigKeyDeprecated is the data key of the manifests HyperShift used before
+	// TODO remove once HyperShift has switched to using new key.
+	TuningConfigKeyDeprecated = "tuned"
+	// MachineConfigKey is the data key of the manifest of the generated ConfigMaps
+	MachineConfigKey = "config"
+)
+
+var (
+	decodeScheme  = runtime.NewScheme()
+	configDecoder runtime.Decoder
+)
+
+func init() {
+	utilruntime.Must(performancev2.AddToScheme(decodeScheme))
+	utilruntime.Must(tunedv1.AddToScheme(decodeScheme))
+	configDecoder = serializer.NewCodecFactory(decodeScheme).UniversalDecoder(performancev2.GroupVersion, tunedv1.SchemeGroupVersion)
+}
+
+// Config is the tuning configuration a NodePool references
+type Config struct {
+	NodePoolName string
+	Profiles     []*performancev2.PerformanceProfile
+	Tuneds       []*tunedv1.Tuned
+}
+
+// IsConfigMap returns true when the ConfigMap wraps the tuning configuration of a NodePool
+func IsConfigMap(cm *corev1.ConfigMap) bool {
+	return cm.Labels[TuningConfigMapLabel] == "true"
+}
+
+// IsBaseProfileConfigMap returns true when the ConfigMap wraps the base PerformanceProfile of the hosted cluster
+func IsBaseProfileConfigMap(cm *corev1.ConfigMap) bool {
+	return cm.Labels[BaseProfileConfigMapLabel] == "true"
+}
+
+// tuningConfigManifests returns the raw manifests wrapped in the tuning configuration ConfigMap and the data key
+// holding them
+func tuningConfigManifests(cm *corev1.ConfigMap) (string, [][]byte, error) {
+	key := TuningConfigKey
+	data, ok := cm.Data[key]
+	if !ok {
+		key = TuningConfigKeyDeprecated
+		data, ok = cm.Data[key]
+		if !ok {
+			return "", nil, fmt.Errorf("ConfigMap %s/%s has no data in the %s or %s (deprecated) keys", cm.Namespace, cm.Name, TuningConfigKey, TuningConfigKeyDeprecated)
+		}
+		klog.Infof("deprecated key %s used in ConfigMap %s/%s", TuningConfigKeyDeprecated, cm.Namespace, cm.Name)
+	}
+
+	manifests, err := util.ParseManifests(key, bytes.NewBufferString(data))
+	if err != nil {
+		return "", nil, fmt.Errorf("error parsing ConfigMap %s/%s key %q: %w", cm.Namespace, cm.Name, key, err)
+	}
+	raws := make([][]byte, 0, len(manifests))
+	for _, m := range manifests {
+		raws = append(raws, m.Raw)
+	}
+	return key, raws, nil
+}
+
+// BaseProfileFromConfigMap decodes the base PerformanceProfile wrapped in the hosted cluster ConfigMap
+func BaseProfileFromConfigMap(cm *corev1.ConfigMap) (*performancev2.PerformanceProfile, error) {
+	key, manifests, err := tuningConfigManifests(cm)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifests) != 1 {
+		return nil, fmt.Errorf("base profile ConfigMap %s/%s key %q holds %d manifests, expected a single PerformanceProfile", cm.Namespace, cm.Name, key, len(manifests))
+	}
+
+	obji, err := runtime.Decode(configDecoder, manifests[0])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding base profile ConfigMap %s/%s key %q manifest: %w", cm.Namespace, cm.Name, key, err)
+	}
+	pp, ok := obji.(*performancev2.PerformanceProfile)
+	if !ok {
+		return nil, fmt.Errorf("base profile ConfigMap %s/%s key %q manifest is a %T, expected a PerformanceProfile", cm.Namespace, cm.Name, key, obji)
+	}
+	return pp, nil
+}
+
+// ConfigFromConfigMap decodes the Tuned and PerformanceProfile manifests wrapped in the NodePool ConfigMap.  When the
+// hosted cluster has a base PerformanceProfile 'base', the NodePool PerformanceProfiles are overrides merged onto it.
+func ConfigFromConfigMap(cm *corev1.ConfigMap, base *performancev2.PerformanceProfile) (*Config, error) {
+	nodePool, ok := cm.Annotations[NodePoolLabel]
+	if !ok {
+		return nil, fmt.Errorf("NodePool ConfigMap %s/%s has no %s annotation", cm.Namespace, cm.Name, NodePoolLabel)
+	}
+
+	key, manifests, err := tuningConfigManifests(cm)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{NodePoolName: ParseNamespacedName(nodePool)}
+	for idx, raw := range manifests {
+		obji, err := runtime.Decode(configDecoder, raw)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding NodePool ConfigMap %s/%s key %q [%d] manifest: %w", cm.Namespace, cm.Name, key, idx+1, err)
+		}
+
+		switch obj := obji.(type) {
+		case *performancev2.PerformanceProfile:
+			if base != nil {
+				obj, err = mergeBaseProfile(base, obj, raw)
+				if err != nil {
+					return nil, fmt.Errorf("error merging NodePool ConfigMap %s/%s key %q [%d] PerformanceProfile onto the base profile %s: %w", cm.Namespace, cm.Name, key, idx+1, base.Name, err)
+				}
+			}
+			cfg.Profiles = append(cfg.Profiles, obj)
+		case *tunedv1.Tuned:
+			cfg.Tuneds = append(cfg.Tuneds, obj)
+		default:
+			return nil, fmt.Errorf("NodePool ConfigMap %s/%s key %q [%d] manifest is a %T, expected a Tuned or a PerformanceProfile", cm.Namespace, cm.Name, key, idx+1, obji)
+		}
+	}
+	return cfg, nil
+}
+
+// mergeBaseProfile returns the NodePool PerformanceProfile 'override' with its spec strategically merged onto the spec
+// of the base PerformanceProfile 'base'.  The spec is taken as written in the manifest 'raw', so only the fields the
+// NodePool sets override the base profile; the lists replace the base ones.
+func mergeBaseProfile(base, override *performancev2.PerformanceProfile, raw []byte) (*performancev2.PerformanceProfile, error) {
+	var manifest struct {
+		Spec json.RawMessage `json:"spec,omitempty"`
+	}
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, err
+	}
+	patch := []byte(manifest.Spec)
+	if len(patch) == 0 {
+		patch = []byte("{}")
+	}
+
+	baseSpec, err := json.Marshal(base.Spec)
+	if err != nil {
+		return nil, err
+	}
+	mergedSpec, err := strategicpatch.StrategicMergePatch(baseSpec, patch, performancev2.PerformanceProfileSpec{})
+	if err != nil {
+		return nil, err
+	}
+
+	pp := override.DeepCopy()
+	pp.Spec = performancev2.PerformanceProfileSpec{}
+	if err := json.Unmarshal(mergedSpec, &pp.Spec); err != nil {
+		return nil, err
+	}
+	return pp, nil
+}
+
+// SetTunedNodePool makes the name of the NodePool Tuned 't' unique if a Tuned is duplicated across NodePools, for
+// example if one ConfigMap is referenced in multiple NodePools, and labels it with the NodePool name.
+func SetTunedNodePool(t *tunedv1.Tuned, nodePoolName string) {
+	t.Name = t.Name + "-" + HashNodePoolName(nodePoolName)
+	SetNodePoolNameLabel(t, nodePoolName)
+}
+
+// RenderProfile returns the components the operator generates for the PerformanceProfile 'pp' of the NodePool
+// 'nodePoolName'.  The hosted clusters have no MachineConfigPools, the NodePool selects the nodes; the NodePool
+// controller does not support the ContainerRuntimeConfigs, so the default container runtime applies.  The Tuned is
+// named and labeled as the other NodePool Tuneds.
+func RenderProfile(pp *performancev2.PerformanceProfile, nodePoolName string) (*manifestset.ManifestResultSet, error) {
+	manifests, err := manifestset.GetNewComponents(pp, &components.Options{
+		MachineConfig: components.MachineConfigOptions{DefaultRuntime: mcfgv1.ContainerRuntimeDefaultRuntimeRunc},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	SetTunedNodePool(manifests.Tuned, nodePoolName)
+	if manifests.RuntimeClass != nil {
+		SetNodePoolNameLabel(manifests.RuntimeClass, nodePoolName)
+	}
+	return manifests, nil
+}
+
+// NewMachineConfigConfigMap wraps the MachineConfig or KubeletConfig 'manifest' of the kind 'kind' generated for the
+// PerformanceProfile 'profileName' in the ConfigMap the NodePool controller merges into the NodePool ignition payload
+func NewMachineConfigConfigMap(kind, profileName, nodePoolName string, manifest interface{}) (*corev1.ConfigMap, error) {
+	b, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s-%s", strings.ToLower(kind), profileName, HashNodePoolName(nodePoolName)),
+			Namespace: ntoconfig.OperatorNamespace(),
+			Labels: map[string]string{
+				GeneratedMachineConfigLabel: "true",
+				NodePoolLabel:               nodePoolName,
+			},
+			Annotations: map[string]string{
+				NodePoolLabel: nodePoolName,
+			},
+		},
+		Data: map[string]string{
+			MachineConfigKey: string(b),
+		},
+	}, nil
+}
+
+// SetNodePoolNameLabel labels the hosted cluster object 'obj' with the NodePool name
+func SetNodePoolNameLabel(obj metav1.Object, nodePoolName string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[NodePoolNameLabel] = nodePoolName
+	obj.SetLabels(labels)
+}
+
+// HashNodePoolName returns the hash of the NodePool name appended to the names of the NodePool objects
+func HashNodePoolName(nodePoolName string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(nodePoolName))
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
+
+// ParseNamespacedName expects a string with the format "namespace/name" and returns the name only
+func ParseNamespacedName(namespacedName string) string {
+	if _, name, ok := strings.Cut(namespacedName, "/"); ok {
+		return name
+	}
+	return namespacedName
+}
@@ -0,0 +1,161 @@
+package nodepool
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+)
+
+const testBaseProfile = `apiVersion: performance.openshift.io/v2
+kind: PerformanceProfile
+metadata:
+  name: base
+spec:
+  cpu:
+    isolated: "2-7"
+    reserved: "0-1"
+  hugepages:
+    defaultHugepagesSize: 1G
+    pages:
+    - count: 4
+      size: 1G
+  additionalKernelArgs:
+  - nosmt
+  nodeSelector:
+    node-role.kubernetes.io/worker: ""
+  realTimeKernel:
+    enabled: false
+`
+
+func newTestTuningConfigMap(labels, annotations map[string]string, tuning string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "tuning",
+			Namespace:   "clusters-hosted",
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Data: map[string]string{TuningConfigKey: tuning},
+	}
+}
+
+func newTestBaseProfile(t *testing.T) *performancev2.PerformanceProfile {
+	cm := newTestTuningConfigMap(map[string]string{BaseProfileConfigMapLabel: "true"}, nil, testBaseProfile)
+	if !IsBaseProfileConfigMap(cm) {
+		t.Fatalf("expected the ConfigMap to hold the base profile")
+	}
+	base, err := BaseProfileFromConfigMap(cm)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return base
+}
+
+func TestBaseProfileFromConfigMap(t *testing.T) {
+	base := newTestBaseProfile(t)
+	if base.Name != "base" || string(*base.Spec.CPU.Isolated) != "2-7" {
+		t.Errorf("expected the base profile decoded, got %s with the spec %+v", base.Name, base.Spec)
+	}
+
+	tuned := `apiVersion: tuned.openshift.io/v1
+kind: Tuned
+metadata:
+  name: tuned
+`
+	tests := []struct {
+		name   string
+		tuning string
+	}{
+		{
+			name:   "more than one manifest",
+			tuning: testBaseProfile + "---\n" + testBaseProfile,
+		},
+		{
+			name:   "not a PerformanceProfile",
+			tuning: tuned,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cm := newTestTuningConfigMap(map[string]string{BaseProfileConfigMapLabel: "true"}, nil, tc.tuning)
+			if _, err := BaseProfileFromConfigMap(cm); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestConfigFromConfigMapOverride(t *testing.T) {
+	base := newTestBaseProfile(t)
+	override := `apiVersion: performance.openshift.io/v2
+kind: PerformanceProfile
+metadata:
+  name: nodepool-1
+spec:
+  cpu:
+    isolated: "4-15"
+    reserved: "0-3"
+  additionalKernelArgs:
+  - nohz_full=4-15
+  realTimeKernel:
+    enabled: true
+`
+	cm := newTestTuningConfigMap(
+		map[string]string{TuningConfigMapLabel: "true"},
+		map[string]string{NodePoolLabel: "clusters/nodepool-1"},
+		override)
+
+	cfg, err := ConfigFromConfigMap(cm, base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.NodePoolName != "nodepool-1" || len(cfg.Profiles) != 1 {
+		t.Fatalf("expected a single profile for the NodePool nodepool-1, got %d for %q", len(cfg.Profiles), cfg.NodePoolName)
+	}
+
+	pp := cfg.Profiles[0]
+	if pp.Name != "nodepool-1" {
+		t.Errorf("expected the NodePool profile name, got %q", pp.Name)
+	}
+	// overridden by the NodePool
+	if string(*pp.Spec.CPU.Isolated) != "4-15" || string(*pp.Spec.CPU.Reserved) != "0-3" {
+		t.Errorf("expected the NodePool CPUs, got %+v", pp.Spec.CPU)
+	}
+	if !reflect.DeepEqual(pp.Spec.AdditionalKernelArgs, []string{"nohz_full=4-15"}) {
+		t.Errorf("expected the NodePool kernel arguments to replace the base ones, got %v", pp.Spec.AdditionalKernelArgs)
+	}
+	if pp.Spec.RealTimeKernel == nil || !*pp.Spec.RealTimeKernel.Enabled {
+		t.Errorf("expected the NodePool real-time kernel setting, got %+v", pp.Spec.RealTimeKernel)
+	}
+	// inherited from the base profile
+	if !reflect.DeepEqual(pp.Spec.HugePages, base.Spec.HugePages) {
+		t.Errorf("expected the base huge pages %+v, got %+v", base.Spec.HugePages, pp.Spec.HugePages)
+	}
+	if !reflect.DeepEqual(pp.Spec.NodeSelector, base.Spec.NodeSelector) {
+		t.Errorf("expected the base node selector %v, got %v", base.Spec.NodeSelector, pp.Spec.NodeSelector)
+	}
+	// the base profile is left alone
+	if string(*base.Spec.CPU.Isolated) != "2-7" || !reflect.DeepEqual(base.Spec.AdditionalKernelArgs, []string{"nosmt"}) {
+		t.Errorf("expected the base profile unchanged, got %+v", base.Spec)
+	}
+}
+
+func TestConfigFromConfigMapWithoutBase(t *testing.T) {
+	cm := newTestTuningConfigMap(
+		map[string]string{TuningConfigMapLabel: "true"},
+		map[string]string{NodePoolLabel: "clusters/nodepool-1"},
+		testBaseProfile)
+
+	cfg, err := ConfigFromConfigMap(cm, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Profiles) != 1 || string(*cfg.Profiles[0].Spec.CPU.Isolated) != "2-7" {
+		t.Errorf("expected the NodePool profile as written, got %+v", cfg.Profiles)
+	}
+}
@@ -0,0 +1,164 @@
+// Package ociartifact packages the rendered performance profile manifests into an OCI artifact,
+// so the ZTP and ACM pipelines pull the pre-rendered tuning payloads from a registry by digest.
+package ociartifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// ArtifactType is the artifact type of the rendered manifests artifact.
+	ArtifactType = "application/vnd.openshift.performanceprofile.manifests.v1"
+	// ManifestMediaType is the media type of the OCI image manifest describing the artifact.
+	ManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	// ManifestLayerMediaType is the media type of the layers holding the rendered manifests.
+	ManifestLayerMediaType = "application/yaml"
+	// ChecksumIndexMediaType is the media type of the layer holding the checksum index.
+	ChecksumIndexMediaType = "application/vnd.openshift.performanceprofile.checksums.v1+json"
+	// emptyConfigMediaType is the media type of the empty config of the artifacts carrying no image config.
+	emptyConfigMediaType = "application/vnd.oci.empty.v1+json"
+
+	// AnnotationTitle names the file a layer holds.
+	AnnotationTitle = "org.opencontainers.image.title"
+	// AnnotationProfiles lists the comma separated names of the rendered performance profiles.
+	AnnotationProfiles = "performance.openshift.io/profiles"
+	// AnnotationOperatorVersion is the version of the operator the manifests were rendered with.
+	AnnotationOperatorVersion = "performance.openshift.io/operator-version"
+	// AnnotationChecksumIndex is the digest of the checksum index layer.
+	AnnotationChecksumIndex = "performance.openshift.io/checksum-index"
+
+	checksumIndexFile = "checksums.json"
+)
+
+// emptyConfig is the content of the empty config.
+var emptyConfig = []byte("{}")
+
+// Descriptor describes a blob of the artifact.
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest is the OCI image manifest of the artifact.
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	ArtifactType  string            `json:"artifactType"`
+	Config        Descriptor        `json:"config"`
+	Layers        []Descriptor      `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// File is a rendered manifest file, its name is relative to the render output directory.
+type File struct {
+	Name string
+	Data []byte
+}
+
+// Artifact is the OCI artifact packaging the rendered manifests.
+type Artifact struct {
+	// Manifest is the serialized OCI image manifest of the artifact.
+	Manifest []byte
+	// Digest is the digest of the manifest, it identifies the artifact.
+	Digest string
+	// Blobs are the config and the layers of the artifact by their digest.
+	Blobs map[string][]byte
+}
+
+// New returns the artifact packaging the files 'files', rendered out of the performance profiles 'profiles'
+// with the operator version 'operatorVersion'. The artifact holds a layer per file and a checksum index
+// layer mapping the file names to their digest. The same files give the same artifact digest.
+func New(files []File, profiles []string, operatorVersion string) (*Artifact, error) {
+	files = append([]File{}, files...)
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	a := &Artifact{Blobs: map[string][]byte{}}
+	manifest := Manifest{
+		SchemaVersion: 2,
+		MediaType:     ManifestMediaType,
+		ArtifactType:  ArtifactType,
+		Config:        a.addBlob(emptyConfigMediaType, emptyConfig, nil),
+		Annotations: map[string]string{
+			AnnotationOperatorVersion: operatorVersion,
+		},
+	}
+	if len(profiles) > 0 {
+		sorted := append([]string{}, profiles...)
+		sort.Strings(sorted)
+		manifest.Annotations[AnnotationProfiles] = strings.Join(sorted, ",")
+	}
+
+	checksums := map[string]string{}
+	for _, f := range files {
+		if f.Name == checksumIndexFile {
+			return nil, fmt.Errorf("the file name %q is reserved for the checksum index", checksumIndexFile)
+		}
+		layer := a.addBlob(ManifestLayerMediaType, f.Data, map[string]string{AnnotationTitle: f.Name})
+		checksums[f.Name] = layer.Digest
+		manifest.Layers = append(manifest.Layers, layer)
+	}
+
+	index, err := json.Marshal(checksums)
+	if err != nil {
+		return nil, err
+	}
+	indexLayer := a.addBlob(ChecksumIndexMediaType, index, map[string]string{AnnotationTitle: checksumIndexFile})
+	manifest.Layers = append(manifest.Layers, indexLayer)
+	manifest.Annotations[AnnotationChecksumIndex] = indexLayer.Digest
+
+	a.Manifest, err = json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	a.Digest = digestOf(a.Manifest)
+
+	return a, nil
+}
+
+// addBlob adds the blob 'data' to the artifact and returns its descriptor.
+func (a *Artifact) addBlob(mediaType string, data []byte, annotations map[string]string) Descriptor {
+	d := digestOf(data)
+	a.Blobs[d] = data
+	return Descriptor{
+		MediaType:   mediaType,
+		Digest:      d,
+		Size:        int64(len(data)),
+		Annotations: annotations,
+	}
+}
+
+// ReadDir returns the files under the directory 'dir', named by their slash separated path relative to 'dir'.
+func ReadDir(dir string) ([]File, error) {
+	var files []File
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, File{Name: filepath.ToSlash(name), Data: data})
+		return nil
+	})
+	return files, err
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,185 @@
+package ociartifact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeRegistry is a minimal OCI distribution API registry requiring a bearer token.
+type fakeRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	uploads   int
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		fmt.Fprint(w, `{"token":"secret"}`)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="fake",scope="repository:ztp/tuning:pull,push"`, r.Host))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	const prefix = "/v2/ztp/tuning"
+	switch {
+	case r.Method == http.MethodHead && strings.HasPrefix(r.URL.Path, prefix+"/blobs/"):
+		if _, ok := f.blobs[strings.TrimPrefix(r.URL.Path, prefix+"/blobs/")]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case r.Method == http.MethodPost && r.URL.Path == prefix+"/blobs/uploads/":
+		f.uploads++
+		w.Header().Set("Location", fmt.Sprintf("%s/blobs/uploads/%d?state=x", prefix, f.uploads))
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, prefix+"/blobs/uploads/"):
+		digest := r.URL.Query().Get("digest")
+		if digest != digestOf(body) || r.URL.Query().Get("state") != "x" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.blobs[digest] = body
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, prefix+"/manifests/"):
+		manifest := &Manifest{}
+		if err := json.Unmarshal(body, manifest); err != nil || r.Header.Get("Content-Type") != ManifestMediaType {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, layer := range append(manifest.Layers, manifest.Config) {
+			if _, ok := f.blobs[layer.Digest]; !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		f.manifests[strings.TrimPrefix(r.URL.Path, prefix+"/manifests/")] = body
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestNew(t *testing.T) {
+	files := []File{
+		{Name: "test_machineconfig.yaml", Data: []byte("kind: MachineConfig\n")},
+		{Name: "test_tuned.yaml", Data: []byte("kind: Tuned\n")},
+	}
+	a, err := New(files, []string{"test"}, "4.15.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reversed, err := New([]File{files[1], files[0]}, []string{"test"}, "4.15.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reversed.Digest != a.Digest {
+		t.Errorf("expected the same files to give the same digest, got %s and %s", a.Digest, reversed.Digest)
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(a.Manifest, manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.ArtifactType != ArtifactType || len(manifest.Layers) != 3 {
+		t.Fatalf("unexpected manifest %s", a.Manifest)
+	}
+	if manifest.Annotations[AnnotationProfiles] != "test" || manifest.Annotations[AnnotationOperatorVersion] != "4.15.0" {
+		t.Errorf("unexpected annotations %v", manifest.Annotations)
+	}
+
+	index := map[string]string{}
+	if err := json.Unmarshal(a.Blobs[manifest.Annotations[AnnotationChecksumIndex]], &index); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if index[f.Name] != digestOf(f.Data) {
+			t.Errorf("expected the checksum index to hold the digest of %s, got %v", f.Name, index)
+		}
+	}
+
+	if _, err := New([]File{{Name: checksumIndexFile}}, nil, ""); err == nil {
+		t.Errorf("expected the checksum index file name to be reserved")
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	for ref, expected := range map[string]string{
+		"oci://quay.io/ztp/tuning:v1":       "quay.io/ztp/tuning:v1",
+		"oci://quay.io/ztp/tuning":          "quay.io/ztp/tuning:latest",
+		"oci://localhost:5000/tuning:v1":    "localhost:5000/tuning:v1",
+		"oci://localhost:5000/a/b/tuning":   "localhost:5000/a/b/tuning:latest",
+		"quay.io/ztp/tuning:v1":             "",
+		"oci://quay.io":                     "",
+		"oci://quay.io/ztp/tuning@sha256:1": "",
+	} {
+		reference, err := ParseReference(ref)
+		if expected == "" {
+			if err == nil {
+				t.Errorf("expected the reference %q to be rejected", ref)
+			}
+			continue
+		}
+		if err != nil || reference.String() != expected {
+			t.Errorf("expected the reference %q to parse as %q, got %q, %v", ref, expected, reference, err)
+		}
+	}
+}
+
+func TestPush(t *testing.T) {
+	registry := &fakeRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	a, err := New([]File{{Name: "test_tuned.yaml", Data: []byte("kind: Tuned\n")}}, []string{"test"}, "4.15.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	opts := PushOptions{PlainHTTP: true}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("REGISTRY_AUTH_FILE", "")
+	t.Setenv("XDG_RUNTIME_DIR", "")
+
+	pushed, err := Push(context.TODO(), "oci://"+host+"/ztp/tuning:v1", a, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pushed != host+"/ztp/tuning@"+a.Digest {
+		t.Errorf("unexpected pushed reference %q", pushed)
+	}
+	if string(registry.manifests["v1"]) != string(a.Manifest) || len(registry.blobs) != len(a.Blobs) {
+		t.Errorf("expected the registry to hold the artifact, got %d blobs", len(registry.blobs))
+	}
+
+	// the blobs the registry holds are not uploaded again
+	uploads := registry.uploads
+	if _, err := Push(context.TODO(), "oci://"+host+"/ztp/tuning:v2", a, opts); err != nil {
+		t.Fatal(err)
+	}
+	if registry.uploads != uploads {
+		t.Errorf("expected no new upload, got %d", registry.uploads-uploads)
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:a/b:pull,push"`)
+	if scheme != "Bearer" || params["realm"] != "https://auth.example.com/token" ||
+		params["service"] != "registry.example.com" || params["scope"] != "repository:a/b:pull,push" {
+		t.Errorf("unexpected challenge %q %v", scheme, params)
+	}
+}
@@ -0,0 +1,354 @@
+package ociartifact
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog"
+)
+
+const (
+	// ReferencePrefix is the prefix of the artifact references, e.g. "oci://quay.io/ztp/tuning:v1".
+	ReferencePrefix = "oci://"
+
+	defaultTag = "latest"
+)
+
+// Reference is the location of the artifact in a registry.
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+}
+
+// String returns the reference without the oci:// prefix.
+func (r Reference) String() string {
+	return r.Registry + "/" + r.Repository + ":" + r.Tag
+}
+
+// ParseReference parses the reference 'ref', "oci://<registry>/<repository>[:<tag>]".
+func ParseReference(ref string) (Reference, error) {
+	if !strings.HasPrefix(ref, ReferencePrefix) {
+		return Reference{}, fmt.Errorf("the reference %q does not start with %q", ref, ReferencePrefix)
+	}
+	registry, repository, found := strings.Cut(strings.TrimPrefix(ref, ReferencePrefix), "/")
+	if !found || registry == "" || repository == "" {
+		return Reference{}, fmt.Errorf("the reference %q has no registry or repository", ref)
+	}
+
+	tag := defaultTag
+	// the registry port is cut off already, a colon after the last slash starts the tag
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, tag = repository[:i], repository[i+1:]
+	}
+	if repository == "" || tag == "" || strings.Contains(repository, "@") {
+		return Reference{}, fmt.Errorf("the reference %q is not a valid tag reference", ref)
+	}
+	return Reference{Registry: registry, Repository: repository, Tag: tag}, nil
+}
+
+// PushOptions are the options of the artifact push.
+type PushOptions struct {
+	// AuthFile is the registry credentials file in the containers auth.json format. When empty, the
+	// REGISTRY_AUTH_FILE, the ${XDG_RUNTIME_DIR}/containers/auth.json and the ~/.docker/config.json
+	// files are looked up; without any, the registry is accessed anonymously.
+	AuthFile string
+	// PlainHTTP accesses the registry over HTTP instead of HTTPS.
+	PlainHTTP bool
+	// Client is the HTTP client of the registry requests, http.DefaultClient when nil.
+	Client *http.Client
+}
+
+// Push pushes the artifact 'a' to the reference 'ref' with the OCI distribution API and returns
+// the pushed artifact reference, "<registry>/<repository>@<digest>".
+func Push(ctx context.Context, ref string, a *Artifact, opts PushOptions) (string, error) {
+	reference, err := ParseReference(ref)
+	if err != nil {
+		return "", err
+	}
+	r, err := newRegistryClient(reference, opts)
+	if err != nil {
+		return "", err
+	}
+
+	// the blobs are pushed first, the registry rejects the manifests referencing missing blobs
+	for digest, data := range a.Blobs {
+		if err := r.pushBlob(ctx, digest, data); err != nil {
+			return "", fmt.Errorf("failed to push the blob %s to %s: %w", digest, reference, err)
+		}
+	}
+	if err := r.pushManifest(ctx, a.Manifest); err != nil {
+		return "", fmt.Errorf("failed to push the manifest to %s: %w", reference, err)
+	}
+
+	pushed := reference.Registry + "/" + reference.Repository + "@" + a.Digest
+	klog.Infof("Pushed the artifact %s as %s", reference, pushed)
+	return pushed, nil
+}
+
+// registryClient talks to the repository of a registry with the OCI distribution API.
+type registryClient struct {
+	ref      Reference
+	baseURL  string
+	client   *http.Client
+	username string
+	password string
+	// authorization is the Authorization header value of the registry requests, once the registry challenged us
+	authorization string
+}
+
+func newRegistryClient(ref Reference, opts PushOptions) (*registryClient, error) {
+	scheme := "https"
+	if opts.PlainHTTP {
+		scheme = "http"
+	}
+	r := &registryClient{
+		ref:     ref,
+		baseURL: scheme + "://" + ref.Registry + "/v2/" + ref.Repository,
+		client:  opts.Client,
+	}
+	if r.client == nil {
+		r.client = http.DefaultClient
+	}
+
+	var err error
+	r.username, r.password, err = lookupCredentials(opts.AuthFile, ref.Registry)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *registryClient) pushBlob(ctx context.Context, digest string, data []byte) error {
+	resp, err := r.do(ctx, http.MethodHead, r.baseURL+"/blobs/"+digest, "", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		klog.V(4).Infof("The blob %s exists in %s", digest, r.ref)
+		return nil
+	}
+
+	resp, err = r.do(ctx, http.MethodPost, r.baseURL+"/blobs/uploads/", "", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("unexpected status %q starting the upload", resp.Status)
+	}
+	location, err := resp.Location()
+	if err != nil {
+		return fmt.Errorf("the upload has no location: %w", err)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	resp, err = r.do(ctx, http.MethodPut, location.String(), "application/octet-stream", data)
+	if err != nil {
+		return err
+	}
+	return checkStatus(resp, http.StatusCreated)
+}
+
+func (r *registryClient) pushManifest(ctx context.Context, manifest []byte) error {
+	resp, err := r.do(ctx, http.MethodPut, r.baseURL+"/manifests/"+r.ref.Tag, ManifestMediaType, manifest)
+	if err != nil {
+		return err
+	}
+	return checkStatus(resp, http.StatusCreated)
+}
+
+// do sends the request and, when the registry challenges it, authenticates and sends it again.
+func (r *registryClient) do(ctx context.Context, method, url, contentType string, body []byte) (*http.Response, error) {
+	send := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.ContentLength = int64(len(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if r.authorization != "" {
+			req.Header.Set("Authorization", r.authorization)
+		}
+		return r.client.Do(req)
+	}
+
+	resp, err := send()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	if err := r.authenticate(ctx, resp.Header.Get("WWW-Authenticate")); err != nil {
+		return nil, err
+	}
+	return send()
+}
+
+// authenticate sets the authorization of the registry requests answering the challenge 'challenge'.
+func (r *registryClient) authenticate(ctx context.Context, challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if r.username == "" {
+			return fmt.Errorf("the registry %s requires credentials", r.ref.Registry)
+		}
+		r.authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(r.username+":"+r.password))
+		return nil
+	case "bearer":
+		token, err := r.fetchToken(ctx, params)
+		if err != nil {
+			return err
+		}
+		r.authorization = "Bearer " + token
+		return nil
+	}
+	return fmt.Errorf("unsupported registry authentication challenge %q", challenge)
+}
+
+// fetchToken returns the bearer token of the token service of the challenge parameters 'params'.
+func (r *registryClient) fetchToken(ctx context.Context, params map[string]string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid token realm %q", params["realm"])
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + r.ref.Repository + ":pull,push"
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if r.username != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %q fetching the registry token", resp.Status)
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode the registry token: %w", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+	return "", fmt.Errorf("the token service returned no token")
+}
+
+// parseChallenge returns the scheme and the parameters of the WWW-Authenticate challenge 'challenge',
+// e.g. `Bearer realm="https://auth.example.com/token",service="registry.example.com"`.
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		key, value, found := strings.Cut(rest, "=")
+		if !found {
+			break
+		}
+		key = strings.TrimSpace(key)
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				params[key] = value[1:]
+				break
+			}
+			params[key] = value[1 : end+1]
+			rest = strings.TrimPrefix(strings.TrimSpace(value[end+2:]), ",")
+			continue
+		}
+		value, rest, _ = strings.Cut(value, ",")
+		params[key] = strings.TrimSpace(value)
+	}
+	return scheme, params
+}
+
+// lookupCredentials returns the username and the password of the registry 'registry' in the auth file 'authFile',
+// or in the default auth files when 'authFile' is empty.
+func lookupCredentials(authFile, registry string) (string, string, error) {
+	authFiles := []string{authFile}
+	if authFile == "" {
+		authFiles = []string{os.Getenv("REGISTRY_AUTH_FILE")}
+		if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+			authFiles = append(authFiles, filepath.Join(runtimeDir, "containers", "auth.json"))
+		}
+		if home, err := os.UserHomeDir(); err == nil {
+			authFiles = append(authFiles, filepath.Join(home, ".docker", "config.json"))
+		}
+	}
+
+	for _, f := range authFiles {
+		if f == "" {
+			continue
+		}
+		data, err := os.ReadFile(f)
+		if os.IsNotExist(err) && authFile == "" {
+			continue
+		}
+		if err != nil {
+			return "", "", err
+		}
+
+		config := struct {
+			Auths map[string]struct {
+				Auth string `json:"auth"`
+			} `json:"auths"`
+		}{}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return "", "", fmt.Errorf("failed to parse the auth file %s: %w", f, err)
+		}
+		auth, ok := config.Auths[registry]
+		if !ok {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to decode the %s credentials of the auth file %s: %w", registry, f, err)
+		}
+		username, password, _ := strings.Cut(string(decoded), ":")
+		return username, password, nil
+	}
+	return "", "", nil
+}
+
+func checkStatus(resp *http.Response, expected int) error {
+	defer resp.Body.Close()
+	if resp.StatusCode == expected {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("unexpected status %q: %s", resp.Status, strings.TrimSpace(string(body)))
+}
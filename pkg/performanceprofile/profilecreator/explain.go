@@ -0,0 +1,186 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ */
+
+package profilecreator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jaypipes/ghw/pkg/topology"
+	"k8s.io/utils/cpuset"
+)
+
+// explanationHeader introduces the explanation comments appended to the profile in explain mode
+const explanationHeader = "#\n# Explanation of the generated performance profile:\n"
+
+// ExplainArgs are the profile creator arguments the values of the profile are explained with
+type ExplainArgs struct {
+	MCPName                     string
+	ReservedCPUCount            int
+	ReserveEfficiencyCores      bool
+	OfflinedCPUCount            int
+	SplitReservedCPUsAcrossNUMA bool
+	DisableHT                   bool
+	AllowMixedCoreTypes         bool
+	TMPolicy                    string
+	// PowerConsumptionMode is the name of the power consumption mode the workload hints are set for
+	PowerConsumptionMode     string
+	RealTimeHint             bool
+	HighPowerConsumptionHint bool
+	PerPodPowerManagement    *bool
+	RTKernel                 bool
+	UserLevelNetworking      *bool
+}
+
+// CPUAllocation is the outcome of the CPUs allocation of a profile
+type CPUAllocation struct {
+	Reserved cpuset.CPUSet
+	Isolated cpuset.CPUSet
+	Offlined cpuset.CPUSet
+}
+
+// ExplainProfile returns the reasons behind every value of the profile computed out of the arguments 'args' and the
+// hardware 'sysInfo' of the nodes 'nodeNames', one line per decision
+func ExplainProfile(args ExplainArgs, nodeNames []string, sysInfo *systemInfo, alloc CPUAllocation) []string {
+	var explanations []string
+	explain := func(format string, a ...interface{}) {
+		explanations = append(explanations, fmt.Sprintf(format, a...))
+	}
+
+	numaCells := numaCellCPUs(sysInfo.TopologyInfo)
+	totalCPUs := 0
+	for _, cpus := range numaCells {
+		totalCPUs += cpus.Size()
+	}
+	explain("topology: %d NUMA cell(s) and %d CPU(s) detected on the nodes of the '%s' MCP %v, all the nodes have the same hardware",
+		len(numaCells), totalCPUs, args.MCPName, nodeNames)
+
+	switch {
+	case args.DisableHT && sysInfo.HtEnabled:
+		explain("SMT: hyperthreading is enabled on the nodes and --disable-ht is set, the %q kernel argument is added "+
+			"and only the first thread of every core is allocated", noSMTKernelArg)
+	case args.DisableHT:
+		explain("SMT: hyperthreading is already disabled on the nodes, the %q kernel argument is added since --disable-ht is set", noSMTKernelArg)
+	case sysInfo.HtEnabled:
+		explain("SMT: hyperthreading is enabled on the nodes, the reserved CPUs are allocated by whole cores " +
+			"so the thread siblings are never split between the reserved and the isolated CPUs")
+	default:
+		explain("SMT: hyperthreading is disabled on the nodes, every CPU is a whole core")
+	}
+
+	efficiency := sysInfo.EfficiencyCPUs
+	if !efficiency.IsEmpty() {
+		explain("core types: hybrid processors detected, %d CPU(s) %s are efficiency cores sharing their L2 cache, the other CPUs are performance cores",
+			efficiency.Size(), efficiency.String())
+	}
+
+	perCell := cpusPerNUMACell(numaCells, alloc.Reserved)
+	if args.ReserveEfficiencyCores {
+		explain("reserved: %d CPU(s) %s, the efficiency cores (--reserved-cpus=efficiencyCores): %s",
+			alloc.Reserved.Size(), alloc.Reserved.String(), perCell)
+	} else if args.SplitReservedCPUsAcrossNUMA {
+		explain("reserved: %d CPU(s) %s, split across the %d NUMA cell(s) (--split-reserved-cpus-across-numa): %s",
+			alloc.Reserved.Size(), alloc.Reserved.String(), len(numaCells), perCell)
+		if len(numaCells) > 0 && args.ReservedCPUCount%len(numaCells) != 0 {
+			explain("reserved: %d CPU(s) can not be split equally across %d NUMA cell(s), the count of the first %d cell(s) is rounded up",
+				args.ReservedCPUCount, len(numaCells), args.ReservedCPUCount%len(numaCells))
+		}
+	} else {
+		explain("reserved: %d CPU(s) %s, allocated sequentially starting from NUMA cell 0: %s",
+			alloc.Reserved.Size(), alloc.Reserved.String(), perCell)
+	}
+
+	if args.OfflinedCPUCount > 0 {
+		strategy := "whole sockets first, then the thread siblings of the cores, then any other CPU"
+		if args.HighPowerConsumptionHint {
+			strategy = fmt.Sprintf("the thread siblings of the cores first, whole sockets are kept online in the %s power consumption mode", args.PowerConsumptionMode)
+		}
+		explain("offlined: %d CPU(s) %s out of the CPUs not reserved, %s", alloc.Offlined.Size(), alloc.Offlined.String(), strategy)
+		if alloc.Offlined.Size() < args.OfflinedCPUCount {
+			explain("offlined: only %d of the %d requested CPU(s) could be offlined", alloc.Offlined.Size(), args.OfflinedCPUCount)
+		}
+	}
+
+	explain("isolated: %d CPU(s) %s, all the CPUs neither reserved nor offlined: %s",
+		alloc.Isolated.Size(), alloc.Isolated.String(), cpusPerNUMACell(numaCells, alloc.Isolated))
+	if args.AllowMixedCoreTypes && !efficiency.IsEmpty() {
+		explain("isolated: the CPUs may mix efficiency and performance cores (--allow-mixed-core-types)")
+	}
+
+	explain("numa.topologyPolicy: %s, from --topology-manager-policy", args.TMPolicy)
+
+	switch {
+	case args.HighPowerConsumptionHint:
+		explain("workloadHints: realTime and highPowerConsumption are enabled by the %s power consumption mode, "+
+			"the CPU C-states and P-states are disabled", args.PowerConsumptionMode)
+	case args.RealTimeHint:
+		explain("workloadHints: realTime is enabled by the %s power consumption mode", args.PowerConsumptionMode)
+	default:
+		explain("workloadHints: realTime and highPowerConsumption are disabled by the %s power consumption mode", args.PowerConsumptionMode)
+	}
+	if args.PerPodPowerManagement != nil && *args.PerPodPowerManagement {
+		explain("workloadHints: perPodPowerManagement is enabled by --per-pod-power-management")
+	}
+
+	if args.RTKernel {
+		explain("realTimeKernel: enabled by --rt-kernel")
+	} else {
+		explain("realTimeKernel: disabled by --rt-kernel")
+	}
+
+	if args.UserLevelNetworking != nil {
+		explain("net.userLevelNetworking: %v, from --user-level-networking", *args.UserLevelNetworking)
+	}
+
+	return explanations
+}
+
+// numaCellCPUs returns the CPUs of every NUMA cell of the topology
+func numaCellCPUs(topologyInfo *topology.Info) []cpuset.CPUSet {
+	var cells []cpuset.CPUSet
+	for _, node := range topologyInfo.Nodes {
+		var cpus []int
+		for _, core := range node.Cores {
+			cpus = append(cpus, core.LogicalProcessors...)
+		}
+		cells = append(cells, cpuset.New(cpus...))
+	}
+	return cells
+}
+
+// cpusPerNUMACell describes how the CPUs are spread over the NUMA cells
+func cpusPerNUMACell(cells []cpuset.CPUSet, cpus cpuset.CPUSet) string {
+	var spread []string
+	for id, cell := range cells {
+		spread = append(spread, fmt.Sprintf("NUMA cell %d: %d", id, cell.Intersection(cpus).Size()))
+	}
+	return strings.Join(spread, ", ")
+}
+
+// WriteExplanations writes the explanations as YAML comments
+func WriteExplanations(explanations []string, out io.Writer) error {
+	if _, err := io.WriteString(out, explanationHeader); err != nil {
+		return err
+	}
+	for _, explanation := range explanations {
+		if _, err := fmt.Fprintf(out, "# - %s\n", explanation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package profilecreator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/pointer"
+)
+
+const (
+	expectedExplanationsDirPath = "../../../test/e2e/performanceprofile/testdata/ppc-expected-explanations"
+	// updateExplanationsEnv rewrites the expected explanations instead of comparing with them,
+	// once the changes of the explanations are reviewed
+	updateExplanationsEnv = "PPC_UPDATE_EXPLANATIONS"
+)
+
+var _ = Describe("PerformanceProfileCreator: Explaining the Performance Profile", func() {
+	DescribeTable("should match the expected explanations",
+		func(goldenFile string, args ExplainArgs) {
+			mustGatherDirAbsolutePath, err := filepath.Abs(mustGatherDirPath)
+			Expect(err).ToNot(HaveOccurred())
+			handle, err := NewGHWHandler(mustGatherDirAbsolutePath, newTestNode("worker1"))
+			Expect(err).ToNot(HaveOccurred())
+			systemInfo, err := handle.GatherSystemInfo()
+			Expect(err).ToNot(HaveOccurred())
+
+			reserved, isolated, offlined, err := CalculateCPUSets(systemInfo, args.ReservedCPUCount, args.OfflinedCPUCount,
+				args.SplitReservedCPUsAcrossNUMA, args.DisableHT, args.HighPowerConsumptionHint)
+			Expect(err).ToNot(HaveOccurred())
+
+			explanations := ExplainProfile(args, []string{"worker1"}, systemInfo, CPUAllocation{
+				Reserved: reserved,
+				Isolated: isolated,
+				Offlined: offlined,
+			})
+			out := strings.Builder{}
+			Expect(WriteExplanations(explanations, &out)).To(Succeed())
+
+			goldenPath := filepath.Join(expectedExplanationsDirPath, goldenFile)
+			if os.Getenv(updateExplanationsEnv) != "" {
+				Expect(os.WriteFile(goldenPath, []byte(out.String()), 0644)).To(Succeed())
+			}
+			expected, err := os.ReadFile(goldenPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.String()).To(Equal(string(expected)), "the explanations changed, review them and run the test with %s=true", updateExplanationsEnv)
+		},
+		Entry("reserved CPUs allocated sequentially", "sequential.txt", ExplainArgs{
+			MCPName:              "worker-cnf",
+			ReservedCPUCount:     20,
+			TMPolicy:             "restricted",
+			PowerConsumptionMode: "default",
+		}),
+		Entry("reserved CPUs split across the NUMA cells with hyperthreading disabled", "split-reserved-ht-disabled.txt", ExplainArgs{
+			MCPName:                     "worker-cnf",
+			ReservedCPUCount:            5,
+			SplitReservedCPUsAcrossNUMA: true,
+			DisableHT:                   true,
+			TMPolicy:                    "single-numa-node",
+			PowerConsumptionMode:        "low-latency",
+			RealTimeHint:                true,
+			PerPodPowerManagement:       pointer.Bool(true),
+			RTKernel:                    true,
+			UserLevelNetworking:         pointer.Bool(true),
+		}),
+		Entry("offlined CPUs in the ultra-low-latency mode", "offlined-ultra-low-latency.txt", ExplainArgs{
+			MCPName:                  "worker-cnf",
+			ReservedCPUCount:         10,
+			OfflinedCPUCount:         6,
+			TMPolicy:                 "best-effort",
+			PowerConsumptionMode:     "ultra-low-latency",
+			RealTimeHint:             true,
+			HighPowerConsumptionHint: true,
+			RTKernel:                 true,
+			UserLevelNetworking:      pointer.Bool(false),
+		}),
+	)
+})
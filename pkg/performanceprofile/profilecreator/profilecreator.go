@@ -58,6 +58,8 @@ const (
 	noSMTKernelArg = "nosmt"
 	// allCores correspond to the value when all the processorCores need to be added to the generated CPUset
 	allCores = -1
+	// hybridCPUCapability is the /proc/cpuinfo flag of the processors mixing performance and efficiency cores
+	hybridCPUCapability = "hybrid_cpu"
 )
 
 var (
@@ -219,11 +221,16 @@ func NewGHWHandler(mustGatherDirPath string, node *v1.Node) (*GHWHandler, error)
 	if err != nil {
 		return nil, fmt.Errorf("can't obtain the path: %s for node %s: %v", nodeName, nodepath, err)
 	}
+	return NewGHWSnapshotHandler(path.Join(nodepath, nodeName, SysInfoFileName), node), nil
+}
+
+// NewGHWSnapshotHandler is a handler to use ghw options corresponding to a node, reading the node
+// hardware out of the ghw snapshot 'snapshotPath' gathered on the node
+func NewGHWSnapshotHandler(snapshotPath string, node *v1.Node) *GHWHandler {
 	options := ghw.WithSnapshot(ghw.SnapshotOptions{
-		Path: path.Join(nodepath, nodeName, SysInfoFileName),
+		Path: snapshotPath,
 	})
-	ghwHandler := &GHWHandler{snapShotOptions: options, Node: node}
-	return ghwHandler, nil
+	return &GHWHandler{snapShotOptions: options, Node: node}
 }
 
 // GHWHandler is a wrapper around ghw to get the API object
@@ -335,6 +342,8 @@ type systemInfo struct {
 	CpuInfo      *extendedCPUInfo
 	TopologyInfo *topology.Info
 	HtEnabled    bool
+	// EfficiencyCPUs are the CPUs of the efficiency cores of hybrid processors, empty on other processors
+	EfficiencyCPUs cpuset.CPUSet
 }
 
 func (ghwHandler GHWHandler) GatherSystemInfo() (*systemInfo, error) {
@@ -359,11 +368,57 @@ func (ghwHandler GHWHandler) GatherSystemInfo() (*systemInfo, error) {
 			NumLogicalProcessorsUsed: make(map[int]int, len(cpuInfo.Processors)),
 			LogicalProcessorsUsed:    make(map[int]struct{}),
 		},
-		TopologyInfo: topologyInfo,
-		HtEnabled:    htEnabled,
+		TopologyInfo:   topologyInfo,
+		HtEnabled:      htEnabled,
+		EfficiencyCPUs: getEfficiencyCPUs(cpuInfo, topologyInfo),
 	}, nil
 }
 
+// getEfficiencyCPUs returns the CPUs of the efficiency cores of hybrid processors, e.g. the Intel E-cores.
+// The efficiency cores are grouped in clusters sharing their L2 cache, while every performance core has
+// an L2 cache of its own, shared by its thread siblings only.
+func getEfficiencyCPUs(cpuInfo *cpu.Info, topologyInfo *topology.Info) cpuset.CPUSet {
+	hybrid := false
+	for _, processor := range cpuInfo.Processors {
+		hybrid = hybrid || processor.HasCapability(hybridCPUCapability)
+	}
+	if !hybrid {
+		return cpuset.New()
+	}
+
+	var efficiency []int
+	for _, node := range topologyInfo.Nodes {
+		coreOf := map[int]int{}
+		for i, core := range node.Cores {
+			for _, lp := range core.LogicalProcessors {
+				coreOf[lp] = i
+			}
+		}
+		for _, cache := range node.Caches {
+			if cache.Level != 2 {
+				continue
+			}
+			cores := map[int]struct{}{}
+			for _, lp := range cache.LogicalProcessors {
+				cores[coreOf[int(lp)]] = struct{}{}
+			}
+			if len(cores) < 2 {
+				continue
+			}
+			for _, lp := range cache.LogicalProcessors {
+				efficiency = append(efficiency, int(lp))
+			}
+		}
+	}
+	return cpuset.New(efficiency...)
+}
+
+// HasMixedCoreTypes returns true if the CPUs 'cpus' mix the efficiency and the performance cores of the system
+func HasMixedCoreTypes(systemInfo *systemInfo, cpus cpuset.CPUSet) bool {
+	efficiency := cpus.Intersection(systemInfo.EfficiencyCPUs)
+	return !efficiency.IsEmpty() && !efficiency.Equals(cpus)
+}
+
 // Calculates the resevered, isolated and offlined cpuSets.
 func CalculateCPUSets(systemInfo *systemInfo, reservedCPUCount int, offlinedCPUCount int, splitReservedCPUsAcrossNUMA bool, disableHTFlag bool, highPowerConsumptionMode bool) (cpuset.CPUSet, cpuset.CPUSet, cpuset.CPUSet, error) {
 	topologyInfo := systemInfo.TopologyInfo
@@ -401,15 +456,59 @@ func CalculateCPUSets(systemInfo *systemInfo, reservedCPUCount int, offlinedCPUC
 		return cpuset.CPUSet{}, cpuset.CPUSet{}, cpuset.CPUSet{}, err
 	}
 
-	updatedExtCPUInfo, err = updateExtendedCPUInfo(updatedExtCPUInfo, reserved, disableHTFlag, htEnabled)
+	offlined, isolated, err := calculateOfflinedAndIsolatedCPUs(updatedExtCPUInfo, updatedTopologyInfo, reserved, offlinedCPUCount, disableHTFlag, htEnabled, highPowerConsumptionMode)
+	if err != nil {
+		return cpuset.CPUSet{}, cpuset.CPUSet{}, cpuset.CPUSet{}, err
+	}
+
+	return reserved, isolated, offlined, nil
+}
+
+// CalculateCPUSetsReservingEfficiencyCores calculates the reserved, isolated and offlined cpuSets of hybrid processors,
+// reserving all the efficiency cores, so the isolated CPUs are performance cores only.
+func CalculateCPUSetsReservingEfficiencyCores(systemInfo *systemInfo, offlinedCPUCount int, disableHTFlag bool, highPowerConsumptionMode bool) (cpuset.CPUSet, cpuset.CPUSet, cpuset.CPUSet, error) {
+	reserved := systemInfo.EfficiencyCPUs
+	if reserved.IsEmpty() {
+		return cpuset.CPUSet{}, cpuset.CPUSet{}, cpuset.CPUSet{}, fmt.Errorf("no efficiency cores detected, the processors are not hybrid processors")
+	}
+
+	updatedTopologyInfo, err := updateTopologyInfo(systemInfo.TopologyInfo, disableHTFlag, systemInfo.HtEnabled)
+	if err != nil {
+		return cpuset.CPUSet{}, cpuset.CPUSet{}, cpuset.CPUSet{}, err
+	}
+
+	updatedExtCPUInfo, err := updateExtendedCPUInfo(systemInfo.CpuInfo, cpuset.CPUSet{}, disableHTFlag, systemInfo.HtEnabled)
+	if err != nil {
+		return cpuset.CPUSet{}, cpuset.CPUSet{}, cpuset.CPUSet{}, err
+	}
+
+	totalThreads := int(updatedExtCPUInfo.CpuInfo.TotalThreads)
+	if reserved.Size() >= totalThreads {
+		return cpuset.CPUSet{}, cpuset.CPUSet{}, cpuset.CPUSet{}, fmt.Errorf("all the CPUs are efficiency cores, no CPU is left to isolate")
+	}
+	if offlinedCPUCount < 0 || reserved.Size()+offlinedCPUCount >= totalThreads {
+		return cpuset.CPUSet{}, cpuset.CPUSet{}, cpuset.CPUSet{}, fmt.Errorf("please specify the offlined CPU count in the range [0,%d]", totalThreads-reserved.Size()-1)
+	}
+
+	offlined, isolated, err := calculateOfflinedAndIsolatedCPUs(updatedExtCPUInfo, updatedTopologyInfo, reserved, offlinedCPUCount, disableHTFlag, systemInfo.HtEnabled, highPowerConsumptionMode)
 	if err != nil {
 		return cpuset.CPUSet{}, cpuset.CPUSet{}, cpuset.CPUSet{}, err
 	}
+
+	return reserved, isolated, offlined, nil
+}
+
+// calculateOfflinedAndIsolatedCPUs calculates the offlined and isolated cpuSets out of the reserved CPUs 'reserved'.
+func calculateOfflinedAndIsolatedCPUs(updatedExtCPUInfo *extendedCPUInfo, updatedTopologyInfo *topology.Info, reserved cpuset.CPUSet, offlinedCPUCount int, disableHTFlag bool, htEnabled bool, highPowerConsumptionMode bool) (cpuset.CPUSet, cpuset.CPUSet, error) {
+	updatedExtCPUInfo, err := updateExtendedCPUInfo(updatedExtCPUInfo, reserved, disableHTFlag, htEnabled)
+	if err != nil {
+		return cpuset.CPUSet{}, cpuset.CPUSet{}, err
+	}
 	//Calculate offlined cpus
 	// note this takes into account the reserved cpus from the step above
 	offlined, err := getOfflinedCPUs(updatedExtCPUInfo, offlinedCPUCount, disableHTFlag, htEnabled, highPowerConsumptionMode)
 	if err != nil {
-		return cpuset.CPUSet{}, cpuset.CPUSet{}, cpuset.CPUSet{}, err
+		return cpuset.CPUSet{}, cpuset.CPUSet{}, err
 	}
 
 	// Calculate isolated cpus.
@@ -417,10 +516,10 @@ func CalculateCPUSets(systemInfo *systemInfo, reservedCPUCount int, offlinedCPUC
 	// to properly calculate isolated CPUS we need to use the updated topology information.
 	isolated, err := getIsolatedCPUs(updatedTopologyInfo.Nodes, reserved, offlined)
 	if err != nil {
-		return cpuset.CPUSet{}, cpuset.CPUSet{}, cpuset.CPUSet{}, err
+		return cpuset.CPUSet{}, cpuset.CPUSet{}, err
 	}
 
-	return reserved, isolated, offlined, nil
+	return offlined, isolated, nil
 }
 
 // Calculates Isolated cpuSet as the difference between all the cpus in the topology and those already chosen as reserved or offlined.
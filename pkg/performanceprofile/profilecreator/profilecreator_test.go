@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	"github.com/jaypipes/ghw/pkg/cpu"
+	"github.com/jaypipes/ghw/pkg/memory"
 	"github.com/jaypipes/ghw/pkg/topology"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -1357,6 +1358,82 @@ var _ = Describe("PerformanceProfileCreator: Test Helper Functions getCPUsSplitA
 	})
 })
 
+var _ = Describe("PerformanceProfileCreator: Hybrid processors with performance and efficiency cores", func() {
+	var sysInfo *systemInfo
+
+	BeforeEach(func() {
+		// 2 performance cores with 2 threads each, 4 efficiency cores sharing an L2 cache
+		cores := []*cpu.ProcessorCore{
+			{ID: 0, Index: 0, NumThreads: 2, LogicalProcessors: []int{0, 1}},
+			{ID: 4, Index: 1, NumThreads: 2, LogicalProcessors: []int{2, 3}},
+			{ID: 8, Index: 2, NumThreads: 1, LogicalProcessors: []int{4}},
+			{ID: 9, Index: 3, NumThreads: 1, LogicalProcessors: []int{5}},
+			{ID: 10, Index: 4, NumThreads: 1, LogicalProcessors: []int{6}},
+			{ID: 11, Index: 5, NumThreads: 1, LogicalProcessors: []int{7}},
+		}
+		cpuInfo := &cpu.Info{
+			TotalCores:   6,
+			TotalThreads: 8,
+			Processors: []*cpu.Processor{
+				{ID: 0, NumCores: 6, NumThreads: 8, Capabilities: []string{"fpu", hybridCPUCapability}, Cores: cores},
+			},
+		}
+		topologyInfo := &topology.Info{
+			Nodes: []*topology.Node{
+				{
+					ID:    0,
+					Cores: cores,
+					Caches: []*memory.Cache{
+						{Level: 2, Type: memory.CACHE_TYPE_UNIFIED, LogicalProcessors: []uint32{0, 1}},
+						{Level: 2, Type: memory.CACHE_TYPE_UNIFIED, LogicalProcessors: []uint32{2, 3}},
+						{Level: 2, Type: memory.CACHE_TYPE_UNIFIED, LogicalProcessors: []uint32{4, 5, 6, 7}},
+						{Level: 3, Type: memory.CACHE_TYPE_UNIFIED, LogicalProcessors: []uint32{0, 1, 2, 3, 4, 5, 6, 7}},
+					},
+				},
+			},
+		}
+		sysInfo = &systemInfo{
+			CpuInfo: &extendedCPUInfo{
+				CpuInfo:                  cpuInfo,
+				NumLogicalProcessorsUsed: map[int]int{},
+				LogicalProcessorsUsed:    map[int]struct{}{},
+			},
+			TopologyInfo:   topologyInfo,
+			HtEnabled:      true,
+			EfficiencyCPUs: getEfficiencyCPUs(cpuInfo, topologyInfo),
+		}
+	})
+
+	It("should classify the cores sharing their L2 cache as efficiency cores", func() {
+		Expect(sysInfo.EfficiencyCPUs.String()).To(Equal("4-7"))
+	})
+
+	It("should not classify efficiency cores on processors which are not hybrid", func() {
+		sysInfo.CpuInfo.CpuInfo.Processors[0].Capabilities = []string{"fpu"}
+		Expect(getEfficiencyCPUs(sysInfo.CpuInfo.CpuInfo, sysInfo.TopologyInfo).IsEmpty()).To(BeTrue())
+	})
+
+	It("should detect CPUs mixing the core types", func() {
+		Expect(HasMixedCoreTypes(sysInfo, cpuset.New(2, 3, 4))).To(BeTrue())
+		Expect(HasMixedCoreTypes(sysInfo, cpuset.New(2, 3))).To(BeFalse())
+		Expect(HasMixedCoreTypes(sysInfo, cpuset.New(4, 5))).To(BeFalse())
+	})
+
+	It("should reserve the efficiency cores and isolate the performance cores", func() {
+		reserved, isolated, offlined, err := CalculateCPUSetsReservingEfficiencyCores(sysInfo, 0, false, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reserved.String()).To(Equal("4-7"))
+		Expect(isolated.String()).To(Equal("0-3"))
+		Expect(offlined.IsEmpty()).To(BeTrue())
+	})
+
+	It("should fail to reserve the efficiency cores of processors which are not hybrid", func() {
+		sysInfo.EfficiencyCPUs = cpuset.New()
+		_, _, _, err := CalculateCPUSetsReservingEfficiencyCores(sysInfo, 0, false, false)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("PerformanceProfileCreator: Ensuring Nodes hardware equality", func() {
 	Context("Testing matching nodes with the same hardware ", func() {
 		It("should pass hardware equality test", func() {
@@ -0,0 +1,231 @@
+// Package tunedsim simulates the application of a TuneD profile on top of a
+// node sysfs/procfs snapshot. It allows to verify the effects of the generated
+// TuneD profiles without running the TuneD daemon on a real node.
+package tunedsim
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+const (
+	// PluginSysctl is the name of the TuneD sysctl plugin
+	PluginSysctl = "sysctl"
+	// PluginSysfs is the name of the TuneD sysfs plugin
+	PluginSysfs = "sysfs"
+	// PluginVM is the name of the TuneD vm plugin
+	PluginVM = "vm"
+	// PluginCPU is the name of the TuneD cpu plugin
+	PluginCPU = "cpu"
+	// PluginBootloader is the name of the TuneD bootloader plugin
+	PluginBootloader = "bootloader"
+)
+
+const (
+	sectionVariables       = "variables"
+	procSysDir             = "proc/sys"
+	transparentHugepages   = "sys/kernel/mm/transparent_hugepage/enabled"
+	cpuGovernorGlob        = "sys/devices/system/cpu/cpu[0-9]*/cpufreq/scaling_governor"
+	intelPstateMinPerfPct  = "sys/devices/system/cpu/intel_pstate/min_perf_pct"
+	cmdlineKeyPrefix       = "cmdline"
+	maxVariableExpansions  = 10
+	pluginEnabledKey       = "enabled"
+	pluginDisabledValue    = "false"
+	vmTransparentHugepages = "transparent_hugepages"
+	cpuGovernor            = "governor"
+	cpuMinPerfPct          = "min_perf_pct"
+)
+
+var (
+	variableRegex    = regexp.MustCompile(`\$\{([a-zA-Z0-9_]+)\}`)
+	selectedValRegex = regexp.MustCompile(`\[([^\]]+)\]`)
+)
+
+// Write describes a single write the TuneD daemon would perform on the node
+type Write struct {
+	// Plugin is the name of the TuneD plugin responsible for the write
+	Plugin string
+	// Path is the path of the written file relative to the node root
+	Path string
+	// Value is the value TuneD would write
+	Value string
+	// Current is the value found under the node snapshot
+	Current string
+}
+
+// Changed returns true when the write modifies the current value
+func (w Write) Changed() bool {
+	return normalize(w.Value) != normalize(w.Current)
+}
+
+// Result holds the outcome of a profile simulation
+type Result struct {
+	// Writes lists the writes in the order they appear in the profile
+	Writes []Write
+	// Skipped lists the paths referenced by the profile that do not exist under the node snapshot
+	Skipped []string
+	// Cmdline is the kernel command line computed out of the bootloader plugin options
+	Cmdline string
+}
+
+// Get returns the write for the path, the path is relative to the node root
+func (r *Result) Get(p string) (Write, bool) {
+	for _, w := range r.Writes {
+		if w.Path == p {
+			return w, true
+		}
+	}
+	return Write{}, false
+}
+
+// Simulate computes the writes TuneD would perform when applying the profile data on the node
+// represented by the snapshot. The snapshot is rooted at the node "/" directory, so it is expected
+// to contain "proc/sys/..." and "sys/..." entries. Only the profile itself is evaluated, included
+// profiles are not resolved and TuneD functions (${f:...}) are left unexpanded.
+func Simulate(profileData string, node fs.FS) (*Result, error) {
+	cfg, err := ini.Load([]byte(profileData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the profile data: %w", err)
+	}
+
+	variables := map[string]string{}
+	if section, err := cfg.GetSection(sectionVariables); err == nil {
+		for _, key := range section.Keys() {
+			variables[key.Name()] = key.Value()
+		}
+	}
+	expand := func(value string) string {
+		return expandVariables(value, variables)
+	}
+
+	result := &Result{}
+	var cmdline []string
+	for _, section := range cfg.Sections() {
+		if section.HasKey(pluginEnabledKey) && section.Key(pluginEnabledKey).String() == pluginDisabledValue {
+			continue
+		}
+
+		switch section.Name() {
+		case PluginSysctl:
+			for _, key := range section.Keys() {
+				if key.Name() == pluginEnabledKey {
+					continue
+				}
+				p := path.Join(procSysDir, strings.ReplaceAll(key.Name(), ".", "/"))
+				if err := result.addWrite(node, PluginSysctl, p, expand(key.Value())); err != nil {
+					return nil, err
+				}
+			}
+		case PluginSysfs:
+			for _, key := range section.Keys() {
+				matches, err := fs.Glob(node, strings.TrimPrefix(expand(key.Name()), "/"))
+				if err != nil {
+					return nil, err
+				}
+				if len(matches) == 0 {
+					result.Skipped = append(result.Skipped, key.Name())
+					continue
+				}
+				for _, p := range matches {
+					if err := result.addWrite(node, PluginSysfs, p, expand(key.Value())); err != nil {
+						return nil, err
+					}
+				}
+			}
+		case PluginVM:
+			if section.HasKey(vmTransparentHugepages) {
+				value := expand(section.Key(vmTransparentHugepages).Value())
+				if err := result.addWrite(node, PluginVM, transparentHugepages, value); err != nil {
+					return nil, err
+				}
+			}
+		case PluginCPU:
+			if section.HasKey(cpuGovernor) {
+				matches, err := fs.Glob(node, cpuGovernorGlob)
+				if err != nil {
+					return nil, err
+				}
+				if len(matches) == 0 {
+					result.Skipped = append(result.Skipped, "/"+cpuGovernorGlob)
+				}
+				sort.Strings(matches)
+				for _, p := range matches {
+					if err := result.addWrite(node, PluginCPU, p, expand(section.Key(cpuGovernor).Value())); err != nil {
+						return nil, err
+					}
+				}
+			}
+			if section.HasKey(cpuMinPerfPct) {
+				if err := result.addWrite(node, PluginCPU, intelPstateMinPerfPct, expand(section.Key(cpuMinPerfPct).Value())); err != nil {
+					return nil, err
+				}
+			}
+		case PluginBootloader:
+			for _, key := range section.Keys() {
+				if !strings.HasPrefix(key.Name(), cmdlineKeyPrefix) {
+					continue
+				}
+				value := strings.TrimPrefix(strings.TrimSpace(expand(key.Value())), "+")
+				if value = normalize(value); value != "" {
+					cmdline = append(cmdline, value)
+				}
+			}
+		}
+	}
+	result.Cmdline = strings.Join(cmdline, " ")
+
+	return result, nil
+}
+
+func (r *Result) addWrite(node fs.FS, plugin, p, value string) error {
+	content, err := fs.ReadFile(node, p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			r.Skipped = append(r.Skipped, "/"+p)
+			return nil
+		}
+		return err
+	}
+
+	current := strings.TrimSpace(string(content))
+	// files like transparent_hugepage/enabled list all the options and mark the selected one with brackets
+	if m := selectedValRegex.FindStringSubmatch(current); m != nil {
+		current = m[1]
+	}
+
+	r.Writes = append(r.Writes, Write{
+		Plugin:  plugin,
+		Path:    p,
+		Value:   value,
+		Current: current,
+	})
+	return nil
+}
+
+func expandVariables(value string, variables map[string]string) string {
+	for i := 0; i < maxVariableExpansions && variableRegex.MatchString(value); i++ {
+		expanded := variableRegex.ReplaceAllStringFunc(value, func(ref string) string {
+			name := variableRegex.FindStringSubmatch(ref)[1]
+			if v, ok := variables[name]; ok {
+				return v
+			}
+			return ref
+		})
+		if expanded == value {
+			break
+		}
+		value = expanded
+	}
+	return value
+}
+
+func normalize(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
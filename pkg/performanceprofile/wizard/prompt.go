@@ -0,0 +1,104 @@
+package wizard
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// prompter reads the answers line by line.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+func (p *prompter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(p.out, format, args...)
+}
+
+// ask prints the question and returns the answer, or the default answer when the answer is empty.
+func (p *prompter) ask(question, defaultAnswer string) (string, error) {
+	if defaultAnswer != "" {
+		p.printf("%s [%s]: ", question, defaultAnswer)
+	} else {
+		p.printf("%s: ", question)
+	}
+
+	line, err := p.in.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		if errors.Is(err, io.EOF) {
+			p.printf("\n")
+			return "", fmt.Errorf("no answer to %q", question)
+		}
+		return "", err
+	}
+
+	answer := strings.TrimSpace(line)
+	if answer == "" {
+		return defaultAnswer, nil
+	}
+	return answer, nil
+}
+
+// askUntilValid asks the question until 'validate' accepts the answer.
+func (p *prompter) askUntilValid(question, defaultAnswer string, validate func(answer string) error) (string, error) {
+	for {
+		answer, err := p.ask(question, defaultAnswer)
+		if err != nil {
+			return "", err
+		}
+		if err := validate(answer); err != nil {
+			p.printf("  invalid answer: %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// askInt asks for an integer between 'min' and 'max'.
+func (p *prompter) askInt(question string, defaultAnswer, min, max int) (int, error) {
+	var value int
+	_, err := p.askUntilValid(question, strconv.Itoa(defaultAnswer), func(answer string) error {
+		var err error
+		value, err = strconv.Atoi(answer)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", answer)
+		}
+		if value < min || value > max {
+			return fmt.Errorf("%d is not between %d and %d", value, min, max)
+		}
+		return nil
+	})
+	return value, err
+}
+
+// askBool asks a yes or no question.
+func (p *prompter) askBool(question string, defaultAnswer bool) (bool, error) {
+	var value bool
+	_, err := p.askUntilValid(question, strconv.FormatBool(defaultAnswer), func(answer string) error {
+		var err error
+		value, err = parseBool(answer)
+		return err
+	})
+	return value, err
+}
+
+func parseBool(answer string) (bool, error) {
+	switch strings.ToLower(answer) {
+	case "y", "yes", "true":
+		return true, nil
+	case "n", "no", "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is neither yes nor no", answer)
+}
@@ -0,0 +1,73 @@
+package wizard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+)
+
+// performanceProfileCRDName is the name of the CRD serving the PerformanceProfile schema
+const performanceProfileCRDName = "performanceprofiles.performance.openshift.io"
+
+// Schema is the OpenAPI schema of the PerformanceProfile version the wizard writes.
+type Schema struct {
+	root *apiextensionsv1.JSONSchemaProps
+}
+
+// NewSchema returns the schema of the PerformanceProfile version the wizard writes out of the CRD.
+func NewSchema(crd *apiextensionsv1.CustomResourceDefinition) (*Schema, error) {
+	for _, version := range crd.Spec.Versions {
+		if version.Name != performancev2.GroupVersion.Version {
+			continue
+		}
+		if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+			break
+		}
+		return &Schema{root: version.Schema.OpenAPIV3Schema}, nil
+	}
+	return nil, fmt.Errorf("the %s CRD has no OpenAPI schema for the version %s", crd.Name, performancev2.GroupVersion.Version)
+}
+
+// LoadSchema returns the PerformanceProfile schema served by the cluster, so the questions
+// match the API version the cluster runs.
+func LoadSchema(ctx context.Context, c client.Client) (*Schema, error) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := c.Get(ctx, client.ObjectKey{Name: performanceProfileCRDName}, crd); err != nil {
+		return nil, fmt.Errorf("failed to get the %s CRD: %w", performanceProfileCRDName, err)
+	}
+	return NewSchema(crd)
+}
+
+// Field returns the schema of the field under the dot separated path, or nil when the schema
+// does not describe it.
+func (s *Schema) Field(path string) *apiextensionsv1.JSONSchemaProps {
+	props := s.root
+	for _, name := range strings.Split(path, ".") {
+		field, ok := props.Properties[name]
+		if !ok {
+			return nil
+		}
+		props = &field
+	}
+	return props
+}
+
+// defaultValue returns the schema default of the field as an answer, or an empty string when
+// the schema sets no default.
+func defaultValue(props *apiextensionsv1.JSONSchemaProps) string {
+	if props.Default == nil {
+		return ""
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(props.Default.Raw, &value); err != nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,88 @@
+package wizard
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const (
+	tunedPodSelector   = "openshift-app=tuned"
+	tunedContainerName = "tuned"
+)
+
+// snapshotCommand packs the ghw snapshot of the host to the standard output, the tuned
+// containers mount the host root filesystem under /host
+var snapshotCommand = []string{"gather-sysinfo", "snapshot", "--root", "/host", "--output", "-"}
+
+// SnapshotSource gathers the ghw snapshots of the node hardware.
+type SnapshotSource interface {
+	// Snapshot writes the ghw snapshot tarball of the node hardware to 'w'.
+	Snapshot(ctx context.Context, node *corev1.Node, w io.Writer) error
+}
+
+// TunedPodSnapshots gathers the snapshots in the tuned pods, the operand image ships the
+// gather-sysinfo binary which must-gather uses to collect the same snapshots.
+type TunedPodSnapshots struct {
+	Config    *rest.Config
+	Client    kubernetes.Interface
+	Namespace string
+}
+
+// Snapshot implements SnapshotSource.
+func (s *TunedPodSnapshots) Snapshot(ctx context.Context, node *corev1.Node, w io.Writer) error {
+	pods, err := s.Client.CoreV1().Pods(s.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: tunedPodSelector,
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node.Name).String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list the tuned pods: %w", err)
+	}
+
+	var pod *corev1.Pod
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning {
+			pod = &pods.Items[i]
+			break
+		}
+	}
+	if pod == nil {
+		return fmt.Errorf("no tuned pod runs on the node %q", node.Name)
+	}
+
+	req := s.Client.CoreV1().RESTClient().
+		Post().
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: tunedContainerName,
+			Command:   snapshotCommand,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	exec, err := remotecommand.NewSPDYExecutor(s.Config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	if err := exec.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: w,
+		Stderr: &stderr,
+	}); err != nil {
+		return fmt.Errorf("failed to gather the hardware snapshot of the node %q: %w: %s", node.Name, err, stderr.String())
+	}
+	return nil
+}
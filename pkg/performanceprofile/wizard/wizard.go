@@ -0,0 +1,529 @@
+// Package wizard walks the users through the creation and the edition of a performance profile.
+// The questions follow the PerformanceProfile OpenAPI schema served by the cluster, the CPU sets
+// are proposed out of the hardware of the targeted nodes, and every answer goes through the
+// admission webhook validation before the next question.
+package wizard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/cpuset"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	ppvalidation "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2/validation"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/profilecreator"
+)
+
+const (
+	defaultProfileName      = "performance"
+	defaultReservedCPUCount = 2
+)
+
+// fieldQuestion asks for the value of a profile field.
+type fieldQuestion struct {
+	// path is the dot separated path of the field in the PerformanceProfile object
+	path string
+	// related lists the paths of the validation errors the field causes on top of its own path
+	related []string
+	// parse checks the answer on top of the schema
+	parse func(answer string) error
+}
+
+var (
+	workloadQuestions = []fieldQuestion{
+		{path: "spec.realTimeKernel.enabled"},
+		{path: "spec.workloadHints.realTime"},
+		{path: "spec.workloadHints.highPowerConsumption"},
+		{path: "spec.workloadHints.perPodPowerManagement"},
+	}
+	cpuQuestions = []fieldQuestion{
+		{path: "spec.cpu.reserved", parse: parseCPUSet},
+		{path: "spec.cpu.isolated", related: []string{"spec.cpu"}, parse: parseCPUSet},
+		{path: "spec.cpu.offlined", related: []string{"spec.cpu"}, parse: parseCPUSet},
+	}
+	nodeQuestions = []fieldQuestion{
+		{path: "spec.numa.topologyPolicy"},
+		{path: "spec.net.userLevelNetworking"},
+	}
+)
+
+// Wizard asks for the fields of a performance profile.
+type Wizard struct {
+	client    client.Client
+	schema    *Schema
+	snapshots SnapshotSource
+	prompter  *prompter
+}
+
+// New returns a wizard reading the answers from 'in' and writing the questions to 'out'.
+func New(c client.Client, schema *Schema, snapshots SnapshotSource, in io.Reader, out io.Writer) *Wizard {
+	return &Wizard{
+		client:    c,
+		schema:    schema,
+		snapshots: snapshots,
+		prompter:  newPrompter(in, out),
+	}
+}
+
+// Create asks for a new performance profile targeting the nodes of a machine config pool, and
+// returns it with the admission warnings.
+func (w *Wizard) Create(ctx context.Context) (*performancev2.PerformanceProfile, admission.Warnings, error) {
+	profiles := &performancev2.PerformanceProfileList{}
+	if err := w.client.List(ctx, profiles); err != nil {
+		return nil, nil, fmt.Errorf("failed to list the performance profiles: %w", err)
+	}
+
+	profile := &performancev2.PerformanceProfile{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PerformanceProfile",
+			APIVersion: performancev2.GroupVersion.String(),
+		},
+	}
+
+	name, err := w.prompter.askUntilValid("Name of the performance profile", defaultProfileName, func(answer string) error {
+		if errs := validation.IsDNS1123Subdomain(answer); len(errs) > 0 {
+			return errors.New(strings.Join(errs, ", "))
+		}
+		for _, pp := range profiles.Items {
+			if pp.Name == answer {
+				return fmt.Errorf("the performance profile %q already exists, edit it instead", answer)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	profile.Name = name
+
+	nodes, err := w.askPool(ctx, profile, profiles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snapshotDir, err := os.MkdirTemp("", "performance-profile-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(snapshotDir)
+
+	handlers, err := w.gatherHardware(ctx, nodes, snapshotDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := w.askFields(profile, workloadQuestions, nil); err != nil {
+		return nil, nil, err
+	}
+
+	proposal, err := w.proposeCPUs(profile, handlers[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := w.askFields(profile, cpuQuestions, proposal); err != nil {
+		return nil, nil, err
+	}
+
+	if err := w.askFields(profile, nodeQuestions, nil); err != nil {
+		return nil, nil, err
+	}
+
+	return w.validate(profile, profiles, nodes)
+}
+
+// Edit asks for the fields of the existing performance profile 'name', proposing the current
+// values, and returns the updated profile with the admission warnings.
+func (w *Wizard) Edit(ctx context.Context, name string) (*performancev2.PerformanceProfile, admission.Warnings, error) {
+	profile := &performancev2.PerformanceProfile{}
+	if err := w.client.Get(ctx, client.ObjectKey{Name: name}, profile); err != nil {
+		return nil, nil, fmt.Errorf("failed to get the performance profile %q: %w", name, err)
+	}
+	profile.TypeMeta = metav1.TypeMeta{
+		Kind:       "PerformanceProfile",
+		APIVersion: performancev2.GroupVersion.String(),
+	}
+
+	profiles := &performancev2.PerformanceProfileList{}
+	if err := w.client.List(ctx, profiles); err != nil {
+		return nil, nil, fmt.Errorf("failed to list the performance profiles: %w", err)
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := w.client.List(ctx, nodeList, client.MatchingLabels(profile.Spec.NodeSelector)); err != nil {
+		return nil, nil, fmt.Errorf("failed to list the nodes of the performance profile %q: %w", name, err)
+	}
+	nodes := make([]*corev1.Node, len(nodeList.Items))
+	for i := range nodeList.Items {
+		nodes[i] = &nodeList.Items[i]
+	}
+
+	if len(nodes) == 0 {
+		w.prompter.printf("No node matches the node selector of the performance profile %q\n", name)
+	} else {
+		snapshotDir, err := os.MkdirTemp("", "performance-profile-")
+		if err != nil {
+			return nil, nil, err
+		}
+		defer os.RemoveAll(snapshotDir)
+
+		if _, err := w.gatherHardware(ctx, nodes, snapshotDir); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, questions := range [][]fieldQuestion{workloadQuestions, cpuQuestions, nodeQuestions} {
+		if err := w.askFields(profile, questions, nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return w.validate(profile, profiles, nodes)
+}
+
+// askPool asks for the machine config pool of the nodes to tune, sets the profile selectors,
+// and returns the nodes of the pool.
+func (w *Wizard) askPool(ctx context.Context, profile *performancev2.PerformanceProfile, profiles *performancev2.PerformanceProfileList) ([]*corev1.Node, error) {
+	poolList := &mcfgv1.MachineConfigPoolList{}
+	if err := w.client.List(ctx, poolList); err != nil {
+		return nil, fmt.Errorf("failed to list the machine config pools: %w", err)
+	}
+	nodeList := &corev1.NodeList{}
+	if err := w.client.List(ctx, nodeList); err != nil {
+		return nil, fmt.Errorf("failed to list the nodes: %w", err)
+	}
+
+	pools := make([]*mcfgv1.MachineConfigPool, len(poolList.Items))
+	for i := range poolList.Items {
+		pools[i] = &poolList.Items[i]
+	}
+	nodes := make([]*corev1.Node, len(nodeList.Items))
+	for i := range nodeList.Items {
+		nodes[i] = &nodeList.Items[i]
+	}
+
+	poolNodes := map[string][]*corev1.Node{}
+	var poolNames []string
+	w.prompter.printf("\nMachine config pools:\n")
+	for _, pool := range pools {
+		matched, err := profilecreator.GetNodesForPool(pool, pools, nodes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the nodes of the machine config pool %q: %w", pool.Name, err)
+		}
+		poolNodes[pool.Name] = matched
+		poolNames = append(poolNames, pool.Name)
+		w.prompter.printf("  %s (%d nodes)\n", pool.Name, len(matched))
+	}
+
+	var defaultPool string
+	if len(poolNames) == 1 {
+		defaultPool = poolNames[0]
+	}
+
+	answer, err := w.prompter.askUntilValid("Machine config pool of the nodes to tune", defaultPool, func(answer string) error {
+		var pool *mcfgv1.MachineConfigPool
+		for _, p := range pools {
+			if p.Name == answer {
+				pool = p
+			}
+		}
+		if pool == nil {
+			return fmt.Errorf("unknown machine config pool, valid values are %v", poolNames)
+		}
+		if pool.Spec.NodeSelector == nil {
+			return fmt.Errorf("the machine config pool %q has no node selector", answer)
+		}
+		if len(poolNodes[answer]) == 0 {
+			return fmt.Errorf("no schedulable node belongs to the machine config pool %q", answer)
+		}
+
+		mcpSelector, err := profilecreator.GetMCPSelector(pool, pools)
+		if err != nil {
+			return err
+		}
+
+		candidate := profile.DeepCopy()
+		candidate.Spec.NodeSelector = pool.Spec.NodeSelector.MatchLabels
+		candidate.Spec.MachineConfigPoolSelector = mcpSelector
+		_, errs := ppvalidation.ValidateAgainst(candidate, profiles, nil)
+		if err := matchingErrors(errs, "spec.nodeSelector", "spec.machineConfigPoolSelector"); err != nil {
+			return err
+		}
+
+		*profile = *candidate
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return poolNodes[answer], nil
+}
+
+// gatherHardware gathers the hardware snapshots of the nodes under 'snapshotDir', makes sure the
+// nodes share the same hardware and describes it.
+func (w *Wizard) gatherHardware(ctx context.Context, nodes []*corev1.Node, snapshotDir string) ([]*profilecreator.GHWHandler, error) {
+	handlers := make([]*profilecreator.GHWHandler, 0, len(nodes))
+	for _, node := range nodes {
+		w.prompter.printf("Gathering the hardware of the node %s\n", node.Name)
+
+		snapshotPath := filepath.Join(snapshotDir, node.Name+"-"+profilecreator.SysInfoFileName)
+		f, err := os.Create(snapshotPath)
+		if err != nil {
+			return nil, err
+		}
+		err = w.snapshots.Snapshot(ctx, node, f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		handlers = append(handlers, profilecreator.NewGHWSnapshotHandler(snapshotPath, node))
+	}
+
+	if err := profilecreator.EnsureNodesHaveTheSameHardware(handlers); err != nil {
+		return nil, fmt.Errorf("the targeted nodes differ: %w", err)
+	}
+
+	systemInfo, err := handlers[0].GatherSystemInfo()
+	if err != nil {
+		return nil, err
+	}
+	w.prompter.printf("\nNodes hardware (SMT enabled: %t):\n", systemInfo.HtEnabled)
+	for _, numaNode := range systemInfo.TopologyInfo.Nodes {
+		var cpus []int
+		for _, core := range numaNode.Cores {
+			cpus = append(cpus, core.LogicalProcessors...)
+		}
+		w.prompter.printf("  NUMA node %d: %d CPUs %s\n", numaNode.ID, len(cpus), cpuset.New(cpus...).String())
+	}
+
+	return handlers, nil
+}
+
+// proposeCPUs asks how many CPUs to reserve and to offline, and returns the CPU sets the
+// performance profile creator computes for the node hardware.
+func (w *Wizard) proposeCPUs(profile *performancev2.PerformanceProfile, handler *profilecreator.GHWHandler) (map[string]string, error) {
+	highPowerConsumption := profile.Spec.WorkloadHints != nil && profile.Spec.WorkloadHints.HighPowerConsumption != nil && *profile.Spec.WorkloadHints.HighPowerConsumption
+
+	w.prompter.printf("\n")
+	for {
+		// the computation marks the CPUs it allocates, gather the system information again on every attempt
+		systemInfo, err := handler.GatherSystemInfo()
+		if err != nil {
+			return nil, err
+		}
+		totalCPUs := int(systemInfo.CpuInfo.CpuInfo.TotalThreads)
+
+		reservedCount, err := w.prompter.askInt("Number of reserved CPUs", defaultReservedCPUCount, 1, totalCPUs-1)
+		if err != nil {
+			return nil, err
+		}
+		offlinedCount, err := w.prompter.askInt("Number of offlined CPUs", 0, 0, totalCPUs-reservedCount-1)
+		if err != nil {
+			return nil, err
+		}
+
+		var splitAcrossNUMA, disableHT bool
+		if len(systemInfo.TopologyInfo.Nodes) > 1 {
+			if splitAcrossNUMA, err = w.prompter.askBool("Split the reserved CPUs across the NUMA nodes", false); err != nil {
+				return nil, err
+			}
+		}
+		if systemInfo.HtEnabled {
+			if disableHT, err = w.prompter.askBool("Disable SMT", false); err != nil {
+				return nil, err
+			}
+		}
+
+		reserved, isolated, offlined, err := profilecreator.CalculateCPUSets(systemInfo, reservedCount, offlinedCount, splitAcrossNUMA, disableHT, highPowerConsumption)
+		if err != nil {
+			w.prompter.printf("  failed to split the CPUs: %v\n", err)
+			continue
+		}
+
+		for _, arg := range profilecreator.GetAdditionalKernelArgs(disableHT) {
+			if !containsString(profile.Spec.AdditionalKernelArgs, arg) {
+				profile.Spec.AdditionalKernelArgs = append(profile.Spec.AdditionalKernelArgs, arg)
+			}
+		}
+
+		return map[string]string{
+			"spec.cpu.reserved": reserved.String(),
+			"spec.cpu.isolated": isolated.String(),
+			"spec.cpu.offlined": offlined.String(),
+		}, nil
+	}
+}
+
+func (w *Wizard) askFields(profile *performancev2.PerformanceProfile, questions []fieldQuestion, proposal map[string]string) error {
+	for _, q := range questions {
+		if err := w.askField(profile, q, proposal[q.path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// askField asks for the field value, proposing the current value, the 'proposal' or the schema
+// default in that order. An empty answer without a proposal leaves the field unset.
+func (w *Wizard) askField(profile *performancev2.PerformanceProfile, q fieldQuestion, proposal string) error {
+	props := w.schema.Field(q.path)
+	if props == nil {
+		w.prompter.printf("\nSkipping %s, the PerformanceProfile schema of the cluster does not describe it\n", q.path)
+		return nil
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(profile)
+	if err != nil {
+		return err
+	}
+	fieldPath := strings.Split(q.path, ".")
+
+	defaultAnswer := proposal
+	if value, found, _ := unstructured.NestedFieldNoCopy(obj, fieldPath...); found && value != nil {
+		defaultAnswer = fmt.Sprint(value)
+	} else if defaultAnswer == "" {
+		defaultAnswer = defaultValue(props)
+	}
+
+	w.prompter.printf("\n%s\n", props.Description)
+	if values := enumValues(props); len(values) > 0 {
+		w.prompter.printf("Valid values: %s\n", strings.Join(values, ", "))
+	}
+
+	_, err = w.prompter.askUntilValid(q.path, defaultAnswer, func(answer string) error {
+		if answer == "" {
+			return nil
+		}
+
+		value, err := parseValue(props, answer)
+		if err != nil {
+			return err
+		}
+		if q.parse != nil {
+			if err := q.parse(answer); err != nil {
+				return err
+			}
+		}
+
+		candidateObj := runtime.DeepCopyJSON(obj)
+		setField(candidateObj, value, fieldPath)
+		candidate := &performancev2.PerformanceProfile{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(candidateObj, candidate); err != nil {
+			return err
+		}
+		if err := matchingErrors(ppvalidation.ValidateBasicFields(candidate), q.path, q.related...); err != nil {
+			return err
+		}
+
+		*profile = *candidate
+		return nil
+	})
+	return err
+}
+
+// setField sets the value of the field under 'fieldPath', replacing the unset parent fields
+// the converter outputs as null values.
+func setField(obj map[string]interface{}, value interface{}, fieldPath []string) {
+	for _, name := range fieldPath[:len(fieldPath)-1] {
+		parent, ok := obj[name].(map[string]interface{})
+		if !ok {
+			parent = map[string]interface{}{}
+			obj[name] = parent
+		}
+		obj = parent
+	}
+	obj[fieldPath[len(fieldPath)-1]] = value
+}
+
+// validate runs the admission webhook validation of the profile.
+func (w *Wizard) validate(profile *performancev2.PerformanceProfile, profiles *performancev2.PerformanceProfileList, nodes []*corev1.Node) (*performancev2.PerformanceProfile, admission.Warnings, error) {
+	nodeItems := make([]corev1.Node, len(nodes))
+	for i, node := range nodes {
+		nodeItems[i] = *node
+	}
+
+	warnings, errs := ppvalidation.Validate(profile, ppvalidation.ClusterState{Profiles: profiles.Items, Nodes: nodeItems})
+	if len(errs) > 0 {
+		return nil, nil, fmt.Errorf("the performance profile %q is invalid: %w", profile.Name, errs.ToAggregate())
+	}
+	return profile, warnings, nil
+}
+
+// matchingErrors returns the errors of the field under 'path', and the errors of the 'related' fields.
+func matchingErrors(errs field.ErrorList, path string, related ...string) error {
+	var matched field.ErrorList
+	for _, err := range errs {
+		if err.Field == path || strings.HasPrefix(err.Field, path+".") || strings.HasPrefix(err.Field, path+"[") || containsString(related, err.Field) {
+			matched = append(matched, err)
+		}
+	}
+	return matched.ToAggregate()
+}
+
+// parseValue converts the answer to the JSON value of the field type.
+func parseValue(props *apiextensionsv1.JSONSchemaProps, answer string) (interface{}, error) {
+	switch props.Type {
+	case "boolean":
+		return parseBool(answer)
+	case "integer":
+		value, err := strconv.ParseInt(answer, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", answer)
+		}
+		return value, nil
+	case "string":
+		if values := enumValues(props); len(values) > 0 && !containsString(values, answer) {
+			return nil, fmt.Errorf("%q is not one of %v", answer, values)
+		}
+		return answer, nil
+	}
+	return nil, fmt.Errorf("the %q fields are not supported", props.Type)
+}
+
+func enumValues(props *apiextensionsv1.JSONSchemaProps) []string {
+	var values []string
+	for _, raw := range props.Enum {
+		var value interface{}
+		if err := json.Unmarshal(raw.Raw, &value); err != nil {
+			continue
+		}
+		values = append(values, fmt.Sprint(value))
+	}
+	return values
+}
+
+func parseCPUSet(answer string) error {
+	if _, err := cpuset.Parse(answer); err != nil {
+		return fmt.Errorf("%q is not a CPU list: %v", answer, err)
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
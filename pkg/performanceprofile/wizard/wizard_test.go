@@ -0,0 +1,195 @@
+package wizard
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/cpuset"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/profilecreator"
+	testutils "github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/utils/testing"
+)
+
+const (
+	crdPath           = "../../../manifests/20-performance-profile.crd.yaml"
+	mustGatherDirPath = "../../../test/e2e/performanceprofile/testdata/must-gather/must-gather.bare-metal"
+)
+
+// mustGatherSnapshots serves the snapshots collected by must-gather
+type mustGatherSnapshots struct{}
+
+func (mustGatherSnapshots) Snapshot(ctx context.Context, node *corev1.Node, w io.Writer) error {
+	matches, err := filepath.Glob(filepath.Join(mustGatherDirPath, "*", profilecreator.Nodes, node.Name, profilecreator.SysInfoFileName))
+	if err != nil {
+		return err
+	}
+	Expect(matches).To(HaveLen(1))
+
+	f, err := os.Open(matches[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func newFakeClient(objs ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+	Expect(apiextensionsv1.AddToScheme(s)).To(Succeed())
+	Expect(mcfgv1.AddToScheme(s)).To(Succeed())
+	Expect(performancev2.AddToScheme(s)).To(Succeed())
+
+	pools, err := profilecreator.GetMCPList(mustGatherDirPath)
+	Expect(err).ToNot(HaveOccurred())
+	for _, pool := range pools {
+		objs = append(objs, pool)
+	}
+	nodes, err := profilecreator.GetNodeList(mustGatherDirPath)
+	Expect(err).ToNot(HaveOccurred())
+	for _, node := range nodes {
+		objs = append(objs, node)
+	}
+
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func newSchema() *Schema {
+	data, err := os.ReadFile(crdPath)
+	Expect(err).ToNot(HaveOccurred())
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	Expect(yaml.Unmarshal(data, crd)).To(Succeed())
+
+	schema, err := NewSchema(crd)
+	Expect(err).ToNot(HaveOccurred())
+	return schema
+}
+
+func makeRange(first, last int) []int {
+	var values []int
+	for i := first; i <= last; i++ {
+		values = append(values, i)
+	}
+	return values
+}
+
+func answers(lines ...string) io.Reader {
+	return strings.NewReader(strings.Join(lines, "\n") + "\n")
+}
+
+var _ = Describe("Wizard", func() {
+	var out *bytes.Buffer
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+	})
+
+	Context("with a new profile", func() {
+		It("should create the profile out of the answers and the pool hardware", func() {
+			in := answers(
+				"",                 // name
+				"unknown",          // pool
+				"worker-cnf",       // pool
+				"true",             // realTimeKernel.enabled
+				"yes",              // workloadHints.realTime
+				"",                 // workloadHints.highPowerConsumption
+				"",                 // workloadHints.perPodPowerManagement
+				"4",                // reserved CPU count
+				"",                 // offlined CPU count
+				"",                 // split across NUMA
+				"",                 // disable SMT
+				"abc",              // cpu.reserved
+				"",                 // cpu.reserved
+				"",                 // cpu.isolated
+				"",                 // cpu.offlined
+				"single-numa-node", // numa.topologyPolicy
+				"",                 // net.userLevelNetworking
+			)
+			w := New(newFakeClient(), newSchema(), mustGatherSnapshots{}, in, out)
+
+			profile, _, err := w.Create(context.TODO())
+			Expect(err).ToNot(HaveOccurred(), out.String())
+
+			Expect(profile.Name).To(Equal(defaultProfileName))
+			Expect(profile.Spec.NodeSelector).To(HaveKey("node-role.kubernetes.io/worker-cnf"))
+			Expect(profile.Spec.MachineConfigPoolSelector).To(HaveKeyWithValue("machineconfiguration.openshift.io/role", "worker-cnf"))
+			Expect(*profile.Spec.RealTimeKernel.Enabled).To(BeTrue())
+			Expect(*profile.Spec.WorkloadHints.RealTime).To(BeTrue())
+			Expect(string(*profile.Spec.CPU.Reserved)).To(Equal("0,2,40,42"))
+			Expect(string(*profile.Spec.CPU.Isolated)).To(Equal(cpuset.New(makeRange(0, 79)...).Difference(cpuset.New(0, 2, 40, 42)).String()))
+			Expect(profile.Spec.CPU.Offlined).To(BeNil())
+			Expect(*profile.Spec.NUMA.TopologyPolicy).To(Equal("single-numa-node"))
+
+			Expect(out.String()).To(ContainSubstring("unknown machine config pool"))
+			Expect(out.String()).To(ContainSubstring(`"abc" is not a CPU list`))
+		})
+
+		It("should reject the pool tuned by another profile", func() {
+			existing := testutils.NewPerformanceProfile("existing")
+			existing.Spec.NodeSelector = map[string]string{"node-role.kubernetes.io/worker-cnf": ""}
+
+			w := New(newFakeClient(existing), newSchema(), mustGatherSnapshots{}, answers("existing", "performance", "worker-cnf"), out)
+			_, _, err := w.Create(context.TODO())
+			Expect(err).To(MatchError(ContainSubstring("no answer")))
+
+			Expect(out.String()).To(ContainSubstring(`the performance profile "existing" already exists`))
+			Expect(out.String()).To(ContainSubstring(`the profile has the same node selector as the performance profile "existing"`))
+		})
+	})
+
+	Context("with an existing profile", func() {
+		It("should propose the current values and validate the changes", func() {
+			existing := testutils.NewPerformanceProfile("existing")
+
+			in := answers(
+				"",          // realTimeKernel.enabled
+				"",          // workloadHints.realTime
+				"",          // workloadHints.highPowerConsumption
+				"maybe",     // workloadHints.perPodPowerManagement
+				"",          // workloadHints.perPodPowerManagement
+				"",          // cpu.reserved
+				"3-5",       // cpu.isolated
+				"4-5,10-11", // cpu.isolated
+				"",          // cpu.offlined
+				"",          // numa.topologyPolicy
+				"true",      // net.userLevelNetworking
+			)
+			w := New(newFakeClient(existing), newSchema(), mustGatherSnapshots{}, in, out)
+
+			profile, _, err := w.Edit(context.TODO(), existing.Name)
+			Expect(err).ToNot(HaveOccurred(), out.String())
+
+			Expect(out.String()).To(ContainSubstring("spec.cpu.reserved [0-3]"))
+			Expect(out.String()).To(ContainSubstring(`"maybe" is neither yes nor no`))
+			Expect(out.String()).To(ContainSubstring("reserved and isolated cpus overlap"))
+
+			Expect(profile.ResourceVersion).ToNot(BeEmpty())
+			Expect(*profile.Spec.RealTimeKernel.Enabled).To(BeTrue())
+			Expect(*profile.Spec.WorkloadHints.PerPodPowerManagement).To(BeFalse())
+			Expect(string(*profile.Spec.CPU.Isolated)).To(Equal("4-5,10-11"))
+			Expect(profile.Spec.HugePages).To(Equal(existing.Spec.HugePages))
+			Expect(profile.Spec.Net.UserLevelNetworking).To(Equal(pointer.Bool(true)))
+		})
+	})
+})
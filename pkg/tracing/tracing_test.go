@@ -0,0 +1,132 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/openshift/cluster-node-tuning-operator/pkg/tracing/tracingtest"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name           string
+		endpoint       string
+		tracesEndpoint string
+		expected       bool
+	}{
+		{
+			name:     "no endpoint",
+			expected: false,
+		},
+		{
+			name:     "OTLP endpoint",
+			endpoint: "http://collector:4317",
+			expected: true,
+		},
+		{
+			name:           "OTLP traces endpoint",
+			tracesEndpoint: "http://collector:4317",
+			expected:       true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(otlpEndpointEnv, tc.endpoint)
+			t.Setenv(otlpTracesEndpointEnv, tc.tracesEndpoint)
+			if got := Enabled(); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	t.Setenv(otlpEndpointEnv, "")
+	t.Setenv(otlpTracesEndpointEnv, "")
+
+	previous := otel.GetTracerProvider()
+	shutdown, err := Setup(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if otel.GetTracerProvider() != previous {
+		t.Errorf("expected the global tracer provider left alone without an OTLP endpoint")
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// the spans are not recorded
+	_, span := Start(context.Background(), "noop")
+	if span.IsRecording() {
+		t.Errorf("expected a non-recording span without an OTLP endpoint")
+	}
+	End(span, nil)
+}
+
+func TestSetupOTLP(t *testing.T) {
+	// the exporter connects lazily, nothing needs to listen on the endpoint
+	t.Setenv(otlpEndpointEnv, "http://127.0.0.1:4317")
+
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	shutdown, err := Setup(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider); !ok {
+		t.Errorf("expected the SDK tracer provider installed, got %T", otel.GetTracerProvider())
+	}
+
+	_, span := Start(context.Background(), "exported")
+	if !span.IsRecording() {
+		t.Errorf("expected a recording span with an OTLP endpoint")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = shutdown(ctx) // the span cannot be exported, only the shutdown matters
+}
+
+func TestStartEnd(t *testing.T) {
+	sr, restore := tracingtest.Install()
+	defer restore()
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, ok := Start(ctx, "ok")
+	End(ok, nil)
+	_, failed := Start(ctx, "failed")
+	End(failed, errors.New("boom"))
+	End(parent, nil)
+
+	spans := sr.Named("ok")
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span named ok, got %d", len(spans))
+	}
+	if s := spans[0]; s.Status().Code != codes.Unset || len(s.Events()) != 0 {
+		t.Errorf("expected no error recorded, got status %v and %d events", s.Status(), len(s.Events()))
+	}
+	if p := sr.Parent(spans[0]); p == nil || p.Name() != "parent" {
+		t.Errorf("expected the span ok to be a child of the span parent, got %v", p)
+	}
+
+	spans = sr.Named("failed")
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span named failed, got %d", len(spans))
+	}
+	s := spans[0]
+	if s.Status().Code != codes.Error || s.Status().Description != "boom" {
+		t.Errorf("expected the error status with the description boom, got %v", s.Status())
+	}
+	if len(s.Events()) != 1 || s.Events()[0].Name != "exception" {
+		t.Errorf("expected the error recorded as an exception event, got %v", s.Events())
+	}
+}
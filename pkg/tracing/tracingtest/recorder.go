@@ -0,0 +1,70 @@
+// Package tracingtest records the spans of the tests in memory.
+package tracingtest
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SpanRecorder is a span processor keeping the ended spans in memory.
+type SpanRecorder struct {
+	mu    sync.Mutex
+	ended []sdktrace.ReadOnlySpan
+}
+
+var _ sdktrace.SpanProcessor = &SpanRecorder{}
+
+// Install sets a global tracer provider recording the spans in a new SpanRecorder.  The returned function restores
+// the previous global tracer provider.
+func Install() (*SpanRecorder, func()) {
+	sr := &SpanRecorder{}
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	return sr, func() { otel.SetTracerProvider(previous) }
+}
+
+func (sr *SpanRecorder) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (sr *SpanRecorder) OnEnd(s sdktrace.ReadOnlySpan) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.ended = append(sr.ended, s)
+}
+
+func (sr *SpanRecorder) Shutdown(context.Context) error { return nil }
+
+func (sr *SpanRecorder) ForceFlush(context.Context) error { return nil }
+
+// Ended returns the ended spans in the order they ended.
+func (sr *SpanRecorder) Ended() []sdktrace.ReadOnlySpan {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	return append([]sdktrace.ReadOnlySpan{}, sr.ended...)
+}
+
+// Named returns the ended spans named 'name'.
+func (sr *SpanRecorder) Named(name string) []sdktrace.ReadOnlySpan {
+	var spans []sdktrace.ReadOnlySpan
+	for _, s := range sr.Ended() {
+		if s.Name() == name {
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+// Parent returns the ended span the span 's' is a child of, or nil if there is none.
+func (sr *SpanRecorder) Parent(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	if !s.Parent().IsValid() {
+		return nil
+	}
+	for _, p := range sr.Ended() {
+		if p.SpanContext().SpanID() == s.Parent().SpanID() {
+			return p
+		}
+	}
+	return nil
+}
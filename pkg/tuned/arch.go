@@ -0,0 +1,62 @@
+package tuned
+
+import (
+	"runtime" // runtime.GOARCH
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+// unameArch maps the GOARCH values to the architecture names reported by "uname -m".
+var unameArch = map[string]string{
+	"amd64":   "x86_64",
+	"arm64":   "aarch64",
+	"ppc64le": "ppc64le",
+	"s390x":   "s390x",
+}
+
+// nodeArch returns the architecture of the node the operand runs on as reported by "uname -m".
+func nodeArch() string {
+	if arch, ok := unameArch[runtime.GOARCH]; ok {
+		return arch
+	}
+	return runtime.GOARCH
+}
+
+// ProfilesForNodeArch returns the TuneD profiles 'profiles' with a single variant
+// of every TuneD profile name, the one for the architecture of the node the operand
+// runs on.
+func ProfilesForNodeArch(profiles []tunedv1.TunedProfile) []tunedv1.TunedProfile {
+	return profilesForArch(profiles, nodeArch())
+}
+
+// profilesForArch returns the TuneD profiles 'profiles' with a single variant of every
+// TuneD profile name: the variant for the architecture 'arch' or, if there is none, the
+// variant without arch.  The variants for the other architectures are dropped.
+func profilesForArch(profiles []tunedv1.TunedProfile, arch string) []tunedv1.TunedProfile {
+	archVariant := map[string]bool{}
+	for _, profile := range profiles {
+		if profile.Name != nil && profile.Arch != nil && *profile.Arch == arch {
+			archVariant[*profile.Name] = true
+		}
+	}
+
+	selected := make([]tunedv1.TunedProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		if profile.Name == nil {
+			// Keep the invalid profiles, the extraction reports them.
+			selected = append(selected, profile)
+			continue
+		}
+		switch {
+		case profile.Arch == nil || *profile.Arch == "":
+			if archVariant[*profile.Name] {
+				continue
+			}
+		case *profile.Arch != arch:
+			continue
+		}
+		selected = append(selected, profile)
+	}
+
+	return selected
+}
@@ -0,0 +1,56 @@
+package tuned
+
+import (
+	"testing"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+func TestProfilesForArch(t *testing.T) {
+	profile := func(name, arch, data string) tunedv1.TunedProfile {
+		p := tunedv1.TunedProfile{Name: &name, Data: &data}
+		if arch != "" {
+			p.Arch = &arch
+		}
+		return p
+	}
+	profiles := []tunedv1.TunedProfile{
+		profile("openshift-node", "", "generic"),
+		profile("openshift-node-custom", "", "custom generic"),
+		profile("openshift-node-custom", "x86_64", "custom x86_64"),
+		profile("openshift-node-custom", "aarch64", "custom aarch64"),
+		profile("openshift-node-x86", "x86_64", "x86_64 only"),
+	}
+
+	tests := []struct {
+		arch     string
+		expected []string
+	}{
+		{
+			arch:     "x86_64",
+			expected: []string{"generic", "custom x86_64", "x86_64 only"},
+		},
+		{
+			arch:     "aarch64",
+			expected: []string{"generic", "custom aarch64"},
+		},
+		{
+			arch:     "s390x",
+			expected: []string{"generic", "custom generic"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.arch, func(t *testing.T) {
+			selected := profilesForArch(profiles, tc.arch)
+			if len(selected) != len(tc.expected) {
+				t.Fatalf("expected %d profiles, got %d", len(tc.expected), len(selected))
+			}
+			for i := range selected {
+				if *selected[i].Data != tc.expected[i] {
+					t.Errorf("expected profile %s data %q, got %q", *selected[i].Name, tc.expected[i], *selected[i].Data)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package tuned
+
+import (
+	"crypto/sha256" // sha256.New()
+	"encoding/hex"  // hex.EncodeToString()
+	"encoding/json" // json.Marshal()
+	"errors"        // errors.Is()
+	"fmt"           // Errorf()
+	"os"            // os.ReadFile(), ...
+	"path/filepath" // filepath.Join()
+	"sync"          // sync.WaitGroup
+
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
+)
+
+const (
+	// The TuneD profiles last applied on the node, persisted on the host so the operand can warm-start
+	// the TuneD daemon with them on restart (e.g. after a node reboot) while it waits for the API server.
+	tunedProfilesCacheDir = "/host" + openshiftTunedHome + "/cache"
+	// Bump the version on incompatible changes of the profilesCache format.
+	profilesCacheVersion = 1
+	// The maximum number of TuneD profiles extracted in parallel.
+	profilesExtractWorkers = 8
+)
+
+// profilesCache is the index of the TuneD profiles cache, the cached profiles
+// are stored in the "profiles" subdirectory of the cache directory.
+type profilesCache struct {
+	// Version of the cache format.
+	Version int `json:"version"`
+	// Profile is the TuneD profile the cached profiles were applied as.
+	Profile string `json:"profile"`
+	// Profiles are the names of the cached custom TuneD profiles.
+	Profiles []string `json:"profiles"`
+	// Checksum is the checksum of the cached profiles, see profilesChecksum().
+	Checksum string `json:"checksum"`
+	// Provider is the cloud provider name the profiles were applied with.
+	Provider string `json:"provider"`
+}
+
+// profilesCacheID identifies the TuneD profiles cached for the applied TuneD profile 'profile'.
+func profilesCacheID(profile, checksum, provider string) string {
+	return profile + "/" + checksum + "/" + provider
+}
+
+func profilesCacheIndexFile(cacheDir string) string {
+	return filepath.Join(cacheDir, "index.json")
+}
+
+func profilesCacheProfilesDir(cacheDir string) string {
+	return filepath.Join(cacheDir, "profiles")
+}
+
+// writeProfilesCache caches the custom TuneD profiles 'profileNames' the applied TuneD profile
+// 'activeProfile' consists of from 'tunedProfilesDir' to 'cacheDir'.  The index is removed first
+// and written last, so an interrupted write leaves no cache behind rather than a partial one.
+func writeProfilesCache(cacheDir, tunedProfilesDir, activeProfile, provider string, profileNames []string) error {
+	indexFile := profilesCacheIndexFile(cacheDir)
+	if err := os.Remove(indexFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove the TuneD profiles cache index %q: %v", indexFile, err)
+	}
+	profilesDir := profilesCacheProfilesDir(cacheDir)
+	if err := os.RemoveAll(profilesDir); err != nil {
+		return fmt.Errorf("failed to remove the cached TuneD profiles %q: %v", profilesDir, err)
+	}
+
+	cache := &profilesCache{
+		Version:  profilesCacheVersion,
+		Profile:  activeProfile,
+		Checksum: profilesChecksum(profileNames, tunedProfilesDir),
+		Provider: provider,
+	}
+	jobs := []func() error{}
+	for _, profileName := range profileNames {
+		data, err := os.ReadFile(filepath.Join(tunedProfilesDir, profileName, tunedConfFile))
+		if errors.Is(err, os.ErrNotExist) {
+			// Profiles shipped with the TuneD daemon.
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read TuneD profile %s: %v", profileName, err)
+		}
+		cache.Profiles = append(cache.Profiles, profileName)
+		profileName := profileName
+		jobs = append(jobs, func() error {
+			return writeProfile(profilesDir, profileName, string(data))
+		})
+	}
+	if err := runParallel(jobs, profilesExtractWorkers); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the TuneD profiles cache index: %v", err)
+	}
+	return writeFileAtomic(indexFile, append(data, '\n'))
+}
+
+// restoreProfilesCache extracts the TuneD profiles cached in 'cacheDir' to 'tunedProfilesDir'
+// after verifying the cached profiles against the checksum of the cache index.  Nothing is
+// extracted unless the whole cache passed the verification.  It returns nil when there is no
+// usable cache and an error when the cache failed the verification.
+func restoreProfilesCache(cacheDir, tunedProfilesDir string) (*profilesCache, error) {
+	data, err := os.ReadFile(profilesCacheIndexFile(cacheDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the TuneD profiles cache index: %v", err)
+	}
+	cache := &profilesCache{}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the TuneD profiles cache index: %v", err)
+	}
+	if cache.Version != profilesCacheVersion {
+		return nil, nil
+	}
+
+	profilesDir := profilesCacheProfilesDir(cacheDir)
+	profilesData := make([][]byte, len(cache.Profiles))
+	jobs := make([]func() error, 0, len(cache.Profiles))
+	for i, profileName := range cache.Profiles {
+		i, profileName := i, profileName
+		jobs = append(jobs, func() error {
+			data, err := os.ReadFile(filepath.Join(profilesDir, profileName, tunedConfFile))
+			if err != nil {
+				return fmt.Errorf("failed to read the cached TuneD profile %s: %v", profileName, err)
+			}
+			profilesData[i] = data
+			return nil
+		})
+	}
+	if err := runParallel(jobs, profilesExtractWorkers); err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	for i, profileName := range cache.Profiles {
+		writeProfileChecksum(h, profileName, profilesData[i])
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != cache.Checksum {
+		return nil, fmt.Errorf("the cached TuneD profiles checksum %s does not match the cache index checksum %s", sum, cache.Checksum)
+	}
+
+	jobs = jobs[:0]
+	for i, profileName := range cache.Profiles {
+		i, profileName := i, profileName
+		jobs = append(jobs, func() error {
+			return writeProfile(tunedProfilesDir, profileName, string(profilesData[i]))
+		})
+	}
+	if err := runParallel(jobs, profilesExtractWorkers); err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+// writeProfile writes the TuneD profile 'profileName' with 'data' to 'tunedProfilesDir'.
+func writeProfile(tunedProfilesDir, profileName, data string) error {
+	profileDir := filepath.Join(tunedProfilesDir, profileName)
+	if err := util.Mkdir(profileDir); err != nil {
+		return fmt.Errorf("failed to create TuneD profile directory %q: %v", profileDir, err)
+	}
+
+	profileFile := filepath.Join(profileDir, tunedConfFile)
+	f, err := os.Create(profileFile)
+	if err != nil {
+		return fmt.Errorf("failed to create TuneD profile file %q: %v", profileFile, err)
+	}
+	defer f.Close()
+	if _, err = f.WriteString(data); err != nil {
+		return fmt.Errorf("failed to write TuneD profile file %q: %v", profileFile, err)
+	}
+
+	return nil
+}
+
+// runParallel runs 'jobs' with at most 'workers' of them at a time and returns
+// the first error encountered, if any, once all of them finished.
+func runParallel(jobs []func() error, workers int) error {
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	sem := make(chan struct{}, workers)
+	for _, job := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(job func() error) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := job(); err != nil {
+				errOnce.Do(func() { firstErr = err })
+			}
+		}(job)
+	}
+	wg.Wait()
+
+	return firstErr
+}
@@ -0,0 +1,61 @@
+package tuned
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfilesCache(t *testing.T) {
+	profilesDir, cacheDir, restoreDir := t.TempDir(), filepath.Join(t.TempDir(), "cache"), t.TempDir()
+	for name, data := range map[string]string{
+		"openshift":      "[main]\nsummary=openshift\n",
+		"openshift-node": "[main]\nsummary=node\ninclude=openshift\n",
+	} {
+		if err := writeProfile(profilesDir, name, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	profileNames := []string{"openshift", "openshift-node", "throughput-performance"}
+
+	if cached, err := restoreProfilesCache(cacheDir, restoreDir); cached != nil || err != nil {
+		t.Fatalf("expected no cache, got %v: %v", cached, err)
+	}
+
+	if err := writeProfilesCache(cacheDir, profilesDir, "openshift-node", "aws", profileNames); err != nil {
+		t.Fatalf("failed to write the profiles cache: %v", err)
+	}
+	cached, err := restoreProfilesCache(cacheDir, restoreDir)
+	if err != nil {
+		t.Fatalf("failed to restore the profiles cache: %v", err)
+	}
+	if cached.Profile != "openshift-node" || cached.Provider != "aws" {
+		t.Errorf("unexpected cached profile %q or provider %q", cached.Profile, cached.Provider)
+	}
+	if len(cached.Profiles) != 2 {
+		t.Errorf("expected the profiles shipped with the TuneD daemon not to be cached, got %v", cached.Profiles)
+	}
+	if got, expected := profilesChecksum(profileNames, restoreDir), profilesChecksum(profileNames, profilesDir); got != expected {
+		t.Errorf("the restored profiles checksum %s does not match the applied profiles checksum %s", got, expected)
+	}
+
+	// Tamper with a cached profile.
+	if err := writeProfile(profilesCacheProfilesDir(cacheDir), "openshift", "[main]\nsummary=changed\n"); err != nil {
+		t.Fatal(err)
+	}
+	tamperedDir := t.TempDir()
+	if _, err := restoreProfilesCache(cacheDir, tamperedDir); err == nil {
+		t.Errorf("expected the verification of the tampered cache to fail")
+	}
+	if entries, err := os.ReadDir(tamperedDir); err != nil || len(entries) != 0 {
+		t.Errorf("expected no profiles extracted from the tampered cache, got %v: %v", entries, err)
+	}
+
+	// An interrupted cache write leaves no index.
+	if err := os.Remove(profilesCacheIndexFile(cacheDir)); err != nil {
+		t.Fatal(err)
+	}
+	if cached, err := restoreProfilesCache(cacheDir, t.TempDir()); cached != nil || err != nil {
+		t.Errorf("expected no cache without the index, got %v: %v", cached, err)
+	}
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/openshift/cluster-node-tuning-operator/pkg/signals"
 	"github.com/openshift/cluster-node-tuning-operator/pkg/tuned"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/tuned/cmd/snapshot"
 	"github.com/openshift/cluster-node-tuning-operator/version"
 
 	"github.com/spf13/cobra"
@@ -51,6 +52,7 @@ func NewTunedCommand() *cobra.Command {
 
 	addKlogFlags(cmd)
 	tunedOpts.AddFlags(cmd.Flags())
+	cmd.AddCommand(snapshot.NewSnapshotCommand())
 	return cmd
 }
 
@@ -210,7 +210,7 @@ func render(inputDir []string, outputDir string, mcpName string) error {
 
 	t := manifests.TunedRenderedResource(tuneD)
 	//extract all the profiles.
-	_, _, _, err = tunedpkg.ProfilesExtract(t.Spec.Profile, recommendedProfile)
+	_, _, _, err = tunedpkg.ProfilesExtract(tunedpkg.ProfilesForNodeArch(t.Spec.Profile), recommendedProfile)
 	if err != nil {
 		klog.Errorf("error extracting tuned profiles : %v", err)
 		return fmt.Errorf("error extracting tuned profiles: %w", err)
@@ -0,0 +1,80 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/klog/v2"
+)
+
+type snapshotOpts struct {
+	output    string
+	configMap string
+}
+
+func NewSnapshotCommand() *cobra.Command {
+	snapshotOpts := snapshotOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Collect a node tuning snapshot for support cases",
+		Long: `Collect the TuneD daemon log, the TuneD profiles, the applied sysctls, the cpuset cgroup tree,
+the IRQ affinities and the kernel command line of the node into a gzip-compressed tarball.
+The tarball is written to the node (or to a mounted volume) or uploaded to a ConfigMap.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := snapshotOpts.Validate(); err != nil {
+				klog.Fatal(err)
+			}
+
+			if err := snapshotOpts.Run(); err != nil {
+				klog.Fatal(err)
+			}
+		},
+	}
+
+	addKlogFlags(cmd)
+	snapshotOpts.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func (s *snapshotOpts) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&s.output, "output", "o", s.output,
+		fmt.Sprintf("Output path for the snapshot tarball; defaults to a %s/tuned-snapshot-<node>-<timestamp>.tar.gz file on the node.", snapshotDirDefault))
+	fs.StringVar(&s.configMap, "configmap", s.configMap, "Name of the ConfigMap in the operand namespace to upload the snapshot tarball to.")
+}
+
+func addKlogFlags(cmd *cobra.Command) {
+	fs := flag.NewFlagSet("", flag.PanicOnError)
+	klog.InitFlags(fs)
+	cmd.Flags().AddGoFlagSet(fs)
+}
+
+func (s *snapshotOpts) Validate() error {
+	if len(s.output) > 0 && len(s.configMap) > 0 {
+		return fmt.Errorf("only one of output and configmap can be specified")
+	}
+
+	return nil
+}
+
+func (s *snapshotOpts) Run() error {
+	return snapshot(s.output, s.configMap)
+}
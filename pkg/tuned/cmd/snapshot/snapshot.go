@@ -0,0 +1,120 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	ntoclient "github.com/openshift/cluster-node-tuning-operator/pkg/client"
+	ntoconfig "github.com/openshift/cluster-node-tuning-operator/pkg/config"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/tuned"
+)
+
+const (
+	// The host root directory mounted to the operand container.
+	hostRootDir        = "/host"
+	snapshotDirDefault = hostRootDir + "/var/tmp"
+	// The ConfigMap size limit is 1MiB, leave room for the ConfigMap metadata.
+	configMapDataMaxSize = 1000 * 1024
+)
+
+func snapshot(output, configMap string) error {
+	nodeName := snapshotNodeName()
+
+	var b bytes.Buffer
+	if err := tuned.WriteSnapshot(&b); err != nil {
+		return err
+	}
+
+	if len(configMap) > 0 {
+		return snapshotUpload(configMap, fmt.Sprintf("tuned-snapshot-%s.tar.gz", nodeName), b.Bytes())
+	}
+
+	if len(output) == 0 {
+		output = filepath.Join(snapshotDirDefault,
+			fmt.Sprintf("tuned-snapshot-%s-%s.tar.gz", nodeName, time.Now().UTC().Format("20060102-150405")))
+	}
+	if err := os.WriteFile(output, b.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write the node tuning snapshot: %v", err)
+	}
+	klog.Infof("node tuning snapshot written to %s", output)
+
+	return nil
+}
+
+// snapshotUpload stores the snapshot 'data' under the key 'key' of the ConfigMap 'name' in the operand
+// namespace.  The ConfigMap is created if it does not exist, the snapshots of the other nodes are kept.
+func snapshotUpload(name, key string, data []byte) error {
+	config, err := ntoclient.GetConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	namespace := ntoconfig.WatchNamespace()
+	cm, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get ConfigMap %s/%s: %v", namespace, name, err)
+	}
+	create := apierrors.IsNotFound(err)
+	if create {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+		}
+	}
+	if cm.BinaryData == nil {
+		cm.BinaryData = map[string][]byte{}
+	}
+	cm.BinaryData[key] = data
+
+	size := 0
+	for _, v := range cm.Data {
+		size += len(v)
+	}
+	for _, v := range cm.BinaryData {
+		size += len(v)
+	}
+	if size > configMapDataMaxSize {
+		return fmt.Errorf("the node tuning snapshot does not fit in ConfigMap %s/%s (%d bytes), write the snapshot to a volume instead", namespace, name, size)
+	}
+
+	if create {
+		_, err = kubeClient.CoreV1().ConfigMaps(namespace).Create(context.TODO(), cm, metav1.CreateOptions{})
+	} else {
+		_, err = kubeClient.CoreV1().ConfigMaps(namespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to upload the node tuning snapshot to ConfigMap %s/%s: %v", namespace, name, err)
+	}
+	klog.Infof("node tuning snapshot uploaded to ConfigMap %s/%s key %s", namespace, name, key)
+
+	return nil
+}
+
+// snapshotNodeName returns the name of the node the snapshot is taken on.
+func snapshotNodeName() string {
+	if name := os.Getenv("OCP_NODE_NAME"); len(name) > 0 {
+		return name
+	}
+	// Not run in the operand container, e.g. in a debug container.
+	name, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return name
+}
@@ -0,0 +1,220 @@
+package tuned
+
+import (
+	"bufio"         // bufio.NewScanner()
+	"context"       // context.TODO()
+	"encoding/json" // json.Unmarshal()
+	"fmt"           // Sprintf()
+	"os"            // os.ReadFile()
+	"path/filepath" // filepath.Glob()
+	"strings"       // strings.HasPrefix()
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
+)
+
+const (
+	// CmdlineTrackedReason is the TunedCmdlineTracked condition reason reported when the Node
+	// booted with the kernel command line its boot loader entry and rendered MachineConfig define.
+	CmdlineTrackedReason = "Tracked"
+	// CmdlineUntrackedModificationsReason is the TunedCmdlineTracked condition reason reported when
+	// the Node booted with kernel command line modifications neither the boot loader entry nor the
+	// rendered MachineConfig define, for example ones edited at the boot loader prompt.
+	CmdlineUntrackedModificationsReason = "UntrackedModifications"
+	// The annotation of the rendered MachineConfig the Node booted with, set by the machine config daemon.
+	mcdCurrentConfigAnnotation = "machineconfiguration.openshift.io/currentConfig"
+	// The kernel argument identifying the booted OSTree deployment and its boot loader entry.
+	ostreeKernelArgPrefix = "ostree="
+)
+
+var (
+	// procCmdlineFile is the kernel command line the Node booted with.
+	procCmdlineFile = "/proc/cmdline"
+	// loaderEntriesDir is the directory of the Boot Loader Specification entries of the host.
+	loaderEntriesDir = hostRoot + "/boot/loader/entries"
+)
+
+// isCmdlineArgIgnored returns true for the kernel arguments the boot loader manages itself.
+func isCmdlineArgIgnored(arg string) bool {
+	return strings.HasPrefix(arg, "BOOT_IMAGE=") ||
+		strings.HasPrefix(arg, "$") || // unexpanded GRUB variables of the boot loader entries
+		arg == "ignition.firstboot"
+}
+
+// getLoaderEntryOptions returns the kernel arguments of the boot loader entry in 'dir' booting
+// the OSTree deployment of kernel command line 'cmdline'.  It returns nil when 'cmdline' does
+// not boot an OSTree deployment.
+func getLoaderEntryOptions(dir string, cmdline []string) ([]string, error) {
+	ostree := ""
+	for _, arg := range cmdline {
+		if strings.HasPrefix(arg, ostreeKernelArgPrefix) {
+			ostree = arg
+		}
+	}
+	if ostree == "" {
+		return nil, nil
+	}
+
+	entries, err := filepath.Glob(filepath.Join(dir, "*.conf"))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		content, err := os.ReadFile(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the boot loader entry %s: %v", entry, err)
+		}
+		scanner := bufio.NewScanner(strings.NewReader(string(content)))
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) == 0 || fields[0] != "options" {
+				continue
+			}
+			for _, arg := range fields[1:] {
+				if arg == ostree {
+					return fields[1:], nil
+				}
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no boot loader entry in %s boots %s", dir, ostree)
+}
+
+// untrackedCmdlineArgs returns the kernel arguments of kernel command line 'cmdline' the boot
+// loader entry options 'options' do not pass (prefixed by "+"), and the kernel arguments of
+// 'options' and of the rendered MachineConfig 'expected' 'cmdline' does not pass (prefixed by "-").
+func untrackedCmdlineArgs(cmdline, options, expected []string) []string {
+	set := func(args []string) map[string]bool {
+		m := map[string]bool{}
+		for _, arg := range args {
+			m[arg] = true
+		}
+		return m
+	}
+	cmdlineSet, optionsSet := set(cmdline), set(options)
+
+	untracked := []string{}
+	seen := map[string]bool{}
+	add := func(arg string) {
+		if !seen[arg] {
+			seen[arg] = true
+			untracked = append(untracked, arg)
+		}
+	}
+	if options != nil {
+		for _, arg := range cmdline {
+			if !isCmdlineArgIgnored(arg) && !optionsSet[arg] {
+				add("+" + arg)
+			}
+		}
+	}
+	for _, args := range [][]string{options, expected} {
+		for _, arg := range args {
+			if !isCmdlineArgIgnored(arg) && !cmdlineSet[arg] {
+				add("-" + arg)
+			}
+		}
+	}
+
+	return untracked
+}
+
+// getCmdlineManifestEntry returns the expected kernel command line of the rendered MachineConfig
+// 'mcName' the operator published or nil if the operator did not publish it (yet).
+func (c *Controller) getCmdlineManifestEntry(mcName string) (*util.KernelCmdlineManifestEntry, error) {
+	if c.cmdlineManifest.entry != nil && c.cmdlineManifest.mcName == mcName {
+		// The rendered MachineConfigs are immutable, so are their manifest entries.
+		return c.cmdlineManifest.entry, nil
+	}
+
+	cm, err := c.clients.Core.ConfigMaps(operandNamespace).Get(context.TODO(), tunedv1.TunedCmdlineManifestConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get ConfigMap %s: %v", tunedv1.TunedCmdlineManifestConfigMapName, err)
+	}
+	data, ok := cm.Data[mcName]
+	if !ok {
+		return nil, nil
+	}
+
+	entry := &util.KernelCmdlineManifestEntry{}
+	if err := json.Unmarshal([]byte(data), entry); err != nil {
+		return nil, fmt.Errorf("failed to parse the kernel command line of MachineConfig %s: %v", mcName, err)
+	}
+	c.cmdlineManifest.mcName, c.cmdlineManifest.entry = mcName, entry
+
+	return entry, nil
+}
+
+// getUntrackedCmdlineArgs returns the untracked kernel command line modifications of Node 'node'
+// and whether they could be determined.  They cannot be before the operator published the expected
+// kernel command line of the rendered MachineConfig the Node booted with.
+func (c *Controller) getUntrackedCmdlineArgs(node *corev1.Node) ([]string, bool) {
+	mcName := node.ObjectMeta.Annotations[mcdCurrentConfigAnnotation]
+	if mcName == "" {
+		return nil, false
+	}
+
+	entry, err := c.getCmdlineManifestEntry(mcName)
+	if err != nil {
+		klog.Errorf("failed to get the expected kernel command line: %v", err)
+		return nil, false
+	}
+	if entry == nil {
+		return nil, false
+	}
+
+	content, err := os.ReadFile(procCmdlineFile)
+	if err != nil {
+		klog.Errorf("failed to read the kernel command line: %v", err)
+		return nil, false
+	}
+	cmdline := strings.Fields(string(content))
+
+	options, err := getLoaderEntryOptions(loaderEntriesDir, cmdline)
+	if err != nil {
+		klog.Errorf("failed to get the boot loader entry: %v", err)
+		return nil, false
+	}
+
+	return untrackedCmdlineArgs(cmdline, options, entry.KernelArguments), true
+}
+
+// setCmdlineTrackedStatusCondition returns 'conditions' with the TunedCmdlineTracked condition
+// reporting the 'untracked' kernel command line modifications, or without the condition when
+// they could not be determined ('determined').
+func setCmdlineTrackedStatusCondition(conditions []tunedv1.ProfileStatusCondition, untracked []string, determined bool) []tunedv1.ProfileStatusCondition {
+	if !determined {
+		newConditions := []tunedv1.ProfileStatusCondition{}
+		for _, c := range conditions {
+			if c.Type != tunedv1.TunedCmdlineTracked {
+				newConditions = append(newConditions, c)
+			}
+		}
+		return newConditions
+	}
+
+	if len(untracked) > 0 {
+		return setStatusCondition(conditions, &tunedv1.ProfileStatusCondition{
+			Type:    tunedv1.TunedCmdlineTracked,
+			Status:  corev1.ConditionFalse,
+			Reason:  CmdlineUntrackedModificationsReason,
+			Message: fmt.Sprintf("The Node booted with untracked kernel command line modifications: %s.", strings.Join(untracked, " ")),
+		})
+	}
+
+	return setStatusCondition(conditions, &tunedv1.ProfileStatusCondition{
+		Type:    tunedv1.TunedCmdlineTracked,
+		Status:  corev1.ConditionTrue,
+		Reason:  CmdlineTrackedReason,
+		Message: "The Node booted with the kernel command line of its boot loader entry and rendered MachineConfig.",
+	})
+}
@@ -0,0 +1,111 @@
+package tuned
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+func TestGetLoaderEntryOptions(t *testing.T) {
+	dir := t.TempDir()
+	entries := map[string]string{
+		"ostree-1-rhcos.conf": "title Red Hat Enterprise Linux CoreOS (ostree:1)\nversion 1\noptions $ignition_firstboot ostree=/ostree/boot.1/rhcos/a/0 nosmt\n",
+		"ostree-2-rhcos.conf": "title Red Hat Enterprise Linux CoreOS (ostree:0)\nversion 2\noptions $ignition_firstboot ostree=/ostree/boot.1/rhcos/b/0 nosmt isolcpus=2-3\n",
+	}
+	for name, content := range entries {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	options, err := getLoaderEntryOptions(dir, strings.Fields("BOOT_IMAGE=(hd0,gpt3)/vmlinuz ostree=/ostree/boot.1/rhcos/b/0 nosmt isolcpus=2-3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Fields("$ignition_firstboot ostree=/ostree/boot.1/rhcos/b/0 nosmt isolcpus=2-3")
+	if !reflect.DeepEqual(options, expected) {
+		t.Errorf("expected the boot loader entry options %v, got %v", expected, options)
+	}
+
+	if options, err := getLoaderEntryOptions(dir, strings.Fields("BOOT_IMAGE=/vmlinuz nosmt")); err != nil || options != nil {
+		t.Errorf("expected no boot loader entry options without an OSTree deployment, got %v: %v", options, err)
+	}
+
+	if _, err := getLoaderEntryOptions(dir, strings.Fields("ostree=/ostree/boot.1/rhcos/c/0")); err == nil {
+		t.Errorf("expected an error without the boot loader entry of the booted OSTree deployment")
+	}
+}
+
+func TestUntrackedCmdlineArgs(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		cmdline  string
+		options  []string
+		expected string
+		result   []string
+	}{
+		{
+			desc:     "should accept the tracked kernel command line",
+			cmdline:  "BOOT_IMAGE=(hd0,gpt3)/vmlinuz ostree=/ostree/0 ignition.firstboot nosmt isolcpus=2-3",
+			options:  strings.Fields("$ignition_firstboot ostree=/ostree/0 nosmt isolcpus=2-3"),
+			expected: "nosmt isolcpus=2-3",
+			result:   []string{},
+		},
+		{
+			desc:     "should flag the kernel arguments edited at the boot loader prompt",
+			cmdline:  "BOOT_IMAGE=/vmlinuz ostree=/ostree/0 nosmt init=/bin/sh",
+			options:  strings.Fields("ostree=/ostree/0 nosmt isolcpus=2-3"),
+			expected: "nosmt isolcpus=2-3",
+			result:   []string{"+init=/bin/sh", "-isolcpus=2-3"},
+		},
+		{
+			desc:     "should flag the kernel arguments of the MachineConfig missing in the boot loader entry",
+			cmdline:  "BOOT_IMAGE=/vmlinuz ostree=/ostree/0 nosmt",
+			options:  strings.Fields("ostree=/ostree/0 nosmt"),
+			expected: "nosmt isolcpus=2-3",
+			result:   []string{"-isolcpus=2-3"},
+		},
+		{
+			desc:     "should only compare with the MachineConfig without the boot loader entry",
+			cmdline:  "BOOT_IMAGE=/vmlinuz nosmt quiet",
+			expected: "nosmt isolcpus=2-3",
+			result:   []string{"-isolcpus=2-3"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result := untrackedCmdlineArgs(strings.Fields(tc.cmdline), tc.options, strings.Fields(tc.expected))
+			if !reflect.DeepEqual(result, tc.result) {
+				t.Errorf("expected the untracked kernel arguments %v, got %v", tc.result, result)
+			}
+		})
+	}
+}
+
+func TestCmdlineTrackedStatusCondition(t *testing.T) {
+	conditions := InitializeStatusConditions()
+
+	conditions = setCmdlineTrackedStatusCondition(conditions, []string{"+init=/bin/sh"}, true)
+	c := findStatusCondition(conditions, tunedv1.TunedCmdlineTracked)
+	if c == nil || c.Status != corev1.ConditionFalse || c.Reason != CmdlineUntrackedModificationsReason || !strings.Contains(c.Message, "+init=/bin/sh") {
+		t.Errorf("expected the untracked TunedCmdlineTracked condition, got %+v", c)
+	}
+
+	conditions = setCmdlineTrackedStatusCondition(conditions, []string{}, true)
+	c = findStatusCondition(conditions, tunedv1.TunedCmdlineTracked)
+	if c == nil || c.Status != corev1.ConditionTrue || c.Reason != CmdlineTrackedReason {
+		t.Errorf("expected the tracked TunedCmdlineTracked condition, got %+v", c)
+	}
+
+	conditions = setCmdlineTrackedStatusCondition(conditions, nil, false)
+	if c := findStatusCondition(conditions, tunedv1.TunedCmdlineTracked); c != nil {
+		t.Errorf("expected no TunedCmdlineTracked condition without the expected kernel command line, got %+v", c)
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"math"    // math.Pow()
 	"os"      // os.Exit(), os.Stderr, ...
 	"os/exec" // os.Exec()
+	"reflect" // reflect.DeepEqual()
 	"strings" // strings.Join()
+	"sync"    // sync.Mutex
 	"syscall" // syscall.SIGHUP, ...
 	"time"    // time.Second, ...
 
@@ -19,6 +21,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	coreset "k8s.io/client-go/kubernetes/typed/core/v1"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
@@ -42,6 +45,7 @@ const (
 	scWarn
 	scError
 	scSysctlOverride
+	scDeferred
 	scUnknown
 )
 
@@ -58,6 +62,8 @@ const (
 	tunedRecommendFile     = tunedRecommendDir + "/50-openshift.conf"
 	tunedBootcmdlineEnvVar = "TUNED_BOOT_CMDLINE"
 	tunedBootcmdlineFile   = tunedProfilesDirCustom + "/bootcmdline"
+	// The boot ID of the node, the same as the kubelet reports in the Node status.
+	bootIDFile = "/proc/sys/kernel/random/boot_id"
 	// A couple of seconds should be more than enough for TuneD daemon to gracefully stop;
 	// be generous and give it 10s.
 	tunedGracefulExitWait  = time.Second * time.Duration(10)
@@ -85,6 +91,10 @@ type Daemon struct {
 	reloaded bool
 	// debugging flag
 	debug bool
+	// verbosity of the TuneD daemon log in the operand log.
+	logLevel tunedv1.TuneDLogLevel
+	// time-boxed capture of the TuneD daemon log requested by the node Profile.
+	capture debugCapture
 	// bit/set representaton of Profile status conditions to report back via API.
 	status Bits
 	// stderr log from TuneD daemon to report back via API.
@@ -93,6 +103,24 @@ type Daemon struct {
 	stopping bool
 	// the TuneD profile we wish to be applied.
 	recommendedProfile string
+	// deferred is true when the node Profile requests that changes are not applied
+	// to a running TuneD daemon.
+	deferred bool
+	// huge pages the node Profile requests to allocate at runtime.
+	hugepages []tunedv1.RuntimeHugepages
+	// huge pages to release as the node Profile no longer requests them.
+	hugepagesReleased []tunedv1.RuntimeHugepages
+	// outcome of the last runtime huge pages allocation to report back via API;
+	// nil when the node Profile requests no huge pages at runtime.
+	hugepagesCondition *tunedv1.ProfileStatusCondition
+	// sysctls the sysctl policy of Tuned/default denies by the TuneD profile name.
+	sysctlsDenied map[string][]string
+	// paths under read-only locations dropped from the TuneD profiles by the TuneD profile name.
+	pathsDropped map[string][]string
+	// SHA-256 checksums of the extracted TuneD profiles by the TuneD profile name.
+	profileHashes map[string]string
+	// checks of the rendered Tuned verifying the TuneD profiles after they are applied.
+	verification []tunedv1.TunedVerification
 }
 
 type Controller struct {
@@ -118,6 +146,9 @@ type Controller struct {
 		// Did the command-line parameters to run the TuneD daemon change?
 		// In other words, is a complete restart of the TuneD daemon needed?
 		daemon bool
+		// Did the huge pages to allocate at runtime change?
+		// It is kept set while the allocation falls short to retry it later.
+		hugepages bool
 	}
 
 	daemon Daemon
@@ -128,6 +159,21 @@ type Controller struct {
 	changeCh     chan bool       // bi-directional channel to wake-up the main thread to process accrued changes
 	changeChRet  chan bool       // bi-directional channel to announce success/failure of change processing
 	tunedMainCfg *ini.File       // global TuneD configuration as defined in tuned-main.conf
+	health       *operandHealth  // TuneD daemon state reported by the health endpoints
+
+	startTime   time.Time // operand start time
+	startupOnce sync.Once // records the operand startup duration once the first profile is applied
+	// warmStart is true when the TuneD daemon was started with the cached profiles.
+	warmStart bool
+	// warmStartTried is true once the cached profiles were considered for a warm start.
+	warmStartTried bool
+	// cachedProfile identifies the profiles last written to the TuneD profiles cache.
+	cachedProfile string
+	// cmdlineManifest is the expected kernel command line of the rendered MachineConfig mcName.
+	cmdlineManifest struct {
+		mcName string
+		entry  *util.KernelCmdlineManifestEntry
+	}
 }
 
 type wqKey struct {
@@ -185,6 +231,8 @@ func newController(stopCh <-chan struct{}) (*Controller, error) {
 		stopCh:      stopCh,
 		changeCh:    make(chan bool, 1),
 		changeChRet: make(chan bool, 1),
+		health:      newOperandHealth(),
+		startTime:   time.Now(),
 	}
 
 	return controller, nil
@@ -248,11 +296,24 @@ func (c *Controller) sync(key wqKey) error {
 			return fmt.Errorf("failed to get Tuned %s: %v", key.name, err)
 		}
 
-		change, err := profilesSync(tuned.Spec.Profile, c.daemon.recommendedProfile)
+		profiles, sysctlsDenied := sysctlPolicyEnforce(tuned.Spec.SysctlPolicy, ProfilesForNodeArch(tuned.Spec.Profile))
+		for profileName, denied := range sysctlsDenied {
+			klog.Warningf("the sysctl policy of Tuned/%s denies sysctl(s) %s in TuneD profile %s, not applying them",
+				tunedv1.TunedDefaultResourceName, strings.Join(denied, ", "), profileName)
+		}
+		profiles, pathsDropped := readOnlyPathsEnforce(profiles)
+		for profileName, dropped := range pathsDropped {
+			klog.Warningf("TuneD profile %s writes read-only path(s) %s, not applying them", profileName, strings.Join(dropped, ", "))
+		}
+		change, err := profilesSync(profiles, c.daemon.recommendedProfile)
 		if err != nil {
 			return err
 		}
 		c.change.rendered = change
+		c.daemon.sysctlsDenied = sysctlsDenied
+		c.daemon.pathsDropped = pathsDropped
+		c.daemon.profileHashes = profileHashes(profiles)
+		c.daemon.verification = tuned.Spec.Verification
 		// Notify the event processor that the Tuned k8s object containing TuneD profiles changed.
 		c.wqTuneD.Add(wqKey{kind: wqKindDaemon})
 
@@ -280,10 +341,22 @@ func (c *Controller) sync(key wqKey) error {
 			return err
 		}
 		c.change.profile = true
+		c.daemon.deferred = profileDeferred(profile)
 
-		if c.daemon.debug != profile.Spec.Config.Debug {
+		if !reflect.DeepEqual(c.daemon.hugepages, profile.Spec.Config.Hugepages) {
+			c.change.hugepages = true
+			c.daemon.hugepagesReleased = append(c.daemon.hugepagesReleased, releasedHugepages(c.daemon.hugepages, profile.Spec.Config.Hugepages)...)
+			c.daemon.hugepages = profile.Spec.Config.Hugepages
+		}
+
+		c.daemon.logLevel = profile.Spec.Config.LogLevel
+		c.debugCaptureSync(profile.Spec.Config.DebugCapture)
+		debug := profile.Spec.Config.Debug ||
+			c.daemon.logLevel == tunedv1.TuneDLogLevelDebug ||
+			c.daemon.capture.active(time.Now())
+		if c.daemon.debug != debug {
 			c.change.daemon = true // A complete restart of the TuneD daemon is needed due to a debugging request switched on or off.
-			c.daemon.debug = profile.Spec.Config.Debug
+			c.daemon.debug = debug
 		}
 		if profile.Spec.Config.TuneDConfig.ReapplySysctl != nil {
 			reapplySysctl := c.tunedMainCfg.Section("").Key("reapply_sysctl").MustBool()
@@ -347,14 +420,21 @@ func ProfilesExtract(profiles []tunedv1.TunedProfile, recommendedProfile string)
 	klog.Infof("profilesExtract(): extracting %d TuneD profiles", len(profiles))
 
 	recommendedProfileDeps := map[string]bool{}
-	if len(recommendedProfile) > 0 {
+	// The recommended profile can be a space-separated list of TuneD profiles merged by the TuneD daemon,
+	// e.g. when supplemental profiles are recommended on top of the primary one.
+	for _, profileName := range strings.Fields(recommendedProfile) {
 		// Get a list of TuneD profiles names the recommended profile depends on.
-		recommendedProfileDeps = profileDepends(recommendedProfile)
+		for dep := range profileDepends(profileName) {
+			recommendedProfileDeps[dep] = true
+		}
 		// Add the recommended profile itself.
-		recommendedProfileDeps[recommendedProfile] = true
+		recommendedProfileDeps[profileName] = true
 	}
 	extracted := map[string]bool{} // TuneD profile names present in TuneD CR and successfully extracted to /etc/tuned/<profile>/
 
+	// The profiles are independent of each other, extract them in parallel.
+	var lock sync.Mutex
+	jobs := make([]func() error, 0, len(profiles))
 	for index, profile := range profiles {
 		if profile.Name == nil {
 			klog.Warningf("profilesExtract(): profile name missing for Profile %v", index)
@@ -364,33 +444,38 @@ func ProfilesExtract(profiles []tunedv1.TunedProfile, recommendedProfile string)
 			klog.Warningf("profilesExtract(): profile data missing for Profile %v", index)
 			continue
 		}
-		profileDir := fmt.Sprintf("%s/%s", tunedProfilesDirCustom, *profile.Name)
-		profileFile := fmt.Sprintf("%s/%s", profileDir, tunedConfFile)
-
-		if err := util.Mkdir(profileDir); err != nil {
-			return change, extracted, recommendedProfileDeps, fmt.Errorf("failed to create TuneD profile directory %q: %v", profileDir, err)
-		}
+		profileName, profileData := *profile.Name, *profile.Data
+		jobs = append(jobs, func() error {
+			profileFile := fmt.Sprintf("%s/%s/%s", tunedProfilesDirCustom, profileName, tunedConfFile)
+			unchanged := profilesEqual(profileFile, profileData)
+
+			if recommendedProfileDeps[profileName] {
+				// Recommended profile (dependency) name matches profile name of the profile
+				// currently being extracted, compare their content.
+				var un string
+				if unchanged {
+					un = "un"
+				}
+				klog.Infof("recommended TuneD profile %s content %schanged [%s]", recommendedProfile, un, profileName)
+			}
 
-		if recommendedProfileDeps[*profile.Name] {
-			// Recommended profile (dependency) name matches profile name of the profile
-			// currently being extracted, compare their content.
-			var un string
-			change = change || !profilesEqual(profileFile, *profile.Data)
-			if !change {
-				un = "un"
+			// Do not rewrite the profiles that did not change, e.g. on the operand restart.
+			if !unchanged {
+				if err := writeProfile(tunedProfilesDirCustom, profileName, profileData); err != nil {
+					return err
+				}
 			}
-			klog.Infof("recommended TuneD profile %s content %schanged [%s]", recommendedProfile, un, *profile.Name)
-		}
 
-		f, err := os.Create(profileFile)
-		if err != nil {
-			return change, extracted, recommendedProfileDeps, fmt.Errorf("failed to create TuneD profile file %q: %v", profileFile, err)
-		}
-		defer f.Close()
-		if _, err = f.WriteString(*profile.Data); err != nil {
-			return change, extracted, recommendedProfileDeps, fmt.Errorf("failed to write TuneD profile file %q: %v", profileFile, err)
-		}
-		extracted[*profile.Name] = true
+			lock.Lock()
+			defer lock.Unlock()
+			change = change || (recommendedProfileDeps[profileName] && !unchanged)
+			extracted[profileName] = true
+			return nil
+		})
+	}
+
+	if err := runParallel(jobs, profilesExtractWorkers); err != nil {
+		return change, extracted, recommendedProfileDeps, err
 	}
 
 	return change, extracted, recommendedProfileDeps, nil
@@ -499,11 +584,21 @@ func (c *Controller) tunedRun() {
 	klog.Infof("starting tuned...")
 
 	defer func() {
+		c.health.daemonExited()
 		close(c.tunedExit)
 	}()
 
 	onDaemonReload := func() {
 		klog.V(2).Infof("profile applied or reload failed, stopping the TuneD watcher")
+		applied := (c.daemon.status & scApplied) != 0
+		c.health.reloadFinished(applied)
+		if applied {
+			c.startupOnce.Do(func() {
+				startup := time.Since(c.startTime)
+				klog.Infof("TuneD daemon applied the first profile %v after the operand start; warm start: %v", startup.Round(time.Millisecond), c.warmStart)
+				startupFinished(startup, c.warmStart)
+			})
+		}
 		// Notify the event processor that the TuneD daemon finished reloading.
 		c.wqTuneD.Add(wqKey{kind: wqKindDaemon})
 	}
@@ -561,6 +656,7 @@ func (c *Controller) tunedReload() error {
 	c.daemon.status = 0 // clear the set out of which Profile status conditions are created
 	c.daemon.stderr = ""
 
+	c.health.reloadStarted()
 	if c.tunedCmd == nil {
 		// TuneD hasn't been started by openshift-tuned, start it.
 		c.tunedCmd = c.tunedCreateCmd()
@@ -618,6 +714,17 @@ func getActiveProfile() (string, error) {
 	return responseString, nil
 }
 
+// getBootID returns the boot ID of the node or an empty string if it cannot be read.
+func getBootID() string {
+	bootID, err := os.ReadFile(bootIDFile)
+	if err != nil {
+		klog.Errorf("failed to read the node boot ID: %v", err)
+		return ""
+	}
+
+	return strings.TrimSpace(string(bootID))
+}
+
 func GetBootcmdline() (string, error) {
 	var responseString = ""
 
@@ -648,7 +755,12 @@ func GetBootcmdline() (string, error) {
 // synced and an error.  Only critical errors are returned, as non-nil errors
 // will cause restart of the main control loop -- the changeWatcher() method.
 func (c *Controller) changeSyncer() (synced bool, err error) {
-	var reload bool
+	var (
+		reload bool
+		// hugepagesShort is true when the runtime huge pages allocation fell short
+		// and needs to be retried later.
+		hugepagesShort bool
+	)
 
 	if c.daemon.reloading {
 		// This should not be necessary, but keep this here as a reminder.
@@ -674,6 +786,24 @@ func (c *Controller) changeSyncer() (synced bool, err error) {
 		}
 	}
 
+	if c.change.hugepages {
+		// The huge pages to allocate at runtime changed or their previous allocation fell short.
+		// The allocation is independent of the TuneD daemon and does not need a reload.
+		c.daemon.hugepagesCondition = allocateHugepages(hugepagesRoot, c.daemon.hugepages, c.daemon.hugepagesReleased)
+		c.daemon.hugepagesReleased = nil
+		if err = c.updateTunedProfile(); err != nil {
+			klog.Error(err.Error())
+			return false, nil // retry later
+		}
+		hugepagesShort = c.daemon.hugepagesCondition != nil && c.daemon.hugepagesCondition.Status != corev1.ConditionTrue
+		if hugepagesShort {
+			// Requeue, the rate limiter spaces the retries so that the memory has a chance to be freed.
+			klog.Errorf("runtime huge pages allocation fell short: %s", c.daemon.hugepagesCondition.Message)
+		} else {
+			c.change.hugepages = false
+		}
+	}
+
 	// Check whether reload of the TuneD daemon is really necessary due to a Profile change.
 	if c.change.profile {
 		// The node Profile k8s object changed.
@@ -682,7 +812,7 @@ func (c *Controller) changeSyncer() (synced bool, err error) {
 			return false, err
 		}
 		if (c.daemon.status & scApplied) == 0 {
-			if len(activeProfile) > 0 {
+			if len(activeProfile) > 0 && (c.daemon.status&scDeferred) == 0 {
 				// activeProfile == "" means we have not started TuneD daemon yet; do not log that case
 				klog.Infof("re-applying profile (%s) as the previous application did not complete", activeProfile)
 			}
@@ -709,18 +839,38 @@ func (c *Controller) changeSyncer() (synced bool, err error) {
 		c.daemon.status = scUnknown
 	}
 
+	if c.deferApply(reload) {
+		// The new configuration has already been written to disk and will be picked up on the
+		// next TuneD daemon start; keep the current tuning to avoid disturbing running workloads.
+		klog.Infof("deferring application of profile (%s) until the next TuneD daemon restart", c.daemon.recommendedProfile)
+		c.daemon.status = scDeferred
+		if err = c.updateTunedProfile(); err != nil {
+			klog.Error(err.Error())
+			return false, nil // retry later
+		}
+		return !hugepagesShort, nil
+	}
+
 	if c.change.daemon {
 		// Complete restart of the TuneD daemon needed (e.g. using --debug option).
 		c.change.daemon = false
 		c.daemon.status = scUnknown
 		err = c.tunedRestart()
-		return err == nil, err
+		return err == nil && !hugepagesShort, err
 	}
 
 	if reload {
 		err = c.tunedReload()
 	}
-	return err == nil, err
+	return err == nil && !hugepagesShort, err
+}
+
+// deferApply returns true if the TuneD daemon reload or restart, requested by 'reload' and
+// c.change.daemon respectively, is to be deferred.  This is the case when TuneD is already
+// running and the node Profile requests deferred updates.  The first application of
+// the profile after the operand start is never deferred.
+func (c *Controller) deferApply(reload bool) bool {
+	return c.daemon.deferred && c.tunedCmd != nil && (reload || c.change.daemon)
 }
 
 // eventProcessorTuneD is a long-running method that will continually
@@ -770,6 +920,16 @@ func (c *Controller) eventProcessorTuneD() {
 	}
 }
 
+// profileDeferred returns true if the Profile 'profile' requests deferred updates.
+func profileDeferred(profile *tunedv1.Profile) bool {
+	if profile.ObjectMeta.Annotations == nil {
+		return false
+	}
+	_, ok := profile.ObjectMeta.Annotations[tunedv1.TunedDeferredUpdate]
+
+	return ok
+}
+
 func getNodeName() string {
 	name := os.Getenv("OCP_NODE_NAME")
 	if len(name) == 0 {
@@ -851,11 +1011,60 @@ func (c *Controller) updateTunedProfile() (err error) {
 		node.ObjectMeta.Annotations = map[string]string{}
 	}
 
+	// The boot ID records the node boot the profile was applied in, so the operator
+	// knows the profile is yet to be applied after a node reboot.
+	bootID := profile.Status.BootID
+	if (c.daemon.status & scApplied) != 0 {
+		bootID = getBootID()
+	}
+
+	// The image and the version of the operand tell the nodes running a stale operand after an upgrade.
+	operandImage := ntoconfig.NodeTunedImage()
+
+	// The time window is reported once the profile the window selected is active.
+	schedule := profile.Status.Schedule
+	if activeProfile == profile.Spec.Config.TunedProfile {
+		schedule = profile.Spec.Config.Schedule
+	}
+
 	statusConditions := computeStatusConditions(c.daemon.status, c.daemon.stderr, profile.Status.Conditions)
+	statusConditions = setHugepagesStatusCondition(statusConditions, c.daemon.hugepagesCondition)
+	statusConditions = setSysctlPolicyStatusCondition(statusConditions, c.recommendedSysctlsDenied())
+	statusConditions = setPathsWritableStatusCondition(statusConditions, c.recommendedPathsDropped())
+	// The checks verify the applied profile, keep the last results until the profile is applied.
+	if (c.daemon.status & scApplied) != 0 {
+		checks := c.recommendedVerification()
+		statusConditions = setVerifiedStatusCondition(statusConditions, len(checks), runVerification(checks))
+	}
+	untrackedCmdline, cmdlineDetermined := c.getUntrackedCmdlineArgs(node)
+	statusConditions = setCmdlineTrackedStatusCondition(statusConditions, untrackedCmdline, cmdlineDetermined)
+	state := newNodeTuningState(activeProfile, bootcmdline, statusConditions)
+	// The state file is best effort, do not block the Profile update on failures to write it.
+	if err := writeNodeTuningState(openshiftTunedStateFile, state); err != nil {
+		klog.Errorf("failed to export the node tuning state: %v", err)
+	}
+	c.updateProfilesCache(state, profile.Spec.Config.ProviderName)
 	bootcmdlineAnnotVal, bootcmdlineAnnotSet := node.ObjectMeta.Annotations[tunedv1.TunedBootcmdlineAnnotationKey]
 
+	annotations := map[string]string{tunedv1.TunedBootcmdlineAnnotationKey: bootcmdline}
+	// The kubelet configuration checksums tell the operator the Node runs the kubelet configuration
+	// the PerformanceProfile generated.  They are best effort, do not block the Profile update.
+	kubeletConfigChecksums, kubeletConfigErr := getKubeletConfigChecksums(kubeletConfigFile)
+	if kubeletConfigErr != nil {
+		klog.Errorf("failed to get the kubelet configuration checksums: %v", kubeletConfigErr)
+	} else {
+		annotations[tunedv1.TunedKubeletConfigChecksumsAnnotationKey] = kubeletConfigChecksums
+	}
+	kubeletConfigAnnotVal := node.ObjectMeta.Annotations[tunedv1.TunedKubeletConfigChecksumsAnnotationKey]
+
 	if bootcmdlineAnnotSet && bootcmdlineAnnotVal == bootcmdline &&
+		(kubeletConfigErr != nil || kubeletConfigAnnotVal == kubeletConfigChecksums) &&
 		profile.Status.TunedProfile == activeProfile &&
+		profile.Status.BootID == bootID &&
+		reflect.DeepEqual(profile.Status.ProfileHashes, c.daemon.profileHashes) &&
+		profile.Status.OperandImage == operandImage &&
+		profile.Status.OperandVersion == version.Version &&
+		profile.Status.Schedule == schedule &&
 		conditionsEqual(profile.Status.Conditions, statusConditions) {
 		// Do not update node Profile unnecessarily (e.g. bootcmdline did not change).
 		// This will save operator CPU cycles trying to reconcile objects that do not
@@ -864,7 +1073,6 @@ func (c *Controller) updateTunedProfile() (err error) {
 		return nil
 	}
 
-	annotations := map[string]string{tunedv1.TunedBootcmdlineAnnotationKey: bootcmdline}
 	err = c.updateNodeAnnotations(node, annotations)
 	if err != nil {
 		return err
@@ -873,6 +1081,11 @@ func (c *Controller) updateTunedProfile() (err error) {
 	profile = profile.DeepCopy() // never update the objects from cache
 
 	profile.Status.TunedProfile = activeProfile
+	profile.Status.BootID = bootID
+	profile.Status.ProfileHashes = c.daemon.profileHashes
+	profile.Status.OperandImage = operandImage
+	profile.Status.OperandVersion = version.Version
+	profile.Status.Schedule = schedule
 	profile.Status.Conditions = statusConditions
 	_, err = c.clients.Tuned.TunedV1().Profiles(operandNamespace).UpdateStatus(context.TODO(), profile, metav1.UpdateOptions{})
 	if err != nil {
@@ -883,6 +1096,111 @@ func (c *Controller) updateTunedProfile() (err error) {
 	return nil
 }
 
+// recommendedSysctlsDenied returns the sysctls the sysctl policy denies in the
+// TuneD profiles the recommended TuneD profile consists of.
+func (c *Controller) recommendedSysctlsDenied() []string {
+	if len(c.daemon.sysctlsDenied) == 0 {
+		return nil
+	}
+
+	return profileEntriesIn(c.daemon.sysctlsDenied, c.recommendedProfiles())
+}
+
+// recommendedPathsDropped returns the read-only paths dropped from the TuneD
+// profiles the recommended TuneD profile consists of.
+func (c *Controller) recommendedPathsDropped() []string {
+	if len(c.daemon.pathsDropped) == 0 {
+		return nil
+	}
+
+	return profileEntriesIn(c.daemon.pathsDropped, c.recommendedProfiles())
+}
+
+// recommendedVerification returns the checks verifying the TuneD profiles
+// the recommended TuneD profile consists of.
+func (c *Controller) recommendedVerification() []tunedv1.TunedVerification {
+	if len(c.daemon.verification) == 0 {
+		return nil
+	}
+
+	profiles := c.recommendedProfiles()
+	checks := []tunedv1.TunedVerification{}
+	for _, check := range c.daemon.verification {
+		if profiles[check.Profile] {
+			checks = append(checks, check)
+		}
+	}
+
+	return checks
+}
+
+// recommendedProfiles returns the set of the TuneD profiles the recommended TuneD profile consists of.
+func (c *Controller) recommendedProfiles() map[string]bool {
+	profiles := map[string]bool{}
+	for _, profileName := range strings.Fields(c.daemon.recommendedProfile) {
+		for dep := range profileDepends(profileName) {
+			profiles[dep] = true
+		}
+		profiles[profileName] = true
+	}
+
+	return profiles
+}
+
+// updateProfilesCache caches the profiles of the applied TuneD profile for the next
+// warm start of the TuneD daemon.  The cache is best effort.
+func (c *Controller) updateProfilesCache(state *nodeTuningState, provider string) {
+	if (c.daemon.status & scApplied) == 0 {
+		// Only cache the profiles the TuneD daemon applied successfully.
+		return
+	}
+	cachedProfile := profilesCacheID(state.Profile, state.Checksum, provider)
+	if cachedProfile == c.cachedProfile {
+		return
+	}
+
+	if err := writeProfilesCache(tunedProfilesCacheDir, tunedProfilesDirCustom, state.Profile, provider, appliedProfiles(state.Profile)); err != nil {
+		klog.Errorf("failed to cache the TuneD profiles: %v", err)
+		return
+	}
+	c.cachedProfile = cachedProfile
+	klog.V(1).Infof("cached the TuneD profiles of profile %s", state.Profile)
+}
+
+// warmStartTuneD starts the TuneD daemon with the profiles cached on the node the last time it
+// applied a profile, so the node is tuned while the operand waits for the API server.  Changes
+// of the Tuned and Profile k8s objects are applied as usual once the informer caches sync.
+func (c *Controller) warmStartTuneD() {
+	c.warmStartTried = true
+
+	cached, err := restoreProfilesCache(tunedProfilesCacheDir, tunedProfilesDirCustom)
+	if err != nil {
+		klog.Warningf("not warm-starting the TuneD daemon: %v", err)
+		return
+	}
+	if cached == nil {
+		klog.V(1).Infof("no cached TuneD profiles, not warm-starting the TuneD daemon")
+		return
+	}
+
+	if err = providerExtract(cached.Provider); err != nil {
+		klog.Warningf("not warm-starting the TuneD daemon: %v", err)
+		return
+	}
+	if err = TunedRecommendFileWrite(cached.Profile); err != nil {
+		klog.Warningf("not warm-starting the TuneD daemon: %v", err)
+		return
+	}
+
+	klog.Infof("warm-starting the TuneD daemon with the cached profile %s", cached.Profile)
+	c.daemon.recommendedProfile = cached.Profile
+	c.cachedProfile = profilesCacheID(cached.Profile, cached.Checksum, cached.Provider)
+	c.warmStart = true
+	if err = c.tunedReload(); err != nil {
+		klog.Errorf("failed to warm-start the TuneD daemon: %v", err)
+	}
+}
+
 func (c *Controller) informerEventHandler(workqueueKey wqKey) cache.ResourceEventHandlerFuncs {
 	return cache.ResourceEventHandlerFuncs{
 		AddFunc: func(o interface{}) {
@@ -962,6 +1280,10 @@ func (c *Controller) changeWatcher() (err error) {
 	if err != nil {
 		return err
 	}
+	c.clients.Core, err = coreset.NewForConfig(c.kubeconfig)
+	if err != nil {
+		return err
+	}
 
 	tunedInformerFactory := tunedinformers.NewSharedInformerFactoryWithOptions(
 		c.clients.Tuned,
@@ -982,6 +1304,11 @@ func (c *Controller) changeWatcher() (err error) {
 
 	tunedInformerFactory.Start(c.stopCh) // Tuned/Profile
 
+	if c.tunedCmd == nil && !c.warmStartTried {
+		// Start the TuneD daemon with the cached profiles while the informer caches sync.
+		c.warmStartTuneD()
+	}
+
 	// Wait for the caches to be synced before starting worker(s).
 	klog.V(1).Info("waiting for informer caches to sync")
 	ok := cache.WaitForCacheSync(c.stopCh,
@@ -1015,6 +1342,8 @@ func (c *Controller) changeWatcher() (err error) {
 	}
 
 	klog.Info("started controller")
+	c.health.controllerStarted()
+	defer c.health.controllerStopped()
 	for {
 		select {
 		case <-c.stopCh:
@@ -1135,5 +1464,7 @@ func RunOperand(stopCh <-chan struct{}, version string, inCluster bool) error {
 		panic(err.Error())
 	}
 
+	go c.health.runHealthServer(stopCh)
+
 	return retryLoop(c)
 }
@@ -0,0 +1,141 @@
+package tuned
+
+import (
+	"os/exec"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+func TestProfileDeferred(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name:     "no annotations",
+			expected: false,
+		},
+		{
+			name:        "other annotations",
+			annotations: map[string]string{"foo": "bar"},
+			expected:    false,
+		},
+		{
+			name:        "deferred update annotation",
+			annotations: map[string]string{tunedv1.TunedDeferredUpdate: ""},
+			expected:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			profile := &tunedv1.Profile{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if got := profileDeferred(profile); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestDeferApply(t *testing.T) {
+	tests := []struct {
+		name          string
+		deferred      bool
+		tunedStarted  bool
+		reload        bool
+		daemonChanged bool
+		expected      bool
+	}{
+		{
+			name:         "reload of the running TuneD is deferred",
+			deferred:     true,
+			tunedStarted: true,
+			reload:       true,
+			expected:     true,
+		},
+		{
+			name:          "restart of the running TuneD is deferred",
+			deferred:      true,
+			tunedStarted:  true,
+			daemonChanged: true,
+			expected:      true,
+		},
+		{
+			name:         "nothing to apply",
+			deferred:     true,
+			tunedStarted: true,
+			expected:     false,
+		},
+		{
+			name:         "the deferred update annotation was removed",
+			deferred:     false,
+			tunedStarted: true,
+			reload:       true,
+			expected:     false,
+		},
+		{
+			name:         "first application after the operand restart",
+			deferred:     true,
+			tunedStarted: false,
+			reload:       true,
+			expected:     false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Controller{daemon: Daemon{deferred: tc.deferred}}
+			c.change.daemon = tc.daemonChanged
+			if tc.tunedStarted {
+				c.tunedCmd = exec.Command("tuned")
+			}
+			if got := c.deferApply(tc.reload); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestComputeStatusConditionsDeferred(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         Bits
+		expectedStatus corev1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "deferred",
+			status:         scDeferred,
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: ProfileAppliedDeferredReason,
+		},
+		{
+			name:           "applied after the deferral ended",
+			status:         scApplied,
+			expectedStatus: corev1.ConditionTrue,
+			expectedReason: "AsExpected",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			conditions := computeStatusConditions(tc.status, "", InitializeStatusConditions())
+			for _, condition := range conditions {
+				if condition.Type != tunedv1.TunedProfileApplied {
+					continue
+				}
+				if condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason {
+					t.Errorf("expected the %s condition %s/%s, got %s/%s", tunedv1.TunedProfileApplied,
+						tc.expectedStatus, tc.expectedReason, condition.Status, condition.Reason)
+				}
+				return
+			}
+			t.Errorf("no %s condition", tunedv1.TunedProfileApplied)
+		})
+	}
+}
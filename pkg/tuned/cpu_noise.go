@@ -0,0 +1,146 @@
+package tuned
+
+import (
+	"bufio"         // bufio.NewScanner()
+	"fmt"           // Sprintf()
+	"os"            // os.ReadFile(), ...
+	"path/filepath" // filepath.Join()
+	"strconv"       // strconv.ParseFloat()
+	"strings"       // strings.Fields()
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/cpuset"
+)
+
+const (
+	isolatedCPUNoiseEventsQuery = "nto_isolated_cpu_noise_events_total"
+
+	// Types of the isolated CPU noise events.
+	cpuNoisePreemption = "preemption"
+	cpuNoiseThrottling = "throttling"
+
+	// The index of the pcount field, the number of timeslices run on the CPU, in the
+	// /proc/schedstat cpu<N> lines following the CPU name.  Unlike the other scheduler
+	// statistics, it is accounted without the kernel.sched_schedstats sysctl.
+	schedstatTimeslicesField = 8
+)
+
+// cpuNoiseRoot is the directory the sysfs and procfs scheduler statistics paths are relative to.
+var cpuNoiseRoot = "/"
+
+// cpuNoiseCollector samples the scheduler and the thermal statistics of the isolated CPUs
+// on every scrape, so the metric carries no stale CPUs after the isolated CPUs changed.
+type cpuNoiseCollector struct {
+	root string
+	desc *prometheus.Desc
+}
+
+func newCPUNoiseCollector(root string) *cpuNoiseCollector {
+	return &cpuNoiseCollector{
+		root: root,
+		desc: prometheus.NewDesc(
+			isolatedCPUNoiseEventsQuery,
+			"The noise events on the isolated CPUs, by CPU and by type: the timeslices the scheduler ran on the CPU (preemption) and the thermal throttling of the CPU core (throttling).",
+			[]string{"cpu", "type"},
+			nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *cpuNoiseCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector.
+func (c *cpuNoiseCollector) Collect(ch chan<- prometheus.Metric) {
+	isolated, err := isolatedCPUs(c.root)
+	if err != nil {
+		klog.Errorf("failed to read the isolated CPUs: %v", err)
+		return
+	}
+	if isolated.IsEmpty() {
+		return
+	}
+
+	timeslices, err := schedstatTimeslices(c.root)
+	if err != nil {
+		klog.Errorf("failed to read the scheduler statistics: %v", err)
+	}
+	for _, cpu := range isolated.List() {
+		label := strconv.Itoa(cpu)
+		if count, ok := timeslices[cpu]; ok {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, count, label, cpuNoisePreemption)
+		}
+		// The thermal throttling statistics are x86 specific.
+		if count, err := readCounter(filepath.Join(c.root, "sys/devices/system/cpu", "cpu"+label, "thermal_throttle/core_throttle_count")); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, count, label, cpuNoiseThrottling)
+		} else if !os.IsNotExist(err) {
+			klog.Errorf("failed to read the thermal throttling of CPU %d: %v", cpu, err)
+		}
+	}
+}
+
+// isolatedCPUs returns the CPUs isolated from the scheduler domains or running without the
+// scheduler tick, the CPUs expected to stay quiet.
+func isolatedCPUs(root string) (cpuset.CPUSet, error) {
+	isolated := cpuset.New()
+	for _, file := range []string{"isolated", "nohz_full"} {
+		data, err := os.ReadFile(filepath.Join(root, "sys/devices/system/cpu", file))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return cpuset.CPUSet{}, err
+		}
+		// nohz_full reads "(null)" on the kernels booted without it.
+		value := strings.TrimSpace(string(data))
+		if value == "" || value == "(null)" {
+			continue
+		}
+		cpus, err := cpuset.Parse(value)
+		if err != nil {
+			return cpuset.CPUSet{}, fmt.Errorf("failed to parse %s: %v", file, err)
+		}
+		isolated = isolated.Union(cpus)
+	}
+	return isolated, nil
+}
+
+// schedstatTimeslices returns the number of timeslices run on the CPUs by their ID, from /proc/schedstat.
+func schedstatTimeslices(root string) (map[int]float64, error) {
+	f, err := os.Open(filepath.Join(root, "proc/schedstat"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	timeslices := map[int]float64{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) <= schedstatTimeslicesField+1 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		cpu, err := strconv.Atoi(strings.TrimPrefix(fields[0], "cpu"))
+		if err != nil {
+			continue
+		}
+		count, err := strconv.ParseFloat(fields[schedstatTimeslicesField+1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the scheduler statistics of CPU %d: %v", cpu, err)
+		}
+		timeslices[cpu] = count
+	}
+	return timeslices, scanner.Err()
+}
+
+// readCounter returns the counter held by the sysfs file 'file'.
+func readCounter(file string) (float64, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+}
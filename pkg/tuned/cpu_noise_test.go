@@ -0,0 +1,57 @@
+package tuned
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCPUNoiseCollector(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"sys/devices/system/cpu/isolated":                                  "2\n",
+		"sys/devices/system/cpu/nohz_full":                                 "3\n",
+		"sys/devices/system/cpu/cpu2/thermal_throttle/core_throttle_count": "5\n",
+		"proc/schedstat": `version 15
+timestamp 4295000000
+cpu0 0 0 100 50 20 10 1000 200 70
+domain0 00000003 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0
+cpu2 0 0 30 15 5 5 400 10 12
+cpu3 0 0 10 5 2 2 100 5 4
+`,
+	}
+	for file, content := range files {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := `
+# HELP nto_isolated_cpu_noise_events_total The noise events on the isolated CPUs, by CPU and by type: the timeslices the scheduler ran on the CPU (preemption) and the thermal throttling of the CPU core (throttling).
+# TYPE nto_isolated_cpu_noise_events_total counter
+nto_isolated_cpu_noise_events_total{cpu="2",type="preemption"} 12
+nto_isolated_cpu_noise_events_total{cpu="2",type="throttling"} 5
+nto_isolated_cpu_noise_events_total{cpu="3",type="preemption"} 4
+`
+	if err := testutil.CollectAndCompare(newCPUNoiseCollector(root), strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+
+	// nohz_full reads "(null)" without isolated CPUs
+	if err := os.WriteFile(filepath.Join(root, "sys/devices/system/cpu/isolated"), []byte("\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "sys/devices/system/cpu/nohz_full"), []byte("(null)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if count := testutil.CollectAndCount(newCPUNoiseCollector(root)); count != 0 {
+		t.Errorf("expected no metric without isolated CPUs, got %d", count)
+	}
+}
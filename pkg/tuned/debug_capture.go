@@ -0,0 +1,228 @@
+package tuned
+
+import (
+	"context" // context.TODO()
+	"fmt"     // fmt.Errorf()
+	"strings" // strings.Contains()
+	"sync"    // sync.Mutex
+	"time"    // time.Time
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+const (
+	// The prefix of the per-node ConfigMap in the operand namespace the captured TuneD daemon log is uploaded to.
+	debugCaptureConfigMapPrefix = "tuned-debug-capture-"
+	debugCaptureConfigMapKey    = "tuned.log"
+	// The ConfigMap size limit is 1MiB, leave room for the ConfigMap metadata.
+	debugCaptureMaxSize = 1000 * 1024
+	// How often the captured TuneD daemon log is uploaded while the capture runs.
+	debugCaptureUploadPeriod = 30 * time.Second
+)
+
+// debugCapture holds the TuneD daemon log lines captured until a deadline.  Once the captured
+// lines exceed debugCaptureMaxSize, the oldest lines are dropped.
+type debugCapture struct {
+	sync.Mutex
+	until time.Time
+	lines []string
+	size  int
+	// changed is true when lines were captured since the last upload.
+	changed bool
+	// done is closed when the capture is replaced or cancelled.
+	done chan struct{}
+}
+
+// start starts a new capture ending at 'until', dropping the lines of the previous capture.
+// Returns the channel closed when the capture is replaced or cancelled.
+func (d *debugCapture) start(until time.Time) <-chan struct{} {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.done != nil {
+		close(d.done)
+	}
+	d.until = until
+	d.lines = nil
+	d.size = 0
+	d.changed = true
+	d.done = make(chan struct{})
+
+	return d.done
+}
+
+// cancel ends the current capture, if any.
+func (d *debugCapture) cancel() {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.done != nil {
+		close(d.done)
+		d.done = nil
+	}
+	d.until = time.Time{}
+}
+
+// deadline returns the time the current capture ends at, the zero time without a capture.
+func (d *debugCapture) deadline() time.Time {
+	d.Lock()
+	defer d.Unlock()
+
+	return d.until
+}
+
+// active returns true if the capture runs at 'now'.
+func (d *debugCapture) active(now time.Time) bool {
+	d.Lock()
+	defer d.Unlock()
+
+	return now.Before(d.until)
+}
+
+// add captures the TuneD daemon log line 'line' if the capture runs.
+func (d *debugCapture) add(line string) {
+	d.Lock()
+	defer d.Unlock()
+
+	if !time.Now().Before(d.until) {
+		return
+	}
+	d.lines = append(d.lines, line)
+	d.size += len(line) + 1
+	for d.size > debugCaptureMaxSize && len(d.lines) > 0 {
+		d.size -= len(d.lines[0]) + 1
+		d.lines = d.lines[1:]
+	}
+	d.changed = true
+}
+
+// data returns the captured log and whether it changed since the last call.
+func (d *debugCapture) data() (string, bool) {
+	d.Lock()
+	defer d.Unlock()
+
+	if !d.changed {
+		return "", false
+	}
+	d.changed = false
+	if len(d.lines) == 0 {
+		return "", true
+	}
+
+	return strings.Join(d.lines, "\n") + "\n", true
+}
+
+// logLevelShows returns true if the TuneD daemon log line 'line' is shown in the operand log at
+// the TuneD daemon log level 'level'.  The lines without a TuneD log level, e.g. the Python
+// tracebacks, are always shown.
+func logLevelShows(level tunedv1.TuneDLogLevel, line string) bool {
+	var hidden []string
+	switch level {
+	case tunedv1.TuneDLogLevelError:
+		hidden = []string{" WARNING ", " INFO ", " DEBUG "}
+	case tunedv1.TuneDLogLevelWarning:
+		hidden = []string{" INFO ", " DEBUG "}
+	default:
+		return true
+	}
+	for _, h := range hidden {
+		if strings.Contains(line, h) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// debugCaptureSync starts, replaces or cancels the capture of the TuneD daemon debug log
+// as requested by the node Profile 'capture'.
+func (c *Controller) debugCaptureSync(capture *tunedv1.TuneDDebugCapture) {
+	if capture == nil || !time.Now().Before(capture.Until.Time) {
+		if c.daemon.capture.active(time.Now()) {
+			klog.Infof("TuneD daemon debug log capture cancelled")
+			c.daemon.capture.cancel()
+			c.debugCaptureUpload()
+		}
+		return
+	}
+	if c.daemon.capture.deadline().Equal(capture.Until.Time) {
+		return
+	}
+
+	klog.Infof("capturing the TuneD daemon debug log until %s", capture.Until.UTC().Format(time.RFC3339))
+	done := c.daemon.capture.start(capture.Until.Time)
+	go c.debugCaptureRun(capture.Until.Time, done)
+}
+
+// debugCaptureRun uploads the captured TuneD daemon log periodically until the capture ends at
+// 'until' or 'done' is closed.  Once the capture ends, the node Profile is synced again to restart
+// the TuneD daemon with the configured verbosity.
+func (c *Controller) debugCaptureRun(until time.Time, done <-chan struct{}) {
+	ticker := time.NewTicker(debugCaptureUploadPeriod)
+	defer ticker.Stop()
+	deadline := time.NewTimer(time.Until(until))
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			c.debugCaptureUpload()
+			return
+		case <-done:
+			return
+		case <-ticker.C:
+			c.debugCaptureUpload()
+		case <-deadline.C:
+			c.debugCaptureUpload()
+			klog.Infof("TuneD daemon debug log capture ended")
+			c.wqKube.Add(wqKey{kind: wqKindProfile, name: getNodeName()})
+			return
+		}
+	}
+}
+
+// debugCaptureUpload uploads the captured TuneD daemon log, if it changed, to the ConfigMap of the node.
+func (c *Controller) debugCaptureUpload() {
+	data, changed := c.daemon.capture.data()
+	if !changed {
+		return
+	}
+	if err := c.debugCaptureConfigMapUpdate(data); err != nil {
+		klog.Errorf("failed to upload the TuneD daemon debug log: %v", err)
+	}
+}
+
+func (c *Controller) debugCaptureConfigMapUpdate(data string) error {
+	name := debugCaptureConfigMapPrefix + getNodeName()
+	cm, err := c.clients.Core.ConfigMaps(operandNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get ConfigMap %s/%s: %v", operandNamespace, name, err)
+	}
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: operandNamespace,
+			},
+			Data: map[string]string{debugCaptureConfigMapKey: data},
+		}
+		_, err = c.clients.Core.ConfigMaps(operandNamespace).Create(context.TODO(), cm, metav1.CreateOptions{})
+	} else {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[debugCaptureConfigMapKey] = data
+		_, err = c.clients.Core.ConfigMaps(operandNamespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update ConfigMap %s/%s: %v", operandNamespace, name, err)
+	}
+	klog.V(2).Infof("TuneD daemon debug log uploaded to ConfigMap %s/%s", operandNamespace, name)
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package tuned
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+func TestDebugCapture(t *testing.T) {
+	var d debugCapture
+
+	d.add("not captured")
+	if _, changed := d.data(); changed {
+		t.Errorf("expected no line captured without a capture")
+	}
+
+	d.start(time.Now().Add(time.Hour))
+	d.add("first")
+	d.add("second")
+	if data, changed := d.data(); !changed || data != "first\nsecond\n" {
+		t.Errorf("expected the captured lines, got %q (changed %v)", data, changed)
+	}
+	if _, changed := d.data(); changed {
+		t.Errorf("expected no change since the last upload")
+	}
+
+	// The oldest lines are dropped once the capture exceeds its size limit.
+	line := strings.Repeat("x", 1023)
+	for i := 0; i < debugCaptureMaxSize/1024+10; i++ {
+		d.add(line)
+	}
+	data, _ := d.data()
+	if len(data) > debugCaptureMaxSize || strings.Contains(data, "first") {
+		t.Errorf("expected the capture trimmed to %d bytes, got %d bytes", debugCaptureMaxSize, len(data))
+	}
+
+	d.cancel()
+	if d.active(time.Now()) {
+		t.Errorf("expected the capture cancelled")
+	}
+	d.add("not captured")
+	if data, _ := d.data(); strings.Contains(data, "not captured") {
+		t.Errorf("expected no line captured after the capture was cancelled")
+	}
+}
+
+func TestLogLevelShows(t *testing.T) {
+	lines := map[string]string{
+		"debug":     "2024-01-01 00:00:00,000 DEBUG    tuned.plugins.base: verifying",
+		"info":      "2024-01-01 00:00:00,000 INFO     tuned.daemon.daemon: starting tuning",
+		"warning":   "2024-01-01 00:00:00,000 WARNING  tuned.plugins.plugin_sysctl: overriding",
+		"error":     "2024-01-01 00:00:00,000 ERROR    tuned.daemon.daemon: failed",
+		"traceback": "Traceback (most recent call last):",
+	}
+	tests := []struct {
+		level  tunedv1.TuneDLogLevel
+		hidden []string
+	}{
+		{level: ""},
+		{level: tunedv1.TuneDLogLevelDebug},
+		{level: tunedv1.TuneDLogLevelInfo},
+		{level: tunedv1.TuneDLogLevelWarning, hidden: []string{"debug", "info"}},
+		{level: tunedv1.TuneDLogLevelError, hidden: []string{"debug", "info", "warning"}},
+	}
+
+	for _, tc := range tests {
+		for name, line := range lines {
+			hidden := false
+			for _, h := range tc.hidden {
+				hidden = hidden || h == name
+			}
+			if shown := logLevelShows(tc.level, line); shown == hidden {
+				t.Errorf("log level %q: expected the %s line shown %v, got %v", tc.level, name, !hidden, shown)
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package tuned
+
+import (
+	"context"  // context.WithTimeout()
+	"errors"   // errors.Is()
+	"fmt"      // Fprintf()
+	"net"      // net.JoinHostPort()
+	"net/http" // http.Server
+	"strconv"  // strconv.Itoa()
+	"sync"     // sync.Mutex
+	"time"     // time.Now()
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// The operand serves the health endpoints on the loopback interface only, the pod runs in the host network namespace.
+	healthHost = "127.0.0.1"
+	healthPort = 60001
+	// The maximum time the TuneD daemon may take to apply a profile, after that it is considered wedged
+	// and the liveness probe fails so that the kubelet restarts the operand together with its TuneD child.
+	tunedReloadTimeout = time.Minute * time.Duration(5)
+)
+
+// operandHealth tracks the state of the TuneD daemon and the operand controller for the liveness and
+// readiness probes.  The controller goroutine updates it, the HTTP server goroutine reads it.
+type operandHealth struct {
+	lock sync.Mutex
+	// now returns the current time; overridden in the unit tests.
+	now func() time.Time
+	// running is true while the TuneD daemon process runs.
+	running bool
+	// controllerRunning is true while the operand controller watches the Tuned and Profile changes.
+	controllerRunning bool
+	// reloadStart is the time the TuneD daemon started the current (re)load; zero when not (re)loading.
+	reloadStart time.Time
+	// lastApplied is the time the TuneD daemon last applied a profile successfully.
+	lastApplied time.Time
+}
+
+func newOperandHealth() *operandHealth {
+	return &operandHealth{now: time.Now}
+}
+
+// reloadStarted records the start or the reload of the TuneD daemon.
+func (h *operandHealth) reloadStarted() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.running = true
+	h.reloadStart = h.now()
+}
+
+// reloadFinished records the end of the TuneD daemon (re)load and whether it applied the profile.
+func (h *operandHealth) reloadFinished(applied bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.reloadStart = time.Time{}
+	if applied {
+		h.lastApplied = h.now()
+	}
+}
+
+// daemonExited records the exit of the TuneD daemon process.
+func (h *operandHealth) daemonExited() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.running = false
+	h.reloadStart = time.Time{}
+}
+
+// controllerStarted records that the operand controller started watching the Tuned and Profile changes.
+func (h *operandHealth) controllerStarted() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.controllerRunning = true
+}
+
+// controllerStopped records that the operand controller stopped watching the Tuned and Profile changes.
+func (h *operandHealth) controllerStopped() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.controllerRunning = false
+}
+
+// live returns an error when the TuneD daemon is wedged applying a profile.
+func (h *operandHealth) live() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if !h.reloadStart.IsZero() {
+		if reloading := h.now().Sub(h.reloadStart); reloading > tunedReloadTimeout {
+			return fmt.Errorf("the TuneD daemon has been applying the profile for %v", reloading.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// ready returns an error unless both the TuneD daemon and the operand controller are alive.  The failures to
+// apply a profile do not make the operand unready, they are reported by the Profile Degraded condition.
+func (h *operandHealth) ready() error {
+	if err := h.live(); err != nil {
+		return err
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if !h.running {
+		return fmt.Errorf("the TuneD daemon is not running")
+	}
+	if !h.controllerRunning {
+		return fmt.Errorf("the operand controller is not running")
+	}
+	return nil
+}
+
+// lastAppliedTime returns the time the TuneD daemon last applied a profile successfully.
+func (h *operandHealth) lastAppliedTime() time.Time {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.lastApplied
+}
+
+// handler returns the HTTP handler serving the /healthz (liveness) and /readyz (readiness) endpoints
+// and the operand /metrics.
+func (h *operandHealth) handler() http.Handler {
+	probe := func(check func() error) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			if lastApplied := h.lastAppliedTime(); !lastApplied.IsZero() {
+				w.Header().Set("X-Last-Applied", lastApplied.UTC().Format(time.RFC3339))
+			}
+			if err := check(); err != nil {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprintf(w, "%v\n", err)
+				return
+			}
+			fmt.Fprintf(w, "ok\n")
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", probe(h.live))
+	mux.Handle("/readyz", probe(h.ready))
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	return mux
+}
+
+// runHealthServer serves the health endpoints until stopCh is closed.
+func (h *operandHealth) runHealthServer(stopCh <-chan struct{}) {
+	server := &http.Server{
+		Addr:              net.JoinHostPort(healthHost, strconv.Itoa(healthPort)),
+		Handler:           h.handler(),
+		ReadHeaderTimeout: time.Second * time.Duration(5),
+	}
+
+	go func() {
+		<-stopCh
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(5))
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			klog.Errorf("failed to shut down the health server: %v", err)
+		}
+	}()
+
+	klog.Infof("serving the health endpoints on %s", server.Addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		klog.Errorf("failed to serve the health endpoints: %v", err)
+	}
+}
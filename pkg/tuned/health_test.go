@@ -0,0 +1,63 @@
+package tuned
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOperandHealth(t *testing.T) {
+	now := time.Date(2023, time.October, 1, 12, 0, 0, 0, time.UTC)
+	h := newOperandHealth()
+	h.now = func() time.Time { return now }
+	handler := h.handler()
+
+	probe := func(path string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+	expect := func(step string, live, ready int) {
+		t.Helper()
+		if got := probe("/healthz"); got != live {
+			t.Errorf("%s: /healthz returned %d, expected %d", step, got, live)
+		}
+		if got := probe("/readyz"); got != ready {
+			t.Errorf("%s: /readyz returned %d, expected %d", step, got, ready)
+		}
+	}
+
+	expect("not started", http.StatusOK, http.StatusServiceUnavailable)
+
+	h.reloadStarted()
+	expect("TuneD started, controller not started", http.StatusOK, http.StatusServiceUnavailable)
+
+	h.controllerStarted()
+	expect("applying", http.StatusOK, http.StatusOK)
+
+	h.reloadFinished(true)
+	expect("applied", http.StatusOK, http.StatusOK)
+	if got := h.lastAppliedTime(); !got.Equal(now) {
+		t.Errorf("last applied time %v, expected %v", got, now)
+	}
+
+	// the failures to apply a profile are reported by the Profile Degraded condition only
+	h.reloadStarted()
+	h.reloadFinished(false)
+	expect("reload failed", http.StatusOK, http.StatusOK)
+	if got := h.lastAppliedTime(); !got.Equal(now) {
+		t.Errorf("last applied time %v, expected %v", got, now)
+	}
+
+	h.controllerStopped()
+	expect("controller stopped", http.StatusOK, http.StatusServiceUnavailable)
+	h.controllerStarted()
+
+	h.reloadStarted()
+	now = now.Add(tunedReloadTimeout + time.Second)
+	expect("wedged", http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+	h.daemonExited()
+	expect("exited", http.StatusOK, http.StatusServiceUnavailable)
+}
@@ -0,0 +1,229 @@
+package tuned
+
+import (
+	"fmt"           // Errorf()
+	"os"            // os.ReadFile(), ...
+	"path/filepath" // filepath.Join()
+	"strconv"       // strconv.Itoa()
+	"strings"       // strings.Join()
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+const (
+	// Reasons of the TunedHugepagesAllocated Profile status condition.
+	hugepagesAllocatedReason  = "AsExpected"
+	hugepagesFragmentedReason = "Fragmented"
+	hugepagesFailedReason     = "Failed"
+	// The kernel allocates fewer huge pages than requested once the free memory is too fragmented
+	// to find contiguous physical pages; compact the memory and try again that many times.
+	hugepagesAllocationAttempts = 3
+)
+
+// hugepagesRoot is the directory the sysfs and procfs huge pages paths are relative to.
+var hugepagesRoot = "/"
+
+// hugepagesSizeKilobytes returns the size of the huge pages 'size' in kilobytes.
+func hugepagesSizeKilobytes(size string) (int, error) {
+	switch size {
+	case "2M":
+		return 2048, nil
+	case "1G":
+		return 1048576, nil
+	}
+	return 0, fmt.Errorf("unsupported huge pages size %q", size)
+}
+
+// hugepagesNrFile returns the nr_hugepages sysfs file holding the number of huge pages
+// 'page' of its NUMA node, or of the whole system when the page has no NUMA node.
+func hugepagesNrFile(root string, page *tunedv1.RuntimeHugepages) (string, error) {
+	sizeKB, err := hugepagesSizeKilobytes(page.Size)
+	if err != nil {
+		return "", err
+	}
+
+	dir := fmt.Sprintf("hugepages-%dkB", sizeKB)
+	if page.Node == nil {
+		return filepath.Join(root, "sys/kernel/mm/hugepages", dir, "nr_hugepages"), nil
+	}
+	return filepath.Join(root, "sys/devices/system/node", fmt.Sprintf("node%d", *page.Node), "hugepages", dir, "nr_hugepages"), nil
+}
+
+// setHugepages writes 'count' to the nr_hugepages file 'file' and returns the number of
+// huge pages the kernel actually allocated.
+func setHugepages(file string, count int32) (int32, error) {
+	if err := os.WriteFile(file, []byte(strconv.Itoa(int(count))), 0644); err != nil {
+		return 0, err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	allocated, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %v", file, err)
+	}
+	return int32(allocated), nil
+}
+
+// hugepagesCount returns the number of huge pages 'page' requests, resolving the memory
+// percentage of the page against the total memory in /proc/meminfo.
+func hugepagesCount(root string, page *tunedv1.RuntimeHugepages) (int32, error) {
+	if page.MemoryPercent == nil {
+		return page.Count, nil
+	}
+
+	sizeKB, err := hugepagesSizeKilobytes(page.Size)
+	if err != nil {
+		return 0, err
+	}
+
+	file := filepath.Join(root, "proc/meminfo")
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		// MemTotal:       65536000 kB
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+		memTotalKB, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %s: %v", file, err)
+		}
+		return int32(memTotalKB * int64(*page.MemoryPercent) / 100 / int64(sizeKB)), nil
+	}
+	return 0, fmt.Errorf("failed to find the total memory in %s", file)
+}
+
+// compactMemory asks the kernel to compact the memory of all the zones, gathering
+// the free pages into contiguous blocks huge pages can be allocated from.
+func compactMemory(root string) error {
+	return os.WriteFile(filepath.Join(root, "proc/sys/vm/compact_memory"), []byte("1"), 0644)
+}
+
+func hugepagesDescription(page *tunedv1.RuntimeHugepages) string {
+	if page.Node == nil {
+		return fmt.Sprintf("%s huge pages", page.Size)
+	}
+	return fmt.Sprintf("%s huge pages on the NUMA node %d", page.Size, *page.Node)
+}
+
+// allocateHugepages releases the huge pages 'released' and allocates the huge pages 'pages'.
+// Returns the TunedHugepagesAllocated condition reporting the outcome of the allocation, or
+// nil when no huge pages are requested.
+func allocateHugepages(root string, pages, released []tunedv1.RuntimeHugepages) *tunedv1.ProfileStatusCondition {
+	for i := range released {
+		file, err := hugepagesNrFile(root, &released[i])
+		if err == nil {
+			_, err = setHugepages(file, 0)
+		}
+		if err != nil {
+			// Not fatal, the huge pages are merely kept allocated.
+			klog.Errorf("failed to release the %s: %v", hugepagesDescription(&released[i]), err)
+		}
+	}
+
+	if len(pages) == 0 {
+		return nil
+	}
+
+	condition := &tunedv1.ProfileStatusCondition{
+		Type: tunedv1.TunedHugepagesAllocated,
+	}
+
+	var short []string
+	for attempt := 1; ; attempt++ {
+		short = nil
+		for i := range pages {
+			page := &pages[i]
+			file, err := hugepagesNrFile(root, page)
+			var count, allocated int32
+			if err == nil {
+				count, err = hugepagesCount(root, page)
+			}
+			if err == nil {
+				allocated, err = setHugepages(file, count)
+			}
+			if err != nil {
+				condition.Status = corev1.ConditionFalse
+				condition.Reason = hugepagesFailedReason
+				condition.Message = fmt.Sprintf("Failed to allocate the %s: %v", hugepagesDescription(page), err)
+				return condition
+			}
+			if allocated < count {
+				short = append(short, fmt.Sprintf("%d of %d %s", allocated, count, hugepagesDescription(page)))
+			}
+		}
+
+		if len(short) == 0 || attempt == hugepagesAllocationAttempts {
+			break
+		}
+		klog.Infof("compacting memory to allocate %s", strings.Join(short, ", "))
+		if err := compactMemory(root); err != nil {
+			klog.Errorf("failed to compact memory: %v", err)
+		}
+	}
+
+	if len(short) > 0 {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = hugepagesFragmentedReason
+		condition.Message = "The memory is too fragmented, allocated only " + strings.Join(short, ", ") + "."
+		return condition
+	}
+
+	condition.Status = corev1.ConditionTrue
+	condition.Reason = hugepagesAllocatedReason
+	condition.Message = "The huge pages requested at runtime are allocated."
+	return condition
+}
+
+// releasedHugepages returns the huge pages of 'oldPages' that 'newPages' no longer
+// requests, with a zero count.
+func releasedHugepages(oldPages, newPages []tunedv1.RuntimeHugepages) []tunedv1.RuntimeHugepages {
+	key := func(page *tunedv1.RuntimeHugepages) string {
+		if page.Node == nil {
+			return page.Size
+		}
+		return fmt.Sprintf("%s/%d", page.Size, *page.Node)
+	}
+
+	requested := map[string]bool{}
+	for i := range newPages {
+		requested[key(&newPages[i])] = true
+	}
+
+	var released []tunedv1.RuntimeHugepages
+	for i := range oldPages {
+		if requested[key(&oldPages[i])] {
+			continue
+		}
+		page := oldPages[i]
+		page.Count = 0
+		released = append(released, page)
+	}
+	return released
+}
+
+// setHugepagesStatusCondition sets the TunedHugepagesAllocated condition 'condition' in the
+// given slice of conditions, or removes it when 'condition' is nil.
+func setHugepagesStatusCondition(conditions []tunedv1.ProfileStatusCondition, condition *tunedv1.ProfileStatusCondition) []tunedv1.ProfileStatusCondition {
+	if condition != nil {
+		c := *condition
+		return setStatusCondition(conditions, &c)
+	}
+
+	newConditions := []tunedv1.ProfileStatusCondition{}
+	for _, c := range conditions {
+		if c.Type != tunedv1.TunedHugepagesAllocated {
+			newConditions = append(newConditions, c)
+		}
+	}
+	return newConditions
+}
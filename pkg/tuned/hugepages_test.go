@@ -0,0 +1,131 @@
+package tuned
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+func TestAllocateHugepages(t *testing.T) {
+	root := t.TempDir()
+	nodeFile := filepath.Join(root, "sys/devices/system/node/node1/hugepages/hugepages-2048kB/nr_hugepages")
+	globalFile := filepath.Join(root, "sys/kernel/mm/hugepages/hugepages-2048kB/nr_hugepages")
+	for _, file := range []string{nodeFile, globalFile} {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("16\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if condition := allocateHugepages(root, nil, nil); condition != nil {
+		t.Errorf("expected no condition without requested huge pages, got %+v", condition)
+	}
+
+	pages := []tunedv1.RuntimeHugepages{
+		{Size: "2M", Count: 128, Node: pointer.Int32(1)},
+	}
+	released := []tunedv1.RuntimeHugepages{
+		{Size: "2M", Count: 0},
+	}
+	condition := allocateHugepages(root, pages, released)
+	if condition == nil || condition.Status != corev1.ConditionTrue || condition.Reason != hugepagesAllocatedReason {
+		t.Fatalf("expected the huge pages to be allocated, got %+v", condition)
+	}
+	for file, expected := range map[string]string{nodeFile: "128", globalFile: "0"} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expected {
+			t.Errorf("expected %s to hold %s huge pages, got %s", file, expected, data)
+		}
+	}
+
+	pages = append(pages, tunedv1.RuntimeHugepages{Size: "2M", Count: 64, Node: pointer.Int32(3)})
+	condition = allocateHugepages(root, pages, nil)
+	if condition == nil || condition.Status != corev1.ConditionFalse || condition.Reason != hugepagesFailedReason {
+		t.Errorf("expected the allocation on a missing NUMA node to fail, got %+v", condition)
+	}
+}
+
+func TestHugepagesCount(t *testing.T) {
+	root := t.TempDir()
+	page := &tunedv1.RuntimeHugepages{Size: "2M", MemoryPercent: pointer.Int32(10)}
+
+	if _, err := hugepagesCount(root, page); err == nil {
+		t.Errorf("expected the count to fail without /proc/meminfo")
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, "proc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	meminfo := "MemTotal:       16777216 kB\nMemFree:         8388608 kB\n"
+	if err := os.WriteFile(filepath.Join(root, "proc/meminfo"), []byte(meminfo), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := hugepagesCount(root, page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 10% of 16Gi in 2M pages
+	if count != 819 {
+		t.Errorf("expected 819 huge pages, got %d", count)
+	}
+
+	count, err = hugepagesCount(root, &tunedv1.RuntimeHugepages{Size: "2M", Count: 128})
+	if err != nil || count != 128 {
+		t.Errorf("expected the count of the page, got %d: %v", count, err)
+	}
+}
+
+func TestReleasedHugepages(t *testing.T) {
+	oldPages := []tunedv1.RuntimeHugepages{
+		{Size: "2M", Count: 128, Node: pointer.Int32(0)},
+		{Size: "2M", Count: 128, Node: pointer.Int32(1)},
+		{Size: "2M", Count: 256},
+	}
+	newPages := []tunedv1.RuntimeHugepages{
+		{Size: "2M", Count: 64, Node: pointer.Int32(1)},
+	}
+
+	expected := []tunedv1.RuntimeHugepages{
+		{Size: "2M", Count: 0, Node: pointer.Int32(0)},
+		{Size: "2M", Count: 0},
+	}
+	if got := releasedHugepages(oldPages, newPages); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the released huge pages %+v, got %+v", expected, got)
+	}
+}
+
+func TestSetHugepagesStatusCondition(t *testing.T) {
+	conditions := InitializeStatusConditions()
+	condition := &tunedv1.ProfileStatusCondition{
+		Type:   tunedv1.TunedHugepagesAllocated,
+		Status: corev1.ConditionFalse,
+		Reason: hugepagesFragmentedReason,
+	}
+
+	conditions = setHugepagesStatusCondition(conditions, condition)
+	if len(conditions) != 3 {
+		t.Fatalf("expected the huge pages condition to be added, got %+v", conditions)
+	}
+
+	conditions = setHugepagesStatusCondition(conditions, nil)
+	if len(conditions) != 2 {
+		t.Fatalf("expected the huge pages condition to be removed, got %+v", conditions)
+	}
+	for _, c := range conditions {
+		if c.Type == tunedv1.TunedHugepagesAllocated {
+			t.Errorf("expected the huge pages condition to be removed, got %+v", conditions)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package tuned
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
+)
+
+const (
+	// The kubelet configuration file the MCO renders the KubeletConfigs of the pool into.
+	kubeletConfigFile = "/etc/kubernetes/kubelet.conf"
+)
+
+// getKubeletConfigChecksums returns the JSON map of the checksums of the kubelet configuration
+// values the PerformanceProfile sets, read from the kubelet configuration file 'path'.
+func getKubeletConfigChecksums(path string) (string, error) {
+	config, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the kubelet configuration file %s: %v", path, err)
+	}
+
+	checksums, err := util.KubeletConfigChecksums(config)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(checksums)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
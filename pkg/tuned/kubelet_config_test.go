@@ -0,0 +1,31 @@
+package tuned
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetKubeletConfigChecksums(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubelet.conf")
+	if _, err := getKubeletConfigChecksums(path); err == nil {
+		t.Errorf("expected an error reading a missing kubelet configuration file")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"cpuManagerPolicy":"static","evictionHard":{"memory.available":"100Mi"},"maxPods":250}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	value, err := getKubeletConfigChecksums(path)
+	if err != nil {
+		t.Fatalf("failed to get the kubelet configuration checksums: %v", err)
+	}
+
+	checksums := map[string]string{}
+	if err := json.Unmarshal([]byte(value), &checksums); err != nil {
+		t.Fatalf("failed to parse the kubelet configuration checksums %q: %v", value, err)
+	}
+	if len(checksums) != 2 || checksums["cpuManagerPolicy"] == "" || checksums["evictionHard.memory.available"] == "" {
+		t.Errorf("expected the checksums of cpuManagerPolicy and evictionHard.memory.available, got %v", checksums)
+	}
+}
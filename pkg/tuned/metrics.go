@@ -0,0 +1,37 @@
+package tuned
+
+import (
+	"strconv" // strconv.FormatBool()
+	"time"    // time.Duration
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// When adding metric names, see https://prometheus.io/docs/practices/naming/#metric-names
+const (
+	startupDurationQuery = "nto_tuned_startup_duration_seconds"
+)
+
+var (
+	// The operand metrics are served next to the health endpoints, see operandHealth.handler().
+	registry        = prometheus.NewRegistry()
+	startupDuration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: startupDurationQuery,
+			Help: "The time from the operand start until the TuneD daemon first applied a profile, by whether the TuneD daemon was warm-started with the cached profiles.",
+		},
+		[]string{"warm_start"},
+	)
+)
+
+func init() {
+	registry.MustRegister(
+		startupDuration,
+		newCPUNoiseCollector(cpuNoiseRoot),
+	)
+}
+
+// startupFinished records the time it took the TuneD daemon to apply the first profile since the operand start.
+func startupFinished(duration time.Duration, warmStart bool) {
+	startupDuration.WithLabelValues(strconv.FormatBool(warmStart)).Set(duration.Seconds())
+}
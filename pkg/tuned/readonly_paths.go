@@ -0,0 +1,167 @@
+package tuned
+
+import (
+	"fmt"           // Sprintf()
+	"path/filepath" // filepath.Join()
+	"sort"          // sort.Strings()
+	"strings"       // strings.Join()
+	"syscall"       // syscall.Statfs()
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+const (
+	// ProfilePathsReadOnlyReason is the TunedPathsWritable condition reason reported while
+	// the TuneD profiles write files under read-only locations, e.g. the image mode /usr.
+	ProfilePathsReadOnlyReason = "ReadOnlyPaths"
+	// stRdOnly is the ST_RDONLY flag of the statfs(2) mount flags.
+	stRdOnly = 0x1
+	// hostRoot is the directory the host root file system is mounted at.
+	hostRoot = "/host"
+)
+
+// etcOverlays maps the vendor configuration directories of the read-only /usr to the /etc
+// directories overriding them; systemd and its tools look the files up in both.
+var etcOverlays = map[string]string{
+	"/usr/lib/modprobe.d/":      "/etc/modprobe.d/",
+	"/usr/lib/sysctl.d/":        "/etc/sysctl.d/",
+	"/usr/lib/systemd/system/":  "/etc/systemd/system/",
+	"/usr/lib/tmpfiles.d/":      "/etc/tmpfiles.d/",
+	"/usr/lib/udev/rules.d/":    "/etc/udev/rules.d/",
+	"/usr/lib/modules-load.d/":  "/etc/modules-load.d/",
+	"/usr/lib/systemd/network/": "/etc/systemd/network/",
+}
+
+// pathReadOnly returns true if the path 'path' is on a read-only file system either in
+// the container or on the host.  Paths not existing yet are checked by the nearest
+// existing parent directory.
+var pathReadOnly = func(path string) bool {
+	for _, p := range []string{path, filepath.Join(hostRoot, path)} {
+		for {
+			var st syscall.Statfs_t
+			err := syscall.Statfs(p, &st)
+			if err == nil {
+				if st.Flags&stRdOnly != 0 {
+					return true
+				}
+				break
+			}
+			if p == "/" || p == hostRoot {
+				break
+			}
+			p = filepath.Dir(p)
+		}
+	}
+	return false
+}
+
+// readOnlyPathsEnforce returns the TuneD profiles 'profiles' with the sysfs plug-in lines
+// writing under read-only locations redirected to the writable /etc overlays, or dropped
+// when no such overlay exists, and the dropped paths by the TuneD profile name.
+func readOnlyPathsEnforce(profiles []tunedv1.TunedProfile) ([]tunedv1.TunedProfile, map[string][]string) {
+	pathsDropped := map[string][]string{}
+	enforced := make([]tunedv1.TunedProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		if profile.Name == nil || profile.Data == nil {
+			enforced = append(enforced, profile)
+			continue
+		}
+		data, dropped := filterReadOnlyPaths(*profile.Name, *profile.Data)
+		if data != *profile.Data {
+			profile = *profile.DeepCopy()
+			profile.Data = &data
+		}
+		if len(dropped) > 0 {
+			pathsDropped[*profile.Name] = dropped
+		}
+		enforced = append(enforced, profile)
+	}
+
+	return enforced, pathsDropped
+}
+
+// filterReadOnlyPaths returns the TuneD profile data 'data' with the sysfs plug-in lines
+// writing under read-only locations redirected or dropped, and the sorted dropped paths.
+func filterReadOnlyPaths(profileName, data string) (string, []string) {
+	lines := strings.Split(data, "\n")
+	sysfsSections := tunedv1.ProfilePluginSections(data, "sysfs")
+	kept := make([]string, 0, len(lines))
+	dropped := []string{}
+	section := ""
+	for _, line := range lines {
+		if name, ok := tunedv1.ProfileSection(line); ok {
+			section = name
+		}
+		path, value, ok := profilePathKey(line)
+		if !ok || !sysfsSections[section] || !pathReadOnly(staticDir(path)) {
+			kept = append(kept, line)
+			continue
+		}
+		if overlay, ok := etcOverlay(path); ok && !pathReadOnly(overlay) {
+			klog.Infof("TuneD profile %s writes read-only %s, writing %s instead", profileName, path, overlay)
+			kept = append(kept, overlay+"="+value)
+			continue
+		}
+		dropped = append(dropped, path)
+	}
+	if len(dropped) == 0 {
+		return strings.Join(kept, "\n"), nil
+	}
+	sort.Strings(dropped)
+
+	return strings.Join(kept, "\n"), dropped
+}
+
+// etcOverlay returns the path overriding the path 'path' of a vendor configuration directory.
+func etcOverlay(path string) (string, bool) {
+	for dir, overlay := range etcOverlays {
+		if strings.HasPrefix(path, dir) && !strings.ContainsAny(path[len(dir):], "*?[") {
+			return overlay + path[len(dir):], true
+		}
+	}
+	return "", false
+}
+
+// profilePathKey returns the absolute path key and the raw value of the TuneD profile line 'line'.
+// Paths built from TuneD variables are not resolved and never considered read-only.
+func profilePathKey(line string) (string, string, bool) {
+	key, _, ok := tunedv1.ProfileKeyValue(line)
+	if !ok || !strings.HasPrefix(key, "/") || strings.Contains(key, "$") {
+		return "", "", false
+	}
+	_, raw, _ := strings.Cut(line, "=")
+	return filepath.Clean(key), raw, true
+}
+
+// staticDir returns the path 'path' up to the directory containing its first glob pattern.
+func staticDir(path string) string {
+	if i := strings.IndexAny(path, "*?["); i >= 0 {
+		return filepath.Dir(path[:i] + "x")
+	}
+	return path
+}
+
+// setPathsWritableStatusCondition returns 'conditions' with the TunedPathsWritable condition
+// reporting the read-only paths 'dropped', or without the condition when no path was dropped.
+func setPathsWritableStatusCondition(conditions []tunedv1.ProfileStatusCondition, dropped []string) []tunedv1.ProfileStatusCondition {
+	if len(dropped) > 0 {
+		return setStatusCondition(conditions, &tunedv1.ProfileStatusCondition{
+			Type:   tunedv1.TunedPathsWritable,
+			Status: corev1.ConditionFalse,
+			Reason: ProfilePathsReadOnlyReason,
+			Message: fmt.Sprintf("The TuneD profile writes the read-only path(s) %s with no writable /etc overlay, the line(s) were not applied.",
+				strings.Join(dropped, ", ")),
+		})
+	}
+
+	newConditions := []tunedv1.ProfileStatusCondition{}
+	for _, c := range conditions {
+		if c.Type != tunedv1.TunedPathsWritable {
+			newConditions = append(newConditions, c)
+		}
+	}
+	return newConditions
+}
@@ -0,0 +1,75 @@
+package tuned
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+func TestReadOnlyPathsEnforce(t *testing.T) {
+	defer func(f func(string) bool) { pathReadOnly = f }(pathReadOnly)
+	pathReadOnly = func(path string) bool {
+		return strings.HasPrefix(path, "/usr/") || strings.HasPrefix(path, "/etc/sysctl.d/")
+	}
+
+	profile := func(name, data string) tunedv1.TunedProfile {
+		return tunedv1.TunedProfile{Name: &name, Data: &data}
+	}
+	profiles := []tunedv1.TunedProfile{
+		profile("writable", "[main]\nsummary=writable\n[sysfs]\n/sys/kernel/mm/ksm/run=0\n"),
+		profile("overlay", "[sysfs]\n/usr/lib/modprobe.d/tuned.conf=options foo bar=1\n/usr/lib/sysctl.d/99-tuned.conf=vm.swappiness=10\n"),
+		profile("custom", "[files]\ntype=sysfs\n/usr/share/tuned/state=1\n/usr/share/*/state=1\n# /usr/share/commented=1\n${f:exec:/usr/bin/foo}=1\n[sysctl]\n/usr/not/a/path=1\n"),
+	}
+
+	enforced, dropped := readOnlyPathsEnforce(profiles)
+
+	expectedData := []string{
+		*profiles[0].Data,
+		"[sysfs]\n/etc/modprobe.d/tuned.conf=options foo bar=1\n",
+		"[files]\ntype=sysfs\n# /usr/share/commented=1\n${f:exec:/usr/bin/foo}=1\n[sysctl]\n/usr/not/a/path=1\n",
+	}
+	for i, p := range enforced {
+		if *p.Data != expectedData[i] {
+			t.Errorf("expected TuneD profile %s data %q, got %q", *p.Name, expectedData[i], *p.Data)
+		}
+	}
+	expectedDropped := map[string][]string{
+		"overlay": {"/usr/lib/sysctl.d/99-tuned.conf"},
+		"custom":  {"/usr/share/*/state", "/usr/share/tuned/state"},
+	}
+	if !reflect.DeepEqual(dropped, expectedDropped) {
+		t.Errorf("expected the dropped paths %v, got %v", expectedDropped, dropped)
+	}
+	if *profiles[1].Data == *enforced[1].Data {
+		t.Errorf("expected the TuneD profiles not to be modified in place")
+	}
+}
+
+func TestPathsWritableStatusCondition(t *testing.T) {
+	conditions := InitializeStatusConditions()
+
+	conditions = setPathsWritableStatusCondition(conditions, []string{"/usr/share/tuned/state"})
+	found := false
+	for _, c := range conditions {
+		if c.Type == tunedv1.TunedPathsWritable {
+			found = true
+			if c.Status != corev1.ConditionFalse || c.Reason != ProfilePathsReadOnlyReason || !strings.Contains(c.Message, "/usr/share/tuned/state") {
+				t.Errorf("unexpected condition %+v", c)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected the paths writable condition to be added, got %+v", conditions)
+	}
+
+	conditions = setPathsWritableStatusCondition(conditions, nil)
+	for _, c := range conditions {
+		if c.Type == tunedv1.TunedPathsWritable {
+			t.Errorf("expected the paths writable condition to be removed, got %+v", conditions)
+		}
+	}
+}
@@ -97,7 +97,10 @@ func TunedRun(cmd *exec.Cmd, daemon *Daemon, onDaemonReload func()) error {
 		for scanner.Scan() {
 			l := scanner.Text()
 
-			fmt.Printf("%s\n", l)
+			if logLevelShows(daemon.logLevel, l) {
+				fmt.Printf("%s\n", l)
+			}
+			daemon.capture.add(l)
 
 			if daemon.stopping {
 				// We have decided to stop TuneD.  Apart from showing the logs it is
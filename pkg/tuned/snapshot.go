@@ -0,0 +1,241 @@
+package tuned
+
+import (
+	"archive/tar"   // tar.NewWriter()
+	"bytes"         // bytes.Buffer
+	"compress/gzip" // gzip.NewWriter()
+	"fmt"           // Fprintf()
+	"io"            // io.Writer
+	"io/fs"         // fs.DirEntry
+	"os"            // os.ReadFile(), ...
+	"path/filepath" // filepath.Join()
+	"sort"          // sort.Slice()
+	"strings"       // strings.TrimSpace()
+	"time"          // time.Now()
+)
+
+const (
+	// The log file of the TuneD daemon.
+	tunedLogFile = "/var/log/tuned/tuned.log"
+
+	snapshotSysctlsFile      = "sysctls.txt"
+	snapshotIRQAffinityFile  = "irq-affinity.txt"
+	snapshotCpusetFile       = "cpuset.txt"
+	snapshotErrorsFile       = "errors.txt"
+	snapshotProcSysDir       = "/proc/sys"
+	snapshotProcIRQDir       = "/proc/irq"
+	snapshotCgroupDir        = "/sys/fs/cgroup"
+	snapshotIRQAffinityEntry = "smp_affinity_list"
+)
+
+// snapshotFiles are the files copied as they are to the node tuning snapshot.
+var snapshotFiles = []string{
+	openshiftTunedStateFile,
+	tunedLogFile,
+	"/proc/cmdline",
+	snapshotProcIRQDir + "/default_smp_affinity",
+}
+
+// snapshotCpusetFiles are the cpuset controller files of the cgroup tree in the node tuning snapshot.
+var snapshotCpusetFiles = map[string]bool{
+	"cpuset.cpus":           true,
+	"cpuset.cpus.effective": true,
+	"cpuset.cpus.exclusive": true,
+	"cpuset.cpus.partition": true,
+	"cpuset.mems":           true,
+	"cpuset.mems.effective": true,
+}
+
+// snapshotWriter writes the node tuning snapshot tarball entries out of the files under 'root'.
+type snapshotWriter struct {
+	root    string
+	tw      *tar.Writer
+	modTime time.Time
+	// errors are the failures to collect a part of the snapshot, the snapshot is best effort.
+	errors []string
+}
+
+// WriteSnapshot writes the gzip-compressed tarball with the node tuning snapshot to 'w':
+// the TuneD daemon log, the TuneD profiles and the TuneD recommended and active profile,
+// the applied sysctls, the cpuset cgroup tree, the IRQ affinities and the kernel command line.
+func WriteSnapshot(w io.Writer) error {
+	return writeSnapshot(w, "/")
+}
+
+func writeSnapshot(w io.Writer, root string) error {
+	gw := gzip.NewWriter(w)
+	sw := &snapshotWriter{
+		root:    root,
+		tw:      tar.NewWriter(gw),
+		modTime: time.Now(),
+	}
+
+	for _, file := range snapshotFiles {
+		sw.addFile(file)
+	}
+	sw.addProfiles()
+	sw.addSysctls()
+	sw.addIRQAffinities()
+	sw.addCpusets()
+
+	if len(sw.errors) > 0 {
+		if err := sw.addEntry(snapshotErrorsFile, []byte(strings.Join(sw.errors, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+	if err := sw.tw.Close(); err != nil {
+		return fmt.Errorf("failed to write the node tuning snapshot: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		return fmt.Errorf("failed to compress the node tuning snapshot: %v", err)
+	}
+
+	return nil
+}
+
+// addEntry adds the tarball entry 'name' with the content 'data'.
+func (sw *snapshotWriter) addEntry(name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    strings.TrimPrefix(name, "/"),
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: sw.modTime,
+	}
+	if err := sw.tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write the node tuning snapshot entry %s: %v", hdr.Name, err)
+	}
+	if _, err := sw.tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write the node tuning snapshot entry %s: %v", hdr.Name, err)
+	}
+
+	return nil
+}
+
+// addFile adds the file 'path' to the snapshot under its own path.
+func (sw *snapshotWriter) addFile(path string) {
+	data, err := os.ReadFile(filepath.Join(sw.root, path))
+	if err != nil {
+		sw.errorf("failed to read %s: %v", path, err)
+		return
+	}
+	if err := sw.addEntry(path, data); err != nil {
+		sw.errorf("%v", err)
+	}
+}
+
+// addProfiles adds the TuneD daemon configuration directory to the snapshot: the custom
+// TuneD profiles, the recommended and the active TuneD profile and the kernel command-line
+// parameters calculated by the TuneD daemon.
+func (sw *snapshotWriter) addProfiles() {
+	sw.walk(tunedProfilesDirCustom, func(path string, d fs.DirEntry) {
+		if d.Type().IsRegular() {
+			sw.addFile(path)
+		}
+	})
+}
+
+// addSysctls adds the "name = value" list of the sysctls applied on the node, the way
+// "sysctl -a" prints them, to the snapshot.
+func (sw *snapshotWriter) addSysctls() {
+	var b bytes.Buffer
+	sw.walk(snapshotProcSysDir, func(path string, d fs.DirEntry) {
+		if !d.Type().IsRegular() {
+			return
+		}
+		info, err := d.Info()
+		if err != nil || info.Mode().Perm()&0444 == 0 {
+			// Skip the write-only sysctls, e.g. vm.drop_caches.
+			return
+		}
+		value, err := os.ReadFile(filepath.Join(sw.root, path))
+		if err != nil {
+			// Some sysctls can not be read even by root, "sysctl -a" skips them too.
+			return
+		}
+		name := strings.ReplaceAll(strings.TrimPrefix(path, snapshotProcSysDir+"/"), "/", ".")
+		fmt.Fprintf(&b, "%s = %s\n", name, strings.ReplaceAll(strings.TrimSpace(string(value)), "\n", " "))
+	})
+	sw.addGenerated(snapshotSysctlsFile, b.Bytes())
+}
+
+// addIRQAffinities adds the "irq: CPUs" list of the IRQ affinities to the snapshot.
+func (sw *snapshotWriter) addIRQAffinities() {
+	entries, err := os.ReadDir(filepath.Join(sw.root, snapshotProcIRQDir))
+	if err != nil {
+		sw.errorf("failed to read %s: %v", snapshotProcIRQDir, err)
+		return
+	}
+
+	irqs := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			irqs = append(irqs, entry.Name())
+		}
+	}
+	// Sort the IRQs numerically, the directory entries are sorted by their names.
+	sort.Slice(irqs, func(i, j int) bool {
+		if len(irqs[i]) != len(irqs[j]) {
+			return len(irqs[i]) < len(irqs[j])
+		}
+		return irqs[i] < irqs[j]
+	})
+
+	var b bytes.Buffer
+	for _, irq := range irqs {
+		affinity, err := os.ReadFile(filepath.Join(sw.root, snapshotProcIRQDir, irq, snapshotIRQAffinityEntry))
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s\n", irq, strings.TrimSpace(string(affinity)))
+	}
+	sw.addGenerated(snapshotIRQAffinityFile, b.Bytes())
+}
+
+// addCpusets adds the "path: value" list of the cpuset controller files in the cgroup tree to the snapshot.
+func (sw *snapshotWriter) addCpusets() {
+	var b bytes.Buffer
+	sw.walk(snapshotCgroupDir, func(path string, d fs.DirEntry) {
+		if d.IsDir() || !snapshotCpusetFiles[d.Name()] {
+			return
+		}
+		value, err := os.ReadFile(filepath.Join(sw.root, path))
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(&b, "%s: %s\n", path, strings.TrimSpace(string(value)))
+	})
+	sw.addGenerated(snapshotCpusetFile, b.Bytes())
+}
+
+// addGenerated adds the entry 'name' generated out of the node files to the snapshot.
+func (sw *snapshotWriter) addGenerated(name string, data []byte) {
+	if err := sw.addEntry(name, data); err != nil {
+		sw.errorf("%v", err)
+	}
+}
+
+// walk calls 'fn' with the paths relative to the snapshot root of the files in the tree 'dir'.
+func (sw *snapshotWriter) walk(dir string, fn func(path string, d fs.DirEntry)) {
+	walkErr := filepath.WalkDir(filepath.Join(sw.root, dir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				// Skip the unreadable subtrees.
+				return fs.SkipDir
+			}
+			return err
+		}
+		rel, err := filepath.Rel(sw.root, path)
+		if err != nil {
+			return err
+		}
+		fn("/"+rel, d)
+		return nil
+	})
+	if walkErr != nil {
+		sw.errorf("failed to walk %s: %v", dir, walkErr)
+	}
+}
+
+func (sw *snapshotWriter) errorf(format string, args ...interface{}) {
+	sw.errors = append(sw.errors, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,86 @@
+package tuned
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteSnapshot(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		tunedActiveProfileFile:                                 "openshift-node\n",
+		tunedProfilesDirCustom + "/openshift-node/tuned.conf":  "[main]\nsummary=node\n",
+		"/proc/cmdline":                                        "BOOT_IMAGE=/vmlinuz isolcpus=2-3\n",
+		"/proc/irq/default_smp_affinity":                       "3\n",
+		"/proc/irq/2/smp_affinity_list":                        "0-1\n",
+		"/proc/irq/10/smp_affinity_list":                       "0\n",
+		"/proc/sys/kernel/sched_rt_runtime_us":                 "-1\n",
+		"/proc/sys/vm/stat_interval":                           "10\n",
+		"/sys/fs/cgroup/kubepods.slice/cpuset.cpus.effective":  "0-3\n",
+		"/sys/fs/cgroup/kubepods.slice/cpu.max":                "max 100000\n",
+		"/sys/fs/cgroup/system.slice/cpuset.cpus":              "0-1\n",
+		"/sys/fs/cgroup/system.slice/crio.service/cpuset.mems": "0\n",
+	}
+	for path, data := range files {
+		fullPath := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Write-only sysctls are skipped.
+	if err := os.WriteFile(filepath.Join(root, "/proc/sys/vm/drop_caches"), []byte{}, 0200); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := writeSnapshot(&b, root); err != nil {
+		t.Fatalf("failed to write the snapshot: %v", err)
+	}
+
+	entries := map[string]string{}
+	gr, err := gzip.NewReader(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[hdr.Name] = string(data)
+	}
+
+	expected := map[string]string{
+		"etc/tuned/active_profile":            "openshift-node\n",
+		"etc/tuned/openshift-node/tuned.conf": "[main]\nsummary=node\n",
+		"proc/cmdline":                        "BOOT_IMAGE=/vmlinuz isolcpus=2-3\n",
+		"proc/irq/default_smp_affinity":       "3\n",
+		snapshotSysctlsFile:                   "kernel.sched_rt_runtime_us = -1\nvm.stat_interval = 10\n",
+		snapshotIRQAffinityFile:               "2: 0-1\n10: 0\n",
+		snapshotCpusetFile: "/sys/fs/cgroup/kubepods.slice/cpuset.cpus.effective: 0-3\n" +
+			"/sys/fs/cgroup/system.slice/cpuset.cpus: 0-1\n" +
+			"/sys/fs/cgroup/system.slice/crio.service/cpuset.mems: 0\n",
+		snapshotErrorsFile: "failed to read " + openshiftTunedStateFile + ": open " + filepath.Join(root, openshiftTunedStateFile) + ": no such file or directory\n" +
+			"failed to read " + tunedLogFile + ": open " + filepath.Join(root, tunedLogFile) + ": no such file or directory\n",
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("expected the snapshot entries %v, got %v", expected, entries)
+	}
+}
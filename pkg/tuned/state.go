@@ -0,0 +1,170 @@
+package tuned
+
+import (
+	"crypto/sha256" // sha256.New()
+	"encoding/hex"  // hex.EncodeToString()
+	"encoding/json" // json.MarshalIndent()
+	"fmt"           // Errorf()
+	"io"            // io.Writer
+	"os"            // os.ReadFile(), ...
+	"path/filepath" // filepath.Dir()
+	"sort"          // sort.Strings()
+	"strings"       // strings.Fields()
+	"time"          // time.Now()
+
+	corev1 "k8s.io/api/core/v1"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/util"
+)
+
+const (
+	// The node tuning state file consumed by node-level agents which have no API access.
+	openshiftTunedStateFile = openshiftTunedRunDir + "/state.json"
+	// Bump the version on incompatible changes of the nodeTuningState format.
+	nodeTuningStateVersion = 1
+)
+
+// nodeTuningState is the machine-readable node tuning state exported to openshiftTunedStateFile.
+type nodeTuningState struct {
+	// Version of the state format.
+	Version int `json:"version"`
+	// Profile is the TuneD profile applied on the node.
+	Profile string `json:"profile"`
+	// Checksum is the SHA-256 checksum of the custom TuneD profiles the applied profile consists of.
+	Checksum string `json:"checksum"`
+	// Bootcmdline holds the kernel command-line parameters calculated by the TuneD daemon.
+	Bootcmdline string `json:"bootcmdline"`
+	// DegradedReasons lists the reasons of the degraded Profile status conditions.
+	DegradedReasons []string `json:"degradedReasons,omitempty"`
+	// Timestamp is the time the state was written.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// newNodeTuningState returns the node tuning state of the applied TuneD profile 'activeProfile'
+// and the Profile status conditions 'conditions'.
+func newNodeTuningState(activeProfile, bootcmdline string, conditions []tunedv1.ProfileStatusCondition) *nodeTuningState {
+	state := &nodeTuningState{
+		Version:     nodeTuningStateVersion,
+		Profile:     activeProfile,
+		Checksum:    profilesChecksum(appliedProfiles(activeProfile), tunedProfilesDirCustom),
+		Bootcmdline: bootcmdline,
+		Timestamp:   time.Now().UTC(),
+	}
+
+	for _, condition := range conditions {
+		if condition.Type == tunedv1.TunedDegraded && condition.Status == corev1.ConditionTrue {
+			state.DegradedReasons = append(state.DegradedReasons, condition.Reason)
+		}
+	}
+
+	return state
+}
+
+// appliedProfiles returns the sorted names of the TuneD profiles the space-separated
+// list of TuneD profiles 'activeProfile' consists of, including the profiles they depend on.
+func appliedProfiles(activeProfile string) []string {
+	profiles := map[string]bool{}
+	for _, profileName := range strings.Fields(activeProfile) {
+		for dep := range profileDepends(profileName) {
+			profiles[dep] = true
+		}
+		profiles[profileName] = true
+	}
+
+	names := make([]string, 0, len(profiles))
+	for profileName := range profiles {
+		names = append(names, profileName)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// profilesChecksum returns the SHA-256 checksum of the TuneD profiles 'profileNames'
+// found in 'tunedProfilesDir'.  Profiles missing from 'tunedProfilesDir', such as the
+// ones shipped with the TuneD daemon, are not part of the checksum.
+func profilesChecksum(profileNames []string, tunedProfilesDir string) string {
+	h := sha256.New()
+	for _, profileName := range profileNames {
+		content, err := os.ReadFile(fmt.Sprintf("%s/%s/%s", tunedProfilesDir, profileName, tunedConfFile))
+		if err != nil {
+			continue
+		}
+		writeProfileChecksum(h, profileName, content)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeProfileChecksum adds the TuneD profile 'profileName' with 'content' to the profiles checksum 'h'.
+func writeProfileChecksum(h io.Writer, profileName string, content []byte) {
+	fmt.Fprintf(h, "%s\n%d\n", profileName, len(content))
+	h.Write(content)
+}
+
+// profileHashes returns the SHA-256 checksums of the data of the TuneD profiles 'profiles'
+// by the TuneD profile name, or nil if there are none.
+func profileHashes(profiles []tunedv1.TunedProfile) map[string]string {
+	var hashes map[string]string
+	for _, profile := range profiles {
+		if profile.Name == nil || profile.Data == nil {
+			// Not extracted either.
+			continue
+		}
+		if hashes == nil {
+			hashes = map[string]string{}
+		}
+		sum := sha256.Sum256([]byte(*profile.Data))
+		hashes[*profile.Name] = hex.EncodeToString(sum[:])
+	}
+
+	return hashes
+}
+
+// writeNodeTuningState writes the node tuning 'state' to 'stateFile'.  The file is
+// replaced atomically so the readers never observe a partially written state.
+func writeNodeTuningState(stateFile string, state *nodeTuningState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the node tuning state: %v", err)
+	}
+
+	if err := writeFileAtomic(stateFile, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write the node tuning state: %v", err)
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes 'data' to a temporary file next to 'file' and renames it to 'file'.
+func writeFileAtomic(file string, data []byte) error {
+	dir := filepath.Dir(file)
+	if err := util.Mkdir(dir); err != nil {
+		return fmt.Errorf("failed to create directory %q: %v", dir, err)
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(file)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary file in %q: %v", dir, err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %q: %v", f.Name(), err)
+	}
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to set the mode of %q: %v", f.Name(), err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close %q: %v", f.Name(), err)
+	}
+
+	if err := os.Rename(f.Name(), file); err != nil {
+		return fmt.Errorf("failed to rename %q to %q: %v", f.Name(), file, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package tuned
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+func TestProfilesChecksum(t *testing.T) {
+	dir := t.TempDir()
+	writeProfile := func(name, data string) {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, tunedConfFile), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeProfile("openshift-node", "[main]\nsummary=node\n")
+	checksum := profilesChecksum([]string{"openshift-node"}, dir)
+
+	if got := profilesChecksum([]string{"openshift-node", "missing"}, dir); got != checksum {
+		t.Errorf("profiles missing from the profiles directory changed the checksum: %s != %s", got, checksum)
+	}
+
+	writeProfile("openshift-node", "[main]\nsummary=node updated\n")
+	if got := profilesChecksum([]string{"openshift-node"}, dir); got == checksum {
+		t.Errorf("the checksum did not change with the profile content")
+	}
+}
+
+func TestProfileHashes(t *testing.T) {
+	name, data := "openshift-node", "[main]\nsummary=node\n"
+	profiles := []tunedv1.TunedProfile{
+		{Name: &name, Data: &data},
+		{Name: &name},
+	}
+
+	expected := map[string]string{
+		// sha256sum of the profile data
+		"openshift-node": "8dc65b51152ce391f49ca33ceffb6e8e1942d3055a96215885952deb4380fd90",
+	}
+	if got := profileHashes(profiles); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the checksums %v, got %v", expected, got)
+	}
+	if got := profileHashes(profiles[1:]); got != nil {
+		t.Errorf("expected no checksums without the profile data, got %v", got)
+	}
+}
+
+func TestWriteNodeTuningState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "run", "state.json")
+	conditions := []tunedv1.ProfileStatusCondition{
+		{
+			Type:   tunedv1.TunedProfileApplied,
+			Status: corev1.ConditionTrue,
+			Reason: "AsExpected",
+		},
+		{
+			Type:   tunedv1.TunedDegraded,
+			Status: corev1.ConditionTrue,
+			Reason: "TunedError",
+		},
+	}
+	state := newNodeTuningState("openshift-node", "nohz=on", conditions)
+
+	if err := writeNodeTuningState(stateFile, state); err != nil {
+		t.Fatalf("failed to write the node tuning state: %v", err)
+	}
+
+	data, err := os.ReadFile(stateFile)
+	if err != nil {
+		t.Fatalf("failed to read the node tuning state: %v", err)
+	}
+	var got nodeTuningState
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to unmarshal the node tuning state: %v", err)
+	}
+
+	if got.Version != nodeTuningStateVersion {
+		t.Errorf("expected version %d, got %d", nodeTuningStateVersion, got.Version)
+	}
+	if got.Profile != "openshift-node" || got.Bootcmdline != "nohz=on" {
+		t.Errorf("unexpected profile %q or bootcmdline %q", got.Profile, got.Bootcmdline)
+	}
+	if !reflect.DeepEqual(got.DegradedReasons, []string{"TunedError"}) {
+		t.Errorf("expected the degraded reasons [TunedError], got %v", got.DegradedReasons)
+	}
+	if !got.Timestamp.Equal(state.Timestamp) {
+		t.Errorf("expected timestamp %v, got %v", state.Timestamp, got.Timestamp)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(stateFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the state file in the state directory, got %d entries", len(entries))
+	}
+}
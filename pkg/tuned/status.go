@@ -7,6 +7,10 @@ import (
 	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
 )
 
+// ProfileAppliedDeferredReason is the TunedProfileApplied condition reason reported
+// while the application of the recommended TuneD profile is deferred.
+const ProfileAppliedDeferredReason = "Deferred"
+
 // setStatusCondition returns the result of setting the specified condition in
 // the given slice of conditions.
 func setStatusCondition(oldConditions []tunedv1.ProfileStatusCondition, condition *tunedv1.ProfileStatusCondition) []tunedv1.ProfileStatusCondition {
@@ -36,6 +40,12 @@ func setStatusCondition(oldConditions []tunedv1.ProfileStatusCondition, conditio
 	return newConditions
 }
 
+// SetStatusCondition returns the result of setting the specified condition in
+// the given slice of conditions, for the conditions the operator reports.
+func SetStatusCondition(oldConditions []tunedv1.ProfileStatusCondition, condition *tunedv1.ProfileStatusCondition) []tunedv1.ProfileStatusCondition {
+	return setStatusCondition(oldConditions, condition)
+}
+
 // conditionsEqual returns true if and only if the provided slices of conditions
 // (ignoring LastTransitionTime) are equal.
 func conditionsEqual(oldConditions, newConditions []tunedv1.ProfileStatusCondition) bool {
@@ -100,7 +110,11 @@ func computeStatusConditions(status Bits, stderr string, conditions []tunedv1.Pr
 		Type: tunedv1.TunedDegraded,
 	}
 
-	if (status & scApplied) != 0 {
+	if (status & scDeferred) != 0 {
+		tunedProfileAppliedCondition.Status = corev1.ConditionFalse
+		tunedProfileAppliedCondition.Reason = ProfileAppliedDeferredReason
+		tunedProfileAppliedCondition.Message = "The TuneD daemon profile change is pending until the next TuneD daemon restart."
+	} else if (status & scApplied) != 0 {
 		tunedProfileAppliedCondition.Status = corev1.ConditionTrue
 		tunedProfileAppliedCondition.Reason = "AsExpected"
 		tunedProfileAppliedCondition.Message = "TuneD profile applied."
@@ -0,0 +1,76 @@
+package tuned
+
+import (
+	"fmt"     // Sprintf()
+	"sort"    // sort.Strings()
+	"strings" // strings.Join()
+
+	corev1 "k8s.io/api/core/v1"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+// ProfileDegradedSysctlPolicyReason is the TunedDegraded condition reason reported
+// while the TuneD profiles set sysctls the sysctl policy of Tuned/default denies.
+const ProfileDegradedSysctlPolicyReason = "SysctlPolicyViolation"
+
+// sysctlPolicyEnforce returns the TuneD profiles 'profiles' without the sysctls the sysctl
+// policy 'policy' denies and the denied sysctls by the TuneD profile name.
+func sysctlPolicyEnforce(policy *tunedv1.SysctlPolicy, profiles []tunedv1.TunedProfile) ([]tunedv1.TunedProfile, map[string][]string) {
+	if policy == nil {
+		return profiles, nil
+	}
+
+	sysctlsDenied := map[string][]string{}
+	enforced := make([]tunedv1.TunedProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		if profile.Name == nil || profile.Data == nil {
+			enforced = append(enforced, profile)
+			continue
+		}
+		data, denied := policy.FilterSysctls(*profile.Data)
+		if len(denied) > 0 {
+			sysctlsDenied[*profile.Name] = denied
+			profile = *profile.DeepCopy()
+			profile.Data = &data
+		}
+		enforced = append(enforced, profile)
+	}
+
+	return enforced, sysctlsDenied
+}
+
+// profileEntriesIn returns the sorted entries, e.g. the denied sysctls, of the TuneD profiles
+// 'profiles' out of the entries by the TuneD profile name 'entries'.
+func profileEntriesIn(entries map[string][]string, profiles map[string]bool) []string {
+	set := map[string]bool{}
+	for profile := range profiles {
+		for _, entry := range entries[profile] {
+			set[entry] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(set))
+	for entry := range set {
+		sorted = append(sorted, entry)
+	}
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// setSysctlPolicyStatusCondition returns 'conditions' with the TunedDegraded condition
+// reporting the sysctls 'denied' by the sysctl policy, if any.
+func setSysctlPolicyStatusCondition(conditions []tunedv1.ProfileStatusCondition, denied []string) []tunedv1.ProfileStatusCondition {
+	if len(denied) == 0 {
+		return conditions
+	}
+
+	return setStatusCondition(conditions, &tunedv1.ProfileStatusCondition{
+		Type:   tunedv1.TunedDegraded,
+		Status: corev1.ConditionTrue,
+		Reason: ProfileDegradedSysctlPolicyReason,
+		Message: fmt.Sprintf("The sysctl policy of Tuned/%s denies the sysctl(s) %s set by the TuneD profile, the sysctl(s) were not applied.",
+			tunedv1.TunedDefaultResourceName, strings.Join(denied, ", ")),
+	})
+}
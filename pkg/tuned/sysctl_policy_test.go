@@ -0,0 +1,99 @@
+package tuned
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+func TestSysctlPolicyEnforce(t *testing.T) {
+	profile := func(name, data string) tunedv1.TunedProfile {
+		return tunedv1.TunedProfile{Name: &name, Data: &data}
+	}
+	profiles := []tunedv1.TunedProfile{
+		profile("production", "[main]\nsummary=production\n[sysctl]\nkernel.panic=10\nkernel/panic_on_oops=1\nnet.core.somaxconn=4096\n[vm]\ntransparent_hugepages=never\n"),
+		profile("custom-sysctl", "[main]\nsummary=custom\n[net]\ntype=sysctl\nreplace=true\nnet.ipv4.ip_forward=1\n"),
+		profile("commented", "[sysctl]\n# kernel.panic=10\nvm.swappiness=10\n"),
+	}
+
+	tests := []struct {
+		name     string
+		policy   *tunedv1.SysctlPolicy
+		denied   map[string][]string
+		expected []string
+	}{
+		{
+			name:     "no policy",
+			expected: []string{*profiles[0].Data, *profiles[1].Data, *profiles[2].Data},
+		},
+		{
+			name:   "denied with a wildcard",
+			policy: &tunedv1.SysctlPolicy{Denied: []string{"kernel.panic*"}},
+			denied: map[string][]string{"production": {"kernel.panic", "kernel.panic_on_oops"}},
+			expected: []string{
+				"[main]\nsummary=production\n[sysctl]\nnet.core.somaxconn=4096\n[vm]\ntransparent_hugepages=never\n",
+				*profiles[1].Data,
+				*profiles[2].Data,
+			},
+		},
+		{
+			name:   "allowed takes a back seat to denied",
+			policy: &tunedv1.SysctlPolicy{Allowed: []string{"net.*", "kernel.panic_on_oops"}, Denied: []string{"net.ipv4.ip_forward"}},
+			denied: map[string][]string{
+				"production":    {"kernel.panic"},
+				"custom-sysctl": {"net.ipv4.ip_forward"},
+				"commented":     {"vm.swappiness"},
+			},
+			expected: []string{
+				"[main]\nsummary=production\n[sysctl]\nkernel/panic_on_oops=1\nnet.core.somaxconn=4096\n[vm]\ntransparent_hugepages=never\n",
+				"[main]\nsummary=custom\n[net]\ntype=sysctl\nreplace=true\n",
+				"[sysctl]\n# kernel.panic=10\n",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			enforced, denied := sysctlPolicyEnforce(tc.policy, profiles)
+			if !reflect.DeepEqual(denied, tc.denied) {
+				t.Errorf("expected the denied sysctls %v, got %v", tc.denied, denied)
+			}
+			for i := range enforced {
+				if *enforced[i].Data != tc.expected[i] {
+					t.Errorf("expected profile %s data %q, got %q", *enforced[i].Name, tc.expected[i], *enforced[i].Data)
+				}
+			}
+		})
+	}
+
+	if *profiles[0].Data != "[main]\nsummary=production\n[sysctl]\nkernel.panic=10\nkernel/panic_on_oops=1\nnet.core.somaxconn=4096\n[vm]\ntransparent_hugepages=never\n" {
+		t.Errorf("the enforced profiles must not modify the original profiles")
+	}
+}
+
+func TestSysctlPolicyStatusCondition(t *testing.T) {
+	sysctlsDenied := map[string][]string{
+		"openshift-node-production": {"kernel.panic"},
+		"unused":                    {"vm.swappiness"},
+	}
+	denied := profileEntriesIn(sysctlsDenied, map[string]bool{"openshift-node": true, "openshift-node-production": true})
+	if !reflect.DeepEqual(denied, []string{"kernel.panic"}) {
+		t.Fatalf("expected only the sysctls denied in the recommended profiles, got %v", denied)
+	}
+
+	conditions := computeStatusConditions(scApplied, "", InitializeStatusConditions())
+	if c := setSysctlPolicyStatusCondition(conditions, nil); !conditionsEqual(c, conditions) {
+		t.Errorf("expected the conditions to be unchanged without denied sysctls")
+	}
+	for _, c := range setSysctlPolicyStatusCondition(conditions, denied) {
+		if c.Type != tunedv1.TunedDegraded {
+			continue
+		}
+		if c.Status != corev1.ConditionTrue || c.Reason != ProfileDegradedSysctlPolicyReason {
+			t.Errorf("expected the profile to be degraded by the sysctl policy, got %v", c)
+		}
+	}
+}
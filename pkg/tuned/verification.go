@@ -0,0 +1,167 @@
+package tuned
+
+import (
+	"context"       // context.WithTimeout()
+	"errors"        // errors.As()
+	"fmt"           // Sprintf()
+	"os"            // os.ReadFile()
+	"os/exec"       // exec.CommandContext()
+	"path/filepath" // filepath.Join()
+	"regexp"        // regexp.Compile()
+	"strings"       // strings.Fields()
+	"syscall"       // syscall.Credential
+	"time"          // time.Second
+
+	corev1 "k8s.io/api/core/v1"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+const (
+	// ProfileVerificationPassedReason is the TunedVerified condition reason reported when
+	// all the checks verifying the applied TuneD profile passed.
+	ProfileVerificationPassedReason = "Passed"
+	// ProfileVerificationFailedReason is the TunedVerified condition reason reported when
+	// some of the checks verifying the applied TuneD profile failed.
+	ProfileVerificationFailedReason = "Failed"
+	// verificationCommandTimeout is the time the command checks are killed after.
+	verificationCommandTimeout = 10 * time.Second
+)
+
+var (
+	// verificationRoot is the directory the files of the file checks are looked up in.
+	verificationRoot = hostRoot
+	// sysctlDir is the directory the sysctls of the sysctl checks are read from.
+	sysctlDir = "/proc/sys"
+	// verificationCredential is the unprivileged user (nobody) the command checks run as.
+	verificationCredential = &syscall.Credential{Uid: 65534, Gid: 65534}
+)
+
+// runVerification runs the checks 'checks' and returns the failures, one per failed check.
+func runVerification(checks []tunedv1.TunedVerification) []string {
+	failures := []string{}
+	for i := range checks {
+		if err := runVerificationCheck(&checks[i]); err != nil {
+			failures = append(failures, fmt.Sprintf("%s (%v)", checks[i].Name, err))
+		}
+	}
+
+	return failures
+}
+
+// runVerificationCheck runs the check 'check' and returns the reason it failed or nil if it passed.
+func runVerificationCheck(check *tunedv1.TunedVerification) error {
+	set := 0
+	for _, isSet := range []bool{check.File != nil, check.Sysctl != nil, check.Command != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of file, sysctl and command must be set")
+	}
+
+	switch {
+	case check.File != nil:
+		return verifyFile(check.File)
+	case check.Sysctl != nil:
+		return verifySysctl(check.Sysctl)
+	default:
+		return verifyCommand(check.Command)
+	}
+}
+
+func verifyFile(check *tunedv1.TunedFileVerification) error {
+	re, err := regexp.Compile(check.Match)
+	if err != nil {
+		return fmt.Errorf("invalid regular expression %q: %v", check.Match, err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(verificationRoot, check.Path))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", check.Path, err)
+	}
+	if !re.Match(content) {
+		return fmt.Errorf("the content of %s does not match %q", check.Path, check.Match)
+	}
+
+	return nil
+}
+
+func verifySysctl(check *tunedv1.TunedSysctlVerification) error {
+	// Sysctl names use either dots or slashes as the separator, see sysctl.d(5).
+	name := strings.ReplaceAll(check.Name, ".", "/")
+	content, err := os.ReadFile(filepath.Join(sysctlDir, filepath.Clean("/"+name)))
+	if err != nil {
+		return fmt.Errorf("failed to read sysctl %s: %v", check.Name, err)
+	}
+
+	// Multi-value sysctls separate the values by tabs.
+	value := strings.Join(strings.Fields(string(content)), " ")
+	if value != strings.Join(strings.Fields(check.Value), " ") {
+		return fmt.Errorf("sysctl %s is %q, expected %q", check.Name, value, check.Value)
+	}
+
+	return nil
+}
+
+func verifyCommand(check *tunedv1.TunedCommandVerification) error {
+	if len(check.Command) == 0 {
+		return fmt.Errorf("no command")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), verificationCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, check.Command[0], check.Command[1:]...)
+	cmd.Env = []string{}
+	cmd.Dir = "/"
+	if verificationCredential != nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{Credential: verificationCredential}
+	}
+
+	exitCode := 0
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || ctx.Err() != nil {
+			return fmt.Errorf("failed to run %s: %v", check.Command[0], err)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+	if int32(exitCode) != check.ExitCode {
+		return fmt.Errorf("%s exited with %d, expected %d", check.Command[0], exitCode, check.ExitCode)
+	}
+
+	return nil
+}
+
+// setVerifiedStatusCondition returns 'conditions' with the TunedVerified condition reporting the
+// 'failures' of the 'checks' checks verifying the applied TuneD profile, or without the condition
+// when no check verifies it.
+func setVerifiedStatusCondition(conditions []tunedv1.ProfileStatusCondition, checks int, failures []string) []tunedv1.ProfileStatusCondition {
+	if len(failures) > 0 {
+		return setStatusCondition(conditions, &tunedv1.ProfileStatusCondition{
+			Type:    tunedv1.TunedVerified,
+			Status:  corev1.ConditionFalse,
+			Reason:  ProfileVerificationFailedReason,
+			Message: fmt.Sprintf("%d of %d check(s) of the applied TuneD profile failed: %s.", len(failures), checks, strings.Join(failures, ", ")),
+		})
+	}
+
+	if checks > 0 {
+		return setStatusCondition(conditions, &tunedv1.ProfileStatusCondition{
+			Type:    tunedv1.TunedVerified,
+			Status:  corev1.ConditionTrue,
+			Reason:  ProfileVerificationPassedReason,
+			Message: fmt.Sprintf("All %d check(s) of the applied TuneD profile passed.", checks),
+		})
+	}
+
+	newConditions := []tunedv1.ProfileStatusCondition{}
+	for _, c := range conditions {
+		if c.Type != tunedv1.TunedVerified {
+			newConditions = append(newConditions, c)
+		}
+	}
+	return newConditions
+}
@@ -0,0 +1,86 @@
+package tuned
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+)
+
+func TestRunVerification(t *testing.T) {
+	defer func(root, dir string, cred *syscall.Credential) {
+		verificationRoot, sysctlDir, verificationCredential = root, dir, cred
+	}(verificationRoot, sysctlDir, verificationCredential)
+	verificationRoot = t.TempDir()
+	sysctlDir = t.TempDir()
+	verificationCredential = nil
+
+	writeFile := func(path, data string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(filepath.Join(verificationRoot, "etc/modprobe.d/ice.conf"), "options ice rx_queues=8\n")
+	writeFile(filepath.Join(sysctlDir, "net/core/busy_read"), "50\n")
+	writeFile(filepath.Join(sysctlDir, "net/ipv4/tcp_rmem"), "4096\t87380\t6291456\n")
+
+	checks := []tunedv1.TunedVerification{
+		{Name: "ice-queues", File: &tunedv1.TunedFileVerification{Path: "/etc/modprobe.d/ice.conf", Match: `rx_queues=8\b`}},
+		{Name: "ice-missing", File: &tunedv1.TunedFileVerification{Path: "/etc/modprobe.d/missing.conf", Match: "."}},
+		{Name: "busy-read", Sysctl: &tunedv1.TunedSysctlVerification{Name: "net.core.busy_read", Value: "50"}},
+		{Name: "tcp-rmem", Sysctl: &tunedv1.TunedSysctlVerification{Name: "net/ipv4/tcp_rmem", Value: "4096 87380 6291456"}},
+		{Name: "busy-read-wrong", Sysctl: &tunedv1.TunedSysctlVerification{Name: "net.core.busy_read", Value: "0"}},
+		{Name: "true", Command: &tunedv1.TunedCommandVerification{Command: []string{"/bin/sh", "-c", "exit 0"}}},
+		{Name: "exit-code", Command: &tunedv1.TunedCommandVerification{Command: []string{"/bin/sh", "-c", "exit 3"}, ExitCode: 3}},
+		{Name: "false", Command: &tunedv1.TunedCommandVerification{Command: []string{"/bin/sh", "-c", "exit 1"}}},
+		{Name: "empty"},
+	}
+
+	failures := runVerification(checks)
+	failed := []string{}
+	for _, failure := range failures {
+		failed = append(failed, strings.Fields(failure)[0])
+	}
+	expected := []string{"ice-missing", "busy-read-wrong", "false", "empty"}
+	if strings.Join(failed, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected the failed checks %v, got %v", expected, failures)
+	}
+}
+
+func TestVerifiedStatusCondition(t *testing.T) {
+	conditions := InitializeStatusConditions()
+
+	conditions = setVerifiedStatusCondition(conditions, 2, []string{"busy-read (sysctl net.core.busy_read is \"0\", expected \"50\")"})
+	c := findStatusCondition(conditions, tunedv1.TunedVerified)
+	if c == nil || c.Status != corev1.ConditionFalse || c.Reason != ProfileVerificationFailedReason || !strings.Contains(c.Message, "busy-read") {
+		t.Errorf("expected the failed TunedVerified condition, got %+v", c)
+	}
+
+	conditions = setVerifiedStatusCondition(conditions, 2, nil)
+	c = findStatusCondition(conditions, tunedv1.TunedVerified)
+	if c == nil || c.Status != corev1.ConditionTrue || c.Reason != ProfileVerificationPassedReason {
+		t.Errorf("expected the passed TunedVerified condition, got %+v", c)
+	}
+
+	conditions = setVerifiedStatusCondition(conditions, 0, nil)
+	if c := findStatusCondition(conditions, tunedv1.TunedVerified); c != nil {
+		t.Errorf("expected no TunedVerified condition without checks, got %+v", c)
+	}
+}
+
+func findStatusCondition(conditions []tunedv1.ProfileStatusCondition, conditionType tunedv1.ProfileConditionType) *tunedv1.ProfileStatusCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
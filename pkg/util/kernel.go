@@ -1,5 +1,12 @@
 package util
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
 // Checks for white-space characters in "C" and "POSIX" locales.
 func isspace(b byte) bool {
 	return b == ' ' || b == '\f' || b == '\n' || b == '\r' || b == '\t' || b == '\v'
@@ -94,3 +101,99 @@ func KernelArgumentsEqual(args1, args2 string, exclude ...string) bool {
 
 	return StringSlicesEqual(a1, a2)
 }
+
+// repeatableKernelArguments are the kernel parameters that can be passed several times with different
+// values.  Their order matters, e.g. "hugepagesz=1G hugepages=1 hugepagesz=2M hugepages=512".
+var repeatableKernelArguments = map[string]bool{
+	"console":    true,
+	"hugepages":  true,
+	"hugepagesz": true,
+}
+
+// KernelArgumentConflict is a kernel parameter passed by two sources with different values.
+type KernelArgumentConflict struct {
+	// Argument is the kernel argument of the deduplicated source.
+	Argument string
+	// Existing is the kernel argument of the source 'Owner' setting the same parameter.
+	Existing string
+	// Owner is the name of the source of 'Existing'.
+	Owner string
+}
+
+// DeduplicateKernelArguments returns kernel arguments 'args' without the arguments the sources 'existing'
+// already pass to the kernel.  'existing' maps source names to their kernel arguments.  Next to the
+// deduplicated arguments, it returns the source owning each of the dropped duplicates and the arguments
+// setting a parameter 'existing' sets to another value.  The conflicting arguments are dropped as well,
+// so the kernel command line never holds contradictory values.  The repeatable parameters are never
+// deduplicated, as their order matters.
+func DeduplicateKernelArguments(args []string, existing map[string][]string) ([]string, map[string]string, []KernelArgumentConflict) {
+	var (
+		deduplicated []string
+		owners       = map[string]string{}
+		conflicts    []KernelArgumentConflict
+	)
+
+	sources := make([]string, 0, len(existing))
+	for source := range existing {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	// The first source in name order owns a parameter.
+	ownedArgs := map[string]string{}
+	ownedKeys := map[string]string{}
+	for _, source := range sources {
+		for _, arg := range existing[source] {
+			key, _, _ := strings.Cut(arg, "=")
+			if repeatableKernelArguments[key] {
+				continue
+			}
+			if _, ok := ownedArgs[arg]; !ok {
+				ownedArgs[arg] = source
+			}
+			if _, ok := ownedKeys[key]; !ok {
+				ownedKeys[key] = arg
+			}
+		}
+	}
+
+	for _, arg := range args {
+		key, _, _ := strings.Cut(arg, "=")
+		if repeatableKernelArguments[key] {
+			deduplicated = append(deduplicated, arg)
+			continue
+		}
+		if owner, ok := ownedArgs[arg]; ok {
+			owners[arg] = owner
+			continue
+		}
+		if existingArg, ok := ownedKeys[key]; ok {
+			conflicts = append(conflicts, KernelArgumentConflict{
+				Argument: arg,
+				Existing: existingArg,
+				Owner:    ownedArgs[existingArg],
+			})
+			continue
+		}
+		deduplicated = append(deduplicated, arg)
+	}
+
+	return deduplicated, owners, conflicts
+}
+
+// KernelCmdlineManifestEntry is the expected kernel command line of a rendered MachineConfig.
+type KernelCmdlineManifestEntry struct {
+	// KernelArguments are the kernel arguments of the rendered MachineConfig in their boot order.
+	KernelArguments []string `json:"kernelArguments"`
+	// SHA256 is the SHA-256 checksum of the space-separated kernel arguments.
+	SHA256 string `json:"sha256"`
+}
+
+// NewKernelCmdlineManifestEntry returns the kernel command line manifest entry of kernel arguments 'args'.
+func NewKernelCmdlineManifestEntry(args []string) KernelCmdlineManifestEntry {
+	sum := sha256.Sum256([]byte(strings.Join(args, " ")))
+	return KernelCmdlineManifestEntry{
+		KernelArguments: args,
+		SHA256:          hex.EncodeToString(sum[:]),
+	}
+}
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeduplicateKernelArguments(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		args              []string
+		existing          map[string][]string
+		expectedArgs      []string
+		expectedOwners    map[string]string
+		expectedConflicts []KernelArgumentConflict
+	}{
+		{
+			desc:           "should keep the arguments without overlap",
+			args:           []string{"skew_tick=1", "nohz=on"},
+			existing:       map[string][]string{"99-worker-custom": {"audit=0"}},
+			expectedArgs:   []string{"skew_tick=1", "nohz=on"},
+			expectedOwners: map[string]string{},
+		},
+		{
+			desc:           "should drop the arguments other sources pass",
+			args:           []string{"skew_tick=1", "nosmt", "nohz=on"},
+			existing:       map[string][]string{"99-worker-custom": {"nosmt", "nohz=on"}},
+			expectedArgs:   []string{"skew_tick=1"},
+			expectedOwners: map[string]string{"nosmt": "99-worker-custom", "nohz=on": "99-worker-custom"},
+		},
+		{
+			desc:           "should drop the conflicting arguments",
+			args:           []string{"skew_tick=1", "nohz=on"},
+			existing:       map[string][]string{"99-worker-custom": {"skew_tick=0"}},
+			expectedArgs:   []string{"nohz=on"},
+			expectedOwners: map[string]string{},
+			expectedConflicts: []KernelArgumentConflict{
+				{Argument: "skew_tick=1", Existing: "skew_tick=0", Owner: "99-worker-custom"},
+			},
+		},
+		{
+			desc:           "should attribute the arguments to the first source by name",
+			args:           []string{"nosmt"},
+			existing:       map[string][]string{"99-worker-b": {"nosmt"}, "99-worker-a": {"nosmt"}},
+			expectedOwners: map[string]string{"nosmt": "99-worker-a"},
+		},
+		{
+			desc:           "should keep the repeatable arguments",
+			args:           []string{"hugepagesz=1G", "hugepages=4", "console=ttyS0"},
+			existing:       map[string][]string{"99-worker-custom": {"hugepagesz=2M", "hugepages=4", "console=tty0"}},
+			expectedArgs:   []string{"hugepagesz=1G", "hugepages=4", "console=ttyS0"},
+			expectedOwners: map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			args, owners, conflicts := DeduplicateKernelArguments(tc.args, tc.existing)
+			if !reflect.DeepEqual(args, tc.expectedArgs) {
+				t.Errorf("expected the arguments %v, got %v", tc.expectedArgs, args)
+			}
+			if !reflect.DeepEqual(owners, tc.expectedOwners) {
+				t.Errorf("expected the owners %v, got %v", tc.expectedOwners, owners)
+			}
+			if !reflect.DeepEqual(conflicts, tc.expectedConflicts) {
+				t.Errorf("expected the conflicts %v, got %v", tc.expectedConflicts, conflicts)
+			}
+		})
+	}
+}
+
+func TestNewKernelCmdlineManifestEntry(t *testing.T) {
+	entry := NewKernelCmdlineManifestEntry([]string{"nosmt", "isolcpus=2-3"})
+	same := NewKernelCmdlineManifestEntry(SplitKernelArguments("nosmt  isolcpus=2-3"))
+	reordered := NewKernelCmdlineManifestEntry([]string{"isolcpus=2-3", "nosmt"})
+
+	if len(entry.SHA256) != 64 {
+		t.Errorf("expected a hex-encoded SHA-256 checksum, got %q", entry.SHA256)
+	}
+	if entry.SHA256 != same.SHA256 {
+		t.Errorf("expected the same checksum of the same kernel arguments, got %q and %q", entry.SHA256, same.SHA256)
+	}
+	if entry.SHA256 == reordered.SHA256 {
+		t.Errorf("expected different checksums of the reordered kernel arguments, got %q", entry.SHA256)
+	}
+}
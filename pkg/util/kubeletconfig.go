@@ -0,0 +1,98 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+// kubeletConfigSyncedKeys are the kubelet configuration keys the performance profile sets
+// and the operand reports the checksums of.
+var kubeletConfigSyncedKeys = []string{
+	"cpuManagerPolicy",
+	"cpuManagerPolicyOptions",
+	"cpuManagerReconcilePeriod",
+	"evictionHard",
+	"kubeReserved",
+	"memoryManagerPolicy",
+	"reservedMemory",
+	"reservedSystemCPUs",
+	"systemReserved",
+	"topologyManagerPolicy",
+	"topologyManagerPolicyOptions",
+	"topologyManagerScope",
+}
+
+// KubeletConfigChecksums returns the checksums of the values of the kubelet configuration keys
+// the performance profile sets, found in the YAML or JSON kubelet configuration 'config'.
+// The entries of map values are checksummed one by one under "<key>.<entry>", as the MCO
+// merges them with the defaults of the rendered kubelet configuration.
+func KubeletConfigChecksums(config []byte) (map[string]string, error) {
+	fields := map[string]interface{}{}
+	if err := yaml.Unmarshal(config, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse the kubelet configuration: %v", err)
+	}
+
+	checksums := map[string]string{}
+	for _, key := range kubeletConfigSyncedKeys {
+		value, ok := fields[key]
+		if !ok {
+			continue
+		}
+
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			checksum, err := kubeletConfigValueChecksum(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to checksum the kubelet configuration key %q: %v", key, err)
+			}
+			checksums[key] = checksum
+			continue
+		}
+
+		for entry, entryValue := range entries {
+			checksum, err := kubeletConfigValueChecksum(entryValue)
+			if err != nil {
+				return nil, fmt.Errorf("failed to checksum the kubelet configuration key %q: %v", key+"."+entry, err)
+			}
+			checksums[key+"."+entry] = checksum
+		}
+	}
+
+	return checksums, nil
+}
+
+// KubeletConfigChecksumsMismatch returns the first key of the 'expected' kubelet configuration checksums
+// missing or differing in the 'actual' ones, or an empty string when all of them match.
+func KubeletConfigChecksumsMismatch(expected, actual map[string]string) string {
+	for _, key := range kubeletConfigSortedKeys(expected) {
+		if actual[key] != expected[key] {
+			return key
+		}
+	}
+	return ""
+}
+
+func kubeletConfigValueChecksum(value interface{}) (string, error) {
+	// JSON marshaling sorts the map keys, so the checksum does not depend on the
+	// order of the keys in the kubelet configuration.
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+func kubeletConfigSortedKeys(checksums map[string]string) []string {
+	keys := make([]string, 0, len(checksums))
+	for key := range checksums {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
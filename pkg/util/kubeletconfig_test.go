@@ -0,0 +1,50 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestKubeletConfigChecksums(t *testing.T) {
+	generated := []byte(`{"cpuManagerPolicy":"static","reservedSystemCPUs":"0-1","kubeReserved":{"memory":"500Mi"},"maxPods":250}`)
+	rendered := []byte(`
+cpuManagerPolicy: static
+reservedSystemCPUs: "0-1"
+kubeReserved:
+  cpu: 500m
+  memory: 500Mi
+systemCgroups: /system.slice
+`)
+
+	expected, err := KubeletConfigChecksums(generated)
+	if err != nil {
+		t.Fatalf("failed to checksum the generated kubelet configuration: %v", err)
+	}
+	for _, key := range []string{"cpuManagerPolicy", "reservedSystemCPUs", "kubeReserved.memory"} {
+		if _, ok := expected[key]; !ok {
+			t.Errorf("expected the checksum of %q, got %v", key, expected)
+		}
+	}
+	if _, ok := expected["maxPods"]; ok {
+		t.Errorf("expected no checksum of the unmanaged key maxPods, got %v", expected)
+	}
+
+	actual, err := KubeletConfigChecksums(rendered)
+	if err != nil {
+		t.Fatalf("failed to checksum the rendered kubelet configuration: %v", err)
+	}
+	if key := KubeletConfigChecksumsMismatch(expected, actual); key != "" {
+		t.Errorf("expected the rendered kubelet configuration to match, got a mismatch of %q", key)
+	}
+
+	stale, err := KubeletConfigChecksums([]byte(`{"cpuManagerPolicy":"none","reservedSystemCPUs":"0-1","kubeReserved":{"memory":"500Mi"}}`))
+	if err != nil {
+		t.Fatalf("failed to checksum the stale kubelet configuration: %v", err)
+	}
+	if key := KubeletConfigChecksumsMismatch(expected, stale); key != "cpuManagerPolicy" {
+		t.Errorf("expected a mismatch of %q, got %q", "cpuManagerPolicy", key)
+	}
+
+	if _, err := KubeletConfigChecksums([]byte(`cpuManagerPolicy: [`)); err == nil {
+		t.Errorf("expected an error parsing an invalid kubelet configuration")
+	}
+}
@@ -17,16 +17,19 @@ package util
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
 	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
 )
 
@@ -75,6 +78,87 @@ func ParseManifests(filename string, r io.Reader) ([]manifest, error) {
 	}
 }
 
+// ManifestFilterOptions selects the manifests kept by FilterManifests. The group of a GroupVersionKind
+// always has to match, while an empty version or kind matches any value, for example
+// {Group: "machineconfiguration.openshift.io"} matches all the kinds of the group.
+type ManifestFilterOptions struct {
+	// Include lists the kinds to keep, all the kinds are kept when empty.
+	Include []schema.GroupVersionKind
+	// Exclude lists the kinds to drop, it takes precedence over Include.
+	Exclude []schema.GroupVersionKind
+}
+
+// IgnoredManifests counts the manifests dropped by FilterManifests per kind.
+// The manifests without apiVersion or kind are counted under the empty GroupVersionKind.
+type IgnoredManifests map[schema.GroupVersionKind]int
+
+// Add adds the counts of 'other' to the ignored manifests.
+func (i IgnoredManifests) Add(other IgnoredManifests) {
+	for gvk, count := range other {
+		i[gvk] += count
+	}
+}
+
+// String returns a summary of the ignored manifests, sorted by kind.
+func (i IgnoredManifests) String() string {
+	var summary []string
+	for gvk, count := range i {
+		kind := gvk.String()
+		if gvk.Empty() {
+			kind = "without apiVersion or kind"
+		}
+		summary = append(summary, fmt.Sprintf("%d %s", count, kind))
+	}
+	sort.Strings(summary)
+	return strings.Join(summary, ", ")
+}
+
+// FilterManifests returns the manifests selected by 'options' and the count of the ignored ones.
+// The manifests without apiVersion or kind are always ignored, they can not be decoded.
+func FilterManifests(manifests []manifest, options ManifestFilterOptions) ([]manifest, IgnoredManifests) {
+	var filtered []manifest
+	ignored := IgnoredManifests{}
+	for _, m := range manifests {
+		gvk := manifestGroupVersionKind(m)
+		if gvk.Empty() {
+			ignored[gvk]++
+			continue
+		}
+		if matchesGroupVersionKind(gvk, options.Exclude) {
+			ignored[gvk]++
+			continue
+		}
+		if len(options.Include) > 0 && !matchesGroupVersionKind(gvk, options.Include) {
+			ignored[gvk]++
+			continue
+		}
+		filtered = append(filtered, m)
+	}
+	return filtered, ignored
+}
+
+func manifestGroupVersionKind(m manifest) schema.GroupVersionKind {
+	typeMeta := v1.TypeMeta{}
+	if err := json.Unmarshal(m.Raw, &typeMeta); err != nil {
+		return schema.GroupVersionKind{}
+	}
+	if typeMeta.APIVersion == "" || typeMeta.Kind == "" {
+		return schema.GroupVersionKind{}
+	}
+	return schema.FromAPIVersionAndKind(typeMeta.APIVersion, typeMeta.Kind)
+}
+
+func matchesGroupVersionKind(gvk schema.GroupVersionKind, selectors []schema.GroupVersionKind) bool {
+	for _, selector := range selectors {
+		if selector.Group == gvk.Group &&
+			(selector.Version == "" || selector.Version == gvk.Version) &&
+			(selector.Kind == "" || selector.Kind == gvk.Kind) {
+			return true
+		}
+	}
+	return false
+}
+
 func ListFiles(dirPaths string) ([]string, error) {
 	dirs := strings.Split(dirPaths, ",")
 	return ListFilesFromMultiplePaths(dirs)
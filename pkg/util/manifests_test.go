@@ -0,0 +1,82 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const renderInputs = `
+apiVersion: performance.openshift.io/v2
+kind: PerformanceProfile
+metadata:
+  name: performance
+---
+apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfigPool
+metadata:
+  name: worker-cnf
+---
+apiVersion: config.openshift.io/v1
+kind: Proxy
+metadata:
+  name: cluster
+---
+apiVersion: v1
+baseDomain: example.com
+metadata:
+  name: install-config
+`
+
+func TestFilterManifests(t *testing.T) {
+	manifests, err := ParseManifests("inputs.yaml", strings.NewReader(renderInputs))
+	if err != nil {
+		t.Fatalf("failed to parse the manifests: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		options  ManifestFilterOptions
+		expected int
+		ignored  string
+	}{
+		{
+			name:     "no selectors",
+			expected: 3,
+			ignored:  "1 without apiVersion or kind",
+		},
+		{
+			name: "included kinds",
+			options: ManifestFilterOptions{
+				Include: []schema.GroupVersionKind{
+					{Group: "performance.openshift.io", Kind: "PerformanceProfile"},
+					{Group: "machineconfiguration.openshift.io"},
+				},
+			},
+			expected: 2,
+			ignored:  "1 config.openshift.io/v1, Kind=Proxy, 1 without apiVersion or kind",
+		},
+		{
+			name: "excluded kinds",
+			options: ManifestFilterOptions{
+				Include: []schema.GroupVersionKind{{Group: "machineconfiguration.openshift.io"}},
+				Exclude: []schema.GroupVersionKind{{Group: "machineconfiguration.openshift.io", Version: "v1", Kind: "MachineConfigPool"}},
+			},
+			expected: 0,
+			ignored:  "1 config.openshift.io/v1, Kind=Proxy, 1 machineconfiguration.openshift.io/v1, Kind=MachineConfigPool, 1 performance.openshift.io/v2, Kind=PerformanceProfile, 1 without apiVersion or kind",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered, ignored := FilterManifests(manifests, tc.options)
+			if len(filtered) != tc.expected {
+				t.Errorf("expected %d manifests, got %d", tc.expected, len(filtered))
+			}
+			if ignored.String() != tc.ignored {
+				t.Errorf("expected the ignored manifests %q, got %q", tc.ignored, ignored.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	coreapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	ntoconfig "github.com/openshift/cluster-node-tuning-operator/pkg/config"
+	util "github.com/openshift/cluster-node-tuning-operator/test/e2e/util"
+)
+
+// Test the operator taints the node whose Profile did not converge when the node taint management is enabled
+var _ = ginkgo.Describe("[basic][node_taint] Node Tuning Operator node taint management", func() {
+	const (
+		profileCauseTunedFailure   = "../testing_manifests/cause_tuned_failure.yaml"
+		nodeLabelCauseTunedFailure = "tuned.openshift.io/cause-tuned-failure"
+		pollInterval               = 5 * time.Second
+		waitDuration               = 5 * time.Minute
+	)
+
+	// waitForNodeTaint waits for the not-converged taint of node 'nodeName' to be 'tainted'
+	waitForNodeTaint := func(nodeName string, tainted bool) error {
+		taint := &coreapi.Taint{Key: tunedv1.TunedNotConvergedTaintKey, Effect: coreapi.TaintEffectNoSchedule}
+		return wait.PollUntilContextTimeout(context.TODO(), pollInterval, waitDuration, true, func(ctx context.Context) (bool, error) {
+			node, err := cs.CoreV1Interface.Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+			if err != nil {
+				util.Logf("failed to get node %s: %v", nodeName, err)
+				return false, nil
+			}
+			found := false
+			for _, t := range node.Spec.Taints {
+				if t.MatchTaint(taint) {
+					found = true
+				}
+			}
+			return found == tainted, nil
+		})
+	}
+
+	ginkgo.Context("node taint management", func() {
+		var (
+			node *coreapi.Node
+		)
+
+		// Cleanup code to roll back cluster changes done by this test even if it fails in the middle of ginkgo.It()
+		ginkgo.AfterEach(func() {
+			ginkgo.By("cluster changes rollback")
+			if node != nil {
+				util.ExecAndLogCommand("oc", "label", "node", "--overwrite", node.Name, nodeLabelCauseTunedFailure+"-")
+			}
+			util.ExecAndLogCommand("oc", "delete", "-n", ntoconfig.WatchNamespace(), "-f", profileCauseTunedFailure)
+			// the operator removes the taint it applied once the node taint management is disabled
+			util.ExecAndLogCommand("oc", "patch", "-n", ntoconfig.WatchNamespace(), "tuned", tunedv1.TunedDefaultResourceName, "--type", "merge",
+				"-p", fmt.Sprintf(`{"spec":{"nodeTaintManagement":"%s"}}`, tunedv1.NodeTaintManagementUnmanaged))
+		})
+
+		ginkgo.It("taints the node whose Profile is degraded and removes the taint", func() {
+			ginkgo.By("getting a list of worker nodes")
+			nodes, err := util.GetNodesByRole(cs, "worker")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(len(nodes)).NotTo(gomega.BeZero(), "number of worker nodes is 0")
+
+			node = &nodes[0]
+
+			ginkgo.By(fmt.Sprintf("enabling the node taint management in Tuned %s", tunedv1.TunedDefaultResourceName))
+			_, _, err = util.ExecAndLogCommand("oc", "patch", "-n", ntoconfig.WatchNamespace(), "tuned", tunedv1.TunedDefaultResourceName, "--type", "merge",
+				"-p", fmt.Sprintf(`{"spec":{"nodeTaintManagement":"%s"}}`, tunedv1.NodeTaintManagementManaged))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			ginkgo.By(fmt.Sprintf("ensuring node %s with a converged Profile is not tainted", node.Name))
+			err = waitForNodeTaint(node.Name, false)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			ginkgo.By(fmt.Sprintf("labelling node %s with label %s", node.Name, nodeLabelCauseTunedFailure))
+			_, _, err = util.ExecAndLogCommand("oc", "label", "node", "--overwrite", node.Name, nodeLabelCauseTunedFailure+"=")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			ginkgo.By(fmt.Sprintf("creating the custom profile %s", profileCauseTunedFailure))
+			_, _, err = util.ExecAndLogCommand("oc", "create", "-n", ntoconfig.WatchNamespace(), "-f", profileCauseTunedFailure)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			ginkgo.By(fmt.Sprintf("waiting for node %s to be tainted with %s", node.Name, tunedv1.TunedNotConvergedTaintKey))
+			err = waitForNodeTaint(node.Name, true)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			ginkgo.By(fmt.Sprintf("deleting the custom profile %s", profileCauseTunedFailure))
+			_, _, err = util.ExecAndLogCommand("oc", "delete", "-n", ntoconfig.WatchNamespace(), "-f", profileCauseTunedFailure)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			ginkgo.By(fmt.Sprintf("waiting for the %s taint to be removed from node %s", tunedv1.TunedNotConvergedTaintKey, node.Name))
+			err = waitForNodeTaint(node.Name, false)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			ginkgo.By(fmt.Sprintf("removing label %s from node %s", nodeLabelCauseTunedFailure, node.Name))
+			_, _, err = util.ExecAndLogCommand("oc", "label", "node", "--overwrite", node.Name, nodeLabelCauseTunedFailure+"-")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"k8s.io/utils/cpuset"
 	"sigs.k8s.io/yaml"
 
 	"github.com/openshift/cluster-node-tuning-operator/cmd/performance-profile-creator/cmd"
@@ -22,6 +24,7 @@ const (
 	mustGatherPath       = "../../testdata/must-gather"
 	expectedProfilesPath = "../../testdata/ppc-expected-profiles"
 	expectedInfoPath     = "../../testdata/ppc-expected-info"
+	expectedMultiMCPPath = "../../testdata/ppc-expected-multi-mcp"
 	ppcPath              = "../../../../../_output/performance-profile-creator"
 )
 
@@ -48,37 +51,7 @@ var _ = Describe("[rfe_id:OCP-38968][ppc] Performance Profile Creator", func() {
 		expectedProfiles := getExpectedProfiles(expectedProfilesPath, mustGatherDirs)
 
 		for expectedProfilePath, args := range expectedProfiles {
-			cmdArgs := []string{
-				fmt.Sprintf("--disable-ht=%v", args.DisableHT),
-				fmt.Sprintf("--mcp-name=%s", args.MCPName),
-				fmt.Sprintf("--must-gather-dir-path=%s", args.MustGatherDirPath),
-				fmt.Sprintf("--reserved-cpu-count=%d", args.ReservedCPUCount),
-				fmt.Sprintf("--rt-kernel=%v", args.RTKernel),
-				fmt.Sprintf("--split-reserved-cpus-across-numa=%v", args.SplitReservedCPUsAcrossNUMA),
-			}
-
-			if args.UserLevelNetworking != nil {
-				cmdArgs = append(cmdArgs, fmt.Sprintf("--user-level-networking=%v", *args.UserLevelNetworking))
-			}
-			if args.PerPodPowerManagement != nil {
-				cmdArgs = append(cmdArgs, fmt.Sprintf("--per-pod-power-management=%v", *args.PerPodPowerManagement))
-			}
-
-			// do not pass empty strings for optional args
-			if len(args.ProfileName) > 0 {
-				cmdArgs = append(cmdArgs, fmt.Sprintf("--profile-name=%s", args.ProfileName))
-			}
-			if len(args.PowerConsumptionMode) > 0 {
-				cmdArgs = append(cmdArgs, fmt.Sprintf("--power-consumption-mode=%s", args.PowerConsumptionMode))
-			}
-			if len(args.TMPolicy) > 0 {
-				cmdArgs = append(cmdArgs, fmt.Sprintf("--topology-manager-policy=%s", args.TMPolicy))
-			}
-
-			if args.OfflinedCPUCount > 0 {
-				cmdArgs = append(cmdArgs, fmt.Sprintf("--offlined-cpu-count=%d", args.OfflinedCPUCount))
-			}
-
+			cmdArgs := ppcCmdArgs(args)
 			out, err := testutils.ExecAndLogCommand(ppcPath, cmdArgs...)
 			Expect(err).To(BeNil(), "failed to run ppc for '%s': %v", expectedProfilePath, err)
 
@@ -211,6 +184,124 @@ var _ = Describe("[rfe_id:OCP-38968][ppc] Performance Profile Creator", func() {
 		Expect(ppcErrorString).To(ContainSubstring("failed to compute the reserved and isolated CPUs: please ensure that reserved-cpu-count plus offlined-cpu-count should be in the range"))
 	})
 
+	It("should explain the generated profile in explain mode", func() {
+		cmdArgs := append([]string{}, defaultArgs...)
+		cmdArgs = append(cmdArgs,
+			"--disable-ht=true",
+			"--reserved-cpu-count=5",
+			"--split-reserved-cpus-across-numa=true",
+			"--rt-kernel=false",
+			"--explain",
+		)
+		outData, _, err := testutils.ExecAndLogCommandWithStderr(ppcPath, cmdArgs...)
+		Expect(err).ToNot(HaveOccurred())
+
+		profile := &performancev2.PerformanceProfile{}
+		Expect(yaml.Unmarshal(outData, profile)).ToNot(HaveOccurred())
+		Expect(profile.Spec.CPU.Reserved).ToNot(BeNil())
+
+		out := string(outData)
+		Expect(out).To(ContainSubstring("# - topology: 2 NUMA cell(s)"))
+		Expect(out).To(ContainSubstring("# - SMT: hyperthreading is enabled on the nodes and --disable-ht is set"))
+		Expect(out).To(ContainSubstring("# - reserved: 5 CPU(s) can not be split equally across 2 NUMA cell(s)"))
+		Expect(out).To(ContainSubstring("# - isolated: "))
+	})
+
+	Context("with several MCPs", func() {
+		var outputDir string
+
+		BeforeEach(func() {
+			var err error
+			outputDir, err = ioutil.TempDir("", "ppc")
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(os.RemoveAll, outputDir)
+		})
+
+		It("should write a profile per MCP to the output directory", func() {
+			cmdArgs := []string{
+				"--mcp-name=worker-cnf,worker",
+				"--profile-name=performance",
+				"--reserved-cpu-count=4",
+				"--rt-kernel=false",
+				fmt.Sprintf("--must-gather-dir-path=%s", mustGatherFullPath),
+				fmt.Sprintf("--output-dir=%s", outputDir),
+			}
+			_, _, err := testutils.ExecAndLogCommandWithStderr(ppcPath, cmdArgs...)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, mcpName := range []string{"worker-cnf", "worker"} {
+				profileName := fmt.Sprintf("performance-%s", mcpName)
+				bytes, err := ioutil.ReadFile(filepath.Join(outputDir, fmt.Sprintf("%s.yaml", profileName)))
+				Expect(err).ToNot(HaveOccurred())
+
+				profile := &performancev2.PerformanceProfile{}
+				Expect(yaml.Unmarshal(bytes, profile)).ToNot(HaveOccurred())
+				Expect(profile.Name).To(Equal(profileName))
+				Expect(profile.Spec.MachineConfigPoolSelector).ToNot(BeEmpty())
+				Expect(profile.Spec.CPU.Reserved).ToNot(BeNil())
+				reserved, err := cpuset.Parse(string(*profile.Spec.CPU.Reserved))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(reserved.Size()).To(Equal(4))
+			}
+		})
+
+		It("should create the expected profile of every MCP", func() {
+			// every directory holds the arguments of a run and the profiles it is expected to write
+			cases, err := ioutil.ReadDir(expectedMultiMCPPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cases).ToNot(BeEmpty())
+
+			for _, c := range cases {
+				casePath := filepath.Join(expectedMultiMCPPath, c.Name())
+				bytes, err := ioutil.ReadFile(filepath.Join(casePath, "ppc-args.json"))
+				Expect(err).ToNot(HaveOccurred(), "failed to read the ppc params file for %q", casePath)
+
+				var args cmd.ProfileCreatorArgs
+				Expect(json.Unmarshal(bytes, &args)).To(Succeed(), "failed to decode the ppc params file for %q", casePath)
+				args.MustGatherDirPath = path.Join(mustGatherPath, args.MustGatherDirPath)
+
+				caseOutputDir := filepath.Join(outputDir, c.Name())
+				cmdArgs := append(ppcCmdArgs(args), fmt.Sprintf("--output-dir=%s", caseOutputDir))
+				_, _, err = testutils.ExecAndLogCommandWithStderr(ppcPath, cmdArgs...)
+				Expect(err).ToNot(HaveOccurred(), "failed to run ppc for %q", casePath)
+
+				expectedFiles, err := filepath.Glob(filepath.Join(casePath, "*.yaml"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(expectedFiles).To(HaveLen(len(strings.Split(args.MCPName, ","))), "expected a profile per MCP for %q", casePath)
+				outputFiles, err := filepath.Glob(filepath.Join(caseOutputDir, "*.yaml"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(outputFiles).To(HaveLen(len(expectedFiles)), "unexpected profiles written for %q", casePath)
+
+				for _, expectedFile := range expectedFiles {
+					expectedProfile := &performancev2.PerformanceProfile{}
+					bytes, err := ioutil.ReadFile(expectedFile)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(yaml.Unmarshal(bytes, expectedProfile)).To(Succeed())
+
+					profile := &performancev2.PerformanceProfile{}
+					bytes, err = ioutil.ReadFile(filepath.Join(caseOutputDir, filepath.Base(expectedFile)))
+					Expect(err).ToNot(HaveOccurred(), "expected profile %q not written", filepath.Base(expectedFile))
+					Expect(yaml.Unmarshal(bytes, profile)).To(Succeed())
+
+					Expect(profile).To(BeEquivalentTo(expectedProfile), "regression test failed for '%s' case", expectedFile)
+				}
+			}
+		})
+
+		It("should fail when an MCP is listed more than once", func() {
+			cmdArgs := []string{
+				"--mcp-name=worker-cnf,worker-cnf",
+				"--reserved-cpu-count=4",
+				"--rt-kernel=false",
+				fmt.Sprintf("--must-gather-dir-path=%s", mustGatherFullPath),
+				fmt.Sprintf("--output-dir=%s", outputDir),
+			}
+			_, errData, _ := testutils.ExecAndLogCommandWithStderr(ppcPath, cmdArgs...)
+			ppcErrorString := errorStringParser(errData)
+			Expect(ppcErrorString).To(ContainSubstring("'worker-cnf' MCP is listed more than once"))
+		})
+	})
+
 	Context("Systems with Hyperthreading disabled", func() {
 		It("[test_id:42035] verify PPC fails when splitting of reserved cpus and single numa-node policy is specified", func() {
 			ppcArgs := []string{
@@ -427,6 +518,41 @@ func getExpectedProfiles(expectedProfilesPath string, mustGatherDirs map[string]
 	return expectedProfiles
 }
 
+// ppcCmdArgs returns the command line arguments to run the performance profile creator with the arguments 'args'
+func ppcCmdArgs(args cmd.ProfileCreatorArgs) []string {
+	cmdArgs := []string{
+		fmt.Sprintf("--disable-ht=%v", args.DisableHT),
+		fmt.Sprintf("--mcp-name=%s", args.MCPName),
+		fmt.Sprintf("--must-gather-dir-path=%s", args.MustGatherDirPath),
+		fmt.Sprintf("--reserved-cpu-count=%d", args.ReservedCPUCount),
+		fmt.Sprintf("--rt-kernel=%v", args.RTKernel),
+		fmt.Sprintf("--split-reserved-cpus-across-numa=%v", args.SplitReservedCPUsAcrossNUMA),
+	}
+
+	if args.UserLevelNetworking != nil {
+		cmdArgs = append(cmdArgs, fmt.Sprintf("--user-level-networking=%v", *args.UserLevelNetworking))
+	}
+	if args.PerPodPowerManagement != nil {
+		cmdArgs = append(cmdArgs, fmt.Sprintf("--per-pod-power-management=%v", *args.PerPodPowerManagement))
+	}
+
+	// do not pass empty strings for optional args
+	if len(args.ProfileName) > 0 {
+		cmdArgs = append(cmdArgs, fmt.Sprintf("--profile-name=%s", args.ProfileName))
+	}
+	if len(args.PowerConsumptionMode) > 0 {
+		cmdArgs = append(cmdArgs, fmt.Sprintf("--power-consumption-mode=%s", args.PowerConsumptionMode))
+	}
+	if len(args.TMPolicy) > 0 {
+		cmdArgs = append(cmdArgs, fmt.Sprintf("--topology-manager-policy=%s", args.TMPolicy))
+	}
+
+	if args.OfflinedCPUCount > 0 {
+		cmdArgs = append(cmdArgs, fmt.Sprintf("--offlined-cpu-count=%d", args.OfflinedCPUCount))
+	}
+	return cmdArgs
+}
+
 // PPC stderr parser
 func errorStringParser(errData []byte) string {
 	stdError := string(errData)
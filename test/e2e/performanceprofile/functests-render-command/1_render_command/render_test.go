@@ -17,6 +17,8 @@ const (
 	pinnedExpectedDir       = "pinned"
 	bootstrapExpectedDir    = "bootstrap"
 	noRefExpectedDir        = "no-ref"
+	hypershiftExpectedDir   = "hypershift"
+	kustomizeExpectedDir    = "kustomize"
 	cpuFrequencyExpectedDir = defaultExpectedDir + "/" + "cpuFrequency"
 )
 
@@ -31,6 +33,7 @@ var (
 	bootstrapPPDir                         string
 	extraMCPDir                            string
 	containerRuntimeConfigDir              string
+	nodePoolDir                            string
 )
 
 var _ = Describe("render command e2e test", func() {
@@ -46,6 +49,7 @@ var _ = Describe("render command e2e test", func() {
 		snoLegacyPinnedDir = filepath.Join(workspaceDir, "test", "e2e", "performanceprofile", "cluster-setup", "pinned-cluster", "single-node-legacy")
 		testDataPath = filepath.Join(workspaceDir, "test", "e2e", "performanceprofile", "testdata")
 		containerRuntimeConfigDir = filepath.Join(workspaceDir, "test", "e2e", "performanceprofile", "cluster-setup", "container-runtime-crun")
+		nodePoolDir = filepath.Join(workspaceDir, "test", "e2e", "performanceprofile", "cluster-setup", "hypershift-cluster", "nodepool")
 		assetsInDirs = []string{assetsInDir, ppDir}
 		assetsCpuFrequencyInDirs = []string{assetsInDir, ppCpuFrequencyDir}
 	})
@@ -217,6 +221,43 @@ var _ = Describe("render command e2e test", func() {
 		})
 	})
 
+	Context("With HyperShift NodePool tuning ConfigMaps", func() {
+		It("should render the NodePool components", func() {
+
+			cmdline := []string{
+				filepath.Join(binPath, "cluster-node-tuning-operator"),
+				"render",
+				"--asset-input-dir", nodePoolDir,
+				"--asset-output-dir", assetsOutDir,
+			}
+			fmt.Fprintf(GinkgoWriter, "running: %v\n", cmdline)
+
+			cmd := exec.Command(cmdline[0], cmdline[1:]...)
+			runAndCompare(cmd, hypershiftExpectedDir)
+
+		})
+	})
+
+	Context("With the kustomize output format", func() {
+		It("should render a kustomize base and the per-pool overlays", func() {
+
+			bootstrapPPDirs := []string{bootstrapPPDir, defaultPinnedDir, extraMCPDir}
+
+			cmdline := []string{
+				filepath.Join(binPath, "cluster-node-tuning-operator"),
+				"render",
+				"--asset-input-dir", strings.Join(bootstrapPPDirs, ","),
+				"--asset-output-dir", assetsOutDir,
+				"--output-format", "kustomize",
+			}
+			fmt.Fprintf(GinkgoWriter, "running: %v\n", cmdline)
+
+			cmd := exec.Command(cmdline[0], cmdline[1:]...)
+			runAndCompare(cmd, kustomizeExpectedDir)
+
+		})
+	})
+
 	AfterEach(func() {
 		cleanArtifacts()
 	})
@@ -238,25 +279,31 @@ func runAndCompare(cmd *exec.Cmd, dir string) {
 	_, err := cmd.Output()
 	Expect(err).ToNot(HaveOccurred(), logStderr(err))
 
-	outputAssetsFiles, err := os.ReadDir(assetsOutDir)
-	Expect(err).ToNot(HaveOccurred())
-
 	refPath := filepath.Join(testDataPath, "render-expected-output", dir)
 	fmt.Fprintf(GinkgoWriter, "reference data at: %q\n", refPath)
 
-	for _, f := range outputAssetsFiles {
-		refData, err := os.ReadFile(filepath.Join(refPath, f.Name()))
+	// the kustomize output format writes the manifests to subdirectories
+	err = filepath.WalkDir(assetsOutDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(assetsOutDir, path)
 		Expect(err).ToNot(HaveOccurred())
 
-		data, err := os.ReadFile(filepath.Join(assetsOutDir, f.Name()))
+		refData, err := os.ReadFile(filepath.Join(refPath, name))
+		Expect(err).ToNot(HaveOccurred())
+
+		data, err := os.ReadFile(path)
 		Expect(err).ToNot(HaveOccurred())
 
 		diff, err := getFilesDiff(data, refData)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(diff).To(BeZero(), "rendered %s file is not identical to its reference file; diff: %v",
-			f.Name(),
+			name,
 			diff)
-	}
+		return nil
+	})
+	Expect(err).ToNot(HaveOccurred())
 }
 
 func logStderr(err error) string {
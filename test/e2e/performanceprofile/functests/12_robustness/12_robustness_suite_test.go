@@ -0,0 +1,17 @@
+package __robustness_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/conformance"
+)
+
+var _ = conformance.RegisterRobustness(conformance.Config{})
+
+func TestRobustness(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Performance Profile Robustness")
+}
@@ -1327,15 +1327,28 @@ func checkSchedKnobs(ctx context.Context, workerNodes []corev1.Node, schedKnobs
 func validateTunedActiveProfile(ctx context.Context, wrknodes []corev1.Node) {
 	var err error
 	var out []byte
-	activeProfileName := components.GetComponentName(testutils.PerformanceProfileName, components.ProfileNamePerformance)
+	paoTunedName := components.GetComponentName(testutils.PerformanceProfileName, components.ProfileNamePerformance)
 
-	// check if some another Tuned profile overwrites PAO profile
+	// the PAO profile is named after its content hash, the PAO Tuned recommends its latest generation
+	paoTuned := &tunedv1.Tuned{}
+	key := types.NamespacedName{Name: paoTunedName, Namespace: components.NamespaceNodeTuningOperator}
+	err = testclient.Client.Get(ctx, key, paoTuned)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(paoTuned.Spec.Recommend).NotTo(BeEmpty())
+	Expect(paoTuned.Spec.Recommend[0].Profile).NotTo(BeNil())
+	activeProfileName := *paoTuned.Spec.Recommend[0].Profile
+
+	// check if some another Tuned profile overwrites PAO profile, either by its hashed name
+	// or by the name without the hash including the latest generation
 	tunedList := &tunedv1.TunedList{}
 	err = testclient.Client.List(ctx, tunedList)
 	Expect(err).NotTo(HaveOccurred())
 
 	for _, t := range tunedList.Items {
-		if len(t.Spec.Profile) > 0 && t.Spec.Profile[0].Data != nil && strings.Contains(*t.Spec.Profile[0].Data, fmt.Sprintf("include=%s", activeProfileName)) {
+		if t.Name == paoTunedName {
+			continue
+		}
+		if len(t.Spec.Profile) > 0 && t.Spec.Profile[0].Data != nil && strings.Contains(*t.Spec.Profile[0].Data, fmt.Sprintf("include=%s", paoTunedName)) {
 			testlog.Warning(fmt.Sprintf("PAO tuned profile amended by '%s' profile, test may fail", t.Name))
 			if t.Spec.Profile[0].Name != nil {
 				activeProfileName = *t.Spec.Profile[0].Name
@@ -0,0 +1,121 @@
+// Package conformance packages the latency and the robustness checks of the performance profile functional tests,
+// so they run against any tuned cluster from an external test suite:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, conformance.Config{
+//			NodeSelector: map[string]string{"node-role.kubernetes.io/worker-cnf": ""},
+//			Latency: &conformance.LatencyConfig{
+//				Runtime:          600,
+//				MaximumLatencies: map[string]int{conformance.Oslat: 20, conformance.Cyclictest: 20},
+//			},
+//			Robustness: true,
+//		})
+//	}
+//
+// The suite reaches the cluster of the KUBECONFIG environment variable and creates its pods in the
+// testutils.NamespaceTesting namespace.
+package conformance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	testutils "github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils"
+	testclient "github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/client"
+	testlog "github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/log"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/namespaces"
+)
+
+const (
+	// Oslat is the name of the oslat latency tool
+	Oslat = "oslat"
+	// Cyclictest is the name of the cyclictest latency tool
+	Cyclictest = "cyclictest"
+	// Hwlatdetect is the name of the hwlatdetect latency tool
+	Hwlatdetect = "hwlatdetect"
+
+	defaultRecoveryTimeout = 10 * time.Minute
+)
+
+// Config configures the conformance checks
+type Config struct {
+	// NodeSelector selects the nodes of the performance profile under test,
+	// defaults to the node selector of the ROLE_WORKER_CNF role, or of the discovered profile
+	NodeSelector map[string]string
+	// Latency configures the latency checks, when nil the checks read the LATENCY_TEST_* and *_MAXIMUM_LATENCY
+	// environment variables
+	Latency *LatencyConfig
+	// Robustness enables the robustness checks, which restart the TuneD daemon of a node and the operator
+	// and verify the tuning recovers
+	Robustness bool
+	// RecoveryTimeout bounds the time the tuning takes to recover from a disruption, defaults to 10 minutes
+	RecoveryTimeout time.Duration
+}
+
+// LatencyConfig configures the latency checks, the zero values select the defaults
+type LatencyConfig struct {
+	// Runtime is the time in seconds every latency tool runs, defaults to 300
+	Runtime int
+	// Delay is the time in seconds the latency tools wait before they start, to give time to the CPU manager
+	// reconcile loop to update the default CPU pool
+	Delay int
+	// CPUs is the number of CPUs of the latency test pod, defaults to the number of isolated CPUs minus one
+	CPUs int
+	// MaximumLatencies maps the latency tools to the maximum latency they may measure, in microseconds.
+	// The latency of the tools without a maximum is not checked.
+	MaximumLatencies map[string]int
+}
+
+// Register registers the latency checks and, when enabled, the robustness checks of the configuration 'cfg'.
+// It must be called before ginkgo.RunSpecs, e.g. from a package level variable declaration.
+func Register(cfg Config) bool {
+	RegisterLatency(cfg)
+	if cfg.Robustness {
+		RegisterRobustness(cfg)
+	}
+	return true
+}
+
+// Run runs the conformance checks of the configuration 'cfg' as a standalone ginkgo suite of the test 't',
+// creating the testing namespace before the checks and deleting it after.
+func Run(t *testing.T, cfg Config) {
+	Register(cfg)
+
+	BeforeSuite(func() {
+		Expect(testclient.ClientsEnabled).To(BeTrue(), "failed to create the cluster clients")
+		err := testclient.Client.Create(context.TODO(), namespaces.TestingNamespace)
+		if errors.IsAlreadyExists(err) {
+			testlog.Warning("test namespace already exists, that is unexpected")
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterSuite(func() {
+		Expect(testclient.Client.Delete(context.TODO(), namespaces.TestingNamespace)).To(Succeed())
+		Expect(namespaces.WaitForDeletion(testutils.NamespaceTesting, 5*time.Minute)).To(Succeed())
+	})
+
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Node tuning conformance")
+}
+
+func (cfg *Config) getNodeSelector() map[string]string {
+	if len(cfg.NodeSelector) > 0 {
+		return cfg.NodeSelector
+	}
+	return testutils.NodeSelectorLabels
+}
+
+func (cfg *Config) getRecoveryTimeout() time.Duration {
+	if cfg.RecoveryTimeout > 0 {
+		return cfg.RecoveryTimeout
+	}
+	return defaultRecoveryTimeout
+}
@@ -0,0 +1,551 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	performancev2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	testutils "github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils"
+	testclient "github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/client"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/discovery"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/events"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/images"
+	testlog "github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/log"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/nodes"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/pods"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/profiles"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/cpuset"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultTestDelay     = 0
+	defaultTestRuntime   = "300"
+	defaultMaxLatency    = -1
+	defaultTestCpus      = -1
+	minCpuAmountForOslat = 2
+)
+
+// latencySettings are the settings of a latency check, resolved from the configuration or the environment
+type latencySettings struct {
+	delay          int
+	runtime        string
+	cpus           int
+	maximumLatency int
+}
+
+// LATENCY_TEST_DELAY delay the run of the binary, can be useful to give time to the CPU manager reconcile loop
+// to update the default CPU pool
+// LATENCY_TEST_RUNTIME: the amount of time in seconds that the latency test should run
+// LATENCY_TEST_CPUS: the amount of CPUs the pod which run the latency test should request
+
+// RegisterLatency registers the latency checks of the configuration 'cfg', which run the oslat, cyclictest and
+// hwlatdetect tools on the isolated CPUs of a node of the profile under test.
+// It must be called before ginkgo.RunSpecs, e.g. from a package level variable declaration.
+func RegisterLatency(cfg Config) bool {
+	return Describe("[performance] Latency Test", Ordered, func() {
+		registerLatencySpecs(&cfg)
+	})
+}
+
+func registerLatencySpecs(cfg *Config) {
+	var workerRTNode *corev1.Node
+	var profile *performancev2.PerformanceProfile
+	var latencyTestPod *corev1.Pod
+	var settings *latencySettings
+	var err error
+
+	BeforeEach(func() {
+		settings, err = cfg.getLatencySettings()
+		Expect(err).ToNot(HaveOccurred())
+
+		if discovery.Enabled() && testutils.ProfileNotFound {
+			Skip("Discovery mode enabled, performance profile not found")
+		}
+
+		profile, err = profiles.GetByNodeLabels(cfg.getNodeSelector())
+		Expect(err).ToNot(HaveOccurred())
+
+		if isOddCpuNumber(settings.cpus, profile) {
+			Skip("Skip the test, the requested number of CPUs should be even to avoid noisy neighbor situation")
+		}
+
+		workerRTNodes, err := nodes.GetByLabels(cfg.getNodeSelector())
+		Expect(err).ToNot(HaveOccurred())
+
+		workerRTNodes, err = nodes.MatchingOptionalSelector(workerRTNodes)
+		Expect(err).ToNot(HaveOccurred(), "error looking for the optional selector: %v", err)
+
+		Expect(workerRTNodes).ToNot(BeEmpty())
+
+		//At least one worker node should have cpu.Allocatable greater than the quantity requested by each test, else skip the test
+		workerRTNodesWithSufficientCpu := nodes.GetByCpuAllocatable(workerRTNodes, settings.cpus)
+		if len(workerRTNodesWithSufficientCpu) == 0 {
+			Skip("Insufficient cpu to run the test")
+
+		}
+		workerRTNode = &workerRTNodesWithSufficientCpu[0]
+
+	})
+
+	AfterEach(func() {
+		if latencyTestPod != nil {
+			err = testclient.Client.Delete(context.TODO(), latencyTestPod)
+			if err != nil {
+				testlog.Error(err)
+			}
+
+			err = pods.WaitForDeletion(context.TODO(), latencyTestPod, pods.DefaultDeletionTimeout*time.Second)
+			if err != nil {
+				testlog.Error(err)
+			}
+		}
+	})
+
+	Context("with the oslat image", func() {
+		testName := Oslat
+
+		BeforeEach(func() {
+			settings.maximumLatency, err = cfg.getMaximumLatency(testName)
+			Expect(err).ToNot(HaveOccurred())
+
+			if profile.Spec.CPU.Isolated == nil {
+				Skip(fmt.Sprintf("Skip the oslat test, the profile %q does not have isolated CPUs", profile.Name))
+			}
+
+			isolatedCpus, err := cpuset.Parse(string(*profile.Spec.CPU.Isolated))
+			Expect(err).ToNot(HaveOccurred(), "failed to parse cpus %q", string(*profile.Spec.CPU.Isolated))
+			// we require at least two CPUs to run oslat test, because one CPU should be used to run the main oslat thread
+			// we can not use all isolated CPUs, because if reserved and isolated include all node CPUs, and reserved CPUs
+			// do not calculated into the Allocated, at least part of time of one of isolated CPUs will be used to run
+			// other node containers
+			// at least two isolated CPUs to run oslat + one isolated CPU used by other containers on the node = at least 3 isolated CPUs
+			if isolatedCpus.Size() < (minCpuAmountForOslat + 1) {
+				Skip(fmt.Sprintf("Skip the oslat test, the profile %q has less than %d isolated CPUs", profile.Name, minCpuAmountForOslat))
+			}
+			if settings.cpus < minCpuAmountForOslat && settings.cpus != defaultTestCpus {
+				Skip(fmt.Sprintf("Skip the oslat test, LATENCY_TEST_CPUS is less than the minimum CPUs amount %d", minCpuAmountForOslat))
+			}
+		})
+
+		It("should succeed", func() {
+			defer func() {
+				logs, err := pods.GetLogs(testclient.K8sClient, latencyTestPod)
+				Expect(err).ToNot(HaveOccurred())
+				testlog.Infof(logs)
+			}()
+			oslatArgs := []string{
+				fmt.Sprintf("-runtime=%s", settings.runtime),
+			}
+			latencyTestPod = getLatencyTestPod(profile, workerRTNode, testName, oslatArgs, settings)
+			createLatencyTestPod(latencyTestPod, settings)
+
+			// verify the maximum latency only when it requested, because this value can be very different
+			// on different systems
+			if settings.maximumLatency == -1 {
+				Skip("no maximum latency value provided, skip buckets latency check")
+			}
+
+			latencies := extractLatencyValues(`Maximum:\t*([\s\d]*)\(us\)`, latencyTestPod)
+			latenciesList := strings.Split(latencies, " ")
+			for _, lat := range latenciesList {
+				if lat == "" {
+					continue
+				}
+				curr, err := strconv.Atoi(lat)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(curr < settings.maximumLatency).To(BeTrue(), "The current latency %d is bigger than the expected one %d", curr, settings.maximumLatency)
+			}
+		})
+	})
+
+	Context("with the cyclictest image", func() {
+		testName := Cyclictest
+
+		BeforeEach(func() {
+			settings.maximumLatency, err = cfg.getMaximumLatency(testName)
+			Expect(err).ToNot(HaveOccurred())
+
+			if profile.Spec.CPU.Isolated == nil {
+				Skip(fmt.Sprintf("Skip the cyclictest test, the profile %q does not have isolated CPUs", profile.Name))
+			}
+		})
+
+		It("should succeed", func() {
+			defer func() {
+				logs, err := pods.GetLogs(testclient.K8sClient, latencyTestPod)
+				Expect(err).ToNot(HaveOccurred())
+				testlog.Infof(logs)
+			}()
+			cyclictestArgs := []string{
+				fmt.Sprintf("-duration=%s", settings.runtime),
+			}
+			latencyTestPod = getLatencyTestPod(profile, workerRTNode, testName, cyclictestArgs, settings)
+			createLatencyTestPod(latencyTestPod, settings)
+
+			// verify the maximum latency only when it requested, because this value can be very different
+			// on different systems
+			if settings.maximumLatency == -1 {
+				Skip("no maximum latency value provided, skip buckets latency check")
+			}
+			latencies := extractLatencyValues(`# Max Latencies:\t*\s*(.*)\s*\t*`, latencyTestPod)
+			for _, lat := range strings.Split(latencies, " ") {
+				if lat == "" {
+					continue
+				}
+
+				curr, err := strconv.Atoi(lat)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(curr < settings.maximumLatency).To(BeTrue(), "The current latency %d is bigger than the expected one %d", curr, settings.maximumLatency)
+			}
+		})
+	})
+
+	Context("with the hwlatdetect image", func() {
+		testName := Hwlatdetect
+
+		BeforeEach(func() {
+			settings.maximumLatency, err = cfg.getMaximumLatency(testName)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should succeed", func() {
+			defer func() {
+				logs, err := pods.GetLogs(testclient.K8sClient, latencyTestPod)
+				Expect(err).ToNot(HaveOccurred())
+				testlog.Infof(logs)
+			}()
+			hardLimit := settings.maximumLatency
+			if hardLimit == -1 {
+				// This value should be > than max latency,
+				// in order to prevent the hwlatdetect return with error 1 in case latency value is bigger than expected.
+				// in case latency value is bigger than expected, it will be handled on different flow.
+				hardLimit = 1000
+			}
+
+			hwlatdetectArgs := []string{
+				fmt.Sprintf("-hardlimit=%d", hardLimit),
+				fmt.Sprintf("-duration=%s", settings.runtime),
+			}
+
+			// set the maximum latency for the test if needed
+			if settings.maximumLatency != -1 {
+				hwlatdetectArgs = append(hwlatdetectArgs, fmt.Sprintf("-threshold=%d", settings.maximumLatency))
+			}
+
+			latencyTestPod = getLatencyTestPod(profile, workerRTNode, testName, hwlatdetectArgs, settings)
+			createLatencyTestPod(latencyTestPod, settings)
+			// here we don't need to parse the latency values.
+			// hwlatdetect will do that for us and exit with error if needed.
+		})
+	})
+}
+
+// getLatencySettings returns the settings of the latency checks out of the configuration,
+// or out of the environment variables when the configuration does not set them
+func (cfg *Config) getLatencySettings() (*latencySettings, error) {
+	if cfg.Latency == nil {
+		delay, err := getLatencyTestDelay()
+		if err != nil {
+			return nil, err
+		}
+		cpus, err := getLatencyTestCpus()
+		if err != nil {
+			return nil, err
+		}
+		runtime, err := getLatencyTestRuntime()
+		if err != nil {
+			return nil, err
+		}
+		return &latencySettings{delay: delay, runtime: runtime, cpus: cpus, maximumLatency: defaultMaxLatency}, nil
+	}
+
+	settings := &latencySettings{
+		delay:          cfg.Latency.Delay,
+		runtime:        defaultTestRuntime,
+		cpus:           defaultTestCpus,
+		maximumLatency: defaultMaxLatency,
+	}
+	if cfg.Latency.Delay < 0 {
+		return nil, fmt.Errorf("the latency test delay %d must be a non-negative integer", cfg.Latency.Delay)
+	}
+	if cfg.Latency.Runtime < 0 {
+		return nil, fmt.Errorf("the latency test runtime %d must be a positive integer", cfg.Latency.Runtime)
+	}
+	if cfg.Latency.Runtime > 0 {
+		settings.runtime = strconv.Itoa(cfg.Latency.Runtime)
+	}
+	if cfg.Latency.CPUs < 0 {
+		return nil, fmt.Errorf("the latency test CPUs %d must be a positive integer", cfg.Latency.CPUs)
+	}
+	if cfg.Latency.CPUs > 0 {
+		settings.cpus = cfg.Latency.CPUs
+	}
+	return settings, nil
+}
+
+// getMaximumLatency returns the maximum latency of the tool 'testName' out of the configuration,
+// or out of the environment variables when the configuration does not set the latency checks
+func (cfg *Config) getMaximumLatency(testName string) (int, error) {
+	if cfg.Latency == nil {
+		return getMaximumLatency(testName)
+	}
+
+	val, ok := cfg.Latency.MaximumLatencies[testName]
+	if !ok {
+		return defaultMaxLatency, nil
+	}
+	if val < 0 {
+		return val, fmt.Errorf("the %s maximum latency %d must be a non-negative integer", testName, val)
+	}
+	return val, nil
+}
+
+func getLatencyTestRuntime() (string, error) {
+	if latencyTestRuntimeEnv, ok := os.LookupEnv("LATENCY_TEST_RUNTIME"); ok {
+		val, err := strconv.Atoi(latencyTestRuntimeEnv)
+		if err != nil {
+			return latencyTestRuntimeEnv, fmt.Errorf("the environment variable LATENCY_TEST_RUNTIME has incorrect value %q, it must be a positive integer with maximum value of %d", latencyTestRuntimeEnv, math.MaxInt32)
+		}
+		if val < 1 || val > math.MaxInt32 {
+			return "", fmt.Errorf("the environment variable LATENCY_TEST_RUNTIME has an invalid number %q, it must be a positive integer with maximum value of %d", latencyTestRuntimeEnv, math.MaxInt32)
+		}
+		return latencyTestRuntimeEnv, nil
+	}
+	return defaultTestRuntime, nil
+}
+
+func getLatencyTestDelay() (int, error) {
+	if latencyTestDelayEnv, ok := os.LookupEnv("LATENCY_TEST_DELAY"); ok {
+		val, err := strconv.Atoi(latencyTestDelayEnv)
+		if err != nil {
+			return val, fmt.Errorf("the environment variable LATENCY_TEST_DELAY has incorrect value %q, it must be a non-negative integer with maximum value of %d: %w", latencyTestDelayEnv, math.MaxInt32, err)
+		}
+		if val < 0 || val > math.MaxInt32 {
+			return val, fmt.Errorf("the environment variable LATENCY_TEST_DELAY has an invalid number %q, it must be a non-negative integer with maximum value of %d", latencyTestDelayEnv, math.MaxInt32)
+		}
+		return val, nil
+	}
+	return defaultTestDelay, nil
+}
+
+func getLatencyTestCpus() (int, error) {
+	if latencyTestCpusEnv, ok := os.LookupEnv("LATENCY_TEST_CPUS"); ok {
+		val, err := strconv.Atoi(latencyTestCpusEnv)
+		if err != nil {
+			return val, fmt.Errorf("the environment variable LATENCY_TEST_CPUS has incorrect value %q, it must be a positive integer with maximum value of %d: %w", latencyTestCpusEnv, math.MaxInt32, err)
+		}
+		if val <= 0 || val > math.MaxInt32 {
+			return val, fmt.Errorf("the environment variable LATENCY_TEST_CPUS has an invalid number %q, it must be a positive integer with maximum value of %d", latencyTestCpusEnv, math.MaxInt32)
+		}
+		return val, nil
+	}
+	return defaultTestCpus, nil
+}
+
+// getMaximumLatency should look for one of the following environment variables:
+// OSLAT_MAXIMUM_LATENCY: the expected maximum latency for all buckets in us
+// CYCLICTEST_MAXIMUM_LATENCY: the expected maximum latency for all buckets in us
+// HWLATDETECT_MAXIMUM_LATENCY: the expected maximum latency for all buckets in us
+// MAXIMUM_LATENCY: unified expected maximum latency for all tests
+func getMaximumLatency(testName string) (int, error) {
+	var err error
+	val := defaultMaxLatency
+	if unifiedMaxLatencyEnv, ok := os.LookupEnv("MAXIMUM_LATENCY"); ok {
+		val, err = strconv.Atoi(unifiedMaxLatencyEnv)
+		if err != nil {
+			return val, fmt.Errorf("the environment variable MAXIMUM_LATENCY has incorrect value %q, it must be a non-negative integer with maximum value of %d: %w", unifiedMaxLatencyEnv, math.MaxInt32, err)
+		}
+		if val < 0 || val > math.MaxInt32 {
+			return val, fmt.Errorf("the environment variable MAXIMUM_LATENCY has an invalid number %q, it must be a non-negative integer with maximum value of %d", unifiedMaxLatencyEnv, math.MaxInt32)
+		}
+	}
+
+	// specific values will have precedence over the general one
+	envVariableName := fmt.Sprintf("%s_MAXIMUM_LATENCY", strings.ToUpper(testName))
+	if maximumLatencyEnv, ok := os.LookupEnv(envVariableName); ok {
+		val, err = strconv.Atoi(maximumLatencyEnv)
+		if err != nil {
+			err = fmt.Errorf("the environment variable %q has incorrect value %q, it must be a non-negative integer with maximum value of %d: %w", envVariableName, maximumLatencyEnv, math.MaxInt32, err)
+		}
+		if val < 0 || val > math.MaxInt32 {
+			err = fmt.Errorf("the environment variable %q has an invalid number %q, it must be a non-negative integer with maximum value of %d", envVariableName, maximumLatencyEnv, math.MaxInt32)
+		}
+	}
+	return val, err
+}
+
+func getLatencyTestPod(profile *performancev2.PerformanceProfile, node *corev1.Node, testName string, testSpecificArgs []string, settings *latencySettings) *corev1.Pod {
+	runtimeClass := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+	testNamePrefix := fmt.Sprintf("%s-", testName)
+	runnerName := fmt.Sprintf("%srunner", testNamePrefix)
+	runnerPath := path.Join("usr", "bin", runnerName)
+
+	if settings.cpus == defaultTestCpus {
+		// we can not use all isolated CPUs, because if reserved and isolated include all node CPUs, and reserved CPUs
+		// do not calculated into the Allocated, at least part of time of one of isolated CPUs will be used to run
+		// other node containers
+		cpus, err := cpuset.Parse(string(*profile.Spec.CPU.Isolated))
+		Expect(err).ToNot(HaveOccurred(), "failed to parse cpus %q", string(*profile.Spec.CPU.Isolated))
+		settings.cpus = cpus.Size() - 1
+	}
+
+	latencyTestRunnerArgs := []string{
+		"-logtostderr=false",
+		"-alsologtostderr=true",
+	}
+
+	latencyTestRunnerArgs = append(latencyTestRunnerArgs, testSpecificArgs...)
+
+	if settings.delay > 0 {
+		latencyTestRunnerArgs = append(latencyTestRunnerArgs, fmt.Sprintf("-%s-start-delay=%d", testName, settings.delay))
+	}
+
+	volumeTypeDirectory := corev1.HostPathDirectory
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: testNamePrefix,
+			Annotations: map[string]string{
+				"irq-load-balancing.crio.io": "disable",
+				"cpu-load-balancing.crio.io": "disable",
+				"cpu-quota.crio.io":          "disable",
+			},
+			Namespace: testutils.NamespaceTesting,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:    corev1.RestartPolicyNever,
+			RuntimeClassName: &runtimeClass,
+			Containers: []corev1.Container{
+				{
+					Name:  runnerName,
+					Image: images.Test(),
+					Command: []string{
+						runnerPath,
+					},
+					Args: latencyTestRunnerArgs,
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse(strconv.Itoa(settings.cpus)),
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+					SecurityContext: &corev1.SecurityContext{
+						Privileged: pointer.Bool(true),
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      "logs",
+							MountPath: "/host",
+						},
+					},
+				},
+			},
+			NodeSelector: map[string]string{
+				"kubernetes.io/hostname": node.Labels["kubernetes.io/hostname"],
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "logs",
+					VolumeSource: corev1.VolumeSource{
+						HostPath: &corev1.HostPathVolumeSource{
+							Path: "/var/log",
+							Type: &volumeTypeDirectory,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func logEventsForPod(testPod *corev1.Pod) {
+	events, err := events.GetEventsForObject(testclient.Client, testPod.Namespace, testPod.Name, string(testPod.UID))
+	if err != nil {
+		testlog.Error(err)
+	}
+	testlog.Infof("log pod %s/%s events due to failure", testPod.Namespace, testPod.Name)
+	for _, event := range events.Items {
+		testlog.Warningf("-> %s %s %s", event.Action, event.Reason, event.Message)
+	}
+}
+
+func createLatencyTestPod(testPod *corev1.Pod, settings *latencySettings) {
+	err := testclient.Client.Create(context.TODO(), testPod)
+	Expect(err).ToNot(HaveOccurred())
+
+	timeout, err := strconv.Atoi(settings.runtime)
+	Expect(err).ToNot(HaveOccurred())
+
+	By("Waiting two minutes to download the latencyTest image")
+	podKey := fmt.Sprintf("%s/%s", testPod.Namespace, testPod.Name)
+	currentPod, err := pods.WaitForPredicate(context.TODO(), client.ObjectKeyFromObject(testPod), 2*time.Minute, func(pod *corev1.Pod) (bool, error) {
+		if pod.Status.Phase == corev1.PodRunning {
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		logEventsForPod(testPod)
+	}
+	Expect(err).ToNot(HaveOccurred(), "pod %q did not reach %q phase; current phase %q", podKey, corev1.PodRunning, currentPod.Status.Phase)
+
+	if runtime, _ := strconv.Atoi(settings.runtime); runtime > 1 {
+		By("Checking actual CPUs number for the running pod")
+		limitsCpusQuantity := testPod.Spec.Containers[0].Resources.Limits.Cpu()
+		RequestsCpusQuantity := testPod.Spec.Containers[0].Resources.Requests.Cpu()
+		//latency pod is guaranteed
+		Expect(isEqual(limitsCpusQuantity, settings.cpus)).To(BeTrue(), fmt.Sprintf("actual limits of cpus number used for the latency pod is not as set in LATENCY_TEST_CPUS, actual number is: %s", limitsCpusQuantity))
+		Expect(isEqual(RequestsCpusQuantity, settings.cpus)).To(BeTrue(), fmt.Sprintf("actual requests of cpus number used for the latency pod is not as set in LATENCY_TEST_CPUS, actual number is: %s", RequestsCpusQuantity))
+	}
+
+	By("Waiting another two minutes to give enough time for the cluster to move the pod to Succeeded phase")
+	podTimeout := time.Duration(timeout + settings.delay + 120)
+	testPod, err = pods.WaitForPhase(context.TODO(), client.ObjectKeyFromObject(testPod), corev1.PodSucceeded, podTimeout*time.Second)
+	if err != nil {
+		logEventsForPod(testPod)
+	}
+	Expect(err).ToNot(HaveOccurred(), "pod %q did not reach %q phase; error: %v", podKey, corev1.PodSucceeded, err)
+}
+
+func extractLatencyValues(exp string, pod *corev1.Pod) string {
+	out, err := pods.GetLogs(testclient.K8sClient, pod)
+	Expect(err).ToNot(HaveOccurred())
+
+	maximumRegex, err := regexp.Compile(exp)
+	Expect(err).ToNot(HaveOccurred())
+
+	latencies := maximumRegex.FindStringSubmatch(out)
+	Expect(len(latencies)).To(Equal(2))
+
+	return latencies[1]
+}
+
+func isEqual(qty *resource.Quantity, amount int) bool {
+	return qty.CmpInt64(int64(amount)) == 0
+}
+
+func isOddCpuNumber(cpusNum int, profile *performancev2.PerformanceProfile) bool {
+	if cpusNum == defaultTestCpus {
+		isolatedCpus, err := cpuset.Parse(string(*profile.Spec.CPU.Isolated))
+		Expect(err).ToNot(HaveOccurred(), "failed to parse cpus %q", string(*profile.Spec.CPU.Isolated))
+		isolatedCpusNum := isolatedCpus.Size() - 1
+		return isolatedCpusNum%2 != 0
+	}
+	return cpusNum%2 != 0
+}
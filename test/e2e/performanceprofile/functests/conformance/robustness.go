@@ -0,0 +1,167 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	testutils "github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils"
+	testclient "github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/client"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/discovery"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/label"
+	testlog "github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/log"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/namespaces"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/nodes"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/pods"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/profiles"
+	"github.com/openshift/cluster-node-tuning-operator/test/e2e/performanceprofile/functests/utils/tuned"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// tuningStateCommands read the node state the generated TuneD profile sets
+var tuningStateCommands = [][]string{
+	{"cat", "/proc/cmdline"},
+	{"cat", "/proc/irq/default_smp_affinity"},
+	{"sysctl", "-n", "kernel.timer_migration", "kernel.hung_task_timeout_secs", "kernel.nmi_watchdog", "vm.stat_interval"},
+}
+
+// RegisterRobustness registers the robustness checks of the configuration 'cfg', which restart the TuneD daemon of
+// a node of the profile under test and the operator, and verify the node tuning recovers unchanged.
+// It must be called before ginkgo.RunSpecs, e.g. from a package level variable declaration.
+func RegisterRobustness(cfg Config) bool {
+	return Describe("[performance] Robustness Test", Ordered, Label(string(label.Robustness), string(label.Tier3)), func() {
+		registerRobustnessSpecs(&cfg)
+	})
+}
+
+func registerRobustnessSpecs(cfg *Config) {
+	var workerRTNode *corev1.Node
+
+	BeforeEach(func() {
+		if discovery.Enabled() && testutils.ProfileNotFound {
+			Skip("Discovery mode enabled, performance profile not found")
+		}
+
+		_, err := profiles.GetByNodeLabels(cfg.getNodeSelector())
+		Expect(err).ToNot(HaveOccurred())
+
+		workerRTNodes, err := nodes.GetByLabels(cfg.getNodeSelector())
+		Expect(err).ToNot(HaveOccurred())
+
+		workerRTNodes, err = nodes.MatchingOptionalSelector(workerRTNodes)
+		Expect(err).ToNot(HaveOccurred(), "error looking for the optional selector: %v", err)
+
+		Expect(workerRTNodes).ToNot(BeEmpty())
+		workerRTNode = &workerRTNodes[0]
+	})
+
+	It("should restore the node tuning after the TuneD daemon restarts", func() {
+		ctx := context.TODO()
+		waitForTunedProfileApplied(ctx, workerRTNode, cfg.getRecoveryTimeout())
+		expected := getTuningState(ctx, workerRTNode)
+
+		tunedPod, err := tuned.GetPod(ctx, workerRTNode)
+		Expect(err).ToNot(HaveOccurred())
+
+		By(fmt.Sprintf("Deleting the TuneD pod %s/%s of the node %q", tunedPod.Namespace, tunedPod.Name, workerRTNode.Name))
+		Expect(testclient.Client.Delete(ctx, tunedPod)).To(Succeed())
+		Expect(pods.WaitForDeletion(ctx, tunedPod, cfg.getRecoveryTimeout())).To(Succeed())
+
+		waitForTunedProfileApplied(ctx, workerRTNode, cfg.getRecoveryTimeout())
+		Expect(getTuningState(ctx, workerRTNode)).To(Equal(expected), "the node %q tuning changed after the TuneD daemon restart", workerRTNode.Name)
+	})
+
+	It("should keep the profile available after the operator restarts", func() {
+		ctx := context.TODO()
+		waitForTunedProfileApplied(ctx, workerRTNode, cfg.getRecoveryTimeout())
+		expected := getTuningState(ctx, workerRTNode)
+
+		operatorPods := &corev1.PodList{}
+		opts := &client.ListOptions{
+			Namespace:     namespaces.PerformanceOperator,
+			LabelSelector: labels.SelectorFromSet(labels.Set{"name": "cluster-node-tuning-operator"}),
+		}
+		Expect(testclient.Client.List(ctx, operatorPods, opts)).To(Succeed())
+		Expect(operatorPods.Items).ToNot(BeEmpty(), "failed to find the operator pods")
+
+		for i := range operatorPods.Items {
+			pod := &operatorPods.Items[i]
+			By(fmt.Sprintf("Deleting the operator pod %s/%s", pod.Namespace, pod.Name))
+			Expect(testclient.Client.Delete(ctx, pod)).To(Succeed())
+			Expect(pods.WaitForDeletion(ctx, pod, cfg.getRecoveryTimeout())).To(Succeed())
+		}
+
+		By("Waiting for the operator to run again")
+		Eventually(func() bool {
+			if err := testclient.Client.List(ctx, operatorPods, opts); err != nil {
+				testlog.Warningf("failed to list the operator pods: %v", err)
+				return false
+			}
+			for _, pod := range operatorPods.Items {
+				if pod.DeletionTimestamp == nil && isPodReady(&pod) {
+					return true
+				}
+			}
+			return false
+		}).WithPolling(10*time.Second).WithTimeout(cfg.getRecoveryTimeout()).Should(BeTrue(), "the operator did not run again")
+
+		By("Verifying the performance profile stays available")
+		Consistently(func() corev1.ConditionStatus {
+			cond := profiles.GetCondition(cfg.getNodeSelector(), conditionsv1.ConditionAvailable)
+			if cond == nil {
+				return corev1.ConditionUnknown
+			}
+			return cond.Status
+		}).WithPolling(10 * time.Second).WithTimeout(time.Minute).Should(Equal(corev1.ConditionTrue))
+
+		waitForTunedProfileApplied(ctx, workerRTNode, cfg.getRecoveryTimeout())
+		Expect(getTuningState(ctx, workerRTNode)).To(Equal(expected), "the node %q tuning changed after the operator restart", workerRTNode.Name)
+	})
+}
+
+// waitForTunedProfileApplied waits until the TuneD daemon of the node 'node' reports its profile applied
+func waitForTunedProfileApplied(ctx context.Context, node *corev1.Node, timeout time.Duration) {
+	By(fmt.Sprintf("Waiting for the TuneD profile of the node %q to be applied", node.Name))
+	EventuallyWithOffset(1, func() bool {
+		tunedProfile, err := tuned.GetProfile(ctx, testclient.Client, components.NamespaceNodeTuningOperator, node.Name)
+		if err != nil {
+			testlog.Warningf("failed to get the TuneD profile of the node %q: %v", node.Name, err)
+			return false
+		}
+		for _, cond := range tunedProfile.Status.Conditions {
+			if cond.Type == tunedv1.TunedProfileApplied {
+				return cond.Status == corev1.ConditionTrue
+			}
+		}
+		return false
+	}).WithPolling(10*time.Second).WithTimeout(timeout).Should(BeTrue(), "the TuneD profile of the node %q was not applied", node.Name)
+}
+
+// getTuningState returns the output of the tuning state commands on the node 'node'
+func getTuningState(ctx context.Context, node *corev1.Node) []string {
+	var state []string
+	for _, cmd := range tuningStateCommands {
+		out, err := nodes.ExecCommandOnNode(ctx, cmd, node)
+		ExpectWithOffset(1, err).ToNot(HaveOccurred(), "failed to execute command %q on node: %q", cmd, node.Name)
+		state = append(state, out)
+	}
+	return state
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -39,6 +39,9 @@ const (
 	// PerformanceProfileCreator should be added in tests that are validating/verifying performance-profile-creator tool
 	// functionally.
 	PerformanceProfileCreator Feature = "performance-profile-creator"
+
+	// Robustness should be added in tests that disrupt the tuning components to verify the tuning recovers.
+	Robustness Feature = "robustness"
 )
 
 // Tier is a label to classify tests under specific grade/level
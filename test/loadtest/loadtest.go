@@ -0,0 +1,555 @@
+// Package loadtest generates cluster-scale load for the operator and measures how it copes: it creates
+// synthetic nodes and Tuned CRs recommending a profile per group of nodes, and measures the time the operator
+// takes to fan the recommended profiles out to the node Profiles, its memory and the rate of its writes.
+//
+// The nodes are plain Node objects annotated for kwok (https://kwok.sigs.k8s.io), so a kwok controller, when
+// deployed, keeps them Ready; the operator computes the Profiles of the nodes either way.  The nodes carry no
+// node role, so they join no MachineConfigPool.
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	statsv1alpha1 "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+	"k8s.io/utils/pointer"
+
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	ntoconfig "github.com/openshift/cluster-node-tuning-operator/pkg/config"
+	tunedset "github.com/openshift/cluster-node-tuning-operator/pkg/generated/clientset/versioned"
+)
+
+const (
+	// runLabel labels the objects of a load test run with the run ID
+	runLabel = "load-test.tuned.openshift.io/run"
+	// groupLabel labels the synthetic nodes with the group of the Tuned recommending their profile
+	groupLabel = "load-test.tuned.openshift.io/group"
+	// kwokNodeAnnotation makes kwok manage the node
+	kwokNodeAnnotation = "kwok.x-k8s.io/node"
+
+	operatorPodLabel = "name=cluster-node-tuning-operator"
+	// tunedPriority is the priority of the load test Tuneds, it wins over the default Tuned
+	tunedPriority = 20
+
+	defaultTimeout      = 30 * time.Minute
+	defaultPollInterval = 5 * time.Second
+	defaultWorkers      = 10
+)
+
+// Config configures a load test run
+type Config struct {
+	// Nodes is the number of synthetic nodes
+	Nodes int
+	// Tuneds is the number of Tuned CRs, every Tuned recommends its profile to its group of nodes
+	Tuneds int
+	// Workers is the number of objects created in parallel, defaults to 10
+	Workers int
+	// Timeout bounds every phase of the run, defaults to 30 minutes
+	Timeout time.Duration
+	// PollInterval is the interval the Profiles and the operator memory are read at, defaults to 5 seconds
+	PollInterval time.Duration
+}
+
+// Thresholds are the pass/fail criteria of a load test run, zero values are not checked
+type Thresholds struct {
+	// MinProfilesPerSecond is the minimum rate the Profiles of the nodes are updated at in every phase
+	MinProfilesPerSecond float64
+	// MaxMemoryBytes is the maximum working set of the operator pod
+	MaxMemoryBytes uint64
+	// MaxWritesPerSecond is the maximum rate of the operator writes to the tuned.openshift.io objects
+	MaxWritesPerSecond float64
+}
+
+// PhaseResult is the outcome of a phase of the run
+type PhaseResult struct {
+	// Name is the name of the phase
+	Name string
+	// Duration is the time the operator took to update the Profiles of all the nodes
+	Duration time.Duration
+	// ProfilesPerSecond is the rate the Profiles of the nodes were updated at
+	ProfilesPerSecond float64
+}
+
+// Result is the outcome of a load test run
+type Result struct {
+	Phases []PhaseResult
+	// PeakMemoryBytes is the peak working set of the operator pod, zero when it could not be measured
+	PeakMemoryBytes uint64
+	// Writes is the number of writes of the operator to the Profiles and the Tuneds, as observed by watches
+	Writes int64
+	// WritesPerSecond is the rate of the operator writes over the phases
+	WritesPerSecond float64
+}
+
+// Check returns the violations of the thresholds 't' by the result
+func (r *Result) Check(t Thresholds) []string {
+	var violations []string
+	if t.MinProfilesPerSecond > 0 {
+		for _, phase := range r.Phases {
+			if phase.ProfilesPerSecond < t.MinProfilesPerSecond {
+				violations = append(violations, fmt.Sprintf("phase %q updated %.2f Profiles per second, below the minimum of %.2f",
+					phase.Name, phase.ProfilesPerSecond, t.MinProfilesPerSecond))
+			}
+		}
+	}
+	if t.MaxMemoryBytes > 0 {
+		if r.PeakMemoryBytes == 0 {
+			violations = append(violations, "the operator memory could not be measured")
+		} else if r.PeakMemoryBytes > t.MaxMemoryBytes {
+			violations = append(violations, fmt.Sprintf("the operator used %d bytes of memory, above the maximum of %d", r.PeakMemoryBytes, t.MaxMemoryBytes))
+		}
+	}
+	if t.MaxWritesPerSecond > 0 && r.WritesPerSecond > t.MaxWritesPerSecond {
+		violations = append(violations, fmt.Sprintf("the operator wrote %.2f times per second, above the maximum of %.2f", r.WritesPerSecond, t.MaxWritesPerSecond))
+	}
+	return violations
+}
+
+// Runner runs load tests against the cluster of a rest config
+type Runner struct {
+	kubeClient  kubernetes.Interface
+	tunedClient tunedset.Interface
+	namespace   string
+	cfg         Config
+	runID       string
+	// ownWrites counts the writes of the runner to the tuned.openshift.io objects, they are not the operator's
+	ownWrites atomic.Int64
+}
+
+// NewRunner returns a new Runner creating the load of the configuration 'cfg' with the rest config 'restConfig'
+func NewRunner(restConfig *rest.Config, cfg Config) (*Runner, error) {
+	if cfg.Nodes <= 0 || cfg.Tuneds <= 0 {
+		return nil, fmt.Errorf("the numbers of nodes and Tuneds must be positive, got %d nodes and %d Tuneds", cfg.Nodes, cfg.Tuneds)
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+
+	r := &Runner{
+		namespace: ntoconfig.WatchNamespace(),
+		cfg:       cfg,
+		runID:     strconv.FormatInt(time.Now().Unix(), 10),
+	}
+
+	restConfig = rest.CopyConfig(restConfig)
+	// the load is created by several workers, do not let the client rate limiter skew the measures
+	restConfig.QPS = float32(10 * cfg.Workers)
+	restConfig.Burst = 20 * cfg.Workers
+	restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &writeCounter{next: rt, writes: &r.ownWrites}
+	})
+
+	var err error
+	if r.kubeClient, err = kubernetes.NewForConfig(restConfig); err != nil {
+		return nil, err
+	}
+	if r.tunedClient, err = tunedset.NewForConfig(restConfig); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Run runs the load test and deletes its objects once done.  The phases are:
+//   - fan-out: the nodes are created, their Profiles must get the profile of their Tuned
+//   - recommendation: the Tuneds recommend a new profile, the Profiles must get it
+//   - removal: the nodes are deleted, their Profiles must be deleted
+func (r *Runner) Run(ctx context.Context) (*Result, error) {
+	defer r.cleanup()
+
+	result := &Result{}
+	memoryCtx, stopMemory := context.WithCancel(ctx)
+	defer stopMemory()
+	memoryDone := make(chan struct{})
+	go func() {
+		defer close(memoryDone)
+		result.PeakMemoryBytes = r.samplePeakMemory(memoryCtx)
+	}()
+
+	writesCtx, stopWrites := context.WithCancel(ctx)
+	defer stopWrites()
+	var watchedWrites atomic.Int64
+	r.countWrites(writesCtx, &watchedWrites)
+
+	start := time.Now()
+	phases := []struct {
+		name string
+		run  func(context.Context) error
+	}{
+		{"fan-out", r.fanOut},
+		{"recommendation", r.recommend},
+		{"removal", r.removeNodes},
+	}
+	for _, phase := range phases {
+		klog.Infof("running the %s phase with %d nodes and %d Tuneds", phase.name, r.cfg.Nodes, r.cfg.Tuneds)
+		phaseStart := time.Now()
+		if err := phase.run(ctx); err != nil {
+			return nil, fmt.Errorf("failed to run the %s phase: %w", phase.name, err)
+		}
+		duration := time.Since(phaseStart)
+		result.Phases = append(result.Phases, PhaseResult{
+			Name:              phase.name,
+			Duration:          duration,
+			ProfilesPerSecond: float64(r.cfg.Nodes) / duration.Seconds(),
+		})
+	}
+	elapsed := time.Since(start)
+
+	stopWrites()
+	stopMemory()
+	<-memoryDone
+
+	result.Writes = watchedWrites.Load() - r.ownWrites.Load()
+	if result.Writes < 0 {
+		result.Writes = 0
+	}
+	result.WritesPerSecond = float64(result.Writes) / elapsed.Seconds()
+	return result, nil
+}
+
+// fanOut creates the Tuneds and the nodes and waits for the Profiles of the nodes to get the profile of their Tuned
+func (r *Runner) fanOut(ctx context.Context) error {
+	if err := r.parallelize(ctx, r.cfg.Tuneds, func(i int) error {
+		_, err := r.tunedClient.TunedV1().Tuneds(r.namespace).Create(ctx, r.newTuned(i, ""), metav1.CreateOptions{})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if err := r.parallelize(ctx, r.cfg.Nodes, func(i int) error {
+		_, err := r.kubeClient.CoreV1().Nodes().Create(ctx, r.newNode(i), metav1.CreateOptions{})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return r.waitForProfiles(ctx, "")
+}
+
+// recommend makes the Tuneds recommend a new profile and waits for the Profiles of the nodes to get it
+func (r *Runner) recommend(ctx context.Context) error {
+	if err := r.parallelize(ctx, r.cfg.Tuneds, func(i int) error {
+		tuned, err := r.tunedClient.TunedV1().Tuneds(r.namespace).Get(ctx, r.tunedName(i), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		tuned.Spec = r.newTuned(i, "-updated").Spec
+		_, err = r.tunedClient.TunedV1().Tuneds(r.namespace).Update(ctx, tuned, metav1.UpdateOptions{})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return r.waitForProfiles(ctx, "-updated")
+}
+
+// removeNodes deletes the nodes and waits for their Profiles to be deleted
+func (r *Runner) removeNodes(ctx context.Context) error {
+	if err := r.parallelize(ctx, r.cfg.Nodes, func(i int) error {
+		return r.kubeClient.CoreV1().Nodes().Delete(ctx, r.nodeName(i), metav1.DeleteOptions{})
+	}); err != nil {
+		return err
+	}
+
+	return r.poll(ctx, func(ctx context.Context) (bool, error) {
+		profiles, err := r.tunedClient.TunedV1().Profiles(r.namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		remaining := 0
+		for _, profile := range profiles.Items {
+			if r.isNodeName(profile.Name) {
+				remaining++
+			}
+		}
+		klog.V(2).Infof("%d/%d Profiles remaining", remaining, r.cfg.Nodes)
+		return remaining == 0, nil
+	})
+}
+
+// waitForProfiles waits for the Profiles of all the nodes to get the profile of their Tuned with the suffix 'suffix'
+func (r *Runner) waitForProfiles(ctx context.Context, suffix string) error {
+	return r.poll(ctx, func(ctx context.Context) (bool, error) {
+		profiles, err := r.tunedClient.TunedV1().Profiles(r.namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		updated := 0
+		for _, profile := range profiles.Items {
+			i, ok := r.nodeIndex(profile.Name)
+			if ok && profile.Spec.Config.TunedProfile == r.profileName(i%r.cfg.Tuneds, suffix) {
+				updated++
+			}
+		}
+		klog.V(2).Infof("%d/%d Profiles updated", updated, r.cfg.Nodes)
+		return updated == r.cfg.Nodes, nil
+	})
+}
+
+func (r *Runner) poll(ctx context.Context, condition wait.ConditionWithContextFunc) error {
+	return wait.PollUntilContextTimeout(ctx, r.cfg.PollInterval, r.cfg.Timeout, true, func(ctx context.Context) (bool, error) {
+		done, err := condition(ctx)
+		if err != nil {
+			// the apiserver may be slow under load, retry until the timeout
+			klog.Warningf("failed to check the Profiles: %v", err)
+			return false, nil
+		}
+		return done, nil
+	})
+}
+
+// parallelize calls 'create' for the indexes from 0 to 'n' with the configured number of workers
+func (r *Runner) parallelize(ctx context.Context, n int, create func(int) error) error {
+	indexes := make(chan int)
+	errs := make(chan error, r.cfg.Workers)
+	var wg sync.WaitGroup
+	for w := 0; w < r.cfg.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := create(i); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	var err error
+loop:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case err = <-errs:
+			break loop
+		case <-ctx.Done():
+			err = ctx.Err()
+			break loop
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	close(errs)
+	if err != nil {
+		return err
+	}
+	return <-errs
+}
+
+// countWrites counts the changes of the Profiles and the Tuneds into 'writes' until the context 'ctx' is done
+func (r *Runner) countWrites(ctx context.Context, writes *atomic.Int64) {
+	watchers := map[string]func(metav1.ListOptions) (watch.Interface, error){
+		"Profiles": func(opts metav1.ListOptions) (watch.Interface, error) {
+			return r.tunedClient.TunedV1().Profiles(r.namespace).Watch(ctx, opts)
+		},
+		"Tuneds": func(opts metav1.ListOptions) (watch.Interface, error) {
+			return r.tunedClient.TunedV1().Tuneds(r.namespace).Watch(ctx, opts)
+		},
+	}
+	for kind, watcher := range watchers {
+		kind, watcher := kind, watcher
+		go func() {
+			resourceVersion := ""
+			for ctx.Err() == nil {
+				w, err := watcher(metav1.ListOptions{ResourceVersion: resourceVersion})
+				if err != nil {
+					klog.Warningf("failed to watch the %s, the writes are not counted: %v", kind, err)
+					return
+				}
+				for event := range w.ResultChan() {
+					if object, ok := event.Object.(metav1.Object); ok && event.Type != watch.Error && event.Type != watch.Bookmark {
+						resourceVersion = object.GetResourceVersion()
+						writes.Add(1)
+					}
+				}
+				w.Stop()
+			}
+		}()
+	}
+}
+
+// samplePeakMemory returns the peak working set of the operator pod until the context 'ctx' is done
+func (r *Runner) samplePeakMemory(ctx context.Context) uint64 {
+	var peak uint64
+	_ = wait.PollUntilContextCancel(ctx, r.cfg.PollInterval, true, func(ctx context.Context) (bool, error) {
+		memory, err := r.getOperatorMemory(ctx)
+		if err != nil {
+			klog.V(2).Infof("failed to read the operator memory: %v", err)
+			return false, nil
+		}
+		if memory > peak {
+			peak = memory
+		}
+		return false, nil
+	})
+	return peak
+}
+
+// getOperatorMemory returns the working set of the operator pod, as reported by the kubelet of its node
+func (r *Runner) getOperatorMemory(ctx context.Context) (uint64, error) {
+	pods, err := r.kubeClient.CoreV1().Pods(r.namespace).List(ctx, metav1.ListOptions{LabelSelector: operatorPodLabel})
+	if err != nil {
+		return 0, err
+	}
+
+	var memory uint64
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.Spec.NodeName == "" {
+			continue
+		}
+
+		raw, err := r.kubeClient.CoreV1().RESTClient().Get().
+			Resource("nodes").Name(pod.Spec.NodeName).SubResource("proxy").Suffix("stats/summary").
+			DoRaw(ctx)
+		if err != nil {
+			return 0, err
+		}
+		summary := &statsv1alpha1.Summary{}
+		if err := json.Unmarshal(raw, summary); err != nil {
+			return 0, err
+		}
+		for _, podStats := range summary.Pods {
+			if podStats.PodRef.Namespace == pod.Namespace && podStats.PodRef.Name == pod.Name &&
+				podStats.Memory != nil && podStats.Memory.WorkingSetBytes != nil {
+				memory += *podStats.Memory.WorkingSetBytes
+			}
+		}
+	}
+	if memory == 0 {
+		return 0, fmt.Errorf("no running operator pod reported its memory")
+	}
+	return memory, nil
+}
+
+// cleanup deletes the objects of the run, it is best effort
+func (r *Runner) cleanup() {
+	ctx := context.Background()
+	selector := labels.SelectorFromSet(labels.Set{runLabel: r.runID}).String()
+	if err := r.kubeClient.CoreV1().Nodes().DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: selector}); err != nil && !errors.IsNotFound(err) {
+		klog.Errorf("failed to delete the load test nodes: %v", err)
+	}
+	if err := r.tunedClient.TunedV1().Tuneds(r.namespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: selector}); err != nil && !errors.IsNotFound(err) {
+		klog.Errorf("failed to delete the load test Tuneds: %v", err)
+	}
+}
+
+func (r *Runner) newNode(i int) *corev1.Node {
+	name := r.nodeName(i)
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				runLabel:                 r.runID,
+				groupLabel:               strconv.Itoa(i % r.cfg.Tuneds),
+				"kubernetes.io/hostname": name,
+				"type":                   "kwok",
+			},
+			Annotations: map[string]string{kwokNodeAnnotation: "fake"},
+		},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{{Key: kwokNodeAnnotation, Value: "fake", Effect: corev1.TaintEffectNoSchedule}},
+		},
+	}
+}
+
+// newTuned returns the Tuned 'i' recommending the profile with the suffix 'suffix' to its group of nodes
+func (r *Runner) newTuned(i int, suffix string) *tunedv1.Tuned {
+	profileName := r.profileName(i, suffix)
+	return &tunedv1.Tuned{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.tunedName(i),
+			Namespace: r.namespace,
+			Labels:    map[string]string{runLabel: r.runID},
+		},
+		Spec: tunedv1.TunedSpec{
+			Profile: []tunedv1.TunedProfile{
+				{
+					Name: pointer.String(profileName),
+					Data: pointer.String("[main]\nsummary=Load test profile\ninclude=openshift-node\n"),
+				},
+			},
+			Recommend: []tunedv1.TunedRecommend{
+				{
+					Profile:  pointer.String(profileName),
+					Priority: pointer.Uint64(tunedPriority),
+					Match: []tunedv1.TunedMatch{
+						{
+							Label: pointer.String(groupLabel),
+							Value: pointer.String(strconv.Itoa(i)),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *Runner) nodeName(i int) string {
+	return fmt.Sprintf("load-test-%s-node-%d", r.runID, i)
+}
+
+// nodeIndex returns the index of the synthetic node 'name'
+func (r *Runner) nodeIndex(name string) (int, bool) {
+	index, found := strings.CutPrefix(name, fmt.Sprintf("load-test-%s-node-", r.runID))
+	if !found {
+		return 0, false
+	}
+	i, err := strconv.Atoi(index)
+	return i, err == nil && i < r.cfg.Nodes
+}
+
+func (r *Runner) isNodeName(name string) bool {
+	_, ok := r.nodeIndex(name)
+	return ok
+}
+
+func (r *Runner) tunedName(i int) string {
+	return fmt.Sprintf("load-test-%s-%d", r.runID, i)
+}
+
+func (r *Runner) profileName(i int, suffix string) string {
+	return fmt.Sprintf("load-test-%d%s", i, suffix)
+}
+
+// writeCounter counts the writes to the tuned.openshift.io objects going through it
+type writeCounter struct {
+	next   http.RoundTripper
+	writes *atomic.Int64
+}
+
+func (c *writeCounter) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := c.next.RoundTrip(req)
+	if err == nil && resp.StatusCode < http.StatusBadRequest && isTunedWrite(req) {
+		c.writes.Add(1)
+	}
+	return resp, err
+}
+
+func isTunedWrite(req *http.Request) bool {
+	if req.Method == http.MethodGet {
+		return false
+	}
+	return strings.HasPrefix(req.URL.Path, "/apis/"+tunedv1.SchemeGroupVersion.Group+"/")
+}
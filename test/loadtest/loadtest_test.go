@@ -0,0 +1,60 @@
+package loadtest
+
+import (
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	result := &Result{
+		Phases: []PhaseResult{
+			{Name: "fan-out", ProfilesPerSecond: 50},
+			{Name: "recommendation", ProfilesPerSecond: 10},
+		},
+		PeakMemoryBytes: 300 << 20,
+		WritesPerSecond: 40,
+	}
+
+	testCases := []struct {
+		desc               string
+		result             *Result
+		thresholds         Thresholds
+		expectedViolations int
+	}{
+		{
+			desc:       "should pass without thresholds",
+			result:     result,
+			thresholds: Thresholds{},
+		},
+		{
+			desc:       "should pass within the thresholds",
+			result:     result,
+			thresholds: Thresholds{MinProfilesPerSecond: 10, MaxMemoryBytes: 512 << 20, MaxWritesPerSecond: 50},
+		},
+		{
+			desc:               "should fail every phase below the minimum rate",
+			result:             result,
+			thresholds:         Thresholds{MinProfilesPerSecond: 100},
+			expectedViolations: 2,
+		},
+		{
+			desc:               "should fail above the maximum memory and writes",
+			result:             result,
+			thresholds:         Thresholds{MaxMemoryBytes: 256 << 20, MaxWritesPerSecond: 20},
+			expectedViolations: 2,
+		},
+		{
+			desc:               "should fail when the memory was not measured",
+			result:             &Result{},
+			thresholds:         Thresholds{MaxMemoryBytes: 256 << 20},
+			expectedViolations: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if violations := tc.result.Check(tc.thresholds); len(violations) != tc.expectedViolations {
+				t.Errorf("expected %d violations, got %v", tc.expectedViolations, violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// The load test creates synthetic nodes and Tuned CRs in the cluster of the KUBECONFIG environment variable,
+// measures how the operator copes with them and fails when the measures cross the thresholds.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	ntoclient "github.com/openshift/cluster-node-tuning-operator/pkg/client"
+	"github.com/openshift/cluster-node-tuning-operator/test/loadtest"
+)
+
+func main() {
+	var (
+		cfg        loadtest.Config
+		thresholds loadtest.Thresholds
+		maxMemory  string
+		confirmed  bool
+	)
+
+	klog.InitFlags(nil)
+	flag.IntVar(&cfg.Nodes, "nodes", 500, "number of synthetic nodes")
+	flag.IntVar(&cfg.Tuneds, "tuneds", 10, "number of Tuned CRs, every Tuned recommends its profile to its group of nodes")
+	flag.IntVar(&cfg.Workers, "workers", 10, "number of objects created in parallel")
+	flag.DurationVar(&cfg.Timeout, "timeout", 30*time.Minute, "timeout of every phase of the load test")
+	flag.DurationVar(&cfg.PollInterval, "poll-interval", 5*time.Second, "interval the Profiles and the operator memory are read at")
+	flag.Float64Var(&thresholds.MinProfilesPerSecond, "min-profiles-per-second", 0, "minimum rate the Profiles are updated at in every phase, 0 disables the check")
+	flag.StringVar(&maxMemory, "max-memory", "", "maximum working set of the operator pod, e.g. 512Mi, empty disables the check")
+	flag.Float64Var(&thresholds.MaxWritesPerSecond, "max-writes-per-second", 0, "maximum rate of the operator writes to the Profiles and the Tuneds, 0 disables the check")
+	flag.BoolVar(&confirmed, "yes", false, "confirm the load test may create and delete nodes in the cluster")
+	flag.Parse()
+
+	if !confirmed {
+		klog.Exit("the load test creates and deletes nodes in the cluster, confirm with --yes")
+	}
+
+	if maxMemory != "" {
+		quantity, err := resource.ParseQuantity(maxMemory)
+		if err != nil {
+			klog.Exitf("invalid --max-memory %q: %v", maxMemory, err)
+		}
+		thresholds.MaxMemoryBytes = uint64(quantity.Value())
+	}
+
+	restConfig, err := ntoclient.GetConfig()
+	if err != nil {
+		klog.Exitf("failed to get the cluster config: %v", err)
+	}
+
+	runner, err := loadtest.NewRunner(restConfig, cfg)
+	if err != nil {
+		klog.Exit(err)
+	}
+
+	result, err := runner.Run(context.Background())
+	if err != nil {
+		klog.Exitf("load test failed: %v", err)
+	}
+
+	fmt.Printf("%-16s %12s %18s\n", "PHASE", "DURATION", "PROFILES/SECOND")
+	for _, phase := range result.Phases {
+		fmt.Printf("%-16s %12s %18.2f\n", phase.Name, phase.Duration.Round(time.Second), phase.ProfilesPerSecond)
+	}
+	if result.PeakMemoryBytes > 0 {
+		fmt.Printf("operator peak memory: %s\n", resource.NewQuantity(int64(result.PeakMemoryBytes), resource.BinarySI))
+	} else {
+		fmt.Println("operator peak memory: not measured")
+	}
+	fmt.Printf("operator writes: %d (%.2f per second)\n", result.Writes, result.WritesPerSecond)
+
+	violations := result.Check(thresholds)
+	for _, violation := range violations {
+		fmt.Printf("FAIL: %s\n", violation)
+	}
+	if len(violations) > 0 {
+		os.Exit(1)
+	}
+	fmt.Println("PASS")
+}